@@ -6,6 +6,7 @@ import (
 	"io"
 	"slices"
 
+	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/inoxd"
 	"github.com/inoxlang/inox/internal/inoxd/cloud/cloudproxy"
 	"github.com/inoxlang/inox/internal/inoxprocess"
@@ -27,13 +28,21 @@ const (
 	INSTALL_COMPLETIONS_SUBCMD   = "install-completions"
 	UNINSTALL_COMPLETIONS_SUBCMD = "uninstall-completions"
 	HELP_SUBCMD                  = "help"
+	DB_SUBCMD                    = "db"
+	DEPS_SUBCMD                  = "deps"
+
+	DB_SEED_SUBCMD    = "seed"
+	DB_CONVERT_SUBCMD = "convert"
+
+	DEPS_UPDATE_SUBCMD = "update"
+	DEPS_VERIFY_SUBCMD = "verify"
 )
 
 var (
 	CLI_SUBCOMMANDS = []string{
 		ADD_SERVICE_SUBCMD, REMOVE_SERVICE_SUBCMD, UPGRADE_INOX_SUBCMD, //root
 		RUN_SUBCMD, CHECK_SUBCMD, SHELL_SUBCMD, EVAL_SUBCMD, EVAL_ALIAS_SUBCMD /*"lsp",*/, PROJECT_SERVER_SUBCMD, HELP_SUBCMD,
-		INSTALL_COMPLETIONS_SUBCMD, UNINSTALL_COMPLETIONS_SUBCMD,
+		INSTALL_COMPLETIONS_SUBCMD, UNINSTALL_COMPLETIONS_SUBCMD, DB_SUBCMD, DEPS_SUBCMD,
 	}
 	SUBCOMMANDS = append(slices.Clone(CLI_SUBCOMMANDS), inoxd.DAEMON_SUBCMD, inoxprocess.CONTROLLED_SUBCMD, cloudproxy.CLOUD_PROXY_SUBCMD_NAME)
 
@@ -50,6 +59,10 @@ var (
 		{SHELL_SUBCMD, "start the shell"},
 		{EVAL_SUBCMD, "evaluate a single statement"},
 		{EVAL_ALIAS_SUBCMD, "alias for eval"},
+		{DB_SUBCMD, "manage the dev databases of a project: `db seed -project-id=<id> [-db=main] <fixtures file>` writes fixtures (.json or .ix file) into a dev database, " +
+			"`db convert -project-id=<id> [-db=main] <json|binary>` converts the values of a dev database to another serialization format"},
+		{DEPS_SUBCMD, "manage the modules imported from URLs: `deps update <module>...` resolves the versions of the imported modules and writes " +
+			core.DEPENDENCY_LOCK_FILENAME + ", `deps verify <module>` checks the locked modules"},
 		//{"lsp",           "start the language server (LSP)"},
 
 		{INSTALL_COMPLETIONS_SUBCMD, "install CLI completions by addding the completion command to the detected rc file (supported shells are bash, zsh and fish)"},
@@ -84,9 +97,15 @@ var (
 				Flags: map[string]complete.Predictor{
 					"test":                     predict.Nothing,
 					"test-trusted":             predict.Nothing,
+					"bench":                    predict.Nothing,
+					"bench-baseline":           predict.Files("*.json"),
+					"bench-out":                predict.Files("*.json"),
+					"update-golden":            predict.Nothing,
 					"fully-trusted":            predict.Nothing,
 					"show-bytecode":            predict.Nothing,
 					"no-optimization":          predict.Nothing,
+					"no-optimization-pass":     predict.Nothing,
+					"no-bytecode-cache":        predict.Nothing,
 					"allow-browser-automation": predict.Nothing,
 					"t":                        predict.Nothing,
 				},
@@ -119,6 +138,42 @@ var (
 			},
 			INSTALL_COMPLETIONS_SUBCMD:   {},
 			UNINSTALL_COMPLETIONS_SUBCMD: {},
+			DB_SUBCMD: {
+				Sub: map[string]*complete.Command{
+					DB_SEED_SUBCMD: {
+						Flags: map[string]complete.Predictor{
+							"project-id":   predict.Nothing,
+							"projects-dir": predict.Dirs("*"),
+							"db":           predict.Set{"main"},
+						},
+						Args: predict.Or(predict.Files("*.json"), predict.Files("*.ix")),
+					},
+					DB_CONVERT_SUBCMD: {
+						Flags: map[string]complete.Predictor{
+							"project-id":   predict.Nothing,
+							"projects-dir": predict.Dirs("*"),
+							"db":           predict.Set{"main"},
+						},
+						Args: predict.Set(core.SERIALIZATION_FORMAT_NAMES),
+					},
+				},
+			},
+			DEPS_SUBCMD: {
+				Sub: map[string]*complete.Command{
+					DEPS_UPDATE_SUBCMD: {
+						Flags: map[string]complete.Predictor{
+							"insecure": predict.Nothing,
+						},
+						Args: predict.Files("*.ix"),
+					},
+					DEPS_VERIFY_SUBCMD: {
+						Flags: map[string]complete.Predictor{
+							"insecure": predict.Nothing,
+						},
+						Args: predict.Files("*.ix"),
+					},
+				},
+			},
 		},
 	}
 )
@@ -26,8 +26,10 @@ import (
 
 	"github.com/inoxlang/inox/internal/core/permkind"
 	"github.com/inoxlang/inox/internal/inoxprocess"
+	"github.com/inoxlang/inox/internal/localdb"
 	"github.com/inoxlang/inox/internal/mod"
 	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/project"
 	"github.com/inoxlang/inox/internal/utils"
 
 	"github.com/inoxlang/inox/internal/projectserver"
@@ -161,14 +163,26 @@ func _main(args []string, outW io.Writer, errW io.Writer) (statusCode int) {
 		var enableTestingModeAndTrust bool
 		var showBytecode bool
 		var disableOptimization bool
+		var disableBytecodeCache bool
+		var disabledOptimizationPassList string
 		var fullyTrusted bool
 		var allowBrowserAutomation bool
+		var enableBenchmarking bool
+		var benchmarkBaselinePath string
+		var benchmarkOutputPath string
+		var updateGoldenFiles bool
 
 		flags.BoolVar(&enableTestingMode, "test", false, "enable testing mode")
 		flags.BoolVar(&enableTestingModeAndTrust, "test-trusted", false, "enable testing mode and do not show confirmation prompt if the risk score is high")
+		flags.BoolVar(&enableBenchmarking, "bench", false, "enable testing mode and run the benchmarks")
+		flags.StringVar(&benchmarkBaselinePath, "bench-baseline", "", "JSON file containing previous benchmark results, regressions make benchmarks fail (-bench-baseline=<file>)")
+		flags.StringVar(&benchmarkOutputPath, "bench-out", "", "write the benchmark results to a JSON file that can be used as a baseline (-bench-out=<file>)")
+		flags.BoolVar(&updateGoldenFiles, "update-golden", false, "enable testing mode and (re)write the golden files instead of comparing them")
 		flags.BoolVar(&useTreeWalking, "t", false, "use tree walking interpreter")
 		flags.BoolVar(&showBytecode, "show-bytecode", false, "show emitted bytecode before evaluating the script")
 		flags.BoolVar(&disableOptimization, "no-optimization", false, "disable bytecode optimization")
+		flags.StringVar(&disabledOptimizationPassList, "no-optimization-pass", "", "comma-separated list of bytecode optimization passes to disable (-no-optimization-pass=peephole,jump-threading)")
+		flags.BoolVar(&disableBytecodeCache, "no-bytecode-cache", false, "always compile the script instead of loading its bytecode from the cache")
 		flags.BoolVar(&fullyTrusted, "fully-trusted", false, "do not show confirmation prompt if the risk score is high")
 		flags.BoolVar(&allowBrowserAutomation, "allow-browser-automation", false, "allow creating and controlling a browser")
 
@@ -213,6 +227,37 @@ func _main(args []string, outW io.Writer, errW io.Writer) (statusCode int) {
 			enableTestingMode = true
 		}
 
+		if updateGoldenFiles {
+			enableTestingMode = true
+		}
+
+		disabledOptimizationPasses, err := core.ParseBytecodeOptimizationPassList(disabledOptimizationPassList)
+		if err != nil {
+			fmt.Fprintln(errW, err.Error())
+			return ERROR_STATUS_CODE
+		}
+
+		var benchmarkBaseline *core.BenchmarkBaseline
+
+		if enableBenchmarking {
+			enableTestingMode = true
+			//benchmarks are only supported by the tree walking interpreter.
+			useTreeWalking = true
+
+			if benchmarkBaselinePath != "" {
+				content, err := os.ReadFile(benchmarkBaselinePath)
+				if err != nil {
+					fmt.Fprintf(errW, "failed to read the benchmark baseline: %s\n", err.Error())
+					return ERROR_STATUS_CODE
+				}
+				benchmarkBaseline, err = core.ParseBenchmarkBaseline(content)
+				if err != nil {
+					fmt.Fprintln(errW, err.Error())
+					return ERROR_STATUS_CODE
+				}
+			}
+		}
+
 		//create a temporary directory for the whole process
 		_, processTempDirPerms, removeTempDir := CreateTempDir()
 		defer removeTempDir()
@@ -269,11 +314,17 @@ func _main(args []string, outW io.Writer, errW io.Writer) (statusCode int) {
 			UseBytecode:      !useTreeWalking,
 			ShowBytecode:     showBytecode,
 			OptimizeBytecode: !useTreeWalking && !disableOptimization,
+			BytecodeCache:    utils.If(disableBytecodeCache, nil, core.NewBytecodeCache(core.DefaultBytecodeCacheDir())),
 			Out:              outW,
 
+			DisabledOptimizationPasses: disabledOptimizationPasses,
+
 			FullAccessToDatabases: true,
 			EnableTesting:         enableTestingMode,
 			TestFilters:           testFilters,
+			EnableBenchmarking:    enableBenchmarking,
+			BenchmarkBaseline:     benchmarkBaseline,
+			UpdateGoldenFiles:     updateGoldenFiles,
 
 			OnPrepared: func(state *core.GlobalState) error {
 				inoxprocess.RestrictProcessAccess(state.Ctx, inoxprocess.ProcessRestrictionConfig{
@@ -326,9 +377,272 @@ func _main(args []string, outW io.Writer, errW io.Writer) (statusCode int) {
 		colorized := config.DEFAULT_PRETTY_PRINT_CONFIG.Colorize
 		backgroundIsDark := config.INITIAL_BG_COLOR.IsDarkBackgroundColor()
 
+		var benchmarkResults []*core.BenchmarkResult
+
 		for _, suiteResult := range scriptState.TestingState.SuiteResults {
 			msg := utils.AddCarriageReturnAfterNewlines(suiteResult.MostAdaptedMessage(colorized, backgroundIsDark))
 			fmt.Fprint(outW, msg)
+			benchmarkResults = append(benchmarkResults, suiteResult.BenchmarkResults()...)
+		}
+
+		//write benchmark results
+
+		if enableBenchmarking && benchmarkOutputPath != "" {
+			content, err := core.MarshalBenchmarkResults(benchmarkResults)
+			if err == nil {
+				err = os.WriteFile(benchmarkOutputPath, content, 0o600)
+			}
+			if err != nil {
+				fmt.Fprintf(errW, "failed to write the benchmark results: %s\n", err.Error())
+				return ERROR_STATUS_CODE
+			}
+		}
+	case DB_SUBCMD:
+		if len(mainSubCommandArgs) == 0 || !slices.Contains([]string{DB_SEED_SUBCMD, DB_CONVERT_SUBCMD}, mainSubCommandArgs[0]) {
+			if slices.Contains(mainSubCommandArgs, "-h") {
+				fmt.Fprintln(outW, CLI_SUBCOMMAND_DESCRIPTION_MAP[DB_SUBCMD])
+				return
+			}
+			fmt.Fprintf(errW, "missing or unknown db command, supported commands: %s, %s\n", DB_SEED_SUBCMD, DB_CONVERT_SUBCMD)
+			return ERROR_STATUS_CODE
+		}
+		dbCommand := mainSubCommandArgs[0]
+		dbCommandArgs := mainSubCommandArgs[1:]
+
+		//read and check arguments
+
+		flags := flag.NewFlagSet(DB_SUBCMD, flag.ExitOnError)
+		var projectId string
+		var projectsDir string
+		var databaseName string
+
+		flags.StringVar(&projectId, "project-id", "", "ID of the project owning the dev database")
+		flags.StringVar(&projectsDir, "projects-dir", filepath.Join(config.USER_HOME, "inox-projects"), "directory of the projects")
+		flags.StringVar(&databaseName, "db", "main", "name of the dev database")
+
+		if showHelp(flags, dbCommandArgs, outW) { //only show help
+			return
+		}
+
+		moveFlagsStart(dbCommandArgs)
+
+		err := flags.Parse(dbCommandArgs)
+		if err != nil {
+			fmt.Fprintln(errW, err)
+			return ERROR_STATUS_CODE
+		}
+
+		var (
+			fixturesPath string
+			format       core.SerializationFormat
+		)
+
+		switch dbCommand {
+		case DB_SEED_SUBCMD:
+			fixturesPath = flags.Arg(0)
+			if fixturesPath == "" {
+				fmt.Fprintf(errW, "missing fixtures path\n")
+				return ERROR_STATUS_CODE
+			}
+		case DB_CONVERT_SUBCMD:
+			if flags.Arg(0) == "" {
+				fmt.Fprintf(errW, "missing serialization format (%s)\n", strings.Join(core.SERIALIZATION_FORMAT_NAMES, ", "))
+				return ERROR_STATUS_CODE
+			}
+			format, err = core.ParseSerializationFormat(flags.Arg(0))
+			if err != nil {
+				fmt.Fprintln(errW, err)
+				return ERROR_STATUS_CODE
+			}
+		}
+
+		if projectId == "" {
+			fmt.Fprintf(errW, "missing project id (-project-id=<id>)\n")
+			return ERROR_STATUS_CODE
+		}
+
+		databaseDir := filepath.Join(project.GetDevDatabasesDir(projectsDir, core.ProjectID(projectId)), databaseName)
+		if _, err := os.Stat(databaseDir); err != nil {
+			fmt.Fprintf(errW, "failed to find the %q dev database of the project: %s\n", databaseName, err.Error())
+			return ERROR_STATUS_CODE
+		}
+
+		databaseHost := core.Host(string(core.LDB_SCHEME) + "://" + databaseName)
+		databaseConfig := localdb.LocalDatabaseConfig{
+			OsFsDir: core.DirPathFrom(databaseDir),
+			Host:    databaseHost,
+		}
+
+		permissions := []core.Permission{
+			core.DatabasePermission{Kind_: permkind.Read, Entity: databaseHost},
+			core.DatabasePermission{Kind_: permkind.Write, Entity: databaseHost},
+		}
+
+		if dbCommand == DB_SEED_SUBCMD {
+			fixturesPath, err = filepath.Abs(fixturesPath)
+			if err != nil {
+				fmt.Fprintln(errW, err)
+				return ERROR_STATUS_CODE
+			}
+			permissions = append(permissions, core.FilesystemPermission{Kind_: permkind.Read, Entity: core.Path(fixturesPath)})
+		}
+
+		ctx := core.NewContext(core.ContextConfig{
+			Permissions: permissions,
+			Filesystem:  fs_ns.GetOsFilesystem(),
+		})
+		core.NewGlobalState(ctx)
+		defer ctx.CancelGracefully()
+
+		switch dbCommand {
+		case DB_SEED_SUBCMD:
+			seededEntities, err := localdb.SeedDatabase(ctx, databaseConfig, core.Path(fixturesPath))
+
+			if err != nil {
+				fmt.Fprintf(errW, "failed to seed the %q dev database: %s\n", databaseName, err.Error())
+				return ERROR_STATUS_CODE
+			}
+
+			fmt.Fprintf(outW, "seeded top-level entities: %s\n", strings.Join(seededEntities, ", "))
+		case DB_CONVERT_SUBCMD:
+			prevFormat, err := localdb.ConvertDatabase(ctx, databaseConfig, format)
+
+			if err != nil {
+				fmt.Fprintf(errW, "failed to convert the %q dev database: %s\n", databaseName, err.Error())
+				return ERROR_STATUS_CODE
+			}
+
+			if prevFormat == format {
+				fmt.Fprintf(outW, "the %q dev database is already in the %s format\n", databaseName, format)
+			} else {
+				fmt.Fprintf(outW, "converted the %q dev database from the %s format to the %s format\n", databaseName, prevFormat, format)
+			}
+		}
+	case DEPS_SUBCMD:
+		if len(mainSubCommandArgs) == 0 || !slices.Contains([]string{DEPS_UPDATE_SUBCMD, DEPS_VERIFY_SUBCMD}, mainSubCommandArgs[0]) {
+			if slices.Contains(mainSubCommandArgs, "-h") {
+				fmt.Fprintln(outW, CLI_SUBCOMMAND_DESCRIPTION_MAP[DEPS_SUBCMD])
+				return
+			}
+			fmt.Fprintf(errW, "missing or unknown deps command, supported commands: %s, %s\n", DEPS_UPDATE_SUBCMD, DEPS_VERIFY_SUBCMD)
+			return ERROR_STATUS_CODE
+		}
+		depsCommand := mainSubCommandArgs[0]
+		depsCommandArgs := mainSubCommandArgs[1:]
+
+		//read and check arguments
+
+		flags := flag.NewFlagSet(DEPS_SUBCMD, flag.ExitOnError)
+		var insecure bool
+
+		flags.BoolVar(&insecure, "insecure", false, "do not verify the TLS certificates of the servers hosting the modules")
+
+		if showHelp(flags, depsCommandArgs, outW) { //only show help
+			return
+		}
+
+		moveFlagsStart(depsCommandArgs)
+
+		err := flags.Parse(depsCommandArgs)
+		if err != nil {
+			fmt.Fprintln(errW, err)
+			return ERROR_STATUS_CODE
+		}
+
+		if flags.NArg() == 0 {
+			fmt.Fprintf(errW, "missing module path\n")
+			return ERROR_STATUS_CODE
+		}
+
+		var modulePaths []string
+		for _, arg := range flags.Args() {
+			absPath, err := filepath.Abs(arg)
+			if err != nil {
+				fmt.Fprintln(errW, err)
+				return ERROR_STATUS_CODE
+			}
+			modulePaths = append(modulePaths, absPath)
+		}
+
+		//the lock file is searched in the directory of the first module and its ancestors,
+		//if there is no lock file a new one is created in the directory of the first module.
+
+		fls := fs_ns.GetOsFilesystem()
+		lockFilePath, lockFileFound := core.FindDependencyLockFile(fls, filepath.Dir(modulePaths[0]))
+		if !lockFileFound {
+			lockFilePath = filepath.Join(filepath.Dir(modulePaths[0]), core.DEPENDENCY_LOCK_FILENAME)
+		}
+		projectDir := core.AppendTrailingSlashIfNotPresent(filepath.Dir(lockFilePath))
+
+		ctx := core.NewContext(core.ContextConfig{
+			Permissions: []core.Permission{
+				core.FilesystemPermission{Kind_: permkind.Read, Entity: core.PathPattern(projectDir + "...")},
+				core.HttpPermission{Kind_: permkind.Read, AnyEntity: true},
+			},
+			Filesystem: fls,
+		})
+		core.NewGlobalState(ctx)
+		defer ctx.CancelGracefully()
+
+		switch depsCommand {
+		case DEPS_UPDATE_SUBCMD:
+			lockFile := core.NewDependencyLockFile()
+
+			for _, modulePath := range modulePaths {
+				_, err := core.ParseLocalModule(modulePath, core.ModuleParsingConfig{
+					Context:              ctx,
+					DependencyLock:       lockFile,
+					UpdateDependencyLock: true,
+					InsecureModImports:   insecure,
+				})
+				if err != nil {
+					fmt.Fprintf(errW, "failed to resolve the dependencies of %s: %s\n", modulePath, err.Error())
+					return ERROR_STATUS_CODE
+				}
+			}
+
+			err := os.WriteFile(lockFilePath, lockFile.Marshal(), core.DEPENDENCY_LOCK_FILE_PERM)
+			if err != nil {
+				fmt.Fprintf(errW, "failed to write %s: %s\n", lockFilePath, err.Error())
+				return ERROR_STATUS_CODE
+			}
+
+			for _, importURL := range lockFile.ImportURLs() {
+				dep, _ := lockFile.Get(importURL)
+				fmt.Fprintf(outW, "%s -> %s\n", importURL, dep.ResolvedURL)
+			}
+			fmt.Fprintf(outW, "%s updated\n", lockFilePath)
+		case DEPS_VERIFY_SUBCMD:
+			if !lockFileFound {
+				fmt.Fprintf(errW, "no %s file found, run `inox deps update`\n", core.DEPENDENCY_LOCK_FILENAME)
+				return ERROR_STATUS_CODE
+			}
+
+			lockFile, err := core.ReadDependencyLockFile(fls, lockFilePath)
+			if err != nil {
+				fmt.Fprintf(errW, "failed to read %s: %s\n", lockFilePath, err.Error())
+				return ERROR_STATUS_CODE
+			}
+
+			if err := lockFile.Verify(ctx, insecure); err != nil {
+				fmt.Fprintln(errW, err)
+				return ERROR_STATUS_CODE
+			}
+
+			//check that all the modules imported from URLs are locked.
+			for _, modulePath := range modulePaths {
+				_, err := core.ParseLocalModule(modulePath, core.ModuleParsingConfig{
+					Context:            ctx,
+					DependencyLock:     lockFile,
+					InsecureModImports: insecure,
+				})
+				if err != nil {
+					fmt.Fprintf(errW, "%s: %s\n", modulePath, err.Error())
+					return ERROR_STATUS_CODE
+				}
+			}
+
+			fmt.Fprintf(outW, "%s: all locked modules are valid\n", lockFilePath)
 		}
 	case CHECK_SUBCMD:
 		if len(mainSubCommandArgs) == 0 {
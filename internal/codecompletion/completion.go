@@ -288,11 +288,52 @@ func handlePatternIdentCompletions(n *parse.PatternIdentifierLiteral, search com
 				LabelDetail: symbolic.Stringify(namespaceData.Value),
 			})
 		}
+
+		//type parameters of the enclosing generic functions and pattern definitions.
+		if _, ok := search.parent.(*parse.TypeParameter); !ok {
+			for _, typeParam := range getTypeParametersInScope(ancestorChain) {
+				name := typeParam.Name.Name
+				if !hasPrefixCaseInsensitive(name, n.Name) {
+					continue
+				}
+
+				s := name
+				if !n.Unprefixed {
+					s = "%" + s
+				}
+
+				detail := ""
+				if constraint, ok := state.Global.SymbolicData.GetMostSpecificNodeValue(typeParam.Name); ok {
+					detail = symbolic.Stringify(constraint)
+				}
+
+				completions = append(completions, Completion{
+					ShownString: s,
+					Value:       s,
+					Kind:        defines.CompletionItemKindTypeParameter,
+					LabelDetail: detail,
+				})
+			}
+		}
 	}
 
 	return completions
 }
 
+// getTypeParametersInScope returns the type parameters of the generic functions and pattern definitions
+// enclosing the last node of ancestorChain, the innermost ones come first.
+func getTypeParametersInScope(ancestorChain []parse.Node) (typeParams []*parse.TypeParameter) {
+	for i := len(ancestorChain) - 1; i >= 0; i-- {
+		switch n := ancestorChain[i].(type) {
+		case *parse.FunctionExpression:
+			typeParams = append(typeParams, n.TypeParameters...)
+		case *parse.PatternDefinition:
+			typeParams = append(typeParams, n.TypeParameters...)
+		}
+	}
+	return
+}
+
 func handlePatternNamespaceCompletions(n parse.Node, search completionSearch) []Completion {
 	state := search.state
 	ctx := state.Global.Ctx
@@ -650,6 +650,36 @@ func runSingleModeTests(t *testing.T, mode Mode, wd, dir string) {
 			}, completions)
 		})
 
+		t.Run("suggest type parameter of the enclosing generic function from first letter", func(t *testing.T) {
+			state := newState()
+			chunk, _ := parseChunkSource("fn f<Tval int>(a Tval){ %T }", "")
+			doSymbolicCheck(chunk, state.Global)
+
+			completions := findCompletions(state, chunk, 26)
+			assert.EqualValues(t, []Completion{
+				{
+					ShownString:   "%Tval",
+					Value:         "%Tval",
+					ReplacedRange: parse.SourcePositionRange{Span: parse.NodeSpan{Start: 24, End: 26}},
+				},
+			}, completions)
+		})
+
+		t.Run("suggest type parameter of the enclosing pattern definition from first letter", func(t *testing.T) {
+			state := newState()
+			chunk, _ := parseChunkSource("pattern p<Tval> = [%T]", "")
+			doSymbolicCheck(chunk, state.Global)
+
+			completions := findCompletions(state, chunk, 21)
+			assert.EqualValues(t, []Completion{
+				{
+					ShownString:   "%Tval",
+					Value:         "%Tval",
+					ReplacedRange: parse.SourcePositionRange{Span: parse.NodeSpan{Start: 19, End: 21}},
+				},
+			}, completions)
+		})
+
 		t.Run("suggest pattern namespace member from first letter", func(t *testing.T) {
 			state := newState()
 			chunk, _ := parseChunkSource("pnamespace namespace. = {patt: 1}; %namespace.p", "")
@@ -35,6 +35,7 @@ var (
 		core.MANIFEST_DATABASE__RESOLUTION_DATA_PROP_NAME:        "nil",
 		core.MANIFEST_DATABASE__EXPECTED_SCHEMA_UPDATE_PROP_NAME: "false  # should be set to true if the module performs a schema update (update_schema call)",
 		core.MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME:   "# object pattern to check the actual schema against",
+		core.MANIFEST_DATABASE__FORMAT_PROP_NAME:                 "#json  # serialization format of the values if the database is new (#json or #binary)",
 	}
 
 	MANIFEST_DB_DESC_DOC = map[string]string{
@@ -42,6 +43,7 @@ var (
 		core.MANIFEST_DATABASE__RESOLUTION_DATA_PROP_NAME:        utils.MustGet(help.HelpFor("manifest/databases-section/resolution-data", helpMessageConfig)),
 		core.MANIFEST_DATABASE__EXPECTED_SCHEMA_UPDATE_PROP_NAME: utils.MustGet(help.HelpFor("manifest/databases-section/expected-schema-update", helpMessageConfig)),
 		core.MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME:   utils.MustGet(help.HelpFor("manifest/databases-section/assert-schema", helpMessageConfig)),
+		core.MANIFEST_DATABASE__FORMAT_PROP_NAME:                 utils.MustGet(help.HelpFor("manifest/databases-section/format", helpMessageConfig)),
 	}
 
 	MODULE_IMPORT_SECTION_DEFAULT_VALUE_COMPLETIONS = map[string]string{
@@ -156,7 +156,7 @@ func (err AssertionError) stringifyNode(node parse.Node) string {
 
 func (err AssertionError) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig) {
 	w.Write(utils.StringAsBytes(err.msg))
-	if err.isTestAssertion {
+	if err.isTestAssertion && err.data != nil {
 		err.writeExplanation(w, config)
 	}
 }
@@ -0,0 +1,980 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+// This file contains the implementation of the binary representation of Serializable values. The binary representation
+// is based on CBOR (RFC 8949): each value is encoded as a CBOR data item, Inox-specific types are encoded as tagged
+// data items. Unlike the JSON representation the binary representation is self-describing, patterns are only used to
+// specialize the parsed values (e.g. integer lists), to add missing properties having a default value and to validate
+// the parsed values. The values that have no native binary encoding are encoded as a tagged JSON representation.
+
+const (
+	MAX_BINARY_REPR_DEPTH = 20
+
+	//CBOR major types
+
+	cborUnsignedInt = 0
+	cborNegativeInt = 1
+	cborByteString  = 2
+	cborTextString  = 3
+	cborArray       = 4
+	cborMap         = 5
+	cborTag         = 6
+	cborSimpleFloat = 7
+
+	//CBOR simple values & floats (additional information of the major type 7)
+
+	cborFalse   = 20
+	cborTrue    = 21
+	cborNull    = 22
+	cborFloat32 = 26
+	cborFloat64 = 27
+)
+
+// tag numbers specific to Inox.
+const (
+	binaryTagRune uint64 = 50_000 + iota
+	binaryTagObject
+	binaryTagRecord
+	binaryTagList
+	binaryTagTuple
+	binaryTagPath
+	binaryTagPathPattern
+	binaryTagURL
+	binaryTagURLPattern
+	binaryTagHost
+	binaryTagHostPattern
+	binaryTagScheme
+	binaryTagEmailAddress
+	binaryTagIdentifier
+	binaryTagPropertyName
+	binaryTagByteCount
+	binaryTagLineCount
+	binaryTagRuneCount
+	binaryTagByteRate
+	binaryTagFrequency
+	binaryTagDuration
+	binaryTagYear
+	binaryTagDate
+	binaryTagDateTime
+	binaryTagIntRange
+	binaryTagFloatRange
+	binaryTagRuneRange
+	binaryTagJSON //JSON representation of a value that has no native binary encoding
+)
+
+var (
+	ErrMaximumBinaryReprDepthReached = errors.New("maximum binary representation depth reached")
+	ErrInvalidBinaryRepresentation   = errors.New("invalid binary representation")
+	ErrBinaryReprNotMatchingPattern  = errors.New("binary representation does not match the pattern")
+)
+
+type BinarySerializationConfig struct {
+	*ReprConfig
+	Pattern Pattern //nillable
+}
+
+// GetBinaryRepresentation returns the binary representation of v, the pattern of the config is used to determine how
+// the values without a native binary encoding are serialized (see WriteJSONRepresentation).
+func GetBinaryRepresentation(v Serializable, ctx *Context, config BinarySerializationConfig) ([]byte, error) {
+	encoder := &binaryEncoder{ctx: ctx, reprConfig: config.ReprConfig}
+	if err := encoder.writeValue(v, config.Pattern, 0); err != nil {
+		return nil, err
+	}
+	return encoder.buf, nil
+}
+
+// ParseBinaryRepresentation parses the binary representation of a value, the parsed value is checked against the
+// pattern if it is not nil.
+func ParseBinaryRepresentation(ctx *Context, data []byte, pattern Pattern) (Serializable, error) {
+	decoder := &binaryDecoder{ctx: ctx, data: data}
+	value, err := decoder.readValue(pattern, 0)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.pos != len(data) {
+		return nil, fmt.Errorf("%w: unexpected data after the value", ErrInvalidBinaryRepresentation)
+	}
+	if pattern != nil && !pattern.Test(ctx, value) {
+		return nil, ErrBinaryReprNotMatchingPattern
+	}
+	return value, nil
+}
+
+// entryPatternForBinaryRepr returns the pattern of the property named key, or nil.
+func entryPatternForBinaryRepr(pattern Pattern, key string) Pattern {
+	switch p := pattern.(type) {
+	case *ObjectPattern:
+		entryPattern, _, _ := p.Entry(key)
+		return entryPattern
+	case *RecordPattern:
+		entryPattern, _, _ := p.Entry(key)
+		return entryPattern
+	}
+	return nil
+}
+
+// elementPatternForBinaryRepr returns the pattern of the element at index, or nil.
+func elementPatternForBinaryRepr(pattern Pattern, index int) Pattern {
+	switch p := pattern.(type) {
+	case *ListPattern:
+		elementPattern, _ := p.ElementPatternAt(index)
+		return elementPattern
+	case *TuplePattern:
+		elementPattern, _ := p.ElementPatternAt(index)
+		return elementPattern
+	}
+	return nil
+}
+
+type binaryEncoder struct {
+	ctx        *Context
+	reprConfig *ReprConfig
+	buf        []byte
+}
+
+func (e *binaryEncoder) writeHead(majorType byte, arg uint64) {
+	major := majorType << 5
+	switch {
+	case arg < 24:
+		e.buf = append(e.buf, major|byte(arg))
+	case arg <= math.MaxUint8:
+		e.buf = append(e.buf, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		e.buf = append(e.buf, major|25)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(arg))
+	case arg <= math.MaxUint32:
+		e.buf = append(e.buf, major|26)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(arg))
+	default:
+		e.buf = append(e.buf, major|27)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, arg)
+	}
+}
+
+func (e *binaryEncoder) writeInt(i int64) {
+	if i >= 0 {
+		e.writeHead(cborUnsignedInt, uint64(i))
+	} else {
+		e.writeHead(cborNegativeInt, ^uint64(i)) //-1 - i
+	}
+}
+
+func (e *binaryEncoder) writeFloat(f float64) {
+	//floats that can be represented exactly with 32 bits are encoded with 32 bits.
+	if f32 := float32(f); float64(f32) == f || math.IsNaN(f) {
+		e.buf = append(e.buf, cborSimpleFloat<<5|cborFloat32)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(f32))
+		return
+	}
+	e.buf = append(e.buf, cborSimpleFloat<<5|cborFloat64)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+func (e *binaryEncoder) writeText(s string) {
+	e.writeHead(cborTextString, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *binaryEncoder) writeTaggedText(tag uint64, s string) {
+	e.writeHead(cborTag, tag)
+	e.writeText(s)
+}
+
+func (e *binaryEncoder) writeTaggedInt(tag uint64, i int64) {
+	e.writeHead(cborTag, tag)
+	e.writeInt(i)
+}
+
+func (e *binaryEncoder) writeTaggedTime(tag uint64, t time.Time) error {
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	e.writeHead(cborTag, tag)
+	e.writeHead(cborByteString, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+	return nil
+}
+
+func (e *binaryEncoder) writeValue(v Serializable, pattern Pattern, depth int) error {
+	if depth > MAX_BINARY_REPR_DEPTH {
+		return ErrMaximumBinaryReprDepthReached
+	}
+
+	switch val := v.(type) {
+	case NilT:
+		e.buf = append(e.buf, cborSimpleFloat<<5|cborNull)
+	case Bool:
+		if val {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborTrue)
+		} else {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborFalse)
+		}
+	case Int:
+		e.writeInt(int64(val))
+	case Float:
+		e.writeFloat(float64(val))
+	case String:
+		e.writeText(string(val))
+	case Rune:
+		e.writeTaggedInt(binaryTagRune, int64(val))
+	case *Object:
+		return e.writeObject(val, pattern, depth)
+	case *Record:
+		return e.writeRecord(val, pattern, depth)
+	case *List:
+		return e.writeList(val, pattern, depth)
+	case *Tuple:
+		e.writeHead(cborTag, binaryTagTuple)
+		e.writeHead(cborArray, uint64(len(val.elements)))
+		for i, elem := range val.elements {
+			if err := e.writeValue(elem, elementPatternForBinaryRepr(pattern, i), depth+1); err != nil {
+				return err
+			}
+		}
+	case Path:
+		e.writeTaggedText(binaryTagPath, string(val))
+	case PathPattern:
+		e.writeTaggedText(binaryTagPathPattern, string(val))
+	case URL:
+		e.writeTaggedText(binaryTagURL, string(val))
+	case URLPattern:
+		e.writeTaggedText(binaryTagURLPattern, string(val))
+	case Host:
+		e.writeTaggedText(binaryTagHost, string(val))
+	case HostPattern:
+		e.writeTaggedText(binaryTagHostPattern, string(val))
+	case Scheme:
+		e.writeTaggedText(binaryTagScheme, string(val))
+	case EmailAddress:
+		e.writeTaggedText(binaryTagEmailAddress, string(val))
+	case Identifier:
+		e.writeTaggedText(binaryTagIdentifier, string(val))
+	case PropertyName:
+		e.writeTaggedText(binaryTagPropertyName, string(val))
+	case ByteCount:
+		e.writeTaggedInt(binaryTagByteCount, int64(val))
+	case LineCount:
+		if val < 0 {
+			return ErrNoRepresentation
+		}
+		e.writeTaggedInt(binaryTagLineCount, int64(val))
+	case RuneCount:
+		if val < 0 {
+			return ErrNoRepresentation
+		}
+		e.writeTaggedInt(binaryTagRuneCount, int64(val))
+	case ByteRate:
+		if val < 0 {
+			return ErrNoRepresentation
+		}
+		e.writeTaggedInt(binaryTagByteRate, int64(val))
+	case Frequency:
+		if val < 0 {
+			return ErrNoRepresentation
+		}
+		e.writeHead(cborTag, binaryTagFrequency)
+		e.writeFloat(float64(val))
+	case Duration:
+		if val < 0 {
+			return ErrNoRepresentation
+		}
+		e.writeTaggedInt(binaryTagDuration, int64(val))
+	case Year:
+		return e.writeTaggedTime(binaryTagYear, time.Time(val))
+	case Date:
+		return e.writeTaggedTime(binaryTagDate, time.Time(val))
+	case DateTime:
+		return e.writeTaggedTime(binaryTagDateTime, time.Time(val))
+	case IntRange:
+		e.writeHead(cborTag, binaryTagIntRange)
+		e.writeHead(cborArray, 2)
+		if val.unknownStart {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborNull)
+		} else {
+			e.writeInt(val.start)
+		}
+		e.writeInt(val.end)
+	case FloatRange:
+		e.writeHead(cborTag, binaryTagFloatRange)
+		e.writeHead(cborArray, 3)
+		if val.unknownStart {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborNull)
+		} else {
+			e.writeFloat(val.start)
+		}
+		e.writeFloat(val.end)
+		if val.inclusiveEnd {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborTrue)
+		} else {
+			e.buf = append(e.buf, cborSimpleFloat<<5|cborFalse)
+		}
+	case RuneRange:
+		e.writeHead(cborTag, binaryTagRuneRange)
+		e.writeHead(cborArray, 2)
+		e.writeInt(int64(val.Start))
+		e.writeInt(int64(val.End))
+	default:
+		repr, err := GetJSONRepresentationWithConfig(v, e.ctx, JSONSerializationConfig{
+			ReprConfig: e.reprConfig,
+			Pattern:    pattern,
+		})
+		if err != nil {
+			return err
+		}
+		e.writeTaggedText(binaryTagJSON, repr)
+	}
+	return nil
+}
+
+func (e *binaryEncoder) writeObject(obj *Object, pattern Pattern, depth int) error {
+	closestState := e.ctx.GetClosestState()
+	obj._lock(closestState)
+	defer obj._unlock(closestState)
+
+	var visibility *ValueVisibility
+	if obj.hasAdditionalFields() {
+		visibility, _ = GetVisibility(obj.visibilityId)
+	}
+
+	var visibleIndexes []int
+	for i, k := range obj.keys {
+		if e.reprConfig.IsPropertyVisible(k, obj.values[i], visibility, e.ctx) {
+			visibleIndexes = append(visibleIndexes, i)
+		}
+	}
+
+	hasURL := obj.hasAdditionalFields() && obj.url != ""
+	entryCount := len(visibleIndexes)
+	if hasURL {
+		entryCount++
+	}
+
+	e.writeHead(cborTag, binaryTagObject)
+	e.writeHead(cborMap, uint64(entryCount))
+
+	//meta properties
+
+	if hasURL {
+		e.writeText(URL_METADATA_KEY)
+		e.writeText(obj.url.UnderlyingString())
+	}
+
+	for _, i := range visibleIndexes {
+		key := obj.keys[i]
+		e.writeText(key)
+		if err := e.writeValue(obj.values[i], entryPatternForBinaryRepr(pattern, key), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *binaryEncoder) writeRecord(rec *Record, pattern Pattern, depth int) error {
+	var visibleIndexes []int
+	for i, k := range rec.keys {
+		if e.reprConfig.IsPropertyVisible(k, rec.values[i], nil, e.ctx) {
+			visibleIndexes = append(visibleIndexes, i)
+		}
+	}
+
+	e.writeHead(cborTag, binaryTagRecord)
+	e.writeHead(cborMap, uint64(len(visibleIndexes)))
+
+	for _, i := range visibleIndexes {
+		key := rec.keys[i]
+		e.writeText(key)
+		if err := e.writeValue(rec.values[i], entryPatternForBinaryRepr(pattern, key), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *binaryEncoder) writeList(list *List, pattern Pattern, depth int) error {
+	length := list.Len()
+
+	e.writeHead(cborTag, binaryTagList)
+	e.writeHead(cborArray, uint64(length))
+
+	for i := 0; i < length; i++ {
+		elem := list.At(e.ctx, i).(Serializable)
+		if !e.reprConfig.IsValueVisible(elem) {
+			return ErrNoRepresentation
+		}
+		if err := e.writeValue(elem, elementPatternForBinaryRepr(pattern, i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type binaryDecoder struct {
+	ctx  *Context
+	data []byte
+	pos  int
+}
+
+func (d *binaryDecoder) errUnexpectedEnd() error {
+	return fmt.Errorf("%w: unexpected end of data", ErrInvalidBinaryRepresentation)
+}
+
+// readHead reads the head of a data item and returns its major type, its additional information and its argument.
+// The argument of major type 7 data items is the raw value of the float (if any).
+func (d *binaryDecoder) readHead() (majorType byte, info byte, arg uint64, _ error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, d.errUnexpectedEnd()
+	}
+	initialByte := d.data[d.pos]
+	d.pos++
+
+	majorType = initialByte >> 5
+	info = initialByte & 0x1f
+
+	argLength := 0
+	switch {
+	case info < 24:
+		return majorType, info, uint64(info), nil
+	case info == 24:
+		argLength = 1
+	case info == 25:
+		argLength = 2
+	case info == 26:
+		argLength = 4
+	case info == 27:
+		argLength = 8
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: indefinite-length items are not supported", ErrInvalidBinaryRepresentation)
+	}
+
+	if d.pos+argLength > len(d.data) {
+		return 0, 0, 0, d.errUnexpectedEnd()
+	}
+
+	b := d.data[d.pos : d.pos+argLength]
+	d.pos += argLength
+
+	switch argLength {
+	case 1:
+		arg = uint64(b[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(b))
+	default:
+		arg = binary.BigEndian.Uint64(b)
+	}
+	return majorType, info, arg, nil
+}
+
+// readBytes reads the content of a byte string or a text string whose head has already been read.
+func (d *binaryDecoder) readBytes(length uint64) ([]byte, error) {
+	if length > uint64(len(d.data)-d.pos) {
+		return nil, d.errUnexpectedEnd()
+	}
+	b := d.data[d.pos : d.pos+int(length)]
+	d.pos += int(length)
+	return b, nil
+}
+
+// readContainerLength checks that the length of an array or map is plausible before anything is allocated.
+func (d *binaryDecoder) readContainerLength(expectedMajorType byte) (int, error) {
+	majorType, _, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if majorType != expectedMajorType {
+		return 0, fmt.Errorf("%w: unexpected major type %d", ErrInvalidBinaryRepresentation, majorType)
+	}
+	//each element takes at least one byte.
+	if arg > uint64(len(d.data)-d.pos) {
+		return 0, d.errUnexpectedEnd()
+	}
+	return int(arg), nil
+}
+
+func (d *binaryDecoder) readText() (string, error) {
+	majorType, _, arg, err := d.readHead()
+	if err != nil {
+		return "", err
+	}
+	if majorType != cborTextString {
+		return "", fmt.Errorf("%w: a text string was expected", ErrInvalidBinaryRepresentation)
+	}
+	b, err := d.readBytes(arg)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("%w: invalid UTF-8 in text string", ErrInvalidBinaryRepresentation)
+	}
+	return string(b), nil
+}
+
+func (d *binaryDecoder) readInt() (int64, error) {
+	v, err := d.readValue(nil, MAX_BINARY_REPR_DEPTH)
+	if err != nil {
+		return 0, err
+	}
+	i, ok := v.(Int)
+	if !ok {
+		return 0, fmt.Errorf("%w: an integer was expected", ErrInvalidBinaryRepresentation)
+	}
+	return int64(i), nil
+}
+
+func (d *binaryDecoder) readFloat() (float64, error) {
+	v, err := d.readValue(nil, MAX_BINARY_REPR_DEPTH)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.(Float)
+	if !ok {
+		return 0, fmt.Errorf("%w: a float was expected", ErrInvalidBinaryRepresentation)
+	}
+	return float64(f), nil
+}
+
+// readIsNull reads the next data item if it is null, it is used to read the start of ranges having an unknown start.
+func (d *binaryDecoder) readIsNull() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborSimpleFloat<<5|cborNull {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+func (d *binaryDecoder) readTime() (time.Time, error) {
+	majorType, _, arg, err := d.readHead()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if majorType != cborByteString {
+		return time.Time{}, fmt.Errorf("%w: a byte string was expected", ErrInvalidBinaryRepresentation)
+	}
+	b, err := d.readBytes(arg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var t time.Time
+	if err := t.UnmarshalBinary(b); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidBinaryRepresentation, err)
+	}
+	return t, nil
+}
+
+func (d *binaryDecoder) readValue(pattern Pattern, depth int) (Serializable, error) {
+	if depth > MAX_BINARY_REPR_DEPTH {
+		return nil, ErrMaximumBinaryReprDepthReached
+	}
+
+	majorType, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch majorType {
+	case cborUnsignedInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", ErrInvalidBinaryRepresentation)
+		}
+		return Int(arg), nil
+	case cborNegativeInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", ErrInvalidBinaryRepresentation)
+		}
+		return Int(^int64(arg)), nil
+	case cborTextString:
+		b, err := d.readBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("%w: invalid UTF-8 in text string", ErrInvalidBinaryRepresentation)
+		}
+		return String(b), nil
+	case cborSimpleFloat:
+		switch info {
+		case cborFalse:
+			return False, nil
+		case cborTrue:
+			return True, nil
+		case cborNull:
+			return Nil, nil
+		case cborFloat32:
+			return Float(math.Float32frombits(uint32(arg))), nil
+		case cborFloat64:
+			return Float(math.Float64frombits(arg)), nil
+		}
+		return nil, fmt.Errorf("%w: unsupported simple value %d", ErrInvalidBinaryRepresentation, info)
+	case cborTag:
+		return d.readTaggedValue(arg, pattern, depth)
+	}
+
+	return nil, fmt.Errorf("%w: unexpected major type %d", ErrInvalidBinaryRepresentation, majorType)
+}
+
+func (d *binaryDecoder) readTaggedValue(tag uint64, pattern Pattern, depth int) (Serializable, error) {
+	switch tag {
+	case binaryTagRune:
+		r, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if r < 0 || r > utf8.MaxRune {
+			return nil, ErrInvalidRuneRepresentation
+		}
+		return Rune(r), nil
+	case binaryTagObject:
+		return d.readObject(pattern, depth)
+	case binaryTagRecord:
+		return d.readRecord(pattern, depth)
+	case binaryTagList:
+		return d.readList(pattern, depth)
+	case binaryTagTuple:
+		length, err := d.readContainerLength(cborArray)
+		if err != nil {
+			return nil, err
+		}
+		elements := make([]Serializable, length)
+		for i := range elements {
+			elements[i], err = d.readValue(elementPatternForBinaryRepr(pattern, i), depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse element %d of tuple: %w", i, err)
+			}
+			if elements[i].IsMutable() {
+				return nil, fmt.Errorf("%w: element %d of tuple is mutable", ErrInvalidBinaryRepresentation, i)
+			}
+		}
+		return NewTuple(elements), nil
+	case binaryTagPath, binaryTagPathPattern, binaryTagURL, binaryTagURLPattern, binaryTagHost, binaryTagHostPattern,
+		binaryTagScheme, binaryTagEmailAddress, binaryTagIdentifier, binaryTagPropertyName:
+		s, err := d.readText()
+		if err != nil {
+			return nil, err
+		}
+		return makeTextualValueFromBinaryRepr(tag, s)
+	case binaryTagByteCount, binaryTagLineCount, binaryTagRuneCount, binaryTagByteRate, binaryTagDuration:
+		i, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 && tag != binaryTagByteCount {
+			return nil, fmt.Errorf("%w: negative quantity", ErrInvalidBinaryRepresentation)
+		}
+		switch tag {
+		case binaryTagByteCount:
+			return ByteCount(i), nil
+		case binaryTagLineCount:
+			return LineCount(i), nil
+		case binaryTagRuneCount:
+			return RuneCount(i), nil
+		case binaryTagByteRate:
+			return ByteRate(i), nil
+		default:
+			return Duration(i), nil
+		}
+	case binaryTagFrequency:
+		f, err := d.readFloat()
+		if err != nil {
+			return nil, err
+		}
+		if f < 0 {
+			return nil, fmt.Errorf("%w: negative quantity", ErrInvalidBinaryRepresentation)
+		}
+		return Frequency(f), nil
+	case binaryTagYear, binaryTagDate, binaryTagDateTime:
+		t, err := d.readTime()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case binaryTagYear:
+			return Year(t), nil
+		case binaryTagDate:
+			return Date(t), nil
+		default:
+			return DateTime(t), nil
+		}
+	case binaryTagIntRange:
+		if length, err := d.readContainerLength(cborArray); err != nil {
+			return nil, err
+		} else if length != 2 {
+			return nil, fmt.Errorf("%w: invalid integer range", ErrInvalidBinaryRepresentation)
+		}
+		unknownStart := d.readIsNull()
+		var start int64
+		if !unknownStart {
+			i, err := d.readInt()
+			if err != nil {
+				return nil, err
+			}
+			start = i
+		}
+		end, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if unknownStart {
+			return NewUnknownStartIntRange(end), nil
+		}
+		if start > end {
+			return nil, errors.New("invalid integer range: start > end")
+		}
+		return NewIntRange(start, end), nil
+	case binaryTagFloatRange:
+		if length, err := d.readContainerLength(cborArray); err != nil {
+			return nil, err
+		} else if length != 3 {
+			return nil, fmt.Errorf("%w: invalid float range", ErrInvalidBinaryRepresentation)
+		}
+		unknownStart := d.readIsNull()
+		var start float64
+		if !unknownStart {
+			f, err := d.readFloat()
+			if err != nil {
+				return nil, err
+			}
+			start = f
+		}
+		end, err := d.readFloat()
+		if err != nil {
+			return nil, err
+		}
+		inclusiveEnd, err := d.readValue(nil, MAX_BINARY_REPR_DEPTH)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := inclusiveEnd.(Bool); !ok {
+			return nil, fmt.Errorf("%w: invalid float range", ErrInvalidBinaryRepresentation)
+		}
+		if unknownStart {
+			return NewUnknownStartFloatRange(end, bool(inclusiveEnd.(Bool))), nil
+		}
+		if start > end {
+			return nil, errors.New("invalid float range: start > end")
+		}
+		return NewFloatRange(start, end, bool(inclusiveEnd.(Bool))), nil
+	case binaryTagRuneRange:
+		if length, err := d.readContainerLength(cborArray); err != nil {
+			return nil, err
+		} else if length != 2 {
+			return nil, fmt.Errorf("%w: invalid rune range", ErrInvalidBinaryRepresentation)
+		}
+		start, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		end, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if start < 0 || end > utf8.MaxRune || start > end {
+			return nil, fmt.Errorf("%w: invalid rune range", ErrInvalidBinaryRepresentation)
+		}
+		return RuneRange{Start: rune(start), End: rune(end)}, nil
+	case binaryTagJSON:
+		repr, err := d.readText()
+		if err != nil {
+			return nil, err
+		}
+		return ParseJSONRepresentation(d.ctx, repr, pattern)
+	}
+
+	return nil, fmt.Errorf("%w: unknown tag %d", ErrInvalidBinaryRepresentation, tag)
+}
+
+func makeTextualValueFromBinaryRepr(tag uint64, s string) (Serializable, error) {
+	switch tag {
+	case binaryTagPath:
+		pth := Path(s)
+		if err := pth.Validate(); err != nil {
+			return nil, err
+		}
+		return pth, nil
+	case binaryTagPathPattern:
+		return PathPattern(s), nil
+	case binaryTagURL:
+		url := URL(s)
+		if err := url.Validate(); err != nil {
+			if err == ErrMissingURLSpecificFeature {
+				//fix
+				return url + "/", nil
+			}
+			return nil, err
+		}
+		return url, nil
+	case binaryTagURLPattern:
+		return URLPattern(s), nil
+	case binaryTagHost:
+		host := Host(s)
+		if err := host.Validate(); err != nil {
+			return nil, err
+		}
+		return host, nil
+	case binaryTagHostPattern:
+		return HostPattern(s), nil
+	case binaryTagScheme:
+		scheme := Scheme(s)
+		if err := scheme.Validate(); err != nil {
+			return nil, err
+		}
+		return scheme, nil
+	case binaryTagEmailAddress:
+		return EmailAddress(s), nil
+	case binaryTagIdentifier:
+		return Identifier(s), nil
+	default:
+		return PropertyName(s), nil
+	}
+}
+
+func (d *binaryDecoder) readObject(pattern Pattern, depth int) (*Object, error) {
+	entryCount, err := d.readContainerLength(cborMap)
+	if err != nil {
+		return nil, err
+	}
+
+	objectPattern, _ := pattern.(*ObjectPattern)
+	var effectivePatternEntries ObjectPatternEntriesHelper
+	if objectPattern != nil {
+		effectivePatternEntries = ObjectPatternEntriesHelper(objectPattern.entries)
+	}
+
+	obj := &Object{}
+
+	for i := 0; i < entryCount; i++ {
+		key, err := d.readText()
+		if err != nil {
+			return nil, err
+		}
+
+		if key == URL_METADATA_KEY {
+			s, err := d.readText()
+			if err != nil {
+				return nil, err
+			}
+			url := URL(s)
+			if err := url.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid URL: %w", err)
+			}
+			if url.Scheme().IsDatabaseScheme() {
+				url = url.WithoutQueryNorFragment()
+			}
+			obj.ensureAdditionalFields()
+			obj.url = url
+			continue
+		}
+
+		if slices.Contains(obj.keys, key) {
+			return nil, fmt.Errorf("%w: duplicate property %q", ErrInvalidBinaryRepresentation, key)
+		}
+
+		val, err := d.readValue(entryPatternForBinaryRepr(pattern, key), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of object property %q: %w", key, err)
+		}
+		obj.keys = append(obj.keys, key)
+		obj.values = append(obj.values, val)
+	}
+
+	return finalizeParsedObject(d.ctx, obj, objectPattern, effectivePatternEntries, false)
+}
+
+func (d *binaryDecoder) readRecord(pattern Pattern, depth int) (*Record, error) {
+	entryCount, err := d.readContainerLength(cborMap)
+	if err != nil {
+		return nil, err
+	}
+
+	recordPattern, ok := pattern.(*RecordPattern)
+	if !ok {
+		recordPattern = EMPTY_INEXACT_RECORD_PATTERN
+	}
+
+	rec := &Record{}
+
+	for i := 0; i < entryCount; i++ {
+		key, err := d.readText()
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(rec.keys, key) {
+			return nil, fmt.Errorf("%w: duplicate property %q", ErrInvalidBinaryRepresentation, key)
+		}
+
+		val, err := d.readValue(entryPatternForBinaryRepr(recordPattern, key), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of record property %s: %w", key, err)
+		}
+		if val.IsMutable() {
+			return nil, fmt.Errorf("%w: value of record property %s is mutable", ErrInvalidBinaryRepresentation, key)
+		}
+		rec.keys = append(rec.keys, key)
+		rec.values = append(rec.values, val)
+	}
+
+	return finalizeParsedRecord(d.ctx, rec, recordPattern, false)
+}
+
+func (d *binaryDecoder) readList(pattern Pattern, depth int) (*List, error) {
+	length, err := d.readContainerLength(cborArray)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]Serializable, length)
+	for i := range elements {
+		elements[i], err = d.readValue(elementPatternForBinaryRepr(pattern, i), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse element %d of list: %w", i, err)
+		}
+	}
+
+	//specialize the list the same way as the JSON parsing does.
+
+	listPattern, ok := pattern.(*ListPattern)
+	if !ok || listPattern.generalElementPattern == nil {
+		return NewWrappedValueListFrom(elements), nil
+	}
+
+	generalElementPattern := listPattern.generalElementPattern
+
+	if _, isIntRangePattern := generalElementPattern.(*IntRangePattern); isIntRangePattern || generalElementPattern == INT_PATTERN {
+		if ints, ok := convertElementsForBinaryRepr[Int](elements); ok {
+			return NewWrappedIntListFrom(ints), nil
+		}
+	} else if generalElementPattern == BOOL_PATTERN {
+		if bools, ok := convertElementsForBinaryRepr[Bool](elements); ok {
+			return NewWrappedBoolList(bools...), nil
+		}
+	} else if _, ok := generalElementPattern.(StringPattern); ok || generalElementPattern == STRING_PATTERN || generalElementPattern == STR_PATTERN {
+		if strings, ok := convertElementsForBinaryRepr[StringLike](elements); ok {
+			return NewWrappedStringListFrom(strings), nil
+		}
+	}
+
+	//the elements do not match the pattern, the final check will fail.
+	return NewWrappedValueListFrom(elements), nil
+}
+
+func convertElementsForBinaryRepr[T Serializable](elements []Serializable) ([]T, bool) {
+	converted := make([]T, len(elements))
+	for i, e := range elements {
+		elem, ok := e.(T)
+		if !ok {
+			return nil, false
+		}
+		converted[i] = elem
+	}
+	return converted, true
+}
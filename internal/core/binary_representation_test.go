@@ -0,0 +1,234 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/inoxlang/inox/internal/testconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRepresentation(t *testing.T) {
+	testconfig.AllowParallelization(t)
+
+	roundtrip := func(t *testing.T, ctx *Context, v Serializable, pattern Pattern) Serializable {
+		repr, err := GetBinaryRepresentation(v, ctx, BinarySerializationConfig{
+			ReprConfig: ALL_VISIBLE_REPR_CONFIG,
+			Pattern:    pattern,
+		})
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		parsed, err := ParseBinaryRepresentation(ctx, repr, pattern)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		return parsed
+	}
+
+	t.Run("simple values", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		now := time.Now().UTC()
+
+		values := []Serializable{
+			Nil, True, False,
+			Int(0), Int(23), Int(-23), Int(1 << 40), Int(-1 << 40),
+			Float(0), Float(1.5), Float(-0.1),
+			String(""), String("a"), String("é"), Rune('a'),
+			Path("/a"), PathPattern("/a/..."), URL("https://example.com/"), URLPattern("https://example.com/..."),
+			Host("https://example.com"), HostPattern("https://**.com"), Scheme("https"),
+			EmailAddress("foo@example.com"), Identifier("a"), PropertyName("a"),
+			ByteCount(1000), LineCount(2), RuneCount(3), ByteRate(4), Frequency(5.5), Duration(time.Second),
+			Year(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), Date(time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC)), DateTime(now),
+			NewIntRange(1, 10), NewIncludedEndFloatRange(1, 2), NewFloatRange(1, 2, false), RuneRange{Start: 'a', End: 'z'},
+		}
+
+		for _, v := range values {
+			assert.Equal(t, v, roundtrip(t, ctx, v, nil), "%#v", v)
+		}
+	})
+
+	t.Run("object", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		obj := NewObjectFromMapNoInit(ValMap{
+			"a": Int(1),
+			"b": NewObjectFromMapNoInit(ValMap{"c": String("c")}),
+		})
+
+		v := roundtrip(t, ctx, obj, nil)
+		if !assert.IsType(t, (*Object)(nil), v) {
+			return
+		}
+		parsed := v.(*Object)
+		assert.Equal(t, Int(1), parsed.Prop(ctx, "a"))
+		assert.Equal(t, String("c"), parsed.Prop(ctx, "b").(*Object).Prop(ctx, "c"))
+	})
+
+	t.Run("object with a URL", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		obj := NewObjectFromMapNoInit(ValMap{"a": Int(1)})
+		obj.SetURLOnce(ctx, URL("ldb://main/users/0"))
+
+		v := roundtrip(t, ctx, obj, nil)
+		if !assert.IsType(t, (*Object)(nil), v) {
+			return
+		}
+		url, ok := v.(*Object).URL()
+		if assert.True(t, ok) {
+			assert.Equal(t, URL("ldb://main/users/0"), url)
+		}
+	})
+
+	t.Run("object with duplicate keys", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		encoder := &binaryEncoder{ctx: ctx, reprConfig: ALL_VISIBLE_REPR_CONFIG}
+		encoder.writeHead(cborTag, binaryTagObject)
+		encoder.writeHead(cborMap, 2)
+		encoder.writeText("a")
+		encoder.writeInt(1)
+		encoder.writeText("a")
+		encoder.writeInt(2)
+
+		_, err := ParseBinaryRepresentation(ctx, encoder.buf, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing property with a default value", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		pattern := NewInexactObjectPattern([]ObjectPatternEntry{
+			{Name: "a", Pattern: INT_PATTERN},
+			{Name: "list", Pattern: NewListPatternOf(INT_PATTERN)},
+		})
+
+		obj := NewObjectFromMapNoInit(ValMap{"a": Int(1)})
+
+		v := roundtrip(t, ctx, obj, pattern)
+		if !assert.IsType(t, (*Object)(nil), v) {
+			return
+		}
+		assert.Equal(t, 0, v.(*Object).Prop(ctx, "list").(*List).Len())
+	})
+
+	t.Run("record", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		rec := NewRecordFromMap(ValMap{"a": Int(1), "b": NewTupleVariadic(Int(1), String("b"))})
+		assert.Equal(t, rec, roundtrip(t, ctx, rec, nil))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		list := NewWrappedValueList(Int(1), String("a"))
+		v := roundtrip(t, ctx, list, nil)
+		if assert.IsType(t, (*List)(nil), v) {
+			assert.Equal(t, list.GetOrBuildElements(ctx), v.(*List).GetOrBuildElements(ctx))
+		}
+
+		//the list should be specialized according to the pattern
+		intList := NewWrappedIntList(1, 2)
+		v = roundtrip(t, ctx, intList, NewListPatternOf(INT_PATTERN))
+		if assert.IsType(t, (*List)(nil), v) {
+			assert.IsType(t, (*IntList)(nil), v.(*List).underlyingList)
+			assert.Equal(t, intList.GetOrBuildElements(ctx), v.(*List).GetOrBuildElements(ctx))
+		}
+	})
+
+	t.Run("values without a native binary representation", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		pattern := NewInexactObjectPattern([]ObjectPatternEntry{{Name: "a", Pattern: INT_PATTERN}})
+
+		v := roundtrip(t, ctx, pattern, nil)
+		if assert.IsType(t, (*ObjectPattern)(nil), v) {
+			assert.True(t, v.(*ObjectPattern).Test(ctx, NewObjectFromMapNoInit(ValMap{"a": Int(1)})))
+		}
+	})
+
+	t.Run("value not matching the pattern", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		repr, err := GetBinaryRepresentation(String("a"), ctx, BinarySerializationConfig{ReprConfig: ALL_VISIBLE_REPR_CONFIG})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = ParseBinaryRepresentation(ctx, repr, INT_PATTERN)
+		assert.ErrorIs(t, err, ErrBinaryReprNotMatchingPattern)
+	})
+
+	t.Run("truncated and trailing data", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		obj := NewObjectFromMapNoInit(ValMap{"a": String("abc")})
+		repr, err := GetBinaryRepresentation(obj, ctx, BinarySerializationConfig{ReprConfig: ALL_VISIBLE_REPR_CONFIG})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		for i := 0; i < len(repr); i++ {
+			_, err := ParseBinaryRepresentation(ctx, repr[:i], nil)
+			assert.ErrorIs(t, err, ErrInvalidBinaryRepresentation, "length %d", i)
+		}
+
+		_, err = ParseBinaryRepresentation(ctx, append(repr, 0), nil)
+		assert.ErrorIs(t, err, ErrInvalidBinaryRepresentation)
+	})
+}
+
+func TestStorageSequenceRepresentation(t *testing.T) {
+	testconfig.AllowParallelization(t)
+
+	for _, format := range []SerializationFormat{JSON_SERIALIZATION_FORMAT, BINARY_SERIALIZATION_FORMAT} {
+		format := format
+
+		t.Run(format.String(), func(t *testing.T) {
+			ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+			defer ctx.CancelGracefully()
+
+			elementPatterns := []Pattern{STR_PATTERN, INT_PATTERN}
+			elements := []Serializable{String("a"), Int(1), String("b"), Int(2)}
+
+			serialized, err := GetStorageSequenceRepresentation(ctx, elements, elementPatterns, format)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var parsed []Serializable
+			err = ParseStorageSequenceRepresentation(ctx, serialized, elementPatterns, format, func(index int, elem Serializable) error {
+				assert.Equal(t, len(parsed), index)
+				parsed = append(parsed, elem)
+				return nil
+			})
+			if assert.NoError(t, err) {
+				assert.Equal(t, elements, parsed)
+			}
+
+			//elements not matching their pattern
+			_, err = GetStorageSequenceRepresentation(ctx, []Serializable{Int(1)}, []Pattern{STR_PATTERN}, format)
+			if err == nil {
+				serialized, _ := GetStorageSequenceRepresentation(ctx, []Serializable{Int(1)}, []Pattern{INT_PATTERN}, format)
+				err = ParseStorageSequenceRepresentation(ctx, serialized, []Pattern{STR_PATTERN}, format, func(index int, elem Serializable) error {
+					return nil
+				})
+				assert.Error(t, err)
+			}
+		})
+	}
+}
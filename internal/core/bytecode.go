@@ -12,8 +12,10 @@ import (
 // The bytecode instructions are in the *CompiledFunction.
 type Bytecode struct {
 	module    *Module
+	mainChunk *parse.ParsedChunkSource //main chunk of the module at compilation time
 	constants []Value
 	main      *CompiledFunction
+	optimized bool
 }
 
 // Constants returns the constants used during bytecode interpretation, the slice should not be modified.
@@ -57,11 +59,15 @@ func (b *Bytecode) Format(ctx *Context, leftPadding string) string {
 type CompiledFunction struct {
 	ParamCount   int
 	IsVariadic   bool
-	LocalCount   int // includes parameters
+	LocalCount   int      // includes parameters
+	LocalNames   []string //name of each local, the same name can be present at several indexes
 	Instructions []byte
 	SourceMap    map[int]instructionSourcePosition
 	Bytecode     *Bytecode //bytecode containing the function
 
+	//position of the first instruction of each statement -> statement, statements without instructions are not present.
+	StatementStarts map[int]parse.Node
+
 	SourceNodeSpan parse.NodeSpan
 	IncludedChunk  *parse.ParsedChunkSource //set if the function is defined in an included chunk
 }
@@ -188,6 +194,7 @@ const (
 	OpCreateSequenceStringPattern
 	OpCreatePatternNamespace
 	OpCreateOptionalPattern
+	OpCreateGenericPattern
 	OpToPattern
 	OpToBool
 	OpCreateString
@@ -263,6 +270,7 @@ const (
 	OpConcatTuples
 	OpRange
 	OpMemb
+	OpGetLocalMemb //fusion of OpGetLocal and OpMemb emitted by the optimizer
 	OpGetBoolField
 	OpGetIntField
 	OpGetFloatField
@@ -344,6 +352,7 @@ var OpcodeNames = [...]string{
 	OpCreatePatternNamespace:       "CRT_PNS",
 	OpToPattern:                    "TO_PATT",
 	OpCreateOptionalPattern:        "CRT_OPTP",
+	OpCreateGenericPattern:         "CRT_GENP",
 	OpToBool:                       "TO_BOOL",
 	OpCreateString:                 "CRT_STR",
 	OpCreateOption:                 "CRT_OPT",
@@ -418,6 +427,7 @@ var OpcodeNames = [...]string{
 	OpConcatTuples:                 "CONCAT_TUPLES",
 	OpRange:                        "RANGE",
 	OpMemb:                         "MEMB",
+	OpGetLocalMemb:                 "GET_LOCAL_MEMB",
 	OpGetBoolField:                 "GET_BOOL_FIELD",
 	OpGetIntField:                  "GET_INT_FIELD",
 	OpGetFloatField:                "GET_FLOAT_FIELD",
@@ -500,6 +510,7 @@ var OpcodeOperands = [...][]int{
 	OpCreatePatternNamespace:       {},
 	OpToPattern:                    {},
 	OpCreateOptionalPattern:        {},
+	OpCreateGenericPattern:         {1},
 	OpToBool:                       {},
 	OpCreateString:                 {1, 1, 2},
 	OpCreateOption:                 {2},
@@ -571,6 +582,7 @@ var OpcodeOperands = [...][]int{
 	OpConcatTuples:                 {2, 2},
 	OpRange:                        {1},
 	OpMemb:                         {2},
+	OpGetLocalMemb:                 {1, 2},
 	OpGetBoolField:                 {2, 2},
 	OpGetIntField:                  {2, 2},
 	OpGetFloatField:                {2, 2},
@@ -653,6 +665,7 @@ var OpcodeConstantIndexes = [...][]bool{
 	OpCreatePatternNamespace:       {},
 	OpToPattern:                    {},
 	OpCreateOptionalPattern:        {},
+	OpCreateGenericPattern:         {false},
 	OpToBool:                       {},
 	OpCreateString:                 {false, false, true},
 	OpCreateOption:                 {true},
@@ -725,6 +738,7 @@ var OpcodeConstantIndexes = [...][]bool{
 	OpConcatTuples:                 {false, true},
 	OpRange:                        {false},
 	OpMemb:                         {true},
+	OpGetLocalMemb:                 {false, true},
 	OpGetBoolField:                 {false, false},
 	OpGetIntField:                  {false, false},
 	OpGetFloatField:                {false, false},
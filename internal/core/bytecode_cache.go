@@ -0,0 +1,186 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	BYTECODE_CACHE_RELPATH     = "inox/bytecode"
+	BYTECODE_CACHE_FILE_SUFFIX = ".bc"
+)
+
+var (
+	runtimeFingerprint     string
+	runtimeFingerprintOnce sync.Once
+)
+
+// A BytecodeCache stores the bytecode of modules in a directory of the OS filesystem. Entries are keyed by a hash
+// of the sources of the module (main chunk, included chunks and imported modules), of the compilation options and
+// of the running executable, so a change to any of them invalidates the entry.
+type BytecodeCache struct {
+	dir string
+}
+
+func NewBytecodeCache(dir string) *BytecodeCache {
+	return &BytecodeCache{dir: dir}
+}
+
+// DefaultBytecodeCacheDir returns the default directory of bytecode caches: $XDG_CACHE_HOME/inox/bytecode.
+func DefaultBytecodeCacheDir() string {
+	return filepath.Join(xdg.CacheHome, BYTECODE_CACHE_RELPATH)
+}
+
+// GetOrCompile returns the cached bytecode of input.Mod if the cache is valid, otherwise it compiles the module and
+// caches the bytecode. The cache is bypassed if input.TraceWriter is set because no compilation trace can be written.
+// Errors related to the cache itself are ignored: the module is compiled in this case.
+func (c *BytecodeCache) GetOrCompile(input CompilationInput) (bytecode *Bytecode, cached bool, _ error) {
+	if input.TraceWriter != nil {
+		bytecode, err := Compile(input)
+		return bytecode, false, err
+	}
+
+	path := filepath.Join(c.dir, ComputeBytecodeCacheKey(input)+BYTECODE_CACHE_FILE_SUFFIX)
+
+	if data, err := os.ReadFile(path); err == nil {
+		bytecode, err := DeserializeBytecode(data, input)
+		if err == nil {
+			return bytecode, true, nil
+		}
+		//invalid entry
+		os.Remove(path)
+	}
+
+	bytecode, err := Compile(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := SerializeBytecode(bytecode)
+	if err == nil {
+		c.write(path, data)
+	} else if !errors.Is(err, ErrUnsupportedBytecodeConstant) {
+		return nil, false, err
+	}
+
+	return bytecode, false, nil
+}
+
+// write writes an entry, errors are ignored because the cache is optional.
+// The data is first written to a temporary file that is then renamed, so readers never see a partial entry.
+func (c *BytecodeCache) write(path string, data []byte) {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+
+	tempFile, err := os.CreateTemp(c.dir, "bytecode-*.tmp")
+	if err != nil {
+		return
+	}
+	tempPath := tempFile.Name()
+
+	_, writeErr := tempFile.Write(data)
+	closeErr := tempFile.Close()
+
+	if writeErr != nil || closeErr != nil || os.Rename(tempPath, path) != nil {
+		os.Remove(tempPath)
+	}
+}
+
+// ComputeBytecodeCacheKey computes the key of the cached bytecode of input.Mod (hex-encoded SHA-256).
+func ComputeBytecodeCacheKey(input CompilationInput) string {
+	h := sha256.New()
+
+	writeHashField(h, getRuntimeFingerprint())
+	writeHashField(h, strconv.Itoa(BYTECODE_FORMAT_VERSION))
+	writeHashField(h, strconv.FormatBool(input.IsTestingEnabled))
+	writeHashField(h, strconv.FormatBool(input.IsImportTestingEnabled))
+	writeHashField(h, strconv.FormatBool(input.IsBenchmarkingEnabled))
+
+	//the set of globals determines how variables are resolved.
+	globalNames := make([]string, 0, len(input.Globals))
+	for name := range input.Globals {
+		globalNames = append(globalNames, name)
+	}
+	sort.Strings(globalNames)
+
+	writeHashField(h, strconv.Itoa(len(globalNames)))
+	for _, name := range globalNames {
+		writeHashField(h, name)
+	}
+
+	hashModuleSources(h, input.Mod, map[*Module]struct{}{})
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashModuleSources writes the name and the code of the chunks of a module and of its imported modules (recursively).
+func hashModuleSources(h hash.Hash, mod *Module, visited map[*Module]struct{}) {
+	if _, ok := visited[mod]; ok {
+		return
+	}
+	visited[mod] = struct{}{}
+
+	chunks := getModuleChunks(mod)
+	writeHashField(h, strconv.Itoa(len(chunks)))
+	for _, chunk := range chunks {
+		writeHashField(h, chunk.Name())
+		writeHashField(h, chunk.Source.Code())
+	}
+
+	importedModuleNames := make([]string, 0, len(mod.DirectlyImportedModules))
+	for name := range mod.DirectlyImportedModules {
+		importedModuleNames = append(importedModuleNames, name)
+	}
+	sort.Strings(importedModuleNames)
+
+	writeHashField(h, strconv.Itoa(len(importedModuleNames)))
+	for _, name := range importedModuleNames {
+		writeHashField(h, name)
+		hashModuleSources(h, mod.DirectlyImportedModules[name], visited)
+	}
+}
+
+// writeHashField writes a length-prefixed field, the prefix prevents collisions between different field sequences.
+func writeHashField(h hash.Hash, s string) {
+	h.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	h.Write([]byte(s))
+}
+
+// getRuntimeFingerprint returns a string identifying the running executable: the build information and
+// the size & modification time of the executable, the latter are included because development builds
+// share the same version.
+func getRuntimeFingerprint() string {
+	runtimeFingerprintOnce.Do(func() {
+		fingerprint := ""
+
+		if info, ok := debug.ReadBuildInfo(); ok {
+			fingerprint += info.GoVersion + " " + info.Main.Path + "@" + info.Main.Version
+			for _, setting := range info.Settings {
+				if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+					fingerprint += " " + setting.Key + "=" + setting.Value
+				}
+			}
+		}
+
+		if executable, err := os.Executable(); err == nil {
+			if stat, err := os.Stat(executable); err == nil {
+				fingerprint += " " + strconv.FormatInt(stat.Size(), 10) + " " + strconv.FormatInt(stat.ModTime().UnixNano(), 10)
+			}
+		}
+
+		runtimeFingerprint = fingerprint
+	})
+	return runtimeFingerprint
+}
@@ -0,0 +1,981 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+)
+
+const (
+	//version of the binary format of serialized bytecode, it should be incremented each time the format changes.
+	BYTECODE_FORMAT_VERSION = 1
+
+	BYTECODE_MAGIC = "INOXBC"
+)
+
+var (
+	ErrUnsupportedBytecodeConstant = errors.New("bytecode contains a constant that cannot be serialized")
+	ErrInvalidSerializedBytecode   = errors.New("invalid serialized bytecode")
+)
+
+// tags of serialized constants
+const (
+	bcTagNil byte = iota + 1
+	bcTagBool
+	bcTagInt
+	bcTagFloat
+	bcTagString
+	bcTagRune
+	bcTagByte
+	bcTagPath
+	bcTagPathPattern
+	bcTagURL
+	bcTagURLPattern
+	bcTagScheme
+	bcTagHost
+	bcTagHostPattern
+	bcTagIdentifier
+	bcTagPropertyName
+	bcTagByteCount
+	bcTagRuneCount
+	bcTagLineCount
+	bcTagByteRate
+	bcTagFrequency
+	bcTagDuration
+	bcTagYear
+	bcTagDate
+	bcTagDateTime
+	bcTagPort
+	bcTagOption
+	bcTagKeyList
+	bcTagByteSlice
+	bcTagValueList
+	bcTagBoolList
+	bcTagTuple
+	bcTagRecord
+	bcTagRegexPattern
+	bcTagExactStringPattern
+	bcTagRuneRangeStringPattern
+	bcTagIntRangeStringPattern
+	bcTagNamedSegmentPathPattern
+	bcTagFunctionPattern
+	bcTagQuantityRange
+	bcTagAstNode
+	bcTagEmbeddedModuleChunkAstNode
+	bcTagInoxFunction
+	bcTagSerializablePattern
+)
+
+// SerializeBytecode serializes the bytecode of a module to a versioned binary format. AST nodes referenced by the bytecode
+// are serialized as references into the chunks of the module, so the deserialization requires the same parsed module.
+// ErrUnsupportedBytecodeConstant is returned if a constant cannot be serialized (e.g. embedded module of a spawn expression).
+func SerializeBytecode(b *Bytecode) ([]byte, error) {
+	if b.module == nil {
+		return nil, errors.New("bytecode has no module")
+	}
+
+	e := &bytecodeEncoder{
+		chunks: getModuleChunks(b.module),
+	}
+
+	e.buf = append(e.buf, BYTECODE_MAGIC...)
+	e.writeUint(BYTECODE_FORMAT_VERSION)
+	e.writeUint(uint64(len(e.chunks)))
+
+	e.writeUint(uint64(len(b.constants)))
+	for _, constant := range b.constants {
+		if err := e.writeConstant(constant); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.writeCompiledFunction(b.main); err != nil {
+		return nil, err
+	}
+
+	return e.buf, nil
+}
+
+// DeserializeBytecode deserializes bytecode serialized by SerializeBytecode. input should contain the module
+// and the data that would be passed to Compile: symbolic data and static check data are used to restore
+// the compiled functions.
+func DeserializeBytecode(data []byte, input CompilationInput) (*Bytecode, error) {
+	if len(data) < len(BYTECODE_MAGIC) || string(data[:len(BYTECODE_MAGIC)]) != BYTECODE_MAGIC {
+		return nil, fmt.Errorf("%w: missing magic", ErrInvalidSerializedBytecode)
+	}
+
+	d := &bytecodeDecoder{
+		data:   data,
+		pos:    len(BYTECODE_MAGIC),
+		input:  input,
+		chunks: getModuleChunks(input.Mod),
+	}
+	d.nodes = make([][]parse.Node, len(d.chunks))
+
+	if version := d.readUint(); version != BYTECODE_FORMAT_VERSION {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidSerializedBytecode, version)
+	}
+
+	if chunkCount := d.readUint(); chunkCount != uint64(len(d.chunks)) {
+		return nil, fmt.Errorf("%w: the module does not have the same number of chunks", ErrInvalidSerializedBytecode)
+	}
+
+	bytecode := &Bytecode{module: input.Mod, mainChunk: input.Mod.MainChunk}
+	d.bytecode = bytecode
+
+	constantCount := d.readLength()
+	bytecode.constants = make([]Value, 0, constantCount)
+
+	for i := 0; i < constantCount && d.err == nil; i++ {
+		bytecode.constants = append(bytecode.constants, d.readConstant())
+	}
+
+	bytecode.main = d.readCompiledFunction()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%w: unexpected trailing data", ErrInvalidSerializedBytecode)
+	}
+
+	return bytecode, nil
+}
+
+// getModuleChunks returns the main chunk of the module followed by its included chunks.
+func getModuleChunks(mod *Module) []*parse.ParsedChunkSource {
+	chunks := []*parse.ParsedChunkSource{mod.MainChunk}
+	for _, includedChunk := range mod.FlattenedIncludedChunkList {
+		chunks = append(chunks, includedChunk.ParsedChunkSource)
+	}
+	return chunks
+}
+
+// walkChunkNodes calls fn for each node of the chunk in a deterministic order.
+func walkChunkNodes(chunk *parse.ParsedChunkSource, fn func(node parse.Node)) {
+	parse.Walk(chunk.Node, func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
+		fn(node)
+		return parse.ContinueTraversal, nil
+	}, nil)
+}
+
+type bytecodeEncoder struct {
+	buf         []byte
+	chunks      []*parse.ParsedChunkSource
+	nodeIndexes []map[parse.Node]int //lazily built, one map per chunk
+}
+
+func (e *bytecodeEncoder) writeUint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *bytecodeEncoder) writeInt(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *bytecodeEncoder) writeBool(b bool) {
+	if b {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *bytecodeEncoder) writeBytes(b []byte) {
+	e.writeUint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *bytecodeEncoder) writeString(s string) {
+	e.writeUint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *bytecodeEncoder) writeFloat(f float64) {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+func (e *bytecodeEncoder) writeTime(t time.Time) error {
+	instant, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	e.writeBytes(instant)
+	e.writeString(t.Location().String())
+	return nil
+}
+
+func (e *bytecodeEncoder) writeSpan(span parse.NodeSpan) {
+	e.writeUint(uint64(span.Start))
+	e.writeUint(uint64(span.End))
+}
+
+// writeChunkIndex writes the index of a chunk of the module, -1 is written for nil.
+func (e *bytecodeEncoder) writeChunkIndex(chunk *parse.ParsedChunkSource) error {
+	if chunk == nil {
+		e.writeInt(-1)
+		return nil
+	}
+	for i, c := range e.chunks {
+		if c == chunk {
+			e.writeInt(int64(i))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: chunk %s is not part of the module", ErrUnsupportedBytecodeConstant, chunk.Name())
+}
+
+// writeNodeRef writes a reference to a node: the index of its chunk and its index in the chunk.
+func (e *bytecodeEncoder) writeNodeRef(node parse.Node) error {
+	if e.nodeIndexes == nil {
+		e.nodeIndexes = make([]map[parse.Node]int, len(e.chunks))
+	}
+
+	for chunkIndex, chunk := range e.chunks {
+		indexes := e.nodeIndexes[chunkIndex]
+		if indexes == nil {
+			indexes = map[parse.Node]int{}
+			walkChunkNodes(chunk, func(n parse.Node) {
+				if _, ok := indexes[n]; !ok {
+					indexes[n] = len(indexes)
+				}
+			})
+			e.nodeIndexes[chunkIndex] = indexes
+		}
+
+		if nodeIndex, ok := indexes[node]; ok {
+			e.writeUint(uint64(chunkIndex))
+			e.writeUint(uint64(nodeIndex))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: node of type %T is not part of the module", ErrUnsupportedBytecodeConstant, node)
+}
+
+// findEmbeddedModuleOfChunk finds the embedded module whose ToChunk method returned chunk.
+func (e *bytecodeEncoder) findEmbeddedModuleOfChunk(chunk *parse.Chunk) (*parse.EmbeddedModule, bool) {
+	var found *parse.EmbeddedModule
+
+	for _, c := range e.chunks {
+		parse.Walk(c.Node, func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
+			embeddedModule, ok := node.(*parse.EmbeddedModule)
+			if ok && embeddedModule.Span == chunk.Span && embeddedModule.Manifest == chunk.Manifest &&
+				len(embeddedModule.Statements) == len(chunk.Statements) &&
+				(len(chunk.Statements) == 0 || embeddedModule.Statements[0] == chunk.Statements[0]) {
+				found = embeddedModule
+				return parse.StopTraversal, nil
+			}
+			return parse.ContinueTraversal, nil
+		}, nil)
+
+		if found != nil {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+func (e *bytecodeEncoder) writeCompiledFunction(fn *CompiledFunction) error {
+	e.writeUint(uint64(fn.ParamCount))
+	e.writeBool(fn.IsVariadic)
+	e.writeUint(uint64(fn.LocalCount))
+
+	e.writeUint(uint64(len(fn.LocalNames)))
+	for _, name := range fn.LocalNames {
+		e.writeString(name)
+	}
+
+	e.writeBytes(fn.Instructions)
+
+	//source map, sorted by instruction address to make the output deterministic.
+	addresses := make([]int, 0, len(fn.SourceMap))
+	for ip := range fn.SourceMap {
+		addresses = append(addresses, ip)
+	}
+	sort.Ints(addresses)
+
+	e.writeUint(uint64(len(addresses)))
+	for _, ip := range addresses {
+		pos := fn.SourceMap[ip]
+		e.writeUint(uint64(ip))
+		if err := e.writeChunkIndex(pos.chunk); err != nil {
+			return err
+		}
+		e.writeSpan(pos.span)
+	}
+
+	//statement starts
+	addresses = addresses[:0]
+	for ip := range fn.StatementStarts {
+		addresses = append(addresses, ip)
+	}
+	sort.Ints(addresses)
+
+	e.writeUint(uint64(len(addresses)))
+	for _, ip := range addresses {
+		e.writeUint(uint64(ip))
+		if err := e.writeNodeRef(fn.StatementStarts[ip]); err != nil {
+			return err
+		}
+	}
+
+	e.writeSpan(fn.SourceNodeSpan)
+
+	return e.writeChunkIndex(fn.IncludedChunk)
+}
+
+func (e *bytecodeEncoder) writeConstant(v Value) error {
+	switch val := v.(type) {
+	case NilT:
+		e.buf = append(e.buf, bcTagNil)
+	case Bool:
+		e.buf = append(e.buf, bcTagBool)
+		e.writeBool(bool(val))
+	case Int:
+		e.buf = append(e.buf, bcTagInt)
+		e.writeInt(int64(val))
+	case Float:
+		e.buf = append(e.buf, bcTagFloat)
+		e.writeFloat(float64(val))
+	case String:
+		e.buf = append(e.buf, bcTagString)
+		e.writeString(string(val))
+	case Rune:
+		e.buf = append(e.buf, bcTagRune)
+		e.writeInt(int64(val))
+	case Byte:
+		e.buf = append(e.buf, bcTagByte, byte(val))
+	case Path:
+		e.buf = append(e.buf, bcTagPath)
+		e.writeString(string(val))
+	case PathPattern:
+		e.buf = append(e.buf, bcTagPathPattern)
+		e.writeString(string(val))
+	case URL:
+		e.buf = append(e.buf, bcTagURL)
+		e.writeString(string(val))
+	case URLPattern:
+		e.buf = append(e.buf, bcTagURLPattern)
+		e.writeString(string(val))
+	case Scheme:
+		e.buf = append(e.buf, bcTagScheme)
+		e.writeString(string(val))
+	case Host:
+		e.buf = append(e.buf, bcTagHost)
+		e.writeString(string(val))
+	case HostPattern:
+		e.buf = append(e.buf, bcTagHostPattern)
+		e.writeString(string(val))
+	case Identifier:
+		e.buf = append(e.buf, bcTagIdentifier)
+		e.writeString(string(val))
+	case PropertyName:
+		e.buf = append(e.buf, bcTagPropertyName)
+		e.writeString(string(val))
+	case ByteCount:
+		e.buf = append(e.buf, bcTagByteCount)
+		e.writeInt(int64(val))
+	case RuneCount:
+		e.buf = append(e.buf, bcTagRuneCount)
+		e.writeInt(int64(val))
+	case LineCount:
+		e.buf = append(e.buf, bcTagLineCount)
+		e.writeInt(int64(val))
+	case ByteRate:
+		e.buf = append(e.buf, bcTagByteRate)
+		e.writeInt(int64(val))
+	case Frequency:
+		e.buf = append(e.buf, bcTagFrequency)
+		e.writeFloat(float64(val))
+	case Duration:
+		e.buf = append(e.buf, bcTagDuration)
+		e.writeInt(int64(val))
+	case Year:
+		e.buf = append(e.buf, bcTagYear)
+		return e.writeTime(time.Time(val))
+	case Date:
+		e.buf = append(e.buf, bcTagDate)
+		return e.writeTime(time.Time(val))
+	case DateTime:
+		e.buf = append(e.buf, bcTagDateTime)
+		return e.writeTime(time.Time(val))
+	case Port:
+		e.buf = append(e.buf, bcTagPort)
+		e.writeUint(uint64(val.Number))
+		e.writeString(string(val.Scheme))
+	case Option:
+		e.buf = append(e.buf, bcTagOption)
+		e.writeString(val.Name)
+		return e.writeConstant(val.Value)
+	case KeyList:
+		e.buf = append(e.buf, bcTagKeyList)
+		e.writeUint(uint64(len(val)))
+		for _, key := range val {
+			e.writeString(key)
+		}
+	case *ByteSlice:
+		e.buf = append(e.buf, bcTagByteSlice)
+		e.writeBool(val.isDataMutable)
+		e.writeString(string(val.contentType))
+		e.writeBytes(val.bytes)
+	case *List:
+		switch underlying := val.underlyingList.(type) {
+		case *ValueList:
+			e.buf = append(e.buf, bcTagValueList)
+			return e.writeConstants(underlying.elements)
+		case *BoolList:
+			e.buf = append(e.buf, bcTagBoolList)
+			e.writeUint(uint64(underlying.Len()))
+			for i := 0; i < underlying.Len(); i++ {
+				e.writeBool(bool(underlying.At(nil, i).(Bool)))
+			}
+		default:
+			return fmt.Errorf("%w: list of type %T", ErrUnsupportedBytecodeConstant, underlying)
+		}
+	case *Tuple:
+		e.buf = append(e.buf, bcTagTuple)
+		return e.writeConstants(val.elements)
+	case *Record:
+		e.buf = append(e.buf, bcTagRecord)
+		e.writeUint(uint64(len(val.keys)))
+		for _, key := range val.keys {
+			e.writeString(key)
+		}
+		return e.writeConstants(val.values)
+	case *RegexPattern:
+		e.buf = append(e.buf, bcTagRegexPattern)
+		e.writeString(val.regexp.String())
+	case *ExactStringPattern:
+		e.buf = append(e.buf, bcTagExactStringPattern)
+		e.writeString(string(val.value))
+	case *RuneRangeStringPattern:
+		e.buf = append(e.buf, bcTagRuneRangeStringPattern)
+		e.writeInt(int64(val.runes.Start))
+		e.writeInt(int64(val.runes.End))
+		return e.writeNodeRef(val.node)
+	case *IntRangeStringPattern:
+		e.buf = append(e.buf, bcTagIntRangeStringPattern)
+		e.writeInt(val.intRange.start)
+		e.writeInt(val.intRange.end)
+		return e.writeNodeRef(val.node)
+	case *NamedSegmentPathPattern:
+		e.buf = append(e.buf, bcTagNamedSegmentPathPattern)
+		return e.writeNodeRef(val.node)
+	case *FunctionPattern:
+		if val.node == nil {
+			return fmt.Errorf("%w: function pattern without node", ErrUnsupportedBytecodeConstant)
+		}
+		e.buf = append(e.buf, bcTagFunctionPattern)
+		return e.writeNodeRef(val.node)
+	case QuantityRange:
+		e.buf = append(e.buf, bcTagQuantityRange)
+		e.writeBool(val.unknownStart)
+		e.writeBool(val.inclusiveEnd)
+		if err := e.writeConstant(val.start); err != nil {
+			return err
+		}
+		return e.writeConstant(val.end)
+	case AstNode:
+		//chunks created from embedded modules (e.g. test suites) are not part of the AST,
+		//so we reference the embedded module instead.
+		if chunk, ok := val.Node.(*parse.Chunk); ok && chunk != e.chunks[0].Node {
+			if embeddedModule, ok := e.findEmbeddedModuleOfChunk(chunk); ok {
+				e.buf = append(e.buf, bcTagEmbeddedModuleChunkAstNode)
+				if err := e.writeNodeRef(embeddedModule); err != nil {
+					return err
+				}
+				return e.writeChunkIndex(val.chunk)
+			}
+		}
+
+		e.buf = append(e.buf, bcTagAstNode)
+		if err := e.writeNodeRef(val.Node); err != nil {
+			return err
+		}
+		return e.writeChunkIndex(val.chunk)
+	case *InoxFunction:
+		if val.compiledFunction == nil || val.originState != nil || len(val.capturedLocals) > 0 || val.treeWalkCapturedLocals != nil {
+			return fmt.Errorf("%w: function with a state", ErrUnsupportedBytecodeConstant)
+		}
+		e.buf = append(e.buf, bcTagInoxFunction)
+		if err := e.writeNodeRef(val.Node); err != nil {
+			return err
+		}
+		if err := e.writeChunkIndex(val.Chunk); err != nil {
+			return err
+		}
+		return e.writeCompiledFunction(val.compiledFunction)
+	case *TypePattern:
+		//erased type parameters without constraint.
+		if val != SERIALIZABLE_PATTERN {
+			return fmt.Errorf("%w: type pattern %s", ErrUnsupportedBytecodeConstant, val.Name)
+		}
+		e.buf = append(e.buf, bcTagSerializablePattern)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedBytecodeConstant, v)
+	}
+	return nil
+}
+
+func (e *bytecodeEncoder) writeConstants(values []Serializable) error {
+	e.writeUint(uint64(len(values)))
+	for _, value := range values {
+		if err := e.writeConstant(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type bytecodeDecoder struct {
+	data     []byte
+	pos      int
+	err      error //first error, once set all reads return zero values
+	input    CompilationInput
+	chunks   []*parse.ParsedChunkSource
+	nodes    [][]parse.Node //lazily built, one slice per chunk
+	bytecode *Bytecode
+}
+
+func (d *bytecodeDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrInvalidSerializedBytecode, fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *bytecodeDecoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if d.pos >= len(d.data) {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *bytecodeDecoder) readUint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.fail("invalid unsigned integer")
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *bytecodeDecoder) readInt() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		d.fail("invalid integer")
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+// readLength reads a length and checks that it is not greater than the number of remaining bytes,
+// since every element is encoded with at least one byte.
+func (d *bytecodeDecoder) readLength() int {
+	length := d.readUint()
+	if length > uint64(len(d.data)-d.pos) {
+		d.fail("invalid length")
+		return 0
+	}
+	return int(length)
+}
+
+func (d *bytecodeDecoder) readBool() bool {
+	return d.readByte() == 1
+}
+
+func (d *bytecodeDecoder) readBytes() []byte {
+	length := d.readLength()
+	if d.err != nil {
+		return nil
+	}
+	b := d.data[d.pos : d.pos+length : d.pos+length]
+	d.pos += length
+	return b
+}
+
+func (d *bytecodeDecoder) readString() string {
+	return string(d.readBytes())
+}
+
+func (d *bytecodeDecoder) readFloat() float64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data)-d.pos < 8 {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	f := math.Float64frombits(binary.LittleEndian.Uint64(d.data[d.pos:]))
+	d.pos += 8
+	return f
+}
+
+func (d *bytecodeDecoder) readTime() time.Time {
+	var t time.Time
+	if err := t.UnmarshalBinary(d.readBytes()); err != nil && d.err == nil {
+		d.fail("invalid time: %s", err)
+	}
+
+	locName := d.readString()
+	if d.err != nil {
+		return time.Time{}
+	}
+
+	loc, err := time.LoadLocation(locName)
+	if err != nil {
+		//keep the fixed offset decoded by UnmarshalBinary.
+		return t
+	}
+	return t.In(loc)
+}
+
+func (d *bytecodeDecoder) readSpan() parse.NodeSpan {
+	start := d.readUint()
+	end := d.readUint()
+	if start > math.MaxInt32 || end > math.MaxInt32 {
+		d.fail("invalid span")
+	}
+	return parse.NodeSpan{Start: int32(start), End: int32(end)}
+}
+
+// readChunkIndex reads a chunk index and returns the chunk (nil for -1).
+func (d *bytecodeDecoder) readChunk() *parse.ParsedChunkSource {
+	index := d.readInt()
+	if index == -1 || d.err != nil {
+		return nil
+	}
+	if index < 0 || index >= int64(len(d.chunks)) {
+		d.fail("invalid chunk index")
+		return nil
+	}
+	return d.chunks[index]
+}
+
+func (d *bytecodeDecoder) readNodeRef() parse.Node {
+	node, _ := d.readNodeRefAndChunk()
+	return node
+}
+
+// readNodeRefAndChunk reads a node reference and returns the node and the chunk containing it.
+func (d *bytecodeDecoder) readNodeRefAndChunk() (parse.Node, *parse.ParsedChunkSource) {
+	chunkIndex := d.readUint()
+	nodeIndex := d.readUint()
+	if d.err != nil {
+		return nil, nil
+	}
+
+	if chunkIndex >= uint64(len(d.chunks)) {
+		d.fail("invalid chunk index in node reference")
+		return nil, nil
+	}
+
+	nodes := d.nodes[chunkIndex]
+	if nodes == nil {
+		seen := map[parse.Node]struct{}{}
+		walkChunkNodes(d.chunks[chunkIndex], func(n parse.Node) {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				nodes = append(nodes, n)
+			}
+		})
+		d.nodes[chunkIndex] = nodes
+	}
+
+	if nodeIndex >= uint64(len(nodes)) {
+		d.fail("invalid node index in node reference")
+		return nil, nil
+	}
+	return nodes[nodeIndex], d.chunks[chunkIndex]
+}
+
+func (d *bytecodeDecoder) readCompiledFunction() *CompiledFunction {
+	fn := &CompiledFunction{
+		ParamCount: int(d.readUint()),
+		IsVariadic: d.readBool(),
+		LocalCount: int(d.readUint()),
+		Bytecode:   d.bytecode,
+	}
+
+	localNameCount := d.readLength()
+	for i := 0; i < localNameCount && d.err == nil; i++ {
+		fn.LocalNames = append(fn.LocalNames, d.readString())
+	}
+
+	fn.Instructions = d.readBytes()
+
+	sourceMapSize := d.readLength()
+	fn.SourceMap = make(map[int]instructionSourcePosition, sourceMapSize)
+	for i := 0; i < sourceMapSize && d.err == nil; i++ {
+		ip := int(d.readUint())
+		fn.SourceMap[ip] = instructionSourcePosition{
+			chunk: d.readChunk(),
+			span:  d.readSpan(),
+		}
+	}
+
+	statementCount := d.readLength()
+	fn.StatementStarts = make(map[int]parse.Node, statementCount)
+	for i := 0; i < statementCount && d.err == nil; i++ {
+		ip := int(d.readUint())
+		fn.StatementStarts[ip] = d.readNodeRef()
+	}
+
+	fn.SourceNodeSpan = d.readSpan()
+	fn.IncludedChunk = d.readChunk()
+	return fn
+}
+
+func (d *bytecodeDecoder) readConstants() []Serializable {
+	count := d.readLength()
+	values := make([]Serializable, 0, count)
+
+	for i := 0; i < count && d.err == nil; i++ {
+		value, ok := d.readConstant().(Serializable)
+		if !ok {
+			d.fail("constant is not serializable")
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func (d *bytecodeDecoder) readConstant() Value {
+	tag := d.readByte()
+	if d.err != nil {
+		return Nil
+	}
+
+	switch tag {
+	case bcTagNil:
+		return Nil
+	case bcTagBool:
+		return Bool(d.readBool())
+	case bcTagInt:
+		return Int(d.readInt())
+	case bcTagFloat:
+		return Float(d.readFloat())
+	case bcTagString:
+		return String(d.readString())
+	case bcTagRune:
+		return Rune(d.readInt())
+	case bcTagByte:
+		return Byte(d.readByte())
+	case bcTagPath:
+		return Path(d.readString())
+	case bcTagPathPattern:
+		return PathPattern(d.readString())
+	case bcTagURL:
+		return URL(d.readString())
+	case bcTagURLPattern:
+		return URLPattern(d.readString())
+	case bcTagScheme:
+		return Scheme(d.readString())
+	case bcTagHost:
+		return Host(d.readString())
+	case bcTagHostPattern:
+		return HostPattern(d.readString())
+	case bcTagIdentifier:
+		return Identifier(d.readString())
+	case bcTagPropertyName:
+		return PropertyName(d.readString())
+	case bcTagByteCount:
+		return ByteCount(d.readInt())
+	case bcTagRuneCount:
+		return RuneCount(d.readInt())
+	case bcTagLineCount:
+		return LineCount(d.readInt())
+	case bcTagByteRate:
+		return ByteRate(d.readInt())
+	case bcTagFrequency:
+		return Frequency(d.readFloat())
+	case bcTagDuration:
+		return Duration(d.readInt())
+	case bcTagYear:
+		return Year(d.readTime())
+	case bcTagDate:
+		return Date(d.readTime())
+	case bcTagDateTime:
+		return DateTime(d.readTime())
+	case bcTagPort:
+		number := d.readUint()
+		if number > math.MaxUint16 {
+			d.fail("invalid port number")
+		}
+		return Port{Number: uint16(number), Scheme: Scheme(d.readString())}
+	case bcTagOption:
+		name := d.readString()
+		return Option{Name: name, Value: d.readConstant()}
+	case bcTagKeyList:
+		count := d.readLength()
+		keys := make(KeyList, 0, count)
+		for i := 0; i < count && d.err == nil; i++ {
+			keys = append(keys, d.readString())
+		}
+		return keys
+	case bcTagByteSlice:
+		isMutable := d.readBool()
+		contentType := Mimetype(d.readString())
+		bytes := []byte(d.readString()) //copy
+		if isMutable {
+			return NewMutableByteSlice(bytes, contentType)
+		}
+		return NewImmutableByteSlice(bytes, contentType)
+	case bcTagValueList:
+		return NewWrappedValueListFrom(d.readConstants())
+	case bcTagBoolList:
+		count := d.readLength()
+		elements := make([]Bool, 0, count)
+		for i := 0; i < count && d.err == nil; i++ {
+			elements = append(elements, Bool(d.readBool()))
+		}
+		return NewWrappedBoolList(elements...)
+	case bcTagTuple:
+		return NewTuple(d.readConstants())
+	case bcTagRecord:
+		count := d.readLength()
+		keys := make([]string, 0, count)
+		for i := 0; i < count && d.err == nil; i++ {
+			keys = append(keys, d.readString())
+		}
+		values := d.readConstants()
+		if d.err != nil {
+			return Nil
+		}
+		if len(values) != len(keys) {
+			d.fail("invalid record")
+			return Nil
+		}
+		return NewRecordFromKeyValLists(keys, values)
+	case bcTagRegexPattern:
+		syntax := d.readString()
+		if d.err != nil {
+			return Nil
+		}
+		return NewRegexPattern(syntax)
+	case bcTagExactStringPattern:
+		return NewExactStringPattern(String(d.readString()))
+	case bcTagRuneRangeStringPattern:
+		lower := rune(d.readInt())
+		upper := rune(d.readInt())
+		node := d.readNodeRef()
+		if d.err != nil {
+			return Nil
+		}
+		return NewRuneRangeStringPattern(lower, upper, node)
+	case bcTagIntRangeStringPattern:
+		lower := d.readInt()
+		upper := d.readInt()
+		node := d.readNodeRef()
+		if d.err != nil {
+			return Nil
+		}
+		return NewIntRangeStringPattern(lower, upper, node)
+	case bcTagNamedSegmentPathPattern:
+		node, ok := d.readNodeRef().(*parse.NamedSegmentPathPatternLiteral)
+		if !ok {
+			d.fail("invalid node of named segment path pattern")
+			return Nil
+		}
+		return &NamedSegmentPathPattern{node: node}
+	case bcTagSerializablePattern:
+		return SERIALIZABLE_PATTERN
+	case bcTagFunctionPattern:
+		n, chunk := d.readNodeRefAndChunk()
+		node, ok := n.(*parse.FunctionPatternExpression)
+		if !ok {
+			d.fail("invalid node of function pattern")
+			return Nil
+		}
+
+		pattern := &FunctionPattern{
+			node:      node,
+			nodeChunk: chunk.Node,
+		}
+		if d.input.SymbolicData != nil {
+			if value, ok := d.input.SymbolicData.GetMostSpecificNodeValue(node); ok {
+				pattern.symbolicValue, _ = value.(*symbolic.FunctionPattern)
+			}
+		}
+		return pattern
+	case bcTagQuantityRange:
+		unknownStart := d.readBool()
+		inclusiveEnd := d.readBool()
+		start, _ := d.readConstant().(Serializable)
+		end, _ := d.readConstant().(Serializable)
+		return QuantityRange{
+			unknownStart: unknownStart,
+			inclusiveEnd: inclusiveEnd,
+			start:        start,
+			end:          end,
+		}
+	case bcTagAstNode:
+		node := d.readNodeRef()
+		return AstNode{Node: node, chunk: d.readChunk()}
+	case bcTagEmbeddedModuleChunkAstNode:
+		embeddedModule, ok := d.readNodeRef().(*parse.EmbeddedModule)
+		chunk := d.readChunk()
+		if !ok {
+			d.fail("invalid node of embedded module")
+			return Nil
+		}
+		return AstNode{Node: embeddedModule.ToChunk(), chunk: chunk}
+	case bcTagInoxFunction:
+		node := d.readNodeRef()
+		chunk := d.readChunk()
+		compiledFunction := d.readCompiledFunction()
+		if d.err != nil {
+			return Nil
+		}
+
+		var fnExpr *parse.FunctionExpression
+		switch n := node.(type) {
+		case *parse.FunctionExpression:
+			fnExpr = n
+		case *parse.FunctionDeclaration:
+			fnExpr = n.Function
+		default:
+			d.fail("invalid node of function")
+			return Nil
+		}
+
+		fn := &InoxFunction{
+			Node:             node,
+			Chunk:            chunk,
+			compiledFunction: compiledFunction,
+		}
+
+		if d.input.SymbolicData != nil {
+			if value, ok := d.input.SymbolicData.GetMostSpecificNodeValue(fnExpr); ok {
+				fn.symbolicValue, _ = value.(*symbolic.InoxFunction)
+			}
+		}
+		if d.input.StaticCheckData != nil {
+			fn.staticData = d.input.StaticCheckData.GetFnData(fnExpr)
+		}
+		return fn
+	default:
+		d.fail("unknown constant tag %d", tag)
+		return Nil
+	}
+}
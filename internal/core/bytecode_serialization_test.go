@@ -0,0 +1,157 @@
+package core
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytecodeSerialization(t *testing.T) {
+
+	parseModule := func(t *testing.T, code string) *Module {
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "core-test",
+			CodeString: code,
+		}))
+		return &Module{MainChunk: chunk}
+	}
+
+	//roundTrip compiles the module, serializes and deserializes the bytecode and then executes it.
+	roundTrip := func(t *testing.T, code string) (Value, *Bytecode, *Bytecode) {
+		mod := parseModule(t, code)
+		state := NewGlobalState(NewDefaultTestContext())
+		t.Cleanup(state.Ctx.CancelGracefully)
+		state.Module = mod
+
+		input := CompilationInput{Mod: mod, Globals: state.Globals.permanent}
+
+		bytecode, err := Compile(input)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		data, err := SerializeBytecode(bytecode)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		deserialized, err := DeserializeBytecode(data, input)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		state.Bytecode = deserialized
+		res, err := EvalVM(mod, state, BytecodeEvaluationConfig{
+			Tracer:             io.Discard,
+			CompilationContext: state.Ctx,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return res, bytecode, deserialized
+	}
+
+	t.Run("primitives", func(t *testing.T) {
+		res, bytecode, deserialized := roundTrip(t, `return [1, 1.5, "a", 1s, 2020y-5mt-3d-0h-UTC, true, 1..3]`)
+
+		assert.Equal(t, bytecode.main.Instructions, deserialized.main.Instructions)
+		assert.Equal(t, bytecode.main.SourceMap, deserialized.main.SourceMap)
+		assert.Equal(t, bytecode.constants, deserialized.constants)
+		assert.Equal(t, "[1, 1.5, \"a\", 1s, 2020y-5mt-3d-0h-0m-0s-0ms-0us-UTC, true, 1..3]", Stringify(res, nil))
+	})
+
+	t.Run("function", func(t *testing.T) {
+		res, _, _ := roundTrip(t, `
+			fn f(a){
+				return [a]
+			}
+			return f(1)
+		`)
+		assert.Equal(t, "[1]", Stringify(res, nil))
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		res, _, _ := roundTrip(t, `
+			pattern p = %str( 'a'..'z' )
+			return %p
+		`)
+		assert.True(t, res.(Pattern).Test(nil, String("a")))
+	})
+
+	t.Run("generic function", func(t *testing.T) {
+		res, _, _ := roundTrip(t, `
+			fn f<T>(a T) []T {
+				return [a]
+			}
+			return f(1)
+		`)
+		assert.Equal(t, "[1]", Stringify(res, nil))
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		mod := parseModule(t, "1")
+		state := NewGlobalState(NewDefaultTestContext())
+		defer state.Ctx.CancelGracefully()
+
+		input := CompilationInput{Mod: mod, Globals: state.Globals.permanent}
+		bytecode := utils.Must(Compile(input))
+		data := utils.Must(SerializeBytecode(bytecode))
+
+		_, err := DeserializeBytecode(data[:len(data)-1], input)
+		assert.ErrorIs(t, err, ErrInvalidSerializedBytecode)
+
+		_, err = DeserializeBytecode([]byte("abc"), input)
+		assert.ErrorIs(t, err, ErrInvalidSerializedBytecode)
+	})
+}
+
+func TestBytecodeCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewBytecodeCache(dir)
+
+	state := NewGlobalState(NewDefaultTestContext())
+	defer state.Ctx.CancelGracefully()
+
+	compile := func(code string) (*Bytecode, bool) {
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "core-test",
+			CodeString: code,
+		}))
+		bytecode, cached, err := cache.GetOrCompile(CompilationInput{
+			Mod:     &Module{MainChunk: chunk},
+			Globals: state.Globals.permanent,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return bytecode, cached
+	}
+
+	_, cached := compile("return 1")
+	assert.False(t, cached)
+
+	bytecode, cached := compile("return 1")
+	assert.True(t, cached)
+	assert.Equal(t, []Value{Int(1)}, bytecode.constants)
+
+	//a change in the source should invalidate the entry.
+	bytecode, cached = compile("return 2")
+	assert.False(t, cached)
+	assert.Equal(t, []Value{Int(2)}, bytecode.constants)
+
+	//invalid entries should be removed.
+	entries := utils.Must(os.ReadDir(dir))
+	for _, entry := range entries {
+		os.WriteFile(dir+"/"+entry.Name(), []byte("invalid"), 0o600)
+	}
+
+	_, cached = compile("return 2")
+	assert.False(t, cached)
+
+	_, cached = compile("return 2")
+	assert.True(t, cached)
+}
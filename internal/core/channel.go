@@ -0,0 +1,298 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inoxlang/inox/internal/core/symbolic"
+)
+
+const (
+	MAX_CHANNEL_CAPACITY = 100_000
+)
+
+var (
+	CHANNEL_PROPNAMES = []string{"send", "receive", "close", "is_closed"}
+
+	ErrClosedChannel               = errors.New("channel is closed")
+	ErrAllSelectedChannelsClosed   = errors.New("all selected channels are closed")
+	ErrInvalidChannelCapacity      = errors.New("invalid channel capacity")
+	ErrValueDoesNotMatchChannelElt = errors.New("value does not match the element pattern of the channel")
+	ErrNoChannelsToSelect          = errors.New("at least one channel should be provided")
+	ErrInvalidSelectArgument       = errors.New("select arguments should be channels, optionally followed by a timeout (duration)")
+
+	_ = []Iterable{(*Channel)(nil)}
+	_ = []Iterator{(*ChannelIterator)(nil)}
+)
+
+func init() {
+	RegisterSymbolicGoFunction(NewChannel, func(ctx *symbolic.Context, elementPattern symbolic.Pattern, capacity *symbolic.Int) *symbolic.Channel {
+		return symbolic.NewChannel(elementPattern)
+	})
+	RegisterSymbolicGoFunction(Select, symbolic.Select)
+}
+
+// A Channel is a bounded queue of values that can be shared between lthreads, the values sent to a channel should
+// match its element pattern. Sent values are shared or cloned using the same rules as the globals of lthreads.
+// Sending to and receiving from a channel block until the operation is possible or until the context is done.
+type Channel struct {
+	elementPattern Pattern
+	ch             chan Value
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+	closedSig chan struct{} //closed when the channel is closed
+}
+
+func NewChannel(ctx *Context, elementPattern Pattern, capacity Int) *Channel {
+	if capacity < 0 || capacity > MAX_CHANNEL_CAPACITY {
+		panic(ErrInvalidChannelCapacity)
+	}
+
+	return &Channel{
+		elementPattern: elementPattern,
+		ch:             make(chan Value, int(capacity)),
+		closedSig:      make(chan struct{}),
+	}
+}
+
+func (c *Channel) ElementPattern() Pattern {
+	return c.elementPattern
+}
+
+func (c *Channel) Capacity() int {
+	return cap(c.ch)
+}
+
+// Send sends a value to the channel, it blocks until there is space in the channel or until the context is done.
+func (c *Channel) Send(ctx *Context, v Value) error {
+	if !c.elementPattern.Test(ctx, v) {
+		return ErrValueDoesNotMatchChannelElt
+	}
+
+	if c.closed.Load() {
+		return ErrClosedChannel
+	}
+
+	v, err := ShareOrClone(v, ctx.GetClosestState())
+	if err != nil {
+		return err
+	}
+
+	select {
+	case c.ch <- v:
+		return nil
+	case <-c.closedSig:
+		return ErrClosedChannel
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive receives a value from the channel, it blocks until a value is available or until the context is done.
+// ErrClosedChannel is returned if the channel is closed and has no remaining values.
+func (c *Channel) Receive(ctx *Context) (Value, error) {
+	select {
+	case v := <-c.ch:
+		return v, nil
+	case <-c.closedSig:
+		return c.receiveRemaining()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// receiveRemaining returns one of the values that remain in the channel, it should only be called after the channel
+// has been closed.
+func (c *Channel) receiveRemaining() (Value, error) {
+	select {
+	case v := <-c.ch:
+		return v, nil
+	default:
+		return nil, ErrClosedChannel
+	}
+}
+
+// Close closes the channel, subsequent sends fail with ErrClosedChannel, the remaining values can still be received.
+func (c *Channel) Close() {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		close(c.closedSig)
+	})
+}
+
+func (c *Channel) IsClosed() bool {
+	return c.closed.Load()
+}
+
+func (c *Channel) PropertyNames(ctx *Context) []string {
+	return CHANNEL_PROPNAMES
+}
+
+func (c *Channel) GetGoMethod(name string) (*GoFunction, bool) {
+	switch name {
+	case "send":
+		return WrapGoMethod(c.Send), true
+	case "receive":
+		return WrapGoMethod(c.Receive), true
+	case "close":
+		return WrapGoClosure(func(ctx *Context) {
+			c.Close()
+		}), true
+	case "is_closed":
+		return WrapGoClosure(func(ctx *Context) Bool {
+			return Bool(c.IsClosed())
+		}), true
+	}
+	return nil, false
+}
+
+func (c *Channel) Prop(ctx *Context, name string) Value {
+	method, ok := c.GetGoMethod(name)
+	if !ok {
+		panic(FormatErrPropertyDoesNotExist(name, c))
+	}
+	return method
+}
+
+func (*Channel) SetProp(ctx *Context, name string, value Value) error {
+	return ErrCannotSetProp
+}
+
+func (c *Channel) IsSharable(originState *GlobalState) (bool, string) {
+	return true, ""
+}
+
+func (c *Channel) Share(originState *GlobalState) {
+	//ok
+}
+
+func (c *Channel) IsShared() bool {
+	return true
+}
+
+func (c *Channel) SmartLock(state *GlobalState) {
+	//
+}
+
+func (c *Channel) SmartUnlock(state *GlobalState) {
+}
+
+// Iterator returns an iterator that receives values from the channel until it is closed and empty.
+func (c *Channel) Iterator(ctx *Context, config IteratorConfiguration) Iterator {
+	return config.CreateIterator(&ChannelIterator{channel: c})
+}
+
+type ChannelIterator struct {
+	i          int
+	channel    *Channel
+	next       Value
+	hasPending bool
+	current    Value
+}
+
+func (it *ChannelIterator) HasNext(ctx *Context) bool {
+	if it.hasPending {
+		return true
+	}
+	v, err := it.channel.Receive(ctx)
+	if err != nil {
+		return false
+	}
+	it.next = v
+	it.hasPending = true
+	return true
+}
+
+func (it *ChannelIterator) Next(ctx *Context) bool {
+	if !it.HasNext(ctx) {
+		return false
+	}
+	it.current = it.next
+	it.next = nil
+	it.hasPending = false
+	it.i++
+	return true
+}
+
+func (it *ChannelIterator) Key(ctx *Context) Value {
+	return Int(it.i - 1)
+}
+
+func (it *ChannelIterator) Value(*Context) Value {
+	return it.current
+}
+
+// Select waits until a value can be received from one of the channels and returns the index of the channel
+// and the received value. If the last argument is a Duration the wait is limited by it: on timeout the
+// returned index is -1. Closed channels with no remaining values are ignored, ErrAllSelectedChannelsClosed
+// is returned if all channels are closed.
+func Select(ctx *Context, args ...Value) (Int, Value, error) {
+	var channels []*Channel
+	var timeout <-chan time.Time
+
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case *Channel:
+			channels = append(channels, a)
+		case Duration:
+			if i != len(args)-1 {
+				return -1, Nil, ErrInvalidSelectArgument
+			}
+			timer := time.NewTimer(time.Duration(a))
+			defer timer.Stop()
+			timeout = timer.C
+		default:
+			return -1, Nil, ErrInvalidSelectArgument
+		}
+	}
+
+	if len(channels) == 0 {
+		return -1, Nil, ErrNoChannelsToSelect
+	}
+
+	//cases: [receive from channels..., closing signals..., context done, timeout]
+	cases := make([]reflect.SelectCase, 2*len(channels)+2)
+	for i, channel := range channels {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.ch)}
+		cases[len(channels)+i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.closedSig)}
+	}
+	ctxDoneIndex := 2 * len(channels)
+	timeoutIndex := ctxDoneIndex + 1
+
+	cases[ctxDoneIndex] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	//a nil channel is never ready.
+	cases[timeoutIndex] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)}
+
+	remaining := len(channels)
+
+	for {
+		chosen, received, _ := reflect.Select(cases)
+
+		switch {
+		case chosen < len(channels):
+			return Int(chosen), received.Interface().(Value), nil
+		case chosen < ctxDoneIndex:
+			channelIndex := chosen - len(channels)
+			v, err := channels[channelIndex].receiveRemaining()
+			if err == nil {
+				return Int(channelIndex), v, nil
+			}
+
+			//ignore the channel from now on.
+			cases[channelIndex].Chan = reflect.Value{}
+			cases[chosen].Chan = reflect.Value{}
+			remaining--
+			if remaining == 0 {
+				return -1, Nil, ErrAllSelectedChannelsClosed
+			}
+		case chosen == ctxDoneIndex:
+			return -1, Nil, ctx.Err()
+		default:
+			return -1, Nil, nil
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannel(t *testing.T) {
+
+	t.Run("send & receive", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel := NewChannel(ctx, INT_PATTERN, 1)
+
+		if !assert.NoError(t, channel.Send(ctx, Int(1))) {
+			return
+		}
+
+		v, err := channel.Receive(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, Int(1), v)
+	})
+
+	t.Run("sending a value that does not match the element pattern should fail", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel := NewChannel(ctx, INT_PATTERN, 1)
+		assert.ErrorIs(t, channel.Send(ctx, String("a")), ErrValueDoesNotMatchChannelElt)
+	})
+
+	t.Run("mutable values should be shared or cloned", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel := NewChannel(ctx, ANYVAL_PATTERN, 1)
+		obj := NewObjectFromMapNoInit(ValMap{"a": Int(1)})
+
+		if !assert.NoError(t, channel.Send(ctx, obj)) {
+			return
+		}
+		assert.True(t, obj.IsShared())
+	})
+
+	t.Run("receiving from a closed channel should return the remaining values", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel := NewChannel(ctx, INT_PATTERN, 2)
+		channel.Send(ctx, Int(1))
+		channel.Close()
+
+		assert.ErrorIs(t, channel.Send(ctx, Int(2)), ErrClosedChannel)
+
+		v, err := channel.Receive(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, Int(1), v)
+
+		_, err = channel.Receive(ctx)
+		assert.ErrorIs(t, err, ErrClosedChannel)
+	})
+
+	t.Run("a blocked receive should stop when the context is cancelled", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		channel := NewChannel(ctx, INT_PATTERN, 0)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			ctx.CancelGracefully()
+		}()
+
+		_, err := channel.Receive(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("select", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel1 := NewChannel(ctx, INT_PATTERN, 1)
+		channel2 := NewChannel(ctx, INT_PATTERN, 1)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			channel2.Send(ctx, Int(2))
+		}()
+
+		index, v, err := Select(ctx, channel1, channel2)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, Int(1), index)
+		assert.Equal(t, Int(2), v)
+	})
+
+	t.Run("select: timeout", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel := NewChannel(ctx, INT_PATTERN, 1)
+
+		start := time.Now()
+		index, v, err := Select(ctx, channel, Duration(10*time.Millisecond))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, Int(-1), index)
+		assert.Equal(t, Nil, v)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+
+	t.Run("select: closed channels should be ignored", func(t *testing.T) {
+		ctx := NewContexWithEmptyState(ContextConfig{}, nil)
+		defer ctx.CancelGracefully()
+
+		channel1 := NewChannel(ctx, INT_PATTERN, 1)
+		channel2 := NewChannel(ctx, INT_PATTERN, 1)
+		channel1.Close()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			channel2.Send(ctx, Int(2))
+			channel2.Close()
+		}()
+
+		index, v, err := Select(ctx, channel1, channel2)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, Int(1), index)
+		assert.Equal(t, Int(2), v)
+
+		_, _, err = Select(ctx, channel1, channel2)
+		assert.ErrorIs(t, err, ErrAllSelectedChannelsClosed)
+	})
+}
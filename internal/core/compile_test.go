@@ -1888,6 +1888,8 @@ func _expectBytecode(t *testing.T, actualBytecode *Bytecode, localCount int, exp
 			constant.symbolicValue = nil
 			if constant.compiledFunction != nil {
 				constant.compiledFunction.SourceMap = nil
+				constant.compiledFunction.StatementStarts = nil
+				constant.compiledFunction.LocalNames = nil
 				constant.compiledFunction.Bytecode = nil
 			}
 		case *Bytecode:
@@ -24,6 +24,7 @@ type CompilationInput struct {
 	TraceWriter                              io.Writer
 	Context                                  *Context
 	IsTestingEnabled, IsImportTestingEnabled bool
+	IsBenchmarkingEnabled                    bool
 }
 
 // Compile compiles a module to bytecode.
@@ -31,6 +32,7 @@ func Compile(input CompilationInput) (*Bytecode, error) {
 	c := NewCompiler(input.Mod, input.Globals, input.SymbolicData, input.StaticCheckData, input.Context, input.TraceWriter)
 	c.isTestingEnabled = input.IsTestingEnabled
 	c.IsImportTestingEnabled = input.IsImportTestingEnabled
+	c.isBenchmarkingEnabled = input.IsBenchmarkingEnabled
 	return c.compileMainChunk(input.Mod.MainChunk)
 }
 
@@ -56,12 +58,14 @@ type compiler struct {
 	context *Context
 
 	isTestingEnabled, IsImportTestingEnabled bool
+	isBenchmarkingEnabled                    bool
 }
 
 // compilationScope contains the instructions for a scope.
 type compilationScope struct {
-	instructions []byte
-	sourceMap    map[int]instructionSourcePosition
+	instructions    []byte
+	sourceMap       map[int]instructionSourcePosition
+	statementStarts map[int]parse.Node
 }
 
 // loopCompilation is used by the compiler to store state about a loop being compiled, see LoopKind.
@@ -92,7 +96,8 @@ func NewCompiler(
 	trace io.Writer,
 ) *compiler {
 	mainScope := compilationScope{
-		sourceMap: make(map[int]instructionSourcePosition),
+		sourceMap:       make(map[int]instructionSourcePosition),
+		statementStarts: make(map[int]parse.Node),
 	}
 
 	symbTable := newSymbolTable()
@@ -815,7 +820,7 @@ func (c *compiler) Compile(node parse.Node) error {
 
 		if len(node.Statements) > 1 {
 			for _, stmt := range node.Statements {
-				if err := c.Compile(stmt); err != nil {
+				if err := c.compileStatement(stmt); err != nil {
 					return err
 				}
 				if stmt.Kind() == parse.Expr {
@@ -823,7 +828,7 @@ func (c *compiler) Compile(node parse.Node) error {
 				}
 			}
 		} else {
-			if err := c.Compile(node.Statements[0]); err != nil {
+			if err := c.compileStatement(node.Statements[0]); err != nil {
 				return err
 			}
 			if node.Statements[0].Kind() == parse.Expr {
@@ -1423,6 +1428,18 @@ func (c *compiler) Compile(node parse.Node) error {
 
 		c.emit(node, OpCreateUnionPattern, len(node.Cases))
 	case *parse.PatternIdentifierLiteral:
+		if node.TypeParameter != nil {
+			//type parameters are erased.
+			if node.TypeParameter.Constraint == nil {
+				c.emit(node, OpPushConstant, c.addConstant(SERIALIZABLE_PATTERN))
+				break
+			}
+			if err := c.Compile(node.TypeParameter.Constraint); err != nil {
+				return err
+			}
+			c.emit(node, OpToPattern)
+			break
+		}
 		c.emit(node, OpResolvePattern, c.addConstant(String(node.Name)))
 	case *parse.OptionalPatternExpression:
 		if err := c.Compile(node.Pattern); err != nil {
@@ -1444,6 +1461,9 @@ func (c *compiler) Compile(node parse.Node) error {
 		name := utils.MustGet(node.PatternName())
 
 		c.emit(node, OpToPattern)
+		if len(node.TypeParameters) > 0 {
+			c.emit(node, OpCreateGenericPattern, len(node.TypeParameters))
+		}
 		c.emit(node, OpAddPattern, c.addConstant(String(name)))
 	case *parse.PatternNamespaceIdentifierLiteral:
 		c.emit(node, OpResolvePatternNamespace, c.addConstant(String(node.Name)))
@@ -1475,7 +1495,8 @@ func (c *compiler) Compile(node parse.Node) error {
 	case *parse.FunctionExpression:
 		//enter local scope
 		scope := compilationScope{
-			sourceMap: make(map[int]instructionSourcePosition),
+			sourceMap:       make(map[int]instructionSourcePosition),
+			statementStarts: make(map[int]parse.Node),
 		}
 		c.scopes = append(c.scopes, scope)
 		c.localSymbolTableStack = append(c.localSymbolTableStack, newSymbolTable())
@@ -1516,9 +1537,11 @@ func (c *compiler) Compile(node parse.Node) error {
 
 		//leave local scope
 		localCount := c.currentLocalSymbols().SymbolCount()
+		localNames := c.currentLocalSymbols().NamesByIndex()
 		instructions := c.currentInstructions()
 
 		sourceMap := c.currentSourceMap()
+		statementStarts := c.scopes[c.scopeIndex].statementStarts
 		c.scopes = c.scopes[:len(c.scopes)-1]
 		c.localSymbolTableStack = c.localSymbolTableStack[:len(c.localSymbolTableStack)-1]
 		c.scopeIndex--
@@ -1527,12 +1550,14 @@ func (c *compiler) Compile(node parse.Node) error {
 		}
 
 		compiledFunction := &CompiledFunction{
-			Instructions:   instructions,
-			LocalCount:     localCount,
-			ParamCount:     len(node.Parameters),
-			IsVariadic:     node.IsVariadic,
-			SourceMap:      sourceMap,
-			SourceNodeSpan: node.Span,
+			Instructions:    instructions,
+			LocalCount:      localCount,
+			LocalNames:      localNames,
+			ParamCount:      len(node.Parameters),
+			IsVariadic:      node.IsVariadic,
+			SourceMap:       sourceMap,
+			StatementStarts: statementStarts,
+			SourceNodeSpan:  node.Span,
 		}
 
 		if len(c.chunkStack) > 1 {
@@ -1805,7 +1830,7 @@ func (c *compiler) Compile(node parse.Node) error {
 
 		//compile statements
 		for _, stmt := range chunk.Node.Statements {
-			if err := c.Compile(stmt); err != nil {
+			if err := c.compileStatement(stmt); err != nil {
 				return err
 			}
 			if stmt.Kind() == parse.Expr {
@@ -2124,6 +2149,11 @@ func (c *compiler) Compile(node parse.Node) error {
 			c.emit(node, OpNoOp)
 		} //else the test suite is on the top of the stack
 
+	case *parse.BenchmarkExpression:
+		if node.IsStatement && (!c.isTestingEnabled || !c.isBenchmarkingEnabled || (len(c.chunkStack) > 1 && !c.IsImportTestingEnabled)) {
+			break
+		}
+		return errors.New("benchmarks are not supported by the bytecode interpreter yet")
 	case *parse.TestCaseExpression:
 		if node.IsStatement && (!c.isTestingEnabled || (len(c.chunkStack) > 1 && !c.IsImportTestingEnabled)) {
 			break
@@ -2682,7 +2712,8 @@ func (c *compiler) compileMainChunk(chunk *parse.ParsedChunkSource) (*Bytecode,
 
 	//add local scope
 	scope := compilationScope{
-		sourceMap: make(map[int]instructionSourcePosition),
+		sourceMap:       make(map[int]instructionSourcePosition),
+		statementStarts: make(map[int]parse.Node),
 	}
 	c.scopes = append(c.scopes, scope)
 	c.scopeIndex++
@@ -2708,12 +2739,12 @@ func (c *compiler) compileMainChunk(chunk *parse.ParsedChunkSource) (*Bytecode,
 		case 0:
 			c.emit(node, OpPushNil)
 		case 1:
-			if err := c.Compile(node.Statements[0]); err != nil {
+			if err := c.compileStatement(node.Statements[0]); err != nil {
 				return nil, err
 			}
 		default:
 			for _, stmt := range node.Statements {
-				if err := c.Compile(stmt); err != nil {
+				if err := c.compileStatement(stmt); err != nil {
 					return nil, err
 				}
 				if stmt.Kind() == parse.Expr {
@@ -2726,7 +2757,9 @@ func (c *compiler) compileMainChunk(chunk *parse.ParsedChunkSource) (*Bytecode,
 	//leave local scope
 	instructions := c.currentInstructions()
 	srcMap := c.currentSourceMap()
+	statementStarts := c.scopes[c.scopeIndex].statementStarts
 	localCount := c.currentLocalSymbols().SymbolCount()
+	localNames := c.currentLocalSymbols().NamesByIndex()
 	c.scopes = c.scopes[:len(c.scopes)-1]
 	c.localSymbolTableStack = c.localSymbolTableStack[:len(c.localSymbolTableStack)-1]
 	c.scopeIndex--
@@ -2737,11 +2770,13 @@ func (c *compiler) compileMainChunk(chunk *parse.ParsedChunkSource) (*Bytecode,
 	//we create the bytecode and its main function
 
 	main := &CompiledFunction{
-		Instructions: append(instructions, OpSuspendVM),
-		SourceMap:    srcMap,
-		ParamCount:   0,
-		IsVariadic:   false,
-		LocalCount:   0,
+		Instructions:    append(instructions, OpSuspendVM),
+		SourceMap:       srcMap,
+		StatementStarts: statementStarts,
+		LocalNames:      localNames,
+		ParamCount:      0,
+		IsVariadic:      false,
+		LocalCount:      0,
 	}
 	main.LocalCount = localCount
 
@@ -2751,6 +2786,7 @@ func (c *compiler) compileMainChunk(chunk *parse.ParsedChunkSource) (*Bytecode,
 
 	b := &Bytecode{
 		module:    c.module,
+		mainChunk: chunk,
 		constants: c.constants,
 		main:      main,
 	}
@@ -2886,6 +2922,28 @@ func (c *compiler) emit(
 	return pos
 }
 
+// compileStatement compiles a statement of a chunk or a block and records the position of its first instruction,
+// the recorded positions are used by the VM to inform the debugger about the statement being executed.
+func (c *compiler) compileStatement(stmt parse.Node) error {
+	statementStarts := c.scopes[c.scopeIndex].statementStarts
+	start := len(c.currentInstructions())
+
+	_, alreadyRecorded := statementStarts[start]
+	if !alreadyRecorded {
+		statementStarts[start] = stmt
+	}
+
+	if err := c.Compile(stmt); err != nil {
+		return err
+	}
+
+	//statements without instructions are not recorded.
+	if !alreadyRecorded && len(c.currentInstructions()) == start {
+		delete(statementStarts, start)
+	}
+	return nil
+}
+
 func (c *compiler) currentChunk() *parse.ParsedChunkSource {
 	return c.chunkStack[len(c.chunkStack)-1]
 }
@@ -78,6 +78,7 @@ type DbOpenConfiguration struct {
 	ResolutionData Value
 	FullAccess     bool
 	Project        Project
+	Format         SerializationFormat //serialization format of the values if the database is new
 }
 
 func checkDatabaseSchema(pattern *ObjectPattern) error {
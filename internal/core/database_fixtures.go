@@ -0,0 +1,331 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/jsoniter"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	MAX_DB_FIXTURES_FILE_SIZE = 50_000_000
+)
+
+var (
+	ErrInvalidDatabaseFixtures = errors.New("invalid database fixtures")
+)
+
+// A FixtureCollectionPattern is implemented by the patterns of collections (e.g. Set patterns) whose initial elements
+// can be specified by database fixtures.
+type FixtureCollectionPattern interface {
+	Pattern
+
+	//FixtureElementPattern returns the pattern the elements specified by fixtures are checked against.
+	FixtureElementPattern() Pattern
+
+	//FixtureElementsHaveURLs should return true if the elements of the collection are identified by their URL.
+	FixtureElementsHaveURLs() bool
+}
+
+// DatabaseFixtures are the initial values of some of the top-level entities of a database. They are read from a JSON
+// file or from an Inox file containing a single object literal, each property of the object initializes a top-level
+// entity. The fixtures of a collection (e.g. a Set) are a list of elements.
+type DatabaseFixtures struct {
+	path     Path
+	schema   *ObjectPattern
+	entities map[string]Serializable //the fixtures of a collection are stored in a *List.
+}
+
+// ReadDatabaseFixtures reads & validates the fixtures located at path in the filesystem of ctx. The elements of collections
+// whose elements are identified by their URL are given a URL that only depends on the name of the collection and
+// on the position of the element: the URLs are stable across runs. Elements having an explicit URL (_url_ property
+// in JSON files) keep their URL.
+func ReadDatabaseFixtures(ctx *Context, path Path, schema *ObjectPattern, baseURL URL) (*DatabaseFixtures, error) {
+	if path.IsDirPath() {
+		return nil, fmt.Errorf("%w: %s is a directory path", ErrInvalidDatabaseFixtures, path)
+	}
+
+	fls := ctx.GetFileSystem()
+	stat, err := fls.Stat(path.UnderlyingString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the database fixtures %s: %w", path, err)
+	}
+	if stat.Size() > MAX_DB_FIXTURES_FILE_SIZE {
+		return nil, fmt.Errorf("%w: file %s is too large", ErrInvalidDatabaseFixtures, path)
+	}
+
+	content, err := util.ReadFile(fls, path.UnderlyingString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the database fixtures %s: %w", path, err)
+	}
+
+	fixtures := &DatabaseFixtures{
+		path:     path,
+		schema:   schema,
+		entities: map[string]Serializable{},
+	}
+
+	switch ext := filepath.Ext(path.UnderlyingString()); ext {
+	case symbolic.DB_FIXTURES_JSON_FILE_EXTENSION:
+		err = fixtures.parseJSON(ctx, content)
+	case symbolic.DB_FIXTURES_INOX_FILE_EXTENSION:
+		err = fixtures.evalObjectLiteral(ctx, content)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDatabaseFixtures, symbolic.INVALID_MAIN_DB_FIXTURES_FILE_EXTENSION)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s): %w", ErrInvalidDatabaseFixtures, path, err)
+	}
+
+	if err := fixtures.assignStableURLs(ctx, baseURL); err != nil {
+		return nil, fmt.Errorf("%w (%s): %w", ErrInvalidDatabaseFixtures, path, err)
+	}
+
+	return fixtures, nil
+}
+
+func (f *DatabaseFixtures) parseJSON(ctx *Context, content []byte) (finalErr error) {
+	it := jsoniter.ParseBytes(jsoniter.ConfigDefault, content)
+
+	if it.WhatIsNext() != jsoniter.ObjectValue {
+		return errors.New("the fixtures should be a JSON object")
+	}
+
+	it.ReadObjectCB(func(it *jsoniter.Iterator, name string) bool {
+		pattern, err := f.getEntityPattern(name)
+		if err != nil {
+			finalErr = err
+			return false
+		}
+
+		collectionPattern, isCollection := pattern.(FixtureCollectionPattern)
+		if !isCollection {
+			value, err := ParseNextJSONRepresentation(ctx, it, pattern, false)
+			if err != nil {
+				finalErr = fmt.Errorf("the fixture of %s does not match the schema: %w", name, err)
+				return false
+			}
+			f.entities[name] = value
+			return true
+		}
+
+		if it.WhatIsNext() != jsoniter.ArrayValue {
+			finalErr = fmt.Errorf("the fixtures of %s should be an array", name)
+			return false
+		}
+
+		elementPattern := collectionPattern.FixtureElementPattern()
+		var elements []Serializable
+
+		it.ReadArrayCB(func(it *jsoniter.Iterator) bool {
+			element, err := ParseNextJSONRepresentation(ctx, it, elementPattern, false)
+			if err != nil {
+				finalErr = fmt.Errorf("element %d of %s does not match the schema: %w", len(elements), name, err)
+				return false
+			}
+			elements = append(elements, element)
+			return true
+		})
+
+		if finalErr != nil {
+			return false
+		}
+
+		f.entities[name] = NewWrappedValueListFrom(elements)
+		return true
+	})
+
+	if finalErr == nil && it.Error != nil {
+		finalErr = it.Error
+	}
+	return
+}
+
+func (f *DatabaseFixtures) evalObjectLiteral(ctx *Context, content []byte) error {
+	chunk, err := parse.ParseChunk(string(content), f.path.UnderlyingString())
+	if err != nil {
+		return err
+	}
+
+	if chunk.Manifest != nil || len(chunk.Statements) != 1 {
+		return errors.New("the file should only contain an object literal")
+	}
+
+	objectLiteral, ok := chunk.Statements[0].(*parse.ObjectLiteral)
+	if !ok {
+		return errors.New("the file should only contain an object literal")
+	}
+
+	//the object literal is evaluated in a state without any global variable, so functions cannot be called.
+	evalCtx := ctx.BoundChild()
+	defer evalCtx.CancelGracefully()
+
+	result, err := TreeWalkEval(objectLiteral, NewTreeWalkState(evalCtx))
+	if err != nil {
+		return err
+	}
+
+	return result.(*Object).ForEachEntry(func(name string, value Serializable) error {
+		pattern, err := f.getEntityPattern(name)
+		if err != nil {
+			return err
+		}
+
+		collectionPattern, isCollection := pattern.(FixtureCollectionPattern)
+		if !isCollection {
+			if !pattern.Test(ctx, value) {
+				return fmt.Errorf("the fixture of %s does not match the schema", name)
+			}
+			f.entities[name] = value
+			return nil
+		}
+
+		list, ok := value.(*List)
+		if !ok {
+			return fmt.Errorf("the fixtures of %s should be a list", name)
+		}
+
+		elementPattern := collectionPattern.FixtureElementPattern()
+		elements := list.GetOrBuildElements(ctx)
+
+		for i, element := range elements {
+			if !elementPattern.Test(ctx, element) {
+				return fmt.Errorf("element %d of %s does not match the schema", i, name)
+			}
+		}
+
+		f.entities[name] = list
+		return nil
+	})
+}
+
+func (f *DatabaseFixtures) getEntityPattern(name string) (Pattern, error) {
+	pattern, _, ok := f.schema.Entry(name)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a top-level entity of the schema", name)
+	}
+	return pattern, nil
+}
+
+func (f *DatabaseFixtures) assignStableURLs(ctx *Context, baseURL URL) error {
+	for _, name := range f.EntityNames() {
+		pattern, _, _ := f.schema.Entry(name)
+		collectionPattern, ok := pattern.(FixtureCollectionPattern)
+		if !ok || !collectionPattern.FixtureElementsHaveURLs() {
+			continue
+		}
+
+		for i, element := range f.entities[name].(*List).GetOrBuildElements(ctx) {
+			holder, ok := element.(UrlHolder)
+			if !ok {
+				return fmt.Errorf("element %d of %s cannot have a URL", i, name)
+			}
+			if _, ok := holder.URL(); ok {
+				continue
+			}
+
+			url := baseURL.ToDirURL().AppendAbsolutePath(Path("/" + name + "/" + MakeStableFixtureULID(name, i).String()))
+			if err := holder.SetURLOnce(ctx, url); err != nil {
+				return fmt.Errorf("failed to set the URL of element %d of %s: %w", i, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MakeStableFixtureULID returns a ULID that only depends on the name of a collection and on the index of an element
+// in the fixtures of the collection, its timestamp is zero.
+func MakeStableFixtureULID(collectionName string, index int) ULID {
+	hash := sha256.Sum256([]byte(collectionName + "/" + strconv.Itoa(index)))
+
+	var id ulid.ULID
+	if err := id.SetEntropy(hash[:10]); err != nil {
+		panic(err)
+	}
+	return ULID(id)
+}
+
+// EntityNames returns the sorted names of the top-level entities initialized by the fixtures.
+func (f *DatabaseFixtures) EntityNames() []string {
+	names := maps.Keys(f.entities)
+	slices.Sort(names)
+	return names
+}
+
+// AddToMigrations returns a copy of a migration object (see DatabaseIL.UpdateSchema) whose inclusion handlers initialize
+// the top-level entities with the fixtures. The fixtures of a top-level entity override its inclusion handler. migrations
+// can be nil. The top-level entities initialized by the fixtures should not be present in the current schema of the database.
+func (f *DatabaseFixtures) AddToMigrations(ctx *Context, migrations *Object) *Object {
+	entries := ValMap{}
+	var inclusionKeys, inclusionValues []Serializable
+
+	if migrations != nil {
+		migrations.ForEachEntry(func(k string, v Serializable) error {
+			if k != symbolic.DB_MIGRATION__INCLUSIONS_PROP_NAME {
+				entries[k] = v
+				return nil
+			}
+
+			return v.(*Dictionary).ForEachEntry(ctx, func(keyRepr string, key, v Serializable) error {
+				if _, ok := f.entities[string(key.(PathPattern))[1:]]; !ok {
+					inclusionKeys = append(inclusionKeys, key)
+					inclusionValues = append(inclusionValues, v)
+				}
+				return nil
+			})
+		})
+	}
+
+	for _, name := range f.EntityNames() {
+		inclusionKeys = append(inclusionKeys, PathPattern("/"+name))
+		inclusionValues = append(inclusionValues, f.entities[name])
+	}
+
+	entries[symbolic.DB_MIGRATION__INCLUSIONS_PROP_NAME] = NewDictionaryFromKeyValueLists(inclusionKeys, inclusionValues, ctx)
+	return NewObjectFromMap(entries, ctx)
+}
+
+// Seed writes the serialized fixtures to a data store (e.g. the storage of a database), overwriting the current
+// value of the top-level entities initialized by the fixtures. Seed does not check that the schema of the database
+// is equal to the schema of the fixtures.
+func (f *DatabaseFixtures) Seed(ctx *Context, store DataStore) error {
+	format := GetDataStoreSerializationFormat(store)
+
+	for _, name := range f.EntityNames() {
+		pattern, _, _ := f.schema.Entry(name)
+		value := f.entities[name]
+
+		var (
+			serialized string
+			err        error
+		)
+
+		if collectionPattern, ok := pattern.(FixtureCollectionPattern); ok {
+			elements := value.(*List).GetOrBuildElements(ctx)
+			elementPatterns := []Pattern{collectionPattern.FixtureElementPattern()}
+
+			serialized, err = GetStorageSequenceRepresentation(ctx, elements, elementPatterns, format)
+			if err != nil {
+				return fmt.Errorf("failed to serialize the elements of %s: %w", name, err)
+			}
+		} else {
+			serialized, err = GetStorageRepresentation(ctx, value, pattern, format)
+			if err != nil {
+				return fmt.Errorf("failed to serialize the fixture of %s: %w", name, err)
+			}
+		}
+
+		store.SetSerialized(ctx, Path("/"+name), serialized)
+	}
+	return nil
+}
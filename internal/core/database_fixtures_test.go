@@ -0,0 +1,131 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDatabaseFixtures(t *testing.T) {
+	const BASE_URL = URL("ldb://main/")
+
+	schema := NewInexactObjectPattern([]ObjectPatternEntry{
+		{
+			Name: "config",
+			Pattern: NewInexactObjectPattern([]ObjectPatternEntry{
+				{Name: "name", Pattern: STR_PATTERN},
+				{Name: "max-users", Pattern: INT_PATTERN},
+			}),
+		},
+	})
+
+	setup := func(t *testing.T, path, content string) *Context {
+		fls := newMemFilesystem()
+		util.WriteFile(fls, path, []byte(content), 0600)
+
+		ctx := NewContexWithEmptyState(ContextConfig{Filesystem: fls}, nil)
+		t.Cleanup(func() { ctx.CancelGracefully() })
+		return ctx
+	}
+
+	t.Run("JSON file", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.json", `{"config": {"name": "app", "max-users": 10}}`)
+
+		fixtures, err := ReadDatabaseFixtures(ctx, "/fixtures.json", schema, BASE_URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, []string{"config"}, fixtures.EntityNames())
+
+		config := fixtures.entities["config"].(*Object)
+		assert.Equal(t, String("app"), config.Prop(ctx, "name"))
+		assert.Equal(t, Int(10), config.Prop(ctx, "max-users"))
+	})
+
+	t.Run("Inox file", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.ix", "# comment\n{\n  config: {name: \"app\", max-users: 10}\n}\n")
+
+		fixtures, err := ReadDatabaseFixtures(ctx, "/fixtures.ix", schema, BASE_URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		config := fixtures.entities["config"].(*Object)
+		assert.Equal(t, String("app"), config.Prop(ctx, "name"))
+		assert.Equal(t, Int(10), config.Prop(ctx, "max-users"))
+	})
+
+	t.Run("Inox file containing a call", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.ix", `{config: f()}`)
+
+		_, err := ReadDatabaseFixtures(ctx, "/fixtures.ix", schema, BASE_URL)
+		assert.ErrorIs(t, err, ErrInvalidDatabaseFixtures)
+	})
+
+	t.Run("unknown top-level entity", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.json", `{"users": []}`)
+
+		_, err := ReadDatabaseFixtures(ctx, "/fixtures.json", schema, BASE_URL)
+		assert.ErrorIs(t, err, ErrInvalidDatabaseFixtures)
+		assert.ErrorContains(t, err, "users is not a top-level entity of the schema")
+	})
+
+	t.Run("fixture not matching the schema", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.ix", `{config: {name: 1, max-users: 10}}`)
+
+		_, err := ReadDatabaseFixtures(ctx, "/fixtures.ix", schema, BASE_URL)
+		assert.ErrorIs(t, err, ErrInvalidDatabaseFixtures)
+		assert.ErrorContains(t, err, "the fixture of config does not match the schema")
+	})
+
+	t.Run("unsupported file extension", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.yaml", ``)
+
+		_, err := ReadDatabaseFixtures(ctx, "/fixtures.yaml", schema, BASE_URL)
+		assert.ErrorIs(t, err, ErrInvalidDatabaseFixtures)
+	})
+
+	t.Run("AddToMigrations", func(t *testing.T) {
+		ctx := setup(t, "/fixtures.json", `{"config": {"name": "app", "max-users": 10}}`)
+
+		fixtures, err := ReadDatabaseFixtures(ctx, "/fixtures.json", schema, BASE_URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		migrations := NewObjectFromMap(ValMap{
+			symbolic.DB_MIGRATION__INCLUSIONS_PROP_NAME: NewDictionaryFromKeyValueLists(
+				[]Serializable{PathPattern("/config"), PathPattern("/other")},
+				[]Serializable{Nil, Int(1)},
+				ctx,
+			),
+		}, ctx)
+
+		result := fixtures.AddToMigrations(ctx, migrations)
+		inclusions := result.Prop(ctx, symbolic.DB_MIGRATION__INCLUSIONS_PROP_NAME).(*Dictionary)
+
+		other, ok := inclusions.Value(ctx, PathPattern("/other"))
+		if assert.True(t, bool(ok)) {
+			assert.Equal(t, Int(1), other)
+		}
+
+		config, ok := inclusions.Value(ctx, PathPattern("/config"))
+		if assert.True(t, bool(ok)) {
+			assert.Same(t, fixtures.entities["config"], config)
+		}
+	})
+}
+
+func TestMakeStableFixtureULID(t *testing.T) {
+	id := MakeStableFixtureULID("users", 0)
+
+	assert.Equal(t, id, MakeStableFixtureULID("users", 0))
+	assert.NotEqual(t, id, MakeStableFixtureULID("users", 1))
+	assert.NotEqual(t, id, MakeStableFixtureULID("clients", 0))
+
+	_, err := ParseULID(id.String())
+	assert.NoError(t, err)
+}
@@ -1,11 +1,16 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -26,7 +31,13 @@ const (
 )
 
 var (
-	ErrDebuggerAlreadyAttached = errors.New("debugger already attached")
+	ErrDebuggerAlreadyAttached  = errors.New("debugger already attached")
+	ErrProgramNotStopped        = errors.New("program is not stopped")
+	ErrUnknownStackFrame        = errors.New("unknown stack frame")
+	ErrPossibleSideEffects      = errors.New("the expression may have side effects")
+	ErrNotSettableVariable      = errors.New("only variables with a simple value can be set")
+	ErrUnknownVariable          = errors.New("unknown variable")
+	ErrNewVariableValueMismatch = errors.New("the new value should have the same type as the current value")
 )
 
 // A Debugger enables the debugging of a running Inox program by handling debug commands
@@ -66,6 +77,17 @@ type evaluationState interface {
 
 	CurrentLocalScope() map[string]Value
 
+	//EvalInCurrentScope evaluates an expression in the current scope, the debugger is not informed
+	//about the statements executed during the evaluation.
+	EvalInCurrentScope(expr parse.Node) (Value, error)
+
+	//EvalInFrame evaluates an expression in the local scope of a frame of the current stack trace,
+	//depth is the position of the frame in the reversed trace (0 for the current frame).
+	EvalInFrame(expr parse.Node, depth int) (Value, error)
+
+	//SetCurrentLocalVariable sets the value of an existing variable in the current scope.
+	SetCurrentLocalVariable(name string, value Value)
+
 	GetGlobalState() *GlobalState
 }
 
@@ -74,6 +96,7 @@ type sharedDebuggerFields struct {
 	nextBreakpointId       int32
 	breakpointsLock        sync.Mutex
 	breakpoints            map[parse.NodeSpan]BreakpointInfo
+	breakpointHitCounts    map[int32]int64
 	exceptionBreakpointsId atomic.Int32
 
 	stackFrameId       atomic.Int32 //incremented by debuggees
@@ -163,11 +186,12 @@ func NewDebugger(args DebuggerArgs) *Debugger {
 		}
 
 		debugger.shared = &sharedDebuggerFields{
-			nextBreakpointId:   nextBreakpointId,
-			breakpoints:        initialBreakpoints,
-			stoppedEventChan:   make(chan ProgramStoppedEvent, runtime.NumCPU()),
-			debuggers:          make(map[StateId]*Debugger),
-			threadIdToFrameIds: make(map[StateId]*[]int32, 0),
+			nextBreakpointId:    nextBreakpointId,
+			breakpoints:         initialBreakpoints,
+			breakpointHitCounts: make(map[int32]int64),
+			stoppedEventChan:    make(chan ProgramStoppedEvent, runtime.NumCPU()),
+			debuggers:           make(map[StateId]*Debugger),
+			threadIdToFrameIds:  make(map[StateId]*[]int32, 0),
 		}
 
 		if args.ExceptionBreakpointId >= INITIAL_BREAKPOINT_ID {
@@ -379,6 +403,16 @@ func (d *Debugger) loop(done *func(), cancelExecution *bool) {
 						d.sendCommandToTargetDebugger(c, c.ThreadId)
 						continue
 					}
+				case DebugCommandEvaluate:
+					if c.ThreadId != d.threadId() {
+						d.sendCommandToTargetDebugger(c, c.ThreadId)
+						continue
+					}
+				case DebugCommandSetVariable:
+					if c.ThreadId != d.threadId() {
+						d.sendCommandToTargetDebugger(c, c.ThreadId)
+						continue
+					}
 				case DebugCommandNextStep:
 					if c.ResumeAllThreads {
 						d.broadcastCommand(c)
@@ -434,7 +468,12 @@ func (d *Debugger) loop(done *func(), cancelExecution *bool) {
 						}
 					}
 
-					breakpointsFromLines, err := GetBreakpointsFromLines(c.BreakPointsByLine, chunk, &d.shared.nextBreakpointId)
+					options := c.BreakpointOptionsByLine
+					if len(options) != len(c.BreakPointsByLine) {
+						options = nil
+					}
+
+					breakpointsFromLines, err := GetBreakpointsFromLinesWithOptions(c.BreakPointsByLine, options, chunk, &d.shared.nextBreakpointId)
 
 					if err == nil {
 						for _, breakpoint := range breakpointsFromLines {
@@ -447,6 +486,16 @@ func (d *Debugger) loop(done *func(), cancelExecution *bool) {
 						d.logger.Err(err).Msg("failed to get breakpoints from lines")
 					}
 
+					//the breakpoints of the other sources are kept, only the hit counts of the replaced
+					//breakpoints are reset.
+					for span, breakpoint := range d.shared.breakpoints {
+						if breakpoint.Chunk == nil || breakpoint.Chunk.Name() == chunk.Name() {
+							delete(d.shared.breakpointHitCounts, breakpoint.Id)
+						} else if _, ok := breakpoints[span]; !ok {
+							breakpoints[span] = breakpoint
+						}
+					}
+
 					d.shared.breakpoints = breakpoints
 				}()
 
@@ -512,6 +561,18 @@ func (d *Debugger) loop(done *func(), cancelExecution *bool) {
 				if d.stoppedProgram.Load() {
 					d.stoppedProgramCommandChan <- c
 				}
+			case DebugCommandEvaluate:
+				if d.stoppedProgram.Load() {
+					d.stoppedProgramCommandChan <- c
+				} else {
+					c.Get(nil, ErrProgramNotStopped)
+				}
+			case DebugCommandSetVariable:
+				if d.stoppedProgram.Load() {
+					d.stoppedProgramCommandChan <- c
+				} else {
+					c.Get(nil, ErrProgramNotStopped)
+				}
 			case DebugCommandInformAboutSecondaryEvent:
 				if !d.isRoot() {
 					d.sendCommandToRootDebugger(c)
@@ -562,6 +623,10 @@ func (d *Debugger) beforeInstruction(n parse.Node, trace []StackFrameInfo, excep
 		breakpointInfo, hasBreakpoint = d.shared.breakpoints[n.Base().Span]
 		d.shared.breakpointsLock.Unlock()
 
+		if hasBreakpoint && !d.shouldStopAtBreakpoint(breakpointInfo) {
+			hasBreakpoint = false
+		}
+
 		if hasBreakpoint {
 			stopReason = BreakpointStop
 		} else {
@@ -643,6 +708,10 @@ func (d *Debugger) beforeInstruction(n parse.Node, trace []StackFrameInfo, excep
 					c.Get(globals, locals)
 				case DebugCommandGetStackTrace:
 					c.Get(trace)
+				case DebugCommandEvaluate:
+					c.Get(d.evaluate(c, trace))
+				case DebugCommandSetVariable:
+					c.Get(d.setVariable(c))
 				}
 			}
 		}
@@ -650,7 +719,181 @@ func (d *Debugger) beforeInstruction(n parse.Node, trace []StackFrameInfo, excep
 
 }
 
+// shouldStopAtBreakpoint evaluates the condition of the breakpoint, updates its hit count and checks its hit condition.
+// If the breakpoint is a logpoint the message is sent as a secondary event and false is returned.
+func (d *Debugger) shouldStopAtBreakpoint(breakpoint BreakpointInfo) bool {
+	if breakpoint.Condition != nil {
+		result, err := d.evaluationState.EvalInCurrentScope(breakpoint.Condition)
+		if err != nil {
+			//we stop in order for the user to notice the error.
+			d.logger.Err(err).Msgf("failed to evaluate the condition of the breakpoint %d", breakpoint.Id)
+			return true
+		}
+
+		isTrue, ok := result.(Bool)
+		if !ok {
+			d.logger.Error().Msgf("the condition of the breakpoint %d should evaluate to a boolean", breakpoint.Id)
+			return true
+		}
+		if !isTrue {
+			return false
+		}
+	}
+
+	d.shared.breakpointsLock.Lock()
+	d.shared.breakpointHitCounts[breakpoint.Id]++
+	hits := d.shared.breakpointHitCounts[breakpoint.Id]
+	d.shared.breakpointsLock.Unlock()
+
+	if !breakpoint.HitCondition.isMet(hits) {
+		return false
+	}
+
+	if breakpoint.IsLogpoint() {
+		d.sendLogpointMessage(breakpoint)
+		return false
+	}
+
+	return true
+}
+
+// evaluate evaluates the expression of a DebugCommandEvaluate, it should only be called by the evaluation's goroutine
+// while the program is stopped.
+func (d *Debugger) evaluate(cmd DebugCommandEvaluate, trace []StackFrameInfo) (Value, error) {
+	//the trace is reversed by beforeInstruction: the first frame is the current (innermost) one.
+	depth := 0
+	if cmd.FrameId != 0 {
+		depth = slices.IndexFunc(trace, func(frame StackFrameInfo) bool {
+			return frame.Id == cmd.FrameId
+		})
+		if depth < 0 {
+			return nil, ErrUnknownStackFrame
+		}
+	}
+
+	if cmd.DisallowSideEffects && !isSideEffectFreeExpression(cmd.Expr) {
+		return nil, ErrPossibleSideEffects
+	}
+
+	return d.evaluationState.EvalInFrame(cmd.Expr, depth)
+}
+
+// setVariable executes a DebugCommandSetVariable, it should only be called by the evaluation's goroutine
+// while the program is stopped.
+func (d *Debugger) setVariable(cmd DebugCommandSetVariable) (_ Value, finalErr error) {
+	defer func() {
+		if e := recover(); e != nil {
+			finalErr = utils.ConvertPanicValueToError(e)
+		}
+	}()
+
+	var currentValue Value
+
+	if cmd.Global {
+		if _, isConstant := d.globalState.Globals.Constants()[cmd.Name]; isConstant {
+			return nil, fmt.Errorf("cannot change the value of the global constant %s", cmd.Name)
+		}
+		currentValue = d.globalState.Globals.Get(cmd.Name)
+	} else {
+		currentValue = d.evaluationState.CurrentLocalScope()[cmd.Name]
+	}
+
+	if currentValue == nil {
+		return nil, ErrUnknownVariable
+	}
+
+	if !IsSimpleInoxVal(currentValue) {
+		return nil, ErrNotSettableVariable
+	}
+
+	if !isSideEffectFreeExpression(cmd.Value) {
+		return nil, ErrPossibleSideEffects
+	}
+
+	newValue, err := d.evaluationState.EvalInCurrentScope(cmd.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeOf(newValue) != reflect.TypeOf(currentValue) {
+		return nil, ErrNewVariableValueMismatch
+	}
+
+	if cmd.Global {
+		d.globalState.Globals.Set(cmd.Name, newValue)
+	} else {
+		d.evaluationState.SetCurrentLocalVariable(cmd.Name, newValue)
+	}
+
+	return newValue, nil
+}
+
+// isSideEffectFreeExpression returns true if the evaluation of $expr cannot have side effects,
+// it only accepts a small set of node types (literals, variables, member expressions, ...).
+func isSideEffectFreeExpression(expr parse.Node) bool {
+	ok := true
+
+	parse.Walk(expr, func(node, parent, scopeNode parse.Node, ancestors []parse.Node, after bool) (parse.TraversalAction, error) {
+		switch node.(type) {
+		case parse.SimpleValueLiteral,
+			*parse.Variable, *parse.GlobalVariable, *parse.IdentifierLiteral,
+			*parse.MemberExpression, *parse.IdentifierMemberExpression, *parse.ComputedMemberExpression,
+			*parse.IndexExpression, *parse.SliceExpression,
+			*parse.UnaryExpression, *parse.BinaryExpression, *parse.BooleanConversionExpression,
+			*parse.ObjectLiteral, *parse.ObjectProperty, *parse.RecordLiteral, *parse.ListLiteral,
+			*parse.TupleLiteral, *parse.ElementSpreadElement, *parse.PatternIdentifierLiteral:
+			return parse.ContinueTraversal, nil
+		}
+		ok = false
+		return parse.StopTraversal, nil
+	}, nil)
+
+	return ok
+}
+
+func (d *Debugger) sendLogpointMessage(breakpoint BreakpointInfo) {
+	buf := bytes.NewBuffer(nil)
+
+	for _, part := range breakpoint.LogMessage {
+		if part.Expr == nil {
+			buf.WriteString(part.Text)
+			continue
+		}
+
+		value, err := d.evaluationState.EvalInCurrentScope(part.Expr)
+		if err != nil {
+			buf.WriteString("<error: " + err.Error() + ">")
+			continue
+		}
+
+		if str, ok := value.(StringLike); ok {
+			buf.WriteString(str.GetOrBuildString())
+		} else {
+			buf.WriteString(Stringify(value, d.globalState.Ctx))
+		}
+	}
+
+	event := DebugCommandInformAboutSecondaryEvent{
+		Event: LogpointHitEvent{
+			StateId:      d.threadId(),
+			BreakpointId: breakpoint.Id,
+			Message:      buf.String(),
+		},
+	}
+
+	select {
+	case d.controlChan <- event:
+	case <-d.globalState.Ctx.Done():
+	}
+}
+
 func ParseFileChunk(absoluteSourcePath string, fls afs.Filesystem) (*parse.ParsedChunkSource, error) {
+	return ParseFileChunkIncrementally(absoluteSourcePath, fls, nil)
+}
+
+// ParseFileChunkIncrementally parses a file, if prev is not nil and has the same name as the file the top level
+// statements of prev that are not affected by the changes are reused.
+func ParseFileChunkIncrementally(absoluteSourcePath string, fls afs.Filesystem, prev *parse.ParsedChunkSource) (*parse.ParsedChunkSource, error) {
 	content, err := ReadFileInFS(fls, absoluteSourcePath, -1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", absoluteSourcePath, err)
@@ -664,7 +907,14 @@ func ParseFileChunk(absoluteSourcePath string, fls afs.Filesystem) (*parse.Parse
 		CodeString:    string(content),
 	}
 
-	chunk, parsingErr := parse.ParseChunkSource(src)
+	var chunk *parse.ParsedChunkSource
+	var parsingErr error
+
+	if prev != nil && prev.Name() == src.Name() {
+		chunk, parsingErr = parse.ParseChunkSourceIncrementally(prev, src, parse.GetSourceEdit(prev.Runes(), []rune(src.Code())))
+	} else {
+		chunk, parsingErr = parse.ParseChunkSource(src)
+	}
 
 	if parsingErr != nil {
 		return chunk, fmt.Errorf("failed to parse %s: %w", absoluteSourcePath, parsingErr)
@@ -673,9 +923,20 @@ func ParseFileChunk(absoluteSourcePath string, fls afs.Filesystem) (*parse.Parse
 }
 
 func GetBreakpointsFromLines(lines []int, chunk *parse.ParsedChunkSource, nextBreakpointId *int32) ([]BreakpointInfo, error) {
+	return GetBreakpointsFromLinesWithOptions(lines, nil, chunk, nextBreakpointId)
+}
+
+// GetBreakpointsFromLinesWithOptions is like GetBreakpointsFromLines but also parses the options of each breakpoint,
+// options should be nil or have the same length as lines. A breakpoint whose options are invalid is not verified
+// and its Message field contains the reason.
+func GetBreakpointsFromLinesWithOptions(lines []int, options []BreakpointOptions, chunk *parse.ParsedChunkSource, nextBreakpointId *int32) ([]BreakpointInfo, error) {
 	var breakpointsSetByLine []BreakpointInfo
 
-	for _, line := range lines {
+	if options != nil && len(options) != len(lines) {
+		return nil, errors.New("the number of breakpoint options should be equal to the number of lines")
+	}
+
+	for i, line := range lines {
 		stmt, _, _ := chunk.FindFirstStatementAndChainOnLine(line)
 
 		id := *nextBreakpointId
@@ -693,7 +954,123 @@ func GetBreakpointsFromLines(lines []int, chunk *parse.ParsedChunkSource, nextBr
 			breakpointInfo.StartColumn = col
 		}
 
+		if options != nil {
+			if err := breakpointInfo.setOptions(options[i]); err != nil {
+				breakpointInfo.NodeSpan = parse.NodeSpan{}
+				breakpointInfo.Message = err.Error()
+			}
+		}
+
 		breakpointsSetByLine = append(breakpointsSetByLine, breakpointInfo)
 	}
 	return breakpointsSetByLine, nil
 }
+
+func (i *BreakpointInfo) setOptions(options BreakpointOptions) error {
+	if condition := strings.TrimSpace(options.Condition); condition != "" {
+		expr, ok := ParseDebugExpression(condition)
+		if !ok {
+			return fmt.Errorf("invalid condition: %q", options.Condition)
+		}
+		i.Condition = expr
+	}
+
+	if options.HitCondition != "" {
+		hitCondition, err := ParseBreakpointHitCondition(options.HitCondition)
+		if err != nil {
+			return err
+		}
+		i.HitCondition = hitCondition
+	}
+
+	if options.LogMessage != "" {
+		parts, err := ParseLogMessage(options.LogMessage)
+		if err != nil {
+			return err
+		}
+		i.LogMessage = parts
+	}
+
+	return nil
+}
+
+// ParseBreakpointHitCondition parses a hit condition such as '3', '>= 3' or '%2',
+// if there is no operator the breakpoint stops once the number of hits is greater or equal to the count.
+func ParseBreakpointHitCondition(hitCondition string) (BreakpointHitCondition, error) {
+	s := strings.TrimSpace(hitCondition)
+	operator := ">="
+
+	for _, op := range []string{"==", ">=", "<=", ">", "<", "%"} {
+		if strings.HasPrefix(s, op) {
+			operator = op
+			s = strings.TrimSpace(s[len(op):])
+			break
+		}
+	}
+
+	count, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || count < 0 || (operator == "%" && count == 0) {
+		return BreakpointHitCondition{}, fmt.Errorf("invalid hit condition: %q", hitCondition)
+	}
+
+	return BreakpointHitCondition{Operator: operator, Count: count}, nil
+}
+
+// ParseLogMessage parses the message of a logpoint, expressions inside braces are interpolated.
+func ParseLogMessage(msg string) ([]LogMessagePart, error) {
+	parts := []LogMessagePart{}
+	runes := []rune(msg)
+	textStart := 0
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '{' {
+			continue
+		}
+
+		if i > textStart {
+			parts = append(parts, LogMessagePart{Text: string(runes[textStart:i])})
+		}
+
+		//find the matching closing brace, the expression can contain braces (e.g. object literals).
+		depth := 1
+		end := i + 1
+		for ; end < len(runes) && depth > 0; end++ {
+			switch runes[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+
+		if depth != 0 {
+			return nil, fmt.Errorf("invalid log message: unterminated interpolation")
+		}
+
+		exprString := strings.TrimSpace(string(runes[i+1 : end-1]))
+		expr, ok := ParseDebugExpression(exprString)
+		if !ok {
+			return nil, fmt.Errorf("invalid log message: invalid expression: %q", exprString)
+		}
+
+		parts = append(parts, LogMessagePart{Expr: expr})
+		i = end - 1
+		textStart = end
+	}
+
+	if textStart < len(runes) {
+		parts = append(parts, LogMessagePart{Text: string(runes[textStart:])})
+	}
+
+	return parts, nil
+}
+
+// ParseDebugExpression parses an expression written by the user, binary expressions
+// are allowed to not be parenthesized (e.g. 'a > 1').
+func ParseDebugExpression(s string) (parse.Node, bool) {
+	expr, ok := parse.ParseExpression(s)
+	if ok {
+		return expr, true
+	}
+	return parse.ParseExpression("(" + s + ")")
+}
@@ -2,11 +2,13 @@ package core
 
 import (
 	"io"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/inoxlang/inox/internal/core/symbolic"
 	"github.com/inoxlang/inox/internal/parse"
 	"github.com/inoxlang/inox/internal/utils"
 	"github.com/rs/zerolog"
@@ -53,6 +55,103 @@ func TestTreeWalkDebug(t *testing.T) {
 	})
 }
 
+func TestBytecodeDebug(t *testing.T) {
+	if !IsSymbolicEquivalentOfGoFunctionRegistered(Sleep) {
+		RegisterSymbolicGoFunction(Sleep, func(ctx *symbolic.Context, d *symbolic.Duration) {})
+	}
+	if !IsSymbolicEquivalentOfGoFunctionRegistered(sendSecondaryDebugEventPlaceholder) {
+		RegisterSymbolicGoFunction(sendSecondaryDebugEventPlaceholder, func(ctx *symbolic.Context) {})
+	}
+
+	testDebugModeEval(t, func(code string, opts ...debugTestOptions) (any, *Context, *parse.ParsedChunkSource, *Debugger) {
+		state := NewGlobalState(NewDefaultTestContext())
+
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "core-test",
+			CodeString: code,
+		}))
+
+		nextBreakPointId := int32(INITIAL_BREAKPOINT_ID)
+		var options debugTestOptions
+		if len(opts) == 1 {
+			options = opts[0]
+		}
+
+		breakpoints, err := GetBreakpointsFromLines(options.breakpointLines, chunk, &nextBreakPointId)
+		if !assert.NoError(t, err) {
+			assert.Fail(t, "failed to get breakpoints from lines "+err.Error())
+		}
+
+		debugger := NewDebugger(DebuggerArgs{
+			Logger:                zerolog.New(io.Discard),
+			InitialBreakpoints:    breakpoints,
+			ExceptionBreakpointId: options.exceptionBreakpointId,
+		})
+
+		state.Module = &Module{MainChunk: chunk}
+
+		//globals set by the test cases after the setup should be defined before the compilation.
+		if strings.Contains(code, "sleep") {
+			state.Globals.Set("sleep", WrapGoFunction(Sleep))
+		}
+		if strings.Contains(code, "send_secondary_debug_event") {
+			//placeholder replaced by the test case.
+			state.Globals.Set("send_secondary_debug_event", WrapGoFunction(sendSecondaryDebugEventPlaceholder))
+		}
+
+		//the compiler requires symbolic data.
+
+		globals := make(map[string]symbolic.ConcreteGlobalValue)
+		state.Globals.Foreach(func(name string, v Value, isConstant bool) error {
+			globals[name] = symbolic.ConcreteGlobalValue{Value: v, IsConstant: isConstant}
+			return nil
+		})
+
+		symbolicCtx, err := state.Ctx.ToSymbolicValue()
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "failed to get the symbolic context")
+		}
+
+		symbolicData, err := symbolic.EvalCheck(symbolic.EvalCheckInput{
+			Node:    chunk.Node,
+			Module:  state.Module.ToSymbolic(),
+			Globals: globals,
+			Context: symbolicCtx,
+		})
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "symbolic evaluation failed")
+		}
+		state.SymbolicData.AddData(symbolicData)
+
+		bytecode, err := Compile(CompilationInput{
+			Mod:          state.Module,
+			Globals:      state.Globals.permanent,
+			SymbolicData: state.SymbolicData.Data,
+			Context:      state.Ctx,
+		})
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "failed to compile")
+		}
+		state.Bytecode = bytecode
+
+		vm, err := NewVM(VMConfig{
+			Bytecode: bytecode,
+			State:    state,
+		})
+		if !assert.NoError(t, err) {
+			assert.FailNow(t, "failed to create the VM")
+		}
+
+		debugger.AttachAndStart(vm)
+
+		return vm, state.Ctx, chunk, debugger
+	}, func(n parse.Node, state any) (Value, error) {
+		return state.(*VM).Run()
+	})
+}
+
+func sendSecondaryDebugEventPlaceholder(ctx *Context) {}
+
 type debugTestOptions struct {
 	breakpointLines       []int
 	exceptionBreakpointId int32
@@ -396,6 +495,275 @@ func testDebugModeEval(
 			}, stackTraces)
 		})
 
+		t.Run("conditional breakpoint set by line", func(t *testing.T) {
+			state, ctx, chunk, debugger := setup(
+				`a = 0
+				for e in [1, 2, 3, 4] {
+					a = e
+				}
+				return a
+			`)
+
+			controlChan := debugger.ControlChan()
+			stoppedChan := debugger.StoppedChan()
+
+			defer ctx.CancelGracefully()
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk:                   chunk,
+				BreakPointsByLine:       []int{3}, //a = e
+				BreakpointOptionsByLine: []BreakpointOptions{{Condition: "e >= 2", HitCondition: "%2"}},
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			var localScopes []map[string]Value
+
+			go func() {
+				for range [2]int{} {
+					<-stoppedChan
+
+					controlChan <- DebugCommandGetScopes{
+						Get: func(globalScope, localScope map[string]Value) {
+							localScopes = append(localScopes, localScope)
+						},
+						ThreadId: debugger.threadId(),
+					}
+
+					controlChan <- DebugCommandContinue{ThreadId: debugger.threadId()}
+				}
+			}()
+
+			result, err := eval(chunk.Node, state)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Int(4), result)
+
+			//the hits are counted only when the condition is true: e = 2 is the first hit, e = 3 the second one.
+			if assert.Len(t, localScopes, 1) {
+				assert.Equal(t, Int(3), localScopes[0]["e"])
+			}
+		})
+
+		t.Run("setting the breakpoints of another source should not reset the hit counts", func(t *testing.T) {
+			state, ctx, chunk, debugger := setup(
+				`a = 0
+				for e in [1, 2, 3, 4] {
+					a = e
+				}
+				return a
+			`)
+
+			otherChunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+				NameString: "other-core-test",
+				CodeString: "a = 1\nb = 2",
+			}))
+
+			controlChan := debugger.ControlChan()
+			stoppedChan := debugger.StoppedChan()
+
+			defer ctx.CancelGracefully()
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk:                   chunk,
+				BreakPointsByLine:       []int{3}, //a = e
+				BreakpointOptionsByLine: []BreakpointOptions{{HitCondition: "%2"}},
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			var localScopes []map[string]Value
+
+			go func() {
+				for i := range [2]int{} {
+					<-stoppedChan
+
+					controlChan <- DebugCommandGetScopes{
+						Get: func(globalScope, localScope map[string]Value) {
+							localScopes = append(localScopes, localScope)
+						},
+						ThreadId: debugger.threadId(),
+					}
+
+					if i == 0 {
+						controlChan <- DebugCommandSetBreakpoints{
+							Chunk:             otherChunk,
+							BreakPointsByLine: []int{2},
+						}
+					}
+
+					controlChan <- DebugCommandContinue{ThreadId: debugger.threadId()}
+				}
+			}()
+
+			result, err := eval(chunk.Node, state)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Int(4), result)
+
+			if assert.Len(t, localScopes, 2) {
+				assert.Equal(t, Int(2), localScopes[0]["e"])
+				assert.Equal(t, Int(4), localScopes[1]["e"])
+			}
+		})
+
+		t.Run("logpoint set by line", func(t *testing.T) {
+			state, ctx, chunk, debugger := setup(
+				`a = 1
+				a = 2
+				return a
+			`)
+
+			controlChan := debugger.ControlChan()
+			stoppedChan := debugger.StoppedChan()
+			secondaryEventChan := debugger.SecondaryEventsChan()
+
+			defer ctx.CancelGracefully()
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk:                   chunk,
+				BreakPointsByLine:       []int{2}, //a = 2
+				BreakpointOptionsByLine: []BreakpointOptions{{LogMessage: "a is {a}, {{b: a}}"}},
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			result, err := eval(chunk.Node, state)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Int(2), result)
+			assert.Empty(t, stoppedChan)
+
+			select {
+			case event := <-secondaryEventChan:
+				assert.Equal(t, LogpointHitEvent{
+					StateId:      debugger.threadId(),
+					BreakpointId: INITIAL_BREAKPOINT_ID,
+					Message:      `a is 1, {"b": 1}`,
+				}, event)
+			case <-time.After(100 * time.Millisecond):
+				assert.Fail(t, "no logpoint event")
+			}
+		})
+
+		t.Run("evaluate expressions and set a variable while stopped", func(t *testing.T) {
+			state, ctx, chunk, debugger := setup(
+				`a = 1
+				b = a
+				return b
+			`)
+
+			controlChan := debugger.ControlChan()
+			stoppedChan := debugger.StoppedChan()
+
+			defer ctx.CancelGracefully()
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk:             chunk,
+				BreakPointsByLine: []int{2}, //b = a
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			type result struct {
+				value Value
+				err   error
+			}
+
+			var results []result
+
+			go func() {
+				<-stoppedChan
+
+				evaluate := func(expr string, disallowSideEffects bool) {
+					done := make(chan struct{})
+					controlChan <- DebugCommandEvaluate{
+						ThreadId:            debugger.threadId(),
+						Expr:                utils.MustGet(ParseDebugExpression(expr)),
+						DisallowSideEffects: disallowSideEffects,
+						Get: func(value Value, err error) {
+							results = append(results, result{value, err})
+							close(done)
+						},
+					}
+					<-done
+				}
+
+				setVariable := func(name string, value string) {
+					done := make(chan struct{})
+					controlChan <- DebugCommandSetVariable{
+						ThreadId: debugger.threadId(),
+						Name:     name,
+						Value:    utils.MustGet(ParseDebugExpression(value)),
+						Get: func(value Value, err error) {
+							results = append(results, result{value, err})
+							close(done)
+						},
+					}
+					<-done
+				}
+
+				evaluate("a + 1", false)
+				evaluate("f()", true)
+				setVariable("a", `"s"`)
+				setVariable("a", "5")
+
+				controlChan <- DebugCommandContinue{ThreadId: debugger.threadId()}
+			}()
+
+			res, err := eval(chunk.Node, state)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Int(5), res)
+
+			assert.Equal(t, []result{
+				{Int(2), nil},
+				{nil, ErrPossibleSideEffects},
+				{nil, ErrNewVariableValueMismatch},
+				{Int(5), nil},
+			}, results)
+		})
+
+		t.Run("breakpoint with invalid condition", func(t *testing.T) {
+			_, ctx, chunk, debugger := setup(
+				`a = 1
+				return a
+			`)
+
+			controlChan := debugger.ControlChan()
+			defer ctx.CancelGracefully()
+
+			var breakpoints []BreakpointInfo
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk:                   chunk,
+				BreakPointsByLine:       []int{1},
+				BreakpointOptionsByLine: []BreakpointOptions{{Condition: "a ==="}},
+				GetBreakpointsSetByLine: func(b []BreakpointInfo) {
+					breakpoints = b
+				},
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			if assert.Len(t, breakpoints, 1) {
+				assert.False(t, breakpoints[0].Verified())
+				assert.NotEmpty(t, breakpoints[0].Message)
+			}
+		})
+
 		t.Run("successive breakpoints set by line with equal but not same chunk", func(t *testing.T) {
 			state, ctx, chunk, debugger := setup(
 				`a = 1
@@ -1477,6 +1845,98 @@ func testDebugModeEval(
 			}, stackTraces)
 		})
 
+		t.Run("evaluate expressions while stopped in a function call", func(t *testing.T) {
+			state, ctx, chunk, debugger := setup(`
+				fn f(a){
+					b = 3
+					return b
+				}
+				c = 10
+				result = f(2)
+				return result
+			`)
+
+			controlChan := debugger.ControlChan()
+			stoppedChan := debugger.StoppedChan()
+
+			defer ctx.CancelGracefully()
+
+			fnBody := parse.FindNode(chunk.Node, (*parse.FunctionExpression)(nil), nil).Body.(*parse.Block)
+
+			controlChan <- DebugCommandSetBreakpoints{
+				Chunk: chunk,
+				BreakpointsAtNode: map[parse.Node]struct{}{
+					fnBody.Statements[1]: {}, //return b
+				},
+			}
+
+			time.Sleep(10 * time.Millisecond) //wait for the debugger to set the breakpoints
+
+			type result struct {
+				value Value
+				err   error
+			}
+
+			var results []result
+			var trace []StackFrameInfo
+
+			go func() {
+				<-stoppedChan
+
+				done := make(chan struct{})
+				controlChan <- DebugCommandGetStackTrace{
+					Get: func(t []StackFrameInfo) {
+						trace = t
+						close(done)
+					},
+					ThreadId: debugger.threadId(),
+				}
+				<-done
+
+				evaluate := func(expr string, frameId int32) {
+					done := make(chan struct{})
+					controlChan <- DebugCommandEvaluate{
+						ThreadId: debugger.threadId(),
+						FrameId:  frameId,
+						Expr:     utils.MustGet(ParseDebugExpression(expr)),
+						Get: func(value Value, err error) {
+							results = append(results, result{value, err})
+							close(done)
+						},
+					}
+					<-done
+				}
+
+				//the first frame is the current one.
+				if len(trace) == 2 {
+					evaluate("(a + b)", trace[0].Id) //function's frame
+					evaluate("c", trace[1].Id)       //module's frame
+					evaluate("c", 1000)              //unknown frame
+				}
+
+				controlChan <- DebugCommandContinue{ThreadId: debugger.threadId()}
+			}()
+
+			res, err := eval(chunk.Node, state)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Int(3), res)
+
+			if !assert.Len(t, trace, 2) {
+				return
+			}
+			assert.Same(t, fnBody.Statements[1], trace[0].Node)
+
+			assert.Equal(t, []result{
+				{Int(5), nil},
+				{Int(10), nil},
+				{nil, ErrUnknownStackFrame},
+			}, results)
+		})
+
 		t.Run("breakpoint & step in function call", func(t *testing.T) {
 			state, ctx, chunk, debugger := setup(`
 				fn f(a){
@@ -2960,3 +3420,50 @@ func testDebugModeEval(
 
 	})
 }
+
+func TestParseBreakpointHitCondition(t *testing.T) {
+	condition, err := ParseBreakpointHitCondition("3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, BreakpointHitCondition{Operator: ">=", Count: 3}, condition)
+	}
+
+	condition, err = ParseBreakpointHitCondition(" == 2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, BreakpointHitCondition{Operator: "==", Count: 2}, condition)
+		assert.False(t, condition.isMet(1))
+		assert.True(t, condition.isMet(2))
+		assert.False(t, condition.isMet(3))
+	}
+
+	condition, err = ParseBreakpointHitCondition("%2")
+	if assert.NoError(t, err) {
+		assert.False(t, condition.isMet(1))
+		assert.True(t, condition.isMet(2))
+	}
+
+	_, err = ParseBreakpointHitCondition("%0")
+	assert.Error(t, err)
+
+	_, err = ParseBreakpointHitCondition("> a")
+	assert.Error(t, err)
+}
+
+func TestParseLogMessage(t *testing.T) {
+	parts, err := ParseLogMessage("a")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []LogMessagePart{{Text: "a"}}, parts)
+	}
+
+	parts, err = ParseLogMessage("a = {a}.")
+	if assert.NoError(t, err) && assert.Len(t, parts, 3) {
+		assert.Equal(t, "a = ", parts[0].Text)
+		assert.IsType(t, (*parse.IdentifierLiteral)(nil), parts[1].Expr)
+		assert.Equal(t, ".", parts[2].Text)
+	}
+
+	_, err = ParseLogMessage("{a")
+	assert.Error(t, err)
+
+	_, err = ParseLogMessage("{a +}")
+	assert.Error(t, err)
+}
@@ -10,12 +10,66 @@ type BreakpointInfo struct {
 	Id          int32 //unique for a given debugger
 	StartLine   int32
 	StartColumn int32
+
+	Condition    parse.Node             //boolean expression evaluated in the scope of the paused frame, can be nil
+	HitCondition BreakpointHitCondition //zero value if not set
+	LogMessage   []LogMessagePart       //not nil if the breakpoint is a logpoint
+	Message      string                 //reason why the breakpoint is not verified
 }
 
 func (i BreakpointInfo) Verified() bool {
 	return i.NodeSpan != parse.NodeSpan{}
 }
 
+func (i BreakpointInfo) IsLogpoint() bool {
+	return i.LogMessage != nil
+}
+
+// BreakpointOptions are the raw options of a breakpoint set by line, they are parsed by GetBreakpointsFromLinesWithOptions.
+type BreakpointOptions struct {
+	Condition    string //Inox boolean expression
+	HitCondition string //examples: '3', '>= 3', '%2'
+	LogMessage   string //expressions inside braces are interpolated, example: 'a is {a}'
+}
+
+// A BreakpointHitCondition specifies the number of hits required to stop at a breakpoint,
+// only the hits for which the condition of the breakpoint is true are counted.
+type BreakpointHitCondition struct {
+	Operator string //'==', '>=', '>', '<=', '<' or '%', empty if the hit condition is not set
+	Count    int64
+}
+
+func (c BreakpointHitCondition) IsSet() bool {
+	return c.Operator != ""
+}
+
+func (c BreakpointHitCondition) isMet(hits int64) bool {
+	switch c.Operator {
+	case "":
+		return true
+	case "==":
+		return hits == c.Count
+	case ">=":
+		return hits >= c.Count
+	case ">":
+		return hits > c.Count
+	case "<=":
+		return hits <= c.Count
+	case "<":
+		return hits < c.Count
+	case "%":
+		return c.Count != 0 && hits%c.Count == 0
+	default:
+		panic(ErrUnreachable)
+	}
+}
+
+// A LogMessagePart is either a piece of text or an interpolated expression.
+type LogMessagePart struct {
+	Text string
+	Expr parse.Node //nil if the part is a piece of text
+}
+
 type StackFrameInfo struct {
 	Name string
 
@@ -67,6 +121,7 @@ type SecondaryDebugEventType int
 const (
 	IncomingMessageReceivedEventType = iota + 1
 	LThreadSpawnedEventType
+	LogpointHitEventType
 )
 
 func (t SecondaryDebugEventType) String() string {
@@ -75,6 +130,8 @@ func (t SecondaryDebugEventType) String() string {
 		return "incomingMessageReceived"
 	case LThreadSpawnedEventType:
 		return "routineSpawnedEvent"
+	case LogpointHitEventType:
+		return "logpointHit"
 	default:
 		panic(ErrUnreachable)
 	}
@@ -97,6 +154,16 @@ func (e LThreadSpawnedEvent) SecondaryDebugEventType() SecondaryDebugEventType {
 	return LThreadSpawnedEventType
 }
 
+type LogpointHitEvent struct {
+	StateId      StateId `json:"threadId,omitempty"`
+	BreakpointId int32   `json:"breakpointId"`
+	Message      string  `json:"message"`
+}
+
+func (e LogpointHitEvent) SecondaryDebugEventType() SecondaryDebugEventType {
+	return LogpointHitEventType
+}
+
 // Commands
 
 type DebugCommandSetBreakpoints struct {
@@ -107,6 +174,10 @@ type DebugCommandSetBreakpoints struct {
 	//GetBreakpointsSetByLine is invoked with the resulting breakpoints, some of them can be disabled.
 	BreakPointsByLine []int
 
+	//options of the breakpoints set by line, this field is ignored if its length is not equal to the length of .BreakPointsByLine.
+	BreakpointOptionsByLine []BreakpointOptions
+
+	//the breakpoints previously set in Chunk are replaced, the breakpoints of the other chunks are kept.
 	Chunk *parse.ParsedChunkSource
 
 	GetBreakpointsSetByLine func(breakpoints []BreakpointInfo)
@@ -155,6 +226,30 @@ type DebugCommandGetStackTrace struct {
 	ThreadId StateId
 }
 
+// DebugCommandEvaluate evaluates an expression in the scope of a stack frame of a stopped thread.
+type DebugCommandEvaluate struct {
+	ThreadId StateId
+	FrameId  int32 //id of the stack frame, the current stack frame is used if zero
+
+	Expr parse.Node
+
+	//if true only expressions that cannot have side effects are evaluated (e.g. no calls).
+	DisallowSideEffects bool
+
+	Get func(result Value, err error)
+}
+
+// DebugCommandSetVariable sets the value of a local or global variable of a stopped thread,
+// the current and new values should be simple values of the same type.
+type DebugCommandSetVariable struct {
+	ThreadId StateId
+	Global   bool
+	Name     string
+	Value    parse.Node //evaluated in the scope of the current stack frame
+
+	Get func(newValue Value, err error)
+}
+
 type DebugCommandCloseDebugger struct {
 	CancelExecution bool
 	Done            func()
@@ -714,6 +714,31 @@ var (
 		Type:          ERROR_TYPE,
 		Name:          patternnames.ERROR,
 		SymbolicValue: symbolic.ANY_ERR,
+		CallImpl: func(typePattern *TypePattern, args []Serializable) (Pattern, error) {
+			if len(args) != 1 {
+				return nil, commonfmt.FmtErrNArgumentsExpected("1")
+			}
+
+			kind, ok := args[0].(Identifier)
+			if !ok {
+				return nil, FmtErrInvalidArgumentAtPos(args[0], 0)
+			}
+			return NewErrorPattern(kind), nil
+		},
+		SymbolicCallImpl: func(ctx *symbolic.Context, args []symbolic.Value) (symbolic.Pattern, error) {
+			if len(args) != 1 {
+				return nil, commonfmt.FmtErrNArgumentsExpected("1")
+			}
+
+			kind, ok := args[0].(*symbolic.Identifier)
+			if !ok {
+				return nil, fmt.Errorf("error kind (identifier) expected at position 0 but is a(n) '%s'", symbolic.Stringify(args[0]))
+			}
+			if !kind.HasConcreteName() {
+				return symbolic.ANY_ERROR_PATTERN, nil
+			}
+			return symbolic.NewErrorPattern(kind.Name()), nil
+		},
 	}
 	SOURCE_POS_PATTERN = NewInexactRecordPattern([]RecordPatternEntry{
 		{Name: "source", Pattern: STR_PATTERN},
@@ -17,7 +17,7 @@ const (
 
 func (err Error) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
 	otherErr, ok := other.(Error)
-	if !ok {
+	if !ok || err.kind != otherErr.kind {
 		return false
 	}
 
@@ -946,6 +946,15 @@ func (pattern *OptionalPattern) Equal(ctx *Context, other Value, alreadyCompared
 	return pattern.pattern.Equal(ctx, otherPattern.pattern, map[uintptr]uintptr{}, 0)
 }
 
+func (pattern *GenericPattern) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
+	otherPattern, ok := other.(*GenericPattern)
+	if !ok || pattern.typeParameterCount != otherPattern.typeParameterCount {
+		return false
+	}
+
+	return pattern.erased.Equal(ctx, otherPattern.erased, map[uintptr]uintptr{}, 0)
+}
+
 func (pattern *FunctionPattern) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
 	otherPattern, ok := other.(*FunctionPattern)
 	if !ok {
@@ -1370,6 +1379,11 @@ func (e RecordPatternEntry) Equal(ctx *Context, other RecordPatternEntry, alread
 		e.Pattern.Equal(ctx, other.Pattern, alreadyCompared, depth+1)
 }
 
+func (patt *ErrorPattern) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
+	otherPatt, ok := other.(*ErrorPattern)
+	return ok && patt.kind == otherPatt.kind
+}
+
 func (patt *MutationPattern) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
 	otherPatt, ok := other.(*MutationPattern)
 	if !ok {
@@ -1854,6 +1868,14 @@ func (c *TestCase) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]
 	return Same(c, otherCase)
 }
 
+func (b *Benchmark) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
+	otherBenchmark, ok := other.(*Benchmark)
+	if !ok {
+		return false
+	}
+	return Same(b, otherBenchmark)
+}
+
 func (r *TestCaseResult) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
 	otherResult, ok := other.(*TestCaseResult)
 	if !ok {
@@ -2009,6 +2031,16 @@ func (r *RingBuffer) Equal(ctx *Context, other Value, alreadyCompared map[uintpt
 	return ok && r == otherBuf
 }
 
+func (c *Channel) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
+	otherChannel, ok := other.(*Channel)
+	return ok && c == otherChannel
+}
+
+func (it *ChannelIterator) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
+	otherIterator, ok := other.(*ChannelIterator)
+	return ok && it == otherIterator
+}
+
 func (c *DataChunk) Equal(ctx *Context, other Value, alreadyCompared map[uintptr]uintptr, depth int) bool {
 	otherChunk, ok := other.(*DataChunk)
 	return ok && c == otherChunk
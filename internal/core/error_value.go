@@ -1,17 +1,32 @@
 package core
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/inoxlang/inox/internal/parse"
+)
 
 var (
-	ERR_PROPNAMES = []string{"text", "data"}
+	ERR_PROPNAMES = []string{"text", "data", "kind", "cause", "stack"}
+
+	ERROR_STACK_FRAME_KEYS = []string{"chunk", "line", "column", "function"}
 
 	_ = error(Error{})
 )
 
 // An Error represents an error with some immutable data, Error implements Value.
+// An error can have a kind (e.g. #not-found) that is matched by error patterns, a cause, and the Inox call stack
+// captured when the error was created by a call from Inox code.
 type Error struct {
 	goError error
 	data    Serializable
+	kind    Identifier //empty if the error has no kind
+	cause   *Error
+	stack   *[]ErrorStackFrame //innermost frame first, nil if no stack has been captured (pointer to keep Error comparable)
 }
 
 func NewError(err error, data Serializable) Error {
@@ -24,24 +39,109 @@ func NewError(err error, data Serializable) Error {
 	}
 }
 
+// ConvertGoError converts a Go error to an Error. If err wraps an Error, for example the error of a failed must-call,
+// the kind, data and stack of the wrapped Error are kept in order for the result to be matchable by error patterns.
+func ConvertGoError(err error) Error {
+	if e, ok := err.(Error); ok {
+		return e
+	}
+
+	var wrapped Error
+	if errors.As(err, &wrapped) {
+		return Error{
+			goError: err,
+			data:    wrapped.data,
+			kind:    wrapped.kind,
+			stack:   wrapped.stack,
+		}
+	}
+	return NewError(err, Nil)
+}
+
+// WithKind returns a copy of the error with the given kind.
+func (e Error) WithKind(kind Identifier) Error {
+	e.kind = kind
+	return e
+}
+
+// WithCause returns a copy of the error with the given cause.
+func (e Error) WithCause(cause Error) Error {
+	e.cause = &cause
+	return e
+}
+
+// WithStack returns a copy of the error with the given call stack (innermost frame first).
+func (e Error) WithStack(stack []ErrorStackFrame) Error {
+	e.stack = &stack
+	return e
+}
+
 func (e Error) Text() string {
 	return e.goError.Error()
 }
 
 func (e Error) Error() string {
+	if e.cause != nil {
+		return e.Text() + ": " + e.cause.Error()
+	}
 	return e.Text()
 }
 
+// Unwrap returns the underlying Go error and the cause if present.
+func (e Error) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.goError, *e.cause}
+	}
+	return []error{e.goError}
+}
+
 func (e Error) Data() Value {
 	return e.data
 }
 
+func (e Error) Kind() (Identifier, bool) {
+	return e.kind, e.kind != ""
+}
+
+func (e Error) Cause() (Error, bool) {
+	if e.cause == nil {
+		return Error{}, false
+	}
+	return *e.cause, true
+}
+
+func (e Error) Stack() []ErrorStackFrame {
+	if e.stack == nil {
+		return nil
+	}
+	return *e.stack
+}
+
 func (e Error) Prop(ctx *Context, name string) Value {
 	switch name {
 	case "text":
 		return String(e.goError.Error())
 	case "data":
 		return e.data
+	case "kind":
+		if e.kind == "" {
+			return Nil
+		}
+		return e.kind
+	case "cause":
+		if e.cause == nil {
+			return Nil
+		}
+		return *e.cause
+	case "stack":
+		stack := e.Stack()
+		frames := make([]Serializable, len(stack))
+		for i, frame := range stack {
+			frames[i] = NewRecordFromKeyValLists(ERROR_STACK_FRAME_KEYS, []Serializable{
+				String(frame.Chunk), Int(frame.Line), Int(frame.Column), String(frame.Function),
+			})
+		}
+		return NewTuple(frames)
 	default:
 		panic(FormatErrPropertyDoesNotExist(name, e))
 	}
@@ -54,3 +154,121 @@ func (Error) SetProp(ctx *Context, name string, value Value) error {
 func (Error) PropertyNames(ctx *Context) []string {
 	return ERR_PROPNAMES
 }
+
+// An ErrorStackFrame is a frame of the Inox call stack captured when an error is created.
+type ErrorStackFrame struct {
+	Chunk    string //name of the chunk
+	Line     int32
+	Column   int32
+	Function string //name of the function, empty for module-level code and anonymous functions
+}
+
+func (f ErrorStackFrame) String() string {
+	s := f.Chunk + ":" + strconv.Itoa(int(f.Line)) + ":" + strconv.Itoa(int(f.Column))
+	if f.Function != "" {
+		s += " in " + f.Function
+	}
+	return s
+}
+
+// makeErrorStack creates the stack of an error from a chunk stack, the position of the last item is lastSpan.
+// Items pointing to the start of a function (see the stacks built by the evaluators) are ignored.
+func makeErrorStack[Item parse.StackItem](items []Item, lastSpan parse.NodeSpan) []ErrorStackFrame {
+	stack := make([]ErrorStackFrame, 0, len(items))
+
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		chunk, ok := item.GetChunk()
+		if !ok {
+			continue
+		}
+
+		span := lastSpan
+		if i != len(items)-1 {
+			span, ok = item.GetCurrentNodeSpan()
+			if !ok {
+				continue
+			}
+		}
+
+		node, ancestors, ok := chunk.GetNodeAndChainAtSpan(span)
+		if !ok {
+			continue
+		}
+
+		if isFunctionStartSpan(span, node, ancestors) {
+			continue
+		}
+
+		line, column := chunk.GetSpanLineColumn(span)
+		stack = append(stack, ErrorStackFrame{
+			Chunk:    chunk.Name(),
+			Line:     line,
+			Column:   column,
+			Function: getNameOfEnclosingFunction(node, ancestors),
+		})
+	}
+
+	return stack
+}
+
+func isFunctionStartSpan(span parse.NodeSpan, node parse.Node, ancestors []parse.Node) bool {
+	isFunctionStart := func(n parse.Node) bool {
+		switch n.(type) {
+		case *parse.FunctionExpression, *parse.FunctionDeclaration:
+			return n.Base().Span == span
+		}
+		return false
+	}
+
+	return isFunctionStart(node) || slices.ContainsFunc(ancestors, isFunctionStart)
+}
+
+func getNameOfEnclosingFunction(node parse.Node, ancestors []parse.Node) string {
+	for i := len(ancestors); i >= 0; i-- {
+		current := node
+		if i < len(ancestors) {
+			current = ancestors[i]
+		}
+
+		switch n := current.(type) {
+		case *parse.FunctionDeclaration:
+			return n.Name.Name
+		case *parse.FunctionExpression:
+			if i > 0 {
+				if decl, ok := ancestors[i-1].(*parse.FunctionDeclaration); ok {
+					return decl.Name.Name
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// addStackToErrorResults sets the stack of the errors returned by a Go function called from Inox code if they have
+// no stack, getStack is only called if necessary.
+func addStackToErrorResults(result Value, fn *GoFunction, getStack func() []ErrorStackFrame) Value {
+	var stack []ErrorStackFrame
+
+	switch r := result.(type) {
+	case Error:
+		if r.stack == nil {
+			return r.WithStack(getStack())
+		}
+	case *Array:
+		//only the array of results created by GoFunction.Call is updated.
+		if reflect.TypeOf(fn.fn).NumOut() < 2 {
+			break
+		}
+		for i, elem := range *r {
+			if err, ok := elem.(Error); ok && err.stack == nil {
+				if stack == nil {
+					stack = getStack()
+				}
+				(*r)[i] = err.WithStack(stack)
+			}
+		}
+	}
+	return result
+}
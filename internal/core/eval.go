@@ -146,6 +146,13 @@ type BytecodeEvaluationConfig struct {
 	ShowCompilationTrace bool
 	OptimizeBytecode     bool
 	CompilationContext   *Context
+
+	//optimization passes that should not be applied, see BYTECODE_OPTIMIZATION_PASSES.
+	DisabledOptimizationPasses []BytecodeOptimizationPass
+
+	//if not nil the bytecode is executed in debug mode with this debugger,
+	//the bytecode is not optimized in this case.
+	Debugger *Debugger
 }
 
 // EvalVM compiles the passed module (in module source) and evaluates the bytecode with the passed global state.
@@ -155,23 +162,29 @@ func EvalVM(mod *Module, state *GlobalState, config BytecodeEvaluationConfig) (V
 		compilationTracer = config.Tracer
 	}
 
-	bytecode, err := Compile(CompilationInput{
-		Mod:                    mod,
-		Globals:                state.Globals.permanent,
-		SymbolicData:           state.SymbolicData.Data,
-		StaticCheckData:        state.StaticCheckData,
-		TraceWriter:            compilationTracer,
-		Context:                config.CompilationContext,
-		IsTestingEnabled:       state.TestingState.IsTestingEnabled,
-		IsImportTestingEnabled: state.TestingState.IsImportTestingEnabled,
-	})
-	if err != nil {
-		return nil, err
+	//the module is not compiled if it has been compiled during its preparation (see ModulePreparationArgs.BytecodeCache).
+	bytecode := state.Bytecode
+	if bytecode == nil || bytecode.module != mod || bytecode.mainChunk != mod.MainChunk {
+		var err error
+		bytecode, err = Compile(CompilationInput{
+			Mod:                    mod,
+			Globals:                state.Globals.permanent,
+			SymbolicData:           state.SymbolicData.Data,
+			StaticCheckData:        state.StaticCheckData,
+			TraceWriter:            compilationTracer,
+			Context:                config.CompilationContext,
+			IsTestingEnabled:       state.TestingState.IsTestingEnabled,
+			IsImportTestingEnabled: state.TestingState.IsImportTestingEnabled,
+			IsBenchmarkingEnabled:  state.TestingState.IsBenchmarkingEnabled,
+		})
+		if err != nil {
+			return nil, err
+		}
+		state.Bytecode = bytecode
 	}
-	state.Bytecode = bytecode
 
-	if config.OptimizeBytecode {
-		optimizeBytecode(bytecode, compilationTracer)
+	if config.OptimizeBytecode && config.Debugger == nil {
+		optimizeBytecode(bytecode, config.DisabledOptimizationPasses, compilationTracer)
 	}
 
 	config.Tracer.Write([]byte(bytecode.Format(config.CompilationContext, "")))
@@ -183,6 +196,11 @@ func EvalVM(mod *Module, state *GlobalState, config BytecodeEvaluationConfig) (V
 	if err != nil {
 		return nil, err
 	}
+
+	if config.Debugger != nil {
+		config.Debugger.AttachAndStart(vm)
+	}
+
 	return vm.Run()
 }
 
@@ -72,7 +72,13 @@ func TestEvalWithRecycledTreeWalkEvalState(t *testing.T) {
 }
 
 func bytecodeTest(t *testing.T, optimize bool) {
-	testEval(t, true, func(c any, s *GlobalState, doCheck bool) (Value, error) {
+	testEval(t, true, makeBytecodeEvalFunc(t, optimize, nil))
+}
+
+// makeBytecodeEvalFunc returns an evaluation function that compiles the code and evaluates the bytecode,
+// disabledPasses is ignored if optimize is false.
+func makeBytecodeEvalFunc(t *testing.T, optimize bool, disabledPasses []BytecodeOptimizationPass) evalFn {
+	return func(c any, s *GlobalState, doCheck bool) (Value, error) {
 		var mod *Module
 
 		switch val := c.(type) {
@@ -160,9 +166,10 @@ func bytecodeTest(t *testing.T, optimize bool) {
 		NewGlobalState(compilationCtx)
 
 		res, err := EvalVM(mod, s, BytecodeEvaluationConfig{
-			Tracer:             &tracer,
-			OptimizeBytecode:   optimize,
-			CompilationContext: compilationCtx,
+			Tracer:                     &tracer,
+			OptimizeBytecode:           optimize,
+			DisabledOptimizationPasses: disabledPasses,
+			CompilationContext:         compilationCtx,
 		})
 
 		if err != nil {
@@ -171,7 +178,7 @@ func bytecodeTest(t *testing.T, optimize bool) {
 		}
 
 		return res, nil
-	})
+	}
 }
 
 // testEval executes the suite of evaluation tests with a given evaluation function
@@ -3798,6 +3805,26 @@ func testEval(t *testing.T, bytecodeEval bool, Eval evalFn) {
 				`,
 				result: Nil,
 			},
+			{
+				name: "generic function",
+				input: `
+					fn first<T>(list []T) T {
+						return list[0]
+					}
+					return first([1, 2])
+				`,
+				result: Int(1),
+			},
+			{
+				name: "generic function with a constraint",
+				input: `
+					fn f<T int>(a T) []T {
+						return [a]
+					}
+					return f(1)
+				`,
+				result: NewWrappedValueList(Int(1)),
+			},
 			{
 				name:  "must call of a function returning an error",
 				error: true,
@@ -4360,6 +4387,92 @@ func testEval(t *testing.T, bytecodeEval bool, Eval evalFn) {
 		}
 	})
 
+	t.Run("error values", func(t *testing.T) {
+		testconfig.AllowParallelization(t)
+
+		setup := func() *GlobalState {
+			state := NewGlobalState(NewDefaultTestContext())
+			state.Globals.Set("make-error", WrapGoFunction(func(ctx *Context, text String, kind Identifier) Error {
+				return NewError(errors.New(string(text)), Nil).WithKind(kind)
+			}))
+			state.Ctx.AddNamedPattern("error", ERROR_PATTERN)
+			return state
+		}
+
+		t.Run("the Inox call stack should be captured", func(t *testing.T) {
+			state := setup()
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(`
+				fn f(){
+					return make-error("a", #a)
+				}
+				return f()
+			`, state, false)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			stack := res.(Error).Stack()
+			if !assert.Len(t, stack, 2) {
+				return
+			}
+			assert.EqualValues(t, 3, stack[0].Line)
+			assert.Equal(t, "f", stack[0].Function)
+			assert.EqualValues(t, 5, stack[1].Line)
+			assert.Equal(t, "", stack[1].Function)
+		})
+
+		t.Run("kind", func(t *testing.T) {
+			state := setup()
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(`return make-error("a", #a).kind`, state, false)
+			if assert.NoError(t, err) {
+				assert.Equal(t, Identifier("a"), res)
+			}
+		})
+
+		t.Run("match statement with error patterns", func(t *testing.T) {
+			state := setup()
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(`
+				err = make-error("a", #b)
+				match err {
+					%error(#a) { return 1 }
+					%error(#b) { return 2 }
+				}
+				return 0
+			`, state, false)
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, Int(2), res)
+			}
+		})
+
+		t.Run("the error of a failed must call should keep the kind", func(t *testing.T) {
+			state := setup()
+			defer state.Ctx.CancelGracefully()
+
+			_, err := Eval(`
+				fn f(){
+					return make-error("a", #a)
+				}
+				return f!()
+			`, state, false)
+
+			if !assert.Error(t, err) {
+				return
+			}
+
+			converted := ConvertGoError(err)
+			assert.True(t, NewErrorPattern("a").Test(state.Ctx, converted))
+			assert.NotEmpty(t, converted.Stack())
+		})
+	})
+
 	t.Run("Go function call", func(t *testing.T) {
 		testconfig.AllowParallelization(t)
 
@@ -6254,6 +6367,178 @@ func testEval(t *testing.T, bytecodeEval bool, Eval evalFn) {
 			assert.Len(t, res.(*LThreadGroup).threads, 2)
 		})
 
+		t.Run("channel: values sent by lthreads", func(t *testing.T) {
+			code := `
+				ch = Channel(%int, 0)
+				for i in 1..3 {
+					go {globals: {ch: ch, i: i}} do {
+						ch.send!(i)
+					}
+				}
+
+				sum = 0
+				for 1..3 {
+					v = ch.receive!()
+					sum = (sum + v)
+				}
+				return sum
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"Channel": WrapGoFunction(NewChannel),
+			})
+			defer state.Ctx.CancelGracefully()
+			state.Ctx.AddNamedPattern("int", INT_PATTERN)
+
+			res, err := Eval(code, state, true)
+			assert.NoError(t, err)
+			assert.Equal(t, Int(6), res)
+		})
+
+		t.Run("channel: iteration should stop when the channel is closed", func(t *testing.T) {
+			code := `
+				ch = Channel(%int, 0)
+				go {globals: {ch: ch}} do {
+					ch.send!(1)
+					ch.send!(2)
+					ch.close()
+				}
+
+				list = []
+				for v in ch {
+					list.append(v)
+				}
+				return list
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"Channel": WrapGoFunction(NewChannel),
+			})
+			defer state.Ctx.CancelGracefully()
+			state.Ctx.AddNamedPattern("int", INT_PATTERN)
+
+			res, err := Eval(code, state, false)
+			assert.NoError(t, err)
+			assert.Equal(t, NewWrappedValueList(Int(1), Int(2)), res)
+		})
+
+		t.Run("channel: select", func(t *testing.T) {
+			code := `
+				ch1 = Channel(%int, 1)
+				ch2 = Channel(%int, 1)
+				go {globals: {ch2: ch2}} do {
+					ch2.send!(5)
+				}
+
+				assign i v = select!(ch1, ch2, 1s)
+				assign j w = select!(ch1, 10ms)
+				return [i, v, j, w]
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"Channel": WrapGoFunction(NewChannel),
+				"select":  WrapGoFunction(Select),
+			})
+			defer state.Ctx.CancelGracefully()
+			state.Ctx.AddNamedPattern("int", INT_PATTERN)
+
+			res, err := Eval(code, state, false)
+			assert.NoError(t, err)
+			assert.Equal(t, NewWrappedValueList(Int(1), Int(5), Int(-1), Nil), res)
+		})
+
+		t.Run("lthread scope: results", func(t *testing.T) {
+			code := `
+				return lthread_scope!(fn(){
+					go do { return 1 }
+					go do { return 2 }
+				})
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"lthread_scope": WrapGoFunction(RunLThreadScope),
+			})
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(code, state, false)
+			assert.NoError(t, err)
+			assert.Equal(t, NewArrayFrom(Int(1), Int(2)), res)
+		})
+
+		t.Run("lthread scope: the first error should be returned and the other lthreads should be cancelled", func(t *testing.T) {
+			code := `
+				assign results err = lthread_scope(fn(){
+					go do { sleep(10s) }
+					go do { fail!() }
+				})
+				return err
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"lthread_scope": WrapGoFunction(RunLThreadScope),
+				"sleep":         WrapGoFunction(Sleep),
+				"fail": WrapGoFunction(func(ctx *Context) error {
+					return errors.New("failure")
+				}),
+			})
+			defer state.Ctx.CancelGracefully()
+
+			start := time.Now()
+			res, err := Eval(code, state, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Less(t, time.Since(start), 5*time.Second)
+
+			assert.ErrorContains(t, res.(Error), "failure")
+		})
+
+		t.Run("lthread scope: the lthreads should be cancelled when the timeout is exceeded", func(t *testing.T) {
+			code := `
+				t = nil
+				assign results err = lthread_scope(fn(){
+					$$t = go do { sleep(10s) }
+				}, 100ms)
+				return err
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"lthread_scope": WrapGoFunction(RunLThreadScope),
+				"sleep":         WrapGoFunction(Sleep),
+			})
+			defer state.Ctx.CancelGracefully()
+
+			start := time.Now()
+			res, err := Eval(code, state, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Less(t, time.Since(start), 5*time.Second)
+
+			assert.ErrorIs(t, res.(Error), ErrLThreadScopeDeadlineExceeded)
+
+			//no lthread should outlive the scope.
+			assert.True(t, state.Globals.Get("t").(*LThread).IsDone())
+		})
+
+		t.Run("lthread scope: the function should be cancelled when the timeout is exceeded", func(t *testing.T) {
+			code := `
+				assign results err = lthread_scope(fn(){
+					sleep(10s)
+				}, 100ms)
+				return err
+			`
+			state := NewGlobalState(NewDefaultTestContext(), map[string]Value{
+				"lthread_scope": WrapGoFunction(RunLThreadScope),
+				"sleep":         WrapGoFunction(Sleep),
+			})
+			defer state.Ctx.CancelGracefully()
+
+			start := time.Now()
+			res, err := Eval(code, state, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Less(t, time.Since(start), 5*time.Second)
+
+			assert.ErrorIs(t, res.(Error), ErrLThreadScopeDeadlineExceeded)
+			assert.False(t, state.Ctx.IsDone())
+		})
+
 		t.Run("call a passed Inox function", func(t *testing.T) {
 			code := `
 				fn f(){
@@ -7533,6 +7818,39 @@ func testEval(t *testing.T, bytecodeEval bool, Eval evalFn) {
 			assert.Len(t, patt.elements, 1)
 		})
 
+		t.Run("generic pattern: type arguments are erased", func(t *testing.T) {
+			code := `
+				pattern one = 1
+				pattern Paginated<T> = {items: []T}
+				return [
+					({items: [1]} match %Paginated(%one)),
+					({items: ["a"]} match %Paginated(%one)),
+					({items: 1} match %Paginated(%one)),
+				]
+			`
+
+			state := NewGlobalState(NewDefaultTestContext())
+			defer state.Ctx.CancelGracefully()
+			res, err := Eval(code, state, false)
+
+			assert.NoError(t, err)
+			assert.Equal(t, NewWrappedValueList(True, True, False), res)
+		})
+
+		t.Run("generic pattern: invalid number of type arguments", func(t *testing.T) {
+			code := `
+				pattern one = 1
+				pattern Paginated<T> = {items: []T}
+				return %Paginated(%one, %one)
+			`
+
+			state := NewGlobalState(NewDefaultTestContext())
+			defer state.Ctx.CancelGracefully()
+			_, err := Eval(code, state, false)
+
+			assert.Error(t, err)
+		})
+
 	})
 
 	t.Run("pattern namespace definition", func(t *testing.T) {
@@ -11013,6 +11331,507 @@ func testEval(t *testing.T, bytecodeEval bool, Eval evalFn) {
 			assert.True(t, isProperlyInitialized.Load())
 		})
 
+		t.Run("parallel test cases: results should be reported in the order of the statements", func(t *testing.T) {
+			src := makeSourceFile(`
+				testsuite({
+					fs: snapshot
+					parallel: 2
+				}) {
+					testcase "1" {
+						wait(40ms)
+					}
+					testcase "2" {
+						wait(20ms)
+						assert false
+					}
+					testcase "3" {
+						wait(0ms)
+					}
+					testcase "4" {
+						wait(10ms)
+					}
+				}
+			`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			fls := newMemFilesystem()
+			util.WriteFile(fls, "/file.txt", []byte("content"), 0400)
+			state.Globals.Set("snapshot", WrapFsSnapshot(&memFilesystemSnapshot{fls: fls}))
+
+			var lock sync.Mutex
+			running := 0
+			maxRunning := 0
+
+			state.Globals.Set("wait", WrapGoFunction(func(ctx *Context, d Duration) {
+				lock.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				lock.Unlock()
+
+				time.Sleep(time.Duration(d))
+
+				lock.Lock()
+				running--
+				lock.Unlock()
+			}))
+
+			res, err := Eval(src, state, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, Nil, res)
+
+			if !assert.Len(t, state.TestingState.SuiteResults, 1) {
+				return
+			}
+
+			caseResults := state.TestingState.SuiteResults[0].caseResults
+			if !assert.Len(t, caseResults, 4) {
+				return
+			}
+
+			for i, caseResult := range caseResults {
+				assert.Equal(t, strconv.Itoa(i+1), caseResult.testCase.name)
+				assert.Equal(t, i != 1, caseResult.Success)
+			}
+
+			assert.Equal(t, 2, maxRunning)
+		})
+
+		t.Run("parallel test cases: filesystem modifications should be isolated", func(t *testing.T) {
+			src := makeSourceFile(`
+				testsuite({
+					parallel: 3
+				}) {
+					testcase {
+						remove_file /file1.txt
+						test_read_file(/file2.txt)
+					}
+					testcase {
+						remove_file /file2.txt
+						test_read_file(/file1.txt)
+					}
+				}
+			`)
+
+			fls := newSnapshotableMemFilesystem()
+			util.WriteFile(fls, "/file1.txt", []byte("content"), 0400)
+			util.WriteFile(fls, "/file2.txt", []byte("content"), 0400)
+
+			ctx := NewContext(ContextConfig{
+				Permissions: []Permission{
+					GlobalVarPermission{permkind.Read, "*"},
+					GlobalVarPermission{permkind.Use, "*"},
+					GlobalVarPermission{permkind.Create, "*"},
+					LThreadPermission{permkind.Create},
+				},
+				Filesystem: fls,
+				Limits:     []Limit{MustMakeNotAutoDepletingCountLimit(THREADS_SIMULTANEOUS_INSTANCES_LIMIT_NAME, 100_000)},
+			})
+
+			state := NewGlobalState(ctx)
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			var lock sync.Mutex
+			callCount := 0
+
+			state.Globals.Set("test_read_file", WrapGoFunction(func(ctx *Context, path Path) {
+				lock.Lock()
+				callCount++
+				lock.Unlock()
+
+				content, err := util.ReadFile(ctx.GetFileSystem(), path.UnderlyingString())
+				if assert.NoError(t, err) {
+					assert.Equal(t, "content", string(content))
+				}
+			}))
+
+			state.Globals.Set("remove_file", WrapGoFunction(func(ctx *Context, path Path) {
+				err := ctx.GetFileSystem().Remove(path.UnderlyingString())
+				assert.NoError(t, err)
+			}))
+
+			res, err := Eval(src, state, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, Nil, res)
+			assert.Equal(t, 2, callCount)
+
+			//the filesystem of the suite should not have been modified.
+			_, err = fls.Stat("/file1.txt")
+			assert.NoError(t, err)
+			_, err = fls.Stat("/file2.txt")
+			assert.NoError(t, err)
+
+			if assert.Len(t, state.TestingState.SuiteResults, 1) {
+				assert.True(t, state.TestingState.SuiteResults[0].Success)
+			}
+		})
+
+		t.Run("parallel test cases: the filesystem of the suite should be snapshotable", func(t *testing.T) {
+			src := makeSourceFile(`
+				testsuite({
+					parallel: 2
+				}) {
+					testcase {}
+				}
+			`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			_, err := Eval(src, state, false)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "not snapshotable")
+			}
+		})
+
+		t.Run("golden file assertion", func(t *testing.T) {
+			goldenFls := newSnapshotableMemFilesystem()
+			util.WriteFile(goldenFls, "/golden/user.json", []byte(`{"object__value": {"name": "foo", "tags": {"list__value": ["a"]}}}`), 0600)
+
+			setup := func(src parse.SourceFile) (*GlobalState, error) {
+				state := NewGlobalState(NewDefaultTestContext())
+				state.TestingState.IsTestingEnabled = true
+				state.TestingState.Filters = allTestsFilter
+				state.TestingState.GoldenFilesystem = goldenFls
+				state.Globals.Set("assert_golden", WrapGoFunction(func(ctx *Context, v Serializable, path Path) {
+					if err := CheckGoldenFile(ctx, path, []byte(ToPrettyJSON(ctx, v, nil)), DiffJSON); err != nil {
+						panic(err)
+					}
+				}))
+
+				_, err := Eval(src, state, false)
+				return state, err
+			}
+
+			t.Run("equivalent value", func(t *testing.T) {
+				state, err := setup(makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({tags: ["a"], name: "foo"}, ./golden/user.json)
+					}
+				}`))
+				defer state.Ctx.CancelGracefully()
+
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+				assert.True(t, state.TestingState.SuiteResults[0].Success)
+			})
+
+			t.Run("different value", func(t *testing.T) {
+				state, err := setup(makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({name: "bar", tags: ["a", "b"]}, ./golden/user.json)
+					}
+				}`))
+				defer state.Ctx.CancelGracefully()
+
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+
+				suiteResult := state.TestingState.SuiteResults[0]
+				assert.False(t, suiteResult.Success)
+
+				if !assert.Len(t, suiteResult.caseResults, 1) {
+					return
+				}
+				message := suiteResult.caseResults[0].Message
+				assert.Contains(t, message, "value does not match the golden file /golden/user.json")
+				assert.Contains(t, message, `$.object__value.name: expected "foo" but got "bar"`)
+				assert.Contains(t, message, `$.object__value.tags.list__value[1]: unexpected element "b"`)
+			})
+
+			t.Run("missing golden file", func(t *testing.T) {
+				state, err := setup(makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({}, ./golden/missing.json)
+					}
+				}`))
+				defer state.Ctx.CancelGracefully()
+
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+
+				suiteResult := state.TestingState.SuiteResults[0]
+				if assert.Len(t, suiteResult.caseResults, 1) {
+					assert.Contains(t, suiteResult.caseResults[0].Message, "run the tests in update mode")
+				}
+			})
+
+			t.Run("absolute path", func(t *testing.T) {
+				state, err := setup(makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({}, /golden/user.json)
+					}
+				}`))
+				defer state.Ctx.CancelGracefully()
+
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+
+				suiteResult := state.TestingState.SuiteResults[0]
+				assert.False(t, suiteResult.Success)
+				if assert.Len(t, suiteResult.caseResults, 1) {
+					assert.Contains(t, suiteResult.caseResults[0].Message, "should be relative to the directory of the spec file")
+				}
+			})
+
+			t.Run("path outside of the directory of the spec file", func(t *testing.T) {
+				state, err := setup(parse.SourceFile{
+					NameString: "/tests/mod.ix",
+					CodeString: `testsuite {
+						testcase {
+							assert_golden({}, ./golden/../../golden/user.json)
+						}
+					}`,
+					UserFriendlyNameString: "/tests/mod.ix",
+					Resource:               "/tests/mod.ix",
+					ResourceDir:            "/tests/",
+				})
+				defer state.Ctx.CancelGracefully()
+
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+
+				suiteResult := state.TestingState.SuiteResults[0]
+				assert.False(t, suiteResult.Success)
+				if assert.Len(t, suiteResult.caseResults, 1) {
+					assert.Contains(t, suiteResult.caseResults[0].Message, "should be located in the directory of the spec file")
+				}
+			})
+
+			newUpdateModeState := func(permissions ...Permission) *GlobalState {
+				ctx := NewContext(ContextConfig{
+					Permissions: append([]Permission{
+						GlobalVarPermission{permkind.Read, "*"},
+						GlobalVarPermission{permkind.Create, "*"},
+						GlobalVarPermission{permkind.Use, "*"},
+						LThreadPermission{permkind.Create},
+					}, permissions...),
+					Filesystem: newOsFilesystem(),
+					Limits:     []Limit{MustMakeNotAutoDepletingCountLimit(THREADS_SIMULTANEOUS_INSTANCES_LIMIT_NAME, 100_000)},
+				})
+
+				state := NewGlobalState(ctx)
+				state.TestingState.IsTestingEnabled = true
+				state.TestingState.Filters = allTestsFilter
+				state.TestingState.GoldenFilesystem = goldenFls
+				state.TestingState.UpdateGoldenFiles = true
+
+				state.Globals.Set("assert_golden", WrapGoFunction(func(ctx *Context, v Serializable, path Path) {
+					if err := CheckGoldenFile(ctx, path, []byte(ToJSON(ctx, v, nil)), DiffJSON); err != nil {
+						panic(err)
+					}
+				}))
+				return state
+			}
+
+			t.Run("update mode: missing write permission", func(t *testing.T) {
+				src := makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({name: "baz"}, ./golden/user.json)
+					}
+				}`)
+
+				state := newUpdateModeState()
+				defer state.Ctx.CancelGracefully()
+
+				_, err := Eval(src, state, false)
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+
+				suiteResult := state.TestingState.SuiteResults[0]
+				assert.False(t, suiteResult.Success)
+				if assert.Len(t, suiteResult.caseResults, 1) {
+					assert.Contains(t, suiteResult.caseResults[0].Message, "cannot update the golden file")
+				}
+
+				//the golden file should not have been modified.
+				content, err := util.ReadFile(goldenFls, "/golden/user.json")
+				if assert.NoError(t, err) {
+					assert.Contains(t, string(content), `"foo"`)
+				}
+			})
+
+			t.Run("update mode", func(t *testing.T) {
+				src := makeSourceFile(`testsuite {
+					testcase {
+						assert_golden({name: "baz"}, ./golden/new/user.json)
+					}
+				}`)
+
+				state := newUpdateModeState(FilesystemPermission{Kind_: permkind.Write, Entity: PathPattern("/...")})
+				defer state.Ctx.CancelGracefully()
+
+				_, err := Eval(src, state, false)
+				if !assert.NoError(t, err) || !assert.Len(t, state.TestingState.SuiteResults, 1) {
+					return
+				}
+				assert.True(t, state.TestingState.SuiteResults[0].Success)
+
+				content, err := util.ReadFile(goldenFls, "/golden/new/user.json")
+				if assert.NoError(t, err) {
+					assert.Equal(t, `{"object__value":{"name":"baz"}}`, string(content))
+				}
+			})
+		})
+
+		t.Run("benchmark: benchmarking disabled", func(t *testing.T) {
+			src := makeSourceFile(`testsuite "name" {
+				benchmark {
+					assert false
+				}
+			}`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(src, state, false)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Nil, res)
+
+			if !assert.Len(t, state.TestingState.SuiteResults, 1) {
+				return
+			}
+
+			testSuitResult := state.TestingState.SuiteResults[0]
+			assert.True(t, testSuitResult.Success)
+			assert.Empty(t, testSuitResult.benchmarkResults)
+		})
+
+		t.Run("benchmark", func(t *testing.T) {
+			src := makeSourceFile(`testsuite "name" {
+				benchmark({name: "bench", warmup: 2, samples: 3, duration: 5ms}) {
+					list = [1, 2, 3]
+				}
+			}`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.IsBenchmarkingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(src, state, false)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Nil, res)
+
+			if !assert.Len(t, state.TestingState.SuiteResults, 1) {
+				return
+			}
+
+			testSuitResult := state.TestingState.SuiteResults[0]
+			if !assert.Len(t, testSuitResult.benchmarkResults, 1) {
+				return
+			}
+
+			benchmarkResult := testSuitResult.benchmarkResults[0]
+			if !assert.True(t, benchmarkResult.Success) {
+				return
+			}
+
+			assert.Equal(t, "name::bench", benchmarkResult.FullName)
+			assert.Equal(t, 3, benchmarkResult.Stats.Samples)
+			assert.GreaterOrEqual(t, benchmarkResult.Stats.Iterations, 3)
+			assert.Greater(t, benchmarkResult.Stats.MeanNsPerOp, 0.0)
+			assert.Equal(t, []*BenchmarkResult{benchmarkResult}, testSuitResult.BenchmarkResults())
+		})
+
+		t.Run("benchmark with failing assertion", func(t *testing.T) {
+			src := makeSourceFile(`testsuite "name" {
+				benchmark {
+					assert false
+				}
+			}`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.IsBenchmarkingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(src, state, false)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Nil, res)
+
+			if !assert.Len(t, state.TestingState.SuiteResults, 1) {
+				return
+			}
+
+			testSuitResult := state.TestingState.SuiteResults[0]
+			assert.False(t, testSuitResult.Success)
+
+			if !assert.Len(t, testSuitResult.benchmarkResults, 1) {
+				return
+			}
+
+			benchmarkResult := testSuitResult.benchmarkResults[0]
+			assert.False(t, benchmarkResult.Success)
+			assert.Nil(t, benchmarkResult.Stats)
+		})
+
+		t.Run("benchmark in sub test suite", func(t *testing.T) {
+			src := makeSourceFile(`testsuite "name" {
+				testsuite "sub" {
+					benchmark({name: "bench", samples: 1, duration: 1ms}) {}
+				}
+			}`)
+
+			state := NewGlobalState(NewDefaultTestContext())
+			state.TestingState.IsTestingEnabled = true
+			state.TestingState.IsBenchmarkingEnabled = true
+			state.TestingState.Filters = allTestsFilter
+			defer state.Ctx.CancelGracefully()
+
+			res, err := Eval(src, state, false)
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, Nil, res)
+
+			if !assert.Len(t, state.TestingState.SuiteResults, 1) {
+				return
+			}
+
+			benchmarkResults := state.TestingState.SuiteResults[0].BenchmarkResults()
+			if !assert.Len(t, benchmarkResults, 1) {
+				return
+			}
+
+			assert.Equal(t, "name::sub::bench", benchmarkResults[0].FullName)
+		})
+
 		t.Run("main db schema and migrations specified by top level suite: tested program should be allowed to update the data", func(t *testing.T) {
 			//TODO
 
@@ -52,6 +52,7 @@ type GlobalState struct {
 	Bytecode     *Bytecode              //can be nil
 	Globals      GlobalVariables        //global variables
 	LThread      *LThread               //not nil if running in a dedicated LThread
+	lthreadScope *lthreadScope          //innermost lthread scope, only accessed by the goroutine evaluating the module
 	Databases    map[string]*DatabaseIL //the map should never change
 	Heap         *ModuleHeap
 	SystemGraph  *SystemGraph
@@ -73,6 +74,9 @@ type GlobalState struct {
 	GetBasePatternsForImportedModule     func() (map[string]Pattern, map[string]*PatternNamespace)       // return nil maps by default
 	SymbolicBaseGlobalsForImportedModule map[string]symbolic.Value                                       // ok if nil, should not be modified
 
+	//cache used to prepare the module (can be nil), the modules prepared by the module (e.g. request handlers) should use it.
+	BytecodeCache *BytecodeCache
+
 	// debugging and testing
 
 	Debugger     atomic.Value //nil or (nillable) *Debugger
@@ -85,6 +89,7 @@ type GlobalState struct {
 	FirstDatabaseOpeningError error
 	StaticCheckData           *StaticCheckData
 	SymbolicData              *SymbolicData
+	IncrementalCheckData      *IncrementalCheckData //only set if the module has been prepared with incremental checking
 }
 
 type StateId int64
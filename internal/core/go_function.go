@@ -244,7 +244,7 @@ func ConvertReturnValue(rval reflect.Value) Value {
 		if rval.Interface() == nil {
 			return Nil
 		}
-		return NewError(rval.Interface().(error), Nil)
+		return ConvertGoError(rval.Interface().(error))
 	}
 
 	if rval.Kind() == reflect.Slice || rval.Kind() == reflect.Pointer && rval.Elem().Kind() == reflect.Slice {
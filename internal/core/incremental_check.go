@@ -0,0 +1,303 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+)
+
+// An IncrementalCheckData holds the results of the static check and of the symbolic evaluation of a module,
+// it is used by the next preparation of the module to reuse the checking of the unchanged top level
+// statements, see ModulePreparationArgs.IncrementalChecking. An IncrementalCheckData should only be used once.
+type IncrementalCheckData struct {
+	module               *Module
+	inputsKey            string
+	staticCheckData      *StaticCheckData
+	symbolicData         *symbolic.Data
+	reusedStatementCount int
+}
+
+// ReusedTopLevelStatementCount returns the number of top level statements whose checking has been reused from
+// the previous preparation.
+func (d *IncrementalCheckData) ReusedTopLevelStatementCount() int {
+	return d.reusedStatementCount
+}
+
+// A staticCheckCheckpoint is a snapshot of the checking state taken just before the checking of a top level statement
+// of the main chunk. The snapshot maps should not be modified.
+type staticCheckCheckpoint struct {
+	errorCount       int
+	warningCount     int
+	fnDataCount      int
+	mappingDataCount int
+
+	fnDecls           map[string]int
+	structDefs        map[string]int
+	globalVars        map[string]globalVarInfo
+	localVars         map[string]localVarInfo
+	hostAliases       map[string]int
+	patterns          map[string]int
+	patternNamespaces map[string]int
+}
+
+// staticCheckCheckpointing is the checkpointing state of a static check, see StaticCheckInput.SaveTopLevelCheckpoints.
+type staticCheckCheckpointing struct {
+	skippedStatementCount int
+	nextStatementIndex    int
+}
+
+// initTopLevelCheckpointing enables the saving of top level checkpoints, if checkInput.ReusedTopLevelStatementCount is not
+// zero the state of the checker is restored from the checkpoint of the previous check and resumed is true.
+func (c *checker) initTopLevelCheckpointing() (resumed bool, err error) {
+	input := c.checkInput
+
+	chunk, ok := input.Node.(*parse.Chunk)
+	if !ok {
+		return false, fmt.Errorf("top level checkpoints can only be saved for a chunk, not a(n) %T", input.Node)
+	}
+
+	reusedStmtCount := input.ReusedTopLevelStatementCount
+	c.checkpointing = &staticCheckCheckpointing{skippedStatementCount: reusedStmtCount}
+	c.data.checkedChunk = chunk
+
+	if reusedStmtCount == 0 {
+		return false, nil
+	}
+
+	prevData := input.PreviousData
+	if prevData == nil || prevData.checkedChunk == nil ||
+		reusedStmtCount >= len(prevData.topLevelCheckpoints) ||
+		reusedStmtCount > len(chunk.Statements) ||
+		!slices.Equal(prevData.checkedChunk.Statements[:reusedStmtCount], chunk.Statements[:reusedStmtCount]) {
+		return false, symbolic.ErrTopLevelStatementsNotReusable
+	}
+
+	checkpoint := prevData.topLevelCheckpoints[reusedStmtCount]
+
+	c.fnDecls[chunk] = maps.Clone(checkpoint.fnDecls)
+	c.structDefs[chunk] = maps.Clone(checkpoint.structDefs)
+	c.globalVars[chunk] = maps.Clone(checkpoint.globalVars)
+	c.localVars[chunk] = maps.Clone(checkpoint.localVars)
+	c.hostAliases[chunk] = maps.Clone(checkpoint.hostAliases)
+	c.patterns[chunk] = maps.Clone(checkpoint.patterns)
+	c.patternNamespaces[chunk] = maps.Clone(checkpoint.patternNamespaces)
+
+	c.data.errors = slices.Clone(prevData.errors[:checkpoint.errorCount])
+	c.data.warnings = slices.Clone(prevData.warnings[:checkpoint.warningCount])
+
+	for _, fnExpr := range prevData.fnDataKeys[:checkpoint.fnDataCount] {
+		c.data.setFnData(fnExpr, prevData.fnData[fnExpr])
+	}
+	for _, expr := range prevData.mappingDataKeys[:checkpoint.mappingDataCount] {
+		c.data.setMappingData(expr, prevData.mappingData[expr])
+	}
+
+	c.data.topLevelCheckpoints = slices.Clone(prevData.topLevelCheckpoints[:reusedStmtCount])
+	return true, nil
+}
+
+// beforeTopLevelNode is called before the checking of each child of the main chunk, it saves a checkpoint before
+// each top level statement and returns parse.Prune for the nodes whose checking is reused.
+func (c *checker) beforeTopLevelNode(node parse.Node) parse.TraversalAction {
+	checkpointing := c.checkpointing
+	statements := c.checkInput.Node.(*parse.Chunk).Statements
+
+	if checkpointing.nextStatementIndex >= len(statements) || node != statements[checkpointing.nextStatementIndex] {
+		//global constant declarations, preinit block, manifest
+		if checkpointing.skippedStatementCount > 0 {
+			return parse.Prune
+		}
+		return parse.ContinueTraversal
+	}
+
+	index := checkpointing.nextStatementIndex
+	checkpointing.nextStatementIndex++
+
+	if index < checkpointing.skippedStatementCount {
+		return parse.Prune
+	}
+
+	c.saveTopLevelCheckpoint()
+	return parse.ContinueTraversal
+}
+
+func (c *checker) saveTopLevelCheckpoint() {
+	chunk := c.data.checkedChunk
+
+	c.data.topLevelCheckpoints = append(c.data.topLevelCheckpoints, &staticCheckCheckpoint{
+		errorCount:       len(c.data.errors),
+		warningCount:     len(c.data.warnings),
+		fnDataCount:      len(c.data.fnDataKeys),
+		mappingDataCount: len(c.data.mappingDataKeys),
+
+		fnDecls:           maps.Clone(c.getModFunctionDecls(chunk)),
+		structDefs:        maps.Clone(c.getModStructDefs(chunk)),
+		globalVars:        maps.Clone(c.getModGlobalVars(chunk)),
+		localVars:         maps.Clone(c.getLocalVarsInScope(chunk)),
+		hostAliases:       maps.Clone(c.getModHostAliases(chunk)),
+		patterns:          maps.Clone(c.getModPatterns(chunk)),
+		patternNamespaces: maps.Clone(c.getModPatternNamespaces(chunk)),
+	})
+}
+
+// getIncrementalCheckInputsKey returns a string identifying the inputs of the checks (except the module),
+// the checking of the top level statements is only reused if the inputs have the same key.
+func getIncrementalCheckInputsKey(globals GlobalVariables, additionalGlobalConsts []string, patterns map[string]Pattern, patternNamespaces map[string]*PatternNamespace) string {
+	var globalDescs []string
+	globals.Foreach(func(name string, v Value, isConst bool) error {
+		globalDescs = append(globalDescs, fmt.Sprintf("%s:%T:%t", name, v, isConst))
+		return nil
+	})
+
+	var patternNames []string
+	for name := range patterns {
+		patternNames = append(patternNames, name)
+	}
+
+	var namespaceNames []string
+	for name := range patternNamespaces {
+		namespaceNames = append(namespaceNames, name)
+	}
+
+	additionalGlobalConsts = slices.Clone(additionalGlobalConsts)
+
+	var key strings.Builder
+	for _, names := range [][]string{globalDescs, additionalGlobalConsts, patternNames, namespaceNames} {
+		sort.Strings(names)
+		key.WriteString(strings.Join(names, ","))
+		key.WriteByte(';')
+	}
+	return key.String()
+}
+
+// getReusableTopLevelStatementCount returns the number of top level statements of mod's main chunk whose checking can
+// be reused from prev: the leading statements shared with the previous version of the chunk. Zero is returned if the
+// checking cannot be reused.
+func getReusableTopLevelStatementCount(prev *IncrementalCheckData, mod *Module, inputsKey string) int {
+	if prev == nil || prev.module.Name() != mod.Name() || prev.inputsKey != inputsKey {
+		return 0
+	}
+
+	prevMod := prev.module
+	prevChunk := prevMod.MainChunk.Node
+	chunk := mod.MainChunk.Node
+
+	if chunk.GlobalConstantDeclarations != prevChunk.GlobalConstantDeclarations ||
+		chunk.Preinit != prevChunk.Preinit ||
+		chunk.Manifest != prevChunk.Manifest ||
+		chunk.IncludableChunkDesc != nil || prevChunk.IncludableChunkDesc != nil {
+		return 0
+	}
+
+	//the dependencies should have been adopted by adoptUnchangedDependencies.
+	if !slices.Equal(mod.FlattenedIncludedChunkList, prevMod.FlattenedIncludedChunkList) ||
+		!maps.Equal(mod.DirectlyImportedModules, prevMod.DirectlyImportedModules) {
+		return 0
+	}
+
+	count := 0
+	for count < len(chunk.Statements) && count < len(prevChunk.Statements) && chunk.Statements[count] == prevChunk.Statements[count] {
+		count++
+	}
+
+	//the struct definitions and the inclusion imports are handled before the top level statements.
+	for _, stmts := range [][]parse.Node{chunk.Statements, prevChunk.Statements} {
+		for _, stmt := range stmts[count:] {
+			switch stmt.(type) {
+			case *parse.StructDefinition, *parse.InclusionImportStatement:
+				return 0
+			}
+		}
+	}
+
+	count = min(count, len(prev.staticCheckData.topLevelCheckpoints)-1, prev.symbolicData.TopLevelCheckpointCount()-1)
+	return max(count, 0)
+}
+
+// adoptUnchangedDependencies replaces the included chunks and the imported modules of mod with the ones of prevMod
+// if they have the same sources, this allows the checking of the top level statements of the main chunk to be reused
+// (the check data reference the nodes of the dependencies). mod should not have been used yet.
+func adoptUnchangedDependencies(mod, prevMod *Module) {
+	if mod.Name() != prevMod.Name() ||
+		len(mod.FlattenedIncludedChunkList) != len(prevMod.FlattenedIncludedChunkList) ||
+		len(mod.IncludedChunkForest) != len(prevMod.IncludedChunkForest) ||
+		len(mod.InclusionStatementMap) != len(prevMod.InclusionStatementMap) ||
+		len(mod.DirectlyImportedModules) != len(prevMod.DirectlyImportedModules) {
+		return
+	}
+
+	for i, includedChunk := range mod.FlattenedIncludedChunkList {
+		prevIncludedChunk := prevMod.FlattenedIncludedChunkList[i]
+		if includedChunk.Name() != prevIncludedChunk.Name() || includedChunk.Source.Code() != prevIncludedChunk.Source.Code() {
+			return
+		}
+	}
+
+	for name, importedMod := range mod.DirectlyImportedModules {
+		prevImportedMod, ok := prevMod.DirectlyImportedModules[name]
+		if !ok || !haveSameSources(importedMod, prevImportedMod) {
+			return
+		}
+	}
+
+	//the inclusion statements of the main chunk are matched by order.
+	inclusionStmts := parse.FindNodes(mod.MainChunk.Node, (*parse.InclusionImportStatement)(nil), nil)
+	prevInclusionStmts := parse.FindNodes(prevMod.MainChunk.Node, (*parse.InclusionImportStatement)(nil), nil)
+	if len(inclusionStmts) != len(prevInclusionStmts) {
+		return
+	}
+
+	inclusionStmtMap := make(map[*parse.InclusionImportStatement]*IncludedChunk, len(prevMod.InclusionStatementMap))
+	for stmt, includedChunk := range prevMod.InclusionStatementMap {
+		//statements of included chunks.
+		if !slices.Contains(prevInclusionStmts, stmt) {
+			inclusionStmtMap[stmt] = includedChunk
+		}
+	}
+
+	for i, stmt := range inclusionStmts {
+		_, ok := mod.InclusionStatementMap[stmt]
+		prevIncludedChunk, prevOk := prevMod.InclusionStatementMap[prevInclusionStmts[i]]
+		if ok != prevOk {
+			return
+		}
+		if ok {
+			inclusionStmtMap[stmt] = prevIncludedChunk
+		}
+	}
+
+	importedModulesByStmt := make(map[*parse.ImportStatement]*Module, len(mod.DirectlyImportedModulesByStatement))
+	for stmt, importedMod := range mod.DirectlyImportedModulesByStatement {
+		for name, m := range mod.DirectlyImportedModules {
+			if m == importedMod {
+				importedModulesByStmt[stmt] = prevMod.DirectlyImportedModules[name]
+				break
+			}
+		}
+	}
+
+	mod.IncludedChunkForest = prevMod.IncludedChunkForest
+	mod.FlattenedIncludedChunkList = prevMod.FlattenedIncludedChunkList
+	mod.IncludedChunkMap = prevMod.IncludedChunkMap
+	mod.InclusionStatementMap = inclusionStmtMap
+	mod.DirectlyImportedModules = maps.Clone(prevMod.DirectlyImportedModules)
+	mod.DirectlyImportedModulesByStatement = importedModulesByStmt
+}
+
+// haveSameSources returns true if the two modules and their imported modules (recursively) have the same chunks.
+func haveSameSources(a, b *Module) bool {
+	hashA := sha256.New()
+	hashB := sha256.New()
+
+	hashModuleSources(hashA, a, map[*Module]struct{}{})
+	hashModuleSources(hashB, b, map[*Module]struct{}{})
+
+	return bytes.Equal(hashA.Sum(nil), hashB.Sum(nil))
+}
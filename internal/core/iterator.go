@@ -1486,6 +1486,10 @@ func (patt *DifferencePattern) Iterator(ctx *Context, config IteratorConfigurati
 	})
 }
 
+func (patt *GenericPattern) Iterator(ctx *Context, config IteratorConfiguration) Iterator {
+	return patt.erased.Iterator(ctx, config)
+}
+
 func (patt *OptionalPattern) Iterator(ctx *Context, config IteratorConfiguration) Iterator {
 
 	i := -1
@@ -1657,6 +1661,10 @@ func (patt *EventPattern) Iterator(ctx *Context, config IteratorConfiguration) I
 	})
 }
 
+func (patt *ErrorPattern) Iterator(ctx *Context, config IteratorConfiguration) Iterator {
+	return NewEmptyPatternIterator()
+}
+
 func (patt *MutationPattern) Iterator(ctx *Context, config IteratorConfiguration) Iterator {
 	return NewEmptyPatternIterator()
 
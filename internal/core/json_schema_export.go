@@ -0,0 +1,353 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	JSON_SCHEMA_2020_12_URI      = "https://json-schema.org/draft/2020-12/schema"
+	MAX_JSON_SCHEMA_EXPORT_DEPTH = 20
+
+	ANY_VALUE_ACCEPTED_JSON_SCHEMA_WARNING_SUFFIX = ", any value is accepted"
+)
+
+// ConvertPatternToJsonSchema converts an Inox pattern to a JSON Schema (draft 2020-12), the returned schema
+// describes the JSON representation of the values matched by the pattern when it is serialized with the pattern
+// (see ToJSONWithConfig). Patterns that cannot be represented exactly are approximated by a more permissive
+// schema, in the worst case by the schema {} that accepts any value. A warning is added to $warnings for each
+// approximation, each warning starts with the JSON pointer of the approximated subschema.
+//
+// Known approximations:
+//   - patterns with no JSON equivalent (e.g. %int-range, %event) are converted to {}.
+//   - constraints involving several properties of an object pattern are ignored.
+//   - the removed values of a difference pattern are not excluded if their schema is itself an approximation.
+//   - regexes using Go-specific syntax (flags, \A, \z, POSIX classes) are kept as is.
+//
+// Integers outside of the JS safe range are encoded as strings by Inox, this is not reflected in the schema.
+func ConvertPatternToJsonSchema(pattern Pattern) (schema map[string]any, warnings []string) {
+	converter := &jsonSchemaConverter{}
+	schema = converter.convert(pattern, "", 0)
+	schema["$schema"] = JSON_SCHEMA_2020_12_URI
+	return schema, converter.warnings
+}
+
+type jsonSchemaConverter struct {
+	warnings []string
+}
+
+func (c *jsonSchemaConverter) warn(pointer string, format string, args ...any) {
+	if pointer == "" {
+		pointer = "/"
+	}
+	c.warnings = append(c.warnings, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *jsonSchemaConverter) convert(pattern Pattern, pointer string, depth int) map[string]any {
+	if depth > MAX_JSON_SCHEMA_EXPORT_DEPTH {
+		c.warn(pointer, "maximum depth reached"+ANY_VALUE_ACCEPTED_JSON_SCHEMA_WARNING_SUFFIX)
+		return map[string]any{}
+	}
+
+	switch patt := pattern.(type) {
+	case *TypePattern:
+		return c.convertTypePattern(patt, pointer)
+	case *ExactValuePattern:
+		switch v := patt.value.(type) {
+		case NilT:
+			return map[string]any{"const": nil}
+		case Bool:
+			return map[string]any{"const": bool(v)}
+		case Int:
+			return map[string]any{"const": int64(v)}
+		case Float:
+			return map[string]any{"const": float64(v)}
+		case StringLike:
+			return map[string]any{"const": v.GetOrBuildString()}
+		}
+		c.warn(pointer, "the exact value pattern has no JSON equivalent"+ANY_VALUE_ACCEPTED_JSON_SCHEMA_WARNING_SUFFIX)
+		return map[string]any{}
+	case *ExactStringPattern:
+		return map[string]any{"const": string(patt.value)}
+	case *UnionPattern:
+		keyword := "anyOf"
+		if patt.disjoint {
+			keyword = "oneOf"
+		}
+		return map[string]any{keyword: c.convertCases(patt.cases, pointer+"/"+keyword, depth)}
+	case *IntersectionPattern:
+		return map[string]any{"allOf": c.convertCases(patt.cases, pointer+"/allOf", depth)}
+	case *DifferencePattern:
+		base := c.convert(patt.base, pointer+"/allOf/0", depth+1)
+
+		warningCount := len(c.warnings)
+		removed := c.convert(patt.removed, pointer+"/allOf/1/not", depth+1)
+
+		if len(c.warnings) != warningCount {
+			//Negating an approximation would make the schema stricter than the pattern.
+			c.warnings = c.warnings[:warningCount]
+			c.warn(pointer, "the removed values of the difference pattern are not excluded")
+			return base
+		}
+		return map[string]any{"allOf": []any{base, map[string]any{"not": removed}}}
+	case *OptionalPattern:
+		return map[string]any{
+			"anyOf": []any{
+				map[string]any{"type": "null"},
+				c.convert(patt.pattern, pointer+"/anyOf/1", depth+1),
+			},
+		}
+	case *ObjectPattern:
+		return c.convertObjectPattern(patt, pointer, depth)
+	case *RecordPattern:
+		schema := map[string]any{"type": "object"}
+		properties := map[string]any{}
+		required := []any{}
+
+		for _, entry := range patt.entries {
+			properties[entry.Name] = c.convert(entry.Pattern, pointer+"/properties/"+escapeJSONPointerToken(entry.Name), depth+1)
+			if !entry.IsOptional {
+				required = append(required, entry.Name)
+			}
+		}
+		setObjectSchemaProperties(schema, properties, required, patt.inexact)
+		return schema
+	case *ListPattern:
+		return c.convertListPattern(patt, pointer, depth)
+	case *TuplePattern:
+		schema := map[string]any{"type": "array"}
+		if patt.generalElementPattern != nil {
+			schema["items"] = c.convert(patt.generalElementPattern, pointer+"/items", depth+1)
+		} else {
+			schema["prefixItems"] = c.convertCases(patt.elementPatterns, pointer+"/prefixItems", depth)
+			schema["items"] = false
+			schema["minItems"] = len(patt.elementPatterns)
+		}
+		return schema
+	case *IntRangePattern:
+		return c.convertIntRangePattern(patt, pointer)
+	case *FloatRangePattern:
+		schema := map[string]any{"type": "number"}
+		floatRange := patt.floatRange
+
+		if floatRange.unknownStart {
+			c.warn(pointer, "the start of the float range is not known, the range is not bounded below")
+		} else if !math.IsInf(floatRange.start, -1) {
+			schema["minimum"] = floatRange.start
+		}
+
+		if !math.IsInf(floatRange.end, 1) {
+			if floatRange.inclusiveEnd {
+				schema["maximum"] = floatRange.end
+			} else {
+				schema["exclusiveMaximum"] = floatRange.end
+			}
+		}
+
+		if patt.multipleOf > 0 {
+			schema["multipleOf"] = float64(patt.multipleOf)
+		}
+		return schema
+	case *LengthCheckingStringPattern:
+		schema := map[string]any{"type": "string"}
+		setStringLengthConstraints(schema, patt.lengthRange)
+		return schema
+	case *RegexPattern:
+		schema := map[string]any{"type": "string"}
+		//The regex of a RegexPattern is not anchored, like the regex of the "pattern" keyword.
+		schema["pattern"] = c.convertRegex(patt.regexp.String(), pointer)
+		if patt.hasEffectiveLengthRange {
+			setStringLengthConstraints(schema, patt.effectiveLengthRange)
+		}
+		return schema
+	case StringPattern:
+		schema := map[string]any{"type": "string"}
+		if !patt.HasRegex() {
+			c.warn(pointer, "the string pattern has no regex, any string is accepted")
+			return schema
+		}
+		schema["pattern"] = "^(?:" + c.convertRegex(patt.Regex(), pointer) + ")$"
+		setStringLengthConstraints(schema, patt.EffectiveLengthRange())
+		return schema
+	}
+
+	c.warn(pointer, "%T has no JSON Schema equivalent"+ANY_VALUE_ACCEPTED_JSON_SCHEMA_WARNING_SUFFIX, pattern)
+	return map[string]any{}
+}
+
+func (c *jsonSchemaConverter) convertCases(cases []Pattern, pointer string, depth int) []any {
+	schemas := make([]any, len(cases))
+	for i, case_ := range cases {
+		schemas[i] = c.convert(case_, pointer+"/"+strconv.Itoa(i), depth+1)
+	}
+	return schemas
+}
+
+func (c *jsonSchemaConverter) convertTypePattern(patt *TypePattern, pointer string) map[string]any {
+	switch patt {
+	case ANYVAL_PATTERN, SERIALIZABLE_PATTERN:
+		return map[string]any{}
+	case NEVER_PATTERN:
+		return map[string]any{"not": map[string]any{}}
+	case NIL_PATTERN:
+		return map[string]any{"type": "null"}
+	case BOOL_PATTERN:
+		return map[string]any{"type": "boolean"}
+	case INT_PATTERN:
+		return map[string]any{"type": "integer"}
+	case FLOAT_PATTERN:
+		return map[string]any{"type": "number"}
+	case STR_PATTERN, STRING_PATTERN, PATH_PATTERN, HOST_PATTERN:
+		return map[string]any{"type": "string"}
+	case RUNE_PATTERN:
+		return map[string]any{"type": "string", "minLength": 1, "maxLength": 1}
+	case URL_PATTERN:
+		return map[string]any{"type": "string", "format": "uri"}
+	case EMAIL_ADDR_PATTERN:
+		return map[string]any{"type": "string", "format": "email"}
+	case OBJECT_PATTERN, RECORD_PATTERN:
+		return map[string]any{"type": "object"}
+	case LIST_PATTERN, TUPLE_PATTERN:
+		return map[string]any{"type": "array"}
+	}
+
+	c.warn(pointer, "%%%s has no JSON Schema equivalent"+ANY_VALUE_ACCEPTED_JSON_SCHEMA_WARNING_SUFFIX, patt.Name)
+	return map[string]any{}
+}
+
+func (c *jsonSchemaConverter) convertObjectPattern(patt *ObjectPattern, pointer string, depth int) map[string]any {
+	schema := map[string]any{"type": "object"}
+	properties := map[string]any{}
+	required := []any{}
+	dependentRequired := map[string]any{}
+	dependentSchemas := map[string]any{}
+
+	for _, entry := range patt.entries {
+		escapedName := escapeJSONPointerToken(entry.Name)
+
+		properties[entry.Name] = c.convert(entry.Pattern, pointer+"/properties/"+escapedName, depth+1)
+		if !entry.IsOptional {
+			required = append(required, entry.Name)
+		}
+
+		deps := entry.Dependencies
+		if len(deps.RequiredKeys) > 0 {
+			keys := make([]any, len(deps.RequiredKeys))
+			for i, key := range deps.RequiredKeys {
+				keys[i] = key
+			}
+			dependentRequired[entry.Name] = keys
+		}
+		if deps.Pattern != nil {
+			dependentSchemas[entry.Name] = c.convert(deps.Pattern, pointer+"/dependentSchemas/"+escapedName, depth+1)
+		}
+	}
+
+	setObjectSchemaProperties(schema, properties, required, patt.inexact)
+
+	if len(dependentRequired) > 0 {
+		schema["dependentRequired"] = dependentRequired
+	}
+	if len(dependentSchemas) > 0 {
+		schema["dependentSchemas"] = dependentSchemas
+	}
+
+	if len(patt.complexPropertyPatterns) > 0 {
+		c.warn(pointer, "constraints involving several properties are ignored")
+	}
+
+	return schema
+}
+
+func setObjectSchemaProperties(schema map[string]any, properties map[string]any, required []any, inexact bool) {
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if !inexact {
+		schema["additionalProperties"] = false
+	}
+}
+
+func (c *jsonSchemaConverter) convertListPattern(patt *ListPattern, pointer string, depth int) map[string]any {
+	schema := map[string]any{"type": "array"}
+
+	if patt.generalElementPattern != nil {
+		schema["items"] = c.convert(patt.generalElementPattern, pointer+"/items", depth+1)
+
+		if minCount := patt.MinElementCount(); minCount > 0 {
+			schema["minItems"] = minCount
+		}
+		if maxCount := patt.MaxElementCount(); maxCount != DEFAULT_LIST_PATTERN_MAX_ELEM_COUNT {
+			schema["maxItems"] = maxCount
+		}
+	} else {
+		schema["prefixItems"] = c.convertCases(patt.elementPatterns, pointer+"/prefixItems", depth)
+		schema["items"] = false
+		schema["minItems"] = len(patt.elementPatterns)
+	}
+
+	if patt.containedElement != nil {
+		schema["contains"] = c.convert(patt.containedElement, pointer+"/contains", depth+1)
+	}
+
+	return schema
+}
+
+func (c *jsonSchemaConverter) convertIntRangePattern(patt *IntRangePattern, pointer string) map[string]any {
+	schema := map[string]any{"type": "integer"}
+	intRange := patt.intRange
+
+	if intRange.unknownStart {
+		c.warn(pointer, "the start of the integer range is not known, the range is not bounded below")
+	} else if intRange.start != math.MinInt64 {
+		schema["minimum"] = intRange.start
+	}
+
+	if intRange.end != math.MaxInt64 {
+		schema["maximum"] = intRange.end
+	}
+
+	if patt.multipleOf > 0 {
+		schema["multipleOf"] = int64(patt.multipleOf)
+	} else if patt.multipleOfFloat != nil {
+		schema["multipleOf"] = float64(*patt.multipleOfFloat)
+	}
+
+	return schema
+}
+
+func setStringLengthConstraints(schema map[string]any, lengthRange IntRange) {
+	if !lengthRange.unknownStart && lengthRange.start > 0 {
+		schema["minLength"] = lengthRange.start
+	}
+	if end := lengthRange.InclusiveEnd(); end != math.MaxInt64 {
+		schema["maxLength"] = end
+	}
+}
+
+// convertRegex converts a Go regex to an ECMA 262 regex, a warning is added if the regex uses syntax
+// that is specific to Go.
+func (c *jsonSchemaConverter) convertRegex(regex string, pointer string) string {
+	//named groups
+	regex = strings.ReplaceAll(regex, "(?P<", "(?<")
+
+	for _, goSpecificSyntax := range []string{`(?i`, `(?m`, `(?s`, `(?U`, `(?-`, `\A`, `\z`, `\Q`, `[[:`} {
+		if strings.Contains(regex, goSpecificSyntax) {
+			c.warn(pointer, "the regex may not be interpreted identically by ECMA 262 regex engines")
+			break
+		}
+	}
+
+	return regex
+}
+
+// escapeJSONPointerToken escapes a reference token of a JSON pointer (RFC 6901).
+func escapeJSONPointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
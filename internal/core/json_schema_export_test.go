@@ -0,0 +1,250 @@
+package core
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertPatternToJsonSchema(t *testing.T) {
+
+	//compile checks that the schema is a valid JSON Schema (draft 2020-12) and returns the compiled schema.
+	compile := func(t *testing.T, schema map[string]any) *jsonschema.Schema {
+		schemaBytes, err := json.Marshal(schema)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		compiler := jsonschema.NewCompiler()
+		compiler.Draft = jsonschema.Draft2020
+		if !assert.NoError(t, compiler.AddResource("schema.json", strings.NewReader(string(schemaBytes)))) {
+			t.FailNow()
+		}
+
+		compiled, err := compiler.Compile("schema.json")
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return compiled
+	}
+
+	//assertSchema checks that the converted pattern is equal to the expected schema (JSON) and that
+	//the conversion produced no warnings.
+	assertSchema := func(t *testing.T, pattern Pattern, expectedSchema string) *jsonschema.Schema {
+		schema, warnings := ConvertPatternToJsonSchema(pattern)
+		assert.Empty(t, warnings)
+		assert.Equal(t, JSON_SCHEMA_2020_12_URI, schema["$schema"])
+
+		delete(schema, "$schema")
+		assert.JSONEq(t, expectedSchema, string(utils.Must(json.Marshal(schema))))
+
+		schema["$schema"] = JSON_SCHEMA_2020_12_URI
+		return compile(t, schema)
+	}
+
+	validate := func(schema *jsonschema.Schema, instance string) bool {
+		var v any
+		if err := json.Unmarshal([]byte(instance), &v); err != nil {
+			panic(err)
+		}
+		return schema.Validate(v) == nil
+	}
+
+	t.Run("type patterns", func(t *testing.T) {
+		assertSchema(t, INT_PATTERN, `{"type": "integer"}`)
+		assertSchema(t, FLOAT_PATTERN, `{"type": "number"}`)
+		assertSchema(t, STR_PATTERN, `{"type": "string"}`)
+		assertSchema(t, BOOL_PATTERN, `{"type": "boolean"}`)
+		assertSchema(t, NIL_PATTERN, `{"type": "null"}`)
+		assertSchema(t, URL_PATTERN, `{"type": "string", "format": "uri"}`)
+		assertSchema(t, SERIALIZABLE_PATTERN, `{}`)
+
+		schema := assertSchema(t, NEVER_PATTERN, `{"not": {}}`)
+		assert.False(t, validate(schema, `1`))
+	})
+
+	t.Run("exact values", func(t *testing.T) {
+		assertSchema(t, NewExactValuePattern(Int(1)), `{"const": 1}`)
+		assertSchema(t, NewExactStringPattern("a"), `{"const": "a"}`)
+	})
+
+	t.Run("object pattern", func(t *testing.T) {
+		pattern := NewExactObjectPattern([]ObjectPatternEntry{
+			{Name: "name", Pattern: STR_PATTERN},
+			{Name: "age", Pattern: INT_PATTERN, IsOptional: true},
+		})
+
+		schema := assertSchema(t, pattern, `{
+			"type": "object",
+			"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+			"required": ["name"],
+			"additionalProperties": false
+		}`)
+
+		assert.True(t, validate(schema, `{"name": "a"}`))
+		assert.True(t, validate(schema, `{"name": "a", "age": 1}`))
+		assert.False(t, validate(schema, `{"age": 1}`))
+		assert.False(t, validate(schema, `{"name": "a", "b": 1}`))
+
+		inexactPattern := NewInexactObjectPattern([]ObjectPatternEntry{{Name: "name", Pattern: STR_PATTERN}})
+		schema = assertSchema(t, inexactPattern, `{
+			"type": "object",
+			"properties": {"name": {"type": "string"}},
+			"required": ["name"]
+		}`)
+		assert.True(t, validate(schema, `{"name": "a", "b": 1}`))
+	})
+
+	t.Run("object pattern with dependencies", func(t *testing.T) {
+		pattern := NewInexactObjectPattern([]ObjectPatternEntry{
+			{Name: "a", Pattern: INT_PATTERN, IsOptional: true, Dependencies: PropertyDependencies{RequiredKeys: []string{"b"}}},
+			{Name: "b", Pattern: INT_PATTERN, IsOptional: true},
+		})
+
+		schema := assertSchema(t, pattern, `{
+			"type": "object",
+			"properties": {"a": {"type": "integer"}, "b": {"type": "integer"}},
+			"dependentRequired": {"a": ["b"]}
+		}`)
+		assert.True(t, validate(schema, `{"b": 1}`))
+		assert.False(t, validate(schema, `{"a": 1}`))
+	})
+
+	t.Run("record pattern", func(t *testing.T) {
+		assertSchema(t, NewExactRecordPattern([]RecordPatternEntry{{Name: "a", Pattern: INT_PATTERN}}), `{
+			"type": "object",
+			"properties": {"a": {"type": "integer"}},
+			"required": ["a"],
+			"additionalProperties": false
+		}`)
+	})
+
+	t.Run("list patterns", func(t *testing.T) {
+		schema := assertSchema(t, NewListPatternOf(INT_PATTERN).WithMinMaxElements(1, 2), `{
+			"type": "array",
+			"items": {"type": "integer"},
+			"minItems": 1,
+			"maxItems": 2
+		}`)
+		assert.True(t, validate(schema, `[1, 2]`))
+		assert.False(t, validate(schema, `[]`))
+		assert.False(t, validate(schema, `[1, 2, 3]`))
+
+		schema = assertSchema(t, NewListPattern([]Pattern{INT_PATTERN, STR_PATTERN}), `{
+			"type": "array",
+			"prefixItems": [{"type": "integer"}, {"type": "string"}],
+			"items": false,
+			"minItems": 2
+		}`)
+		assert.True(t, validate(schema, `[1, "a"]`))
+		assert.False(t, validate(schema, `[1]`))
+		assert.False(t, validate(schema, `[1, "a", 2]`))
+
+		assertSchema(t, NewListPatternOf(INT_PATTERN).WithElement(NewExactValuePattern(Int(0))), `{
+			"type": "array",
+			"items": {"type": "integer"},
+			"contains": {"const": 0}
+		}`)
+
+		assertSchema(t, NewTuplePatternOf(INT_PATTERN), `{"type": "array", "items": {"type": "integer"}}`)
+	})
+
+	t.Run("union, intersection and difference patterns", func(t *testing.T) {
+		assertSchema(t, NewUnionPattern([]Pattern{INT_PATTERN, STR_PATTERN}, nil), `{
+			"anyOf": [{"type": "integer"}, {"type": "string"}]
+		}`)
+
+		assertSchema(t, NewDisjointUnionPattern([]Pattern{INT_PATTERN, STR_PATTERN}, nil), `{
+			"oneOf": [{"type": "integer"}, {"type": "string"}]
+		}`)
+
+		assertSchema(t, NewIntersectionPattern([]Pattern{OBJECT_PATTERN, NewInexactRecordPattern(nil)}, nil), `{
+			"allOf": [{"type": "object"}, {"type": "object"}]
+		}`)
+
+		schema := assertSchema(t, NewDifferencePattern(INT_PATTERN, NewExactValuePattern(Int(0))), `{
+			"allOf": [{"type": "integer"}, {"not": {"const": 0}}]
+		}`)
+		assert.True(t, validate(schema, `1`))
+		assert.False(t, validate(schema, `0`))
+	})
+
+	t.Run("optional pattern", func(t *testing.T) {
+		assertSchema(t, &OptionalPattern{pattern: INT_PATTERN}, `{
+			"anyOf": [{"type": "null"}, {"type": "integer"}]
+		}`)
+	})
+
+	t.Run("int range patterns", func(t *testing.T) {
+		schema := assertSchema(t, NewIncludedEndIntRangePattern(0, 10, 2), `{
+			"type": "integer",
+			"minimum": 0,
+			"maximum": 10,
+			"multipleOf": 2
+		}`)
+		assert.True(t, validate(schema, `4`))
+		assert.False(t, validate(schema, `3`))
+		assert.False(t, validate(schema, `12`))
+
+		assertSchema(t, NewIntRangePattern(NewIntRange(0, math.MaxInt64), -1), `{"type": "integer", "minimum": 0}`)
+	})
+
+	t.Run("float range patterns", func(t *testing.T) {
+		assertSchema(t, NewFloatRangePattern(NewIncludedEndFloatRange(0, 1), -1), `{
+			"type": "number",
+			"minimum": 0,
+			"maximum": 1
+		}`)
+
+		assertSchema(t, NewFloatRangePattern(FloatRange{start: math.Inf(-1), end: 1}, -1), `{
+			"type": "number",
+			"exclusiveMaximum": 1
+		}`)
+	})
+
+	t.Run("string patterns", func(t *testing.T) {
+		assertSchema(t, NewLengthCheckingStringPattern(1, 10), `{"type": "string", "minLength": 1, "maxLength": 10}`)
+
+		schema := assertSchema(t, NewRegexPattern("a+"), `{"type": "string", "pattern": "a+"}`)
+		assert.True(t, validate(schema, `"baa"`))
+		assert.False(t, validate(schema, `"b"`))
+
+		sequence, err := NewSequenceStringPattern(nil, nil, []StringPattern{NewExactStringPattern("a"), NewRegexPattern("[0-9]+")}, KeyList{"", ""})
+		if !assert.NoError(t, err) {
+			return
+		}
+		sequenceSchema, warnings := ConvertPatternToJsonSchema(sequence)
+		assert.Empty(t, warnings)
+
+		compiled := compile(t, sequenceSchema)
+		assert.True(t, validate(compiled, `"a12"`))
+		assert.False(t, validate(compiled, `"a"`))
+		assert.False(t, validate(compiled, `"ba1"`))
+	})
+
+	t.Run("approximations", func(t *testing.T) {
+		schema, warnings := ConvertPatternToJsonSchema(NewInexactObjectPattern([]ObjectPatternEntry{
+			{Name: "a", Pattern: INT_RANGE_PATTERN},
+		}))
+		compile(t, schema)
+
+		assert.Equal(t, map[string]any{}, schema["properties"].(map[string]any)["a"])
+		assert.Equal(t, []string{"/properties/a: %int-range has no JSON Schema equivalent, any value is accepted"}, warnings)
+
+		//the removed pattern is an approximation
+		schema, warnings = ConvertPatternToJsonSchema(NewDifferencePattern(INT_PATTERN, INT_RANGE_PATTERN))
+		compile(t, schema)
+
+		assert.Equal(t, "integer", schema["type"])
+		assert.Equal(t, []string{"/: the removed values of the difference pattern are not excluded"}, warnings)
+
+		//Go-specific regex syntax
+		_, warnings = ConvertPatternToJsonSchema(NewRegexPattern(`(?i)a`))
+		assert.Len(t, warnings, 1)
+	})
+}
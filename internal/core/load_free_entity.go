@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/inoxlang/inox/internal/utils"
 )
 
 var (
@@ -119,6 +121,7 @@ func loadFreeObject(ctx *Context, args FreeEntityLoadingParams) (UrlHolder, erro
 	storage := args.Storage
 
 	objectPattern := pattern.(*ObjectPattern)
+	format := GetDataStoreSerializationFormat(storage)
 
 	object, ok := args.InitialValue.(*Object)
 	if !ok && args.InitialValue != nil {
@@ -131,7 +134,7 @@ func loadFreeObject(ctx *Context, args FreeEntityLoadingParams) (UrlHolder, erro
 			return nil, fmt.Errorf("%w: %s", ErrFailedToLoadNonExistingValue, path)
 		}
 
-		parsed, err := ParseJSONRepresentation(ctx, serialized, objectPattern)
+		parsed, err := ParseStorageRepresentation(ctx, serialized, objectPattern, format)
 
 		if err != nil {
 			return nil, err
@@ -154,7 +157,7 @@ func loadFreeObject(ctx *Context, args FreeEntityLoadingParams) (UrlHolder, erro
 	}
 
 	if args.InitialValue != nil {
-		storage.SetSerialized(ctx, path, GetJSONRepresentation(object, ctx, pattern))
+		storage.SetSerialized(ctx, path, utils.Must(GetStorageRepresentation(ctx, object, pattern, format)))
 		if args.Migration != nil {
 			panic(ErrUnreachable)
 		}
@@ -187,7 +190,7 @@ func loadFreeObject(ctx *Context, args FreeEntityLoadingParams) (UrlHolder, erro
 			return nil, fmt.Errorf("missing next pattern for %s", path)
 		}
 		pattern = args.Migration.NextPattern
-		updatedRepr := GetJSONRepresentation(object, ctx, pattern)
+		updatedRepr := utils.Must(GetStorageRepresentation(ctx, object, pattern, format))
 		storage.SetSerialized(ctx, path, updatedRepr)
 	}
 
@@ -196,7 +199,7 @@ func loadFreeObject(ctx *Context, args FreeEntityLoadingParams) (UrlHolder, erro
 	//add mutation handlers
 	object.OnMutation(ctx, func(ctx *Context, mutation Mutation) (registerAgain bool) {
 		registerAgain = true
-		updatedRepr := GetJSONRepresentation(object, ctx, pattern)
+		updatedRepr := utils.Must(GetStorageRepresentation(ctx, object, pattern, format))
 		storage.SetSerialized(ctx, path, updatedRepr)
 		return
 	}, MutationWatchingConfiguration{
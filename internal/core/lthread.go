@@ -10,6 +10,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/inoxlang/inox/internal/afs"
 	permkind "github.com/inoxlang/inox/internal/core/permkind"
 	"github.com/inoxlang/inox/internal/core/symbolic"
 	"github.com/inoxlang/inox/internal/parse"
@@ -52,6 +53,7 @@ type LThread struct {
 	err         Error
 	done        atomic.Bool
 	wait_result chan struct{}
+	finished    chan struct{} //closed once the result is set
 }
 
 type LthreadSpawnArgs struct {
@@ -70,6 +72,12 @@ type LthreadSpawnArgs struct {
 	TestItem         TestItem
 	TestedProgram    *Module
 
+	IsBenchmarkingEnabled bool
+	BenchmarkBaseline     *BenchmarkBaseline
+
+	UpdateGoldenFiles bool
+	GoldenFilesystem  afs.Filesystem
+
 	//AbsScriptDir string
 	Bytecode    *Bytecode
 	UseBytecode bool
@@ -159,11 +167,19 @@ func SpawnLThread(args LthreadSpawnArgs) (*LThread, error) {
 	if args.IsTestingEnabled {
 		modState.TestingState.IsTestingEnabled = true
 		modState.TestingState.Filters = args.TestFilters
+		modState.TestingState.IsBenchmarkingEnabled = args.IsBenchmarkingEnabled
+		modState.TestingState.BenchmarkBaseline = args.BenchmarkBaseline
+		modState.TestingState.UpdateGoldenFiles = args.UpdateGoldenFiles
+		modState.TestingState.GoldenFilesystem = args.GoldenFilesystem
 
 		if args.TestItem != nil {
 			modState.TestingState.Item = args.TestItem
 			modState.TestingState.TestedProgram = args.TestedProgram
 			modState.TestingState.ItemFullName = makeTestFullName(args.TestItem, args.SpawnerState)
+
+			if suite, ok := args.TestItem.(*TestSuite); ok && suite.RunsTestCasesInParallel() {
+				modState.TestingState.parallelTestCases = newParallelTestCaseRunner(suite.parallelism)
+			}
 		}
 	}
 	modState.OutputFieldsInitialized.Store(true)
@@ -197,6 +213,7 @@ func SpawnLthreadWithState(args LthreadWithStateSpawnArgs) (*LThread, error) {
 		module:           modState.Module,
 		state:            modState,
 		wait_result:      make(chan struct{}, 1),
+		finished:         make(chan struct{}),
 		continueExecChan: make(chan struct{}, 1),
 		useBytecode:      args.UseBytecode,
 		executedStepCallbackFn: func(step ExecutedStep, lthread *LThread) (continueExec bool) {
@@ -225,6 +242,8 @@ func SpawnLthreadWithState(args LthreadWithStateSpawnArgs) (*LThread, error) {
 		var res Value
 		var err error
 
+		defer close(lthread.finished)
+
 		defer func() {
 			e := recover()
 			if v, ok := e.(error); ok {
@@ -274,30 +293,59 @@ func SpawnLthreadWithState(args LthreadWithStateSpawnArgs) (*LThread, error) {
 		defer modState.Ctx.CancelGracefully()
 		defer modState.Ctx.DefinitelyStopCPUTimeDepletion()
 
+		//attachDebugger attaches a child of the spawner's debugger (if any) to the evaluation state of the lthread.
+		attachDebugger := func(state evaluationState) (detach func()) {
+			parentDebugger, ok := args.SpawnerState.Debugger.Load().(*Debugger)
+			if !ok || parentDebugger.Closed() {
+				return func() {}
+			}
+			debugger := parentDebugger.NewChild()
+
+			parentDebugger.ControlChan() <- DebugCommandInformAboutSecondaryEvent{
+				Event: LThreadSpawnedEvent{
+					StateId: modState.id,
+				},
+			}
+			debugger.AttachAndStart(state)
+			modState.Debugger.Store(debugger)
+
+			return func() {
+				debugger.ControlChan() <- DebugCommandCloseDebugger{}
+			}
+		}
+
 		if args.UseBytecode {
-			res, err = EvalBytecode(lthread.state.Bytecode, modState, args.Self)
+			var vm *VM
+			vm, err = NewVM(VMConfig{
+				Bytecode: lthread.state.Bytecode,
+				State:    modState,
+				Self:     args.Self,
+			})
+			if err != nil {
+				return
+			}
+			defer attachDebugger(vm)()
+
+			res, err = vm.Run()
 		} else {
 			state := NewTreeWalkStateWithGlobal(modState)
 			state.self = args.Self
 
-			parentDebugger, ok := args.SpawnerState.Debugger.Load().(*Debugger)
-			if ok && !parentDebugger.Closed() {
-				debugger := parentDebugger.NewChild()
-
-				parentDebugger.ControlChan() <- DebugCommandInformAboutSecondaryEvent{
-					Event: LThreadSpawnedEvent{
-						StateId: modState.id,
-					},
-				}
-				debugger.AttachAndStart(state)
-				modState.Debugger.Store(debugger)
-
-				defer func() {
-					debugger.ControlChan() <- DebugCommandCloseDebugger{}
-				}()
+			defer attachDebugger(state)()
+
+			if benchmark, ok := modState.TestingState.Item.(*Benchmark); ok {
+				res, err = benchmark.measure(chunk.(*parse.Chunk), state)
+			} else {
+				res, err = TreeWalkEval(chunk, state)
 			}
+		}
 
-			res, err = TreeWalkEval(chunk, state)
+		//wait for the test cases running in parallel.
+		if runner := modState.TestingState.parallelTestCases; runner != nil {
+			waitErr := runner.wait(modState)
+			if err == nil {
+				err = waitErr
+			}
 		}
 
 	}(modState, modState.Module.MainChunk.Node, lthread, args.StartPaused, args.Self)
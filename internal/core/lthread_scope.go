@@ -0,0 +1,173 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+var (
+	ErrLThreadScopeDeadlineExceeded = errors.New("lthread scope: deadline exceeded")
+	ErrInvalidLThreadScopeTimeout   = errors.New("lthread scope: the timeout should be positive")
+)
+
+func init() {
+	RegisterSymbolicGoFunction(RunLThreadScope, symbolic.RunLThreadScope)
+}
+
+// An lthreadScope is the set of lthreads spawned during the execution of the function passed to RunLThreadScope.
+// The first failure (error, cancellation or deadline) causes the cancellation of all the lthreads of the scope.
+type lthreadScope struct {
+	lock     sync.Mutex
+	threads  []*LThread
+	firstErr error
+}
+
+// add adds a lthread to the scope, the lthread is immediately cancelled if the scope has already failed.
+func (s *lthreadScope) add(lthread *LThread) {
+	s.lock.Lock()
+	s.threads = append(s.threads, lthread)
+	failed := s.firstErr != nil
+	s.lock.Unlock()
+
+	if failed {
+		lthread.Cancel(nil)
+		return
+	}
+
+	go func() {
+		<-lthread.finished
+		if err := lthread.err.goError; err != nil {
+			s.fail(err)
+		}
+	}()
+}
+
+// fail records err if it is the first failure and cancels all the lthreads of the scope.
+func (s *lthreadScope) fail(err error) {
+	s.lock.Lock()
+	if s.firstErr != nil {
+		s.lock.Unlock()
+		return
+	}
+	s.firstErr = err
+	threads := s.threads
+	s.lock.Unlock()
+
+	for _, lthread := range threads {
+		lthread.Cancel(nil)
+	}
+}
+
+// wait waits for the termination of all the lthreads of the scope, if ctx is done the lthreads are cancelled.
+// Since lthreads return their token for the threads/simul-instances limit before terminating, the tokens are
+// available once wait returns.
+func (s *lthreadScope) wait(ctx *Context) {
+	s.lock.Lock()
+	threads := s.threads
+	s.lock.Unlock()
+
+	ctx.DoIO(func() error {
+		for _, lthread := range threads {
+			select {
+			case <-lthread.finished:
+			case <-ctx.Done():
+				s.fail(ctx.Err())
+				<-lthread.finished
+			}
+		}
+		return nil
+	})
+}
+
+// RunLThreadScope calls fn, the lthreads spawned during the call are part of a scope that ends when all of them
+// are done: no lthread of the scope outlives the call to RunLThreadScope. If one of the lthreads fails, or if fn
+// fails or returns an error, the other lthreads are cancelled and the first error is returned. An optional timeout
+// can be passed after the function, fn and the lthreads are cancelled when it is exceeded. On success the results
+// of the lthreads are returned in the order they were spawned.
+func RunLThreadScope(ctx *Context, fn *InoxFunction, timeoutParam *OptionalParam[Duration]) (*Array, error) {
+	var timeout time.Duration
+
+	if timeoutParam != nil {
+		timeout = time.Duration(timeoutParam.Value)
+		if timeout <= 0 {
+			return nil, ErrInvalidLThreadScopeTimeout
+		}
+	}
+
+	state := ctx.GetClosestState()
+
+	scope := &lthreadScope{}
+
+	//fn is called with a child context that is cancelled when the deadline is exceeded,
+	//the lthreads spawned by fn are descendants of this context.
+	fnCtx := ctx.BoundChild()
+	defer fnCtx.CancelGracefully()
+
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			scope.fail(ErrLThreadScopeDeadlineExceeded)
+			fnCtx.CancelGracefully()
+		})
+		defer timer.Stop()
+	}
+
+	var fnErr error
+
+	func() {
+		parentScope := state.lthreadScope
+		parentCtx := state.Ctx
+		state.lthreadScope = scope
+		state.Ctx = fnCtx
+
+		defer func() {
+			state.lthreadScope = parentScope
+			state.Ctx = parentCtx
+
+			if e := recover(); e != nil {
+				fnErr = utils.ConvertPanicValueToError(e)
+			}
+		}()
+
+		result, err := fn.Call(state, nil, nil, nil)
+		if e, ok := result.(Error); ok && err == nil {
+			err = e
+		}
+		fnErr = err
+	}()
+
+	if fnErr != nil {
+		scope.fail(fnErr)
+	}
+
+	scope.wait(ctx)
+
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+
+	//the success is checked before the first error because the deadline may be exceeded after the termination
+	//of the lthreads. The failure of an lthread may also not be recorded yet.
+	var lthreadErr error
+	for _, lthread := range scope.threads {
+		if err := lthread.err.goError; err != nil {
+			lthreadErr = err
+			break
+		}
+	}
+
+	if fnErr != nil || lthreadErr != nil {
+		if scope.firstErr != nil {
+			return nil, scope.firstErr
+		}
+		return nil, lthreadErr
+	}
+
+	results := make(Array, len(scope.threads))
+	for i, lthread := range scope.threads {
+		results[i] = lthread.result
+	}
+	return &results, nil
+}
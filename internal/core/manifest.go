@@ -44,6 +44,7 @@ const (
 	MANIFEST_DATABASE__RESOLUTION_DATA_PROP_NAME        = "resolution-data"
 	MANIFEST_DATABASE__EXPECTED_SCHEMA_UPDATE_PROP_NAME = "expected-schema-update"
 	MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME   = "assert-schema"
+	MANIFEST_DATABASE__FORMAT_PROP_NAME                 = "format"
 
 	//invocation section
 	MANIFEST_INVOCATION__ON_ADDED_ELEM_PROP_NAME = "on-added-element"
@@ -85,6 +86,7 @@ var (
 		LifetimeJobModule:     {MANIFEST_PERMS_SECTION_NAME, MANIFEST_LIMITS_SECTION_NAME},
 		TestSuiteModule:       {MANIFEST_PERMS_SECTION_NAME, MANIFEST_LIMITS_SECTION_NAME},
 		TestCaseModule:        {MANIFEST_PERMS_SECTION_NAME, MANIFEST_LIMITS_SECTION_NAME},
+		BenchmarkModule:       {MANIFEST_PERMS_SECTION_NAME, MANIFEST_LIMITS_SECTION_NAME},
 	}
 
 	MANIFEST_DATABASE_PROPNAMES = []string{
@@ -92,6 +94,7 @@ var (
 		MANIFEST_DATABASE__RESOLUTION_DATA_PROP_NAME,
 		MANIFEST_DATABASE__EXPECTED_SCHEMA_UPDATE_PROP_NAME,
 		MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME,
+		MANIFEST_DATABASE__FORMAT_PROP_NAME,
 	}
 
 	ErrURLNotCorrespondingToDefinedDB = errors.New("URL does not correspond to a defined database")
@@ -173,7 +176,8 @@ type DatabaseConfig struct {
 	Resource             SchemeHolder //URL or Host
 	ResolutionData       Value        //ResourceName or Nil
 	ExpectedSchemaUpdate bool
-	ExpectedSchema       *ObjectPattern //can be nil, not related to .ExpectedSchemaUpdate
+	ExpectedSchema       *ObjectPattern      //can be nil, not related to .ExpectedSchemaUpdate
+	Format               SerializationFormat //serialization format of the values if the database is new
 	Owned                bool
 
 	Provided *DatabaseIL //optional (can be provided by another module instance)
@@ -1601,6 +1605,17 @@ func getDatabaseConfigurations(v Value, parentState *GlobalState) (DatabaseConfi
 				default:
 					return fmt.Errorf("invalid value found for the .%s of a database description", MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME)
 				}
+			case MANIFEST_DATABASE__FORMAT_PROP_NAME:
+				switch val := propVal.(type) {
+				case Identifier:
+					format, err := ParseSerializationFormat(string(val))
+					if err != nil {
+						return err
+					}
+					config.Format = format
+				default:
+					return fmt.Errorf("invalid value found for the .%s of a database description", MANIFEST_DATABASE__FORMAT_PROP_NAME)
+				}
 			}
 			return nil
 		})
@@ -41,6 +41,7 @@ var (
 		UserLThreadModule:     "userlthread",
 		TestSuiteModule:       "testsuite",
 		TestCaseModule:        "testcase",
+		BenchmarkModule:       "benchmark",
 		LifetimeJobModule:     "lifetimejob",
 		ApplicationModule:     "application",
 	}
@@ -102,6 +103,7 @@ const (
 	UserLThreadModule
 	TestSuiteModule
 	TestCaseModule
+	BenchmarkModule
 	LifetimeJobModule
 )
 
@@ -116,7 +118,7 @@ func ParseModuleKind(s string) (ModuleKind, error) {
 }
 
 func (k ModuleKind) IsTestModule() bool {
-	return k == TestSuiteModule || k == TestCaseModule
+	return k == TestSuiteModule || k == TestCaseModule || k == BenchmarkModule
 }
 
 func (k ModuleKind) IsEmbedded() bool {
@@ -337,6 +339,16 @@ func ParseLocalModule(fpath string, config ModuleParsingConfig) (*Module, error)
 		return nil, err
 	}
 
+	if config.DependencyLock == nil && !config.UpdateDependencyLock {
+		if lockFilePath, found := FindDependencyLockFile(fls, filepath.Dir(absPath)); found {
+			lockFile, err := ReadDependencyLockFile(fls, lockFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", lockFilePath, err)
+			}
+			config.DependencyLock = lockFile
+		}
+	}
+
 	//read the script
 
 	{
@@ -394,6 +406,16 @@ type ModuleParsingConfig struct {
 	RecoverFromNonExistingIncludedFiles bool
 	IgnoreBadlyConfiguredModuleImports  bool
 	InsecureModImports                  bool
+
+	//If DependencyLock is nil ParseLocalModule searches for a lock file (DEPENDENCY_LOCK_FILENAME) in the directory
+	//of the module and its ancestors. If a lock file is used the modules imported from URLs are the locked ones.
+	DependencyLock *DependencyLockFile
+	//If true the version constraints in import URLs are resolved and DependencyLock (if not nil) is updated.
+	UpdateDependencyLock bool
+
+	//If set and if its name is the name of the parsed module's source, the module is parsed incrementally:
+	//the top level statements of PreviousMainChunk that are not affected by the changes are reused.
+	PreviousMainChunk *parse.ParsedChunkSource
 	//DefaultLimits          []Limit
 	//CustomPermissionTypeHandler CustomPermissionTypeHandler
 
@@ -434,7 +456,13 @@ func ParseModuleFromSource(src parse.ChunkSource, resource ResourceName, config
 		nodeId = node.Id
 	}
 
-	code, err := parse.ParseChunkSource(src)
+	var code *parse.ParsedChunkSource
+	if prev := config.PreviousMainChunk; prev != nil && prev.Name() == src.Name() {
+		code, err = parse.ParseChunkSourceIncrementally(prev, src, parse.GetSourceEdit(prev.Runes(), []rune(src.Code())))
+	} else {
+		code, err = parse.ParseChunkSource(src)
+	}
+
 	if err != nil && code == nil {
 		return nil, fmt.Errorf("failed to parse %s: %w", resource.ResourceName(), err)
 	}
@@ -0,0 +1,392 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/adrg/xdg"
+	afs "github.com/inoxlang/inox/internal/afs"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	DEPENDENCY_LOCK_FILENAME            = "inox-lock.json"
+	DEPENDENCY_LOCK_FORMAT_VERSION      = 1
+	DEPENDENCY_LOCK_FILE_PERM           = 0o644
+	MODULE_VERSION_LIST_FILENAME        = "versions.json"
+	MODULE_VERSION_SEPARATOR            = "@"
+	PERSISTENT_MODULE_CACHE_RELPATH     = "inox/modules"
+	PERSISTENT_MODULE_CACHE_FILE_SUFFIX = ".ix"
+)
+
+var (
+	ErrModuleNotInDependencyLock          = errors.New("module is not in the dependency lock file, run `inox deps update`")
+	ErrLockedModuleHashMismatch           = errors.New("the SHA-256 of the module does not match the one in the dependency lock file")
+	ErrNoModuleVersionSatisfiesConstraint = errors.New("no version of the module satisfies the constraint")
+	ErrUnsupportedDependencyLockVersion   = errors.New("unsupported dependency lock file version")
+
+	persistentModuleCacheDir     = filepath.Join(xdg.CacheHome, PERSISTENT_MODULE_CACHE_RELPATH)
+	persistentModuleCacheDirLock sync.Mutex
+)
+
+// A DependencyLockFile records the resolved URL, the version and the SHA-256 of every module (transitively) imported
+// from a URL. Entries are keyed by the import URL, that may contain a version constraint (e.g. https://example.com/lib@~1.2/main.ix).
+// The lock file of a project is stored in a DEPENDENCY_LOCK_FILENAME file, it is created and updated by `inox deps update`.
+type DependencyLockFile struct {
+	lock    sync.Mutex
+	entries map[string]LockedDependency
+}
+
+type LockedDependency struct {
+	ResolvedURL string `json:"resolved"`
+	Version     string `json:"version,omitempty"`
+	SHA256      string `json:"sha256"` //hex-encoded
+}
+
+type dependencyLockFileJSON struct {
+	Version int                         `json:"version"`
+	Modules map[string]LockedDependency `json:"modules"`
+}
+
+func NewDependencyLockFile() *DependencyLockFile {
+	return &DependencyLockFile{entries: map[string]LockedDependency{}}
+}
+
+func ParseDependencyLockFile(content []byte) (*DependencyLockFile, error) {
+	var data dependencyLockFileJSON
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("invalid dependency lock file: %w", err)
+	}
+
+	if data.Version != DEPENDENCY_LOCK_FORMAT_VERSION {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedDependencyLockVersion, data.Version)
+	}
+
+	lockFile := NewDependencyLockFile()
+	for importURL, dep := range data.Modules {
+		if dep.ResolvedURL == "" || len(dep.SHA256) != 2*sha256.Size {
+			return nil, fmt.Errorf("invalid dependency lock file: invalid entry for %s", importURL)
+		}
+		lockFile.entries[importURL] = dep
+	}
+	return lockFile, nil
+}
+
+// ReadDependencyLockFile reads and parses the lock file at path.
+func ReadDependencyLockFile(fls afs.Filesystem, path string) (*DependencyLockFile, error) {
+	content, err := ReadFileInFS(fls, path, -1)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDependencyLockFile(content)
+}
+
+// FindDependencyLockFile searches for a DEPENDENCY_LOCK_FILENAME file in dir and its ancestors,
+// dir should be absolute.
+func FindDependencyLockFile(fls afs.Filesystem, dir string) (path string, found bool) {
+	for {
+		path = fls.Join(dir, DEPENDENCY_LOCK_FILENAME)
+		if info, err := fls.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Marshal returns the indented JSON representation of the lock file, entries are sorted by import URL.
+func (f *DependencyLockFile) Marshal() []byte {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return utils.Must(json.MarshalIndent(dependencyLockFileJSON{
+		Version: DEPENDENCY_LOCK_FORMAT_VERSION,
+		Modules: f.entries,
+	}, "", "  "))
+}
+
+func (f *DependencyLockFile) Get(importURL URL) (LockedDependency, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	dep, ok := f.entries[string(importURL)]
+	return dep, ok
+}
+
+func (f *DependencyLockFile) set(importURL URL, dep LockedDependency) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.entries[string(importURL)] = dep
+}
+
+// ImportURLs returns the sorted import URLs of the entries.
+func (f *DependencyLockFile) ImportURLs() []URL {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	urls := make([]URL, 0, len(f.entries))
+	for importURL := range f.entries {
+		urls = append(urls, URL(importURL))
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i] < urls[j]
+	})
+	return urls
+}
+
+// Verify checks that the content of every locked module has the locked SHA-256, modules that are not
+// in the persistent cache are downloaded.
+func (f *DependencyLockFile) Verify(ctx *Context, insecure bool) error {
+	var errs []error
+
+	for _, importURL := range f.ImportURLs() {
+		dep, _ := f.Get(importURL)
+		if _, err := fetchLockedModule(ctx, importURL, dep, insecure, DEFAULT_FETCH_TIMEOUT); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fetchLockedModule returns the content of the locked version of a module, the persistent cache is searched first.
+func fetchLockedModule(ctx *Context, importURL URL, dep LockedDependency, insecure bool, timeout time.Duration) ([]byte, error) {
+	if content, ok := getModuleFromPersistentCache(dep.SHA256); ok {
+		return content, nil
+	}
+
+	content, err := fetchModuleFileFromURL(ctx, URL(dep.ResolvedURL), insecure, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if computeSHA256Hex(content) != dep.SHA256 {
+		return nil, &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: %s", importURL, ErrLockedModuleHashMismatch)}
+	}
+
+	putModuleInPersistentCache(content)
+	return content, nil
+}
+
+// checkLockedModuleContent checks that a module imported from a URL is in the lock file and that content has the locked SHA-256,
+// it is used when the content of the module is retrieved from a cache instead of being fetched by fetchURLModule.
+func checkLockedModuleContent(importURL URL, content []byte, lockFile *DependencyLockFile) error {
+	dep, ok := lockFile.Get(importURL)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrModuleNotInDependencyLock, importURL)
+	}
+
+	if computeSHA256Hex(content) != dep.SHA256 {
+		return &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: %s", importURL, ErrLockedModuleHashMismatch)}
+	}
+	return nil
+}
+
+// fetchURLModule returns the content of a module imported from a URL. If lockFile is not nil and update is false
+// the locked version of the module is fetched, the import fails if the module is not in the lock file. If update is
+// true the version constraint in the URL (if any) is resolved and the lock file is updated.
+func fetchURLModule(ctx *Context, importURL URL, lockFile *DependencyLockFile, update bool, insecure bool, timeout time.Duration) ([]byte, error) {
+	if lockFile != nil && !update {
+		dep, ok := lockFile.Get(importURL)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrModuleNotInDependencyLock, importURL)
+		}
+		return fetchLockedModule(ctx, importURL, dep, insecure, timeout)
+	}
+
+	resolvedURL, version, err := ResolveModuleURLVersion(ctx, importURL, insecure, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := fetchModuleFileFromURL(ctx, resolvedURL, insecure, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	putModuleInPersistentCache(content)
+
+	if lockFile != nil {
+		lockFile.set(importURL, LockedDependency{
+			ResolvedURL: string(resolvedURL),
+			Version:     version,
+			SHA256:      computeSHA256Hex(content),
+		})
+	}
+	return content, nil
+}
+
+// ResolveModuleURLVersion resolves the version constraint of a module URL. The constraint is specified in the first
+// path segment of the form <name>@<constraint> (e.g. lib@~1.2, lib@1.x, lib@1.2.3). Because '^' is not allowed in URL
+// literals caret constraints should be percent-encoded (lib@%5E1.2). Unless the constraint is an exact version the list
+// of the published versions is fetched from <name>/versions.json (JSON array of strings) in the same directory, the
+// highest version satisfying the constraint is selected. URLs without any constraint are returned unchanged.
+func ResolveModuleURLVersion(ctx *Context, u URL, insecure bool, timeout time.Duration) (resolved URL, version string, _ error) {
+	prefix, name, constraint, rest, ok := splitVersionedModuleURL(u)
+	if !ok {
+		return u, "", nil
+	}
+
+	makeURL := func(version string) URL {
+		return URL(prefix + name + MODULE_VERSION_SEPARATOR + version + rest)
+	}
+
+	if exactVersion, err := semver.StrictNewVersion(constraint); err == nil {
+		return makeURL(exactVersion.Original()), exactVersion.String(), nil
+	}
+
+	versionConstraint, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid version constraint in %s: %w", u, err)
+	}
+
+	listURL := URL(prefix + name + "/" + MODULE_VERSION_LIST_FILENAME)
+	listContent, err := fetchModuleFileFromURL(ctx, listURL, insecure, timeout)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get the versions of %s: %w", u, err)
+	}
+
+	var versions []string
+	if err := json.Unmarshal(listContent, &versions); err != nil {
+		return "", "", fmt.Errorf("invalid version list %s: %w", listURL, err)
+	}
+
+	var best *semver.Version
+	for _, v := range versions {
+		candidate, err := semver.NewVersion(v)
+		if err != nil || !versionConstraint.Check(candidate) {
+			continue
+		}
+		if best == nil || candidate.GreaterThan(best) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return "", "", fmt.Errorf("%w: %s", ErrNoModuleVersionSatisfiesConstraint, u)
+	}
+
+	return makeURL(best.Original()), best.String(), nil
+}
+
+// splitVersionedModuleURL splits a URL around its first path segment of the form <name>@<constraint>,
+// the returned constraint is unescaped. prefix ends with a slash and rest starts with a slash (or is empty).
+func splitVersionedModuleURL(u URL) (prefix, name, constraint, rest string, ok bool) {
+	s := string(u)
+	schemeEnd := strings.Index(s, "://")
+	if schemeEnd < 0 {
+		return
+	}
+
+	pathStart := strings.Index(s[schemeEnd+3:], "/")
+	if pathStart < 0 {
+		return
+	}
+	pathStart += schemeEnd + 3
+
+	segmentStart := pathStart + 1
+	for segmentStart < len(s) {
+		segmentEnd := strings.IndexAny(s[segmentStart:], "/?#")
+		if segmentEnd < 0 {
+			segmentEnd = len(s)
+		} else {
+			segmentEnd += segmentStart
+		}
+
+		segment := s[segmentStart:segmentEnd]
+		if sepIndex := strings.Index(segment, MODULE_VERSION_SEPARATOR); sepIndex > 0 && segmentEnd < len(s) && s[segmentEnd] == '/' {
+			unescaped, err := url.PathUnescape(segment[sepIndex+1:])
+			if err != nil || unescaped == "" {
+				return
+			}
+			return s[:segmentStart], segment[:sepIndex], unescaped, s[segmentEnd:], true
+		}
+
+		if segmentEnd >= len(s) || s[segmentEnd] != '/' {
+			return
+		}
+		segmentStart = segmentEnd + 1
+	}
+	return
+}
+
+// SetPersistentModuleCacheDir sets the directory of the content-addressed cache storing the modules
+// imported from URLs, an empty dir disables the cache. The default directory is $XDG_CACHE_HOME/inox/modules.
+func SetPersistentModuleCacheDir(dir string) {
+	persistentModuleCacheDirLock.Lock()
+	defer persistentModuleCacheDirLock.Unlock()
+	persistentModuleCacheDir = dir
+}
+
+func getPersistentModuleCacheDir() string {
+	persistentModuleCacheDirLock.Lock()
+	defer persistentModuleCacheDirLock.Unlock()
+	return persistentModuleCacheDir
+}
+
+// getModuleFromPersistentCache returns the content of a cached module, the content is only returned if its hash
+// is the expected one.
+func getModuleFromPersistentCache(sha256Hex string) (content []byte, found bool) {
+	dir := getPersistentModuleCacheDir()
+	if dir == "" || len(sha256Hex) != 2*sha256.Size {
+		return nil, false
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, sha256Hex+PERSISTENT_MODULE_CACHE_FILE_SUFFIX))
+	if err != nil || computeSHA256Hex(content) != sha256Hex {
+		return nil, false
+	}
+	return content, true
+}
+
+// putModuleInPersistentCache stores a module in the persistent cache, errors are ignored because the cache is optional.
+// The content is first written to a temporary file that is then renamed, so readers never see a partial file.
+func putModuleInPersistentCache(content []byte) {
+	dir := getPersistentModuleCacheDir()
+	if dir == "" {
+		return
+	}
+
+	path := filepath.Join(dir, computeSHA256Hex(content)+PERSISTENT_MODULE_CACHE_FILE_SUFFIX)
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+
+	tempFile, err := os.CreateTemp(dir, "module-*.tmp")
+	if err != nil {
+		return
+	}
+	tempPath := tempFile.Name()
+
+	_, writeErr := tempFile.Write(content)
+	closeErr := tempFile.Close()
+
+	if writeErr != nil || closeErr != nil || os.Rename(tempPath, path) != nil {
+		os.Remove(tempPath)
+	}
+}
+
+func computeSHA256Hex(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
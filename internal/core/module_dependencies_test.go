@@ -0,0 +1,263 @@
+package core
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core/permkind"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	//tests should not write to the cache of the user.
+	SetPersistentModuleCacheDir("")
+}
+
+func TestModuleDependencyLock(t *testing.T) {
+	//not parallel because the tests set the directory of the persistent cache.
+
+	const MAIN_MODULE_PATH = "/main.ix"
+
+	type testServer struct {
+		*httptest.Server
+		lock         sync.Mutex
+		files        map[string]string
+		requestCount atomic.Int32
+	}
+
+	setup := func(t *testing.T) (*testServer, *Context) {
+		SetPersistentModuleCacheDir(t.TempDir())
+		t.Cleanup(func() {
+			SetPersistentModuleCacheDir("")
+		})
+
+		server := &testServer{
+			files: map[string]string{
+				"/lib/versions.json":   `["1.1.0", "1.2.0", "1.2.5", "1.3.0", "invalid"]`,
+				"/lib@1.2.5/main.ix":   "manifest {}\nimport util ./util.ix {}",
+				"/lib@1.2.5/util.ix":   "manifest {}",
+				"/lib@1.3.0/main.ix":   "manifest {}",
+				"/other@2.0.0/main.ix": "manifest {}",
+			},
+		}
+
+		server.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server.requestCount.Add(1)
+
+			server.lock.Lock()
+			content, ok := server.files[r.URL.Path]
+			server.lock.Unlock()
+
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		}))
+		t.Cleanup(server.Close)
+
+		fls := newMemFilesystemRootWD()
+		util.WriteFile(fls, MAIN_MODULE_PATH, []byte("manifest {}\nimport lib "+server.URL+"/lib@~1.2/main.ix {}"), 0o600)
+
+		ctx := NewContexWithEmptyState(ContextConfig{
+			Permissions: []Permission{
+				CreateFsReadPerm(Path(MAIN_MODULE_PATH)),
+				HttpPermission{Kind_: permkind.Read, Entity: URLPattern(server.URL + "/...")},
+			},
+			Filesystem: fls,
+		}, nil)
+		t.Cleanup(ctx.CancelGracefully)
+
+		return server, ctx
+	}
+
+	//update parses the main module and returns the resulting lock file.
+	update := func(t *testing.T, ctx *Context) *DependencyLockFile {
+		lockFile := NewDependencyLockFile()
+		_, err := ParseLocalModule(MAIN_MODULE_PATH, ModuleParsingConfig{
+			Context:              ctx,
+			DependencyLock:       lockFile,
+			UpdateDependencyLock: true,
+			InsecureModImports:   true,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return lockFile
+	}
+
+	t.Run("update", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		lockFile := update(t, ctx)
+
+		assert.Equal(t, []URL{
+			URL(server.URL + "/lib@~1.2/main.ix"),
+			URL(server.URL + "/lib@~1.2/util.ix"),
+		}, lockFile.ImportURLs())
+
+		dep, _ := lockFile.Get(URL(server.URL + "/lib@~1.2/main.ix"))
+		assert.Equal(t, LockedDependency{
+			ResolvedURL: server.URL + "/lib@1.2.5/main.ix",
+			Version:     "1.2.5",
+			SHA256:      computeSHA256Hex([]byte(server.files["/lib@1.2.5/main.ix"])),
+		}, dep)
+
+		parsed, err := ParseDependencyLockFile(lockFile.Marshal())
+		if assert.NoError(t, err) {
+			assert.Equal(t, lockFile.entries, parsed.entries)
+		}
+	})
+
+	t.Run("the lock file should be found and the locked modules should be read from the persistent cache", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		lockFile := update(t, ctx)
+		util.WriteFile(ctx.GetFileSystem(), "/"+DEPENDENCY_LOCK_FILENAME, lockFile.Marshal(), 0o600)
+
+		//a newer version matching the constraint should not be used.
+		server.lock.Lock()
+		server.files["/lib/versions.json"] = `["1.2.5", "1.2.6"]`
+		server.lock.Unlock()
+
+		requestCount := server.requestCount.Load()
+
+		mod, err := ParseLocalModule(MAIN_MODULE_PATH, ModuleParsingConfig{Context: ctx, InsecureModImports: true})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, requestCount, server.requestCount.Load())
+		assert.Contains(t, mod.DirectlyImportedModules, server.URL+"/lib@~1.2/main.ix")
+
+		assert.NoError(t, lockFile.Verify(ctx, true))
+	})
+
+	t.Run("modules that are not in the persistent cache should be downloaded and checked", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		lockFile := update(t, ctx)
+		SetPersistentModuleCacheDir(t.TempDir())
+
+		_, err := ParseLocalModule(MAIN_MODULE_PATH, ModuleParsingConfig{Context: ctx, DependencyLock: lockFile, InsecureModImports: true})
+		assert.NoError(t, err)
+
+		SetPersistentModuleCacheDir(t.TempDir())
+		server.lock.Lock()
+		server.files["/lib@1.2.5/util.ix"] = "manifest {}\n"
+		server.lock.Unlock()
+
+		_, err = ParseLocalModule(MAIN_MODULE_PATH, ModuleParsingConfig{Context: ctx, DependencyLock: lockFile, InsecureModImports: true})
+		assert.ErrorContains(t, err, ErrLockedModuleHashMismatch.Error())
+		assert.ErrorContains(t, lockFile.Verify(ctx, true), ErrLockedModuleHashMismatch.Error())
+	})
+
+	t.Run("modules that are not in the lock file should not be imported", func(t *testing.T) {
+		_, ctx := setup(t)
+
+		_, err := ParseLocalModule(MAIN_MODULE_PATH, ModuleParsingConfig{
+			Context:            ctx,
+			DependencyLock:     NewDependencyLockFile(),
+			InsecureModImports: true,
+		})
+		assert.ErrorIs(t, err, ErrModuleNotInDependencyLock)
+	})
+
+	t.Run("the lock file should be checked when a module with a known hash is retrieved from a cache", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		update(t, ctx) //put the modules in the persistent cache
+
+		importURL := URL(server.URL + "/lib@~1.2/main.ix")
+		content := []byte(server.files["/lib@1.2.5/main.ix"])
+		hash := sha256.Sum256(content)
+
+		fetch := func(lockFile *DependencyLockFile) error {
+			_, err := fetchParseImportedModule(ctx, importURL, sourceFileDownloadConfig{
+				validation: string(hash[:]),
+				insecure:   true,
+				subModuleParsing: ModuleParsingConfig{
+					Context:            ctx,
+					DependencyLock:     lockFile,
+					InsecureModImports: true,
+				},
+			})
+			return err
+		}
+
+		requestCount := server.requestCount.Load()
+
+		//persistent cache
+		assert.ErrorIs(t, fetch(NewDependencyLockFile()), ErrModuleNotInDependencyLock)
+		assert.Equal(t, requestCount, server.requestCount.Load())
+
+		//in-memory cache, the imported module is fetched because it is not in a cache.
+		assert.NoError(t, fetch(nil))
+		requestCount = server.requestCount.Load()
+
+		assert.ErrorIs(t, fetch(NewDependencyLockFile()), ErrModuleNotInDependencyLock)
+
+		lockFile := NewDependencyLockFile()
+		lockFile.set(importURL, LockedDependency{
+			ResolvedURL: server.URL + "/lib@1.2.5/main.ix",
+			Version:     "1.2.5",
+			SHA256:      computeSHA256Hex([]byte("manifest {}")),
+		})
+		assert.ErrorContains(t, fetch(lockFile), ErrLockedModuleHashMismatch.Error())
+
+		assert.Equal(t, requestCount, server.requestCount.Load())
+	})
+
+	t.Run("no version satisfying the constraint", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		_, _, err := ResolveModuleURLVersion(ctx, URL(server.URL+"/lib@~2.0/main.ix"), true, DEFAULT_FETCH_TIMEOUT)
+		assert.ErrorIs(t, err, ErrNoModuleVersionSatisfiesConstraint)
+	})
+
+	t.Run("percent-encoded constraint", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		resolved, version, err := ResolveModuleURLVersion(ctx, URL(server.URL+"/lib@%5E1.1/main.ix"), true, DEFAULT_FETCH_TIMEOUT)
+		if assert.NoError(t, err) {
+			assert.Equal(t, URL(server.URL+"/lib@1.3.0/main.ix"), resolved)
+			assert.Equal(t, "1.3.0", version)
+		}
+	})
+
+	t.Run("exact version", func(t *testing.T) {
+		server, ctx := setup(t)
+
+		resolved, version, err := ResolveModuleURLVersion(ctx, URL(server.URL+"/other@2.0.0/main.ix"), true, DEFAULT_FETCH_TIMEOUT)
+		if assert.NoError(t, err) {
+			assert.Equal(t, URL(server.URL+"/other@2.0.0/main.ix"), resolved)
+			assert.Equal(t, "2.0.0", version)
+			assert.Zero(t, server.requestCount.Load())
+		}
+	})
+}
+
+func TestSplitVersionedModuleURL(t *testing.T) {
+	prefix, name, constraint, rest, ok := splitVersionedModuleURL("https://example.com/a/lib@~1.2/b/main.ix")
+	if assert.True(t, ok) {
+		assert.Equal(t, "https://example.com/a/", prefix)
+		assert.Equal(t, "lib", name)
+		assert.Equal(t, "~1.2", constraint)
+		assert.Equal(t, "/b/main.ix", rest)
+	}
+
+	_, _, _, _, ok = splitVersionedModuleURL("https://example.com/lib/main.ix")
+	assert.False(t, ok)
+
+	//the last segment is a file name.
+	_, _, _, _, ok = splitVersionedModuleURL("https://example.com/lib/main@1.ix")
+	assert.False(t, ok)
+
+	_, _, _, _, ok = splitVersionedModuleURL("https://user@example.com/lib/main.ix")
+	assert.False(t, ok)
+}
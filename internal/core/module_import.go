@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -257,6 +258,12 @@ func ImportModule(config ImportConfig) (*LThread, error) {
 
 		IsTestingEnabled: parentState.TestingState.IsTestingEnabled && parentState.TestingState.IsImportTestingEnabled,
 		TestFilters:      parentState.TestingState.Filters,
+
+		IsBenchmarkingEnabled: parentState.TestingState.IsBenchmarkingEnabled,
+		BenchmarkBaseline:     parentState.TestingState.BenchmarkBaseline,
+
+		UpdateGoldenFiles: parentState.TestingState.UpdateGoldenFiles,
+		GoldenFilesystem:  parentState.TestingState.GoldenFilesystem,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("import: %s", err.Error())
@@ -510,8 +517,6 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 		timeout = DEFAULT_FETCH_TIMEOUT
 	}
 
-	deadline := time.Now().Add(timeout)
-
 	var b []byte
 	var modString string
 	var ok bool
@@ -519,6 +524,13 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 	var isResourceURL bool
 	fls := ctx.GetFileSystem()
 
+	lockFile := config.subModuleParsing.DependencyLock
+	updateLockFile := config.subModuleParsing.UpdateDependencyLock
+	importURL, isURLImport := resolvedImportedSrc.(URL)
+
+	//the lock file is updated by fetchURLModule so the caches are not used.
+	ignoreCaches := isURLImport && lockFile != nil && updateLockFile
+
 	moduleCacheLock.Lock()
 	unlock := true
 	defer func() {
@@ -527,7 +539,7 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 		}
 	}()
 
-	if modString, ok = moduleCache[config.validation]; !ok || config.validation == "" {
+	if modString, ok = moduleCache[config.validation]; !ok || config.validation == "" || ignoreCaches {
 		switch srcVal := resolvedImportedSrc.(type) {
 		case Path:
 			absSrc, err := fls.Absolute(string(srcVal))
@@ -557,59 +569,25 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 				absScriptDir = string(srcVal.Host()) + string(pth)[:lastSlashIndex+1]
 			}
 
-			transport := &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   5 * time.Second,
-					KeepAlive: 5 * time.Second,
-					Deadline:  deadline.Add(-time.Second),
-				}).DialContext,
-				ForceAttemptHTTP2:     true,
-				MaxIdleConns:          1,
-				IdleConnTimeout:       1 * time.Second,
-				TLSHandshakeTimeout:   5 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-			}
-
-			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: config.insecure}
-			client := http.Client{
-				Timeout:   timeout,
-				Transport: transport,
-			}
-
-			req, err := http.NewRequest("GET", string(srcVal), nil)
-			req.Header.Add("Accept", INOX_MIMETYPE)
-
-			reqCtx, cancel := context.WithDeadline(ctx, deadline)
-			defer cancel()
-			req = req.WithContext(reqCtx)
-
-			if err != nil {
-				return nil, err
+			if config.validation != "" && !ignoreCaches {
+				//the hash is known so the module may be in the persistent cache.
+				if content, ok := getModuleFromPersistentCache(hex.EncodeToString([]byte(config.validation))); ok {
+					if lockFile != nil {
+						if err := checkLockedModuleContent(importURL, content, lockFile); err != nil {
+							return nil, err
+						}
+					}
+					b = content
+					break
+				}
 			}
 
-			resp, err := client.Do(req)
-
+			content, err := fetchURLModule(ctx, srcVal,
+				config.subModuleParsing.DependencyLock, config.subModuleParsing.UpdateDependencyLock, config.insecure, timeout)
 			if err != nil {
 				return nil, err
 			}
-
-			//TODO: sanitize .Status, Content-Type, etc before writing them to the terminal
-			var bodyErr error
-			b, bodyErr = io.ReadAll(resp.Body)
-
-			if resp.StatusCode != 200 {
-				return nil, &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: status %d: %s", srcVal, resp.StatusCode, resp.Status)}
-			}
-
-			// ctype := resp.Header.Get("Content-Type")
-			// if ctype != INOX_MIMETYPE {
-			// 	return nil, fmt.Errorf("failed to get %s: content-type is '%s'", importURL, ctype)
-			// }
-
-			if bodyErr != nil {
-				return nil, &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: failed to read body: %s", srcVal, err.Error())}
-			}
+			b = content
 		}
 
 		array := sha256.Sum256(b)
@@ -623,6 +601,10 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 		moduleCache[string(hash)] = modString
 
 		//TODO: limit cache size
+	} else if isURLImport && lockFile != nil {
+		if err := checkLockedModuleContent(importURL, []byte(modString), lockFile); err != nil {
+			return nil, err
+		}
 	}
 
 	unlock = false
@@ -639,6 +621,66 @@ func fetchParseImportedModule(ctx *Context, resolvedImportedSrc ResourceName, co
 	return ParseModuleFromSource(source, resolvedImportedSrc, config.subModuleParsing)
 }
 
+// fetchModuleFileFromURL makes a GET request to u and returns the body of the response.
+func fetchModuleFileFromURL(ctx *Context, u URL, insecure bool, timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 5 * time.Second,
+			Deadline:  deadline.Add(-time.Second),
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          1,
+		IdleConnTimeout:       1 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecure}
+	client := http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	req, err := http.NewRequest("GET", string(u), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", INOX_MIMETYPE)
+
+	reqCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	req = req.WithContext(reqCtx)
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	//TODO: sanitize .Status, Content-Type, etc before writing them to the terminal
+	b, bodyErr := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: status %d: %s", u, resp.StatusCode, resp.Status)}
+	}
+
+	// ctype := resp.Header.Get("Content-Type")
+	// if ctype != INOX_MIMETYPE {
+	// 	return nil, fmt.Errorf("failed to get %s: content-type is '%s'", importURL, ctype)
+	// }
+
+	if bodyErr != nil {
+		return nil, &ModuleRetrievalError{message: fmt.Sprintf("failed to get %s: failed to read body: %s", u, bodyErr.Error())}
+	}
+
+	return b, nil
+}
+
 type ModuleRetrievalError struct {
 	message string
 }
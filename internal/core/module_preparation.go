@@ -43,6 +43,18 @@ type ModulePreparationArgs struct {
 	//if not nil the module is not parsed and this value is used.
 	CachedModule *Module
 
+	//if not nil and .CachedModule is nil the module is parsed incrementally from this chunk,
+	//see ModuleParsingConfig.PreviousMainChunk.
+	PreviousMainChunk *parse.ParsedChunkSource
+
+	//If true the state of the static check and of the symbolic evaluation is saved before each top level statement,
+	//the resulting IncrementalCheckData is stored in the .IncrementalCheckData field of the state.
+	IncrementalChecking bool
+
+	//If set and if .IncrementalChecking is true the checking of the top level statements that are shared with the
+	//previously prepared version of the module is reused. PreviousCheckData should not be used after the call.
+	PreviousCheckData *IncrementalCheckData
+
 	// enable data extraction mode, this mode allows some errors.
 	// this mode is intended to be used by the LSP server.
 	DataExtractionMode bool
@@ -54,6 +66,13 @@ type ModulePreparationArgs struct {
 	EnableTesting bool
 	TestFilters   TestFilters
 
+	// If true the benchmark statements are executed, this has no effect if EnableTesting is false.
+	EnableBenchmarking bool
+	BenchmarkBaseline  *BenchmarkBaseline //can be nil
+
+	// If true the golden file assertions (re)write the golden files, this has no effect if EnableTesting is false.
+	UpdateGoldenFiles bool
+
 	// If set this function is called just before the context creation,
 	// the preparation is aborted if an error is returned.
 	// The returned limits are used instead of the manifest limits.
@@ -98,6 +117,10 @@ type ModulePreparationArgs struct {
 	ScriptContextFileSystem afs.Filesystem
 
 	AdditionalGlobalsTestOnly map[string]Value
+
+	//If set the module is compiled (or its bytecode is loaded from the cache) at the end of the preparation,
+	//the bytecode is stored in the .Bytecode field of the state.
+	BytecodeCache *BytecodeCache
 }
 
 // PrepareLocalModule parses & checks a module located in the filesystem and initializes its state.
@@ -145,6 +168,7 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 		module, parsingErr = ParseLocalModule(args.Fpath, ModuleParsingConfig{
 			Context:                             args.ParsingCompilationContext,
 			RecoverFromNonExistingIncludedFiles: args.DataExtractionMode,
+			PreviousMainChunk:                   args.PreviousMainChunk,
 		})
 		preparationLogger.Debug().Dur("parsing", time.Since(start)).Send()
 
@@ -155,6 +179,10 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 			finalErr = parsingErr
 			return
 		}
+
+		if args.IncrementalChecking && args.PreviousCheckData != nil {
+			adoptUnchangedDependencies(mod, args.PreviousCheckData.module)
+		}
 	}
 
 	//create context and state
@@ -313,8 +341,16 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 	state.Manifest = manifest
 	state.PrenitStaticCheckErrors = preinitStaticCheckErrors
 	state.MainPreinitError = preinitErr
+	state.BytecodeCache = args.BytecodeCache
 	state.TestingState.IsTestingEnabled = args.EnableTesting
 	state.TestingState.Filters = args.TestFilters
+	state.TestingState.IsBenchmarkingEnabled = args.EnableBenchmarking
+	state.TestingState.BenchmarkBaseline = args.BenchmarkBaseline
+	state.TestingState.UpdateGoldenFiles = args.UpdateGoldenFiles
+	if args.EnableTesting {
+		//golden files are stored in the filesystem containing the module.
+		state.TestingState.GoldenFilesystem = args.ParsingCompilationContext.GetFileSystem()
+	}
 
 	if args.UseParentStateAsMainState {
 		if parentState == nil {
@@ -366,6 +402,7 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 			ResolutionData: config.ResolutionData,
 			FullAccess:     args.FullAccessToDatabases,
 			Project:        project,
+			Format:         config.Format,
 		})
 		if err != nil {
 			err = fmt.Errorf("failed to open the '%s' database: %w", config.Name, err)
@@ -514,22 +551,45 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 
 	staticCheckStart := time.Now()
 
+	var additionalGlobalConsts []string
+	if modArgsError != nil {
+		additionalGlobalConsts = []string{MOD_ARGS_VARNAME}
+	}
+	patterns := state.Ctx.GetNamedPatterns()
+	patternNamespaces := state.Ctx.GetPatternNamespaces()
+
+	//determine the number of top level statements whose checking can be reused.
+	var (
+		checkInputsKey       string
+		reusedStatementCount int
+		prevStaticCheckData  *StaticCheckData
+		prevSymbolicData     *symbolic.Data
+	)
+
+	if args.IncrementalChecking {
+		checkInputsKey = getIncrementalCheckInputsKey(state.Globals, additionalGlobalConsts, patterns, patternNamespaces)
+		reusedStatementCount = getReusableTopLevelStatementCount(args.PreviousCheckData, mod, checkInputsKey)
+		if reusedStatementCount > 0 {
+			prevStaticCheckData = args.PreviousCheckData.staticCheckData
+			prevSymbolicData = args.PreviousCheckData.symbolicData
+		}
+	}
+
 	staticCheckData, staticCheckErr := StaticCheck(StaticCheckInput{
-		State:   state,
-		Module:  mod,
-		Node:    mod.MainChunk.Node,
-		Chunk:   mod.MainChunk,
-		Globals: state.Globals,
-		AdditionalGlobalConsts: func() []string {
-			if modArgsError != nil {
-				return []string{MOD_ARGS_VARNAME}
-			}
-			return nil
-		}(),
-		Patterns:          state.Ctx.GetNamedPatterns(),
-		PatternNamespaces: state.Ctx.GetPatternNamespaces(),
+		State:                  state,
+		Module:                 mod,
+		Node:                   mod.MainChunk.Node,
+		Chunk:                  mod.MainChunk,
+		Globals:                state.Globals,
+		AdditionalGlobalConsts: additionalGlobalConsts,
+		Patterns:               patterns,
+		PatternNamespaces:      patternNamespaces,
+
+		SaveTopLevelCheckpoints:      args.IncrementalChecking,
+		PreviousData:                 prevStaticCheckData,
+		ReusedTopLevelStatementCount: reusedStatementCount,
 	})
-	preparationLogger.Debug().Dur("static-check-dur", time.Since(staticCheckStart)).Send()
+	preparationLogger.Debug().Dur("static-check-dur", time.Since(staticCheckStart)).Int("reused-top-level-stmts", reusedStatementCount).Send()
 
 	state.StaticCheckData = staticCheckData
 
@@ -600,6 +660,10 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 		ProjectFilesystem: utils.If[billy.Filesystem](state.Project != nil, ctx.GetFileSystem(), nil),
 
 		Context: symbolicCtx,
+
+		SaveTopLevelCheckpoints:      args.IncrementalChecking,
+		PreviousData:                 prevSymbolicData,
+		ReusedTopLevelStatementCount: reusedStatementCount,
 	})
 	preparationLogger.Debug().Dur("symb-check-dur", time.Since(symbolicCheckStart)).Send()
 
@@ -607,6 +671,16 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 
 	if symbolicData != nil {
 		state.SymbolicData.AddData(symbolicData)
+
+		if args.IncrementalChecking && staticCheckData != nil {
+			state.IncrementalCheckData = &IncrementalCheckData{
+				module:               mod,
+				inputsKey:            checkInputsKey,
+				staticCheckData:      staticCheckData,
+				symbolicData:         symbolicData,
+				reusedStatementCount: reusedStatementCount,
+			}
+		}
 	}
 
 	if parsingErr != nil { //priority to parsing error
@@ -629,6 +703,28 @@ func PrepareLocalModule(args ModulePreparationArgs) (state *GlobalState, mod *Mo
 		}
 	}
 
+	if finalErr == nil && args.BytecodeCache != nil {
+		compilationStart := time.Now()
+
+		bytecode, cached, err := args.BytecodeCache.GetOrCompile(CompilationInput{
+			Mod:                    mod,
+			Globals:                state.Globals.permanent,
+			SymbolicData:           state.SymbolicData.Data,
+			StaticCheckData:        state.StaticCheckData,
+			Context:                args.ParsingCompilationContext,
+			IsTestingEnabled:       state.TestingState.IsTestingEnabled,
+			IsImportTestingEnabled: state.TestingState.IsImportTestingEnabled,
+			IsBenchmarkingEnabled:  state.TestingState.IsBenchmarkingEnabled,
+		})
+		preparationLogger.Debug().Dur("compilation-dur", time.Since(compilationStart)).Bool("cached-bytecode", cached).Send()
+
+		if err != nil {
+			finalErr = err
+		} else {
+			state.Bytecode = bytecode
+		}
+	}
+
 	return state, mod, manifest, finalErr
 }
 
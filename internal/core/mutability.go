@@ -240,6 +240,10 @@ func (pattern *OptionalPattern) IsMutable() bool {
 	return false
 }
 
+func (pattern *GenericPattern) IsMutable() bool {
+	return false
+}
+
 func (pattern *FunctionPattern) IsMutable() bool {
 	return false
 }
@@ -320,6 +324,10 @@ func (patt *MutationPattern) IsMutable() bool {
 	return false
 }
 
+func (patt *ErrorPattern) IsMutable() bool {
+	return false
+}
+
 func (patt *ParserBasedPseudoPattern) IsMutable() bool {
 	return false
 }
@@ -503,6 +511,10 @@ func (c *TestCase) IsMutable() bool {
 	return false
 }
 
+func (b *Benchmark) IsMutable() bool {
+	return false
+}
+
 func (c *TestCaseResult) IsMutable() bool {
 	return false
 }
@@ -573,6 +585,14 @@ func (*RingBuffer) IsMutable() bool {
 	return true
 }
 
+func (*Channel) IsMutable() bool {
+	return true
+}
+
+func (*ChannelIterator) IsMutable() bool {
+	return true
+}
+
 func (*DataChunk) IsMutable() bool {
 	return true
 }
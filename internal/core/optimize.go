@@ -1,16 +1,842 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/inoxlang/inox/internal/parse"
 )
 
-func optimizeBytecode(b *Bytecode, tracer io.Writer) {
-	deduplicateConstants(b, tracer)
+// A BytecodeOptimizationPass is a transformation of the bytecode that does not change the behavior of the program.
+type BytecodeOptimizationPass string
+
+const (
+	CONSTANT_FOLDING_PASS       BytecodeOptimizationPass = "constant-folding"
+	PEEPHOLE_PASS               BytecodeOptimizationPass = "peephole"
+	JUMP_THREADING_PASS         BytecodeOptimizationPass = "jump-threading"
+	DEAD_CODE_ELIMINATION_PASS  BytecodeOptimizationPass = "dead-code-elimination"
+	LOCAL_SLOT_REUSE_PASS       BytecodeOptimizationPass = "local-slot-reuse"
+	CONSTANT_DEDUPLICATION_PASS BytecodeOptimizationPass = "constant-deduplication"
+)
+
+var (
+	// BYTECODE_OPTIMIZATION_PASSES lists the optimization passes in their order of execution.
+	BYTECODE_OPTIMIZATION_PASSES = []BytecodeOptimizationPass{
+		CONSTANT_FOLDING_PASS,
+		PEEPHOLE_PASS,
+		JUMP_THREADING_PASS,
+		DEAD_CODE_ELIMINATION_PASS,
+		LOCAL_SLOT_REUSE_PASS,
+		CONSTANT_DEDUPLICATION_PASS,
+	}
+
+	errNotOptimizableFunction = errors.New("function cannot be optimized")
+)
+
+// ParseBytecodeOptimizationPassList parses a comma-separated list of optimization pass names (e.g. 'peephole,constant-folding').
+func ParseBytecodeOptimizationPassList(s string) ([]BytecodeOptimizationPass, error) {
+	var passes []BytecodeOptimizationPass
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		pass := BytecodeOptimizationPass(name)
+		if !slices.Contains(BYTECODE_OPTIMIZATION_PASSES, pass) {
+			return nil, fmt.Errorf("unknown bytecode optimization pass '%s'", name)
+		}
+		passes = append(passes, pass)
+	}
+	return passes, nil
+}
+
+// optimizeBytecode applies the optimization passes that are not disabled to the main function and the compiled
+// functions of a bytecode. Bytecode that has already been optimized is left unchanged. The number of changes made
+// by each pass is written to tracer if it is not nil.
+func optimizeBytecode(b *Bytecode, disabledPasses []BytecodeOptimizationPass, tracer io.Writer) {
+	if b.optimized {
+		return
+	}
+	b.optimized = true
+
+	isEnabled := func(pass BytecodeOptimizationPass) bool {
+		return !slices.Contains(disabledPasses, pass)
+	}
+
+	//functions of the bytecode and number of reserved local slots (parameters and captured locals).
+	functions := []*CompiledFunction{b.main}
+	reservedLocalCounts := map[*CompiledFunction]int{b.main: 0}
+
+	for _, c := range b.constants {
+		fn, ok := c.(*InoxFunction)
+		if !ok || fn.compiledFunction == nil || fn.compiledFunction.Bytecode != b {
+			continue
+		}
+		compiled := fn.compiledFunction
+		if _, ok := reservedLocalCounts[compiled]; ok {
+			continue
+		}
+
+		reservedLocalCount := compiled.LocalCount
+		if fnExpr, ok := fn.Node.(*parse.FunctionExpression); ok {
+			reservedLocalCount = compiled.ParamCount + len(fnExpr.CaptureList)
+		}
+
+		functions = append(functions, compiled)
+		reservedLocalCounts[compiled] = reservedLocalCount
+	}
+
+	changeCounts := map[BytecodeOptimizationPass]int{}
+
+	for _, fn := range functions {
+		optimizer, err := newFunctionOptimizer(b, fn, reservedLocalCounts[fn])
+		if err != nil {
+			continue
+		}
+
+		for _, pass := range BYTECODE_OPTIMIZATION_PASSES {
+			if !isEnabled(pass) {
+				continue
+			}
+
+			switch pass {
+			case CONSTANT_FOLDING_PASS:
+				changeCounts[pass] += optimizer.foldConstants()
+			case PEEPHOLE_PASS:
+				changeCounts[pass] += optimizer.applyPeepholeRules()
+			case JUMP_THREADING_PASS:
+				changeCounts[pass] += optimizer.threadJumps()
+			case DEAD_CODE_ELIMINATION_PASS:
+				changeCounts[pass] += optimizer.eliminateDeadCode()
+			case LOCAL_SLOT_REUSE_PASS:
+				changeCounts[pass] += optimizer.reuseLocalSlots()
+			}
+			optimizer.compact()
+		}
+
+		optimizer.apply()
+	}
+
+	if isEnabled(CONSTANT_DEDUPLICATION_PASS) {
+		changeCounts[CONSTANT_DEDUPLICATION_PASS] += deduplicateConstants(b, tracer)
+	}
+
+	if tracer != nil {
+		for _, pass := range BYTECODE_OPTIMIZATION_PASSES {
+			if isEnabled(pass) {
+				fmt.Fprintf(tracer, "optimization pass %s: %d change(s)\n", pass, changeCounts[pass])
+			} else {
+				fmt.Fprintf(tracer, "optimization pass %s: disabled\n", pass)
+			}
+		}
+	}
+}
+
+// An optimizedInstruction is a decoded instruction, the operand of jumps is replaced by the index of the targeted instruction.
+type optimizedInstruction struct {
+	op           Opcode
+	operands     []int
+	target       int //index of the targeted instruction if the instruction is a jump, -1 otherwise
+	sourcePos    instructionSourcePosition
+	hasSourcePos bool
+	statement    parse.Node //set if the instruction is the first instruction of a statement
+	removed      bool
+}
+
+func (instr *optimizedInstruction) isJump() bool {
+	return instr.target >= 0
+}
+
+// A functionOptimizer decodes the instructions of a compiled function, transforms them and then re-encodes them.
+type functionOptimizer struct {
+	bytecode           *Bytecode
+	fn                 *CompiledFunction
+	instructions       []*optimizedInstruction
+	reservedLocalCount int
+}
+
+func newFunctionOptimizer(b *Bytecode, fn *CompiledFunction, reservedLocalCount int) (*functionOptimizer, error) {
+	o := &functionOptimizer{
+		bytecode:           b,
+		fn:                 fn,
+		reservedLocalCount: reservedLocalCount,
+	}
+
+	posToIndex := map[int]int{}
+	instructions := fn.Instructions
+
+	for pos := 0; pos < len(instructions); {
+		op := instructions[pos]
+		if int(op) >= len(OpcodeOperands) {
+			return nil, errNotOptimizableFunction
+		}
+		widths := OpcodeOperands[op]
+		size := 1
+		for _, w := range widths {
+			size += w
+		}
+		if pos+size > len(instructions) {
+			return nil, errNotOptimizableFunction
+		}
+
+		operands, _ := ReadOperands(widths, instructions[pos+1:])
+		sourcePos, hasSourcePos := fn.SourceMap[pos]
+
+		posToIndex[pos] = len(o.instructions)
+		o.instructions = append(o.instructions, &optimizedInstruction{
+			op:           op,
+			operands:     operands,
+			target:       -1,
+			sourcePos:    sourcePos,
+			hasSourcePos: hasSourcePos,
+			statement:    fn.StatementStarts[pos],
+		})
+		pos += size
+	}
+	posToIndex[len(instructions)] = len(o.instructions)
+
+	for _, instr := range o.instructions {
+		if !isJumpOpcode(instr.op) {
+			continue
+		}
+		target, ok := posToIndex[instr.operands[0]]
+		if !ok {
+			return nil, errNotOptimizableFunction
+		}
+		instr.target = target
+	}
+
+	return o, nil
+}
+
+func isJumpOpcode(op Opcode) bool {
+	switch op {
+	case OpJump, OpJumpIfFalse, OpAndJump, OpOrJump, OpPopJumpIfTestDisabled:
+		return true
+	}
+	return false
+}
+
+// jumpTargets returns a slice indicating for each instruction whether it is targeted by a jump,
+// targeted instructions start a basic block and cannot be merged with the previous instructions.
+func (o *functionOptimizer) jumpTargets() []bool {
+	targets := make([]bool, len(o.instructions)+1)
+	for _, instr := range o.instructions {
+		if instr.isJump() && !instr.removed {
+			targets[instr.target] = true
+			if instr.op == OpPopJumpIfTestDisabled {
+				//the VM resumes the execution after the targeted instruction.
+				targets[min(instr.target+1, len(o.instructions))] = true
+			}
+		}
+	}
+	return targets
+}
+
+// compact deletes removed instructions, jumps to a removed instruction are updated to target the next instruction.
+// The statement of a removed instruction is moved to the next instruction if it has none.
+func (o *functionOptimizer) compact() {
+	newIndexes := make([]int, len(o.instructions)+1)
+	var kept []*optimizedInstruction
+	var pendingStatement parse.Node
+
+	for i, instr := range o.instructions {
+		newIndexes[i] = len(kept)
+		if instr.removed {
+			if pendingStatement == nil {
+				pendingStatement = instr.statement
+			}
+			continue
+		}
+		if instr.statement == nil {
+			instr.statement = pendingStatement
+		}
+		pendingStatement = nil
+		kept = append(kept, instr)
+	}
+	newIndexes[len(o.instructions)] = len(kept)
+
+	for _, instr := range kept {
+		if instr.isJump() {
+			instr.target = newIndexes[instr.target]
+		}
+	}
+	o.instructions = kept
+}
+
+// apply encodes the instructions and updates the compiled function.
+func (o *functionOptimizer) apply() {
+	o.compact()
+
+	positions := make([]int, len(o.instructions)+1)
+	pos := 0
+	for i, instr := range o.instructions {
+		positions[i] = pos
+		pos += 1
+		for _, w := range OpcodeOperands[instr.op] {
+			pos += w
+		}
+	}
+	positions[len(o.instructions)] = pos
+
+	if pos > math.MaxUint16 {
+		//jump addresses would not fit in the operands, this should not happen because the optimized function
+		//is not larger than the original one.
+		return
+	}
+
+	instructions := make([]byte, 0, pos)
+	sourceMap := make(map[int]instructionSourcePosition, len(o.instructions))
+	statementStarts := make(map[int]parse.Node)
+
+	for i, instr := range o.instructions {
+		if instr.isJump() {
+			instr.operands[0] = positions[instr.target]
+		}
+		instructions = append(instructions, MakeInstruction(instr.op, instr.operands...)...)
+
+		if instr.hasSourcePos {
+			sourceMap[positions[i]] = instr.sourcePos
+		}
+		if instr.statement != nil {
+			statementStarts[positions[i]] = instr.statement
+		}
+	}
+
+	o.fn.Instructions = instructions
+	o.fn.SourceMap = sourceMap
+	o.fn.StatementStarts = statementStarts
+}
+
+// previousInstructions returns the indexes of the count non-removed instructions preceding the instruction at index,
+// ok is false if there are not enough instructions or if one of them (except the first one) is a jump target.
+func (o *functionOptimizer) previousInstructions(index int, count int, targets []bool) (indexes []int, ok bool) {
+	if targets[index] {
+		return nil, false
+	}
+	indexes = make([]int, count)
+
+	i := index - 1
+	for n := count - 1; n >= 0; n-- {
+		for i >= 0 && o.instructions[i].removed {
+			i--
+		}
+		if i < 0 || (n != 0 && targets[i]) {
+			return nil, false
+		}
+		indexes[n] = i
+		i--
+	}
+	return indexes, true
+}
+
+// pushedConstant returns the value pushed by the instruction if it pushes a constant.
+func (o *functionOptimizer) pushedConstant(instr *optimizedInstruction) (Value, bool) {
+	switch instr.op {
+	case OpPushConstant:
+		return o.bytecode.constants[instr.operands[0]], true
+	case OpPushTrue:
+		return True, true
+	case OpPushFalse:
+		return False, true
+	case OpPushNil:
+		return Nil, true
+	}
+	return nil, false
+}
+
+// setPushedConstant turns the instruction into an instruction pushing value, it returns false if the constant pool is full.
+func (o *functionOptimizer) setPushedConstant(instr *optimizedInstruction, value Value) bool {
+	switch value {
+	case True:
+		instr.op, instr.operands = OpPushTrue, nil
+		return true
+	case False:
+		instr.op, instr.operands = OpPushFalse, nil
+		return true
+	}
+
+	if len(o.bytecode.constants) >= math.MaxUint16 {
+		return false
+	}
+	o.bytecode.constants = append(o.bytecode.constants, value)
+	instr.op, instr.operands = OpPushConstant, []int{len(o.bytecode.constants) - 1}
+	return true
+}
+
+// foldConstants replaces operations whose operands are all constants by their result, operations that would fail
+// at runtime are not folded. The pass is repeated until no operation can be folded.
+func (o *functionOptimizer) foldConstants() (folded int) {
+	for {
+		foldedInIteration := 0
+		targets := o.jumpTargets()
+
+		for i, instr := range o.instructions {
+			operandCount := 0
+
+			switch instr.op {
+			case OpIntBin, OpFloatBin, OpNumBin, OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpStrConcat:
+				operandCount = 2
+			case OpMinus, OpBooleanNot:
+				operandCount = 1
+			case OpConcatStrLikes:
+				operandCount = instr.operands[0]
+			default:
+				continue
+			}
+
+			if operandCount == 0 {
+				continue
+			}
+
+			operandIndexes, ok := o.previousInstructions(i, operandCount, targets)
+			if !ok {
+				continue
+			}
+
+			operands := make([]Value, operandCount)
+			for j, index := range operandIndexes {
+				operands[j], ok = o.pushedConstant(o.instructions[index])
+				if !ok {
+					break
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			result, ok := o.evalConstantOperation(instr, operands)
+			if !ok || !o.setPushedConstant(instr, result) {
+				continue
+			}
+
+			for _, index := range operandIndexes {
+				o.instructions[index].removed = true
+			}
+			foldedInIteration++
+		}
+
+		if foldedInIteration == 0 {
+			return
+		}
+		folded += foldedInIteration
+		o.compact()
+	}
+}
+
+// evalConstantOperation evaluates an operation the same way the VM does, ok is false if the operation
+// cannot be evaluated at compile time or if its evaluation fails.
+func (o *functionOptimizer) evalConstantOperation(instr *optimizedInstruction, operands []Value) (result Value, ok bool) {
+	var err error
+
+	switch instr.op {
+	case OpIntBin, OpFloatBin, OpNumBin:
+		operator := parse.BinaryOperator(instr.operands[0])
+
+		switch left := operands[0].(type) {
+		case Int:
+			right, ok := operands[1].(Int)
+			if !ok || instr.op == OpFloatBin {
+				return nil, false
+			}
+			result, err = evalIntBinaryOperation(operator, left, right)
+		case Float:
+			right, ok := operands[1].(Float)
+			if !ok || instr.op == OpIntBin {
+				return nil, false
+			}
+			result, err = evalFloatBinaryOperation(operator, left, right)
+		default:
+			return nil, false
+		}
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		operator := map[Opcode]parse.BinaryOperator{
+			OpLess:         parse.LessThan,
+			OpLessEqual:    parse.LessOrEqual,
+			OpGreater:      parse.GreaterThan,
+			OpGreaterEqual: parse.GreaterOrEqual,
+		}[instr.op]
+
+		switch left := operands[0].(type) {
+		case Int:
+			right, ok := operands[1].(Int)
+			if !ok {
+				return nil, false
+			}
+			result, err = evalIntBinaryOperation(operator, left, right)
+		case Float:
+			right, ok := operands[1].(Float)
+			if !ok {
+				return nil, false
+			}
+			result, err = evalFloatBinaryOperation(operator, left, right)
+		default:
+			return nil, false
+		}
+	case OpMinus:
+		switch x := operands[0].(type) {
+		case Int:
+			if x == -x && x != 0 {
+				return nil, false
+			}
+			result = -x
+		case Float:
+			result = -x
+		default:
+			return nil, false
+		}
+	case OpBooleanNot:
+		b, ok := operands[0].(Bool)
+		if !ok {
+			return nil, false
+		}
+		result = !b
+	case OpStrConcat:
+		left, ok1 := operands[0].(String)
+		right, ok2 := operands[1].(String)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		result = left + right
+	case OpConcatStrLikes:
+		spreadElemSet := o.bytecode.constants[instr.operands[1]].(*List).underlyingList.(*BoolList)
+		stringLikes := make([]StringLike, len(operands))
+
+		for i, operand := range operands {
+			str, ok := operand.(String)
+			if !ok || spreadElemSet.BoolAt(i) {
+				return nil, false
+			}
+			stringLikes[i] = str
+		}
+
+		var concatenation StringLike
+		concatenation, err = ConcatStringLikes(stringLikes...)
+		//lazy concatenations are not folded because they are built on first use.
+		if _, ok := concatenation.(String); !ok {
+			return nil, false
+		}
+		result = concatenation
+	default:
+		return nil, false
+	}
+
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// applyPeepholeRules simplifies or fuses pairs of adjacent instructions:
+//   - PUSH_TRUE + JUMP_IFF are removed and PUSH_FALSE + JUMP_IFF is replaced by a JUMP.
+//   - an instruction pushing a value without side effects followed by a POP are removed.
+//   - GET_LOCAL + MEMB are fused into GET_LOCAL_MEMB.
+func (o *functionOptimizer) applyPeepholeRules() (count int) {
+	for {
+		countInIteration := 0
+		targets := o.jumpTargets()
+
+		for i := 0; i < len(o.instructions)-1; i++ {
+			first := o.instructions[i]
+			second := o.instructions[i+1]
+
+			if targets[i+1] {
+				continue
+			}
+
+			switch {
+			case first.op == OpPushTrue && second.op == OpJumpIfFalse:
+				first.removed = true
+				second.removed = true
+			case first.op == OpPushFalse && second.op == OpJumpIfFalse:
+				first.removed = true
+				second.op = OpJump
+			case second.op == OpPop && (first.op == OpPushConstant || first.op == OpPushNil || first.op == OpPushTrue ||
+				first.op == OpPushFalse || first.op == OpCopyTop || first.op == OpGetLocal):
+				first.removed = true
+				second.removed = true
+			case first.op == OpGetLocal && second.op == OpMemb:
+				first.removed = true
+				second.op = OpGetLocalMemb
+				second.operands = []int{first.operands[0], second.operands[0]}
+			default:
+				continue
+			}
+
+			countInIteration++
+			i++ //the second instruction should not be part of another pair.
+		}
+
+		if countInIteration == 0 {
+			return
+		}
+		count += countInIteration
+		o.compact()
+	}
+}
+
+// threadJumps makes jumps to unconditional jumps directly target the final destination, replaces unconditional
+// jumps to a RETURN by the RETURN and removes unconditional jumps to the next instruction.
+func (o *functionOptimizer) threadJumps() (count int) {
+	for i, instr := range o.instructions {
+		if instr.op != OpJump && instr.op != OpJumpIfFalse {
+			continue
+		}
+
+		target := instr.target
+		//the number of steps is limited because of infinite loops.
+		for steps := 0; steps < len(o.instructions) && target < len(o.instructions); steps++ {
+			targeted := o.instructions[target]
+			if targeted.op != OpJump || targeted.target == target {
+				break
+			}
+			target = targeted.target
+		}
+
+		if target != instr.target {
+			instr.target = target
+			count++
+		}
+
+		if instr.op != OpJump {
+			continue
+		}
+
+		if target == i+1 {
+			instr.removed = true
+			count++
+		} else if target < len(o.instructions) && o.instructions[target].op == OpReturn {
+			ret := o.instructions[target]
+			instr.op = OpReturn
+			instr.operands = slices.Clone(ret.operands)
+			instr.target = -1
+			instr.sourcePos, instr.hasSourcePos = ret.sourcePos, ret.hasSourcePos
+			count++
+		}
+	}
+	return
+}
+
+// eliminateDeadCode removes the instructions that are not reachable from the first instruction, for example
+// the instructions following a RETURN, a break or a continue statement. Unconditional jumps to the next
+// instruction are also removed.
+func (o *functionOptimizer) eliminateDeadCode() (removed int) {
+	if len(o.instructions) == 0 {
+		return
+	}
+
+	reachable := make([]bool, len(o.instructions))
+	stack := []int{0}
+
+	push := func(index int) {
+		if index < len(o.instructions) && !reachable[index] {
+			stack = append(stack, index)
+		}
+	}
+
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if reachable[index] {
+			continue
+		}
+		reachable[index] = true
+		instr := o.instructions[index]
+
+		switch instr.op {
+		case OpReturn:
+		case OpJump:
+			push(instr.target)
+		case OpPopJumpIfTestDisabled:
+			push(index + 1)
+			push(instr.target)
+			push(instr.target + 1)
+		default:
+			push(index + 1)
+			if instr.isJump() {
+				push(instr.target)
+			}
+		}
+	}
+
+	for i, instr := range o.instructions {
+		if !reachable[i] {
+			instr.removed = true
+			removed++
+		}
+	}
+	o.compact()
+
+	//unconditional jumps to the next instruction are removed because they may have been jumping over unreachable code.
+	for i, instr := range o.instructions {
+		if instr.op == OpJump && instr.target == i+1 {
+			instr.removed = true
+			removed++
+		}
+	}
+	return
+}
+
+// reuseLocalSlots assigns the same slot to locals whose lifetimes do not overlap and decreases the local count of
+// the function accordingly. The lifetime of a local is the range of instructions between its first and last
+// reference, extended to the whole body of the loops it overlaps. Only locals referenced by GET_LOCAL, SET_LOCAL
+// and GET_LOCAL_MEMB instructions and whose first reference is a SET_LOCAL can share a slot; parameters and
+// captured locals keep their slot.
+func (o *functionOptimizer) reuseLocalSlots() (freed int) {
+	localCount := o.fn.LocalCount
+	if o.reservedLocalCount >= localCount {
+		return
+	}
+
+	type localInfo struct {
+		index, start, end int
+		referenced        bool
+		shareable         bool
+	}
+
+	locals := make([]localInfo, localCount)
+	for i := range locals {
+		locals[i] = localInfo{index: i, shareable: i >= o.reservedLocalCount}
+	}
+
+	reference := func(local int, instrIndex int, isSet, isPlainAccess bool) {
+		if local >= localCount {
+			return
+		}
+		info := &locals[local]
+		if !info.referenced {
+			info.referenced = true
+			info.start = instrIndex
+			info.shareable = info.shareable && isSet
+		}
+		info.end = instrIndex
+		info.shareable = info.shareable && isPlainAccess
+	}
+
+	for i, instr := range o.instructions {
+		switch instr.op {
+		case OpGetLocal, OpGetLocalMemb:
+			reference(instr.operands[0], i, false, true)
+		case OpSetLocal:
+			reference(instr.operands[0], i, true, true)
+		case OpIterNext, OpIterNextChunk, OpIterValue, OpIterPrune, OpGroupMatch:
+			reference(instr.operands[0], i, false, false)
+		}
+	}
+
+	//extend the lifetimes to the loops they overlap, a value set during an iteration may be read during the next one.
+	for changed := true; changed; {
+		changed = false
+		for i, instr := range o.instructions {
+			if !instr.isJump() || instr.target > i {
+				continue
+			}
+			loopStart, loopEnd := instr.target, i
+
+			for j := range locals {
+				info := &locals[j]
+				if !info.referenced || info.start > loopEnd || info.end < loopStart {
+					continue
+				}
+				if info.start > loopStart || info.end < loopEnd {
+					info.start = min(info.start, loopStart)
+					info.end = max(info.end, loopEnd)
+					changed = true
+				}
+			}
+		}
+	}
+
+	//assign the new slots (linear scan).
+	newIndexes := make([]int, localCount)
+	for i := range newIndexes {
+		newIndexes[i] = -1
+	}
+	for i := 0; i < o.reservedLocalCount; i++ {
+		newIndexes[i] = i
+	}
+	nextSlot := o.reservedLocalCount
+
+	var ordered []localInfo
+	for _, info := range locals {
+		if info.index >= o.reservedLocalCount && info.referenced {
+			ordered = append(ordered, info)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].start < ordered[j].start
+	})
+
+	type activeSlot struct {
+		slot, end int
+	}
+	var active []activeSlot
+	var freeSlots []int
+
+	for _, info := range ordered {
+		//release the slots of the locals whose lifetime has ended.
+		remaining := active[:0]
+		for _, a := range active {
+			if a.end < info.start {
+				freeSlots = append(freeSlots, a.slot)
+			} else {
+				remaining = append(remaining, a)
+			}
+		}
+		active = remaining
+
+		slot := -1
+		if info.shareable && len(freeSlots) > 0 {
+			sort.Ints(freeSlots)
+			slot = freeSlots[0]
+			freeSlots = freeSlots[1:]
+		} else {
+			slot = nextSlot
+			nextSlot++
+		}
+		newIndexes[info.index] = slot
+
+		if info.shareable {
+			active = append(active, activeSlot{slot: slot, end: info.end})
+		}
+	}
+
+	if nextSlot >= localCount {
+		return
+	}
+
+	for _, instr := range o.instructions {
+		switch instr.op {
+		case OpGetLocal, OpGetLocalMemb, OpSetLocal, OpIterNext, OpIterNextChunk, OpIterValue, OpIterPrune, OpGroupMatch:
+			if local := instr.operands[0]; local < localCount {
+				instr.operands[0] = newIndexes[local]
+			}
+		}
+	}
+
+	if o.fn.LocalNames != nil {
+		localNames := make([]string, nextSlot)
+		for oldIndex, newIndex := range newIndexes {
+			if newIndex >= 0 && oldIndex < len(o.fn.LocalNames) && localNames[newIndex] == "" {
+				localNames[newIndex] = o.fn.LocalNames[oldIndex]
+			}
+		}
+		o.fn.LocalNames = localNames
+	}
+
+	freed = localCount - nextSlot
+	o.fn.LocalCount = nextSlot
+	return
 }
 
-func deduplicateConstants(b *Bytecode, tracer io.Writer) {
+func deduplicateConstants(b *Bytecode, tracer io.Writer) (remapped int) {
 	constantsMapping := make([]int, len(b.constants))
 	ctx := NewContext(ContextConfig{})
 
@@ -54,6 +880,7 @@ func deduplicateConstants(b *Bytecode, tracer io.Writer) {
 					tracer.Write([]byte(s))
 				}
 				constantsMapping[j] = newConstantIndex
+				remapped++
 			}
 		}
 	}
@@ -95,4 +922,5 @@ func deduplicateConstants(b *Bytecode, tracer io.Writer) {
 
 	b.main.Instructions = newInstructions
 	b.constants = newConstants
+	return
 }
@@ -0,0 +1,308 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/testconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytecodeOptimizationPasses(t *testing.T) {
+	testconfig.AllowParallelization(t)
+
+	//optimize compiles the code and only applies the passed optimization pass.
+	optimize := func(t *testing.T, code string, pass BytecodeOptimizationPass) *Bytecode {
+		bytecode, _, _ := traceCompile(t, code, nil)
+
+		var disabledPasses []BytecodeOptimizationPass
+		for _, p := range BYTECODE_OPTIMIZATION_PASSES {
+			if p != pass {
+				disabledPasses = append(disabledPasses, p)
+			}
+		}
+
+		optimizeBytecode(bytecode, disabledPasses, nil)
+		return bytecode
+	}
+
+	t.Run("constant folding", func(t *testing.T) {
+		bytecode := optimize(t, "return (1 + (2 * 3))", CONSTANT_FOLDING_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 4),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+		assert.Equal(t, Int(7), bytecode.constants[4])
+
+		bytecode = optimize(t, "return (1 < 2)", CONSTANT_FOLDING_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushTrue),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+
+		bytecode = optimize(t, `return concat "a" "b"`, CONSTANT_FOLDING_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 3),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+		assert.Equal(t, String("ab"), bytecode.constants[3])
+
+		//operations failing at runtime should not be folded.
+		bytecode = optimize(t, "return (1 / 0)", CONSTANT_FOLDING_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 0),
+			inst(OpPushConstant, 1),
+			inst(OpNumBin, int(parse.Div)),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+	})
+
+	t.Run("peephole", func(t *testing.T) {
+		bytecode := optimize(t, "if false { return 1 }; return 2", PEEPHOLE_PASS)
+		assert.Equal(t, instrs(
+			inst(OpJump, 8),
+			inst(OpPushConstant, 0),
+			inst(OpReturn, 1),
+			inst(OpPushConstant, 1),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+
+		bytecode = optimize(t, "o = {a: 1}; return o.a", PEEPHOLE_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 0),
+			inst(OpPushConstant, 1),
+			inst(OpCreateObject, 1, 2),
+			inst(OpSetLocal, 0),
+			inst(OpGetLocalMemb, 0, 3),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+	})
+
+	t.Run("jump threading", func(t *testing.T) {
+		bytecode := optimize(t, "for i in [1, 2] { if (i == 1) { continue }; break }; return 1", JUMP_THREADING_PASS)
+
+		//the continue statement should directly jump to the loop condition and the break statement
+		//should directly jump after the loop.
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 0),
+			inst(OpPushConstant, 1),
+			inst(OpCreateList, 2),
+			inst(OpIterInit, 0),
+			inst(OpSetLocal, 0),
+			inst(OpGetLocal, 0),
+			inst(OpIterNext, 1),
+			inst(OpJumpIfFalse, 44),
+			inst(OpGetLocal, 0),
+			inst(OpIterValue, 1),
+			inst(OpSetLocal, 2),
+			inst(OpGetLocal, 2),
+			inst(OpPushConstant, 2),
+			inst(OpEqual),
+			inst(OpJumpIfFalse, 44),
+			inst(OpJump, 13),
+			inst(OpJump, 44),
+			inst(OpJump, 13),
+			inst(OpPushConstant, 3),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+	})
+
+	t.Run("dead code elimination", func(t *testing.T) {
+		bytecode := optimize(t, "fn f(){ return 1; return 2 }; return f()", DEAD_CODE_ELIMINATION_PASS)
+
+		fn := bytecode.constants[2].(*InoxFunction)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 0),
+			inst(OpReturn, 1),
+		), fn.compiledFunction.Instructions)
+
+		//the SUSPEND instruction is not reachable.
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 2),
+			inst(OpSetGlobal, 3),
+			inst(OpPushNil),
+			inst(OpPushNil),
+			inst(OpGetGlobal, 4),
+			inst(OpCall, 0, 0, 0),
+			inst(OpReturn, 1),
+		), bytecode.main.Instructions)
+	})
+
+	t.Run("local slot reuse", func(t *testing.T) {
+		bytecode := optimize(t, "a = 1; b = a; c = 2; d = c; return d", LOCAL_SLOT_REUSE_PASS)
+		assert.Equal(t, 1, bytecode.main.LocalCount)
+
+		//the lifetime of a local overlapping a loop is extended to the whole loop.
+		bytecode = optimize(t, "a = 0; for i in [1, 2] { b = a; a = i }; c = a; return c", LOCAL_SLOT_REUSE_PASS)
+		assert.Equal(t, instrs(
+			inst(OpPushConstant, 0),
+			inst(OpSetLocal, 0),
+			inst(OpPushConstant, 1),
+			inst(OpPushConstant, 2),
+			inst(OpCreateList, 2),
+			inst(OpIterInit, 0),
+			inst(OpSetLocal, 1),
+			inst(OpGetLocal, 1),
+			inst(OpIterNext, 2),
+			inst(OpJumpIfFalse, 42),
+			inst(OpGetLocal, 1),
+			inst(OpIterValue, 2),
+			inst(OpSetLocal, 3),
+			inst(OpGetLocal, 0),
+			inst(OpSetLocal, 4),
+			inst(OpGetLocal, 3),
+			inst(OpSetLocal, 0),
+			inst(OpJump, 18),
+			inst(OpGetLocal, 0),
+			inst(OpSetLocal, 0), //c reuses the slot of a
+			inst(OpGetLocal, 0),
+			inst(OpReturn, 1),
+			inst(OpSuspendVM),
+		), bytecode.main.Instructions)
+		assert.Equal(t, 5, bytecode.main.LocalCount)
+	})
+
+	t.Run("already optimized bytecode should not be optimized again", func(t *testing.T) {
+		bytecode, _, _ := traceCompile(t, "return (1 + 2)", nil)
+		optimizeBytecode(bytecode, nil, nil)
+		instructions := bytecode.main.Instructions
+
+		optimizeBytecode(bytecode, nil, nil)
+		assert.Equal(t, instructions, bytecode.main.Instructions)
+	})
+}
+
+func TestParseBytecodeOptimizationPassList(t *testing.T) {
+	passes, err := ParseBytecodeOptimizationPassList("peephole, constant-folding,")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []BytecodeOptimizationPass{PEEPHOLE_PASS, CONSTANT_FOLDING_PASS}, passes)
+	}
+
+	_, err = ParseBytecodeOptimizationPassList("peephole,unknown")
+	assert.ErrorContains(t, err, "unknown")
+}
+
+// TestOptimizedBytecodeDifferential compares the results of the tree walk interpreter with the results of
+// the VM with no optimization, with each optimization pass alone and with all passes.
+func TestOptimizedBytecodeDifferential(t *testing.T) {
+	testconfig.AllowParallelization(t)
+
+	programs := []string{
+		"return (1 + (2 * 3))",
+		"return [(1.5 * 2.0), (- 3), !true, (1 < 2), (2.0 >= 3.0)]",
+		`return concat "a" "b" "c"`,
+		"return (1 / 0)",
+		"return (9223372036854775807 + 1)",
+		"a = 1; if true { a = 2 } else { a = 3 }; return a",
+		"a = 1; if false { a = 2 }; return a",
+		`
+			s = 0
+			for i in [1, 2, 3, 4, 5] {
+				if (i == 2) { continue }
+				if (i == 5) { break }
+				s = (s + i)
+			}
+			return s
+		`,
+		`
+			a = 0
+			for i in [1, 2, 3] {
+				b = (a + i)
+				a = b
+			}
+			c = 10
+			d = (c + a)
+			return [a, c, d]
+		`,
+		`
+			count = 0
+			for i in [1, 2, 3] {
+				for j in [1, 2, 3] {
+					if (j == 3) { break }
+					count = (count + (i * j))
+				}
+			}
+			return count
+		`,
+		`
+			fn f(x %int){
+				y = (x + 1)
+				if (y > 2) { 
+					return y 
+				}
+				return 0
+				return 1
+			}
+			return [f(1), f(3)]
+		`,
+		"o = {a: 1, b: {c: 2}}; return (o.a + o.b.c)",
+		`
+			x = 2
+			switch x {
+				1 { return "one" }
+				2 { return "two" }
+			}
+			return "none"
+		`,
+	}
+
+	type configuration struct {
+		name           string
+		optimize       bool
+		disabledPasses []BytecodeOptimizationPass
+	}
+
+	configurations := []configuration{
+		{name: "no optimization"},
+		{name: "all passes", optimize: true},
+	}
+
+	for _, pass := range BYTECODE_OPTIMIZATION_PASSES {
+		var disabledPasses []BytecodeOptimizationPass
+		for _, p := range BYTECODE_OPTIMIZATION_PASSES {
+			if p != pass {
+				disabledPasses = append(disabledPasses, p)
+			}
+		}
+		configurations = append(configurations, configuration{
+			name:           string(pass),
+			optimize:       true,
+			disabledPasses: disabledPasses,
+		})
+	}
+
+	newState := func() *GlobalState {
+		ctx := NewDefaultTestContext()
+		ctx.AddNamedPattern("int", INT_PATTERN)
+		return NewGlobalState(ctx)
+	}
+
+	for _, program := range programs {
+		state := newState()
+		expected, expectedErr := makeTreeWalkEvalFunc(t)(program, state, true)
+		state.Ctx.CancelGracefully()
+
+		for _, config := range configurations {
+			state := newState()
+			res, err := makeBytecodeEvalFunc(t, config.optimize, config.disabledPasses)(program, state, true)
+			state.Ctx.CancelGracefully()
+
+			if expectedErr != nil {
+				assert.Error(t, err, "%s\n%s", config.name, program)
+				continue
+			}
+
+			if !assert.NoError(t, err, "%s\n%s", config.name, program) {
+				continue
+			}
+			assert.Equal(t, Stringify(expected, nil), Stringify(res, nil), "%s\n%s", config.name, program)
+		}
+	}
+}
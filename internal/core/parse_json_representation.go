@@ -482,6 +482,12 @@ func ParseObjectJSONrepresentation(ctx *Context, it *jsoniter.Iterator, pattern
 		return nil, finalErr
 	}
 
+	return finalizeParsedObject(ctx, obj, pattern, effectivePatternEntries, try)
+}
+
+// finalizeParsedObject checks the properties of a freshly parsed object against its pattern, adds the missing
+// properties that have a default value, and instantiates the message handlers and lifetime jobs of the object.
+func finalizeParsedObject(ctx *Context, obj *Object, pattern *ObjectPattern, effectivePatternEntries ObjectPatternEntriesHelper, try bool) (*Object, error) {
 	var missingRequiredProperties []string
 
 	//If exact, check that there are no additional properties
@@ -613,6 +619,12 @@ func parseRecordJSONrepresentation(ctx *Context, it *jsoniter.Iterator, pattern
 		return nil, finalErr
 	}
 
+	return finalizeParsedRecord(ctx, rec, pattern, try)
+}
+
+// finalizeParsedRecord adds the missing properties of a freshly parsed record that have a default value and checks
+// that no required property is missing.
+func finalizeParsedRecord(ctx *Context, rec *Record, pattern *RecordPattern, try bool) (*Record, error) {
 	var missingRequiredProperties []string
 
 	pattern.ForEachEntry(func(entry RecordPatternEntry) error {
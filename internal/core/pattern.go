@@ -1058,6 +1058,36 @@ func (patt *OptionalPattern) StringPattern() (StringPattern, bool) {
 	return nil, false
 }
 
+// A GenericPattern is the value of a pattern definition with type parameters (e.g. pattern Paginated<T> = {items: []T}).
+// Type arguments are only checked by the symbolic evaluator, at runtime they are erased: calling a GenericPattern
+// returns the pattern obtained by replacing each type parameter with its constraint (%serializable if there is no constraint).
+type GenericPattern struct {
+	erased             Pattern
+	typeParameterCount int
+}
+
+func NewGenericPattern(erased Pattern, typeParameterCount int) *GenericPattern {
+	return &GenericPattern{
+		erased:             erased,
+		typeParameterCount: typeParameterCount,
+	}
+}
+
+func (patt *GenericPattern) Call(values []Serializable) (Pattern, error) {
+	if len(values) != patt.typeParameterCount {
+		return nil, fmt.Errorf("invalid number of type arguments: %d, %d was expected", len(values), patt.typeParameterCount)
+	}
+	return patt.erased, nil
+}
+
+func (patt *GenericPattern) Test(ctx *Context, v Value) bool {
+	return patt.erased.Test(ctx, v)
+}
+
+func (patt *GenericPattern) StringPattern() (StringPattern, bool) {
+	return nil, false
+}
+
 // A FunctionPattern represents a pattern that matches that either matches any function or
 // functions with certain parameters and return types.
 // Inox's function pattern literals (e.g. fn() int) evaluate to a function pattern.
@@ -1349,6 +1379,27 @@ func (patt *MutationPattern) StringPattern() (StringPattern, bool) {
 	return nil, false
 }
 
+// An ErrorPattern matches errors having a given kind, ErrorPattern instances are created by calling the %error pattern
+// with an identifier: %error(#not-found).
+type ErrorPattern struct {
+	kind Identifier
+
+	NotCallablePatternMixin
+}
+
+func NewErrorPattern(kind Identifier) *ErrorPattern {
+	return &ErrorPattern{kind: kind}
+}
+
+func (patt *ErrorPattern) Test(ctx *Context, v Value) bool {
+	err, ok := v.(Error)
+	return ok && err.kind == patt.kind
+}
+
+func (patt *ErrorPattern) StringPattern() (StringPattern, bool) {
+	return nil, false
+}
+
 func isFloatPattern(p Pattern) bool {
 	switch pattern := p.(type) {
 	case *TypePattern:
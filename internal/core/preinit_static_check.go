@@ -604,6 +604,12 @@ func checkDatabasesObject(
 					isValidDescription = false
 					onError(p, DATABASES__DB_ASSERT_SCHEMA_SHOULD_BE_PATT_IDENT_OR_OBJ_PATT)
 				}
+			case MANIFEST_DATABASE__FORMAT_PROP_NAME:
+				ident, ok := prop.Value.(*parse.UnambiguousIdentifierLiteral)
+				if !ok || !slices.Contains(SERIALIZATION_FORMAT_NAMES, ident.Name) {
+					isValidDescription = false
+					onError(p, DATABASES__DB_FORMAT_SHOULD_BE_A_FORMAT_IDENT)
+				}
 			default:
 				isValidDescription = false
 				onError(p, fmtUnexpectedPropOfDatabaseDescription(prop.Name()))
@@ -921,6 +921,39 @@ func TestPreInit(t *testing.T) {
 			},
 			expectedResolutions: nil,
 		},
+		{
+			name: "correct_database_with_format",
+			module: `manifest {
+					databases: {
+						main: {
+							resource: ldb://main
+							resolution-data: nil
+							format: #binary
+						}
+					}
+				}`,
+			expectedPermissions: []Permission{
+				DatabasePermission{
+					permkind.Read,
+					Host("ldb://main"),
+				},
+				DatabasePermission{
+					permkind.Write,
+					Host("ldb://main"),
+				},
+			},
+			expectedLimits: []Limit{minLimitA, minLimitB, threadLimit},
+			expectedDatabaseConfigs: DatabaseConfigs{
+				{
+					Name:           "main",
+					Owned:          true,
+					Resource:       Host("ldb://main"),
+					ResolutionData: Nil,
+					Format:         BINARY_SERIALIZATION_FORMAT,
+				},
+			},
+			expectedResolutions: nil,
+		},
 		{
 			name: "correct_database_with_assert_schema",
 			module: `
@@ -1026,6 +1059,20 @@ func TestPreInit(t *testing.T) {
 			error:                     true,
 			expectedStaticCheckErrors: []string{DATABASES__DB_EXPECTED_SCHEMA_UPDATE_SHOULD_BE_BOOL_LIT},
 		},
+		{
+			name: "database_with_invalid_format",
+			module: `manifest {
+					databases: {
+						main: {
+							resource: ldb://main
+							resolution-data: nil
+							format: #xml
+						}
+					}
+				}`,
+			error:                     true,
+			expectedStaticCheckErrors: []string{DATABASES__DB_FORMAT_SHOULD_BE_A_FORMAT_IDENT},
+		},
 		{
 			name: "database_with_missing_resource",
 			module: `manifest {
@@ -138,8 +138,8 @@ func GetNodeColorizations(chunk *parse.Chunk, lightMode bool) []ColorizationInfo
 			//control keywords
 		case parse.BREAK_KEYWORD, parse.CONTINUE_KEYWORD, parse.PRUNE_KEYWORD, parse.YIELD_KEYWORD, parse.RETURN_KEYWORD,
 			parse.DEFAULTCASE_KEYWORD, parse.SWITCH_KEYWORD, parse.MATCH_KEYWORD, parse.ASSERT_KEYWORD,
-			parse.GO_KEYWORD, parse.DO_KEYWORD, parse.TESTSUITE_KEYWORD, parse.TESTCASE_KEYWORD, parse.COMP_KEYWORD,
-			parse.LIFETIMEJOB_KEYWORD, parse.FOR_KEYWORD, parse.IN_KEYWORD, parse.IF_KEYWORD, parse.ELSE_KEYWORD,
+			parse.GO_KEYWORD, parse.DO_KEYWORD, parse.TESTSUITE_KEYWORD, parse.TESTCASE_KEYWORD, parse.BENCHMARK_KEYWORD,
+			parse.COMP_KEYWORD, parse.LIFETIMEJOB_KEYWORD, parse.FOR_KEYWORD, parse.IN_KEYWORD, parse.IF_KEYWORD, parse.ELSE_KEYWORD,
 			parse.PREINIT_KEYWORD, parse.ON_KEYWORD, parse.WALK_KEYWORD,
 			parse.DROP_PERMS_KEYWORD, parse.IMPORT_KEYWORD:
 			colorizations = append(colorizations, ColorizationInfo{
@@ -1483,6 +1483,10 @@ func (pattern *OptionalPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrint
 	InspectPrint(w, pattern)
 }
 
+func (pattern *GenericPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
+	InspectPrint(w, pattern)
+}
+
 func (pattern *FunctionPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
 	InspectPrint(w, pattern)
 }
@@ -1806,6 +1810,10 @@ func (patt *MutationPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrintCon
 	InspectPrint(w, patt)
 }
 
+func (patt *ErrorPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
+	utils.Must(fmt.Fprintf(w, "%%error(#%s)", patt.kind))
+}
+
 func (patt *ParserBasedPseudoPattern) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
 	InspectPrint(w, patt)
 }
@@ -2099,6 +2107,10 @@ func (c *TestCase) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth
 	InspectPrint(w, c)
 }
 
+func (b *Benchmark) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
+	InspectPrint(w, b)
+}
+
 func (r *TestCaseResult) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
 	if r.Success {
 		w.Write(utils.StringAsBytes(r.Message))
@@ -2176,6 +2188,14 @@ func (r *RingBuffer) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, dep
 	PrintType(w, r)
 }
 
+func (c *Channel) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
+	PrintType(w, c)
+}
+
+func (it *ChannelIterator) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
+	InspectPrint(w, it)
+}
+
 func (c *DataChunk) PrettyPrint(w *bufio.Writer, config *PrettyPrintConfig, depth int, parentIndentCount int) {
 	PrintType(w, c)
 }
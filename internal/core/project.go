@@ -32,6 +32,15 @@ type Project interface {
 	DevDatabasesDirOnOsFs() string
 }
 
+// An InMemoryDatabasesProject is a Project whose local databases may only be stored in memory.
+type InMemoryDatabasesProject interface {
+	Project
+
+	//HasInMemoryDatabases should return true if the local databases should be stored in memory
+	//instead of DevDatabasesDirOnOsFs().
+	HasInMemoryDatabases() bool
+}
+
 type ProjectID string
 
 func RandomProjectID(projectName string) ProjectID {
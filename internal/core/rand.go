@@ -301,6 +301,10 @@ func (patt *OptionalPattern) Random(ctx *Context, options ...Option) Value {
 	return patt.pattern.Random(ctx, options...)
 }
 
+func (patt *GenericPattern) Random(ctx *Context, options ...Option) Value {
+	return patt.erased.Random(ctx, options...)
+}
+
 func (patt *FunctionPattern) Random(ctx *Context, options ...Option) Value {
 	panic(ErrNotImplemented)
 }
@@ -407,6 +411,10 @@ func (patt *MutationPattern) Random(ctx *Context, options ...Option) Value {
 	panic(ErrNotImplementedYet)
 }
 
+func (patt *ErrorPattern) Random(ctx *Context, options ...Option) Value {
+	panic(ErrNotImplementedYet)
+}
+
 func (pattern *NamedSegmentPathPattern) Random(ctx *Context, options ...Option) Value {
 	panic(ErrNotImplementedYet)
 }
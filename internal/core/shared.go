@@ -11,7 +11,7 @@ const SYNC_CHAN_SIZE = 100
 var (
 	_ = []PotentiallySharable{
 		(*Object)(nil), (*InoxFunction)(nil), (*GoFunction)(nil), (*Mapping)(nil),
-		(*RingBuffer)(nil), (*ValueHistory)(nil),
+		(*RingBuffer)(nil), (*ValueHistory)(nil), (*Channel)(nil),
 	}
 
 	ErrValueNotSharableNorClonable = errors.New("value is not sharable nor clonable")
@@ -39,6 +39,16 @@ type StaticCheckInput struct {
 	ShellLocalVars         map[string]Value
 	Patterns               map[string]Pattern
 	PatternNamespaces      map[string]*PatternNamespace
+
+	//If true the checking state is saved before each top level statement of the chunk, the returned data can
+	//then be passed as PreviousData to reuse the checking of the first statements.
+	SaveTopLevelCheckpoints bool
+
+	//If SaveTopLevelCheckpoints is true and ReusedTopLevelStatementCount is not zero the checking of the first
+	//ReusedTopLevelStatementCount top level statements of PreviousData's chunk is reused, see
+	//symbolic.EvalCheckInput.ReusedTopLevelStatementCount for the conditions.
+	PreviousData                 *StaticCheckData
+	ReusedTopLevelStatementCount int
 }
 
 // StaticCheck performs various checks on an AST, like checking duplicate declarations and keys or checking that statements like return,
@@ -108,7 +118,16 @@ func StaticCheck(input StaticCheckInput) (*StaticCheckData, error) {
 		},
 	}
 
-	if module != nil {
+	resumed := false
+	if input.SaveTopLevelCheckpoints {
+		var err error
+		resumed, err = checker.initTopLevelCheckpointing()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if module != nil && !resumed {
 		var statements []parse.Node
 		if chunk, ok := module.(*parse.Chunk); ok {
 			statements = chunk.Statements
@@ -124,6 +143,11 @@ func StaticCheck(input StaticCheckInput) (*StaticCheckData, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if checker.checkpointing != nil {
+		checker.saveTopLevelCheckpoint()
+	}
+
 	return checker.data, combineStaticCheckErrors(checker.data.errors...)
 }
 
@@ -164,6 +188,8 @@ type checker struct {
 	store map[parse.Node]any
 
 	data *StaticCheckData
+
+	checkpointing *staticCheckCheckpointing //only set for the checker of the main chunk
 }
 
 // globalVarInfo represents the information stored about a global variable during checking.
@@ -286,6 +312,11 @@ func (c *checker) defineStructs(closestModule parse.Node, statements []parse.Nod
 
 func (checker *checker) check(node parse.Node) error {
 	checkNode := func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
+		if checker.checkpointing != nil && parent == checker.checkInput.Node {
+			if checker.beforeTopLevelNode(node) == parse.Prune {
+				return parse.Prune, nil
+			}
+		}
 		return checker.checkSingleNode(node, parent, scopeNode, ancestorChain, after), nil
 	}
 	postCheckNode := func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
@@ -681,6 +712,8 @@ func (c *checker) checkSingleNode(n, parent, scopeNode parse.Node, ancestorChain
 		return c.checkTestSuiteExpr(node, ancestorChain)
 	case *parse.TestCaseExpression:
 		return c.checkTestCaseExpr(node, ancestorChain)
+	case *parse.BenchmarkExpression:
+		return c.checkBenchmarkExpr(node, ancestorChain)
 	case *parse.EmbeddedModule:
 		return c.checkEmbeddedModule(node, parent, closestModule, ancestorChain)
 	}
@@ -1202,12 +1235,12 @@ func (c *checker) checkInclusionImportStmt(node *parse.InclusionImportStatement,
 		c.data.warnings = append(c.data.warnings, chunkChecker.data.warnings...)
 	}
 
-	for k, v := range chunkChecker.data.fnData {
-		c.data.fnData[k] = v
+	for _, k := range chunkChecker.data.fnDataKeys {
+		c.data.setFnData(k, chunkChecker.data.fnData[k])
 	}
 
-	for k, v := range chunkChecker.data.mappingData {
-		c.data.mappingData[k] = v
+	for _, k := range chunkChecker.data.mappingDataKeys {
+		c.data.setMappingData(k, chunkChecker.data.mappingData[k])
 	}
 
 	// include all global data & top level local variables
@@ -1683,6 +1716,8 @@ func (c *checker) checkFuncDecl(node *parse.FunctionDeclaration, parent, closest
 func (c *checker) checkFuncExpr(node *parse.FunctionExpression, closestModule parse.Node, ancestorChain []parse.Node) parse.TraversalAction {
 	fnLocalVars := c.getLocalVarsInScope(node)
 
+	c.checkTypeParameters(node.TypeParameters)
+
 	//we check that the captured variable exists & is a local
 	for _, e := range node.CaptureList {
 		name := e.(*parse.IdentifierLiteral).Name
@@ -2236,6 +2271,13 @@ func (c *checker) checkPatternDef(node *parse.PatternDefinition, parent, closest
 		}
 	}
 
+	if len(node.TypeParameters) > 0 {
+		if node.IsLazy {
+			c.addError(node, LAZY_PATTERN_DEFINITIONS_CANNOT_HAVE_TYPE_PARAMETERS)
+		}
+		c.checkTypeParameters(node.TypeParameters)
+	}
+
 	patternName, ok := node.PatternName()
 	if ok {
 		patterns := c.getModPatterns(closestModule)
@@ -2282,8 +2324,29 @@ func (c *checker) checkPatternNamespaceIdentifier(node *parse.PatternNamespaceId
 	return parse.ContinueTraversal
 }
 
+// checkTypeParameters checks the type parameters of a generic function or pattern definition.
+func (c *checker) checkTypeParameters(params []*parse.TypeParameter) {
+	for i, param := range params {
+		for _, prev := range params[:i] {
+			if prev.Name.Name == param.Name.Name {
+				c.addError(param.Name, fmtDuplicateTypeParameter(param.Name.Name))
+				break
+			}
+		}
+	}
+}
+
 func (c *checker) checkPatternIdentifier(node *parse.PatternIdentifierLiteral, parent, closestModule parse.Node, ancestorChain []parse.Node) parse.TraversalAction {
 
+	//Type parameters are declared by generic functions and pattern definitions.
+	if node.TypeParameter != nil {
+		return parse.ContinueTraversal
+	}
+
+	if param, ok := parent.(*parse.TypeParameter); ok && param.Name == node {
+		return parse.ContinueTraversal
+	}
+
 	if _, ok := parent.(*parse.OtherPropsExpr); ok && node.Name == parse.NO_OTHERPROPS_PATTERN_NAME {
 		return parse.ContinueTraversal
 
@@ -2469,10 +2532,17 @@ func (c *checker) checkTestSuiteExpr(node *parse.TestSuiteExpression, ancestorCh
 				if i-1 <= 0 {
 					break search_test_case
 				}
-				testCaseExpr, ok := ancestorChain[i-1].(*parse.TestCaseExpression)
-				if ok && testCaseExpr.IsStatement {
-					c.addError(node, TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_TEST_CASE_STMTS)
-					break search_test_case
+				switch expr := ancestorChain[i-1].(type) {
+				case *parse.TestCaseExpression:
+					if expr.IsStatement {
+						c.addError(node, TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_TEST_CASE_STMTS)
+						break search_test_case
+					}
+				case *parse.BenchmarkExpression:
+					if expr.IsStatement {
+						c.addError(node, TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_BENCHMARK_STMTS)
+						break search_test_case
+					}
 				}
 			}
 		}
@@ -2506,6 +2576,30 @@ search_test_suite:
 	return parse.ContinueTraversal
 }
 
+func (c *checker) checkBenchmarkExpr(node *parse.BenchmarkExpression, ancestorChain []parse.Node) parse.TraversalAction {
+	inTestSuite := false
+
+	//unlike test cases, benchmarks should be direct children of test suites.
+search_test_suite:
+	for i := len(ancestorChain) - 1; i >= 0; i-- {
+		switch ancestorChain[i].(type) {
+		case *parse.EmbeddedModule:
+			if i-1 <= 0 {
+				break search_test_suite
+			}
+			testSuiteExpr, ok := ancestorChain[i-1].(*parse.TestSuiteExpression)
+			inTestSuite = ok && testSuiteExpr.Module == ancestorChain[i]
+			break search_test_suite
+		}
+	}
+
+	if !inTestSuite && node.IsStatement && (c.currentModule == nil || c.currentModule.ModuleKind != TestSuiteModule) {
+		c.addError(node, BENCHMARK_STMTS_NOT_ALLOWED_OUTSIDE_OF_TEST_SUITES)
+	}
+
+	return parse.ContinueTraversal
+}
+
 func (c *checker) checkEmbeddedModule(node *parse.EmbeddedModule, parent, parentModule parse.Node, ancestorChain []parse.Node) parse.TraversalAction {
 	globals := c.getModGlobalVars(node)
 	patterns := c.getModPatterns(node)
@@ -2535,6 +2629,27 @@ func (c *checker) checkEmbeddedModule(node *parse.EmbeddedModule, parent, parent
 			patternNamespaces[name] = info
 		}
 
+		//inherit host aliases
+		for name, info := range parentModuleHostAliases {
+			hostAliases[name] = info
+		}
+	case *parse.BenchmarkExpression:
+		//inherit globals
+		for name, info := range parentModuleGlobals {
+			if slices.Contains(globalnames.TEST_ITEM_NON_INHERITED_GLOBALS, name) {
+				continue
+			}
+			globals[name] = info
+		}
+
+		//inherit patterns
+		for name, info := range parentModulePatterns {
+			patterns[name] = info
+		}
+		for name, info := range parentModulePatternNamespaces {
+			patternNamespaces[name] = info
+		}
+
 		//inherit host aliases
 		for name, info := range parentModuleHostAliases {
 			hostAliases[name] = info
@@ -2597,6 +2712,8 @@ func (checker *checker) postCheckSingleNode(node, parent, scopeNode parse.Node,
 					moduleKind = TestSuiteModule
 				case *parse.TestCaseExpression:
 					moduleKind = TestCaseModule
+				case *parse.BenchmarkExpression:
+					moduleKind = BenchmarkModule
 				default:
 					panic(ErrUnreachable)
 				}
@@ -14,6 +14,12 @@ type StaticCheckData struct {
 	fnData      map[*parse.FunctionExpression]*FunctionStaticData
 	mappingData map[*parse.MappingExpression]*MappingStaticData
 
+	//see StaticCheckInput.SaveTopLevelCheckpoints.
+	fnDataKeys          []*parse.FunctionExpression //keys of fnData in insertion order
+	mappingDataKeys     []*parse.MappingExpression  //keys of mappingData in insertion order
+	checkedChunk        *parse.Chunk
+	topLevelCheckpoints []*staticCheckCheckpoint
+
 	//.errors property accessible from scripts
 	errorsPropSet atomic.Bool
 	errorsProp    *Tuple
@@ -92,11 +98,7 @@ type MappingStaticData struct {
 }
 
 func (data *StaticCheckData) addFnCapturedGlobal(fnExpr *parse.FunctionExpression, name string, optionalInfo *globalVarInfo) {
-	fnData := data.fnData[fnExpr]
-	if fnData == nil {
-		fnData = &FunctionStaticData{}
-		data.fnData[fnExpr] = fnData
-	}
+	fnData := data.getCreateFnData(fnExpr)
 
 	if !utils.SliceContains(fnData.capturedGlobals, name) {
 		fnData.capturedGlobals = append(fnData.capturedGlobals, name)
@@ -120,7 +122,7 @@ func (data *StaticCheckData) addMappingCapturedGlobal(expr *parse.MappingExpress
 	mappingData := data.mappingData[expr]
 	if mappingData == nil {
 		mappingData = &MappingStaticData{}
-		data.mappingData[expr] = mappingData
+		data.setMappingData(expr, mappingData)
 	}
 
 	if !utils.SliceContains(mappingData.referencedGlobals, name) {
@@ -129,13 +131,32 @@ func (data *StaticCheckData) addMappingCapturedGlobal(expr *parse.MappingExpress
 }
 
 func (data *StaticCheckData) addFnAssigningGlobal(fnExpr *parse.FunctionExpression) {
+	fnData := data.getCreateFnData(fnExpr)
+
+	fnData.assignGlobal = true
+}
+
+func (data *StaticCheckData) getCreateFnData(fnExpr *parse.FunctionExpression) *FunctionStaticData {
 	fnData := data.fnData[fnExpr]
 	if fnData == nil {
 		fnData = &FunctionStaticData{}
-		data.fnData[fnExpr] = fnData
+		data.setFnData(fnExpr, fnData)
 	}
+	return fnData
+}
 
-	fnData.assignGlobal = true
+func (data *StaticCheckData) setFnData(fnExpr *parse.FunctionExpression, fnData *FunctionStaticData) {
+	if _, ok := data.fnData[fnExpr]; !ok {
+		data.fnDataKeys = append(data.fnDataKeys, fnExpr)
+	}
+	data.fnData[fnExpr] = fnData
+}
+
+func (data *StaticCheckData) setMappingData(expr *parse.MappingExpression, mappingData *MappingStaticData) {
+	if _, ok := data.mappingData[expr]; !ok {
+		data.mappingDataKeys = append(data.mappingDataKeys, expr)
+	}
+	data.mappingData[expr] = mappingData
 }
 
 func (data *StaticCheckData) GetFnData(fnExpr *parse.FunctionExpression) *FunctionStaticData {
@@ -60,6 +60,7 @@ const (
 	DATABASES__DB_RESOURCE_SHOULD_BE_HOST_OR_URL                 = "the ." + MANIFEST_DATABASE__RESOURCE_PROP_NAME + " property of database descriptions in the '" + MANIFEST_DATABASES_SECTION_NAME + "' section (manifest) should be a Host or a URL"
 	DATABASES__DB_EXPECTED_SCHEMA_UPDATE_SHOULD_BE_BOOL_LIT      = "the ." + MANIFEST_DATABASE__EXPECTED_SCHEMA_UPDATE_PROP_NAME + " property of database descriptions in the '" + MANIFEST_DATABASES_SECTION_NAME + "' section (manifest) should be a boolean literal (the property is optional)"
 	DATABASES__DB_ASSERT_SCHEMA_SHOULD_BE_PATT_IDENT_OR_OBJ_PATT = "the ." + MANIFEST_DATABASE__ASSERT_SCHEMA_UPDATE_PROP_NAME + " property of database descriptions in the '" + MANIFEST_DATABASES_SECTION_NAME + "' section (manifest) should be a pattern identifier or an object pattern literal (the property is optional)"
+	DATABASES__DB_FORMAT_SHOULD_BE_A_FORMAT_IDENT                = "the ." + MANIFEST_DATABASE__FORMAT_PROP_NAME + " property of database descriptions in the '" + MANIFEST_DATABASES_SECTION_NAME + "' section (manifest) should be #json or #binary (the property is optional)"
 	DATABASES_SECTION_NOT_AVAILABLE_IN_EMBEDDED_MODULE_MANIFESTS = "the '" + MANIFEST_DATABASES_SECTION_NAME + "' section is not available in embedded module manifests"
 	DATABASES__DB_RESOLUTION_DATA_ONLY_NIL_AND_PATHS_SUPPORTED   = "nil and paths are the only supported values for ." + MANIFEST_DATABASE__RESOLUTION_DATA_PROP_NAME + " in a database description"
 
@@ -124,6 +125,8 @@ const (
 	MISPLACED_EXTEND_STATEMENT_TOP_LEVEL_STMT                      = "misplaced extend statement: it should be located at the top level"
 	MISPLACED_STRUCT_DEF_TOP_LEVEL_STMT                            = "misplaced struct definition: it should be located at the top level"
 
+	LAZY_PATTERN_DEFINITIONS_CANNOT_HAVE_TYPE_PARAMETERS = "lazy pattern definitions cannot have type parameters"
+
 	INVALID_MEM_HOST_ONLY_VALID_VALUE                                 = "invalid mem:// host, only valid value is " + MEM_HOSTNAME
 	LOWER_BOUND_OF_INT_RANGE_LIT_SHOULD_BE_SMALLER_THAN_UPPER_BOUND   = "the lower bound of an integer range literal should be smaller than the upper bound"
 	LOWER_BOUND_OF_FLOAT_RANGE_LIT_SHOULD_BE_SMALLER_THAN_UPPER_BOUND = "the lower bound of a float range literal should be smaller than the upper bound"
@@ -159,6 +162,8 @@ const (
 	TEST_CASES_NOT_ALLOWED_IF_SUBSUITES_ARE_PRESENT     = "test cases are not allowed if sub suites are presents"
 	TEST_CASE_STMTS_NOT_ALLOWED_OUTSIDE_OF_TEST_SUITES  = "test case statements are not allowed outside of test suites"
 	TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_TEST_CASE_STMTS = "test suite statements are not allowed in test case statements"
+	BENCHMARK_STMTS_NOT_ALLOWED_OUTSIDE_OF_TEST_SUITES  = "benchmark statements are not allowed outside of test suites"
+	TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_BENCHMARK_STMTS = "test suite statements are not allowed in benchmark statements"
 
 	//new expressions
 	A_STRUCT_TYPE_NAME_IS_EXPECTED = "a struct type name is expected"
@@ -334,6 +339,10 @@ func fmtPatternAlreadyDeclared(name string) string {
 	return fmt.Sprintf("pattern %%%s is already declared", name)
 }
 
+func fmtDuplicateTypeParameter(name string) string {
+	return fmt.Sprintf("duplicate type parameter %s", name)
+}
+
 func fmtPatternNamespaceAlreadyDeclared(name string) string {
 	return fmt.Sprintf("pattern namespace %%%s is already declared", name)
 }
@@ -1502,6 +1502,16 @@ func TestCheck(t *testing.T) {
 			assert.Equal(t, expectedErr, err)
 		})
 
+		t.Run("type parameter used in the signature and in the body", func(t *testing.T) {
+			n, src := mustParseCode(`
+				fn f<T>(a T) T {
+					var b T = a
+					return b
+				}
+			`)
+			assert.NoError(t, staticCheckNoData(StaticCheckInput{Node: n, Chunk: src}))
+		})
+
 		t.Run("captured variable should be accessible in body", func(t *testing.T) {
 			n, src := mustParseCode(`
 				a = 1
@@ -2473,6 +2483,59 @@ func TestCheck(t *testing.T) {
 		})
 	})
 
+	t.Run("benchmark statements", func(t *testing.T) {
+		t.Run("allowed in test suites", func(t *testing.T) {
+			n, src := mustParseCode(`
+				manifest {}
+
+				$$a = 1
+				testsuite {
+					benchmark {
+						a
+					}
+				}
+			`)
+
+			assert.NoError(t, staticCheckNoData(StaticCheckInput{Node: n, Chunk: src}))
+		})
+
+		t.Run(BENCHMARK_STMTS_NOT_ALLOWED_OUTSIDE_OF_TEST_SUITES, func(t *testing.T) {
+			n, src := mustParseCode(`
+				manifest {}
+
+				benchmark {}
+			`)
+
+			benchmarkStmt := parse.FindNode(n, (*parse.BenchmarkExpression)(nil), nil)
+			err := staticCheckNoData(StaticCheckInput{Node: n, Chunk: src})
+			expectedErr := utils.CombineErrors(
+				makeError(benchmarkStmt, src, BENCHMARK_STMTS_NOT_ALLOWED_OUTSIDE_OF_TEST_SUITES),
+			)
+			assert.Equal(t, expectedErr, err)
+		})
+
+		t.Run(TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_BENCHMARK_STMTS, func(t *testing.T) {
+			n, src := mustParseCode(`
+				manifest {}
+
+				testsuite {
+					benchmark {
+						testsuite {}
+					}
+				}
+			`)
+
+			benchmarkStmt := parse.FindNode(n, (*parse.BenchmarkExpression)(nil), nil)
+			testSuiteStmt := parse.FindNode(benchmarkStmt, (*parse.TestSuiteExpression)(nil), nil)
+
+			err := staticCheckNoData(StaticCheckInput{Node: n, Chunk: src})
+			expectedErr := utils.CombineErrors(
+				makeError(testSuiteStmt, src, TEST_SUITE_STMTS_NOT_ALLOWED_INSIDE_BENCHMARK_STMTS),
+			)
+			assert.Equal(t, expectedErr, err)
+		})
+	})
+
 	t.Run("testsuite expression", func(t *testing.T) {
 
 		t.Run("should have its own local scope", func(t *testing.T) {
@@ -3584,6 +3647,26 @@ func TestCheck(t *testing.T) {
 			)
 			assert.Equal(t, expectedErr, err)
 		})
+
+		t.Run("type parameters", func(t *testing.T) {
+			n, src := mustParseCode(`
+				pattern p<T> = {a: T}
+			`)
+			assert.NoError(t, staticCheckNoData(StaticCheckInput{Node: n, Chunk: src}))
+		})
+
+		t.Run("duplicate type parameter", func(t *testing.T) {
+			n, src := mustParseCode(`
+				pattern p<T, T> = {a: T}
+			`)
+			typeParam := parse.FindNodes(n, (*parse.TypeParameter)(nil), nil)[1]
+
+			err := staticCheckNoData(StaticCheckInput{Node: n, Chunk: src})
+			expectedErr := utils.CombineErrors(
+				makeError(typeParam.Name, src, fmtDuplicateTypeParameter("T")),
+			)
+			assert.Equal(t, expectedErr, err)
+		})
 	})
 
 	t.Run("pattern namespace definition", func(t *testing.T) {
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inoxlang/inox/internal/jsoniter"
+)
+
+const (
+	JSON_SERIALIZATION_FORMAT   SerializationFormat = iota //default format
+	BINARY_SERIALIZATION_FORMAT                            //see binary_representation.go
+)
+
+var (
+	ErrUnknownSerializationFormat = errors.New("unknown serialization format")
+
+	SERIALIZATION_FORMAT_NAMES = []string{
+		JSON_SERIALIZATION_FORMAT:   "json",
+		BINARY_SERIALIZATION_FORMAT: "binary",
+	}
+)
+
+// A SerializationFormat is the format of the representations of values stored in a DataStore.
+type SerializationFormat int
+
+func (f SerializationFormat) String() string {
+	if f < 0 || int(f) >= len(SERIALIZATION_FORMAT_NAMES) {
+		return "unknown"
+	}
+	return SERIALIZATION_FORMAT_NAMES[f]
+}
+
+func ParseSerializationFormat(name string) (SerializationFormat, error) {
+	for i, formatName := range SERIALIZATION_FORMAT_NAMES {
+		if formatName == name {
+			return SerializationFormat(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownSerializationFormat, name)
+}
+
+// A FormatAwareDataStore is a DataStore that specifies the serialization format of the values it stores,
+// the values of data stores not implementing this interface are in the JSON format.
+type FormatAwareDataStore interface {
+	DataStore
+	SerializationFormat() SerializationFormat
+}
+
+func GetDataStoreSerializationFormat(store DataStore) SerializationFormat {
+	if formatAware, ok := store.(FormatAwareDataStore); ok {
+		return formatAware.SerializationFormat()
+	}
+	return JSON_SERIALIZATION_FORMAT
+}
+
+// A StorageSequencePattern is implemented by the patterns of free entities (e.g. collections) that are stored as a
+// sequence of values rather than as a single value. The pattern of the i-th stored value is
+// StoredElementPatterns()[i % len(StoredElementPatterns())].
+type StorageSequencePattern interface {
+	Pattern
+
+	StoredElementPatterns() []Pattern
+}
+
+// GetStorageRepresentation returns the representation of v in the given format, all properties are visible.
+func GetStorageRepresentation(ctx *Context, v Serializable, pattern Pattern, format SerializationFormat) (string, error) {
+	switch format {
+	case JSON_SERIALIZATION_FORMAT:
+		return GetJSONRepresentationWithConfig(v, ctx, JSONSerializationConfig{
+			ReprConfig: ALL_VISIBLE_REPR_CONFIG,
+			Pattern:    pattern,
+		})
+	case BINARY_SERIALIZATION_FORMAT:
+		repr, err := GetBinaryRepresentation(v, ctx, BinarySerializationConfig{
+			ReprConfig: ALL_VISIBLE_REPR_CONFIG,
+			Pattern:    pattern,
+		})
+		return string(repr), err
+	default:
+		return "", ErrUnknownSerializationFormat
+	}
+}
+
+func ParseStorageRepresentation(ctx *Context, serialized string, pattern Pattern, format SerializationFormat) (Serializable, error) {
+	switch format {
+	case JSON_SERIALIZATION_FORMAT:
+		return ParseJSONRepresentation(ctx, serialized, pattern)
+	case BINARY_SERIALIZATION_FORMAT:
+		return ParseBinaryRepresentation(ctx, []byte(serialized), pattern)
+	default:
+		return nil, ErrUnknownSerializationFormat
+	}
+}
+
+// GetStorageSequenceRepresentation returns the representation of a sequence of values in the given format (an array),
+// the pattern of the i-th element is elementPatterns[i % len(elementPatterns)].
+func GetStorageSequenceRepresentation(ctx *Context, elements []Serializable, elementPatterns []Pattern, format SerializationFormat) (string, error) {
+	switch format {
+	case JSON_SERIALIZATION_FORMAT:
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 0)
+		stream.WriteArrayStart()
+		for i, elem := range elements {
+			if i != 0 {
+				stream.WriteMore()
+			}
+			err := elem.WriteJSONRepresentation(ctx, stream, JSONSerializationConfig{
+				ReprConfig: ALL_VISIBLE_REPR_CONFIG,
+				Pattern:    elementPatterns[i%len(elementPatterns)],
+			}, 1)
+			if err != nil {
+				return "", fmt.Errorf("failed to serialize element %d: %w", i, err)
+			}
+		}
+		stream.WriteArrayEnd()
+		return string(stream.Buffer()), nil
+	case BINARY_SERIALIZATION_FORMAT:
+		encoder := &binaryEncoder{ctx: ctx, reprConfig: ALL_VISIBLE_REPR_CONFIG}
+		encoder.writeHead(cborArray, uint64(len(elements)))
+		for i, elem := range elements {
+			if err := encoder.writeValue(elem, elementPatterns[i%len(elementPatterns)], 1); err != nil {
+				return "", fmt.Errorf("failed to serialize element %d: %w", i, err)
+			}
+		}
+		return string(encoder.buf), nil
+	default:
+		return "", ErrUnknownSerializationFormat
+	}
+}
+
+// ParseStorageSequenceRepresentation parses the representation of a sequence of values and calls fn for each element,
+// the pattern of the i-th element is elementPatterns[i % len(elementPatterns)]. The iteration stops if fn returns an error.
+func ParseStorageSequenceRepresentation(
+	ctx *Context, serialized string, elementPatterns []Pattern, format SerializationFormat,
+	fn func(index int, elem Serializable) error,
+) (finalErr error) {
+	switch format {
+	case JSON_SERIALIZATION_FORMAT:
+		index := 0
+		it := jsoniter.ParseString(jsoniter.ConfigDefault, serialized)
+		it.ReadArrayCB(func(it *jsoniter.Iterator) bool {
+			elem, err := ParseNextJSONRepresentation(ctx, it, elementPatterns[index%len(elementPatterns)], false)
+			if err != nil {
+				finalErr = fmt.Errorf("failed to parse element %d: %w", index, err)
+				return false
+			}
+			if err := fn(index, elem); err != nil {
+				finalErr = err
+				return false
+			}
+			index++
+			return true
+		})
+		if finalErr == nil && it.Error != nil && it.Error != io.EOF {
+			finalErr = it.Error
+		}
+		return
+	case BINARY_SERIALIZATION_FORMAT:
+		decoder := &binaryDecoder{ctx: ctx, data: []byte(serialized)}
+		length, err := decoder.readContainerLength(cborArray)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < length; i++ {
+			pattern := elementPatterns[i%len(elementPatterns)]
+
+			elem, err := decoder.readValue(pattern, 1)
+			if err != nil {
+				return fmt.Errorf("failed to parse element %d: %w", i, err)
+			}
+			if pattern != nil && !pattern.Test(ctx, elem) {
+				return fmt.Errorf("failed to parse element %d: %w", i, ErrBinaryReprNotMatchingPattern)
+			}
+			if err := fn(i, elem); err != nil {
+				return err
+			}
+		}
+		if decoder.pos != len(decoder.data) {
+			return fmt.Errorf("%w: unexpected data after the sequence", ErrInvalidBinaryRepresentation)
+		}
+		return nil
+	default:
+		return ErrUnknownSerializationFormat
+	}
+}
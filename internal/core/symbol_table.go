@@ -18,6 +18,7 @@ type symbol struct {
 // A symbolTable represents a symbol table for a single module during compilation.
 type symbolTable struct {
 	store           map[string]*symbol
+	allSymbols      []*symbol //includes shadowed symbols
 	nextSymbolIndex int
 }
 
@@ -37,6 +38,7 @@ func (t *symbolTable) Define(name string) *symbol {
 	t.nextSymbolIndex++
 
 	t.store[name] = symbol
+	t.allSymbols = append(t.allSymbols, symbol)
 	return symbol
 }
 
@@ -57,3 +59,13 @@ func (t *symbolTable) SymbolNames() []string {
 	}
 	return names
 }
+
+// NamesByIndex returns a slice containing the name of each symbol at its index,
+// the names of the symbols shadowed by a redefinition are kept.
+func (t *symbolTable) NamesByIndex() []string {
+	names := make([]string, t.nextSymbolIndex)
+	for _, symbol := range t.allSymbols {
+		names[symbol.Index] = symbol.Name
+	}
+	return names
+}
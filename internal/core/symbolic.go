@@ -532,6 +532,9 @@ func (e Error) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Va
 	if err != nil {
 		return nil, err
 	}
+	if e.kind != "" {
+		return symbolic.NewErrorWithKind(data, e.kind.UnderlyingString()), nil
+	}
 	return symbolic.NewError(data), nil
 }
 
@@ -1098,6 +1101,11 @@ func (p *DifferencePattern) ToSymbolicValue(ctx *Context, encountered map[uintpt
 	}, nil
 }
 
+func (p *GenericPattern) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
+	//type arguments are erased.
+	return p.erased.ToSymbolicValue(ctx, encountered)
+}
+
 func (p *OptionalPattern) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	ptr := reflect.ValueOf(p).Pointer()
 	if r, ok := encountered[ptr]; ok {
@@ -1140,6 +1148,10 @@ func (p *MutationPattern) ToSymbolicValue(ctx *Context, encountered map[uintptr]
 	return symbolic.NewMutationPattern(&symbolic.Int{}, data0Pattern.(symbolic.Pattern)), nil
 }
 
+func (p *ErrorPattern) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
+	return symbolic.NewErrorPattern(p.kind.UnderlyingString()), nil
+}
+
 func (p *ParserBasedPseudoPattern) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	ptr := reflect.ValueOf(p).Pointer()
 	if r, ok := encountered[ptr]; ok {
@@ -1452,6 +1464,10 @@ func (c *TestCase) ToSymbolicValue(ctx *Context, encountered map[uintptr]symboli
 	return &symbolic.TestCase{}, nil
 }
 
+func (b *Benchmark) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
+	return symbolic.ANY_BENCHMARK, nil
+}
+
 func (r *TestCaseResult) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	//TODO
 	return symbolic.ANY, nil
@@ -1621,6 +1637,18 @@ func (r *RingBuffer) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbo
 	return symbolic.ANY_RING_BUFFER, nil
 }
 
+func (c *Channel) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
+	pattern, err := c.elementPattern.ToSymbolicValue(ctx, encountered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert element pattern of channel to symbolic value: %w", err)
+	}
+	return symbolic.NewChannel(pattern.(symbolic.Pattern)), nil
+}
+
+func (it *ChannelIterator) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
+	return &symbolic.Iterator{}, nil
+}
+
 func (c *DataChunk) ToSymbolicValue(ctx *Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	data, err := c.data.ToSymbolicValue(ctx, encountered)
 	if err != nil {
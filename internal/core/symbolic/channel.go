@@ -0,0 +1,165 @@
+package symbolic
+
+import (
+	pprint "github.com/inoxlang/inox/internal/prettyprint"
+)
+
+var (
+	ANY_CHANNEL = &Channel{}
+
+	CHANNEL_PROPNAMES = []string{"send", "receive", "close", "is_closed"}
+
+	_ = []Iterable{(*Channel)(nil)}
+)
+
+// A Channel represents a symbolic Channel.
+type Channel struct {
+	UnassignablePropsMixin
+	elementPattern Pattern //if nil any serializable value can be sent
+	shared         bool
+}
+
+func NewChannel(elementPattern Pattern) *Channel {
+	return &Channel{elementPattern: elementPattern}
+}
+
+func (c *Channel) Test(v Value, state RecTestCallState) bool {
+	state.StartCall()
+	defer state.FinishCall()
+
+	otherChannel, ok := v.(*Channel)
+	if !ok {
+		return false
+	}
+	if c.elementPattern == nil {
+		return true
+	}
+	if otherChannel.elementPattern == nil {
+		return false
+	}
+	//channels are both read and written so their element types should be equivalent.
+	return c.elementPattern.Test(otherChannel.elementPattern, state) &&
+		otherChannel.elementPattern.Test(c.elementPattern, state)
+}
+
+// ElementValue returns a value that represents all values that can be received from the channel.
+func (c *Channel) ElementValue() Value {
+	if c.elementPattern == nil {
+		return ANY_SERIALIZABLE
+	}
+	return c.elementPattern.SymbolicValue()
+}
+
+func (c *Channel) Send(ctx *Context, v Value) *Error {
+	if c.elementPattern != nil && !c.elementPattern.TestValue(v, RecTestCallState{}) {
+		ctx.AddSymbolicGoFunctionError(fmtValueDoesNotMatchChannelElement(v, c.elementPattern))
+	}
+	return nil
+}
+
+func (c *Channel) Receive(ctx *Context) (Value, *Error) {
+	return c.ElementValue(), nil
+}
+
+func (c *Channel) Close(ctx *Context) {
+
+}
+
+func (c *Channel) IsClosed(ctx *Context) *Bool {
+	return ANY_BOOL
+}
+
+func (c *Channel) GetGoMethod(name string) (*GoFunction, bool) {
+	switch name {
+	case "send":
+		return WrapGoMethod(c.Send), true
+	case "receive":
+		return WrapGoMethod(c.Receive), true
+	case "close":
+		return WrapGoMethod(c.Close), true
+	case "is_closed":
+		return WrapGoMethod(c.IsClosed), true
+	}
+	return nil, false
+}
+
+func (c *Channel) Prop(name string) Value {
+	method, ok := c.GetGoMethod(name)
+	if !ok {
+		panic(FormatErrPropertyDoesNotExist(name, c))
+	}
+	return method
+}
+
+func (*Channel) PropertyNames() []string {
+	return CHANNEL_PROPNAMES
+}
+
+func (c *Channel) IteratorElementKey() Value {
+	return ANY_INT
+}
+
+func (c *Channel) IteratorElementValue() Value {
+	return c.ElementValue()
+}
+
+func (c *Channel) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
+	w.WriteName("channel")
+	if c.elementPattern != nil {
+		w.WriteString("(")
+		c.elementPattern.PrettyPrint(w.ZeroIndent(), config)
+		w.WriteString(")")
+	}
+}
+
+func (c *Channel) WidestOfType() Value {
+	return ANY_CHANNEL
+}
+
+func (c *Channel) IsSharable() (bool, string) {
+	return true, ""
+}
+
+func (c *Channel) Share(originState *State) PotentiallySharable {
+	copy := *c
+	copy.shared = true
+	return &copy
+}
+
+func (c *Channel) IsShared() bool {
+	return c.shared
+}
+
+// Select is the symbolic equivalent of core.Select.
+func Select(ctx *Context, args ...Value) (*Int, Value, *Error) {
+	var elements []Value
+	hasTimeout := false
+
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case *Channel:
+			elements = append(elements, a.ElementValue())
+		case *Duration:
+			if i != len(args)-1 {
+				ctx.AddSymbolicGoFunctionError(SELECT_TIMEOUT_SHOULD_BE_LAST_ARG)
+			}
+			hasTimeout = true
+		default:
+			ctx.AddSymbolicGoFunctionError(SELECT_ARGS_SHOULD_BE_CHANNELS)
+		}
+	}
+
+	if len(elements) == 0 {
+		ctx.AddSymbolicGoFunctionError(AT_LEAST_ONE_CHANNEL_SHOULD_BE_SELECTED)
+		return ANY_INT, ANY, nil
+	}
+
+	if hasTimeout {
+		elements = append(elements, Nil)
+	}
+
+	if len(elements) == 1 {
+		return ANY_INT, elements[0], nil
+	}
+	return ANY_INT, NewMultivalue(elements...), nil
+}
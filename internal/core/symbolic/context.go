@@ -228,6 +228,13 @@ func (ctx *Context) AddSymbolicGoFunctionWarning(msg string) {
 	ctx.associatedState.addSymbolicGoFunctionWarning(msg)
 }
 
+// AddMissingPermissionWarning adds a warning about a permission that might be missing, the permission
+// is also recorded in the symbolic data (see Data.GetMissingPermissions).
+func (ctx *Context) AddMissingPermissionWarning(kind permkind.PermissionKind, typename permkind.InternalPermissionTypename, msg string) {
+	ctx.associatedState.addSymbolicGoFunctionWarning(msg)
+	ctx.associatedState.addSymbolicGoFunctionMissingPermission(MissingPermission{Kind: kind, TypeName: typename})
+}
+
 func (ctx *Context) AddFormattedSymbolicGoFunctionError(format string, args ...any) {
 	ctx.associatedState.addSymbolicGoFunctionError(fmt.Sprintf(format, args...))
 }
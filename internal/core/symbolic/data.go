@@ -2,8 +2,10 @@ package symbolic
 
 import (
 	"errors"
+	"slices"
 	"sort"
 
+	"github.com/inoxlang/inox/internal/core/permkind"
 	"github.com/inoxlang/inox/internal/parse"
 	pprint "github.com/inoxlang/inox/internal/prettyprint"
 	"github.com/inoxlang/inox/internal/utils"
@@ -28,6 +30,7 @@ type Data struct {
 	usedTypeExtensions          map[*parse.DoubleColonExpression]*TypeExtension
 	typeExtensions              map[*parse.DoubleColonExpression][]*TypeExtension
 	urlReferencedEntities       map[*parse.DoubleColonExpression]Value
+	missingPermissions          map[parse.Node][]MissingPermission
 
 	comptimeTypes map[ /* *Chunk or *EmbeddModule */ parse.Node]*ModuleCompileTimeTypes
 
@@ -36,6 +39,13 @@ type Data struct {
 
 	warningMessageSet map[string]bool
 	warnings          []SymbolicEvaluationWarning
+
+	//see EvalCheckInput.SaveTopLevelCheckpoints.
+	changeRecording     bool
+	changes             []dataChange
+	checkpointedChunk   *parse.Chunk
+	checkpointedState   *State
+	topLevelCheckpoints []*topLevelCheckpoint
 }
 
 func NewSymbolicData() *Data {
@@ -51,6 +61,7 @@ func NewSymbolicData() *Data {
 		usedTypeExtensions:          make(map[*parse.DoubleColonExpression]*TypeExtension, 0),
 		typeExtensions:              make(map[*parse.DoubleColonExpression][]*TypeExtension, 0),
 		urlReferencedEntities:       make(map[*parse.DoubleColonExpression]Value, 0),
+		missingPermissions:          make(map[parse.Node][]MissingPermission, 0),
 
 		comptimeTypes: make(map[parse.Node]*ModuleCompileTimeTypes, 0),
 
@@ -64,6 +75,8 @@ func (data *Data) IsEmpty() bool {
 }
 
 func (data *Data) AddError(err SymbolicEvaluationError) {
+	data.recordChange(errorAddition, nil, err)
+
 	if data.errorMessageSet[err.Error()] {
 		return
 	}
@@ -73,6 +86,8 @@ func (data *Data) AddError(err SymbolicEvaluationError) {
 }
 
 func (data *Data) AddWarning(warning SymbolicEvaluationWarning) {
+	data.recordChange(warningAddition, nil, warning)
+
 	if warning.LocatedMessage != "" {
 		if data.warningMessageSet[warning.LocatedMessage] {
 			return
@@ -86,6 +101,7 @@ func (data *Data) SetMostSpecificNodeValue(node parse.Node, v Value) {
 	if data == nil {
 		return
 	}
+	data.recordChange(mostSpecificNodeValueUpdate, node, v)
 
 	_, ok := data.mostSpecificNodeValues[node]
 	if ok {
@@ -106,7 +122,11 @@ func (data *Data) SetLessSpecificNodeValue(node parse.Node, v Value) {
 	if data == nil {
 		return
 	}
+	data.recordChange(lessSpecificNodeValueUpdate, node, v)
+	data.setLessSpecificNodeValue(node, v)
+}
 
+func (data *Data) setLessSpecificNodeValue(node parse.Node, v Value) {
 	_, ok := data.lessSpecificNodeValues[node]
 	if ok {
 		//TODO:
@@ -126,11 +146,12 @@ func (data *Data) PushNodeValue(node parse.Node, v Value) {
 	if data == nil {
 		return
 	}
+	data.recordChange(nodeValuePush, node, v)
 
 	prev, ok := data.mostSpecificNodeValues[node]
 	if ok {
 		data.mostSpecificNodeValues[node] = v
-		data.SetLessSpecificNodeValue(node, prev)
+		data.setLessSpecificNodeValue(node, prev)
 		return
 	}
 
@@ -141,6 +162,7 @@ func (data *Data) SetRuntimeTypecheckPattern(node parse.Node, pattern any) {
 	if data == nil {
 		return
 	}
+	data.recordChange(runtimeTypecheckPatternUpdate, node, pattern)
 
 	_, ok := data.runtimeTypeCheckPatterns[node]
 	if ok {
@@ -159,6 +181,7 @@ func (data *Data) SetAllowedNonPresentProperties(node parse.Node, properties []s
 	if data == nil {
 		return
 	}
+	data.recordChange(allowedNonPresentPropertiesUpdate, node, properties)
 	sort.Strings(properties)
 	data.allowedNonPresentProperties[node] = properties
 }
@@ -172,6 +195,7 @@ func (data *Data) SetAllowedNonPresentKeys(node parse.Node, keys []string) {
 	if data == nil {
 		return
 	}
+	data.recordChange(allowedNonPresentKeysUpdate, node, keys)
 	sort.Strings(keys)
 	data.allowedNonPresentKeys[node] = keys
 }
@@ -234,6 +258,12 @@ func (data *Data) AddData(newData *Data) {
 		data.SetURLReferencedEntity(k, v)
 	}
 
+	for k, permissions := range newData.missingPermissions {
+		for _, perm := range permissions {
+			data.AddMissingPermission(k, perm)
+		}
+	}
+
 	data.errors = append(data.errors, newData.errors...)
 	data.warnings = append(data.warnings, newData.warnings...)
 }
@@ -345,6 +375,7 @@ func (d *Data) SetLocalScopeData(n parse.Node, scopeData ScopeData) {
 	if d == nil {
 		return
 	}
+	d.recordChange(localScopeDataUpdate, n, scopeData)
 
 	_, ok := d.localScopeData[n]
 	if ok {
@@ -359,6 +390,7 @@ func (d *Data) SetGlobalScopeData(n parse.Node, scopeData ScopeData) {
 	if d == nil {
 		return
 	}
+	d.recordChange(globalScopeDataUpdate, n, scopeData)
 
 	_, ok := d.globalScopeData[n]
 	if ok {
@@ -372,6 +404,7 @@ func (d *Data) SetContextData(n parse.Node, contextData ContextData) {
 	if d == nil {
 		return
 	}
+	d.recordChange(contextDataUpdate, n, contextData)
 
 	_, ok := d.contextData[n]
 	if ok {
@@ -390,6 +423,7 @@ func (d *Data) SetUsedTypeExtension(n *parse.DoubleColonExpression, ext *TypeExt
 	if d == nil {
 		return
 	}
+	d.recordChange(usedTypeExtensionUpdate, n, ext)
 
 	_, ok := d.usedTypeExtensions[n]
 	if ok {
@@ -408,6 +442,7 @@ func (d *Data) SetAllTypeExtensions(n *parse.DoubleColonExpression, extensions [
 	if d == nil {
 		return
 	}
+	d.recordChange(allTypeExtensionsUpdate, n, extensions)
 
 	_, ok := d.typeExtensions[n]
 	if ok {
@@ -426,6 +461,7 @@ func (d *Data) SetURLReferencedEntity(n *parse.DoubleColonExpression, value Valu
 	if d == nil {
 		return
 	}
+	d.recordChange(urlReferencedEntityUpdate, n, value)
 
 	_, ok := d.urlReferencedEntities[n]
 	if ok {
@@ -435,6 +471,26 @@ func (d *Data) SetURLReferencedEntity(n *parse.DoubleColonExpression, value Valu
 	d.urlReferencedEntities[n] = value
 }
 
+// GetMissingPermissions returns the permissions that might be missing in order to evaluate a node (call).
+func (d *Data) GetMissingPermissions(n parse.Node) ([]MissingPermission, bool) {
+	permissions, ok := d.missingPermissions[n]
+	return permissions, ok
+}
+
+func (d *Data) AddMissingPermission(n parse.Node, perm MissingPermission) {
+	if d == nil {
+		return
+	}
+	d.recordChange(missingPermissionAddition, n, perm)
+
+	permissions := d.missingPermissions[n]
+	if slices.Contains(permissions, perm) {
+		return
+	}
+
+	d.missingPermissions[n] = append(permissions, perm)
+}
+
 func (d *Data) GetVariableDefinitionPosition(node parse.Node, ancestors []parse.Node) (pos parse.SourcePositionRange, found bool) {
 
 	var data ScopeData
@@ -561,6 +617,7 @@ func (d *Data) getModuleComptimeTypes(module parse.Node, create bool) *ModuleCom
 		types = NewModuleCompileTimeTypes()
 
 		d.comptimeTypes[module] = types
+		d.recordChange(comptimeTypesCreation, module, types)
 	}
 
 	return types
@@ -584,6 +641,18 @@ type VarData struct {
 	Name               string
 	Value              Value
 	DefinitionPosition parse.SourcePositionRange
+	IsConstant         bool
+}
+
+// A MissingPermission is a permission that might be missing, it is described by its kind and type because
+// the exact permission is generally not known during symbolic evaluation.
+type MissingPermission struct {
+	Kind     permkind.PermissionKind
+	TypeName permkind.InternalPermissionTypename
+}
+
+func (p MissingPermission) String() string {
+	return p.Kind.String() + " " + string(p.TypeName)
 }
 
 type ContextData struct {
@@ -35,6 +35,18 @@ const (
 	//object pattern
 	PROPERTY_PATTERNS_IN_OBJECT_AND_REC_PATTERNS_MUST_HAVE_SERIALIZABLE_VALUEs = "property patterns in object and record patterns must have serializable values"
 
+	//generics
+	GENERIC_PATTERNS_SHOULD_BE_INSTANTIATED = "generic patterns should be instantiated with type arguments (e.g. %Paginated(%int))"
+	TYPE_ARGUMENTS_SHOULD_BE_PATTERNS       = "type arguments should be patterns"
+
+	//channels
+	SELECT_ARGS_SHOULD_BE_CHANNELS          = "select arguments should be channels, optionally followed by a timeout (duration)"
+	SELECT_TIMEOUT_SHOULD_BE_LAST_ARG       = "the timeout should be the last argument of select"
+	AT_LEAST_ONE_CHANNEL_SHOULD_BE_SELECTED = "at least one channel should be provided"
+
+	//lthread scopes
+	LTHREAD_SCOPE_FN_SHOULD_HAVE_NO_PARAMS = "the function passed to lthread_scope should have no parameters"
+
 	CANNOT_ADD_NEW_PROPERTY_TO_AN_EXACT_OBJECT = "cannot add new property to an exact object"
 
 	MISSING_RETURN_IN_FUNCTION                                                   = "missing return in function"
@@ -147,10 +159,16 @@ const (
 	//test suites & cases
 	META_VAL_OF_TEST_SUITE_SHOULD_EITHER_BE_A_STRING_OR_A_RECORD    = "the meta value of a test suite should either be a string or an object (e.g. {name: \"my test suite\"})"
 	META_VAL_OF_TEST_CASE_SHOULD_EITHER_BE_A_STRING_OR_A_RECORD     = "the meta value of a test case should either be a string or an object (e.g. {name: \"my test suite\"})"
+	META_VAL_OF_BENCHMARK_SHOULD_EITHER_BE_A_STRING_OR_A_RECORD     = "the meta value of a benchmark should either be a string or an object (e.g. {name: \"my benchmark\"})"
 	PROGRAM_TESTING_ONLY_SUPPORTED_IN_PROJECTS                      = "program testing is only supported in projects"
 	MAIN_DB_SCHEMA_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM     = "main database schema can only be specified when testing a program"
 	MAIN_DB_MIGRATIONS_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM = "main database migrations can only be specified when testing a program"
 	MISSING_MAIN_DB_MIGRATIONS_PROPERTY                             = "missing property: '" + TEST_ITEM_META__MAIN_DB_MIGRATIONS + "'"
+	MAIN_DB_FIXTURES_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM   = "main database fixtures can only be specified when testing a program"
+	MAIN_DB_FIXTURES_REQUIRE_A_MAIN_DB_SCHEMA                       = "main database fixtures require the '" + TEST_ITEM_META__MAIN_DB_SCHEMA + "' property to be present"
+	INVALID_MAIN_DB_FIXTURES_FILE_EXTENSION                         = "the main database fixtures should be located in a " + DB_FIXTURES_JSON_FILE_EXTENSION + " or " + DB_FIXTURES_INOX_FILE_EXTENSION + " file"
+	PARALLELISM_CAN_ONLY_BE_SPECIFIED_BY_TEST_SUITES                = "the '" + TEST_SUITE_META__PARALLEL_PROPNAME + "' property can only be specified by test suites"
+	PARALLELISM_SHOULD_BE_POSITIVE                                  = "the value of the '" + TEST_SUITE_META__PARALLEL_PROPNAME + "' property should be a positive integer"
 
 	RIGHT_OPERAND_MAY_NOT_HAVE_A_URL = "right operand may not have a URL"
 
@@ -456,6 +474,20 @@ func fmtRHSSequenceShouldHaveLenOf(length int) string {
 	return fmt.Sprintf("sequence on the right hand side should have a length of %d", length)
 }
 
+func fmtInvalidNumberOfTypeArgs(actual, expected int) string {
+	return fmt.Sprintf("invalid number of type arguments: %v, %v were expected", actual, expected)
+}
+
+func fmtValueDoesNotMatchChannelElement(v Value, elementPattern Pattern) string {
+	return fmt.Sprintf("value does not match the element pattern of the channel: type is %s, but a value matching %s was expected",
+		Stringify(v), Stringify(elementPattern))
+}
+
+func fmtTypeArgDoesNotSatisfyConstraint(typeParamName string, typeArg, constraint Value) string {
+	return fmt.Sprintf("type argument for %%%s does not satisfy its constraint: type is %s, but %s was expected",
+		typeParamName, Stringify(typeArg), Stringify(constraint))
+}
+
 func fmtPatternIsNotDeclared(name string) string {
 	return fmt.Sprintf("pattern %%%s is not declared", name)
 }
@@ -688,6 +720,10 @@ func fmtNotRegularFile(path string) string {
 	return fmt.Sprintf("%q is not a regular file", path)
 }
 
+func fmtMainDatabaseFixturesFileNotFound(path string) string {
+	return fmt.Sprintf("the main database fixtures file %q does not exist or is not a regular file", path)
+}
+
 func fmtValueAtURLHasNoProperties(value Value) string {
 	return fmt.Sprintf("value at url has no properties, type is %s", Stringify(value))
 }
@@ -739,3 +775,11 @@ func fmtExpectedLeftOperandForArithmetic(left Value, operator parse.BinaryOperat
 	}
 	return fmtLeftOperandOfBinaryShouldBe(operator, s, Stringify(left))
 }
+
+func fmtBenchmarkMetaPropShouldNotBeNegative(propName string) string {
+	return fmt.Sprintf("the .%s property of a benchmark's meta value should not be negative", propName)
+}
+
+func fmtErrorKindsNotHandledByMatchStatement(kinds []string) string {
+	return fmt.Sprintf("the following error kinds are not handled: #%s; add a case for each kind or a default case", strings.Join(kinds, ", #"))
+}
@@ -1,16 +1,26 @@
 package symbolic
 
 import (
+	"slices"
+
 	pprint "github.com/inoxlang/inox/internal/prettyprint"
 )
 
 var (
-	ERR_PROPNAMES = []string{"text", "data"}
+	ERR_PROPNAMES = []string{"text", "data", "kind", "cause", "stack"}
 	ANY_ERR       = &Error{data: ANY}
+
+	ERROR_STACK_FRAME_RECORD = NewExactRecord(map[string]Serializable{
+		"chunk":    ANY_STRING,
+		"line":     ANY_INT,
+		"column":   ANY_INT,
+		"function": ANY_STRING,
+	}, nil)
 )
 
 type Error struct {
 	data Value
+	kind string //empty if the kind is unknown or if the error has no kind
 	UnassignablePropsMixin
 	SerializableMixin
 }
@@ -19,17 +29,32 @@ func NewError(data Value) *Error {
 	return &Error{data: data}
 }
 
+func NewErrorWithKind(data Value, kind string) *Error {
+	return &Error{data: data, kind: kind}
+}
+
+// Kind returns the kind of the error, ok is false if the kind is unknown or if the error has no kind.
+func (e *Error) Kind() (kind string, ok bool) {
+	return e.kind, e.kind != ""
+}
+
 func (e *Error) Test(v Value, state RecTestCallState) bool {
 	state.StartCall()
 	defer state.FinishCall()
 
 	otherError, ok := v.(*Error)
 
-	return ok && e.data.Test(otherError.data, state)
+	return ok && e.data.Test(otherError.data, state) && (e.kind == "" || e.kind == otherError.kind)
 }
 
 func (e *Error) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
-	w.WriteName("error")
+	if e.kind == "" {
+		w.WriteName("error")
+		return
+	}
+	w.WriteName("error(")
+	w.WriteStringF("#%s", e.kind)
+	w.WriteByte(')')
 }
 
 func (e *Error) WidestOfType() Value {
@@ -42,6 +67,15 @@ func (e *Error) Prop(name string) Value {
 		return ANY_STR_LIKE
 	case "data":
 		return e.data
+	case "kind":
+		if e.kind != "" {
+			return NewIdentifier(e.kind)
+		}
+		return NewMultivalue(ANY_IDENTIFIER, Nil)
+	case "cause":
+		return NewMultivalue(ANY_ERR, Nil)
+	case "stack":
+		return NewTupleOf(ERROR_STACK_FRAME_RECORD)
 	}
 	panic(FormatErrPropertyDoesNotExist(name, e))
 }
@@ -49,3 +83,58 @@ func (e *Error) Prop(name string) Value {
 func (*Error) PropertyNames() []string {
 	return ERR_PROPNAMES
 }
+
+// GetErrorKinds returns the sorted list of the known kinds of the errors that v can be, v is usually the return
+// value of a function.
+func GetErrorKinds(v Value) []string {
+	var kinds []string
+
+	addKind := func(v Value) {
+		if err, ok := v.(*Error); ok && err.kind != "" && !slices.Contains(kinds, err.kind) {
+			kinds = append(kinds, err.kind)
+		}
+	}
+
+	switch val := v.(type) {
+	case IMultivalue:
+		for _, value := range val.OriginalMultivalue().getValues() {
+			addKind(value)
+		}
+	case *Array:
+		if val.HasKnownLen() && val.KnownLen() > 0 {
+			return GetErrorKinds(val.ElementAt(val.KnownLen() - 1))
+		}
+	default:
+		addKind(v)
+	}
+
+	slices.Sort(kinds)
+	return kinds
+}
+
+// getErrorsOfKind returns the errors that v can be and that have the given kind, ok is false if there are none.
+func getErrorsOfKind(v Value, kind string) (_ Value, ok bool) {
+	if kind == "" {
+		return nil, false
+	}
+
+	var errors []Value
+	addError := func(v Value) {
+		if err, ok := v.(*Error); ok && err.kind == kind {
+			errors = append(errors, err)
+		}
+	}
+
+	if multi, ok := v.(IMultivalue); ok {
+		for _, value := range multi.OriginalMultivalue().getValues() {
+			addError(value)
+		}
+	} else {
+		addError(v)
+	}
+
+	if len(errors) == 0 {
+		return nil, false
+	}
+	return joinValues(errors), true
+}
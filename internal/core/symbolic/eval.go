@@ -117,6 +117,19 @@ type EvalCheckInput struct {
 	//nil if no project
 	ProjectFilesystem billy.Filesystem
 
+	//If true the state of the evaluation is saved before each top level statement of Node, the returned data can
+	//then be passed as PreviousData to reuse the evaluation of the first statements.
+	SaveTopLevelCheckpoints bool
+
+	//If SaveTopLevelCheckpoints is true and ReusedTopLevelStatementCount is not zero the evaluation of the first
+	//ReusedTopLevelStatementCount top level statements of PreviousData's chunk is reused. The caller should make sure
+	//that the previous evaluation had the same inputs, that the reused statements are the first statements of Node,
+	//that all struct definitions are reused statements and that the other parts of the chunk (manifest, ...) and the
+	//included chunks are the same nodes. PreviousData is updated and returned by EvalCheck, it should not be used
+	//by the caller after the call.
+	PreviousData                 *Data
+	ReusedTopLevelStatementCount int
+
 	importPositions     []parse.SourcePositionRange
 	initialSymbolicData *Data
 }
@@ -183,6 +196,14 @@ func EvalCheck(input EvalCheckInput) (*Data, error) {
 		state.symbolicData = NewSymbolicData()
 	}
 
+	if input.SaveTopLevelCheckpoints {
+		var err error
+		state, err = initTopLevelCheckpointing(input, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	_, err := symbolicEval(input.Node, state)
 
 	finalErrBuff := bytes.NewBuffer(nil)
@@ -220,6 +241,9 @@ type evalOptions struct {
 	doubleColonExprAncestorChain []parse.Node
 
 	neverModifiedArgument bool
+
+	//true if the evaluated node is the callee of a pattern call, generic patterns are only allowed as callees.
+	isPatternCallCallee bool
 }
 
 func (opts evalOptions) setActualValueMismatchIfNotNil() {
@@ -833,6 +857,15 @@ func _symbolicEval(node parse.Node, state *State, options evalOptions) (result V
 
 		return ANY_BOOL, nil
 	case *parse.PatternIdentifierLiteral:
+		if n.TypeParameter != nil {
+			patt, ok := state.typeParameterBindings[n.TypeParameter]
+			if !ok {
+				//should not happen because type parameters are bound during the evaluation of definitions and calls.
+				return &TypePattern{val: ANY_SERIALIZABLE}, nil
+			}
+			return patt, nil
+		}
+
 		patt := state.ctx.ResolveNamedPattern(n.Name)
 		if patt == nil {
 			names := state.ctx.AllNamedPatternNames()
@@ -848,11 +881,21 @@ func _symbolicEval(node parse.Node, state *State, options evalOptions) (result V
 
 			state.addError(makeSymbolicEvalError(node, state, msg))
 			return ANY_PATTERN, nil
+		} else if _, ok := patt.(*GenericPattern); ok && !options.isPatternCallCallee {
+			state.addError(makeSymbolicEvalError(node, state, GENERIC_PATTERNS_SHOULD_BE_INSTANTIATED))
+			return ANY_PATTERN, nil
 		} else {
 			return patt, nil
 		}
 	case *parse.PatternDefinition:
-		pattern, err := evalPatternNode(n.Right, state)
+		var pattern Pattern
+		var err error
+
+		if len(n.TypeParameters) > 0 {
+			pattern, err = evalGenericPatternDefinition(n, state)
+		} else {
+			pattern, err = evalPatternNode(n.Right, state)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -997,6 +1040,8 @@ func _symbolicEval(node parse.Node, state *State, options evalOptions) (result V
 		return evalTestsuiteExpression(n, state, options)
 	case *parse.TestCaseExpression:
 		return evalTestcaseExpression(n, state, options)
+	case *parse.BenchmarkExpression:
+		return evalBenchmarkExpression(n, state, options)
 	case *parse.LifetimejobExpression:
 		return evalLifetimejobExpression(n, state, options)
 	case *parse.ReceptionHandlerExpression:
@@ -1084,16 +1129,90 @@ func evalChunk(n *parse.Chunk, state *State) (_ Value, finalErr error) {
 		defer state.unsetSelf()
 	}
 
+	checkpointed := state.isCheckpointedChunk(n)
+
+	if checkpointed && state.checkpointing.resumedCheckpoint != nil {
+		//the evaluation resumes before the first non-reused statement.
+		state.restoreTopLevelCheckpoint(state.checkpointing.resumedCheckpoint)
+	} else {
+		if err := evalGlobalConstantsPreinitAndManifest(n, state); err != nil {
+			return nil, err
+		}
+
+		state.symbolicData.SetGlobalScopeData(n, state.currentGlobalScopeData())
+		state.symbolicData.SetContextData(n, state.ctx.currentData())
+
+		//register all structs defined in the current module
+
+		if n == state.Module.mainChunk.Node {
+			defineStructs(state.Module.mainChunk, n.Statements, state)
+		}
+	}
+
+	//evaluation of statements
+	if len(n.Statements) == 1 {
+		res, err := symbolicEval(n.Statements[0], state)
+		if err != nil {
+			return nil, err
+		}
+		checkCallExprWithUnhandledError(n.Statements[0], res, state)
+		if state.returnValue != nil && !state.conditionalReturn {
+			return state.returnValue, nil
+		}
+
+		if res == nil && state.returnValue != nil {
+			return joinValues([]Value{state.returnValue, Nil}), nil
+		}
+		return res, nil
+	}
+
+	var returnValue Value
+	for i, stmt := range n.Statements {
+		if checkpointed {
+			if i < state.checkpointing.skippedStatementCount {
+				continue
+			}
+			if returnValue == nil && state.returnValue == nil {
+				state.saveTopLevelCheckpoint()
+			}
+		}
+
+		res, err := symbolicEval(stmt, state)
+
+		if err != nil {
+			return nil, err
+		}
+
+		checkCallExprWithUnhandledError(stmt, res, state)
+
+		if state.returnValue != nil {
+			if state.conditionalReturn {
+				returnValue = state.returnValue
+				continue
+			}
+			return state.returnValue, nil
+		}
+	}
+
+	if checkpointed && returnValue == nil && state.returnValue == nil {
+		state.saveTopLevelCheckpoint()
+	}
+
+	return returnValue, nil
+}
+
+// evalGlobalConstantsPreinitAndManifest evaluates the global constants, the preinit block and the manifest of a chunk.
+func evalGlobalConstantsPreinitAndManifest(n *parse.Chunk, state *State) error {
 	//evaluation of constants
 	if n.GlobalConstantDeclarations != nil {
 		for _, decl := range n.GlobalConstantDeclarations.Declarations {
 			constVal, err := symbolicEval(decl.Right, state)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			state.symbolicData.SetMostSpecificNodeValue(decl.Left, constVal)
 			if !state.setGlobal(decl.Ident().Name, constVal, GlobalConst, decl.Left) {
-				return nil, fmt.Errorf("failed to set global '%s'", decl.Ident().Name)
+				return fmt.Errorf("failed to set global '%s'", decl.Ident().Name)
 			}
 		}
 	}
@@ -1105,10 +1224,10 @@ func evalChunk(n *parse.Chunk, state *State) (_ Value, finalErr error) {
 			_, err := symbolicEval(stmt, state)
 
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if state.returnValue != nil {
-				return nil, fmt.Errorf("preinit block should not return")
+				return fmt.Errorf("preinit block should not return")
 			}
 		}
 		state.inPreinit = false
@@ -1118,7 +1237,7 @@ func evalChunk(n *parse.Chunk, state *State) (_ Value, finalErr error) {
 	if n.Manifest != nil {
 		manifestObject, err := symbolicEval(n.Manifest.Object, state)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		//if the manifest object has the correct AND the module arguments variable is not already defined
@@ -1144,52 +1263,7 @@ func evalChunk(n *parse.Chunk, state *State) (_ Value, finalErr error) {
 		}
 	}
 
-	state.symbolicData.SetGlobalScopeData(n, state.currentGlobalScopeData())
-	state.symbolicData.SetContextData(n, state.ctx.currentData())
-
-	//register all structs defined in the current module
-
-	if n == state.Module.mainChunk.Node {
-		defineStructs(state.Module.mainChunk, n.Statements, state)
-	}
-
-	//evaluation of statements
-	if len(n.Statements) == 1 {
-		res, err := symbolicEval(n.Statements[0], state)
-		if err != nil {
-			return nil, err
-		}
-		checkCallExprWithUnhandledError(n.Statements[0], res, state)
-		if state.returnValue != nil && !state.conditionalReturn {
-			return state.returnValue, nil
-		}
-
-		if res == nil && state.returnValue != nil {
-			return joinValues([]Value{state.returnValue, Nil}), nil
-		}
-		return res, nil
-	}
-
-	var returnValue Value
-	for _, stmt := range n.Statements {
-		res, err := symbolicEval(stmt, state)
-
-		if err != nil {
-			return nil, err
-		}
-
-		checkCallExprWithUnhandledError(stmt, res, state)
-
-		if state.returnValue != nil {
-			if state.conditionalReturn {
-				returnValue = state.returnValue
-				continue
-			}
-			return state.returnValue, nil
-		}
-	}
-
-	return returnValue, nil
+	return nil
 }
 
 func evalURLExpression(n *parse.URLExpression, state *State) (_ Value, finalErr error) {
@@ -1278,7 +1352,7 @@ func evalReturnStatement(n *parse.ReturnStatement, state *State) (_ Value, final
 }
 
 func evalPatternCallExpression(n *parse.PatternCallExpression, state *State) (_ Value, finalErr error) {
-	callee, err := symbolicEval(n.Callee, state)
+	callee, err := _symbolicEval(n.Callee, state, evalOptions{isPatternCallCallee: true})
 	if err != nil {
 		return nil, err
 	}
@@ -1303,6 +1377,7 @@ func evalPatternCallExpression(n *parse.PatternCallExpression, state *State) (_
 		state.consumeSymbolicGoFunctionWarnings(func(msg string) {
 			state.addWarning(makeSymbolicEvalWarning(n, state, msg))
 		})
+		state.consumeSymbolicGoFunctionMissingPermissions(n)
 
 		if err != nil {
 			state.addError(makeSymbolicEvalError(n, state, err.Error()))
@@ -1313,6 +1388,52 @@ func evalPatternCallExpression(n *parse.PatternCallExpression, state *State) (_
 	return ANY_PATTERN, nil
 }
 
+// evalTypeParameterConstraints evaluates the constraints of type parameters and binds each type parameter
+// to its constraint in definitionState. Type parameters without a constraint are constrained to serializable values.
+func evalTypeParameterConstraints(typeParams []*parse.TypeParameter, definitionState *State) ([]Pattern, error) {
+	var constraints []Pattern
+
+	for _, typeParam := range typeParams {
+		var constraint Pattern = &TypePattern{val: ANY_SERIALIZABLE}
+
+		if typeParam.Constraint != nil {
+			pattern, err := evalPatternNode(typeParam.Constraint, definitionState)
+			if err != nil {
+				return nil, err
+			}
+			constraint = pattern
+		}
+
+		definitionState.symbolicData.SetMostSpecificNodeValue(typeParam.Name, constraint)
+		definitionState.typeParameterBindings = definitionState.bindTypeParameters([]*parse.TypeParameter{typeParam}, []Pattern{constraint})
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
+func evalGenericPatternDefinition(n *parse.PatternDefinition, state *State) (*GenericPattern, error) {
+	definitionState := state.fork()
+
+	constraints, err := evalTypeParameterConstraints(n.TypeParameters, definitionState)
+	if err != nil {
+		return nil, err
+	}
+
+	//the right side is checked with the type parameters bound to their constraint.
+	erased, err := evalPatternNode(n.Right, definitionState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenericPattern{
+		node:            n,
+		constraints:     constraints,
+		erased:          erased,
+		definitionState: definitionState,
+	}, nil
+}
+
 func evalLocalVariableDeclarations(n *parse.LocalVariableDeclarations, state *State) (finalErr error) {
 	for _, decl := range n.Declarations {
 		name := decl.Left.(*parse.IdentifierLiteral).Name
@@ -2575,6 +2696,10 @@ func evalMatchStatement(n *parse.MatchStatement, state *State) (_ Value, finalEr
 	var forks []*State
 	var possibleValues []Value
 
+	//error kinds handled by the cases, used to report unhandled kinds.
+	var handledErrorKinds []string
+	allErrorKindsHandled := false
+
 	for _, matchCase := range n.Cases {
 		for _, valNode := range matchCase.Values { //TODO: fix handling of multi cases
 			if valNode.Base().Err != nil {
@@ -2610,6 +2735,14 @@ func evalMatchStatement(n *parse.MatchStatement, state *State) (_ Value, finalEr
 				}
 			}
 
+			if err, ok := pattern.SymbolicValue().(*Error); ok {
+				if kind, ok := err.Kind(); ok {
+					handledErrorKinds = append(handledErrorKinds, kind)
+				} else {
+					allErrorKindsHandled = true
+				}
+			}
+
 			if matchCase.Block == nil {
 				continue
 			}
@@ -2617,6 +2750,13 @@ func evalMatchStatement(n *parse.MatchStatement, state *State) (_ Value, finalEr
 			blockStateFork := state.fork()
 			forks = append(forks, blockStateFork)
 			patternMatchingValue := pattern.SymbolicValue()
+			if errPattern, ok := pattern.(*ErrorPattern); ok {
+				//the data of the errors matched by an error pattern is unknown, so we narrow
+				//to the errors of the discriminant that have the pattern's kind.
+				if errors, ok := getErrorsOfKind(discriminant, errPattern.kind); ok {
+					patternMatchingValue = errors
+				}
+			}
 			possibleValues = append(possibleValues, patternMatchingValue)
 
 			narrowChain(n.Discriminant, setExactValue, patternMatchingValue, blockStateFork, 0)
@@ -2663,6 +2803,19 @@ func evalMatchStatement(n *parse.MatchStatement, state *State) (_ Value, finalEr
 		}
 	}
 
+	//If the cases match errors by kind, report the kinds that are not handled.
+	if len(handledErrorKinds) > 0 && !allErrorKindsHandled && len(n.DefaultCases) == 0 {
+		var unhandledKinds []string
+		for _, kind := range GetErrorKinds(discriminant) {
+			if !slices.Contains(handledErrorKinds, kind) {
+				unhandledKinds = append(unhandledKinds, kind)
+			}
+		}
+		if len(unhandledKinds) > 0 {
+			state.addWarning(makeSymbolicEvalWarning(n.Discriminant, state, fmtErrorKindsNotHandledByMatchStatement(unhandledKinds)))
+		}
+	}
+
 	state.join(forks...)
 
 	return nil, nil
@@ -2984,6 +3137,11 @@ func evalFunctionExpression(n *parse.FunctionExpression, state *State, options e
 		defer stateFork.unsetSelf()
 	}
 
+	typeParameterConstraints, err := evalTypeParameterConstraints(n.TypeParameters, stateFork)
+	if err != nil {
+		return nil, err
+	}
+
 	var params []Value
 	var paramNames []string
 
@@ -3091,7 +3249,6 @@ func evalFunctionExpression(n *parse.FunctionExpression, state *State, options e
 	//-----------------------------
 
 	var storedReturnType Value
-	var err error
 
 	if n.Body == nil {
 		goto return_function
@@ -3180,14 +3337,20 @@ func evalFunctionExpression(n *parse.FunctionExpression, state *State, options e
 	}
 
 return_function:
-	return &InoxFunction{
+	fn := &InoxFunction{
 		node:           n,
 		nodeChunk:      state.currentChunk().Node,
 		parameters:     params,
 		parameterNames: paramNames,
 		result:         storedReturnType,
 		capturedLocals: capturedLocals,
-	}, nil
+	}
+
+	if len(typeParameterConstraints) > 0 {
+		fn.typeParameterConstraints = typeParameterConstraints
+		fn.definitionState = stateFork
+	}
+	return fn, nil
 }
 
 func evalFunctionPatternExpression(n *parse.FunctionPatternExpression, state *State) (_ Value, finalErr error) {
@@ -3333,7 +3496,7 @@ func evalInclusionImportStatement(n *parse.InclusionImportStatement, state *Stat
 
 func evalImportStatement(n *parse.ImportStatement, state *State) (_ Value, finalErr error) {
 	value := ANY
-	state.setGlobal(n.Identifier.Name, value, GlobalConst)
+	state.setGlobal(n.Identifier.Name, value, GlobalConst, n.Identifier)
 
 	state.symbolicData.SetMostSpecificNodeValue(n.Identifier, value)
 	state.symbolicData.SetGlobalScopeData(n, state.currentGlobalScopeData())
@@ -5097,6 +5260,54 @@ func evalTestcaseExpression(n *parse.TestCaseExpression, state *State, options e
 	return &TestCase{}, nil
 }
 
+func evalBenchmarkExpression(n *parse.BenchmarkExpression, state *State, options evalOptions) (Value, error) {
+	if n.Meta != nil {
+		err := checkBenchmarkMeta(n.Meta, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	v, err := symbolicEval(n.Module, state)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddedModule := v.(*AstNode).Node.(*parse.Chunk)
+
+	//TODO: read the manifest to known the permissions
+	modCtx := NewSymbolicContext(state.ctx.startingConcreteContext, state.ctx.startingConcreteContext, state.ctx)
+	state.ctx.CopyNamedPatternsIn(modCtx)
+	state.ctx.CopyPatternNamespacesIn(modCtx)
+	state.ctx.CopyHostAliasesIn(modCtx)
+
+	modState := newSymbolicState(modCtx, &parse.ParsedChunkSource{
+		Node:   embeddedModule,
+		Source: state.currentChunk().Source,
+	})
+	modState.Module = state.Module
+	modState.symbolicData = state.symbolicData
+	state.forEachGlobal(func(name string, info varSymbolicInfo) {
+		modState.setGlobal(name, info.value, GlobalConst)
+	})
+
+	//evaluate
+	_, err = symbolicEval(embeddedModule, modState)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, err := range modState.errors() {
+		state.addError(err)
+	}
+
+	for _, warning := range modState.warnings() {
+		state.addWarning(warning)
+	}
+
+	return ANY_BENCHMARK, nil
+}
+
 func evalLifetimejobExpression(n *parse.LifetimejobExpression, state *State, options evalOptions) (Value, error) {
 
 	meta, err := symbolicEval(n.Meta, state)
@@ -5325,6 +5536,7 @@ func evalXMLExpression(n *parse.XMLExpression, state *State, options evalOptions
 		state.consumeSymbolicGoFunctionWarnings(func(msg string) {
 			state.addWarning(makeSymbolicEvalWarning(n, state, msg))
 		})
+		state.consumeSymbolicGoFunctionMissingPermissions(n)
 
 		return result, err
 	}
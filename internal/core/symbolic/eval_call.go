@@ -243,6 +243,7 @@ func callSymbolicFunc(callNode *parse.CallExpression, calleeNode parse.Node, sta
 		state.consumeSymbolicGoFunctionWarnings(func(msg string) {
 			state.addWarning(makeSymbolicEvalWarning(callNode, state, msg))
 		})
+		state.consumeSymbolicGoFunctionMissingPermissions(callNode)
 
 		updatedSelf, ok := state.consumeUpdatedSelf()
 		if ok && self != nil {
@@ -420,6 +421,8 @@ func callSymbolicFunc(callNode *parse.CallExpression, calleeNode parse.Node, sta
 		returnType = function.results[0]
 	}
 
+	providedArgCount := len(args)
+
 	if isVariadic {
 		if nonSpreadArgCount < nonVariadicParamCount {
 			state.addError(makeSymbolicEvalError(callNode, state, fmtInvalidNumberOfNonSpreadArgs(nonSpreadArgCount, nonVariadicParamCount)))
@@ -447,6 +450,34 @@ func callSymbolicFunc(callNode *parse.CallExpression, calleeNode parse.Node, sta
 		}
 	}
 
+	//instantiate generic functions
+
+	var typeParameterBindings map[*parse.TypeParameter]Pattern
+
+	if inoxFn, ok := callee.(*InoxFunction); ok && inoxFn.IsGeneric() {
+		//missing arguments are ignored
+		typeArgs := inferTypeArguments(inoxFn, args[:min(providedArgCount, len(args))], callNode, state)
+
+		instantiation, err := inoxFn.instantiate(typeArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		callee = instantiation
+		nonGoParameters = instantiation.parameters
+		returnType = instantiation.result
+		typeParameterBindings = state.bindTypeParameters(inoxFn.FuncExpr().TypeParameters, typeArgs)
+
+		//update the symbolic data of the callee with the instantiation.
+		state.symbolicData.PushNodeValue(calleeNode, instantiation)
+		switch c := calleeNode.(type) {
+		case *parse.IdentifierMemberExpression:
+			state.symbolicData.PushNodeValue(c.PropertyNames[len(c.PropertyNames)-1], instantiation)
+		case *parse.MemberExpression:
+			state.symbolicData.PushNodeValue(c.PropertyName, instantiation)
+		}
+	}
+
 	//check arguments
 
 	var params []Value
@@ -552,6 +583,14 @@ func callSymbolicFunc(callNode *parse.CallExpression, calleeNode parse.Node, sta
 		state.setLocal(name, variadicArgs, nil)
 	}
 
+	if typeParameterBindings != nil {
+		prevBindings := state.typeParameterBindings
+		state.typeParameterBindings = typeParameterBindings
+		defer func() {
+			state.typeParameterBindings = prevBindings
+		}()
+	}
+
 	//---------
 	if hasReturnTypeAnnotation { //if a return type is specified we return the value representing the return type
 		return returnType, nil
@@ -794,3 +833,116 @@ func checkCallExprWithUnhandledError(node parse.Node, ret Value, state *State) {
 		state.addWarning(makeSymbolicEvalWarning(node, state, CALL_MAY_RETURN_ERROR_NOT_HANDLED_EITHER_HANDLE_IT_OR_TURN_THE_CALL_IN_A_MUST_CALL))
 	}
 }
+
+// inferTypeArguments infers the type arguments of a call to a generic function from the arguments. If a type argument
+// cannot be inferred or does not satisfy its constraint the constraint is used instead.
+func inferTypeArguments(fn *InoxFunction, args []Value, callNode parse.Node, state *State) []Pattern {
+	funcExpr := fn.FuncExpr()
+	candidates := map[*parse.TypeParameter][]Value{}
+	nonVariadicParamCount := funcExpr.NonVariadicParamCount()
+
+	for i, arg := range args {
+		var paramNode *parse.FunctionParameter
+		if i < nonVariadicParamCount {
+			paramNode = funcExpr.Parameters[i]
+		} else {
+			paramNode = funcExpr.VariadicParameter()
+		}
+
+		if paramNode.Type != nil {
+			collectTypeArgumentCandidates(paramNode.Type, arg, candidates)
+		}
+	}
+
+	typeArgs := make([]Pattern, len(funcExpr.TypeParameters))
+
+	for i, typeParam := range funcExpr.TypeParameters {
+		constraint := fn.typeParameterConstraints[i]
+		typeParamCandidates := candidates[typeParam]
+
+		if len(typeParamCandidates) == 0 {
+			typeArgs[i] = constraint
+			continue
+		}
+
+		for j, candidate := range typeParamCandidates {
+			if mv, ok := candidate.(IMultivalue); ok {
+				typeParamCandidates[j] = mv.OriginalMultivalue().TransformsValues(WidenToStaticType)
+			} else {
+				typeParamCandidates[j] = WidenToStaticType(candidate)
+			}
+		}
+		typeArg := joinValues(typeParamCandidates)
+
+		if !constraint.TestValue(typeArg, RecTestCallState{}) {
+			msg := fmtTypeArgDoesNotSatisfyConstraint(typeParam.Name.Name, typeArg, constraint.SymbolicValue())
+			state.addError(makeSymbolicEvalError(callNode, state, msg))
+			typeArgs[i] = constraint
+			continue
+		}
+
+		typeArgs[i] = &TypePattern{val: typeArg}
+	}
+
+	return typeArgs
+}
+
+// collectTypeArgumentCandidates matches the type of a parameter against an argument and adds the values matching
+// type parameters to candidates.
+func collectTypeArgumentCandidates(typeNode parse.Node, arg Value, candidates map[*parse.TypeParameter][]Value) {
+	switch n := typeNode.(type) {
+	case *parse.PatternIdentifierLiteral:
+		if n.TypeParameter != nil {
+			candidates[n.TypeParameter] = append(candidates[n.TypeParameter], arg)
+		}
+	case *parse.OptionalPatternExpression:
+		if _, ok := arg.(*NilT); !ok {
+			collectTypeArgumentCandidates(n.Pattern, narrowOut(Nil, arg), candidates)
+		}
+	case *parse.ListPatternLiteral:
+		collectSequenceTypeArgumentCandidates(n.Elements, n.GeneralElement, arg, candidates)
+	case *parse.TuplePatternLiteral:
+		collectSequenceTypeArgumentCandidates(n.Elements, n.GeneralElement, arg, candidates)
+	case *parse.ObjectPatternLiteral:
+		collectPropertyTypeArgumentCandidates(n.Properties, arg, candidates)
+	case *parse.RecordPatternLiteral:
+		collectPropertyTypeArgumentCandidates(n.Properties, arg, candidates)
+	}
+}
+
+func collectSequenceTypeArgumentCandidates(elements []parse.Node, generalElement parse.Node, arg Value, candidates map[*parse.TypeParameter][]Value) {
+	if generalElement != nil {
+		iterable, ok := asIterable(arg).(Iterable)
+		if ok {
+			collectTypeArgumentCandidates(generalElement, iterable.IteratorElementValue(), candidates)
+		}
+		return
+	}
+
+	indexable, ok := asIndexable(arg).(Indexable)
+	if !ok || !indexable.HasKnownLen() || indexable.KnownLen() != len(elements) {
+		return
+	}
+
+	for i, element := range elements {
+		collectTypeArgumentCandidates(element, indexable.ElementAt(i), candidates)
+	}
+}
+
+func collectPropertyTypeArgumentCandidates(properties []*parse.ObjectPatternProperty, arg Value, candidates map[*parse.TypeParameter][]Value) {
+	iprops, ok := AsIprops(arg).(IProps)
+	if !ok {
+		return
+	}
+
+	for _, prop := range properties {
+		if prop.HasImplicitKey() {
+			continue
+		}
+		name := prop.Name()
+		if !HasRequiredProperty(iprops, name) {
+			continue
+		}
+		collectTypeArgumentCandidates(prop.Value, iprops.Prop(name), candidates)
+	}
+}
@@ -4626,6 +4626,113 @@ func TestSymbolicEval(t *testing.T) {
 		})
 	})
 
+	t.Run("call generic Inox function", func(t *testing.T) {
+		t.Run("return type is instantiated", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn first<T>(list []T) T {
+					return list[0]
+				}
+
+				return first([1, 2])
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_INT, res)
+
+			//the callee should have the instantiated function as most specific value.
+			callee := parse.FindNode(n, (*parse.CallExpression)(nil), nil).Callee
+
+			calleeValue, ok := state.symbolicData.GetMostSpecificNodeValue(callee)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, "fn(list []int) int", Stringify(calleeValue))
+
+			genericFn, ok := state.symbolicData.GetLessSpecificNodeValue(callee)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, "fn<T>(list []serializable) serializable", Stringify(genericFn))
+		})
+
+		t.Run("return type is not annotated", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn id<T>(a T) => a
+
+				return id("a")
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, NewString("a"), res)
+		})
+
+		t.Run("type argument inferred from a property", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn get_items<T>(page {items: []T}) []T {
+					return page.items
+				}
+
+				return get_items({items: [1]})
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, NewListOf(ANY_INT), res)
+		})
+
+		t.Run("type arguments inferred from several arguments are joined", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn pick<T>(a T, b T) T {
+					return a
+				}
+
+				return pick(1, "a")
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, NewMultivalue(ANY_INT, ANY_STRING), res)
+		})
+
+		t.Run("type argument does not satisfy the constraint", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn double<T int>(a T) T {
+					return a
+				}
+
+				return double("a")
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+
+			callNode := parse.FindNode(n, (*parse.CallExpression)(nil), nil)
+
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(callNode, state, fmtTypeArgDoesNotSatisfyConstraint("T", ANY_STRING, ANY_INT)),
+				makeSymbolicEvalError(callNode.Arguments[0], state, FmtInvalidArg(0, NewString("a"), ANY_INT)),
+			}, state.errors())
+			assert.Equal(t, ANY_INT, res)
+		})
+
+		t.Run("body is checked against the constraint", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				fn f<T int>(a T) T {
+					return "a"
+				}
+			`)
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+
+			returnStmt := parse.FindNode(n, (*parse.ReturnStatement)(nil), nil)
+
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(returnStmt, state, fmtInvalidReturnValue(NewString("a"), ANY_INT)),
+			}, state.errors())
+		})
+	})
+
 	t.Run("call Go function", func(t *testing.T) {
 		t.Run("signature is func(*Context) int", func(t *testing.T) {
 			n, state := MakeTestStateAndChunk(`
@@ -8908,6 +9015,61 @@ func TestSymbolicEval(t *testing.T) {
 			), res)
 		})
 
+		t.Run("error kinds not handled by the cases", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				match err {
+					%error-a {}
+				}
+			`, map[string]Value{
+				"err": NewMultivalue(NewErrorWithKind(Nil, "a"), NewErrorWithKind(Nil, "b"), NewErrorWithKind(Nil, "c")),
+			})
+			state.ctx.AddNamedPattern("error-a", NewErrorPattern("a"), false)
+
+			discriminant := n.Statements[0].(*parse.MatchStatement).Discriminant
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, []SymbolicEvaluationWarning{
+				makeSymbolicEvalWarning(discriminant, state, fmtErrorKindsNotHandledByMatchStatement([]string{"b", "c"})),
+			}, state.warnings())
+		})
+
+		t.Run("all error kinds handled by the cases", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				match err {
+					%error-a {}
+					%error-b {}
+				}
+			`, map[string]Value{
+				"err": NewMultivalue(NewErrorWithKind(Nil, "a"), NewErrorWithKind(Nil, "b")),
+			})
+			state.ctx.AddNamedPattern("error-a", NewErrorPattern("a"), false)
+			state.ctx.AddNamedPattern("error-b", NewErrorPattern("b"), false)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Empty(t, state.warnings())
+		})
+
+		t.Run("error kinds not handled by the cases but there is a default case", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				match err {
+					%error-a {}
+					defaultcase {}
+				}
+			`, map[string]Value{
+				"err": NewMultivalue(NewErrorWithKind(Nil, "a"), NewErrorWithKind(Nil, "b")),
+			})
+			state.ctx.AddNamedPattern("error-a", NewErrorPattern("a"), false)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Empty(t, state.warnings())
+		})
+
 		t.Run("error in a case value", func(t *testing.T) {
 			n, state := MakeTestStateAndChunk(`
 				v = /path
@@ -10273,6 +10435,93 @@ func TestSymbolicEval(t *testing.T) {
 		})
 	})
 
+	t.Run("generic pattern definition", func(t *testing.T) {
+		t.Run("instantiation", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				pattern Paginated<T> = {items: []T, next: int}
+				return %Paginated(%int)
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+
+			if !assert.IsType(t, (*ObjectPattern)(nil), res) {
+				return
+			}
+			assert.Equal(t, NewListOf(ANY_INT), res.(*ObjectPattern).entries["items"].SymbolicValue())
+		})
+
+		t.Run("instantiation inside an object pattern", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				pattern Paginated<T> = {items: []T}
+				pattern user = {name: str}
+				pattern users = Paginated(%user)
+
+				fn get_first_name(page users){
+					return page.items[0].name
+				}
+				return get_first_name
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+
+			if !assert.IsType(t, (*InoxFunction)(nil), res) {
+				return
+			}
+			assert.Equal(t, ANY_STR_LIKE, res.(*InoxFunction).Result())
+		})
+
+		t.Run("type argument does not satisfy the constraint", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				pattern Page<T int> = {items: []T}
+				return %Page(%str)
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+
+			callNode := parse.FindNode(n, (*parse.PatternCallExpression)(nil), nil)
+
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(callNode, state, fmtTypeArgDoesNotSatisfyConstraint("T", ANY_STR_LIKE, ANY_INT)),
+			}, state.errors())
+			assert.Equal(t, ANY_PATTERN, res)
+		})
+
+		t.Run("invalid number of type arguments", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				pattern Page<T> = {items: []T}
+				return %Page(%int, %str)
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+
+			callNode := parse.FindNode(n, (*parse.PatternCallExpression)(nil), nil)
+
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(callNode, state, fmtInvalidNumberOfTypeArgs(2, 1)),
+			}, state.errors())
+			assert.Equal(t, ANY_PATTERN, res)
+		})
+
+		t.Run("generic pattern used without type arguments", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				pattern Page<T> = {items: []T}
+				return %Page
+			`)
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+
+			patternIdents := parse.FindNodes(n, (*parse.PatternIdentifierLiteral)(nil), nil)
+			patternIdent := patternIdents[len(patternIdents)-1]
+
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(patternIdent, state, GENERIC_PATTERNS_SHOULD_BE_INSTANTIATED),
+			}, state.errors())
+			assert.Equal(t, ANY_PATTERN, res)
+		})
+	})
+
 	t.Run("pattern namespace definition", func(t *testing.T) {
 		t.Run("RHS is an object literal", func(t *testing.T) {
 			n, state := MakeTestStateAndChunk(`
@@ -11135,6 +11384,60 @@ func TestSymbolicEval(t *testing.T) {
 			}, state.errors())
 		})
 
+		t.Run("main-db-fixtures property in meta should not be present if the program property is not present", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				manifest {}; 
+				testsuite({main-db-fixtures: /fixtures.json}) {}
+			`)
+
+			objectLit := parse.FindNode(n.Statements[0], (*parse.ObjectLiteral)(nil), func(n *parse.ObjectLiteral, isUnique bool) bool {
+				return len(n.Properties) == 1
+			})
+
+			fls := memfs.New()
+			state.projectFilesystem = fls
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(objectLit, state, MAIN_DB_FIXTURES_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM),
+			}, state.errors())
+		})
+
+		t.Run("parallel property in meta", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`testsuite({parallel: 4}) {}`)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+		})
+
+		t.Run("parallel property in meta should be positive", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`testsuite({parallel: 0}) {}`)
+
+			objectLit := parse.FindNode(n, (*parse.ObjectLiteral)(nil), nil)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(objectLit, state, PARALLELISM_SHOULD_BE_POSITIVE),
+			}, state.errors())
+		})
+
+		t.Run("parallel property should not be present in the meta of test cases", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`testsuite {
+				testcase({parallel: 2}) {}
+			}`)
+
+			objectLit := parse.FindNode(n, (*parse.ObjectLiteral)(nil), nil)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(objectLit, state, PARALLELISM_CAN_ONLY_BE_SPECIFIED_BY_TEST_SUITES),
+			}, state.errors())
+		})
+
 		t.Run("error in module", func(t *testing.T) {
 			n, state := MakeTestStateAndChunk(`testsuite "name" {
 				(1 + true)
@@ -11517,6 +11820,30 @@ func TestSymbolicEval(t *testing.T) {
 		})
 	})
 
+	t.Run("benchmark expression", func(t *testing.T) {
+		t.Run("empty module", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`benchmark {}`)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_BENCHMARK, res)
+		})
+
+		t.Run("run method", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				b = benchmark {}
+				assign lthread err = b.run()
+				return lthread
+			`)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_LTHREAD, res)
+		})
+	})
+
 	t.Run("lifetimejob expression", func(t *testing.T) {
 		t.Run("should have access to implicit subject properties defined before and after the jobs", func(t *testing.T) {
 			n, state := MakeTestStateAndChunk(`{ 
@@ -11914,6 +12241,127 @@ func TestSymbolicEval(t *testing.T) {
 		})
 	})
 
+	t.Run("channel", func(t *testing.T) {
+		intChannel := NewChannel(&TypePattern{val: ANY_INT})
+
+		t.Run("received values should have the element type", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				return ch.receive!()
+			`)
+			state.setGlobal("ch", intChannel, GlobalConst)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_INT, res)
+		})
+
+		t.Run("iterated values should have the element type", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				for v in ch {
+					return v
+				}
+				return 0
+			`)
+			state.setGlobal("ch", intChannel, GlobalConst)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_INT, res)
+		})
+
+		t.Run("sending a value that does not match the element pattern", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				ch.send!("a")
+			`)
+			state.setGlobal("ch", intChannel, GlobalConst)
+			call := parse.FindNode(n, (*parse.CallExpression)(nil), nil)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(call, state, fmtValueDoesNotMatchChannelElement(NewString("a"), &TypePattern{val: ANY_INT})),
+			}, state.errors())
+		})
+
+		t.Run("passing a channel to a lthread", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				go {globals: {ch: ch}} do {
+					ch.send!(1)
+				}
+			`)
+			state.setGlobal("ch", intChannel, GlobalConst)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+		})
+
+		t.Run("select", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				assign index value = select!(ch1, ch2, timeout)
+				return value
+			`)
+			state.setGlobal("timeout", ANY_DURATION, GlobalConst)
+			state.setGlobal("ch1", intChannel, GlobalConst)
+			state.setGlobal("ch2", NewChannel(&TypePattern{val: ANY_STR_LIKE}), GlobalConst)
+			state.setGlobal("select", WrapGoFunction(Select), GlobalConst)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, NewMultivalue(ANY_INT, ANY_STR_LIKE, Nil), res)
+		})
+
+		t.Run("select: invalid argument", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				select!(ch, 1)
+			`)
+			state.setGlobal("ch", intChannel, GlobalConst)
+			state.setGlobal("select", WrapGoFunction(Select), GlobalConst)
+			call := parse.FindNode(n, (*parse.CallExpression)(nil), nil)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(call, state, SELECT_ARGS_SHOULD_BE_CHANNELS),
+			}, state.errors())
+		})
+	})
+
+	t.Run("lthread scope", func(t *testing.T) {
+
+		t.Run("function without parameters", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				return lthread_scope!(fn(){
+					go do { return 1 }
+				}, timeout)
+			`)
+			state.setGlobal("timeout", ANY_DURATION, GlobalConst)
+			state.setGlobal("lthread_scope", WrapGoFunction(RunLThreadScope), GlobalConst)
+
+			res, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Empty(t, state.errors())
+			assert.Equal(t, ANY_ARRAY, res)
+		})
+
+		t.Run("function with parameters", func(t *testing.T) {
+			n, state := MakeTestStateAndChunk(`
+				lthread_scope!(fn(a){})
+			`)
+			state.setGlobal("lthread_scope", WrapGoFunction(RunLThreadScope), GlobalConst)
+			call := parse.FindNode(n, (*parse.CallExpression)(nil), nil)
+
+			_, err := symbolicEval(n, state)
+			assert.NoError(t, err)
+			assert.Equal(t, []SymbolicEvaluationError{
+				makeSymbolicEvalError(call, state, LTHREAD_SCOPE_FN_SHOULD_HAVE_NO_PARAMS),
+			}, state.errors())
+		})
+	})
+
 	t.Run("reception handler expression", func(t *testing.T) {
 		n, state := MakeTestStateAndChunk(`
 			{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -39,6 +40,11 @@ type InoxFunction struct {
 	forbiddenNodeExplanation string //optional
 	globalsAtCreation        map[string]Value
 
+	//only set if the function is generic: in the parameters and the result type parameters are bound to their constraint.
+	typeParameterConstraints []Pattern
+	definitionState          *State
+	isInstantiation          bool
+
 	SerializableMixin
 }
 
@@ -95,6 +101,63 @@ func (fn *InoxFunction) FuncExpr() *parse.FunctionExpression {
 	}
 }
 
+// IsGeneric returns true if the function has type parameters and is not an instantiation.
+func (fn *InoxFunction) IsGeneric() bool {
+	return len(fn.typeParameterConstraints) > 0 && !fn.isInstantiation
+}
+
+// instantiate returns a copy of the generic function with the types of the parameters and the result evaluated
+// with the type parameters bound to typeArgs. The result is not updated if the function has no return type annotation.
+func (fn *InoxFunction) instantiate(typeArgs []Pattern) (*InoxFunction, error) {
+	funcExpr := fn.FuncExpr()
+
+	stateFork := fn.definitionState.fork()
+	//errors have already been reported during the evaluation of the definition.
+	stateFork.symbolicData = NewSymbolicData()
+	stateFork.typeParameterBindings = fn.definitionState.bindTypeParameters(funcExpr.TypeParameters, typeArgs)
+
+	params := slices.Clone(fn.parameters)
+
+	for i, paramNode := range funcExpr.Parameters {
+		if paramNode.Type == nil {
+			continue
+		}
+		pattern, err := evalPatternNode(paramNode.Type, stateFork)
+		if err != nil {
+			return nil, err
+		}
+		if funcExpr.IsVariadic && i == len(funcExpr.Parameters)-1 {
+			params[i] = NewArrayOf(pattern.SymbolicValue())
+		} else {
+			params[i] = pattern.SymbolicValue()
+		}
+	}
+
+	result := fn.result
+
+	if funcExpr.ReturnType != nil {
+		pattern, err := evalPatternNode(funcExpr.ReturnType, stateFork)
+		if err != nil {
+			return nil, err
+		}
+		result = pattern.SymbolicValue()
+	}
+
+	instantiation := &InoxFunction{
+		node:                     fn.node,
+		nodeChunk:                fn.nodeChunk,
+		parameters:               params,
+		parameterNames:           fn.parameterNames,
+		result:                   result,
+		capturedLocals:           fn.capturedLocals,
+		originState:              fn.originState,
+		typeParameterConstraints: fn.typeParameterConstraints,
+		definitionState:          fn.definitionState,
+		isInstantiation:          true,
+	}
+	return instantiation, nil
+}
+
 func (fn *InoxFunction) Test(v Value, state RecTestCallState) bool {
 	state.StartCall()
 	defer state.FinishCall()
@@ -208,7 +271,20 @@ func (fn *InoxFunction) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.P
 		return
 	}
 
-	w.WriteString("fn(")
+	w.WriteString("fn")
+
+	if fn.IsGeneric() {
+		w.WriteString("<")
+		for i, typeParam := range fn.FuncExpr().TypeParameters {
+			if i != 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(typeParam.Name.Name)
+		}
+		w.WriteString(">")
+	}
+
+	w.WriteString("(")
 
 	for i, param := range fn.parameters {
 		if i != 0 {
@@ -0,0 +1,222 @@
+package symbolic
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/inoxlang/inox/internal/parse"
+)
+
+var (
+	ErrTopLevelStatementsNotReusable = errors.New("the top level statements of the previous evaluation cannot be reused")
+)
+
+// A dataChange is a modification of a Data recorded during an evaluation with top level checkpoints,
+// the changes are replayed in order to reuse the evaluation of the unchanged top level statements.
+type dataChange struct {
+	kind  dataChangeKind
+	node  parse.Node //nil for errors and warnings
+	value any
+}
+
+type dataChangeKind uint8
+
+const (
+	errorAddition dataChangeKind = iota + 1
+	warningAddition
+	mostSpecificNodeValueUpdate
+	lessSpecificNodeValueUpdate
+	nodeValuePush
+	runtimeTypecheckPatternUpdate
+	allowedNonPresentPropertiesUpdate
+	allowedNonPresentKeysUpdate
+	localScopeDataUpdate
+	globalScopeDataUpdate
+	contextDataUpdate
+	usedTypeExtensionUpdate
+	allTypeExtensionsUpdate
+	urlReferencedEntityUpdate
+	missingPermissionAddition
+	comptimeTypesCreation
+)
+
+// A topLevelCheckpoint is a snapshot of the evaluation state taken just before the evaluation of a top level statement
+// of the main chunk. The snapshot maps should not be modified.
+type topLevelCheckpoint struct {
+	changeCount int //number of data changes recorded before the statement
+
+	globals map[string]varSymbolicInfo
+	locals  map[string]varSymbolicInfo
+
+	hostAliases                         map[string]Value
+	namedPatterns                       map[string]Pattern
+	namedPatternPositionDefinitions     map[string]parse.SourcePositionRange
+	patternNamespaces                   map[string]*PatternNamespace
+	patternNamespacePositionDefinitions map[string]parse.SourcePositionRange
+	typeExtensions                      []*TypeExtension //extensions added since the start of the evaluation
+}
+
+// topLevelCheckpointing is the checkpointing state of an evaluation, see EvalCheckInput.SaveTopLevelCheckpoints.
+type topLevelCheckpointing struct {
+	resumedCheckpoint         *topLevelCheckpoint //nil if no statement is reused
+	skippedStatementCount     int
+	initialTypeExtensionCount int
+}
+
+// TopLevelCheckpointCount returns the number of top level statements whose evaluation can be reused by the next
+// evaluation of the checked chunk (see EvalCheckInput.ReusedTopLevelStatementCount), plus one. The result is
+// zero if no checkpoints were saved.
+func (d *Data) TopLevelCheckpointCount() int {
+	return len(d.topLevelCheckpoints)
+}
+
+func (d *Data) recordChange(kind dataChangeKind, node parse.Node, value any) {
+	if d.changeRecording {
+		d.changes = append(d.changes, dataChange{kind: kind, node: node, value: value})
+	}
+}
+
+// replayChanges applies changes recorded during the evaluation of prevChunk, changes related to prevChunk
+// are applied to chunk.
+func (d *Data) replayChanges(changes []dataChange, prevChunk, chunk *parse.Chunk) {
+	for _, change := range changes {
+		node := change.node
+		if node == parse.Node(prevChunk) {
+			node = chunk
+		}
+
+		switch change.kind {
+		case errorAddition:
+			d.AddError(change.value.(SymbolicEvaluationError))
+		case warningAddition:
+			d.AddWarning(change.value.(SymbolicEvaluationWarning))
+		case mostSpecificNodeValueUpdate:
+			d.SetMostSpecificNodeValue(node, change.value.(Value))
+		case lessSpecificNodeValueUpdate:
+			d.SetLessSpecificNodeValue(node, change.value.(Value))
+		case nodeValuePush:
+			d.PushNodeValue(node, change.value.(Value))
+		case runtimeTypecheckPatternUpdate:
+			d.SetRuntimeTypecheckPattern(node, change.value)
+		case allowedNonPresentPropertiesUpdate:
+			d.SetAllowedNonPresentProperties(node, change.value.([]string))
+		case allowedNonPresentKeysUpdate:
+			d.SetAllowedNonPresentKeys(node, change.value.([]string))
+		case localScopeDataUpdate:
+			d.SetLocalScopeData(node, change.value.(ScopeData))
+		case globalScopeDataUpdate:
+			d.SetGlobalScopeData(node, change.value.(ScopeData))
+		case contextDataUpdate:
+			d.SetContextData(node, change.value.(ContextData))
+		case usedTypeExtensionUpdate:
+			d.SetUsedTypeExtension(node.(*parse.DoubleColonExpression), change.value.(*TypeExtension))
+		case allTypeExtensionsUpdate:
+			d.SetAllTypeExtensions(node.(*parse.DoubleColonExpression), change.value.([]*TypeExtension))
+		case urlReferencedEntityUpdate:
+			d.SetURLReferencedEntity(node.(*parse.DoubleColonExpression), change.value.(Value))
+		case missingPermissionAddition:
+			d.AddMissingPermission(node, change.value.(MissingPermission))
+		case comptimeTypesCreation:
+			//the compile-time types are only modified when the structs are defined, before the
+			//evaluation of the top level statements.
+			d.comptimeTypes[node] = change.value.(*ModuleCompileTimeTypes)
+			d.recordChange(comptimeTypesCreation, node, change.value)
+		default:
+			panic(ErrUnreachable)
+		}
+	}
+}
+
+// initTopLevelCheckpointing enables the recording of the data changes and the saving of top level checkpoints,
+// the returned state should be used for the evaluation. If input.ReusedTopLevelStatementCount is not zero the state,
+// the context and the data of the previous evaluation are reused: the values created by the reused statements
+// may reference them (e.g. generic functions).
+func initTopLevelCheckpointing(input EvalCheckInput, state *State) (*State, error) {
+	reusedStmtCount := input.ReusedTopLevelStatementCount
+	prevData := input.PreviousData
+
+	if reusedStmtCount != 0 {
+		if prevData == nil || prevData.checkpointedChunk == nil ||
+			reusedStmtCount >= len(prevData.topLevelCheckpoints) ||
+			reusedStmtCount > len(input.Node.Statements) ||
+			!slices.Equal(prevData.checkpointedChunk.Statements[:reusedStmtCount], input.Node.Statements[:reusedStmtCount]) {
+			return nil, ErrTopLevelStatementsNotReusable
+		}
+
+		prevChunk := prevData.checkpointedChunk
+		checkpoint := prevData.topLevelCheckpoints[reusedStmtCount]
+		changes := prevData.changes[:checkpoint.changeCount]
+		checkpoints := slices.Clone(prevData.topLevelCheckpoints[:reusedStmtCount])
+
+		prevState := prevData.checkpointedState
+		prevCtx := prevState.ctx
+
+		*prevData = *state.symbolicData
+		*prevCtx = *state.ctx
+		*prevState = *state
+
+		prevState.ctx = prevCtx
+		prevState.symbolicData = prevData
+		prevCtx.associatedState = prevState
+		state = prevState
+
+		state.symbolicData.changeRecording = true
+		state.symbolicData.replayChanges(changes, prevChunk, input.Node)
+		state.symbolicData.topLevelCheckpoints = checkpoints
+
+		state.checkpointing = &topLevelCheckpointing{
+			resumedCheckpoint:     checkpoint,
+			skippedStatementCount: reusedStmtCount,
+		}
+	} else {
+		state.checkpointing = &topLevelCheckpointing{}
+	}
+
+	data := state.symbolicData
+	data.changeRecording = true
+	data.checkpointedChunk = input.Node
+	data.checkpointedState = state
+
+	state.checkpointing.initialTypeExtensionCount = len(state.ctx.typeExtensions)
+	return state, nil
+}
+
+func (state *State) isCheckpointedChunk(chunk *parse.Chunk) bool {
+	return state.checkpointing != nil && state.symbolicData.checkpointedChunk == chunk
+}
+
+func (state *State) saveTopLevelCheckpoint() {
+	ctx := state.ctx
+	data := state.symbolicData
+
+	data.topLevelCheckpoints = append(data.topLevelCheckpoints, &topLevelCheckpoint{
+		changeCount: len(data.changes),
+
+		globals: maps.Clone(state.scopeStack[0].variables),
+		locals:  maps.Clone(state.scopeStack[len(state.scopeStack)-1].variables),
+
+		hostAliases:                         maps.Clone(ctx.hostAliases),
+		namedPatterns:                       maps.Clone(ctx.namedPatterns),
+		namedPatternPositionDefinitions:     maps.Clone(ctx.namedPatternPositionDefinitions),
+		patternNamespaces:                   maps.Clone(ctx.patternNamespaces),
+		patternNamespacePositionDefinitions: maps.Clone(ctx.patternNamespacePositionDefinitions),
+		typeExtensions:                      slices.Clone(ctx.typeExtensions[state.checkpointing.initialTypeExtensionCount:]),
+	})
+}
+
+// restoreTopLevelCheckpoint restores the variables and the context's definitions of a checkpoint, the global
+// variables, patterns and pattern namespaces defined by the input are overwritten by the ones of the checkpoint.
+func (state *State) restoreTopLevelCheckpoint(checkpoint *topLevelCheckpoint) {
+	ctx := state.ctx
+
+	maps.Copy(state.scopeStack[0].variables, checkpoint.globals)
+	maps.Copy(state.scopeStack[len(state.scopeStack)-1].variables, checkpoint.locals)
+
+	maps.Copy(ctx.hostAliases, checkpoint.hostAliases)
+	maps.Copy(ctx.namedPatterns, checkpoint.namedPatterns)
+	maps.Copy(ctx.namedPatternPositionDefinitions, checkpoint.namedPatternPositionDefinitions)
+	maps.Copy(ctx.patternNamespaces, checkpoint.patternNamespaces)
+	maps.Copy(ctx.patternNamespacePositionDefinitions, checkpoint.patternNamespacePositionDefinitions)
+	ctx.typeExtensions = append(ctx.typeExtensions, checkpoint.typeExtensions...)
+}
@@ -0,0 +1,116 @@
+package symbolic
+
+import (
+	"testing"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementalEvalCheck(t *testing.T) {
+
+	prevCode := "manifest {}\n" +
+		"fn f(a int) int {\n\treturn a\n}\n" +
+		"fn first<T>(list []T) T {\n\treturn list[0]\n}\n" +
+		"struct S {\n\tx int\n}\n" +
+		"pattern p = int\n" +
+		"var a int = f(1)\n" +
+		"b = (a + \"s\")\n" +
+		"c = a\n" +
+		"d = b\n"
+
+	code := "manifest {}\n" +
+		"fn f(a int) int {\n\treturn a\n}\n" +
+		"fn first<T>(list []T) T {\n\treturn list[0]\n}\n" +
+		"struct S {\n\tx int\n}\n" +
+		"pattern p = int\n" +
+		"var a int = f(1)\n" +
+		"b = (a + \"s\")\n" +
+		"c = a\n" +
+		"d = first([%p])\n"
+
+	evalCheck := func(chunk *parse.ParsedChunkSource, prevData *Data, reusedStmtCount int) (*Data, error) {
+		ctx := NewSymbolicContext(nil, nil, nil)
+		ctx.AddNamedPattern("int", &TypePattern{val: ANY_INT}, false)
+
+		return EvalCheck(EvalCheckInput{
+			Node:   chunk.Node,
+			Module: &Module{mainChunk: chunk},
+
+			UseBaseGlobals:      true,
+			SymbolicBaseGlobals: map[string]Value{"int": ANY_INT},
+			Context:             ctx,
+
+			SaveTopLevelCheckpoints:      true,
+			PreviousData:                 prevData,
+			ReusedTopLevelStatementCount: reusedStmtCount,
+		})
+	}
+
+	prevChunk, err := parse.ParseChunkSource(parse.InMemorySource{NameString: "test", CodeString: prevCode})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	//the previous data is updated by the incremental evaluation, so each subtest evaluates the previous chunk.
+	evalPrevChunk := func() *Data {
+		prevData, _ := evalCheck(prevChunk, nil, 0)
+		assert.Equal(t, len(prevChunk.Node.Statements)+1, prevData.TopLevelCheckpointCount())
+		return prevData
+	}
+
+	chunk, err := parse.ParseChunkSourceIncrementally(prevChunk, parse.InMemorySource{NameString: "test", CodeString: code},
+		parse.GetSourceEdit([]rune(prevCode), []rune(code)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	//the statements before the edit are shared with the previous chunk.
+	reusedStmtCount := 0
+	for chunk.Node.Statements[reusedStmtCount] == prevChunk.Node.Statements[reusedStmtCount] {
+		reusedStmtCount++
+	}
+	if !assert.GreaterOrEqual(t, reusedStmtCount, 4) {
+		return
+	}
+
+	t.Run("the result should be the same as the result of a full evaluation", func(t *testing.T) {
+		data, err := evalCheck(chunk, evalPrevChunk(), reusedStmtCount)
+		expectedData, expectedErr := evalCheck(chunk, nil, 0)
+
+		if !assert.NotNil(t, data) {
+			return
+		}
+
+		assert.Equal(t, expectedErr, err)
+		assert.Len(t, data.Errors(), 1)
+		assert.Equal(t, expectedData.Errors(), data.Errors())
+		assert.Equal(t, expectedData.Warnings(), data.Warnings())
+		assert.Equal(t, expectedData.TopLevelCheckpointCount(), data.TopLevelCheckpointCount())
+
+		parse.Walk(chunk.Node, func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
+			expectedValue, expectedOk := expectedData.GetMostSpecificNodeValue(node)
+			value, ok := data.GetMostSpecificNodeValue(node)
+
+			//the values are not compared with assert.Equal because the values created by the reused statements
+			//may reference the previous chunk.
+			if assert.Equal(t, expectedOk, ok, "%T", node) && ok {
+				assert.Equal(t, Stringify(expectedValue), Stringify(value), "%T", node)
+			}
+			return parse.ContinueTraversal, nil
+		}, nil)
+
+		_, ok := data.GetComptimeTypes(chunk.Node)
+		assert.True(t, ok)
+
+		expectedScopeData, _ := expectedData.GetGlobalScopeData(chunk.Node, nil)
+		scopeData, _ := data.GetGlobalScopeData(chunk.Node, nil)
+		assert.ElementsMatch(t, expectedScopeData.Variables, scopeData.Variables)
+	})
+
+	t.Run("statements that are not shared with the previous chunk should not be reused", func(t *testing.T) {
+		data, err := evalCheck(chunk, evalPrevChunk(), len(chunk.Node.Statements))
+		assert.True(t, data == nil)
+		assert.ErrorIs(t, err, ErrTopLevelStatementsNotReusable)
+	})
+}
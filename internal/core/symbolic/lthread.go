@@ -196,3 +196,12 @@ func (s *ExecutedStep) Cancel(*Context) {
 func (s *ExecutedStep) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
 	w.WriteName("executed-step")
 }
+
+// RunLThreadScope is the symbolic equivalent of core.RunLThreadScope.
+func RunLThreadScope(ctx *Context, fn *InoxFunction, timeout *OptionalParam[*Duration]) (*Array, *Error) {
+	if len(fn.Parameters()) != 0 || fn.IsVariadic() {
+		ctx.AddSymbolicGoFunctionError(LTHREAD_SCOPE_FN_SHOULD_HAVE_NO_PARAMS)
+	}
+
+	return ANY_ARRAY, nil
+}
@@ -312,6 +312,10 @@ func (pattern *DifferencePattern) IsMutable() bool {
 	return false
 }
 
+func (pattern *GenericPattern) IsMutable() bool {
+	return false
+}
+
 func (pattern *OptionalPattern) IsMutable() bool {
 	return false
 }
@@ -392,6 +396,10 @@ func (patt *MutationPattern) IsMutable() bool {
 	return false
 }
 
+func (patt *ErrorPattern) IsMutable() bool {
+	return false
+}
+
 func (*Reader) IsMutable() bool {
 	return true
 }
@@ -476,6 +484,10 @@ func (c *TestCase) IsMutable() bool {
 	return false
 }
 
+func (b *Benchmark) IsMutable() bool {
+	return false
+}
+
 func (c *DynamicValue) IsMutable() bool {
 	return true
 }
@@ -543,6 +555,10 @@ func (r *RingBuffer) IsMutable() bool {
 	return true
 }
 
+func (c *Channel) IsMutable() bool {
+	return true
+}
+
 func (c *DataChunk) IsMutable() bool {
 	return true
 }
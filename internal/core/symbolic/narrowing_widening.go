@@ -576,3 +576,9 @@ func ImplementsOrIsMultivalueWithAllValuesImplementing[T Value](v Value) bool {
 func haveSameGoTypes(a, b Value) bool {
 	return reflect.TypeOf(a) == reflect.TypeOf(b)
 }
+
+// WidenToStaticType returns the symbolic value of the static pattern of $value, it can be used to display
+// the type of a value without its exact data (e.g. int instead of int(1)).
+func WidenToStaticType(value Value) Value {
+	return getStatic(value).SymbolicValue()
+}
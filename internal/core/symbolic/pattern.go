@@ -29,7 +29,7 @@ var (
 		(*HostPattern)(nil), (*ListPattern)(nil), (*ObjectPattern)(nil), (*TuplePattern)(nil), (*RecordPattern)(nil),
 		(*OptionPattern)(nil), (*RegexPattern)(nil), (*TypePattern)(nil), (*AnyPattern)(nil), (*FunctionPattern)(nil),
 		(*ExactValuePattern)(nil), (*ExactStringPattern)(nil), (*ParserBasedPattern)(nil),
-		(*IntRangePattern)(nil), (*FloatRangePattern)(nil), (*EventPattern)(nil), (*MutationPattern)(nil), (*OptionalPattern)(nil),
+		(*IntRangePattern)(nil), (*FloatRangePattern)(nil), (*EventPattern)(nil), (*MutationPattern)(nil), (*ErrorPattern)(nil), (*OptionalPattern)(nil),
 		(*FunctionPattern)(nil),
 		(*DifferencePattern)(nil),
 		(*IntersectionPattern)(nil),
@@ -103,6 +103,7 @@ var (
 	ANY_FLOAT_RANGE_PATTERN = NewFloatRangePattern(ANY_FLOAT_RANGE)
 	ANY_EVENT_PATTERN       = &EventPattern{ValuePattern: ANY_PATTERN}
 	ANY_MUTATION_PATTERN    = &MutationPattern{}
+	ANY_ERROR_PATTERN       = &ErrorPattern{}
 
 	ANY_FUNCTION_PATTERN = &FunctionPattern{}
 
@@ -3132,6 +3133,104 @@ func (p *OptionalPattern) WidestOfType() Value {
 	return &OptionalPattern{}
 }
 
+// A GenericPattern represents a symbolic GenericPattern, it is the value of a pattern definition with type parameters
+// (e.g. pattern Paginated<T> = {items: []T}). Calling a GenericPattern instantiates it: the right side of the definition
+// is evaluated with the type parameters bound to the type arguments.
+type GenericPattern struct {
+	node            *parse.PatternDefinition
+	constraints     []Pattern
+	erased          Pattern //right side evaluated with the type parameters bound to their constraint
+	definitionState *State
+
+	SerializableMixin
+}
+
+func (p *GenericPattern) Test(v Value, state RecTestCallState) bool {
+	state.StartCall()
+	defer state.FinishCall()
+
+	other, ok := v.(*GenericPattern)
+	return ok && other.node == p.node
+}
+
+func (p *GenericPattern) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
+	w.WriteName("generic-pattern")
+
+	name, ok := p.node.PatternName()
+	if !ok {
+		return
+	}
+	w.WriteString("(%")
+	w.WriteString(name)
+	w.WriteString("<")
+	for i, typeParam := range p.node.TypeParameters {
+		if i != 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(typeParam.Name.Name)
+	}
+	w.WriteString(">)")
+}
+
+func (p *GenericPattern) HasUnderlyingPattern() bool {
+	return p.erased.HasUnderlyingPattern()
+}
+
+func (p *GenericPattern) TestValue(v Value, state RecTestCallState) bool {
+	return p.erased.TestValue(v, state)
+}
+
+func (p *GenericPattern) Call(ctx *Context, values []Value) (Pattern, error) {
+	typeParams := p.node.TypeParameters
+
+	if len(values) != len(typeParams) {
+		return nil, errors.New(fmtInvalidNumberOfTypeArgs(len(values), len(typeParams)))
+	}
+
+	typeArgs := make([]Pattern, len(values))
+
+	for i, val := range values {
+		typeArg, ok := val.(Pattern)
+		if !ok {
+			return nil, errors.New(TYPE_ARGUMENTS_SHOULD_BE_PATTERNS)
+		}
+
+		constraint := p.constraints[i]
+		if !constraint.TestValue(typeArg.SymbolicValue(), RecTestCallState{}) {
+			msg := fmtTypeArgDoesNotSatisfyConstraint(typeParams[i].Name.Name, typeArg.SymbolicValue(), constraint.SymbolicValue())
+			return nil, errors.New(msg)
+		}
+		typeArgs[i] = typeArg
+	}
+
+	stateFork := p.definitionState.fork()
+	//errors have already been reported during the evaluation of the definition.
+	stateFork.symbolicData = NewSymbolicData()
+	stateFork.typeParameterBindings = p.definitionState.bindTypeParameters(typeParams, typeArgs)
+
+	return evalPatternNode(p.node.Right, stateFork)
+}
+
+func (p *GenericPattern) SymbolicValue() Value {
+	return p.erased.SymbolicValue()
+}
+
+func (p *GenericPattern) StringPattern() (StringPattern, bool) {
+	return nil, false
+}
+
+func (p *GenericPattern) IteratorElementKey() Value {
+	return ANY_INT
+}
+
+func (p *GenericPattern) IteratorElementValue() Value {
+	return ANY
+}
+
+func (p *GenericPattern) WidestOfType() Value {
+	return ANY_PATTERN
+}
+
 type FunctionPattern struct {
 	function *Function //if nil any function is matched
 
@@ -3550,6 +3649,73 @@ func (p *MutationPattern) WidestOfType() Value {
 	return ANY_MUTATION_PATTERN
 }
 
+// An ErrorPattern represents a symbolic ErrorPattern.
+type ErrorPattern struct {
+	kind string //if empty any error pattern is matched.
+
+	NotCallablePatternMixin
+	SerializableMixin
+}
+
+func NewErrorPattern(kind string) *ErrorPattern {
+	return &ErrorPattern{kind: kind}
+}
+
+// Kind returns the kind of the errors matched by the pattern, ok is false if the pattern is any error pattern.
+func (p *ErrorPattern) Kind() (kind string, ok bool) {
+	return p.kind, p.kind != ""
+}
+
+func (p *ErrorPattern) Test(v Value, state RecTestCallState) bool {
+	state.StartCall()
+	defer state.FinishCall()
+
+	other, ok := v.(*ErrorPattern)
+	return ok && (p.kind == "" || p.kind == other.kind)
+}
+
+func (p *ErrorPattern) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
+	if p.kind == "" {
+		w.WriteName("error-pattern")
+		return
+	}
+	w.WriteName("error-pattern(")
+	w.WriteStringF("#%s", p.kind)
+	w.WriteByte(')')
+}
+
+func (p *ErrorPattern) HasUnderlyingPattern() bool {
+	return true
+}
+
+func (p *ErrorPattern) TestValue(v Value, state RecTestCallState) bool {
+	state.StartCall()
+	defer state.FinishCall()
+
+	err, ok := v.(*Error)
+	return ok && (p.kind == "" || p.kind == err.kind)
+}
+
+func (p *ErrorPattern) SymbolicValue() Value {
+	return NewErrorWithKind(ANY, p.kind)
+}
+
+func (p *ErrorPattern) StringPattern() (StringPattern, bool) {
+	return nil, false
+}
+
+func (p *ErrorPattern) IteratorElementKey() Value {
+	return ANY_INT
+}
+
+func (p *ErrorPattern) IteratorElementValue() Value {
+	return p.SymbolicValue()
+}
+
+func (p *ErrorPattern) WidestOfType() Value {
+	return ANY_ERROR_PATTERN
+}
+
 // A PatternNamespace represents a symbolic PatternNamespace.
 type PatternNamespace struct {
 	entries map[string]Pattern //if nil, matches any pattern namespace
@@ -10,7 +10,7 @@ import (
 var (
 	_ = []PotentiallySharable{
 		(*Object)(nil), (*InoxFunction)(nil), (*GoFunction)(nil), (*RingBuffer)(nil),
-		(*Mapping)(nil), (*ValueHistory)(nil),
+		(*Mapping)(nil), (*ValueHistory)(nil), (*Channel)(nil),
 	}
 
 	ErrMissingNodeValue = errors.New("missing node value")
@@ -23,6 +23,10 @@ type State struct {
 	inPreinit             bool
 	recursiveFunctionName string
 
+	//patterns bound to the type parameters of the generic functions and pattern definitions being evaluated,
+	//the map should not be mutated: it is shared between forks.
+	typeParameterBindings map[*parse.TypeParameter]Pattern
+
 	callStack             []inoxCallInfo
 	topLevelSelf          Value // can be nil
 	returnType            Value
@@ -42,6 +46,7 @@ type State struct {
 
 	tempSymbolicGoFunctionErrors         []string
 	tempSymbolicGoFunctionWarnings       []string
+	tempSymbolicGoFunctionMissingPerms   []MissingPermission
 	tempSymbolicGoFunctionParameters     *[]Value
 	tempSymbolicGoFunctionParameterNames []string
 	tempSymbolicGoFunctionIsVariadic     bool
@@ -53,6 +58,8 @@ type State struct {
 
 	testedProgram *TestedProgram //can be nil
 
+	checkpointing *topLevelCheckpointing //nil if no top level checkpoints are saved
+
 	//nil if no project
 	projectFilesystem billy.Filesystem
 }
@@ -513,6 +520,7 @@ func (state *State) currentLocalScopeData() ScopeData {
 			Name:               k,
 			Value:              v.value,
 			DefinitionPosition: v.definitionPosition,
+			IsConstant:         v.isConstant,
 		})
 	}
 	return ScopeData{Variables: vars}
@@ -526,6 +534,7 @@ func (state *State) currentGlobalScopeData() ScopeData {
 			Name:               k,
 			Value:              v.value,
 			DefinitionPosition: v.definitionPosition,
+			IsConstant:         v.isConstant,
 		})
 	}
 	return ScopeData{Variables: vars}
@@ -571,6 +580,7 @@ func (state *State) fork() *State {
 	child.basePatternNamespaces = state.basePatternNamespaces
 	child.checkXMLInterpolation = state.checkXMLInterpolation
 	child.projectFilesystem = state.projectFilesystem
+	child.typeParameterBindings = state.typeParameterBindings
 
 	globalScopeCopy := &scopeInfo{
 		variables: make(map[string]varSymbolicInfo, 0),
@@ -606,6 +616,19 @@ func (state *State) fork() *State {
 	return child
 }
 
+// bindTypeParameters returns a copy of the state's type parameter bindings with additional bindings,
+// the result is intended to be set as the new bindings of a state.
+func (state *State) bindTypeParameters(params []*parse.TypeParameter, patterns []Pattern) map[*parse.TypeParameter]Pattern {
+	bindings := make(map[*parse.TypeParameter]Pattern, len(state.typeParameterBindings)+len(params))
+	for param, pattern := range state.typeParameterBindings {
+		bindings[param] = pattern
+	}
+	for i, param := range params {
+		bindings[param] = patterns[i]
+	}
+	return bindings
+}
+
 func (state *State) join(forks ...*State) {
 	scope := state.scopeStack[len(state.scopeStack)-1]
 
@@ -664,6 +687,18 @@ func (state *State) consumeSymbolicGoFunctionWarnings(fn func(msg string)) {
 	state.tempSymbolicGoFunctionWarnings = state.tempSymbolicGoFunctionWarnings[:0]
 }
 
+func (state *State) addSymbolicGoFunctionMissingPermission(perm MissingPermission) {
+	state.tempSymbolicGoFunctionMissingPerms = append(state.tempSymbolicGoFunctionMissingPerms, perm)
+}
+
+// consumeSymbolicGoFunctionMissingPermissions records the permissions that might be missing to evaluate $node.
+func (state *State) consumeSymbolicGoFunctionMissingPermissions(node parse.Node) {
+	for _, perm := range state.tempSymbolicGoFunctionMissingPerms {
+		state.symbolicData.AddMissingPermission(node, perm)
+	}
+	state.tempSymbolicGoFunctionMissingPerms = state.tempSymbolicGoFunctionMissingPerms[:0]
+}
+
 func (state *State) setSymbolicGoFunctionParameters(parameters *[]Value, names []string, isVariadic bool) {
 	if state.tempSymbolicGoFunctionParameters != nil {
 		panic(errors.New("a temporary signature is already present"))
@@ -2,6 +2,7 @@ package symbolic
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/inoxlang/inox/internal/parse"
 	pprint "github.com/inoxlang/inox/internal/prettyprint"
@@ -14,6 +15,15 @@ const (
 	TEST_ITEM_META__PASS_LIVE_FS_COPY  = "pass-live-fs-copy-to-subtests"
 	TEST_ITEM_META__MAIN_DB_SCHEMA     = "main-db-schema"
 	TEST_ITEM_META__MAIN_DB_MIGRATIONS = "main-db-migrations"
+	TEST_ITEM_META__MAIN_DB_FIXTURES   = "main-db-fixtures"
+	TEST_SUITE_META__PARALLEL_PROPNAME = "parallel"
+
+	DB_FIXTURES_JSON_FILE_EXTENSION = ".json"
+	DB_FIXTURES_INOX_FILE_EXTENSION = ".ix"
+
+	BENCHMARK_META__WARMUP_PROPNAME   = "warmup"
+	BENCHMARK_META__SAMPLES_PROPNAME  = "samples"
+	BENCHMARK_META__DURATION_PROPNAME = "duration"
 )
 
 var (
@@ -24,9 +34,13 @@ var (
 		TEST_ITEM_META__FS_PROPNAME:       ANY_FS_SNAPSHOT_IL,
 		TEST_ITEM_META__PASS_LIVE_FS_COPY: ANY_BOOL,
 
+		//maximum number of test cases running at the same time
+		TEST_SUITE_META__PARALLEL_PROPNAME: ANY_INT,
+
 		//program testing
 		TEST_ITEM_META__PROGRAM_PROPNAME: ANY_ABS_NON_DIR_PATH,
 		TEST_ITEM_META__MAIN_DB_SCHEMA:   ANY_OBJECT_PATTERN,
+		TEST_ITEM_META__MAIN_DB_FIXTURES: ANY_ABS_NON_DIR_PATH,
 		TEST_ITEM_META__MAIN_DB_MIGRATIONS: NewInexactObject(
 			map[string]Serializable{
 				DB_MIGRATION__DELETIONS_PROP_NAME:       ANY_DICT,
@@ -45,8 +59,26 @@ var (
 		),
 	}, nil, nil))
 
+	BENCHMARK__EXPECTED_META_VALUE = NewMultivalue(ANY_STR_LIKE, NewExactObject(
+		map[string]Serializable{
+			TEST_ITEM_META__NAME_PROPNAME:     ANY_STR_LIKE,
+			BENCHMARK_META__WARMUP_PROPNAME:   ANY_INT,
+			BENCHMARK_META__SAMPLES_PROPNAME:  ANY_INT,
+			BENCHMARK_META__DURATION_PROPNAME: ANY_DURATION,
+		},
+		//optional entries
+		map[string]struct{}{
+			TEST_ITEM_META__NAME_PROPNAME:     {},
+			BENCHMARK_META__WARMUP_PROPNAME:   {},
+			BENCHMARK_META__SAMPLES_PROPNAME:  {},
+			BENCHMARK_META__DURATION_PROPNAME: {},
+		},
+		nil,
+	))
+
 	ANY_TEST_SUITE = &TestSuite{}
 	ANY_TEST_CASE  = &TestCase{}
+	ANY_BENCHMARK  = &Benchmark{}
 
 	ANY_TESTED_PROGRAM_OR_NIL = NewMultivalue(ANY_TESTED_PROGRAM, Nil)
 	ANY_TESTED_PROGRAM        = &TestedProgram{databases: ANY_MUTABLE_ENTRIES_NAMESPACE}
@@ -151,6 +183,83 @@ func (s *TestCase) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.Pretty
 	w.WriteName("test-case")
 }
 
+// A Benchmark represents a symbolic Benchmark.
+type Benchmark struct {
+	UnassignablePropsMixin
+	_ int
+}
+
+func (b *Benchmark) Test(v Value, state RecTestCallState) bool {
+	state.StartCall()
+	defer state.FinishCall()
+
+	switch v.(type) {
+	case *Benchmark:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *Benchmark) Run(ctx *Context, options ...*Option) (*LThread, *Error) {
+	return ANY_LTHREAD, nil
+}
+
+func (b *Benchmark) WidestOfType() Value {
+	return ANY_BENCHMARK
+}
+
+func (b *Benchmark) GetGoMethod(name string) (*GoFunction, bool) {
+	switch name {
+	case "run":
+		return WrapGoMethod(b.Run), true
+	}
+	return nil, false
+}
+
+func (b *Benchmark) Prop(name string) Value {
+	method, ok := b.GetGoMethod(name)
+	if !ok {
+		panic(FormatErrPropertyDoesNotExist(name, b))
+	}
+	return method
+}
+
+func (*Benchmark) PropertyNames() []string {
+	return []string{"run"}
+}
+
+func (b *Benchmark) PrettyPrint(w pprint.PrettyPrintWriter, config *pprint.PrettyPrintConfig) {
+	w.WriteName("benchmark")
+}
+
+// checkBenchmarkMeta evaluates & checks the meta value of a benchmark.
+func checkBenchmarkMeta(node parse.Node, state *State) error {
+	meta, err := _symbolicEval(node, state, evalOptions{
+		expectedValue: BENCHMARK__EXPECTED_META_VALUE,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch m := meta.(type) {
+	case *Object:
+		for _, propName := range []string{BENCHMARK_META__WARMUP_PROPNAME, BENCHMARK_META__SAMPLES_PROPNAME} {
+			if !m.hasProperty(propName) {
+				continue
+			}
+			count, ok := m.Prop(propName).(*Int)
+			if ok && count.hasValue && count.value < 0 {
+				state.addError(makeSymbolicEvalError(node, state, fmtBenchmarkMetaPropShouldNotBeNegative(propName)))
+			}
+		}
+	case StringLike:
+	default:
+		state.addError(makeSymbolicEvalError(node, state, META_VAL_OF_BENCHMARK_SHOULD_EITHER_BE_A_STRING_OR_A_RECORD))
+	}
+	return nil
+}
+
 // checkTestItemMeta evaluates & checks the meta value of a test item, it returns a *CurrentTest for test cases.
 func checkTestItemMeta(node parse.Node, state *State, isTestCase bool) (currentTest *CurrentTest, testedProgram *TestedProgram, _ error) {
 	meta, err := _symbolicEval(node, state, evalOptions{
@@ -168,11 +277,20 @@ func checkTestItemMeta(node parse.Node, state *State, isTestCase bool) (currentT
 
 	switch m := meta.(type) {
 	case *Object:
+		if m.hasProperty(TEST_SUITE_META__PARALLEL_PROPNAME) {
+			if isTestCase {
+				state.addError(makeSymbolicEvalError(node, state, PARALLELISM_CAN_ONLY_BE_SPECIFIED_BY_TEST_SUITES))
+			} else if parallelism, ok := m.Prop(TEST_SUITE_META__PARALLEL_PROPNAME).(*Int); ok && parallelism.hasValue && parallelism.value <= 0 {
+				state.addError(makeSymbolicEvalError(node, state, PARALLELISM_SHOULD_BE_POSITIVE))
+			}
+		}
+
 		hasMainDatabaseSchema := m.hasProperty(TEST_ITEM_META__MAIN_DB_SCHEMA)
 		hasMainDatabaseMigrations := m.hasProperty(TEST_ITEM_META__MAIN_DB_MIGRATIONS)
+		hasMainDatabaseFixtures := m.hasProperty(TEST_ITEM_META__MAIN_DB_FIXTURES)
 		hasProgram := m.hasProperty(TEST_ITEM_META__PROGRAM_PROPNAME)
 
-		if parentTestedProgram != nil && !hasProgram && !hasMainDatabaseSchema && !hasMainDatabaseMigrations {
+		if parentTestedProgram != nil && !hasProgram && !hasMainDatabaseSchema && !hasMainDatabaseMigrations && !hasMainDatabaseFixtures {
 			//inherit tested program
 			testedProgram = parentTestedProgram
 			currentTest = &CurrentTest{testedProgram: testedProgram}
@@ -186,6 +304,9 @@ func checkTestItemMeta(node parse.Node, state *State, isTestCase bool) (currentT
 			if hasMainDatabaseMigrations {
 				state.addError(makeSymbolicEvalError(node, state, MAIN_DB_MIGRATIONS_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM))
 			}
+			if hasMainDatabaseFixtures {
+				state.addError(makeSymbolicEvalError(node, state, MAIN_DB_FIXTURES_CAN_ONLY_BE_SPECIFIED_WHEN_TESTING_A_PROGRAM))
+			}
 			return
 		}
 		//else if the test item tests a program
@@ -200,8 +321,13 @@ func checkTestItemMeta(node parse.Node, state *State, isTestCase bool) (currentT
 		}
 		currentTest = &CurrentTest{testedProgram: testedProgram}
 
+		if hasMainDatabaseFixtures && !hasMainDatabaseSchema {
+			state.addError(makeSymbolicEvalError(node, state, MAIN_DB_FIXTURES_REQUIRE_A_MAIN_DB_SCHEMA))
+		}
+
 		if hasMainDatabaseSchema {
-			if !hasMainDatabaseMigrations {
+			//the top-level entities that are not initialized by the fixtures should be initialized by the migrations.
+			if !hasMainDatabaseMigrations && !hasMainDatabaseFixtures {
 				state.addError(makeSymbolicEvalError(node, state, MISSING_MAIN_DB_MIGRATIONS_PROPERTY))
 			}
 
@@ -217,6 +343,18 @@ func checkTestItemMeta(node parse.Node, state *State, isTestCase bool) (currentT
 			}
 		}
 
+		if hasMainDatabaseFixtures {
+			fixtures, ok := m.Prop(TEST_ITEM_META__MAIN_DB_FIXTURES).(*Path)
+			if ok && fixtures.hasValue {
+				ext := filepath.Ext(fixtures.value)
+				if ext != DB_FIXTURES_JSON_FILE_EXTENSION && ext != DB_FIXTURES_INOX_FILE_EXTENSION {
+					state.addError(makeSymbolicEvalError(node, state, INVALID_MAIN_DB_FIXTURES_FILE_EXTENSION))
+				} else if info, err := state.projectFilesystem.Stat(fixtures.value); err != nil || !info.Mode().IsRegular() {
+					state.addError(makeSymbolicEvalError(node, state, fmtMainDatabaseFixturesFileNotFound(fixtures.value)))
+				}
+			}
+		}
+
 		program, ok := m.Prop(TEST_ITEM_META__PROGRAM_PROPNAME).(*Path)
 		if !ok || program.pattern == nil || program.pattern.absoluteness != AbsolutePath || program.pattern.dirConstraint != DirPath {
 			return
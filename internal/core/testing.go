@@ -38,9 +38,18 @@ type TestingState struct {
 	Item                   TestItem //can be nil
 	ItemFullName           string   //can be empty
 	TestedProgram          *Module  //can be nil
+
+	IsBenchmarkingEnabled bool               //if true the benchmarks encountered in test suites are run
+	BenchmarkBaseline     *BenchmarkBaseline //can be nil, used to detect regressions
+	BenchmarkResults      []*BenchmarkResult
+
+	UpdateGoldenFiles bool           //if true golden file assertions (re)write the golden files instead of comparing
+	GoldenFilesystem  afs.Filesystem //filesystem of the tested project, can be nil
+
+	parallelTestCases *parallelTestCaseRunner //only set in test suites running their test cases in parallel
 }
 
-// A TestItem is a TestSuite or a TestCase.
+// A TestItem is a TestSuite, a TestCase or a Benchmark.
 type TestItem interface {
 	ItemName() (string, bool)
 	ParentChunk() *parse.ParsedChunkSource
@@ -60,6 +69,8 @@ type TestSuite struct {
 	programProject                   Project        //set if .testedProgramPath is set
 	mainDatabaseSchema               *ObjectPattern //can be nil
 	mainDatabaseMigrations           *Object        //can be nil
+	mainDatabaseFixtures             Path           //can be empty
+	parallelism                      int            //maximum number of test cases running at the same time, 0 if not specified
 
 	node         *parse.TestSuiteExpression
 	module       *Module // module executed when running the test suite
@@ -144,6 +155,13 @@ func NewTestSuite(input TestSuiteCreationInput) (*TestSuite, error) {
 				suite.mainDatabaseSchema = v.(*ObjectPattern)
 			case symbolic.TEST_ITEM_META__MAIN_DB_MIGRATIONS:
 				suite.mainDatabaseMigrations = v.(*Object)
+			case symbolic.TEST_ITEM_META__MAIN_DB_FIXTURES:
+				suite.mainDatabaseFixtures = v.(Path)
+			case symbolic.TEST_SUITE_META__PARALLEL_PROPNAME:
+				suite.parallelism = int(v.(Int))
+				if suite.parallelism <= 0 {
+					return errors.New(symbolic.PARALLELISM_SHOULD_BE_POSITIVE)
+				}
 			}
 			return nil
 		})
@@ -164,6 +182,7 @@ func NewTestSuite(input TestSuiteCreationInput) (*TestSuite, error) {
 			suite.programProject = parentTestSuite.programProject
 			suite.mainDatabaseSchema = parentTestSuite.mainDatabaseSchema
 			suite.mainDatabaseMigrations = parentTestSuite.mainDatabaseMigrations
+			suite.mainDatabaseFixtures = parentTestSuite.mainDatabaseFixtures
 		}
 	}
 
@@ -198,6 +217,11 @@ func (s *TestSuite) FilesystemSnapshot() (FilesystemSnapshot, bool) {
 	return nil, false
 }
 
+// RunsTestCasesInParallel returns true if the test cases of the suite are executed concurrently.
+func (s *TestSuite) RunsTestCasesInParallel() bool {
+	return s.parallelism > 1
+}
+
 func (s *TestSuite) Run(ctx *Context, options ...Option) (*LThread, error) {
 	if !s.node.IsStatement {
 		//TODO: if the TestSuiteExpression node is not a statement,
@@ -236,7 +260,7 @@ func (s *TestSuite) Run(ctx *Context, options ...Option) (*LThread, error) {
 		return nil, fmt.Errorf("testing: following permission is required for running tests: %w", err)
 	}
 
-	return runTestItem(ctx, spawnerState, s, s.module, fsProvider, timeout, parentTestSuite, "", nil, nil, nil, nil, nil)
+	return runTestItem(ctx, spawnerState, s, s.module, fsProvider, timeout, parentTestSuite, "", nil, nil, nil, nil, nil, "")
 }
 
 func (s *TestSuite) GetGoMethod(name string) (*GoFunction, bool) {
@@ -273,6 +297,7 @@ type TestCase struct {
 	programProject                   Project        //set if .testedProgramPath is set
 	mainDatabaseSchema               *ObjectPattern //can be nil
 	mainDatabaseMigrations           *Object        //can be nil
+	mainDatabaseFixtures             Path           //can be empty
 
 	node *parse.TestCaseExpression
 
@@ -373,6 +398,8 @@ func NewTestCase(input TestCaseCreationInput) (*TestCase, error) {
 				testCase.mainDatabaseSchema = v.(*ObjectPattern)
 			case symbolic.TEST_ITEM_META__MAIN_DB_MIGRATIONS:
 				testCase.mainDatabaseMigrations = v.(*Object)
+			case symbolic.TEST_ITEM_META__MAIN_DB_FIXTURES:
+				testCase.mainDatabaseFixtures = v.(Path)
 			}
 			return nil
 		})
@@ -453,6 +480,7 @@ func (c *TestCase) Run(ctx *Context, options ...Option) (*LThread, error) {
 	programProject := c.programProject
 	mainDatabaseSchema := c.mainDatabaseSchema
 	mainDatabaseMigrations := c.mainDatabaseMigrations
+	mainDatabaseFixtures := c.mainDatabaseFixtures
 
 	var programModuleCache *Module
 	var programDatabasePermissions []Permission
@@ -463,6 +491,7 @@ func (c *TestCase) Run(ctx *Context, options ...Option) (*LThread, error) {
 		programProject = parentTestSuite.programProject
 		mainDatabaseSchema = parentTestSuite.mainDatabaseSchema
 		mainDatabaseMigrations = parentTestSuite.mainDatabaseMigrations
+		mainDatabaseFixtures = parentTestSuite.mainDatabaseFixtures
 
 		if spawnerState.TestingState.TestedProgram == nil {
 			panic(ErrUnreachable)
@@ -494,6 +523,7 @@ func (c *TestCase) Run(ctx *Context, options ...Option) (*LThread, error) {
 		programDatabasePermissions,
 		mainDatabaseSchema,
 		mainDatabaseMigrations,
+		mainDatabaseFixtures,
 	)
 }
 
@@ -536,12 +566,14 @@ func runTestItem(
 	programDatabasePermissions []Permission,
 	mainDatabaseSchema *ObjectPattern, //can be nil
 	mainDatabaseMigrations *Object, //can be nil
+	mainDatabaseFixtures Path, //can be empty
 ) (*LThread, error) {
 
 	suite, isTestSuite := testItem.(*TestSuite)
 	_, isTestCase := testItem.(*TestCase)
+	_, isBenchmark := testItem.(*Benchmark)
 
-	if !isTestCase && !isTestSuite {
+	if !isTestCase && !isTestSuite && !isBenchmark {
 		panic(ErrUnreachable)
 	}
 
@@ -662,11 +694,27 @@ func runTestItem(
 		fls = testItemFSProvider.getFilesystemOnlyOnce()
 	}
 
+	limits := manifest.Limits
+
+	//benchmarks report the CPU time of their iterations, so the limit is added if it is not already present.
+	if isBenchmark {
+		_, err := parentCtx.GetTotal(EXECUTION_CPU_TIME_LIMIT_NAME)
+		hasCPUTimeLimit := err == nil
+
+		if !hasCPUTimeLimit && !slices.ContainsFunc(limits, func(l Limit) bool { return l.Name == EXECUTION_CPU_TIME_LIMIT_NAME }) {
+			cpuTimeLimit, err := GetLimit(parentCtx, EXECUTION_CPU_TIME_LIMIT_NAME, Duration(MAX_LIMIT_VALUE))
+			if err != nil {
+				return nil, err
+			}
+			limits = append(slices.Clone(limits), cpuTimeLimit)
+		}
+	}
+
 	lthreadCtx := NewContext(ContextConfig{
 		Kind:            TestingContext,
 		ParentContext:   parentCtx,
 		Permissions:     permissions,
-		Limits:          manifest.Limits,
+		Limits:          limits,
 		HostDefinitions: manifest.HostDefinitions,
 
 		Filesystem: fls,
@@ -693,6 +741,13 @@ func runTestItem(
 	var testedProgramDatabases *Namespace
 
 	if programToExecute != "" {
+		//test cases running in parallel should not share the databases of the tested program,
+		//and test cases with database fixtures should start with fresh databases.
+		isParallelTestCase := isTestCase && parentTestSuite != nil && parentTestSuite.RunsTestCasesInParallel()
+		if isParallelTestCase || mainDatabaseFixtures != "" {
+			programProject = newIsolatedDatabasesProject(programProject)
+		}
+
 		programState, _, _, err := PrepareLocalModule(ModulePreparationArgs{
 			FullAccessToDatabases:   true,
 			ForceExpectSchemaUpdate: true,
@@ -724,6 +779,16 @@ func runTestItem(
 			if !ok {
 				return nil, fmt.Errorf("testing: the program to test (%q) has not a main database", programToExecute)
 			}
+
+			if mainDatabaseFixtures != "" {
+				fixtures, err := ReadDatabaseFixtures(programState.Ctx, mainDatabaseFixtures, mainDatabaseSchema, URL(db.Resource().UnderlyingString()+"/"))
+				if err != nil {
+					programState.Ctx.CancelGracefully()
+					return nil, fmt.Errorf("testing: %w", err)
+				}
+				mainDatabaseMigrations = fixtures.AddToMigrations(programState.Ctx, mainDatabaseMigrations)
+			}
+
 			db.UpdateSchema(programState.Ctx, mainDatabaseSchema, mainDatabaseMigrations)
 		}
 
@@ -756,7 +821,7 @@ func runTestItem(
 	//Therefore the globals cannot be modified by another goroutine.
 
 	var currentTest *CurrentTest
-	if !isTestSuite {
+	if isTestCase {
 		currentTest = &CurrentTest{
 			&TestedProgram{
 				lthread:   testedProgramThread,
@@ -779,6 +844,12 @@ func runTestItem(
 		TestFilters:      spawnerState.TestingState.Filters,
 		TestItem:         testItem,
 		TestedProgram:    testedProgramModule,
+
+		IsBenchmarkingEnabled: spawnerState.TestingState.IsBenchmarkingEnabled,
+		BenchmarkBaseline:     spawnerState.TestingState.BenchmarkBaseline,
+
+		UpdateGoldenFiles: spawnerState.TestingState.UpdateGoldenFiles,
+		GoldenFilesystem:  spawnerState.TestingState.GoldenFilesystem,
 	})
 
 	if err != nil {
@@ -799,11 +870,17 @@ func runTestItem(
 // getTestItemFilesystemProvider retrieves or create the filesystem for a test item and returns a testItemFSProvider providing it.
 //   - if the test item has a filesystem snapshot then a filesystem is created from it.
 //   - else if the parent test suite is nil then the parent state's filesystem is returned.
-//   - else if the parent test suite is configured to pass a shapshot of its live filesystem
+//   - else if the parent test suite is configured to pass a shapshot of its live filesystem, or if the test item is
+//     a test case running in parallel with other test cases and the parent suite has no filesystem snapshot,
 //     then its filesystem is snapshoted and a filesystem is created from it.
 //   - else if the parent test suite has a filesystem snapshot then a filesystem is created from it.
 //   - else the parent state's filesystem is returned.
 func getTestItemFilesystemProvider(test TestItem, parentTestSuite *TestSuite, spawnerState *GlobalState) (*fsProvider, error) {
+	_, isTestCase := test.(*TestCase)
+	//test cases running in parallel should not share the filesystem of the suite.
+	isParallelTestCase := isTestCase && parentTestSuite != nil &&
+		parentTestSuite.RunsTestCasesInParallel() && parentTestSuite.filesystemSnapshot == nil
+
 	if snapshot, ok := test.FilesystemSnapshot(); ok {
 		fls, err := snapshot.NewAdaptedFilesystem(TEST__MAX_FS_STORAGE_HINT)
 		if err != nil {
@@ -825,7 +902,7 @@ func getTestItemFilesystemProvider(test TestItem, parentTestSuite *TestSuite, sp
 				return spawnerState.Ctx
 			},
 		}, nil
-	} else if parentTestSuite.passLiveFilesystemCopyToSubTests {
+	} else if parentTestSuite.passLiveFilesystemCopyToSubTests || isParallelTestCase {
 		parentFls := spawnerState.Ctx.GetFileSystem()
 
 		if snapshotable, ok := parentFls.(SnapshotableFilesystem); ok {
@@ -973,12 +1050,19 @@ func (f TestFilter) IsTestEnabled(absoluteFilePath string, item TestItem, parent
 			stmtSpan != f.NodeSpan &&
 			(stmtSpan.Start < f.NodeSpan.Start || stmtSpan.End > f.NodeSpan.End) {
 
-			if _, ok := item.(*TestCase); ok {
+			switch item.(type) {
+			case *TestCase:
 				return false, fmt.Sprintf(
 					"the test case is disabled because its span (%d:%d) does not match the filter's span (%d:%d)",
 					stmtSpan.Start, stmtSpan.End, f.NodeSpan.Start, f.NodeSpan.End)
-				//else check that the span is inside the test suite's span
-			} else if f.NodeSpan.Start < stmtSpan.Start || f.NodeSpan.End > stmtSpan.End {
+			case *Benchmark:
+				return false, fmt.Sprintf(
+					"the benchmark is disabled because its span (%d:%d) does not match the filter's span (%d:%d)",
+					stmtSpan.Start, stmtSpan.End, f.NodeSpan.Start, f.NodeSpan.End)
+			}
+
+			//check that the span is inside the test suite's span
+			if f.NodeSpan.Start < stmtSpan.Start || f.NodeSpan.End > stmtSpan.End {
 				return false, fmt.Sprintf(
 					"the test is disabled because its span (%d:%d) does not includes the filter's span (%d:%d)",
 					stmtSpan.Start, stmtSpan.End, f.NodeSpan.Start, f.NodeSpan.End)
@@ -0,0 +1,325 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/inoxlang/inox/internal/commonfmt"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+)
+
+const (
+	DEFAULT_BENCHMARK_WARMUP_ITERATIONS = 1
+	DEFAULT_BENCHMARK_SAMPLE_COUNT      = 5
+	DEFAULT_BENCHMARK_SAMPLE_DURATION   = 100 * time.Millisecond
+
+	//maximum number of iterations of a single sample.
+	MAX_BENCHMARK_ITERATIONS_PER_SAMPLE = 1_000_000
+)
+
+var (
+	_ = TestItem((*Benchmark)(nil))
+)
+
+// A Benchmark represents a benchmark, Benchmark implements Value.
+// Benchmarks are TestItems that repeatedly execute their module in order
+// to measure its timing, allocations and CPU time.
+type Benchmark struct {
+	meta           Value
+	name           string        //can be empty
+	warmup         int           //number of iterations executed before the measurements
+	samples        int           //number of measured samples
+	sampleDuration time.Duration //targeted duration of each sample
+
+	node *parse.BenchmarkExpression
+
+	module       *Module // module executed when running the benchmark
+	parentModule *Module
+	parentChunk  *parse.ParsedChunkSource
+
+	positionStack     parse.SourcePositionStack //can be nil
+	formattedPosition string                    //can be empty
+
+	stats *BenchmarkStats //set by the lthread running the benchmark
+}
+
+type BenchmarkCreationInput struct {
+	Meta Value
+	Node *parse.BenchmarkExpression
+
+	ModChunk    *parse.Chunk
+	ParentState *GlobalState
+	ParentChunk *parse.ParsedChunkSource
+
+	//optional
+	PositionStack     parse.SourcePositionStack
+	FormattedLocation string
+}
+
+func NewBenchmark(input BenchmarkCreationInput) (*Benchmark, error) {
+	//implementation should not perform resource intensive operations or IO operations.
+
+	meta := input.Meta
+	parentChunk := input.ParentChunk
+
+	parsedChunk := &parse.ParsedChunkSource{
+		Node:   input.ModChunk,
+		Source: parentChunk.Source,
+	}
+
+	benchmarkMod := &Module{
+		MainChunk:        parsedChunk,
+		ManifestTemplate: parsedChunk.Node.Manifest,
+		ModuleKind:       BenchmarkModule,
+	}
+
+	benchmark := &Benchmark{
+		meta:           meta,
+		node:           input.Node,
+		warmup:         DEFAULT_BENCHMARK_WARMUP_ITERATIONS,
+		samples:        DEFAULT_BENCHMARK_SAMPLE_COUNT,
+		sampleDuration: DEFAULT_BENCHMARK_SAMPLE_DURATION,
+
+		module:       benchmarkMod,
+		parentModule: input.ParentState.Module,
+		parentChunk:  parentChunk,
+
+		positionStack: input.PositionStack,
+	}
+
+	switch m := meta.(type) {
+	case StringLike:
+		benchmark.name = m.GetOrBuildString()
+	case *Object:
+		m.ForEachEntry(func(k string, v Serializable) error {
+			switch k {
+			case symbolic.TEST_ITEM_META__NAME_PROPNAME:
+				strLike, ok := v.(StringLike)
+				if ok {
+					benchmark.name = strLike.GetOrBuildString()
+				}
+			case symbolic.BENCHMARK_META__WARMUP_PROPNAME:
+				benchmark.warmup = int(v.(Int))
+			case symbolic.BENCHMARK_META__SAMPLES_PROPNAME:
+				benchmark.samples = int(v.(Int))
+			case symbolic.BENCHMARK_META__DURATION_PROPNAME:
+				benchmark.sampleDuration = time.Duration(v.(Duration))
+			}
+			return nil
+		})
+	case NilT:
+	default:
+		panic(ErrUnreachable)
+	}
+
+	if benchmark.warmup < 0 {
+		return nil, errors.New("the number of warm-up iterations of a benchmark should not be negative")
+	}
+
+	if benchmark.samples <= 0 {
+		return nil, errors.New("the number of samples of a benchmark should be positive")
+	}
+
+	//clean formattedPosition
+	formattedPosition := strings.TrimSpace(input.FormattedLocation)
+	formattedPosition = strings.TrimSuffix(formattedPosition, ":")
+	benchmark.formattedPosition = formattedPosition
+
+	return benchmark, nil
+}
+
+// Module returns the module that contains the benchmark.
+func (b *Benchmark) ParentModule() *Module {
+	return b.parentModule
+}
+
+// Module returns the chunk that contains the benchmark.
+func (b *Benchmark) ParentChunk() *parse.ParsedChunkSource {
+	return b.parentChunk
+}
+
+func (b *Benchmark) ItemName() (string, bool) {
+	if b.name == "" {
+		return "", false
+	}
+	return b.name, true
+}
+
+func (b *Benchmark) Statement() parse.Node {
+	return b.node
+}
+
+func (b *Benchmark) FilesystemSnapshot() (FilesystemSnapshot, bool) {
+	return nil, false
+}
+
+// Stats returns the statistics computed by the last execution of the benchmark, the result is nil if the benchmark
+// has not been executed or if it failed.
+func (b *Benchmark) Stats() *BenchmarkStats {
+	return b.stats
+}
+
+func (b *Benchmark) Run(ctx *Context, options ...Option) (*LThread, error) {
+	var timeout time.Duration
+
+	if !b.node.IsStatement {
+		return nil, errors.New("running free benchmarks is not supported yet")
+	}
+
+	for _, opt := range options {
+		switch opt.Name {
+		case "timeout":
+			if timeout != 0 {
+				return nil, commonfmt.FmtErrOptionProvidedAtLeastTwice("timeout")
+			}
+			timeout = time.Duration(opt.Value.(Duration))
+		default:
+			return nil, commonfmt.FmtErrInvalidOptionName(opt.Name)
+		}
+	}
+
+	spawnerState := ctx.GetClosestState()
+	if spawnerState.TestingState.Item == nil {
+		panic(ErrUnreachable)
+	}
+	parentTestSuite := spawnerState.TestingState.Item.(*TestSuite)
+
+	fls, err := getTestItemFilesystemProvider(b, parentTestSuite, spawnerState)
+	if err != nil {
+		return nil, err
+	}
+
+	return runTestItem(
+		ctx,
+		spawnerState,
+		b,
+		b.module,
+		fls,
+		timeout,
+		parentTestSuite,
+
+		//program testing is not supported by benchmarks.
+		"",
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		"",
+	)
+}
+
+// measure is called by the lthread running the benchmark, it evaluates the chunk of the benchmark's module
+// several times and sets the .stats field. The global constants of the chunk are only evaluated once.
+func (b *Benchmark) measure(chunk *parse.Chunk, state *TreeWalkState) (Value, error) {
+	ctx := state.Global.Ctx
+
+	chunkWithoutConstants := *chunk
+	chunkWithoutConstants.GlobalConstantDeclarations = nil
+
+	evaluatedOnce := false
+
+	iterate := func(n int) error {
+		for i := 0; i < n; i++ {
+			if ctx.IsDone() {
+				return ctx.makeDoneContextError()
+			}
+			var err error
+			if evaluatedOnce {
+				_, err = TreeWalkEval(&chunkWithoutConstants, state)
+			} else {
+				_, err = TreeWalkEval(chunk, state)
+				evaluatedOnce = true
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	//warm-up
+
+	if err := iterate(b.warmup); err != nil {
+		return nil, err
+	}
+
+	//determine the number of iterations per sample using a single timed iteration.
+
+	start := time.Now()
+	if err := iterate(1); err != nil {
+		return nil, err
+	}
+	elapsed := max(time.Since(start), 1)
+
+	iterationsPerSample := int(b.sampleDuration / elapsed)
+	iterationsPerSample = min(max(1, iterationsPerSample), MAX_BENCHMARK_ITERATIONS_PER_SAMPLE)
+
+	//measure the samples
+
+	samples := make([]BenchmarkSample, 0, b.samples)
+	var memStats runtime.MemStats
+
+	for i := 0; i < b.samples; i++ {
+		cpuTimeBefore, cpuTimeErr := ctx.GetTotal(EXECUTION_CPU_TIME_LIMIT_NAME)
+
+		runtime.ReadMemStats(&memStats)
+		mallocsBefore := memStats.Mallocs
+		bytesBefore := memStats.TotalAlloc
+
+		start := time.Now()
+		if err := iterate(iterationsPerSample); err != nil {
+			return nil, err
+		}
+		elapsed := time.Since(start)
+
+		runtime.ReadMemStats(&memStats)
+
+		sample := BenchmarkSample{
+			Iterations: iterationsPerSample,
+			Duration:   elapsed,
+			Allocs:     memStats.Mallocs - mallocsBefore,
+			Bytes:      memStats.TotalAlloc - bytesBefore,
+		}
+
+		if cpuTimeErr == nil {
+			cpuTimeAfter, err := ctx.GetTotal(EXECUTION_CPU_TIME_LIMIT_NAME)
+			if err == nil {
+				//the remaining CPU time decreases as the benchmark runs.
+				sample.CPUTime = time.Duration(max(0, cpuTimeBefore-cpuTimeAfter))
+			}
+		}
+
+		samples = append(samples, sample)
+	}
+
+	b.stats = NewBenchmarkStats(samples)
+	return Nil, nil
+}
+
+func (b *Benchmark) GetGoMethod(name string) (*GoFunction, bool) {
+	switch name {
+	case "run":
+		return WrapGoMethod(b.Run), true
+	}
+	return nil, false
+}
+
+func (b *Benchmark) Prop(ctx *Context, name string) Value {
+	method, ok := b.GetGoMethod(name)
+	if !ok {
+		panic(FormatErrPropertyDoesNotExist(name, b))
+	}
+	return method
+}
+
+func (*Benchmark) SetProp(ctx *Context, name string, value Value) error {
+	return ErrCannotSetProp
+}
+
+func (*Benchmark) PropertyNames(ctx *Context) []string {
+	return []string{"run"}
+}
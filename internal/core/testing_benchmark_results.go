@@ -0,0 +1,272 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/muesli/termenv"
+)
+
+const (
+	//relative increase of the mean time per operation above which a benchmark is considered to have regressed.
+	BENCHMARK_REGRESSION_THRESHOLD = 0.10
+)
+
+// A BenchmarkSample is the measurement of several iterations of a benchmark.
+type BenchmarkSample struct {
+	Iterations int
+	Duration   time.Duration
+	CPUTime    time.Duration //zero if the CPU time is not available
+	Allocs     uint64
+	Bytes      uint64
+}
+
+// BenchmarkStats are the statistics computed from the samples of a benchmark, all values are per operation.
+type BenchmarkStats struct {
+	Samples    int `json:"samples"`
+	Iterations int `json:"iterations"` //total number of measured iterations
+
+	MeanNsPerOp   float64 `json:"meanNsPerOp"`
+	MedianNsPerOp float64 `json:"medianNsPerOp"`
+	MinNsPerOp    float64 `json:"minNsPerOp"`
+	MaxNsPerOp    float64 `json:"maxNsPerOp"`
+	StddevNsPerOp float64 `json:"stddevNsPerOp"`
+
+	CPUNsPerOp      float64 `json:"cpuNsPerOp"`
+	AllocsPerOp     float64 `json:"allocsPerOp"`
+	AllocBytesPerOp float64 `json:"allocBytesPerOp"`
+}
+
+func NewBenchmarkStats(samples []BenchmarkSample) *BenchmarkStats {
+	stats := &BenchmarkStats{
+		Samples: len(samples),
+	}
+
+	if len(samples) == 0 {
+		return stats
+	}
+
+	nsPerOp := make([]float64, len(samples))
+	var totalCPUTime time.Duration
+	var totalAllocs, totalBytes uint64
+
+	for i, sample := range samples {
+		iterations := max(sample.Iterations, 1)
+		nsPerOp[i] = float64(sample.Duration.Nanoseconds()) / float64(iterations)
+
+		stats.Iterations += iterations
+		totalCPUTime += sample.CPUTime
+		totalAllocs += sample.Allocs
+		totalBytes += sample.Bytes
+	}
+
+	sum := 0.0
+	for _, v := range nsPerOp {
+		sum += v
+	}
+	stats.MeanNsPerOp = sum / float64(len(nsPerOp))
+
+	variance := 0.0
+	for _, v := range nsPerOp {
+		variance += (v - stats.MeanNsPerOp) * (v - stats.MeanNsPerOp)
+	}
+	stats.StddevNsPerOp = math.Sqrt(variance / float64(len(nsPerOp)))
+
+	sorted := slices.Clone(nsPerOp)
+	slices.Sort(sorted)
+	stats.MinNsPerOp = sorted[0]
+	stats.MaxNsPerOp = sorted[len(sorted)-1]
+
+	if len(sorted)%2 == 1 {
+		stats.MedianNsPerOp = sorted[len(sorted)/2]
+	} else {
+		stats.MedianNsPerOp = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	stats.CPUNsPerOp = float64(totalCPUTime.Nanoseconds()) / float64(stats.Iterations)
+	stats.AllocsPerOp = float64(totalAllocs) / float64(stats.Iterations)
+	stats.AllocBytesPerOp = float64(totalBytes) / float64(stats.Iterations)
+
+	return stats
+}
+
+// A BenchmarkBaseline contains the statistics of previous benchmark runs, it is used to detect regressions.
+type BenchmarkBaseline struct {
+	Benchmarks map[string]*BenchmarkStats `json:"benchmarks"` //full name -> stats
+}
+
+// ParseBenchmarkBaseline parses a JSON file produced by MarshalBenchmarkResults.
+func ParseBenchmarkBaseline(content []byte) (*BenchmarkBaseline, error) {
+	baseline := &BenchmarkBaseline{}
+	if err := json.Unmarshal(content, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark baseline: %w", err)
+	}
+	if baseline.Benchmarks == nil {
+		baseline.Benchmarks = map[string]*BenchmarkStats{}
+	}
+	return baseline, nil
+}
+
+// MarshalBenchmarkResults returns the JSON representation of the statistics of successful benchmarks, the output
+// can be used as a baseline for subsequent runs.
+func MarshalBenchmarkResults(results []*BenchmarkResult) ([]byte, error) {
+	baseline := BenchmarkBaseline{Benchmarks: map[string]*BenchmarkStats{}}
+
+	for _, result := range results {
+		if result.Stats != nil {
+			baseline.Benchmarks[result.FullName] = result.Stats
+		}
+	}
+
+	return json.MarshalIndent(baseline, "", "  ")
+}
+
+type BenchmarkResult struct {
+	error     error
+	benchmark *Benchmark
+
+	FullName   string          `json:"fullName"`
+	Success    bool            `json:"success"`
+	Stats      *BenchmarkStats `json:"stats,omitempty"`    //nil if the benchmark failed
+	Baseline   *BenchmarkStats `json:"baseline,omitempty"` //nil if there is no baseline for the benchmark
+	Regression bool            `json:"regression"`
+
+	DarkModePrettyMessage  string //colorized
+	LightModePrettyMessage string //colorized
+	Message                string
+}
+
+// NewBenchmarkResult creates a result from the statistics of a benchmark, if baseline is not nil the statistics
+// are compared with the ones of the baseline. A regression makes the result unsuccessful.
+func NewBenchmarkResult(ctx *Context, executionError error, benchmark *Benchmark, fullName string, baseline *BenchmarkBaseline) (*BenchmarkResult, error) {
+	result := &BenchmarkResult{
+		error:     executionError,
+		benchmark: benchmark,
+		FullName:  fullName,
+		Success:   executionError == nil,
+	}
+
+	red := string(GetFullColorSequence(termenv.ANSIBrightRed, false))
+
+	if executionError != nil {
+		result.Message = "FAIL: unexpected error: " + utils.StripANSISequences(executionError.Error())
+	} else {
+		stats := benchmark.Stats()
+		if stats == nil {
+			return nil, fmt.Errorf("benchmark %q has no statistics", fullName)
+		}
+		result.Stats = stats
+
+		if baseline != nil {
+			result.Baseline = baseline.Benchmarks[fullName]
+		}
+
+		if result.Baseline != nil && IsBenchmarkRegression(result.Baseline, stats) {
+			result.Regression = true
+			result.Success = false
+		}
+
+		report := stats.String()
+		if result.Baseline != nil {
+			report += "\n" + fmtBenchmarkComparison(result.Baseline, stats)
+		}
+
+		if result.Regression {
+			prefix := "REGRESSION "
+			result.DarkModePrettyMessage = red + prefix + ANSI_RESET_SEQUENCE_STRING + report
+			result.LightModePrettyMessage = result.DarkModePrettyMessage
+			result.Message = prefix + report
+		} else {
+			prefix := "OK "
+			result.DarkModePrettyMessage = string(GetFullColorSequence(termenv.ANSIBrightGreen, false)) +
+				prefix + ANSI_RESET_SEQUENCE_STRING + report
+			result.LightModePrettyMessage = string(GetFullColorSequence(termenv.ANSIGreen, false)) +
+				prefix + ANSI_RESET_SEQUENCE_STRING + report
+			result.Message = prefix + report
+		}
+	}
+
+	name := benchmark.name
+	if name == "" {
+		name = benchmark.formattedPosition
+		if name == "" {
+			name = "?"
+		}
+	}
+
+	result.forEachNotEmptyMessage(func(s string, isDarkMode, isLightMode bool) string {
+		header := "BENCHMARK " + name
+
+		if isDarkMode {
+			color := string(DEFAULT_DARKMODE_DISCRETE_COLOR)
+			if !result.Success {
+				color = red
+			}
+			header = color + header + ANSI_RESET_SEQUENCE_STRING
+		} else if isLightMode {
+			color := string(DEFAULT_LIGHMODE_DISCRETE_COLOR)
+			if !result.Success {
+				color = red
+			}
+			header = color + header + ANSI_RESET_SEQUENCE_STRING
+		}
+
+		return header + "\n" + s
+	})
+
+	return result, nil
+}
+
+func (r *BenchmarkResult) forEachNotEmptyMessage(fn func(s string, isDarkMode, isLightMode bool) string) {
+	if r.DarkModePrettyMessage != "" {
+		r.DarkModePrettyMessage = fn(r.DarkModePrettyMessage, true, false)
+	}
+	if r.LightModePrettyMessage != "" {
+		r.LightModePrettyMessage = fn(r.LightModePrettyMessage, false, true)
+	}
+	if r.Message != "" {
+		r.Message = fn(r.Message, false, false)
+	}
+}
+
+// IsBenchmarkRegression returns true if the mean time per operation has increased by more than
+// BENCHMARK_REGRESSION_THRESHOLD and if the increase is not explained by the variability of the measurements.
+func IsBenchmarkRegression(baseline, current *BenchmarkStats) bool {
+	if baseline.MeanNsPerOp <= 0 {
+		return false
+	}
+
+	diff := current.MeanNsPerOp - baseline.MeanNsPerOp
+	relativeDiff := diff / baseline.MeanNsPerOp
+
+	return relativeDiff > BENCHMARK_REGRESSION_THRESHOLD && diff > max(baseline.StddevNsPerOp, current.StddevNsPerOp)
+}
+
+func (s *BenchmarkStats) String() string {
+	buf := &strings.Builder{}
+
+	fmt.Fprintf(buf, "%s/op (median %s, min %s, max %s, ±%s)",
+		fmtNsPerOp(s.MeanNsPerOp), fmtNsPerOp(s.MedianNsPerOp), fmtNsPerOp(s.MinNsPerOp), fmtNsPerOp(s.MaxNsPerOp), fmtNsPerOp(s.StddevNsPerOp))
+
+	fmt.Fprintf(buf, "  cpu %s/op  %.1f allocs/op  %.0f B/op  (%d samples, %d iterations)",
+		fmtNsPerOp(s.CPUNsPerOp), s.AllocsPerOp, s.AllocBytesPerOp, s.Samples, s.Iterations)
+
+	return buf.String()
+}
+
+func fmtBenchmarkComparison(baseline, current *BenchmarkStats) string {
+	percent := 0.0
+	if baseline.MeanNsPerOp > 0 {
+		percent = 100 * (current.MeanNsPerOp - baseline.MeanNsPerOp) / baseline.MeanNsPerOp
+	}
+	return fmt.Sprintf("baseline: %s/op (%+.1f%%)", fmtNsPerOp(baseline.MeanNsPerOp), percent)
+}
+
+func fmtNsPerOp(ns float64) string {
+	return time.Duration(ns).String()
+}
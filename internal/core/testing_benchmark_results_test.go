@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBenchmarkStats(t *testing.T) {
+	stats := NewBenchmarkStats([]BenchmarkSample{
+		{Iterations: 10, Duration: 1000 * time.Nanosecond, Allocs: 20, Bytes: 100},
+		{Iterations: 10, Duration: 3000 * time.Nanosecond, Allocs: 20, Bytes: 100},
+		{Iterations: 20, Duration: 4000 * time.Nanosecond, Allocs: 40, Bytes: 200, CPUTime: 4000 * time.Nanosecond},
+	})
+
+	assert.Equal(t, 3, stats.Samples)
+	assert.Equal(t, 40, stats.Iterations)
+	assert.InDelta(t, 200, stats.MeanNsPerOp, 0.001)
+	assert.InDelta(t, 200, stats.MedianNsPerOp, 0.001)
+	assert.InDelta(t, 100, stats.MinNsPerOp, 0.001)
+	assert.InDelta(t, 300, stats.MaxNsPerOp, 0.001)
+	assert.InDelta(t, 2, stats.AllocsPerOp, 0.001)
+	assert.InDelta(t, 10, stats.AllocBytesPerOp, 0.001)
+	assert.InDelta(t, 100, stats.CPUNsPerOp, 0.001)
+}
+
+func TestIsBenchmarkRegression(t *testing.T) {
+	baseline := &BenchmarkStats{MeanNsPerOp: 1000, StddevNsPerOp: 10}
+
+	assert.False(t, IsBenchmarkRegression(baseline, &BenchmarkStats{MeanNsPerOp: 900}))
+	assert.False(t, IsBenchmarkRegression(baseline, &BenchmarkStats{MeanNsPerOp: 1050}))
+	assert.True(t, IsBenchmarkRegression(baseline, &BenchmarkStats{MeanNsPerOp: 1200}))
+
+	//the increase is explained by the variability of the measurements.
+	assert.False(t, IsBenchmarkRegression(baseline, &BenchmarkStats{MeanNsPerOp: 1200, StddevNsPerOp: 300}))
+}
+
+func TestBenchmarkBaseline(t *testing.T) {
+	stats := &BenchmarkStats{Samples: 1, Iterations: 10, MeanNsPerOp: 1000}
+
+	content, err := MarshalBenchmarkResults([]*BenchmarkResult{
+		{FullName: "suite::bench", Stats: stats},
+		{FullName: "suite::failing-bench"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	baseline, err := ParseBenchmarkBaseline(content)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, map[string]*BenchmarkStats{"suite::bench": stats}, baseline.Benchmarks)
+}
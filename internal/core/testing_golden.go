@@ -0,0 +1,263 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/afs"
+	"github.com/inoxlang/inox/internal/core/permkind"
+	"github.com/inoxlang/inox/internal/utils"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	GOLDEN_FILE_FMODE = 0600
+	GOLDEN_DIR_FMODE  = 0700
+
+	//maximum number of differences reported by a failed golden file assertion.
+	MAX_REPORTED_GOLDEN_FILE_DIFFS = 20
+	MAX_GOLDEN_DIFF_VALUE_LENGTH   = 60
+)
+
+var (
+	ErrGoldenFileAssertionOutsideOfTest = errors.New("golden file assertions are only allowed in test suites and test cases")
+)
+
+// A GoldenFileDiffer returns a readable list of the differences between the expected and the actual renderings of a value,
+// the list is empty if the renderings are equivalent.
+type GoldenFileDiffer func(expected, actual []byte) ([]string, error)
+
+// CheckGoldenFile compares the rendering of a value with the content of a golden file, the path should be relative to
+// the directory of the test module. Golden files are read from (and written to) the filesystem of the tested
+// project, not the filesystem of the test. In update mode the golden file is (re)written and no comparison is performed,
+// the context should have the permission to write it.
+// The returned error is an *AssertionError if the renderings differ, callers are expected to panic with it.
+func CheckGoldenFile(ctx *Context, path Path, actual []byte, diff GoldenFileDiffer) error {
+	state := ctx.GetClosestState()
+	if !state.TestingState.IsTestingEnabled || state.Module == nil || !state.Module.ModuleKind.IsTestModule() {
+		return ErrGoldenFileAssertionOutsideOfTest
+	}
+
+	absPath, err := resolveGoldenFilePath(state.Module, path)
+	if err != nil {
+		return err
+	}
+
+	fls := state.TestingState.GoldenFilesystem
+	if fls == nil {
+		fls = ctx.GetFileSystem()
+	}
+
+	if state.TestingState.UpdateGoldenFiles {
+		if err := checkGoldenFileWritePermissions(ctx, fls, absPath); err != nil {
+			return err
+		}
+
+		if err := fls.MkdirAll(filepath.Dir(absPath), GOLDEN_DIR_FMODE); err != nil {
+			return fmt.Errorf("failed to create the directory of the golden file %s: %w", absPath, err)
+		}
+		if err := util.WriteFile(fls, absPath, actual, GOLDEN_FILE_FMODE); err != nil {
+			return fmt.Errorf("failed to update the golden file %s: %w", absPath, err)
+		}
+		return nil
+	}
+
+	expected, err := util.ReadFile(fls, absPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return newGoldenFileAssertionError(state, fmt.Sprintf("golden file %s does not exist, run the tests in update mode to create it", absPath))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the golden file %s: %w", absPath, err)
+	}
+
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	diffs, err := diff(expected, actual)
+	if err != nil {
+		return fmt.Errorf("failed to compare the value with the golden file %s: %w", absPath, err)
+	}
+
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	return newGoldenFileAssertionError(state, fmtGoldenFileDiffs(absPath, diffs))
+}
+
+// resolveGoldenFilePath resolves the path of a golden file against the directory of the test module, the golden file
+// should be located in this directory or in one of its subdirectories.
+func resolveGoldenFilePath(testModule *Module, path Path) (string, error) {
+	if path.IsDirPath() {
+		return "", fmt.Errorf("the path of a golden file should not be a directory path: %s", path)
+	}
+
+	if path.IsAbsolute() {
+		return "", fmt.Errorf("the path of a golden file should be relative to the directory of the spec file: %s", path)
+	}
+
+	moduleName := testModule.Name()
+	if !filepath.IsAbs(moduleName) {
+		return "", fmt.Errorf("the relative golden file path %s cannot be resolved because the test module is not in a filesystem", path)
+	}
+
+	moduleDir := filepath.Dir(moduleName)
+	absPath := filepath.Join(moduleDir, path.UnderlyingString())
+
+	if !strings.HasPrefix(absPath, strings.TrimSuffix(moduleDir, "/")+"/") {
+		return "", fmt.Errorf("the golden file %s should be located in the directory of the spec file or in one of its subdirectories", path)
+	}
+
+	return absPath, nil
+}
+
+// checkGoldenFileWritePermissions checks that the current context is allowed to (re)write a golden file,
+// the same permissions as the fs namespace functions are required.
+func checkGoldenFileWritePermissions(ctx *Context, fls afs.Filesystem, absPath string) error {
+	dir := filepath.Dir(absPath)
+
+	if _, err := fls.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		perm := FilesystemPermission{Kind_: permkind.Create, Entity: DirPathFrom(dir)}
+		if err := ctx.CheckHasPermission(perm); err != nil {
+			return fmt.Errorf("cannot create the directory of the golden file %s: %w", absPath, err)
+		}
+	}
+
+	perm := FilesystemPermission{Kind_: permkind.Update, Entity: PathFrom(absPath)}
+
+	if _, err := fls.Stat(absPath); errors.Is(err, os.ErrNotExist) {
+		perm.Kind_ = permkind.Create
+	}
+
+	if err := ctx.CheckHasPermission(perm); err != nil {
+		return fmt.Errorf("cannot update the golden file %s: %w", absPath, err)
+	}
+	return nil
+}
+
+func newGoldenFileAssertionError(state *GlobalState, msg string) *AssertionError {
+	return &AssertionError{
+		msg:             msg,
+		isTestAssertion: true,
+		testModule:      state.Module,
+	}
+}
+
+func fmtGoldenFileDiffs(path string, diffs []string) string {
+	buf := &strings.Builder{}
+	buf.WriteString("value does not match the golden file ")
+	buf.WriteString(path)
+	buf.WriteByte(':')
+
+	for i, diff := range diffs {
+		if i == MAX_REPORTED_GOLDEN_FILE_DIFFS {
+			fmt.Fprintf(buf, "\n  ... %d more difference(s)", len(diffs)-i)
+			break
+		}
+		buf.WriteString("\n  - ")
+		buf.WriteString(diff)
+	}
+
+	return buf.String()
+}
+
+// DiffJSON is a GoldenFileDiffer that structurally compares two JSON documents, the formatting and the order of
+// object properties are not taken into account.
+func DiffJSON(expected, actual []byte) ([]string, error) {
+	var expectedValue, actualValue any
+
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return nil, fmt.Errorf("the golden file is not valid JSON: %w", err)
+	}
+
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return nil, fmt.Errorf("the actual value is not valid JSON: %w", err)
+	}
+
+	var diffs []string
+	diffJSONValues("$", expectedValue, actualValue, &diffs)
+	return diffs, nil
+}
+
+func diffJSONValues(path string, expected, actual any, diffs *[]string) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := maps.Keys(e)
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			propPath := path + "." + key
+			actualPropValue, ok := a[key]
+			if !ok {
+				*diffs = append(*diffs, propPath+": missing property, expected "+fmtGoldenDiffJSONValue(e[key]))
+				continue
+			}
+			diffJSONValues(propPath, e[key], actualPropValue, diffs)
+		}
+
+		actualKeys := maps.Keys(a)
+		slices.Sort(actualKeys)
+
+		for _, key := range actualKeys {
+			if _, ok := e[key]; !ok {
+				*diffs = append(*diffs, path+"."+key+": unexpected property "+fmtGoldenDiffJSONValue(a[key]))
+			}
+		}
+		return
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			break
+		}
+
+		for i := 0; i < min(len(e), len(a)); i++ {
+			diffJSONValues(path+"["+strconv.Itoa(i)+"]", e[i], a[i], diffs)
+		}
+
+		for i := len(a); i < len(e); i++ {
+			*diffs = append(*diffs, path+"["+strconv.Itoa(i)+"]: missing element, expected "+fmtGoldenDiffJSONValue(e[i]))
+		}
+
+		for i := len(e); i < len(a); i++ {
+			*diffs = append(*diffs, path+"["+strconv.Itoa(i)+"]: unexpected element "+fmtGoldenDiffJSONValue(a[i]))
+		}
+		return
+	default:
+		if expected == actual {
+			return
+		}
+	}
+
+	*diffs = append(*diffs, path+": expected "+fmtGoldenDiffJSONValue(expected)+" but got "+fmtGoldenDiffJSONValue(actual))
+}
+
+func fmtGoldenDiffJSONValue(v any) string {
+	b, err := utils.MarshalJsonNoHTMLEspace(v)
+	if err != nil {
+		return "?"
+	}
+	return FmtGoldenDiffValue(string(b))
+}
+
+// FmtGoldenDiffValue truncates the representation of a value in order to keep the reported differences readable.
+func FmtGoldenDiffValue(s string) string {
+	s = strings.TrimSpace(s)
+	if runes := []rune(s); len(runes) > MAX_GOLDEN_DIFF_VALUE_LENGTH {
+		return string(runes[:MAX_GOLDEN_DIFF_VALUE_LENGTH-3]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJSON(t *testing.T) {
+	diffs, err := DiffJSON([]byte(`{"a": 1, "b": [1, 2]}`), []byte(`{"b":[1,2],"a":1}`))
+	if assert.NoError(t, err) {
+		assert.Empty(t, diffs)
+	}
+
+	diffs, err = DiffJSON([]byte(`{"a": 1, "b": [1, 2], "c": {"d": true}}`), []byte(`{"a": "1", "b": [1], "c": {"e": null}}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			`$.a: expected 1 but got "1"`,
+			`$.b[1]: missing element, expected 2`,
+			`$.c.d: missing property, expected true`,
+			`$.c.e: unexpected property null`,
+		}, diffs)
+	}
+
+	_, err = DiffJSON([]byte(`{`), []byte(`{}`))
+	assert.ErrorContains(t, err, "golden file is not valid JSON")
+}
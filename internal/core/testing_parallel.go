@@ -0,0 +1,118 @@
+package core
+
+import (
+	"errors"
+	"sync"
+)
+
+// A parallelTestCaseRunner runs the test cases of a test suite concurrently, the number of test cases
+// running at the same time is limited by the parallelism of the suite. The results are reported in
+// the order of the test case statements, regardless of the order in which the test cases finish.
+type parallelTestCaseRunner struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	lock    sync.Mutex
+	results []*TestCaseResult //nil elements correspond to test cases that have not finished yet.
+	err     error             //first error that is not a test failure
+}
+
+func newParallelTestCaseRunner(parallelism int) *parallelTestCaseRunner {
+	return &parallelTestCaseRunner{
+		slots: make(chan struct{}, parallelism),
+	}
+}
+
+// start waits for a free slot, spawns the lthread of the test case and returns without waiting for the test case to finish.
+func (r *parallelTestCaseRunner) start(suiteState *GlobalState, testCase *TestCase) error {
+	ctx := suiteState.Ctx
+
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.makeDoneContextError()
+	}
+
+	lthread, err := testCase.Run(ctx)
+	if err != nil {
+		<-r.slots
+		return err
+	}
+
+	r.lock.Lock()
+	index := len(r.results)
+	r.results = append(r.results, nil)
+	r.lock.Unlock()
+
+	r.wg.Add(1)
+
+	go func() {
+		defer func() {
+			<-r.slots
+			r.wg.Done()
+		}()
+
+		result, executionErr := lthread.WaitResult(ctx)
+
+		caseResult, err := func() (*TestCaseResult, error) {
+			if !lthread.state.TestingState.ResultsLock.TryLock() {
+				return nil, errors.New("test results should not be locked")
+			}
+			defer lthread.state.TestingState.ResultsLock.Unlock()
+
+			return NewTestCaseResult(ctx, result, executionErr, testCase)
+		}()
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		if err != nil {
+			if r.err == nil {
+				r.err = err
+			}
+			return
+		}
+		r.results[index] = caseResult
+	}()
+
+	return nil
+}
+
+// wait waits for all started test cases to finish and adds their results to the testing state of the suite.
+func (r *parallelTestCaseRunner) wait(suiteState *GlobalState) error {
+	r.wg.Wait()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	suiteState.TestingState.ResultsLock.Lock()
+	defer suiteState.TestingState.ResultsLock.Unlock()
+
+	for _, result := range r.results {
+		if result != nil {
+			suiteState.TestingState.CaseResults = append(suiteState.TestingState.CaseResults, result)
+		}
+	}
+	r.results = nil
+
+	return r.err
+}
+
+// An isolatedDatabasesProject is a Project whose local databases are stored in memory, it is used by test cases
+// running in parallel and by test cases with database fixtures in order to not share the databases of the tested program.
+// No file is left behind since the databases are lost when they are closed.
+type isolatedDatabasesProject struct {
+	Project
+}
+
+var _ = InMemoryDatabasesProject((*isolatedDatabasesProject)(nil))
+
+func newIsolatedDatabasesProject(project Project) *isolatedDatabasesProject {
+	return &isolatedDatabasesProject{
+		Project: project,
+	}
+}
+
+func (p *isolatedDatabasesProject) HasInMemoryDatabases() bool {
+	return true
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"slices"
 
 	pprint "github.com/inoxlang/inox/internal/prettyprint"
 	"github.com/inoxlang/inox/internal/utils"
@@ -108,6 +109,20 @@ func NewTestCaseResult(ctx *Context, executionResult Value, executionError error
 	return result, nil
 }
 
+// FailureMessage returns the message of the error that caused the test case to fail, the message is empty
+// if the test case succeeded.
+func (r *TestCaseResult) FailureMessage() string {
+	if r.error == nil {
+		return ""
+	}
+	return utils.StripANSISequences(r.error.Error())
+}
+
+// TestCase returns the test case that produced the result.
+func (r *TestCaseResult) TestCase() *TestCase {
+	return r.testCase
+}
+
 func (r *TestCaseResult) forEachNotEmptyMessage(fn func(s string, isDarkMode, isLightMode bool) string) {
 	if r.DarkModePrettyMessage != "" {
 		r.DarkModePrettyMessage = fn(r.DarkModePrettyMessage, true, false)
@@ -121,9 +136,10 @@ func (r *TestCaseResult) forEachNotEmptyMessage(fn func(s string, isDarkMode, is
 }
 
 type TestSuiteResult struct {
-	testSuite       *TestSuite
-	caseResults     []*TestCaseResult
-	subSuiteResults []*TestSuiteResult
+	testSuite        *TestSuite
+	caseResults      []*TestCaseResult
+	benchmarkResults []*BenchmarkResult
+	subSuiteResults  []*TestSuiteResult
 
 	Success bool
 
@@ -132,12 +148,19 @@ type TestSuiteResult struct {
 	Message                string
 }
 
-func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuiteResults []*TestSuiteResult, testSuite *TestSuite) (*TestSuiteResult, error) {
+func NewTestSuiteResult(
+	ctx *Context,
+	testCaseResults []*TestCaseResult,
+	benchmarkResults []*BenchmarkResult,
+	subSuiteResults []*TestSuiteResult,
+	testSuite *TestSuite,
+) (*TestSuiteResult, error) {
 	suiteResult := &TestSuiteResult{
-		testSuite:       testSuite,
-		caseResults:     testCaseResults,
-		subSuiteResults: subSuiteResults,
-		Success:         true,
+		testSuite:        testSuite,
+		caseResults:      testCaseResults,
+		benchmarkResults: benchmarkResults,
+		subSuiteResults:  subSuiteResults,
+		Success:          true,
 	}
 
 	allHaveDarkModeMessage := true
@@ -155,6 +178,18 @@ func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuit
 		}
 	}
 
+	for _, benchmarkResult := range benchmarkResults {
+		if !benchmarkResult.Success {
+			suiteResult.Success = false
+		}
+		if benchmarkResult.DarkModePrettyMessage == "" {
+			allHaveDarkModeMessage = false
+		}
+		if benchmarkResult.LightModePrettyMessage == "" {
+			allHaveLightModeMessage = false
+		}
+	}
+
 	for _, subSuiteResult := range subSuiteResults {
 		if !subSuiteResult.Success {
 			suiteResult.Success = false
@@ -173,6 +208,10 @@ func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuit
 			suiteResult.DarkModePrettyMessage += caseResult.DarkModePrettyMessage + "\n\n"
 		}
 
+		for _, benchmarkResult := range benchmarkResults {
+			suiteResult.DarkModePrettyMessage += benchmarkResult.DarkModePrettyMessage + "\n\n"
+		}
+
 		for _, subSuiteResult := range subSuiteResults {
 			suiteResult.DarkModePrettyMessage += subSuiteResult.DarkModePrettyMessage
 		}
@@ -184,6 +223,10 @@ func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuit
 			suiteResult.LightModePrettyMessage += caseResult.LightModePrettyMessage + "\n\n"
 		}
 
+		for _, benchmarkResult := range benchmarkResults {
+			suiteResult.LightModePrettyMessage += benchmarkResult.LightModePrettyMessage + "\n\n"
+		}
+
 		for _, subSuiteResult := range subSuiteResults {
 			suiteResult.LightModePrettyMessage += subSuiteResult.LightModePrettyMessage
 		}
@@ -194,6 +237,10 @@ func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuit
 		suiteResult.Message += caseResult.Message + "\n\n"
 	}
 
+	for _, benchmarkResult := range benchmarkResults {
+		suiteResult.Message += benchmarkResult.Message + "\n\n"
+	}
+
 	for _, subSuiteResult := range subSuiteResults {
 		suiteResult.Message += subSuiteResult.Message + "\n\n"
 	}
@@ -220,6 +267,30 @@ func NewTestSuiteResult(ctx *Context, testCaseResults []*TestCaseResult, subSuit
 	return suiteResult, nil
 }
 
+// TestSuite returns the test suite that produced the result.
+func (r *TestSuiteResult) TestSuite() *TestSuite {
+	return r.testSuite
+}
+
+// CaseResults returns the results of the test cases directly inside the suite.
+func (r *TestSuiteResult) CaseResults() []*TestCaseResult {
+	return slices.Clone(r.caseResults)
+}
+
+// SubSuiteResults returns the results of the test suites directly inside the suite.
+func (r *TestSuiteResult) SubSuiteResults() []*TestSuiteResult {
+	return slices.Clone(r.subSuiteResults)
+}
+
+// BenchmarkResults returns the results of the benchmarks of the suite and of its sub suites.
+func (r *TestSuiteResult) BenchmarkResults() []*BenchmarkResult {
+	results := slices.Clone(r.benchmarkResults)
+	for _, subSuiteResult := range r.subSuiteResults {
+		results = append(results, subSuiteResult.BenchmarkResults()...)
+	}
+	return results
+}
+
 func (r *TestSuiteResult) MostAdaptedMessage(colorized bool, darkBackground bool) string {
 	if !colorized {
 		return r.Message
@@ -811,9 +811,11 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 					StatementStartLine:   1,
 					StatementStartColumn: 1,
 				})
+				state.frameScopes = append(state.frameScopes, state.CurrentLocalScope())
 
 				defer func() {
 					state.frameInfo = state.frameInfo[:len(state.frameInfo)-1]
+					state.frameScopes = state.frameScopes[:len(state.frameScopes)-1]
 				}()
 			}
 		}
@@ -1200,6 +1202,10 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 			group.Add(lthread)
 		}
 
+		if scope := state.Global.lthreadScope; scope != nil {
+			scope.add(lthread)
+		}
+
 		return lthread, nil
 	case *parse.MappingExpression:
 		return NewMapping(n, state.Global)
@@ -2182,6 +2188,10 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 			}
 		}
 
+		if len(n.TypeParameters) > 0 {
+			right = NewGenericPattern(right, len(n.TypeParameters))
+		}
+
 		name := utils.MustGet(n.PatternName())
 		state.Global.Ctx.AddNamedPattern(name, right)
 		return Nil, nil
@@ -2199,6 +2209,13 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 		state.Global.Ctx.AddPatternNamespace(name, ns)
 		return Nil, nil
 	case *parse.PatternIdentifierLiteral:
+		if n.TypeParameter != nil {
+			//type parameters are erased.
+			if n.TypeParameter.Constraint == nil {
+				return SERIALIZABLE_PATTERN, nil
+			}
+			return evalPatternNode(n.TypeParameter.Constraint, state)
+		}
 		return resolvePattern(n, state.Global)
 	case *parse.PatternNamespaceMemberExpression:
 		return resolvePattern(n, state.Global)
@@ -2451,7 +2468,7 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 
 		if !ok.(Bool) {
 			modKind := state.Global.Module.ModuleKind
-			isTestAssertion := modKind == TestSuiteModule || modKind == TestCaseModule
+			isTestAssertion := modKind.IsTestModule()
 			var testModule *Module
 			if isTestAssertion {
 				testModule = state.Global.Module
@@ -2542,9 +2559,10 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 				defer lthread.state.TestingState.ResultsLock.Unlock()
 
 				testCaseResults := lthread.state.TestingState.CaseResults
+				benchmarkResults := lthread.state.TestingState.BenchmarkResults
 				testSuiteResults := lthread.state.TestingState.SuiteResults
 
-				result, err := NewTestSuiteResult(state.Global.Ctx, testCaseResults, testSuiteResults, suite)
+				result, err := NewTestSuiteResult(state.Global.Ctx, testCaseResults, benchmarkResults, testSuiteResults, suite)
 				if err != nil {
 					return err
 				}
@@ -2608,6 +2626,14 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 				return Nil, nil
 			}
 
+			//the result is added by the runner once the test case is finished.
+			if runner := state.Global.TestingState.parallelTestCases; runner != nil {
+				if err := runner.start(state.Global, testCase); err != nil {
+					return nil, err
+				}
+				return Nil, nil
+			}
+
 			lthread, err := testCase.Run(state.Global.Ctx)
 			if err != nil {
 				return nil, err
@@ -2644,6 +2670,76 @@ func TreeWalkEval(node parse.Node, state *TreeWalkState) (result Value, err erro
 		} else {
 			return testCase, nil
 		}
+	case *parse.BenchmarkExpression:
+		testingState := &state.Global.TestingState
+		if (!testingState.IsTestingEnabled || !testingState.IsBenchmarkingEnabled || state.forceDisableTesting) && n.IsStatement {
+			return Nil, nil
+		}
+
+		var meta Value = Nil
+		if n.Meta != nil {
+			var err error
+			meta, err = TreeWalkEval(n.Meta, state)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		expr, err := TreeWalkEval(n.Module, state)
+		if err != nil {
+			return nil, err
+		}
+
+		chunk := expr.(AstNode).Node.(*parse.Chunk)
+
+		positionStack, formattedLocation := state.formatLocation(node)
+
+		benchmark, err := NewBenchmark(BenchmarkCreationInput{
+			Node: n,
+
+			Meta:        meta,
+			ModChunk:    chunk,
+			ParentState: state.Global,
+			ParentChunk: state.currentChunk(),
+
+			PositionStack:     positionStack,
+			FormattedLocation: formattedLocation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		//execute the benchmark if the node is a statement
+		if n.IsStatement {
+			if ok, _ := testingState.Filters.IsTestEnabled(benchmark, state.Global); !ok {
+				return Nil, nil
+			}
+
+			lthread, err := benchmark.Run(state.Global.Ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = lthread.WaitResult(state.Global.Ctx)
+
+			if state.Global.Module.ModuleKind != TestSuiteModule {
+				return Nil, nil
+			}
+
+			fullName := makeTestFullName(benchmark, state.Global)
+			benchmarkResult, err := NewBenchmarkResult(state.Global.Ctx, err, benchmark, fullName, testingState.BenchmarkBaseline)
+			if err != nil {
+				return nil, err
+			}
+
+			testingState.ResultsLock.Lock()
+			testingState.BenchmarkResults = append(testingState.BenchmarkResults, benchmarkResult)
+			testingState.ResultsLock.Unlock()
+
+			return Nil, nil
+		} else {
+			return benchmark, nil
+		}
 	case *parse.LifetimejobExpression:
 		meta, err := TreeWalkEval(n.Meta, state)
 		if err != nil {
@@ -143,7 +143,13 @@ func TreeWalkCallFunc(call TreeWalkCall) (Value, error) {
 			panic(fmt.Errorf("cannot call node of type %T", node))
 		}
 	case *GoFunction:
-		return f.Call(args, state.Global, extState, isSharedFunction, must)
+		result, err := f.Call(args, state.Global, extState, isSharedFunction, must)
+		if err != nil || call.callNode == nil {
+			return result, err
+		}
+		return addStackToErrorResults(result, f, func() []ErrorStackFrame {
+			return makeErrorStack(state.fullChunkStack, call.callNode.Span)
+		}), nil
 	default:
 		panic(fmt.Errorf("cannot call node value of type %T", callee))
 	}
@@ -204,9 +210,11 @@ func TreeWalkCallFunc(call TreeWalkCall) (Value, error) {
 			StartColumn: col,
 			Id:          state.debug.shared.getNextStackFrameId(),
 		})
+		state.frameScopes = append(state.frameScopes, state.CurrentLocalScope())
 
 		defer func() {
 			state.frameInfo = state.frameInfo[:len(state.frameInfo)-1]
+			state.frameScopes = state.frameScopes[:len(state.frameScopes)-1]
 		}()
 	}
 
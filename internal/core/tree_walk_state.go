@@ -12,6 +12,7 @@ type TreeWalkState struct {
 	Global          *GlobalState
 	LocalScopeStack []map[string]Value //TODO: reduce memory usage by using a struct { small *memds.Map8[string,Value]; grown map[string]Value } ?
 	frameInfo       []StackFrameInfo   //used for debugging only, the list is reversed
+	frameScopes     []map[string]Value //used for debugging only, local scope of each frame in frameInfo
 	chunkStack      []*parse.ChunkStackItem
 	fullChunkStack  []*parse.ChunkStackItem //chunk stack but including calls' chunks
 
@@ -93,6 +94,7 @@ func (state *TreeWalkState) Reset(global *GlobalState) {
 	state.postHandle = nil
 	state.debug = nil
 	state.frameInfo = state.frameInfo[:0]
+	state.frameScopes = state.frameScopes[:0]
 }
 
 func (state TreeWalkState) currentChunkStackItem() *parse.ChunkStackItem {
@@ -187,6 +189,38 @@ func (state *TreeWalkState) CurrentLocalScope() map[string]Value {
 	return state.LocalScopeStack[len(state.LocalScopeStack)-1]
 }
 
+func (state *TreeWalkState) EvalInCurrentScope(expr parse.Node) (Value, error) {
+	debugger := state.debug
+	state.debug = nil
+	defer func() {
+		state.debug = debugger
+	}()
+
+	return TreeWalkEval(expr, state)
+}
+
+// EvalInFrame evaluates an expression in the local scope of a frame, depth is the position of the frame
+// in the reversed stack trace: 0 for the current frame.
+func (state *TreeWalkState) EvalInFrame(expr parse.Node, depth int) (Value, error) {
+	if depth == 0 {
+		return state.EvalInCurrentScope(expr)
+	}
+
+	index := len(state.frameScopes) - 1 - depth
+	if depth < 0 || index < 0 {
+		return nil, ErrUnknownStackFrame
+	}
+
+	frameState := NewTreeWalkStateWithGlobal(state.Global)
+	frameState.LocalScopeStack = []map[string]Value{state.frameScopes[index]}
+
+	return TreeWalkEval(expr, frameState)
+}
+
+func (state *TreeWalkState) SetCurrentLocalVariable(name string, value Value) {
+	state.CurrentLocalScope()[name] = value
+}
+
 func (state *TreeWalkState) PushScope() {
 	state.LocalScopeStack = append(state.LocalScopeStack, make(map[string]Value))
 }
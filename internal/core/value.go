@@ -132,7 +132,7 @@ func ValOf(v interface{}) Value {
 			return &GoFunction{fn: rval.Interface(), kind: GoFunc}
 		case reflect.Pointer:
 			if rval.Type().Implements(ERROR_INTERFACE_TYPE) {
-				return NewError(rval.Interface().(error), Nil)
+				return ConvertGoError(rval.Interface().(error))
 			}
 
 			rval = rval.Elem()
@@ -143,7 +143,7 @@ func ValOf(v interface{}) Value {
 			}
 
 			if rval.Type().Implements(ERROR_INTERFACE_TYPE) {
-				return NewError(rval.Interface().(error), Nil)
+				return ConvertGoError(rval.Interface().(error))
 			}
 		}
 		panic(fmt.Errorf("cannot convert a value of type %T to a Inox value", val))
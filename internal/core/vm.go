@@ -44,6 +44,11 @@ type VM struct {
 
 	chunkStack []*parse.ChunkStackItem
 
+	//debugging
+	debug           *Debugger
+	frameInfo       []StackFrameInfo //used for debugging only, the list is reversed
+	debugFrameNames map[parse.Node]string
+
 	//the following fields are only set for isolated function calls.
 
 	runFn              bool
@@ -184,6 +189,23 @@ func (v *VM) Run() (result Value, err error) {
 				Chunk: v.module.MainChunk,
 			},
 		}
+
+		if v.debug != nil {
+			chunk := v.module.MainChunk
+			line, col := chunk.GetLineColumn(chunk.Node)
+
+			v.frameInfo = append(v.frameInfo[:0], StackFrameInfo{
+				Node:        chunk.Node,
+				Name:        chunk.Name(),
+				Chunk:       chunk,
+				StartLine:   line,
+				StartColumn: col,
+				Id:          v.debug.shared.getNextStackFrameId(),
+
+				StatementStartLine:   1,
+				StatementStartColumn: 1,
+			})
+		}
 	}
 	v.run()
 	atomic.StoreInt64(&v.aborting, 0)
@@ -215,6 +237,10 @@ func (v *VM) run() {
 		if e != nil {
 			var assertionErr *AssertionError
 
+			if v.debug != nil && len(v.frameInfo) > 0 {
+				v.informDebuggerAboutError(e)
+			}
+
 			if er, ok := e.(error); ok {
 				if errors.As(er, &assertionErr) {
 					assertionErr = assertionErr.ShallowCopy()
@@ -231,33 +257,7 @@ func (v *VM) run() {
 			if !v.runFn {
 				//build the stack
 				currentNodePos := v.curFrame.fn.GetSourcePositionRange(ip)
-
-				var stackItems []parse.StackItem
-
-				//add the mainn chunk and the included chunks
-				for _, chunk := range v.chunkStack {
-					stackItems = append(stackItems, chunk)
-				}
-
-				//add call frames.
-				//note: we start at 1 because the first frame is the module's frame.
-				for i := 1; i < v.framesIndex; i++ {
-					frame := v.frames[i]
-
-					functionChunk, ok := frame.GetChunk()
-					//add the position of the function's start.
-					if ok {
-						functionStackItem := parse.ChunkStackItem{
-							Chunk:           functionChunk,
-							CurrentNodeSpan: frame.fn.SourceNodeSpan,
-						}
-						stackItems = append(stackItems, functionStackItem)
-					}
-
-					stackItems = append(stackItems, frame)
-				}
-
-				positionStack, formatted := parse.GetSourcePositionStack(currentNodePos.Span, stackItems)
+				positionStack, formatted := parse.GetSourcePositionStack(currentNodePos.Span, v.stackItems())
 
 				//wrap v.err in a LocatedEvalError
 
@@ -297,6 +297,13 @@ func (v *VM) run() {
 
 		ip = v.ip
 
+		if v.debug != nil {
+			if stmt, ok := v.curFrame.fn.StatementStarts[ip]; ok {
+				v.updateStackTrace(stmt)
+				v.debug.beforeInstruction(stmt, v.frameInfo, nil)
+			}
+		}
+
 		switch v.curInsts[ip] {
 		//STACK OPERATIONS AND CONSTANTS
 		case OpPushConstant:
@@ -417,6 +424,10 @@ func (v *VM) run() {
 			}
 
 			if !topLevelFnEval {
+				if v.debug != nil && len(v.frameInfo) > 1 {
+					v.frameInfo = v.frameInfo[:len(v.frameInfo)-1]
+				}
+
 				v.framesIndex--
 				v.curFrame = &v.frames[v.framesIndex-1]
 				v.curInsts = v.curFrame.fn.Instructions
@@ -1002,6 +1013,15 @@ func (v *VM) run() {
 
 			memb := object.(IProps).Prop(v.global.Ctx, memberName)
 			v.stack[v.sp-1] = memb
+		case OpGetLocalMemb:
+			v.ip += 3
+			localIndex := int(v.curInsts[v.ip-2])
+			memberNameIndex := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+			memberName := string(v.constants[memberNameIndex].(String))
+
+			object := v.stack[v.curFrame.basePointer+localIndex]
+			v.stack[v.sp] = object.(IProps).Prop(v.global.Ctx, memberName)
+			v.sp++
 		case OpGetBoolField:
 			v.ip += 4
 			structSize := int(v.curInsts[v.ip-2]) | int(v.curInsts[v.ip-3])<<8
@@ -1551,114 +1571,115 @@ func (v *VM) doSafeIntBinOp() {
 	operator := parse.BinaryOperator(v.curInsts[v.ip])
 
 	var res Value
+	res, v.err = evalIntBinaryOperation(operator, left, right)
+	if v.err != nil {
+		return
+	}
+
+	v.stack[v.sp-2] = res
+	v.sp--
+}
+
+func (v *VM) doSafeFloatBinOp() {
+	right := v.stack[v.sp-1].(Float)
+	left := v.stack[v.sp-2].(Float)
+	v.ip++
+
+	operator := parse.BinaryOperator(v.curInsts[v.ip])
+
+	var res Value
+	res, v.err = evalFloatBinaryOperation(operator, left, right)
+	if v.err != nil {
+		return
+	}
+
+	v.stack[v.sp-2] = res
+	v.sp--
+}
+
+// evalIntBinaryOperation evaluates an arithmetic or comparison operation on two integers,
+// it is also used by the bytecode optimizer to fold constant operations.
+func evalIntBinaryOperation(operator parse.BinaryOperator, left, right Int) (Value, error) {
 	switch operator {
 	case parse.Add:
-		res, v.err = intAdd(left, right)
-		if v.err != nil {
-			return
-		}
+		return intAdd(left, right)
 	case parse.Sub:
-		res, v.err = intSub(left, right)
-		if v.err != nil {
-			return
-		}
+		return intSub(left, right)
 	case parse.Mul:
 		if right > 0 {
 			if left > math.MaxInt64/right || left < math.MinInt64/right {
-				v.err = ErrIntOverflow
-				return
+				return nil, ErrIntOverflow
 			}
 		} else if right < 0 {
 			if right == -1 {
 				if left == math.MinInt64 {
-					v.err = ErrIntOverflow
-					return
+					return nil, ErrIntOverflow
 				}
 			} else if left < math.MaxInt64/right || left > math.MinInt64/right {
-				v.err = ErrIntUnderflow
-				return
+				return nil, ErrIntUnderflow
 			}
 		}
-		res = left * right
+		return left * right, nil
 	case parse.Div:
 		if right == 0 {
-			v.err = ErrIntDivisionByZero
-			return
+			return nil, ErrIntDivisionByZero
 		}
 		if left == math.MinInt64 && right == -1 {
-			v.err = ErrIntOverflow
-			return
+			return nil, ErrIntOverflow
 		}
-		res = left / right
+		return left / right, nil
 	case parse.LessThan:
-		res = Bool(left < right)
+		return Bool(left < right), nil
 	case parse.LessOrEqual:
-		res = Bool(left <= right)
+		return Bool(left <= right), nil
 	case parse.GreaterThan:
-		res = Bool(left > right)
+		return Bool(left > right), nil
 	case parse.GreaterOrEqual:
-		res = Bool(left >= right)
+		return Bool(left >= right), nil
 	default:
-		v.err = fmt.Errorf("invalid binary operator")
-		return
+		return nil, fmt.Errorf("invalid binary operator")
 	}
-
-	v.stack[v.sp-2] = res
-	v.sp--
 }
 
-func (v *VM) doSafeFloatBinOp() {
-	right := v.stack[v.sp-1].(Float)
-	left := v.stack[v.sp-2].(Float)
-	v.ip++
-
+// evalFloatBinaryOperation evaluates an arithmetic or comparison operation on two floats,
+// it is also used by the bytecode optimizer to fold constant operations.
+func evalFloatBinaryOperation(operator parse.BinaryOperator, left, right Float) (Value, error) {
 	if math.IsNaN(float64(left)) || math.IsInf(float64(left), 0) {
-		v.err = ErrNaNinfinityOperand
-		return
+		return nil, ErrNaNinfinityOperand
 	}
 
 	if math.IsNaN(float64(right)) || math.IsInf(float64(right), 0) {
-		v.err = ErrNaNinfinityOperand
-		return
+		return nil, ErrNaNinfinityOperand
 	}
 
-	operator := parse.BinaryOperator(v.curInsts[v.ip])
-
-	var res Value
 	switch operator {
 	case parse.Add:
-		res = left + right
+		return left + right, nil
 	case parse.Sub:
-		res = left - right
+		return left - right, nil
 	case parse.Mul:
 		f := left * right
-		res = f
 		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
-			v.err = ErrNaNinfinityResult
-			return
+			return nil, ErrNaNinfinityResult
 		}
+		return f, nil
 	case parse.Div:
 		f := left / right
-		res = f
 		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
-			v.err = ErrNaNinfinityResult
-			return
+			return nil, ErrNaNinfinityResult
 		}
+		return f, nil
 	case parse.LessThan:
-		res = Bool(left < right)
+		return Bool(left < right), nil
 	case parse.LessOrEqual:
-		res = Bool(left <= right)
+		return Bool(left <= right), nil
 	case parse.GreaterThan:
-		res = Bool(left > right)
+		return Bool(left > right), nil
 	case parse.GreaterOrEqual:
-		res = Bool(left >= right)
+		return Bool(left >= right), nil
 	default:
-		v.err = fmt.Errorf("invalid binary operator")
-		return
+		return nil, fmt.Errorf("invalid binary operator")
 	}
-
-	v.stack[v.sp-2] = res
-	v.sp--
 }
 
 //go:noinline
@@ -1989,6 +2010,10 @@ func (v *VM) handleOtherOpcodes(op byte) (_continue bool) {
 			return
 		}
 		v.stack[v.sp-1] = val
+	case OpCreateGenericPattern:
+		v.ip += 1
+		typeParameterCount := int(v.curInsts[v.ip])
+		v.stack[v.sp-1] = NewGenericPattern(v.stack[v.sp-1].(Pattern), typeParameterCount)
 	case OpSpreadObjectPattern:
 		patt := v.stack[v.sp-2].(*ObjectPattern)
 		spreadObjectPatt := v.stack[v.sp-1].(*ObjectPattern)
@@ -2067,9 +2092,17 @@ func (v *VM) handleOtherOpcodes(op byte) (_continue bool) {
 		v.chunkStack = append(v.chunkStack, &parse.ChunkStackItem{
 			Chunk: chunk.ParsedChunkSource,
 		})
+
+		if v.debug != nil {
+			v.setChunkOfCurrentDebugFrame(chunk.ParsedChunkSource)
+		}
 	case OpPopIncludedChunk:
 		v.chunkStack = v.chunkStack[:len(v.chunkStack)-1]
 		v.chunkStack[len(v.chunkStack)-1].CurrentNodeSpan = parse.NodeSpan{}
+
+		if v.debug != nil {
+			v.setChunkOfCurrentDebugFrame(v.chunkStack[len(v.chunkStack)-1].Chunk)
+		}
 	//XML
 	case OpCreateXMLelem:
 		v.ip += 6
@@ -2360,9 +2393,10 @@ func (v *VM) handleOtherOpcodes(op byte) (_continue bool) {
 			defer lthread.state.TestingState.ResultsLock.Unlock()
 
 			testCaseResults := lthread.state.TestingState.CaseResults
+			benchmarkResults := lthread.state.TestingState.BenchmarkResults
 			testSuiteResults := lthread.state.TestingState.SuiteResults
 
-			result, err := NewTestSuiteResult(v.global.Ctx, testCaseResults, testSuiteResults, testSuite)
+			result, err := NewTestSuiteResult(v.global.Ctx, testCaseResults, benchmarkResults, testSuiteResults, testSuite)
 			if err != nil {
 				return err
 			}
@@ -2597,6 +2631,10 @@ func (v *VM) handleOtherOpcodes(op byte) (_continue bool) {
 			group.Add(lthread)
 		}
 
+		if scope := v.global.lthreadScope; scope != nil {
+			scope.add(lthread)
+		}
+
 		v.sp -= 1
 		v.stack[v.sp-1] = lthread
 		// isCall := v.curInsts[v.ip] == 1
@@ -2721,7 +2759,7 @@ func (v *VM) handleOtherOpcodes(op byte) (_continue bool) {
 		v.sp--
 
 		modKind := v.global.Module.ModuleKind
-		isTestAssertion := modKind == TestSuiteModule || modKind == TestCaseModule
+		isTestAssertion := modKind.IsTestModule()
 		var testModule *Module
 		if isTestAssertion {
 			testModule = v.global.Module
@@ -3018,6 +3056,15 @@ func (v *VM) fnCall(numArgs int, spread, must bool, callIp int) bool {
 		v.ip = -1
 		v.framesIndex++
 		v.sp = v.sp - numArgs + compiled.LocalCount
+
+		if v.debug != nil && !v.runFn {
+			//clear the slots of the locals that are not set yet in order for the debugger to ignore them.
+			firstUnsetLocal := v.curFrame.basePointer + numArgs + len(InoxFunction.capturedLocals)
+			for i := firstUnsetLocal; i < v.sp; i++ {
+				v.stack[i] = nil
+			}
+			v.pushDebugFrame(InoxFunction)
+		}
 	} else { //Go function
 		var args []any
 		for _, arg := range v.stack[v.sp-numArgs : v.sp] {
@@ -3037,6 +3084,12 @@ func (v *VM) fnCall(numArgs int, spread, must bool, callIp int) bool {
 			return false
 		}
 
+		if callIp >= 0 && !v.runFn {
+			ret = addStackToErrorResults(ret, goFunc, func() []ErrorStackFrame {
+				return makeErrorStack(v.stackItems(), v.curFrame.fn.GetSourcePositionRange(callIp).Span)
+			})
+		}
+
 		//
 		v.sp -= numArgs + 1
 
@@ -3051,6 +3104,181 @@ func (v *VM) fnCall(numArgs int, spread, must bool, callIp int) bool {
 	return true
 }
 
+// stackItems returns the chunk stack of the VM including the call frames, the current node span of the last item
+// should not be used.
+func (v *VM) stackItems() []parse.StackItem {
+	var stackItems []parse.StackItem
+
+	//add the mainn chunk and the included chunks
+	for _, chunk := range v.chunkStack {
+		stackItems = append(stackItems, chunk)
+	}
+
+	//add call frames.
+	//note: we start at 1 because the first frame is the module's frame.
+	for i := 1; i < v.framesIndex; i++ {
+		frame := v.frames[i]
+
+		functionChunk, ok := frame.GetChunk()
+		//add the position of the function's start.
+		if ok {
+			functionStackItem := parse.ChunkStackItem{
+				Chunk:           functionChunk,
+				CurrentNodeSpan: frame.fn.SourceNodeSpan,
+			}
+			stackItems = append(stackItems, functionStackItem)
+		}
+
+		stackItems = append(stackItems, frame)
+	}
+
+	return stackItems
+}
+
+func (v *VM) pushDebugFrame(fn *InoxFunction) {
+	chunk := fn.Chunk
+	line, col := chunk.GetLineColumn(fn.Node)
+
+	frameName, ok := v.debugFrameNames[fn.Node]
+	if !ok {
+		frameName = FUNCTION_FRAME_PREFIX + chunk.GetFormattedNodeLocation(fn.Node)
+		if v.debugFrameNames == nil {
+			v.debugFrameNames = map[parse.Node]string{}
+		}
+		v.debugFrameNames[fn.Node] = frameName
+	}
+
+	v.frameInfo = append(v.frameInfo, StackFrameInfo{
+		Node:        fn.Node,
+		Name:        frameName,
+		Chunk:       chunk,
+		StartLine:   line,
+		StartColumn: col,
+		Id:          v.debug.shared.getNextStackFrameId(),
+	})
+}
+
+func (v *VM) setChunkOfCurrentDebugFrame(chunk *parse.ParsedChunkSource) {
+	if len(v.frameInfo) == 0 {
+		return
+	}
+	currentFrame := &v.frameInfo[len(v.frameInfo)-1]
+	currentFrame.Chunk = chunk
+	currentFrame.Name = chunk.Name()
+}
+
+func (v *VM) updateStackTrace(currentStmt parse.Node) {
+	currentFrame := v.frameInfo[len(v.frameInfo)-1]
+	currentFrame.Node = currentStmt
+
+	line, col := currentFrame.Chunk.GetLineColumn(currentStmt)
+	currentFrame.StatementStartLine = line
+	currentFrame.StatementStartColumn = col
+
+	v.frameInfo[len(v.frameInfo)-1] = currentFrame
+}
+
+// informDebuggerAboutError calls the beforeInstruction method of the debugger with the error, the program stops
+// if exception breakpoints are enabled.
+func (v *VM) informDebuggerAboutError(e any) {
+	defer func() {
+		//the context may be done while the program is stopped.
+		recover()
+	}()
+
+	err, ok := e.(error)
+	if !ok {
+		err = fmt.Errorf("%s", e)
+	}
+
+	currentStmt := v.frameInfo[len(v.frameInfo)-1].Node
+	if currentStmt == nil {
+		return
+	}
+	v.debug.beforeInstruction(currentStmt, v.frameInfo, err)
+}
+
+func (v *VM) AttachDebugger(debugger *Debugger) {
+	if v.debug != nil {
+		panic(ErrDebuggerAlreadyAttached)
+	}
+
+	if !v.global.Debugger.CompareAndSwap(nil, debugger) {
+		panic(ErrDebuggerAlreadyAttached)
+	}
+
+	v.debug = debugger
+}
+
+func (v *VM) DetachDebugger() {
+	v.debug = nil
+	v.global.Debugger.Store((*Debugger)(nil))
+}
+
+func (v *VM) GetGlobalState() *GlobalState {
+	return v.global
+}
+
+// CurrentLocalScope returns a map containing the locals of the current frame, modifying the map has no effect.
+func (v *VM) CurrentLocalScope() map[string]Value {
+	return v.frameLocalScope(v.framesIndex - 1)
+}
+
+// frameLocalScope returns a map containing the locals of the frame at frameIndex, modifying the map has no effect.
+func (v *VM) frameLocalScope(frameIndex int) map[string]Value {
+	scope := map[string]Value{}
+	frame := &v.frames[frameIndex]
+	basePointer := frame.basePointer
+
+	for index, name := range frame.fn.LocalNames {
+		value := v.stack[basePointer+index]
+		//locals that are not set yet are ignored.
+		if value != nil && name != "" {
+			scope[name] = value
+		}
+	}
+	return scope
+}
+
+func (v *VM) SetCurrentLocalVariable(name string, value Value) {
+	basePointer := v.frames[v.framesIndex-1].basePointer
+	names := v.curFrame.fn.LocalNames
+
+	//the last local with the name is the one that is visible.
+	for index := len(names) - 1; index >= 0; index-- {
+		if names[index] == name && v.stack[basePointer+index] != nil {
+			v.stack[basePointer+index] = value
+			return
+		}
+	}
+	panic(fmt.Errorf("local %s does not exist", name))
+}
+
+// EvalInCurrentScope evaluates an expression with the tree walk interpreter in a scope containing
+// the locals of the current frame.
+func (v *VM) EvalInCurrentScope(expr parse.Node) (Value, error) {
+	state := NewTreeWalkStateWithGlobal(v.global)
+	state.LocalScopeStack = []map[string]Value{v.CurrentLocalScope()}
+
+	return TreeWalkEval(expr, state)
+}
+
+// EvalInFrame evaluates an expression with the tree walk interpreter in a scope containing the locals of a frame,
+// depth is the position of the frame in the reversed stack trace: 0 for the current frame.
+func (v *VM) EvalInFrame(expr parse.Node, depth int) (Value, error) {
+	frameIndex := v.framesIndex - 1 - depth
+
+	//each frame of the VM has a frame in the stack trace.
+	if depth < 0 || frameIndex < 0 || (depth > 0 && len(v.frameInfo) != v.framesIndex) {
+		return nil, ErrUnknownStackFrame
+	}
+
+	state := NewTreeWalkStateWithGlobal(v.global)
+	state.LocalScopeStack = []map[string]Value{v.frameLocalScope(frameIndex)}
+
+	return TreeWalkEval(expr, state)
+}
+
 func (v *VM) IsStackEmpty() bool {
 	return v.sp == 0
 }
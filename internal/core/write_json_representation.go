@@ -1216,6 +1216,13 @@ func (pattern *OptionalPattern) WriteJSONRepresentation(ctx *Context, w *jsonite
 	return ErrNotImplementedYet
 }
 
+func (pattern *GenericPattern) WriteJSONRepresentation(ctx *Context, w *jsoniter.Stream, config JSONSerializationConfig, depth int) error {
+	if depth > MAX_JSON_REPR_WRITING_DEPTH {
+		return ErrMaximumJSONReprWritingDepthReached
+	}
+	return ErrNotImplementedYet
+}
+
 func (patt RegexPattern) WriteJSONRepresentation(ctx *Context, w *jsoniter.Stream, config JSONSerializationConfig, depth int) error {
 	if depth > MAX_JSON_REPR_WRITING_DEPTH {
 		return ErrMaximumJSONReprWritingDepthReached
@@ -1720,6 +1727,14 @@ func (patt *MutationPattern) WriteJSONRepresentation(ctx *Context, w *jsoniter.S
 	return ErrNotImplementedYet
 }
 
+func (patt *ErrorPattern) WriteJSONRepresentation(ctx *Context, w *jsoniter.Stream, config JSONSerializationConfig, depth int) error {
+	if depth > MAX_JSON_REPR_WRITING_DEPTH {
+		return ErrMaximumJSONReprWritingDepthReached
+	}
+
+	return ErrNotImplementedYet
+}
+
 // end of pattern serialization methods.
 
 func (mt Mimetype) WriteJSONRepresentation(ctx *Context, w *jsoniter.Stream, config JSONSerializationConfig, depth int) error {
@@ -0,0 +1,19 @@
+//go:build linux
+
+package filekv
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openInMemoryFile creates an anonymous file that only lives in memory, it is freed once closed.
+func openInMemoryFile(name string) (*os.File, error) {
+	fd, err := unix.MemfdCreate(name, unix.MFD_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create an in-memory file: %w", err)
+	}
+	return os.NewFile(uintptr(fd), name), nil
+}
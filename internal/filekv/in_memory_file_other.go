@@ -0,0 +1,23 @@
+//go:build !linux
+
+package filekv
+
+import (
+	"fmt"
+	"os"
+)
+
+// openInMemoryFile creates a temporary file that is immediately removed from its directory: the file
+// is no longer reachable and it is freed once closed.
+func openInMemoryFile(name string) (*os.File, error) {
+	f, err := os.CreateTemp("", name+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create an anonymous file: %w", err)
+	}
+
+	if err := os.Remove(f.Name()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create an anonymous file: %w", err)
+	}
+	return f, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"os"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -15,8 +16,9 @@ import (
 )
 
 const (
-	KV_STORE_LOG_SRC  = "kv"
-	BBOLT_FILE_FPERMS = 0700
+	KV_STORE_LOG_SRC       = "kv"
+	BBOLT_FILE_FPERMS      = 0700
+	IN_MEMORY_KV_FILE_NAME = "in-memory-kv"
 )
 
 var (
@@ -53,6 +55,10 @@ type SingleFileKV struct {
 
 type KvStoreConfig struct {
 	Path core.Path
+
+	//if true the data is stored in an anonymous in-memory file and Path is ignored,
+	//the data is lost when the KV store is closed.
+	InMemory bool
 }
 
 func OpenSingleFileKV(config KvStoreConfig) (_ *SingleFileKV, finalErr error) {
@@ -64,7 +70,17 @@ func OpenSingleFileKV(config KvStoreConfig) (_ *SingleFileKV, finalErr error) {
 		transactions: map[*core.Transaction]*bbolt.Tx{},
 	}
 
-	db, err := bbolt.Open(path, BBOLT_FILE_FPERMS, bboltOptions)
+	options := bboltOptions
+	if config.InMemory {
+		inMemoryOptions := *bboltOptions
+		inMemoryOptions.OpenFile = func(name string, _ int, _ os.FileMode) (*os.File, error) {
+			return openInMemoryFile(name)
+		}
+		options = &inMemoryOptions
+		path = IN_MEMORY_KV_FILE_NAME
+	}
+
+	db, err := bbolt.Open(path, BBOLT_FILE_FPERMS, options)
 
 	if errors.Is(err, bbolt.ErrTimeout) {
 		finalErr = ErrOpenKvStore
@@ -13,6 +13,35 @@ import (
 
 //TODO: add equivalent tests for transactions
 
+func TestInMemoryKv(t *testing.T) {
+	testconfig.AllowParallelization(t)
+
+	ctx := core.NewContexWithEmptyState(core.ContextConfig{}, nil)
+	defer ctx.CancelGracefully()
+
+	kv1, err := OpenSingleFileKV(KvStoreConfig{InMemory: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer kv1.Close(ctx)
+
+	kv2, err := OpenSingleFileKV(KvStoreConfig{InMemory: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer kv2.Close(ctx)
+
+	kv1.SetSerialized(ctx, "/a", "1", nil)
+
+	serialized, found, err := kv1.GetSerialized(ctx, "/a", nil)
+	if assert.NoError(t, err) && assert.True(t, bool(found)) {
+		assert.Equal(t, "1", serialized)
+	}
+
+	//in-memory KV stores should not share their data.
+	assert.False(t, bool(kv2.Has(ctx, "/a", nil)))
+}
+
 func TestKvSet(t *testing.T) {
 	testconfig.AllowParallelization(t)
 
@@ -81,8 +81,9 @@ var (
 		SymbolicValue: coll_symbolic.ANY_MAP_PATTERN,
 	}
 
-	_ core.DefaultValuePattern   = (*MapPattern)(nil)
-	_ core.MigrationAwarePattern = (*MapPattern)(nil)
+	_ core.DefaultValuePattern    = (*MapPattern)(nil)
+	_ core.MigrationAwarePattern  = (*MapPattern)(nil)
+	_ core.StorageSequencePattern = (*MapPattern)(nil)
 )
 
 type MapPattern struct {
@@ -136,6 +137,11 @@ func (p *MapPattern) DefaultValue(ctx *core.Context) (core.Value, error) {
 	return NewMapWithConfig(ctx, nil, p.config), nil
 }
 
+// StoredElementPatterns returns the patterns of the keys and values, a Map is stored as a sequence of key-value pairs.
+func (p *MapPattern) StoredElementPatterns() []core.Pattern {
+	return []core.Pattern{p.config.Key, p.config.Value}
+}
+
 func (p *MapPattern) ToSymbolicValue(ctx *core.Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	keyPatt, err := p.config.Key.ToSymbolicValue(ctx, encountered)
 	if err != nil {
@@ -32,13 +32,8 @@ func loadMap(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 		}
 	} else {
 		serialized, hasSerializedMap = storage.GetSerialized(ctx, path)
-		if !hasSerializedMap {
-			if args.AllowMissing {
-				serialized = "[]"
-				hasSerializedMap = true
-			} else {
-				return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
-			}
+		if !hasSerializedMap && !args.AllowMissing {
+			return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
 		}
 		//TODO: return an error if there are duplicate keys.
 	}
@@ -56,70 +51,54 @@ func loadMap(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 	m.url = storage.BaseURL().AppendAbsolutePath(path)
 
 	if hasSerializedMap {
-		var finalErr error
-
-		//TODO: lazy load if no migration
-		it := jsoniter.ParseString(jsoniter.ConfigDefault, serialized)
-
 		var key core.Serializable
 
-		it.ReadArrayCB(func(it *jsoniter.Iterator) (cont bool) {
-			defer func() {
-				e := recover()
-
-				if err, ok := e.(error); ok {
-					finalErr = err
-				} else if e != nil {
-					cont = false
-					finalErr = fmt.Errorf("%#v", e)
+		//TODO: lazy load if no migration
+		format := core.GetDataStoreSerializationFormat(storage)
+		err := core.ParseStorageSequenceRepresentation(ctx, serialized, mapPattern.StoredElementPatterns(), format,
+			func(index int, val core.Serializable) (finalErr error) {
+				defer func() {
+					e := recover()
+
+					if err, ok := e.(error); ok {
+						finalErr = err
+					} else if e != nil {
+						finalErr = fmt.Errorf("%#v", e)
+					}
+				}()
+
+				if index%2 == 0 { //key
+					if val.IsMutable() {
+						return ErrKeysShouldBeImmutable
+					}
+					key = val
+					return nil
 				}
-			}()
 
-			if key == nil {
-				val, err := core.ParseNextJSONRepresentation(ctx, it, mapPattern.config.Key, false)
-				if err != nil {
-					finalErr = fmt.Errorf("failed to parse representation of one of the Map's key: %w", err)
-					return false
-				}
+				//value
 
 				if val.IsMutable() {
-					finalErr = ErrKeysShouldBeImmutable
-					cont = false
-					return
-				}
-				key = val
-				return true
-			}
-
-			//value
-
-			value, err := core.ParseNextJSONRepresentation(ctx, it, mapPattern.config.Value, false)
-			if err != nil {
-				finalErr = fmt.Errorf("failed to parse representation of one of the Map's value: %w", err)
-				return false
-			}
-
-			if value.IsMutable() {
-				_, ok := value.(core.Watchable)
-				if !ok {
-					finalErr = fmt.Errorf("element should either be immutable or watchable")
-					cont = false
-					return
+					_, ok := val.(core.Watchable)
+					if !ok {
+						return fmt.Errorf("element should either be immutable or watchable")
+					}
+					//mutation handler is added later in the function
 				}
-				//mutation handler is added later in the function
-			}
 
-			m.putEntryInSharedMap(ctx, entry{
-				key:   key,
-				value: value,
-			}, true)
-			key = nil
+				m.putEntryInSharedMap(ctx, entry{
+					key:   key,
+					value: val,
+				}, true)
+				key = nil
+				return nil
+			})
 
-			return true
-		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the entries of the Map: %w", err)
+		}
 
-		if finalErr != nil {
-			return nil, finalErr
+		if key != nil {
+			return nil, errors.New("failed to load the entries of the Map: missing value of the last entry")
 		}
 	}
 
@@ -165,19 +144,18 @@ func loadMap(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 }
 
 func persistMap(ctx *core.Context, m *Map, path core.Path, storage core.DataStore) error {
-	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 0)
-	err := m.WriteJSONRepresentation(ctx, stream, core.JSONSerializationConfig{
-		ReprConfig: &core.ReprConfig{
-			AllVisible: true,
-		},
-		Pattern: m.pattern,
-	}, 9)
+	keysAndValues := make([]core.Serializable, 0, 2*len(m.entryByKey))
+	for _, e := range m.entryByKey {
+		keysAndValues = append(keysAndValues, e.key, e.value)
+	}
 
+	format := core.GetDataStoreSerializationFormat(storage)
+	serialized, err := core.GetStorageSequenceRepresentation(ctx, keysAndValues, []core.Pattern{m.config.Key, m.config.Value}, format)
 	if err != nil {
 		return err
 	}
 
-	storage.SetSerialized(ctx, path, string(stream.Buffer()))
+	storage.SetSerialized(ctx, path, serialized)
 	return nil
 }
 
@@ -76,8 +76,10 @@ var (
 		SymbolicValue: coll_symbolic.ANY_SET_PATTERN,
 	}
 
-	_ core.DefaultValuePattern   = (*SetPattern)(nil)
-	_ core.MigrationAwarePattern = (*SetPattern)(nil)
+	_ core.DefaultValuePattern      = (*SetPattern)(nil)
+	_ core.MigrationAwarePattern    = (*SetPattern)(nil)
+	_ core.FixtureCollectionPattern = (*SetPattern)(nil)
+	_ core.StorageSequencePattern   = (*SetPattern)(nil)
 )
 
 type SetPattern struct {
@@ -128,6 +130,18 @@ func (p *SetPattern) DefaultValue(ctx *core.Context) (core.Value, error) {
 	return NewSetWithConfig(ctx, nil, p.config), nil
 }
 
+func (p *SetPattern) FixtureElementPattern() core.Pattern {
+	return p.config.Element
+}
+
+func (p *SetPattern) StoredElementPatterns() []core.Pattern {
+	return []core.Pattern{p.config.Element}
+}
+
+func (p *SetPattern) FixtureElementsHaveURLs() bool {
+	return p.config.Uniqueness.Type == common.UniqueURL
+}
+
 func (p *SetPattern) GetMigrationOperations(ctx *core.Context, next core.Pattern, pseudoPath string) ([]core.MigrationOp, error) {
 	nextSet, ok := next.(*SetPattern)
 	if !ok || nextSet.config.Uniqueness != p.config.Uniqueness {
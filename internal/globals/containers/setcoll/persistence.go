@@ -24,30 +24,27 @@ func loadSet(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 		ok               bool
 		serialized       string
 		hasSerializedSet bool
+		initialElements  []core.Serializable //elements of an initial list (e.g. database fixtures)
 	)
 
 	if initialValue != nil {
 		set, ok = initialValue.(*Set)
 		if !ok {
 			list, isList := initialValue.(*core.List)
-			if !isList || list.Len() != 0 {
-				return nil, fmt.Errorf("%w: a Set or an empty list is expected", core.ErrInvalidInitialValue)
+			if !isList {
+				return nil, fmt.Errorf("%w: a Set or a list is expected", core.ErrInvalidInitialValue)
 			}
+			initialElements = list.GetOrBuildElements(ctx)
 		}
 	} else {
 		serialized, hasSerializedSet = storage.GetSerialized(ctx, path)
-		if !hasSerializedSet {
-			if args.AllowMissing {
-				serialized = "[]"
-				hasSerializedSet = true
-			} else {
-				return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
-			}
+		if !hasSerializedSet && !args.AllowMissing {
+			return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
 		}
 		//TODO: return an error if there are duplicate keys.
 	}
 
-	if set == nil { //true if there is no initial value or if the initial value is an empty list
+	if set == nil { //true if there is no initial value or if the initial value is a list
 		set = NewSetWithConfig(ctx, nil, setPattern.config)
 		set.pattern = setPattern
 		set.storage = storage
@@ -60,42 +57,39 @@ func loadSet(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 	set.url = storage.BaseURL().AppendAbsolutePath(path)
 
 	if hasSerializedSet {
-		var finalErr error
-
 		//TODO: lazy load if no migration
-		it := jsoniter.ParseString(jsoniter.ConfigDefault, serialized)
-		it.ReadArrayCB(func(i *jsoniter.Iterator) (cont bool) {
-			val, err := core.ParseNextJSONRepresentation(ctx, it, setPattern.config.Element, false)
-			if err != nil {
-				finalErr = fmt.Errorf("failed to parse representation of one of the Set's element: %w", err)
-				return false
-			}
-
-			defer func() {
-				e := recover()
-
-				if err, ok := e.(error); ok {
-					finalErr = err
-				} else if e != nil {
-					cont = false
-					finalErr = fmt.Errorf("%#v", e)
-				}
-			}()
-			set.addToSharedSetNoPersist(ctx, val, true)
-			if val.IsMutable() {
-				_, ok := val.(core.Watchable)
-				if !ok {
-					finalErr = fmt.Errorf("element should either be immutable or watchable")
-					cont = false
-					return
+		format := core.GetDataStoreSerializationFormat(storage)
+		err := core.ParseStorageSequenceRepresentation(ctx, serialized, setPattern.StoredElementPatterns(), format,
+			func(_ int, val core.Serializable) (finalErr error) {
+				defer func() {
+					e := recover()
+
+					if err, ok := e.(error); ok {
+						finalErr = err
+					} else if e != nil {
+						finalErr = fmt.Errorf("%#v", e)
+					}
+				}()
+				set.addToSharedSetNoPersist(ctx, val, true)
+				if val.IsMutable() {
+					_, ok := val.(core.Watchable)
+					if !ok {
+						return fmt.Errorf("element should either be immutable or watchable")
+					}
+					//mutation handler is added later in the function
 				}
-				//mutation handler is added later in the function
-			}
-			return true
-		})
+				return nil
+			})
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the elements of the Set: %w", err)
+		}
+	}
 
-		if finalErr != nil {
-			return nil, finalErr
+	if len(initialElements) > 0 {
+		err := addInitialElements(ctx, set, initialElements)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -140,20 +134,44 @@ func loadSet(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlHold
 	return set, nil
 }
 
+// addInitialElements adds the elements of an initial list to a Set that is not shared yet and persists the Set.
+func addInitialElements(ctx *core.Context, set *Set, elements []core.Serializable) (finalErr error) {
+	defer func() {
+		e := recover()
+
+		if err, ok := e.(error); ok {
+			finalErr = fmt.Errorf("failed to add an initial element: %w", err)
+		} else if e != nil {
+			finalErr = fmt.Errorf("failed to add an initial element: %#v", e)
+		}
+	}()
+
+	for _, elem := range elements {
+		if elem.IsMutable() {
+			if _, ok := elem.(core.Watchable); !ok {
+				return fmt.Errorf("element should either be immutable or watchable")
+			}
+			//mutation handler is added by the caller
+		}
+		set.addToSharedSetNoPersist(ctx, elem, true)
+	}
+
+	return persistSet(ctx, set, set.path, set.storage)
+}
+
 func persistSet(ctx *core.Context, set *Set, path core.Path, storage core.DataStore) error {
-	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 0)
-	err := set.WriteJSONRepresentation(ctx, stream, core.JSONSerializationConfig{
-		ReprConfig: &core.ReprConfig{
-			AllVisible: true,
-		},
-		Pattern: set.pattern,
-	}, 9)
+	elements := make([]core.Serializable, 0, len(set.elementByKey))
+	for _, e := range set.elementByKey {
+		elements = append(elements, e)
+	}
 
+	format := core.GetDataStoreSerializationFormat(storage)
+	serialized, err := core.GetStorageSequenceRepresentation(ctx, elements, []core.Pattern{set.config.Element}, format)
 	if err != nil {
 		return err
 	}
 
-	storage.SetSerialized(ctx, path, string(stream.Buffer()))
+	storage.SetSerialized(ctx, path, serialized)
 	return nil
 }
 
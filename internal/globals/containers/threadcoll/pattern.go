@@ -57,7 +57,8 @@ var (
 		SymbolicValue: coll_symbolic.ANY_THREAD_PATTERN,
 	}
 
-	_ core.MigrationAwarePattern = (*ThreadPattern)(nil)
+	_ core.MigrationAwarePattern  = (*ThreadPattern)(nil)
+	_ core.StorageSequencePattern = (*ThreadPattern)(nil)
 )
 
 type ThreadPattern struct {
@@ -115,6 +116,10 @@ func (p *ThreadPattern) PrettyPrint(w *bufio.Writer, config *core.PrettyPrintCon
 	w.WriteByte(')')
 }
 
+func (p *ThreadPattern) StoredElementPatterns() []core.Pattern {
+	return []core.Pattern{p.config.Element}
+}
+
 func (p *ThreadPattern) ToSymbolicValue(ctx *core.Context, encountered map[uintptr]symbolic.Value) (symbolic.Value, error) {
 	symbolicElemPattern, err := p.config.Element.ToSymbolicValue(ctx, encountered)
 	if err != nil {
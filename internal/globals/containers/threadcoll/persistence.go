@@ -33,13 +33,8 @@ func loadThread(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlH
 		}
 	} else {
 		serialized, hasSerializedThread = storage.GetSerialized(ctx, path)
-		if !hasSerializedThread {
-			if args.AllowMissing {
-				serialized = "[]"
-				hasSerializedThread = true
-			} else {
-				return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
-			}
+		if !hasSerializedThread && !args.AllowMissing {
+			return nil, fmt.Errorf("%w: %s", core.ErrFailedToLoadNonExistingValue, path)
 		}
 		//TODO: return an error if there are duplicate keys.
 	}
@@ -55,35 +50,30 @@ func loadThread(ctx *core.Context, args core.FreeEntityLoadingParams) (core.UrlH
 	}
 
 	if hasSerializedThread {
-		var finalErr error
-
 		//TODO: lazy load if no migration
-		it := jsoniter.ParseString(jsoniter.ConfigDefault, serialized)
-		it.ReadArrayCB(func(i *jsoniter.Iterator) (cont bool) {
-			val, err := core.ParseObjectJSONrepresentation(ctx, it, threadPattern.config.Element, false)
-			if err != nil {
-				finalErr = fmt.Errorf("failed to parse representation of one of the thread's element: %w", err)
-				return false
-			}
-
-			defer func() {
-				e := recover()
-
-				if err, ok := e.(error); ok {
-					finalErr = err
-				} else if e != nil {
-					cont = false
-					finalErr = fmt.Errorf("%#v", e)
+		format := core.GetDataStoreSerializationFormat(storage)
+		err := core.ParseStorageSequenceRepresentation(ctx, serialized, threadPattern.StoredElementPatterns(), format,
+			func(_ int, val core.Serializable) (finalErr error) {
+				defer func() {
+					e := recover()
+
+					if err, ok := e.(error); ok {
+						finalErr = err
+					} else if e != nil {
+						finalErr = fmt.Errorf("%#v", e)
+					}
+				}()
+
+				obj, ok := val.(*core.Object)
+				if !ok {
+					return errors.New("elements of message threads should be objects")
 				}
-			}()
-
-			thread.addNoLock(ctx, nil, val, true)
-			cont = true
-			return
-		})
+				thread.addNoLock(ctx, nil, obj, true)
+				return nil
+			})
 
-		if finalErr != nil {
-			return nil, finalErr
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the elements of the thread: %w", err)
 		}
 	}
 
@@ -154,19 +144,18 @@ func (t *MessageThread) makePersistOnMutationCallback(elem *core.Object) core.Mu
 }
 
 func persistThread(ctx *core.Context, thread *MessageThread, path core.Path, storage core.DataStore) error {
-	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 0)
-	err := thread.WriteJSONRepresentation(ctx, stream, core.JSONSerializationConfig{
-		ReprConfig: &core.ReprConfig{
-			AllVisible: true,
-		},
-		Pattern: thread.pattern,
-	}, 9)
+	elements := make([]core.Serializable, 0, len(thread.elements))
+	for _, e := range thread.elements {
+		elements = append(elements, e.actualElement)
+	}
 
+	format := core.GetDataStoreSerializationFormat(storage)
+	serialized, err := core.GetStorageSequenceRepresentation(ctx, elements, []core.Pattern{thread.config.Element}, format)
 	if err != nil {
 		return err
 	}
 
-	storage.SetSerialized(ctx, path, string(stream.Buffer()))
+	storage.SetSerialized(ctx, path, serialized)
 	return nil
 }
 
@@ -246,19 +246,20 @@ func NewDefaultGlobalState(ctx *core.Context, conf core.DefaultGlobalStateConfig
 		globalnames.UNHEX_FN: core.ValOf(decodeHex),
 
 		// conversion
-		globalnames.TOSTR_FN:     core.ValOf(_tostr),
-		globalnames.TOSTRING_FN:  core.ValOf(_tostring),
-		globalnames.TORUNE_FN:    core.ValOf(_torune),
-		globalnames.TOBYTE_FN:    core.ValOf(_tobyte),
-		globalnames.TOFLOAT_FN:   core.ValOf(_tofloat),
-		globalnames.TOINT_FN:     core.ValOf(_toint),
-		globalnames.TOBYTECOUNT:  core.ValOf(_tobytecount),
-		globalnames.TORSTREAM_FN: core.ValOf(_torstream),
-		globalnames.TOJSON_FN:    core.ValOf(core.ToJSON),
-		globalnames.TOPJSON_FN:   core.ValOf(core.ToPrettyJSON),
-		globalnames.ASJSON_FN:    core.ValOf(asJSON),
-		globalnames.PARSE_FN:     core.ValOf(_parse),
-		globalnames.SPLIT_FN:     core.ValOf(_split),
+		globalnames.TOSTR_FN:        core.ValOf(_tostr),
+		globalnames.TOSTRING_FN:     core.ValOf(_tostring),
+		globalnames.TORUNE_FN:       core.ValOf(_torune),
+		globalnames.TOBYTE_FN:       core.ValOf(_tobyte),
+		globalnames.TOFLOAT_FN:      core.ValOf(_tofloat),
+		globalnames.TOINT_FN:        core.ValOf(_toint),
+		globalnames.TOBYTECOUNT:     core.ValOf(_tobytecount),
+		globalnames.TORSTREAM_FN:    core.ValOf(_torstream),
+		globalnames.TOJSON_FN:       core.ValOf(core.ToJSON),
+		globalnames.TOPJSON_FN:      core.ValOf(core.ToPrettyJSON),
+		globalnames.ASJSON_FN:       core.ValOf(asJSON),
+		globalnames.TOJSONSCHEMA_FN: core.ValOf(_tojsonschema),
+		globalnames.PARSE_FN:        core.ValOf(_parse),
+		globalnames.SPLIT_FN:        core.ValOf(_split),
 
 		// time
 		globalnames.AGO_FN:        core.ValOf(_ago),
@@ -299,10 +300,13 @@ func NewDefaultGlobalState(ctx *core.Context, conf core.DefaultGlobalStateConfig
 		globalnames.FIND_FIRST_FN:      core.WrapGoFunction(_find_first),
 
 		// concurrency & execution
-		globalnames.LTHREADGROUP_FN: core.ValOf(core.NewLThreadGroup),
-		globalnames.RUN_FN:          core.ValOf(_run),
-		globalnames.EXEC_FN:         core.ValOf(_execute),
-		globalnames.CANCEL_EXEC_FN:  core.ValOf(_cancel_exec),
+		globalnames.LTHREADGROUP_FN:  core.ValOf(core.NewLThreadGroup),
+		globalnames.CHANNEL_FN:       core.ValOf(core.NewChannel),
+		globalnames.SELECT_FN:        core.ValOf(core.Select),
+		globalnames.LTHREAD_SCOPE_FN: core.ValOf(core.RunLThreadScope),
+		globalnames.RUN_FN:           core.ValOf(_run),
+		globalnames.EXEC_FN:          core.ValOf(_execute),
+		globalnames.CANCEL_EXEC_FN:   core.ValOf(_cancel_exec),
 
 		// integer
 		globalnames.IS_EVEN_FN: core.ValOf(_is_even),
@@ -326,6 +330,9 @@ func NewDefaultGlobalState(ctx *core.Context, conf core.DefaultGlobalStateConfig
 		globalnames.ARRAY_FN: core.ValOf(core.NewArray),
 		globalnames.LIST_FN:  core.ValOf(_List),
 
+		// testing
+		globalnames.ASSERT_GOLDEN_FN: core.ValOf(_assert_golden),
+
 		globalnames.TYPEOF_FN:     core.ValOf(_typeof),
 		globalnames.URL_OF_FN:     core.ValOf(_url_of),
 		globalnames.LEN_FN:        core.ValOf(_len),
@@ -3,6 +3,7 @@ package internal
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -18,6 +19,8 @@ import (
 	"github.com/inoxlang/inox/internal/jsoniter"
 	"github.com/inoxlang/inox/internal/mod"
 
+	"github.com/inoxlang/inox/internal/globals/globalnames"
+	"github.com/inoxlang/inox/internal/globals/html_ns"
 	"github.com/inoxlang/inox/internal/globals/inoxsh_ns"
 
 	"github.com/inoxlang/inox/internal/core/permkind"
@@ -27,6 +30,10 @@ import (
 
 const BUFF_WRITER_SIZE = 100
 
+var (
+	JSON_SCHEMA_RECORD_PROPNAMES = []string{"schema", "warnings"}
+)
+
 func _get_current_tx(ctx *core.Context) *core.Transaction {
 	return ctx.GetTx()
 }
@@ -61,16 +68,46 @@ func _fprint(ctx *core.Context, out core.Writable, args ...core.Value) {
 	__fprint(ctx, out.Writer(), args...)
 }
 
+// _Error creates an error, the optional arguments are the kind of the error (identifier), its cause (error)
+// and some immutable data.
 func _Error(ctx *core.Context, text core.String, args ...core.Serializable) core.Error {
-	goErr := errors.New(string(text))
-	if len(args) == 0 {
-		return core.NewError(goErr, core.Nil)
-	}
-	if len(args) > 1 {
-		panic(errors.New("at most two arguments were expected"))
+	var (
+		kind  core.Identifier
+		cause *core.Error
+		data  core.Serializable = core.Nil
+	)
+
+	hasData := false
+
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case core.Identifier:
+			if kind != "" {
+				panic(commonfmt.FmtErrArgumentProvidedAtLeastTwice("kind"))
+			}
+			kind = a
+		case core.Error:
+			if cause != nil {
+				panic(commonfmt.FmtErrArgumentProvidedAtLeastTwice("cause"))
+			}
+			cause = &a
+		default:
+			if hasData {
+				panic(commonfmt.FmtErrArgumentProvidedAtLeastTwice("data"))
+			}
+			hasData = true
+			data = a
+		}
 	}
 
-	return core.NewError(goErr, args[0])
+	err := core.NewError(errors.New(string(text)), data)
+	if kind != "" {
+		err = err.WithKind(kind)
+	}
+	if cause != nil {
+		err = err.WithCause(*cause)
+	}
+	return err
 }
 
 func _typeof(ctx *core.Context, arg core.Value) core.Type {
@@ -455,6 +492,19 @@ func asJSON(ctx *core.Context, v core.Serializable) core.String {
 	return core.String(stream.Buffer())
 }
 
+// _tojsonschema returns a record containing the JSON Schema (JSON string) of a pattern and the warnings
+// about the approximations made during the conversion.
+func _tojsonschema(ctx *core.Context, pattern core.Pattern) *core.Record {
+	schema, warnings := core.ConvertPatternToJsonSchema(pattern)
+
+	warningValues := utils.MapSlice(warnings, func(s string) core.Serializable { return core.String(s) })
+
+	return core.NewRecordFromKeyValLists(JSON_SCHEMA_RECORD_PROPNAMES, []core.Serializable{
+		core.String(utils.Must(json.Marshal(schema))),
+		core.NewTuple(warningValues),
+	})
+}
+
 func _asJSON(ctx *core.Context, v core.Serializable, w *jsoniter.Stream) {
 	switch v := v.(type) {
 	case *core.Object:
@@ -497,3 +547,21 @@ func _asJSON(ctx *core.Context, v core.Serializable, w *jsoniter.Stream) {
 		panic(fmt.Errorf("unexpected value %s, `asjson` only supports objects, lists, integers, floats, bools and string-likes", core.Stringify(v, ctx)))
 	}
 }
+
+// _assert_golden compares the rendering of a value with a golden file and panics if they differ: HTML nodes are rendered
+// as HTML, other values are serialized to JSON.
+func _assert_golden(ctx *core.Context, v core.Value, path core.Path) {
+	var err error
+
+	if node, ok := v.(*html_ns.HTMLNode); ok {
+		err = core.CheckGoldenFile(ctx, path, html_ns.Render(ctx, node).UnderlyingBytes(), html_ns.DiffHTML)
+	} else if serializable, ok := v.(core.Serializable); ok {
+		err = core.CheckGoldenFile(ctx, path, []byte(core.ToPrettyJSON(ctx, serializable, nil)), core.DiffJSON)
+	} else {
+		err = fmt.Errorf("%s: the value should be an HTML node or a serializable value", globalnames.ASSERT_GOLDEN_FN)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
@@ -63,19 +63,20 @@ const (
 	UNHEX_FN = "unhex"
 
 	// conversion
-	TOSTR_FN     = "tostr"
-	TOSTRING_FN  = "tostring"
-	TORUNE_FN    = "torune"
-	TOBYTE_FN    = "tobyte"
-	TOFLOAT_FN   = "tofloat"
-	TOINT_FN     = "toint"
-	TOBYTECOUNT  = "tobytecount"
-	TORSTREAM_FN = "torstream"
-	TOJSON_FN    = "tojson"
-	TOPJSON_FN   = "topjson"
-	ASJSON_FN    = "asjson"
-	PARSE_FN     = "parse"
-	SPLIT_FN     = "split"
+	TOSTR_FN        = "tostr"
+	TOSTRING_FN     = "tostring"
+	TORUNE_FN       = "torune"
+	TOBYTE_FN       = "tobyte"
+	TOFLOAT_FN      = "tofloat"
+	TOINT_FN        = "toint"
+	TOBYTECOUNT     = "tobytecount"
+	TORSTREAM_FN    = "torstream"
+	TOJSON_FN       = "tojson"
+	TOPJSON_FN      = "topjson"
+	ASJSON_FN       = "asjson"
+	TOJSONSCHEMA_FN = "tojsonschema"
+	PARSE_FN        = "parse"
+	SPLIT_FN        = "split"
 
 	// time
 	AGO_FN        = "ago"
@@ -116,10 +117,13 @@ const (
 	FIND_FIRST_FN      = "find_first"
 
 	// concurrency & execution
-	LTHREADGROUP_FN = "LThreadGroup"
-	RUN_FN          = "run"
-	EXEC_FN         = "ex" //command execution
-	CANCEL_EXEC_FN  = "cancel_exec"
+	LTHREADGROUP_FN  = "LThreadGroup"
+	CHANNEL_FN       = "Channel"
+	SELECT_FN        = "select"
+	LTHREAD_SCOPE_FN = "lthread_scope"
+	RUN_FN           = "run"
+	EXEC_FN          = "ex" //command execution
+	CANCEL_EXEC_FN   = "cancel_exec"
 
 	// integer
 	IS_EVEN_FN = "is_even"
@@ -2,6 +2,8 @@ package globalnames
 
 const (
 	CURRENT_TEST = "__test"
+
+	ASSERT_GOLDEN_FN = "assert_golden"
 )
 
 var (
@@ -34,19 +34,20 @@ func registerHelp() {
 		globalnames.UNHEX_FN: decodeHex,
 
 		// conversion
-		globalnames.TOSTR_FN:     _tostr,
-		globalnames.TOSTRING_FN:  _tostring,
-		globalnames.TORUNE_FN:    _torune,
-		globalnames.TOBYTE_FN:    _tobyte,
-		globalnames.TOFLOAT_FN:   _tofloat,
-		globalnames.TOINT_FN:     _toint,
-		globalnames.TOBYTECOUNT:  _tobytecount,
-		globalnames.TORSTREAM_FN: _torstream,
-		globalnames.TOJSON_FN:    core.ToJSON,
-		globalnames.TOPJSON_FN:   core.ToPrettyJSON,
-		globalnames.ASJSON_FN:    asJSON,
-		globalnames.PARSE_FN:     _parse,
-		globalnames.SPLIT_FN:     _split,
+		globalnames.TOSTR_FN:        _tostr,
+		globalnames.TOSTRING_FN:     _tostring,
+		globalnames.TORUNE_FN:       _torune,
+		globalnames.TOBYTE_FN:       _tobyte,
+		globalnames.TOFLOAT_FN:      _tofloat,
+		globalnames.TOINT_FN:        _toint,
+		globalnames.TOBYTECOUNT:     _tobytecount,
+		globalnames.TORSTREAM_FN:    _torstream,
+		globalnames.TOJSON_FN:       core.ToJSON,
+		globalnames.TOPJSON_FN:      core.ToPrettyJSON,
+		globalnames.ASJSON_FN:       asJSON,
+		globalnames.TOJSONSCHEMA_FN: _tojsonschema,
+		globalnames.PARSE_FN:        _parse,
+		globalnames.SPLIT_FN:        _split,
 
 		//time
 		globalnames.AGO_FN:   _ago,
@@ -83,10 +84,13 @@ func registerHelp() {
 		globalnames.FIND_FN:            _find,
 
 		// concurrency & execution
-		globalnames.LTHREADGROUP_FN: core.NewLThreadGroup,
-		globalnames.RUN_FN:          _run,
-		globalnames.EXEC_FN:         _execute,
-		globalnames.CANCEL_EXEC_FN:  _cancel_exec,
+		globalnames.LTHREADGROUP_FN:  core.NewLThreadGroup,
+		globalnames.CHANNEL_FN:       core.NewChannel,
+		globalnames.SELECT_FN:        core.Select,
+		globalnames.LTHREAD_SCOPE_FN: core.RunLThreadScope,
+		globalnames.RUN_FN:           _run,
+		globalnames.EXEC_FN:          _execute,
+		globalnames.CANCEL_EXEC_FN:   _cancel_exec,
 
 		//integer
 		globalnames.IS_EVEN_FN: _is_even,
@@ -0,0 +1,211 @@
+package html_ns
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/inoxlang/inox/internal/core"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DiffHTML is a core.GoldenFileDiffer that structurally compares two HTML documents or fragments. Whitespace-only
+// text nodes, leading & trailing whitespace in text nodes and the order of attributes are not taken into account.
+func DiffHTML(expected, actual []byte) ([]string, error) {
+	expectedNodes, err := parseHTMLForDiff(expected)
+	if err != nil {
+		return nil, fmt.Errorf("the golden file is not valid HTML: %w", err)
+	}
+
+	actualNodes, err := parseHTMLForDiff(actual)
+	if err != nil {
+		return nil, fmt.Errorf("the actual value is not valid HTML: %w", err)
+	}
+
+	var diffs []string
+	diffHTMLNodeLists("", expectedNodes, actualNodes, &diffs)
+	return diffs, nil
+}
+
+func parseHTMLForDiff(content []byte) ([]*html.Node, error) {
+	trimmed := strings.ToLower(string(bytes.TrimSpace(content)))
+
+	if strings.HasPrefix(trimmed, "<!doctype") || strings.HasPrefix(trimmed, "<html") {
+		doc, err := html.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		return getDiffableChildren(doc), nil
+	}
+
+	nodes, err := html.ParseFragment(bytes.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(nodes, func(n *html.Node) bool {
+		return !isDiffableHTMLNode(n)
+	}), nil
+}
+
+func diffHTMLNodes(path string, expected, actual *html.Node, diffs *[]string) {
+	isElement := expected.Type == html.ElementNode
+
+	if expected.Type != actual.Type || (isElement && expected.Data != actual.Data) {
+		*diffs = append(*diffs, path+": expected "+describeHTMLNode(expected)+" but got "+describeHTMLNode(actual))
+		return
+	}
+
+	switch expected.Type {
+	case html.TextNode, html.CommentNode:
+		if strings.TrimSpace(expected.Data) != strings.TrimSpace(actual.Data) {
+			*diffs = append(*diffs, path+": expected "+describeHTMLNode(expected)+" but got "+describeHTMLNode(actual))
+		}
+		return
+	case html.ElementNode:
+		diffHTMLAttributes(path, expected, actual, diffs)
+	}
+
+	diffHTMLNodeLists(path, getDiffableChildren(expected), getDiffableChildren(actual), diffs)
+}
+
+func diffHTMLAttributes(path string, expected, actual *html.Node, diffs *[]string) {
+	actualAttrs := map[string]string{}
+	for _, attr := range actual.Attr {
+		actualAttrs[attr.Key] = attr.Val
+	}
+
+	expectedAttrs := map[string]string{}
+	for _, attr := range expected.Attr {
+		expectedAttrs[attr.Key] = attr.Val
+
+		actualVal, ok := actualAttrs[attr.Key]
+		if !ok {
+			*diffs = append(*diffs, path+": missing attribute "+attr.Key+"="+fmtDiffText(attr.Val))
+		} else if actualVal != attr.Val {
+			*diffs = append(*diffs, path+": expected attribute "+attr.Key+"="+fmtDiffText(attr.Val)+" but got "+
+				attr.Key+"="+fmtDiffText(actualVal))
+		}
+	}
+
+	for _, attr := range actual.Attr {
+		if _, ok := expectedAttrs[attr.Key]; !ok {
+			*diffs = append(*diffs, path+": unexpected attribute "+attr.Key+"="+fmtDiffText(attr.Val))
+		}
+	}
+}
+
+func diffHTMLNodeLists(parentPath string, expected, actual []*html.Node, diffs *[]string) {
+	expectedPaths := makeHTMLNodePaths(parentPath, expected)
+	actualPaths := makeHTMLNodePaths(parentPath, actual)
+
+	for i := 0; i < min(len(expected), len(actual)); i++ {
+		diffHTMLNodes(expectedPaths[i], expected[i], actual[i], diffs)
+	}
+
+	for i := len(actual); i < len(expected); i++ {
+		*diffs = append(*diffs, expectedPaths[i]+": missing "+describeHTMLNode(expected[i]))
+	}
+
+	for i := len(expected); i < len(actual); i++ {
+		*diffs = append(*diffs, actualPaths[i]+": unexpected "+describeHTMLNode(actual[i]))
+	}
+}
+
+// makeHTMLNodePaths returns the paths of sibling nodes, the index of a node is only added if it has siblings of the
+// same kind (e.g. `ul > li[2]`).
+func makeHTMLNodePaths(parentPath string, nodes []*html.Node) []string {
+	names := make([]string, len(nodes))
+	counts := map[string]int{}
+
+	for i, node := range nodes {
+		switch node.Type {
+		case html.ElementNode:
+			names[i] = node.Data
+		case html.TextNode:
+			names[i] = "#text"
+		case html.CommentNode:
+			names[i] = "#comment"
+		default:
+			names[i] = "#node"
+		}
+		counts[names[i]]++
+	}
+
+	paths := make([]string, len(nodes))
+	indexes := map[string]int{}
+
+	for i, name := range names {
+		indexes[name]++
+		if counts[name] > 1 {
+			name += "[" + strconv.Itoa(indexes[name]) + "]"
+		}
+
+		if parentPath == "" {
+			paths[i] = name
+		} else {
+			paths[i] = parentPath + " > " + name
+		}
+	}
+
+	return paths
+}
+
+func getDiffableChildren(node *html.Node) (children []*html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if isDiffableHTMLNode(child) {
+			children = append(children, child)
+		}
+	}
+	return
+}
+
+func isDiffableHTMLNode(node *html.Node) bool {
+	switch node.Type {
+	case html.TextNode:
+		return strings.TrimSpace(node.Data) != ""
+	case html.DoctypeNode:
+		return false
+	}
+	return true
+}
+
+func describeHTMLNode(node *html.Node) string {
+	switch node.Type {
+	case html.ElementNode:
+		buf := &strings.Builder{}
+		buf.WriteByte('<')
+		buf.WriteString(node.Data)
+
+		attrs := slices.Clone(node.Attr)
+		slices.SortFunc(attrs, func(a, b html.Attribute) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+
+		for _, attr := range attrs {
+			buf.WriteByte(' ')
+			buf.WriteString(attr.Key)
+			buf.WriteString("=")
+			buf.WriteString(strconv.Quote(attr.Val))
+		}
+		buf.WriteByte('>')
+		return core.FmtGoldenDiffValue(buf.String())
+	case html.TextNode:
+		return "text " + fmtDiffText(strings.TrimSpace(node.Data))
+	case html.CommentNode:
+		return "comment " + fmtDiffText(strings.TrimSpace(node.Data))
+	default:
+		return "node"
+	}
+}
+
+func fmtDiffText(s string) string {
+	return strconv.Quote(core.FmtGoldenDiffValue(s))
+}
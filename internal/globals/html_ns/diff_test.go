@@ -0,0 +1,44 @@
+package html_ns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffHTML(t *testing.T) {
+	t.Run("equivalent fragments", func(t *testing.T) {
+		diffs, err := DiffHTML(
+			[]byte("<div class=\"a\" id=\"b\">\n  <span>text</span>\n</div>"),
+			[]byte(`<div id="b" class="a"><span> text </span></div>`),
+		)
+		if assert.NoError(t, err) {
+			assert.Empty(t, diffs)
+		}
+	})
+
+	t.Run("different fragments", func(t *testing.T) {
+		diffs, err := DiffHTML(
+			[]byte(`<ul class="list"><li>1</li><li>2</li></ul>`),
+			[]byte(`<ul><li>1</li><li>3</li><li>4</li></ul><p></p>`),
+		)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{
+				`ul: missing attribute class="list"`,
+				`ul > li[2] > #text: expected text "2" but got text "3"`,
+				`ul > li[3]: unexpected <li>`,
+				`p: unexpected <p>`,
+			}, diffs)
+		}
+	})
+
+	t.Run("documents", func(t *testing.T) {
+		diffs, err := DiffHTML(
+			[]byte(`<!DOCTYPE html><html><head><title>a</title></head><body></body></html>`),
+			[]byte(`<!DOCTYPE html><html><head><title>b</title></head><body></body></html>`),
+		)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{`html > head > title > #text: expected text "a" but got text "b"`}, diffs)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package http_ns
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
@@ -131,6 +132,11 @@ func newFilesytemRouter(dynamicDir, staticDir core.Path, server *HttpsServer, fl
 }
 
 func (router *filesystemRouter) handle(req *Request, rw *ResponseWriter, handlerGlobalState *core.GlobalState) {
+	if router.dynamicDir != "" && req.Path == spec.API_JSON_SCHEMAS_PATH && req.IsGetOrHead() {
+		router.serveAPIJSONSchemas(rw, handlerGlobalState)
+		return
+	}
+
 	if router.staticDir != "" {
 		staticFilePath := router.staticDir.JoinAbsolute(req.Path, handlerGlobalState.Ctx.GetFileSystem())
 
@@ -174,6 +180,26 @@ func (router *filesystemRouter) handle(req *Request, rw *ResponseWriter, handler
 	}
 }
 
+// serveAPIJSONSchemas writes the JSON Schemas of the request and response bodies of the API, the warnings
+// about approximated schemas are logged.
+func (router *filesystemRouter) serveAPIJSONSchemas(rw *ResponseWriter, handlerGlobalState *core.GlobalState) {
+	router.server.apiLock.Lock()
+	api := router.server.api
+	router.server.apiLock.Unlock()
+
+	document, warnings := api.JSONSchemas()
+	if len(warnings) > 0 {
+		fsRoutingLogger := handlerGlobalState.Ctx.NewChildLoggerForInternalSource(FS_ROUTING_LOG_SRC)
+		for _, warning := range warnings {
+			fsRoutingLogger.Warn().Msg(warning)
+		}
+	}
+
+	rw.SetContentType(mimeconsts.JSON_CTYPE)
+	rw.writeHeaders(http.StatusOK)
+	rw.DetachBodyWriter().Write(utils.Must(json.Marshal(document)))
+}
+
 func (router *filesystemRouter) handleDynamic(req *Request, rw *ResponseWriter, handlerGlobalState *core.GlobalState) {
 	path := req.Path
 	method := req.Method.UnderlyingString()
@@ -279,6 +305,10 @@ func (router *filesystemRouter) handleDynamic(req *Request, rw *ResponseWriter,
 
 		FullAccessToDatabases: false, //databases should be passed by parent state
 		PreinitFilesystem:     handlerCtx.GetFileSystem(),
+
+		//the bytecode of the handler is loaded from the cache if the server's module has been prepared with one.
+		BytecodeCache: router.server.state.BytecodeCache,
+
 		GetArguments: func(manifest *core.Manifest) (*core.ModuleArgs, error) {
 			args, errStatusCode, err := getHandlerModuleArguments(req, manifest, handlerCtx, methodSpecificModule)
 			if err != nil {
@@ -331,6 +361,9 @@ func (router *filesystemRouter) handleDynamic(req *Request, rw *ResponseWriter,
 		IgnoreHighRiskScore:       true,
 		Debugger:                  debugger,
 
+		//the bytecode is only present if the module has been compiled (or loaded from the cache) during its preparation.
+		UseBytecode: state.Bytecode != nil && debugger == nil,
+
 		DoNotCancelWhenFinished: true,
 	})
 
@@ -87,44 +87,44 @@ func init() {
 	core.RegisterSymbolicGoFunctions([]any{
 		httpExists, func(ctx *symbolic.Context, arg symbolic.Value) *symbolic.Bool {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Read, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_READ_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Read, permkind.HTTP_PERM_TYPENAME, HTTP_READ_PERM_MIGHT_BE_MISSING)
 			}
 			return symbolic.ANY_BOOL
 		},
 		HttpGet, func(ctx *symbolic.Context, u *symbolic.URL, args ...symbolic.Value) (*http_symbolic.Response, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Read, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_READ_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Read, permkind.HTTP_PERM_TYPENAME, HTTP_READ_PERM_MIGHT_BE_MISSING)
 			}
 			return http_symbolic.ANY_RESP, nil
 		},
 		HttpRead, func(ctx *symbolic.Context, u *symbolic.URL, args ...symbolic.Value) (symbolic.Value, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Read, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_READ_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Read, permkind.HTTP_PERM_TYPENAME, HTTP_READ_PERM_MIGHT_BE_MISSING)
 			}
 			return symbolic.ANY, nil
 		},
 		HttpPost, func(ctx *symbolic.Context, args ...symbolic.Value) (*http_symbolic.Response, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Write, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_WRITE_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Write, permkind.HTTP_PERM_TYPENAME, HTTP_WRITE_PERM_MIGHT_BE_MISSING)
 			}
 			return http_symbolic.ANY_RESP, nil
 		},
 		HttpPatch, func(ctx *symbolic.Context, args ...symbolic.Value) (*http_symbolic.Response, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Write, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_WRITE_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Write, permkind.HTTP_PERM_TYPENAME, HTTP_WRITE_PERM_MIGHT_BE_MISSING)
 			}
 			return http_symbolic.ANY_RESP, nil
 		},
 		HttpDelete, func(ctx *symbolic.Context, args ...symbolic.Value) (*http_symbolic.Response, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Delete, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_DELETE_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Delete, permkind.HTTP_PERM_TYPENAME, HTTP_DELETE_PERM_MIGHT_BE_MISSING)
 			}
 			return http_symbolic.ANY_RESP, nil
 		},
 		NewHttpsServer, newSymbolicHttpsServer,
 		NewFileServer, func(ctx *symbolic.Context, args ...symbolic.Value) (*http_symbolic.HttpsServer, *symbolic.Error) {
 			if !ctx.HasAPermissionWithKindAndType(permkind.Provide, permkind.HTTP_PERM_TYPENAME) {
-				ctx.AddSymbolicGoFunctionWarning(HTTP_PROVIDE_PERM_MIGHT_BE_MISSING)
+				ctx.AddMissingPermissionWarning(permkind.Provide, permkind.HTTP_PERM_TYPENAME, HTTP_PROVIDE_PERM_MIGHT_BE_MISSING)
 			}
 			return &http_symbolic.HttpsServer{}, nil
 		},
@@ -510,7 +510,7 @@ func (serv *HttpsServer) Close(ctx *core.Context) {
 
 func newSymbolicHttpsServer(ctx *symbolic.Context, host *symbolic.Host, args ...symbolic.Value) (*http_ns_symb.HttpsServer, *symbolic.Error) {
 	if !ctx.HasAPermissionWithKindAndType(permkind.Provide, permkind.HTTP_PERM_TYPENAME) {
-		ctx.AddSymbolicGoFunctionWarning(HTTP_PROVIDE_PERM_MIGHT_BE_MISSING)
+		ctx.AddMissingPermissionWarning(permkind.Provide, permkind.HTTP_PERM_TYPENAME, HTTP_PROVIDE_PERM_MIGHT_BE_MISSING)
 	}
 
 	symbolic.ANY_HOST_PATTERN.PropertyNames()
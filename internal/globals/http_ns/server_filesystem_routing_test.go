@@ -2,6 +2,8 @@ package http_ns
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/go-git/go-billy/v5/util"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/globals/fs_ns"
+	"github.com/inoxlang/inox/internal/globals/http_ns/spec"
 	"github.com/inoxlang/inox/internal/mimeconsts"
 	"github.com/stretchr/testify/assert"
 )
@@ -365,6 +368,40 @@ func TestFilesystemRouting(t *testing.T) {
 		)
 	})
 
+	t.Run("GET "+spec.API_JSON_SCHEMAS_PATH+" should return the JSON Schemas of the request bodies", func(t *testing.T) {
+		runServerTest(t,
+			serverTestCase{
+				input: `return {
+						routing: {dynamic: /routes/}
+					}`,
+				makeFilesystem: func() core.SnapshotableFilesystem {
+					fls := fs_ns.NewMemFilesystem(10_000)
+					fls.MkdirAll("/routes", fs_ns.DEFAULT_DIR_FMODE)
+					util.WriteFile(fls, "/routes/POST-x.ix", []byte(`
+							manifest {
+								parameters: {
+									name: %str
+								}
+							}
+	
+							return concat "name is " mod-args.name
+						`), fs_ns.DEFAULT_FILE_FMODE)
+					return fls
+				},
+				requests: []requestTestInfo{
+					{
+						path:                spec.API_JSON_SCHEMAS_PATH,
+						acceptedContentType: mimeconsts.JSON_CTYPE,
+						result: `{"/x":{"POST":{"request":{` +
+							`"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+							`"properties":{"name":{"type":"string"}},"required":["name"],"type":"object"}}}}`,
+					},
+				},
+			},
+			createClient,
+		)
+	})
+
 	t.Run("method-agnostic handler module with %(#POST) _method parameter should only accept POST requests", func(t *testing.T) {
 		runServerTest(t,
 			serverTestCase{
@@ -858,6 +895,80 @@ func TestFilesystemRouting(t *testing.T) {
 		)
 	})
 
+	t.Run("the bytecode of a handler module should be cached by the first request and reused by the next ones", func(t *testing.T) {
+		cacheDir := t.TempDir()
+
+		var firstEntry os.FileInfo
+
+		getCacheEntries := func() []os.FileInfo {
+			paths, err := filepath.Glob(filepath.Join(cacheDir, "*"+core.BYTECODE_CACHE_FILE_SUFFIX))
+			if !assert.NoError(t, err) {
+				return nil
+			}
+			var entries []os.FileInfo
+			for _, path := range paths {
+				info, err := os.Stat(path)
+				if assert.NoError(t, err) {
+					entries = append(entries, info)
+				}
+			}
+			return entries
+		}
+
+		runServerTest(t,
+			serverTestCase{
+				input: `return {
+						routing: {dynamic: /routes/}
+					}`,
+				avoidTestParallelization: true,
+				makeFilesystem: func() core.SnapshotableFilesystem {
+					fls := fs_ns.NewMemFilesystem(10_000)
+					fls.MkdirAll("/routes", fs_ns.DEFAULT_DIR_FMODE)
+					util.WriteFile(fls, "/routes/x.ix", []byte(`
+							manifest {}
+	
+							return "hello"
+						`), fs_ns.DEFAULT_FILE_FMODE)
+
+					return fls
+				},
+				finalizeState: func(gs *core.GlobalState) error {
+					gs.BytecodeCache = core.NewBytecodeCache(cacheDir)
+					return nil
+				},
+				requests: []requestTestInfo{
+					{
+						path:                "/x",
+						acceptedContentType: mimeconsts.PLAIN_TEXT_CTYPE,
+						result:              `hello`,
+						onStatusReceived: func() {
+							entries := getCacheEntries()
+							if assert.Len(t, entries, 1) {
+								firstEntry = entries[0]
+							}
+						},
+					},
+					{
+						pause:               100 * time.Millisecond,
+						path:                "/x",
+						acceptedContentType: mimeconsts.PLAIN_TEXT_CTYPE,
+						result:              `hello`,
+						onStatusReceived: func() {
+							entries := getCacheEntries()
+							if !assert.Len(t, entries, 1) || !assert.NotNil(t, firstEntry) {
+								return
+							}
+							//the entry should not have been rewritten.
+							assert.Equal(t, firstEntry.Name(), entries[0].Name())
+							assert.Equal(t, firstEntry.ModTime(), entries[0].ModTime())
+						},
+					},
+				},
+			},
+			createClient,
+		)
+	})
+
 	t.Run("a nonce should be added to all <script> elements", func(t *testing.T) {
 		runServerTest(t,
 			serverTestCase{
@@ -0,0 +1,77 @@
+package spec
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/inoxlang/inox/internal/core"
+)
+
+const (
+	//path of the document listing the JSON Schemas of the request and response bodies of a server's API.
+	API_JSON_SCHEMAS_PATH = "/.well-known/inox/api-schemas.json"
+)
+
+// JSONSchemas returns a JSON-serializable document containing the JSON Schemas (draft 2020-12) of the JSON request
+// and response bodies of the API's operations. The document is indexed by endpoint path and HTTP method:
+//
+//	{"/users": {"POST": {"request": {...}, "responses": {"201": {...}}}}}
+//
+// Operations without any JSON body and catch-all endpoints are not listed. Each warning about an approximated
+// schema is prefixed with the endpoint path, the method and the kind of body (e.g. '/users POST request').
+func (api *API) JSONSchemas() (document map[string]any, warnings []string) {
+	document = map[string]any{}
+
+	endpointPaths := make([]string, 0, len(api.endpoints))
+	for endpointPath := range api.endpoints {
+		endpointPaths = append(endpointPaths, endpointPath)
+	}
+	sort.Strings(endpointPaths) //sorted to make the warnings deterministic
+
+	convert := func(pattern core.Pattern, location string) map[string]any {
+		schema, schemaWarnings := core.ConvertPatternToJsonSchema(pattern)
+		for _, warning := range schemaWarnings {
+			warnings = append(warnings, location+" "+warning)
+		}
+		return schema
+	}
+
+	for _, endpointPath := range endpointPaths {
+		endpoint := api.endpoints[endpointPath]
+		operations := map[string]any{}
+
+		for _, operation := range endpoint.operations {
+			location := endpointPath + " " + operation.httpMethod
+			schemas := map[string]any{}
+
+			if operation.jsonRequestBody != nil {
+				schemas["request"] = convert(operation.jsonRequestBody, location+" request")
+			}
+
+			if len(operation.jsonResponseBodies) > 0 {
+				statusCodes := make([]int, 0, len(operation.jsonResponseBodies))
+				for statusCode := range operation.jsonResponseBodies {
+					statusCodes = append(statusCodes, int(statusCode))
+				}
+				sort.Ints(statusCodes)
+
+				responses := map[string]any{}
+				for _, statusCode := range statusCodes {
+					code := strconv.Itoa(statusCode)
+					responses[code] = convert(operation.jsonResponseBodies[uint16(statusCode)], location+" response "+code)
+				}
+				schemas["responses"] = responses
+			}
+
+			if len(schemas) > 0 {
+				operations[operation.httpMethod] = schemas
+			}
+		}
+
+		if len(operations) > 0 {
+			document[endpointPath] = operations
+		}
+	}
+
+	return document, warnings
+}
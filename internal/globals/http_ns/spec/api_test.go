@@ -1,8 +1,10 @@
 package spec
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,3 +26,56 @@ func TestAPIGetEndpoint(t *testing.T) {
 		assert.Same(t, api.endpoints["/users/{user-id}"], endpt)
 	})
 }
+
+func TestAPIJSONSchemas(t *testing.T) {
+	usersEndpoint := &ApiEndpoint{path: "/users"}
+	usersEndpoint.operations = []ApiOperation{
+		{
+			endpoint:   usersEndpoint,
+			httpMethod: "POST",
+			jsonRequestBody: core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+				{Name: "name", Pattern: core.STR_PATTERN},
+				{Name: "range", Pattern: core.INT_RANGE_PATTERN},
+			}),
+			jsonResponseBodies: map[uint16]core.Pattern{
+				201: core.NewInexactObjectPattern([]core.ObjectPatternEntry{{Name: "id", Pattern: core.INT_PATTERN}}),
+			},
+		},
+		{
+			endpoint:   usersEndpoint,
+			httpMethod: "GET",
+		},
+	}
+
+	api := utils.Must(NewAPI(map[string]*ApiEndpoint{
+		"/users": usersEndpoint,
+		"/":      {path: "/"},
+	}))
+
+	document, warnings := api.JSONSchemas()
+
+	assert.Equal(t, []string{
+		"/users POST request /properties/range: %int-range has no JSON Schema equivalent, any value is accepted",
+	}, warnings)
+
+	assert.JSONEq(t, `{
+		"/users": {
+			"POST": {
+				"request": {
+					"$schema": "https://json-schema.org/draft/2020-12/schema",
+					"type": "object",
+					"properties": {"name": {"type": "string"}, "range": {}},
+					"required": ["name", "range"]
+				},
+				"responses": {
+					"201": {
+						"$schema": "https://json-schema.org/draft/2020-12/schema",
+						"type": "object",
+						"properties": {"id": {"type": "integer"}},
+						"required": ["id"]
+					}
+				}
+			}
+		}
+	}`, string(utils.Must(json.Marshal(document))))
+}
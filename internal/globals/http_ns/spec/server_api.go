@@ -31,6 +31,74 @@ type ServerApiResolutionConfig struct {
 	IgnoreModulesWithErrors bool
 }
 
+// GetFSRoutingHandlerEndpoint returns the path of the endpoint implemented by the handler module at modulePath,
+// dir is the directory containing the handler modules. If the module is not inside dir or is a .spec.ix file
+// ok is false. The returned method is empty if the module supports several methods.
+func GetFSRoutingHandlerEndpoint(modulePath, dir string) (endpointPath string, method string, ok bool) {
+	dir = core.AppendTrailingSlashIfNotPresent(dir)
+
+	if !strings.HasPrefix(modulePath, dir) ||
+		!strings.HasSuffix(modulePath, inoxconsts.INOXLANG_FILE_EXTENSION) ||
+		strings.HasSuffix(modulePath, inoxconsts.INOXLANG_SPEC_FILE_SUFFIX) {
+		return "", "", false
+	}
+
+	urlDirPath := "/"
+	segments := strings.Split(strings.TrimPrefix(modulePath, dir), "/")
+
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == "" {
+			continue
+		}
+		if segment[0] == ':' {
+			segment = "{" + segment[1:] + "}"
+		}
+		urlDirPath = filepath.Join(urlDirPath, segment) + "/"
+	}
+
+	endpointPath, method, _ = getFSRoutingHandlerEndpoint(segments[len(segments)-1], urlDirPath)
+	return endpointPath, method, true
+}
+
+// getFSRoutingHandlerEndpoint determines the endpoint path and method by 'parsing' the name of a handler module,
+// urlDirPath is the URL path of the module's directory. explicit is true if the name is a method (GET.ix), starts
+// with a method (GET-about.ix) or is index.ix.
+func getFSRoutingHandlerEndpoint(entryName, urlDirPath string) (endpointPath string, method string, explicit bool) {
+	urlDirPath = core.AppendTrailingSlashIfNotPresent(urlDirPath)
+
+	urlDirPathNoTrailingSlash := strings.TrimSuffix(urlDirPath, "/")
+	if urlDirPath == "/" {
+		urlDirPathNoTrailingSlash = "/"
+	}
+
+	entryNameNoExt := strings.TrimSuffix(entryName, inoxconsts.INOXLANG_FILE_EXTENSION)
+	explicit = true
+
+	if slices.Contains(FS_ROUTING_METHODS, entryNameNoExt) { //GET.ix, POST.ix, ...
+		//add operation
+		method = entryNameNoExt
+		endpointPath = urlDirPathNoTrailingSlash
+	} else {
+		beforeName, name, ok := strings.Cut(entryNameNoExt, "-")
+
+		if ok && slices.Contains(FS_ROUTING_METHODS, beforeName) { //POST-... , GET-...
+			method = beforeName
+			endpointPath = filepath.Join(urlDirPath, name)
+		} else if entryName == FS_ROUTING_INDEX_MODULE { //index.ix
+			endpointPath = urlDirPathNoTrailingSlash
+		} else { //example: about.ix
+			endpointPath = filepath.Join(urlDirPath, entryNameNoExt)
+			explicit = false
+		}
+	}
+
+	//Remove trailing slash.
+	if endpointPath != "/" {
+		endpointPath = strings.TrimSuffix(endpointPath, "/")
+	}
+	return
+}
+
 func GetFSRoutingServerAPI(ctx *core.Context, dir string, config ServerApiResolutionConfig) (*API, error) {
 	preparedModuleCache := map[string]*core.GlobalState{}
 	defer func() {
@@ -72,11 +140,6 @@ func addFilesysteDirEndpoints(
 		return err
 	}
 
-	urlDirPathNoTrailingSlash := strings.TrimSuffix(urlDirPath, "/")
-	if urlDirPath == "/" {
-		urlDirPathNoTrailingSlash = "/"
-	}
-
 	parentState, _ := ctx.GetState()
 
 	for _, entry := range entries {
@@ -111,35 +174,8 @@ func addFilesysteDirEndpoints(
 			continue
 		}
 
-		entryNameNoExt := strings.TrimSuffix(entryName, inoxconsts.INOXLANG_FILE_EXTENSION)
-
 		//Determine the endpoint path and method by 'parsing' the entry name.
-		var endpointPath string
-		var method string //if empty the handler module supports several methods
-		returnErrIfNotModule := true
-
-		if slices.Contains(FS_ROUTING_METHODS, entryNameNoExt) { //GET.ix, POST.ix, ...
-			//add operation
-			method = entryNameNoExt
-			endpointPath = urlDirPathNoTrailingSlash
-		} else {
-			beforeName, name, ok := strings.Cut(entryNameNoExt, "-")
-
-			if ok && slices.Contains(FS_ROUTING_METHODS, beforeName) { //POST-... , GET-...
-				method = beforeName
-				endpointPath = filepath.Join(urlDirPath, name)
-			} else if entryName == FS_ROUTING_INDEX_MODULE { //index.ix
-				endpointPath = urlDirPathNoTrailingSlash
-			} else { //example: about.ix
-				endpointPath = filepath.Join(urlDirPath, entryNameNoExt)
-				returnErrIfNotModule = false
-			}
-		}
-
-		//Remove trailing slash.
-		if endpointPath != "/" {
-			endpointPath = strings.TrimSuffix(endpointPath, "/")
-		}
+		endpointPath, method, returnErrIfNotModule := getFSRoutingHandlerEndpoint(entryName, urlDirPath)
 
 		//Determine if the file is an Inox module.
 		chunk, err := core.ParseFileChunk(absEntryPath, fls)
@@ -749,3 +749,31 @@ func TestGetFilesystemRoutingServerAPI(t *testing.T) {
 		})
 	})
 }
+
+func TestGetFSRoutingHandlerEndpoint(t *testing.T) {
+	testCases := []struct {
+		modulePath   string
+		endpointPath string
+		method       string
+		ok           bool
+	}{
+		{"/routes/index.ix", "/", "", true},
+		{"/routes/GET.ix", "/", "GET", true},
+		{"/routes/about.ix", "/about", "", true},
+		{"/routes/GET-users.ix", "/users", "GET", true},
+		{"/routes/users/POST.ix", "/users", "POST", true},
+		{"/routes/users/:user-id/GET.ix", "/users/{user-id}", "GET", true},
+		{"/routes/users/index.ix", "/users", "", true},
+		{"/routes/GET.spec.ix", "", "", false},
+		{"/other/GET.ix", "", "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.modulePath, func(t *testing.T) {
+			endpointPath, method, ok := GetFSRoutingHandlerEndpoint(testCase.modulePath, "/routes")
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.endpointPath, endpointPath)
+			assert.Equal(t, testCase.method, method)
+		})
+	}
+}
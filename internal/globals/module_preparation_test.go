@@ -317,6 +317,101 @@ func TestPrepareLocalModule(t *testing.T) {
 		assert.Nil(t, state2)
 	})
 
+	t.Run("incremental checking", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "script.ix")
+		includedFile := filepath.Join(dir, "included.ix")
+		compilationCtx := createCompilationCtx(dir)
+		defer compilationCtx.CancelGracefully()
+
+		os.WriteFile(includedFile, []byte(`
+			includable-chunk
+
+			fn double(n int) int {
+				return (2 * n)
+			}
+		`), 0o600)
+
+		code := `
+			manifest {
+				permissions: {
+					read: %/...
+				}
+			}
+			import ./included.ix
+
+			fn f(s str) str {
+				return s
+			}
+
+			a = double(1)
+			b = c 		  	# static check error
+			d = f(a) 		# symbolic check error
+			e = 1
+			g = e
+		`
+
+		prepare := func(prevMod *core.Module, prevCheckData *core.IncrementalCheckData, incremental bool) (*core.GlobalState, *core.Module) {
+			ctx := core.NewContext(core.ContextConfig{
+				Permissions: append(core.GetDefaultGlobalVarPermissions(), core.CreateFsReadPerm(core.PathPattern("/..."))),
+				Filesystem:  fs_ns.GetOsFilesystem(),
+			})
+			core.NewGlobalState(ctx)
+			t.Cleanup(func() { ctx.CancelGracefully() })
+
+			args := core.ModulePreparationArgs{
+				Fpath:                     file,
+				ParsingCompilationContext: compilationCtx,
+				ParentContext:             ctx,
+				ParentContextRequired:     true,
+				Out:                       io.Discard,
+				DataExtractionMode:        true,
+				ScriptContextFileSystem:   fs_ns.GetOsFilesystem(),
+
+				IncrementalChecking: incremental,
+				PreviousCheckData:   prevCheckData,
+			}
+			if prevMod != nil {
+				args.PreviousMainChunk = prevMod.MainChunk
+			}
+
+			state, mod, _, err := core.PrepareLocalModule(args)
+			assert.Error(t, err)
+			return state, mod
+		}
+
+		os.WriteFile(file, []byte(code), 0o600)
+
+		state1, mod1 := prepare(nil, nil, true)
+		if !assert.NotNil(t, state1) || !assert.NotNil(t, state1.IncrementalCheckData) {
+			return
+		}
+		assert.Zero(t, state1.IncrementalCheckData.ReusedTopLevelStatementCount())
+
+		//change the last statement and prepare the module again.
+
+		os.WriteFile(file, []byte(strings.Replace(code, "g = e", "g = (e + \"s\") # symbolic check error", 1)), 0o600)
+
+		state2, _ := prepare(mod1, state1.IncrementalCheckData, true)
+		if !assert.NotNil(t, state2) || !assert.NotNil(t, state2.IncrementalCheckData) {
+			return
+		}
+		assert.GreaterOrEqual(t, state2.IncrementalCheckData.ReusedTopLevelStatementCount(), 4)
+
+		//the results should be the same as the results of a full preparation.
+
+		state3, _ := prepare(nil, nil, false)
+		if !assert.NotNil(t, state3) {
+			return
+		}
+
+		assert.Nil(t, state3.IncrementalCheckData)
+		assert.Equal(t, state3.StaticCheckData.Errors(), state2.StaticCheckData.Errors())
+		assert.Len(t, state2.StaticCheckData.Errors(), 1)
+		assert.Equal(t, state3.SymbolicData.Errors(), state2.SymbolicData.Errors())
+		assert.Len(t, state2.SymbolicData.Errors(), 3)
+	})
+
 	t.Run("specified log level", func(t *testing.T) {
 		dir := t.TempDir()
 		file := filepath.Join(dir, "script.ix")
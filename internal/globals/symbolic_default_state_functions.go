@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"github.com/inoxlang/inox/internal/commonfmt"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/core/symbolic"
 	_http_symbolic "github.com/inoxlang/inox/internal/globals/http_ns/symbolic"
@@ -13,6 +14,11 @@ var (
 		symbolic.NewMultivalue(symbolic.ANY_PATTERN, symbolic.ANY_INDEXABLE),
 	}
 	RAND_FN_PARAM_NAMES = []string{"arg"}
+
+	SYMB_JSON_SCHEMA_RECORD = symbolic.NewInexactRecord(map[string]symbolic.Serializable{
+		"schema":   symbolic.ANY_STRING,
+		"warnings": symbolic.NewTupleOf(symbolic.ANY_STRING),
+	}, nil)
 )
 
 func init() {
@@ -55,19 +61,44 @@ func init() {
 		},
 
 		_print, func(ctx *symbolic.Context, arg ...symbolic.Value) {},
+		_assert_golden, func(ctx *symbolic.Context, v symbolic.Value, path *symbolic.Path) {},
 		_fprint, func(ctx *symbolic.Context, out symbolic.Writable, arg ...symbolic.Value) {},
 		_Error, func(ctx *symbolic.Context, s *symbolic.String, args ...symbolic.Serializable) *symbolic.Error {
-			if len(args) > 1 {
-				ctx.AddSymbolicGoFunctionError("at most two arguments were expected")
-			}
-			if len(args) == 0 {
-				return symbolic.NewError(symbolic.Nil)
+			var (
+				kind     *symbolic.Identifier
+				hasCause bool
+				data     symbolic.Value = symbolic.Nil
+				hasData  bool
+			)
+
+			for _, arg := range args {
+				switch a := arg.(type) {
+				case *symbolic.Identifier:
+					if kind != nil {
+						ctx.AddSymbolicGoFunctionError(commonfmt.FmtErrArgumentProvidedAtLeastTwice("kind").Error())
+					}
+					kind = a
+				case *symbolic.Error:
+					if hasCause {
+						ctx.AddSymbolicGoFunctionError(commonfmt.FmtErrArgumentProvidedAtLeastTwice("cause").Error())
+					}
+					hasCause = true
+				default:
+					if hasData {
+						ctx.AddSymbolicGoFunctionError(commonfmt.FmtErrArgumentProvidedAtLeastTwice("data").Error())
+					}
+					if a.IsMutable() {
+						ctx.AddSymbolicGoFunctionError("data provided to create error should be immutable")
+					}
+					hasData = true
+					data = a
+				}
 			}
 
-			if args[0].IsMutable() {
-				ctx.AddSymbolicGoFunctionError("data provided to create error should be immutable")
+			if kind != nil && kind.HasConcreteName() {
+				return symbolic.NewErrorWithKind(data, kind.Name())
 			}
-			return symbolic.NewError(args[0])
+			return symbolic.NewError(data)
 		},
 
 		//resource
@@ -179,6 +210,9 @@ func init() {
 		core.ToPrettyJSON, func(ctx *symbolic.Context, arg symbolic.Value, pattern *symbolic.OptionalParam[symbolic.Pattern]) *symbolic.String {
 			return symbolic.ANY_STRING
 		},
+		_tojsonschema, func(ctx *symbolic.Context, pattern symbolic.Pattern) *symbolic.Record {
+			return SYMB_JSON_SCHEMA_RECORD
+		},
 
 		_parse, func(ctx *symbolic.Context, arg symbolic.Readable, p symbolic.Pattern) (symbolic.Value, *symbolic.Error) {
 			return p.SymbolicValue(), nil
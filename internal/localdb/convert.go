@@ -0,0 +1,125 @@
+package localdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/inoxlang/inox/internal/buntdb"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/filekv"
+)
+
+// ConvertDatabase converts the top-level entities of an existing local database to another serialization format, the
+// entities are parsed using the current schema of the database. All entities are converted in memory before anything
+// is written, so an entity that cannot be converted leaves the database untouched. The previous format is returned.
+func ConvertDatabase(ctx *core.Context, config LocalDatabaseConfig, format core.SerializationFormat) (_ core.SerializationFormat, finalErr error) {
+	if config.Restricted {
+		return 0, errors.New("a database opened in restricted mode cannot be converted")
+	}
+
+	db, err := openLocalDatabaseWithConfig(ctx, config)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		closeErr := db.Close(ctx)
+		if finalErr == nil && closeErr != nil {
+			finalErr = closeErr
+		}
+	}()
+
+	prevFormat := db.format
+	if prevFormat == format {
+		return prevFormat, nil
+	}
+
+	converted := map[core.Path]string{}
+
+	err = db.schema.ForEachEntry(func(entry core.ObjectPatternEntry) error {
+		path := core.PathFrom("/" + entry.Name)
+
+		serialized, ok := db.GetSerialized(ctx, path)
+		if !ok {
+			return nil
+		}
+
+		repr, err := convertRepresentation(ctx, serialized, entry.Pattern, prevFormat, format)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+		converted[path] = repr
+		return nil
+	})
+
+	if err != nil {
+		return prevFormat, err
+	}
+
+	//make sure the converted representations can be loaded before writing anything.
+	err = db.schema.ForEachEntry(func(entry core.ObjectPatternEntry) error {
+		path := core.PathFrom("/" + entry.Name)
+
+		repr, ok := converted[path]
+		if !ok {
+			return nil
+		}
+
+		if _, err := convertRepresentation(ctx, repr, entry.Pattern, format, format); err != nil {
+			return fmt.Errorf("the converted representation of %s cannot be loaded: %w", path, err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return prevFormat, err
+	}
+
+	//the values and the format are written in a single transaction of the meta KV that is only committed if the
+	//transaction of the main KV has been committed, so a failure leaves the database untouched.
+	err = db.metaKV.Update(func(metaTx *buntdb.Tx) error {
+		if _, _, err := metaTx.Set(FORMAT_KEY, format.String(), nil); err != nil {
+			return fmt.Errorf("failed to store the new serialization format: %w", err)
+		}
+
+		return db.mainKV.UpdateNoCtx(func(tx *filekv.KVTx) error {
+			for path, repr := range converted {
+				if err := tx.SetSerialized(ctx, path, repr); err != nil {
+					return fmt.Errorf("failed to write the converted representation of %s: %w", path, err)
+				}
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return prevFormat, err
+	}
+	db.format = format
+
+	return prevFormat, nil
+}
+
+func convertRepresentation(ctx *core.Context, serialized string, pattern core.Pattern, from, to core.SerializationFormat) (string, error) {
+	sequencePattern, isSequence := pattern.(core.StorageSequencePattern)
+	if !isSequence {
+		value, err := core.ParseStorageRepresentation(ctx, serialized, pattern, from)
+		if err != nil {
+			return "", err
+		}
+		return core.GetStorageRepresentation(ctx, value, pattern, to)
+	}
+
+	elementPatterns := sequencePattern.StoredElementPatterns()
+
+	var elements []core.Serializable
+	err := core.ParseStorageSequenceRepresentation(ctx, serialized, elementPatterns, from, func(_ int, elem core.Serializable) error {
+		elements = append(elements, elem)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return core.GetStorageSequenceRepresentation(ctx, elements, elementPatterns, to)
+}
@@ -21,6 +21,7 @@ import (
 
 const (
 	SCHEMA_KEY = "/_schema_"
+	FORMAT_KEY = "/_format_" //serialization format of the values, databases created before its introduction use JSON.
 
 	DB_KV_FILE   = "db.bbolt"
 	META_KV_FILE = "meta.buntdb"
@@ -34,12 +35,13 @@ var (
 
 	ErrOpenDatabase = errors.New("database is already open by the current process or another one")
 
-	_ core.Database = (*LocalDatabase)(nil)
+	_ core.Database             = (*LocalDatabase)(nil)
+	_ core.FormatAwareDataStore = (*LocalDatabase)(nil)
 )
 
 func init() {
 	core.RegisterOpenDbFn(core.LDB_SCHEME, func(ctx *core.Context, config core.DbOpenConfiguration) (core.Database, error) {
-		return OpenDatabase(ctx, config.Resource, !config.FullAccess)
+		return openDatabase(ctx, config.Resource, !config.FullAccess, config.Format)
 	})
 
 	checkResolutionData := func(node parse.Node, _ core.Project) (errMsg string) {
@@ -63,6 +65,7 @@ type LocalDatabase struct {
 	mainKV  *filekv.SingleFileKV
 	metaKV  *buntdb.DB
 	schema  *core.ObjectPattern
+	format  core.SerializationFormat
 	logger  zerolog.Logger
 
 	topLevelValues     map[string]core.Serializable
@@ -70,14 +73,24 @@ type LocalDatabase struct {
 }
 
 type LocalDatabaseConfig struct {
-	OsFsDir    core.Path
+	OsFsDir    core.Path //ignored if InMemory is true
 	Host       core.Host
-	InMemory   bool
+	InMemory   bool //if true the data is only stored in memory and is lost when the database is closed
 	Restricted bool
+
+	//Format is the serialization format of the values of the database if it is new,
+	//the format of existing databases can be changed by calling ConvertDatabase.
+	Format core.SerializationFormat
 }
 
 // OpenDatabase opens a local database, read, create & write permissions are required.
+// The values of the database are stored in the JSON format if the database is new.
 func OpenDatabase(ctx *core.Context, r core.ResourceName, restrictedAccess bool) (*LocalDatabase, error) {
+	return openDatabase(ctx, r, restrictedAccess, core.JSON_SERIALIZATION_FORMAT)
+}
+
+// openDatabase opens a local database, $format is the serialization format of the values if the database is new.
+func openDatabase(ctx *core.Context, r core.ResourceName, restrictedAccess bool, format core.SerializationFormat) (*LocalDatabase, error) {
 
 	var host core.Host
 	switch resource := r.(type) {
@@ -108,18 +121,30 @@ func OpenDatabase(ctx *core.Context, r core.ResourceName, restrictedAccess bool)
 	if project == nil || reflect.ValueOf(project).IsZero() {
 		return nil, errors.New("local databases are only supported in project mode")
 	}
-	dbsDir := project.DevDatabasesDirOnOsFs()
 
-	db, err := openLocalDatabaseWithConfig(ctx, LocalDatabaseConfig{
-		OsFsDir:    core.DirPathFrom(filepath.Join(dbsDir, host.Name())),
+	config := LocalDatabaseConfig{
 		Host:       host,
 		Restricted: restrictedAccess,
-	})
+		Format:     format,
+	}
+
+	if p, ok := project.(core.InMemoryDatabasesProject); ok && p.HasInMemoryDatabases() {
+		config.InMemory = true
+	} else {
+		dbsDir := project.DevDatabasesDirOnOsFs()
+		config.OsFsDir = core.DirPathFrom(filepath.Join(dbsDir, host.Name()))
+	}
+
+	db, err := openLocalDatabaseWithConfig(ctx, config)
 
 	return db, err
 }
 
 func openLocalDatabaseWithConfig(ctx *core.Context, config LocalDatabaseConfig) (*LocalDatabase, error) {
+	if config.InMemory {
+		return openInMemoryLocalDatabase(ctx, config)
+	}
+
 	osFs := fs_ns.GetOsFilesystem()
 	mainKVPath := config.OsFsDir.Join(core.Path("./"+DB_KV_FILE), osFs)
 	metaKVPath := config.OsFsDir.Join(core.Path("./"+META_KV_FILE), osFs)
@@ -190,9 +215,39 @@ func openLocalDatabaseWithConfig(ctx *core.Context, config LocalDatabaseConfig)
 		}
 	}
 
+	return localDB.loadMetadata(ctx, config)
+}
+
+// openInMemoryLocalDatabase opens a database whose data is only stored in memory, no file is created and the data
+// is lost when the database is closed.
+func openInMemoryLocalDatabase(ctx *core.Context, config LocalDatabaseConfig) (*LocalDatabase, error) {
+	localDB := &LocalDatabase{
+		host: config.Host,
+	}
+
+	mainKv, err := filekv.OpenSingleFileKV(filekv.KvStoreConfig{
+		InMemory: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	localDB.mainKV = mainKv
+
+	metaKV, err := buntdb.OpenBuntDBNoPermCheck(":memory:", nil)
+	if err != nil {
+		mainKv.Close(ctx)
+		return nil, err
+	}
+	localDB.metaKV = metaKV
+
+	return localDB.loadMetadata(ctx, config)
+}
+
+// loadMetadata reads the schema and the serialization format of the database from the meta KV.
+func (localDB *LocalDatabase) loadMetadata(ctx *core.Context, config LocalDatabaseConfig) (*LocalDatabase, error) {
 	//get schema
 	var serializedSchema string
-	err = localDB.metaKV.View(func(tx *buntdb.Tx) error {
+	err := localDB.metaKV.View(func(tx *buntdb.Tx) error {
 		serialized, err := tx.Get(SCHEMA_KEY, true)
 		if err != nil {
 			return err
@@ -227,6 +282,43 @@ func openLocalDatabaseWithConfig(ctx *core.Context, config LocalDatabaseConfig)
 		localDB.schema = core.NewInexactObjectPattern([]core.ObjectPatternEntry{})
 	}
 
+	//get serialization format
+	var serializedFormat string
+	err = localDB.metaKV.View(func(tx *buntdb.Tx) error {
+		serialized, err := tx.Get(FORMAT_KEY, true)
+		if err != nil {
+			return err
+		}
+		serializedFormat = serialized
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		format, err := core.ParseSerializationFormat(serializedFormat)
+		if err != nil {
+			localDB.Close(ctx)
+			return nil, fmt.Errorf("failed to read the serialization format of the database: %w", err)
+		}
+		localDB.format = format
+	case !errors.Is(err, buntdb.ErrNotFound):
+		localDB.Close(ctx)
+		return nil, fmt.Errorf("failed to read the serialization format of the database: %w", err)
+	case schemaFound:
+		localDB.format = core.JSON_SERIALIZATION_FORMAT
+	default: //new database
+		localDB.format = config.Format
+
+		err := localDB.metaKV.Update(func(tx *buntdb.Tx) error {
+			_, _, err := tx.Set(FORMAT_KEY, localDB.format.String(), nil)
+			return err
+		})
+		if err != nil {
+			localDB.Close(ctx)
+			return nil, fmt.Errorf("failed to store the serialization format of the database: %w", err)
+		}
+	}
+
 	return localDB, nil
 }
 
@@ -238,6 +330,11 @@ func (ldb *LocalDatabase) Schema() *core.ObjectPattern {
 	return ldb.schema
 }
 
+// SerializationFormat returns the format of the values stored in the database.
+func (ldb *LocalDatabase) SerializationFormat() core.SerializationFormat {
+	return ldb.format
+}
+
 func (ldb *LocalDatabase) BaseURL() core.URL {
 	return core.URL(ldb.host + "/")
 }
@@ -282,6 +379,10 @@ func (ldb *LocalDatabase) UpdateSchema(ctx *core.Context, schema *core.ObjectPat
 
 	err := ldb.metaKV.Update(func(tx *buntdb.Tx) error {
 		_, _, err := tx.Set(SCHEMA_KEY, repr, nil)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(FORMAT_KEY, ldb.format.String(), nil)
 		return err
 	})
 	if err != nil {
@@ -382,7 +483,11 @@ func (ldb *LocalDatabase) Close(ctx *core.Context) error {
 }
 
 func (ldb *LocalDatabase) Get(ctx *core.Context, key core.Path) (core.Value, core.Bool) {
-	return utils.Must2(ldb.mainKV.Get(ctx, key, ldb))
+	serialized, found := ldb.GetSerialized(ctx, key)
+	if !found {
+		return core.Nil, false
+	}
+	return utils.Must(core.ParseStorageRepresentation(ctx, serialized, nil, ldb.format)), true
 }
 
 func (ldb *LocalDatabase) GetSerialized(ctx *core.Context, key core.Path) (string, bool) {
@@ -395,7 +500,7 @@ func (ldb *LocalDatabase) Has(ctx *core.Context, key core.Path) bool {
 }
 
 func (ldb *LocalDatabase) Set(ctx *core.Context, key core.Path, value core.Serializable) {
-	ldb.mainKV.Set(ctx, key, value, ldb)
+	ldb.SetSerialized(ctx, key, utils.Must(core.GetStorageRepresentation(ctx, value, nil, ldb.format)))
 }
 
 func (ldb *LocalDatabase) SetSerialized(ctx *core.Context, key core.Path, serialized string) {
@@ -403,7 +508,7 @@ func (ldb *LocalDatabase) SetSerialized(ctx *core.Context, key core.Path, serial
 }
 
 func (ldb *LocalDatabase) Insert(ctx *core.Context, key core.Path, value core.Serializable) {
-	ldb.mainKV.Insert(ctx, key, value, ldb)
+	ldb.InsertSerialized(ctx, key, utils.Must(core.GetStorageRepresentation(ctx, value, nil, ldb.format)))
 }
 
 func (ldb *LocalDatabase) InsertSerialized(ctx *core.Context, key core.Path, serialized string) {
@@ -3,10 +3,12 @@
 package localdb
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/core/permkind"
 	_ "github.com/inoxlang/inox/internal/globals/containers"
@@ -260,6 +262,107 @@ func TestOpenDatabase(t *testing.T) {
 		assert.True(t, schemaVisibleByDB2.Equal(ctx2, schema, map[uintptr]uintptr{}, 0))
 	})
 
+	t.Run("in-memory databases", func(t *testing.T) {
+		dir, _ := filepath.Abs(t.TempDir())
+
+		fls := fs_ns.NewMemFilesystem(MEM_FS_STORAGE_SIZE)
+		project := &inMemoryDatabasesProject{
+			databasesDirProject{
+				Project:      project.NewDummyProject("proj", fls),
+				databasesDir: dir,
+			},
+		}
+
+		ctxConfig := core.ContextConfig{
+			HostDefinitions: map[core.Host]core.Value{
+				core.Host("ldb://main"): HOST,
+			},
+			Filesystem: fls,
+		}
+
+		ctx1 := core.NewContexWithEmptyState(ctxConfig, nil)
+		ctx1.GetClosestState().Project = project
+
+		db1, err := OpenDatabase(ctx1, HOST, false)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer db1.Close(ctx1)
+
+		db1.SetSerialized(ctx1, "/a", "1")
+
+		//the databases should be isolated from each other.
+		ctx2 := core.NewContexWithEmptyState(ctxConfig, nil)
+		ctx2.GetClosestState().Project = project
+
+		db2, err := OpenDatabase(ctx2, HOST, false)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer db2.Close(ctx2)
+
+		assert.False(t, db2.Has(ctx2, "/a"))
+		assert.True(t, db1.Has(ctx1, "/a"))
+
+		//no file should be created.
+		entries, err := os.ReadDir(dir)
+		if assert.NoError(t, err) {
+			assert.Empty(t, entries)
+		}
+	})
+
+	t.Run("new database with the format of the open configuration", func(t *testing.T) {
+		dir, _ := filepath.Abs(t.TempDir())
+
+		fls := fs_ns.NewMemFilesystem(MEM_FS_STORAGE_SIZE)
+		project := &databasesDirProject{
+			Project:      project.NewDummyProject("proj", fls),
+			databasesDir: dir,
+		}
+
+		ctxConfig := core.ContextConfig{
+			HostDefinitions: map[core.Host]core.Value{
+				core.Host("ldb://main"): HOST,
+			},
+			Filesystem: fls,
+		}
+
+		openDB, ok := core.GetOpenDbFn(core.LDB_SCHEME)
+		if !assert.True(t, ok) {
+			return
+		}
+
+		ctx1 := core.NewContexWithEmptyState(ctxConfig, nil)
+		ctx1.GetClosestState().Project = project
+
+		db1, err := openDB(ctx1, core.DbOpenConfiguration{
+			Resource:   HOST,
+			FullAccess: true,
+			Project:    project,
+			Format:     core.BINARY_SERIALIZATION_FORMAT,
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, core.BINARY_SERIALIZATION_FORMAT, db1.(*LocalDatabase).SerializationFormat())
+
+		//the format should be stored in the database.
+		if !assert.NoError(t, db1.Close(ctx1)) {
+			return
+		}
+
+		ctx2 := core.NewContexWithEmptyState(ctxConfig, nil)
+		ctx2.GetClosestState().Project = project
+
+		db2, err := OpenDatabase(ctx2, HOST, false)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer db2.Close(ctx2)
+
+		assert.Equal(t, core.BINARY_SERIALIZATION_FORMAT, db2.SerializationFormat())
+	})
+
 	t.Run("re-open with a schema", func(t *testing.T) {
 
 		t.Run("top-level Set with URL-based uniqueness", func(t *testing.T) {
@@ -336,6 +439,23 @@ func TestOpenDatabase(t *testing.T) {
 	})
 }
 
+type databasesDirProject struct {
+	core.Project
+	databasesDir string
+}
+
+func (p *databasesDirProject) DevDatabasesDirOnOsFs() string {
+	return p.databasesDir
+}
+
+type inMemoryDatabasesProject struct {
+	databasesDirProject
+}
+
+func (p *inMemoryDatabasesProject) HasInMemoryDatabases() bool {
+	return true
+}
+
 func TestLocalDatabase(t *testing.T) {
 
 	HOST := core.Host("ldb://main")
@@ -916,4 +1036,373 @@ func TestUpdateSchema(t *testing.T) {
 			return
 		}
 	})
+
+	t.Run("top level Set included with a non-empty list during migration should contain the elements", func(t *testing.T) {
+		tempdir := t.TempDir()
+		fls := fs_ns.NewMemFilesystem(MEM_FS_STORAGE_SIZE)
+
+		ldb, ctx, ok := openDB(tempdir, fls)
+		if !ok {
+			return
+		}
+		defer ldb.Close(ctx)
+
+		namedObjectPattern := core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+			{
+				Name:    "name",
+				Pattern: core.STR_PATTERN,
+			},
+		})
+
+		setPattern :=
+			utils.Must(setcoll.SET_PATTERN.CallImpl(
+				setcoll.SET_PATTERN,
+				[]core.Serializable{namedObjectPattern, common.URL_UNIQUENESS_IDENT}),
+			)
+
+		schema := core.NewInexactObjectPattern([]core.ObjectPatternEntry{{Name: "users", Pattern: setPattern}})
+
+		ldb.UpdateSchema(ctx, schema, core.MigrationOpHandlers{
+			Inclusions: map[core.PathPattern]*core.MigrationOpHandler{
+				"/users": {
+					InitialValue: core.NewWrappedValueList(
+						core.NewObjectFromMap(core.ValMap{"name": core.String("foo")}, ctx),
+					),
+				},
+			},
+		})
+
+		topLevelValues := utils.Must(ldb.LoadTopLevelEntities(ctx))
+		if !assert.Contains(t, topLevelValues, "users") {
+			return
+		}
+
+		assert.False(t, topLevelValues["users"].(*setcoll.Set).IsEmpty(ctx))
+
+		//make sure the Set has been saved
+		s, _ := ldb.GetSerialized(ctx, "/users")
+		assert.Contains(t, s, "foo")
+	})
+}
+
+func TestSeedDatabase(t *testing.T) {
+	const HOST = core.Host("ldb://main")
+
+	namedObjectPattern := core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+		{
+			Name:    "name",
+			Pattern: core.STR_PATTERN,
+		},
+	})
+
+	setPattern :=
+		utils.Must(setcoll.SET_PATTERN.CallImpl(
+			setcoll.SET_PATTERN,
+			[]core.Serializable{namedObjectPattern, common.URL_UNIQUENESS_IDENT}),
+		)
+
+	schema := core.NewInexactObjectPattern([]core.ObjectPatternEntry{{Name: "users", Pattern: setPattern}})
+
+	setup := func(t *testing.T) (*core.Context, LocalDatabaseConfig) {
+		tempdir := t.TempDir()
+		fls := fs_ns.NewMemFilesystem(MEM_FS_STORAGE_SIZE)
+
+		ctx := core.NewContexWithEmptyState(core.ContextConfig{
+			Permissions: []core.Permission{
+				core.DatabasePermission{Kind_: permkind.Read, Entity: HOST},
+				core.DatabasePermission{Kind_: permkind.Write, Entity: HOST},
+			},
+			Filesystem: fls,
+		}, nil)
+		t.Cleanup(func() { ctx.CancelGracefully() })
+
+		config := LocalDatabaseConfig{
+			Host:    HOST,
+			OsFsDir: core.DirPathFrom(filepath.Join(tempdir, "data")),
+		}
+
+		return ctx, config
+	}
+
+	createSchema := func(t *testing.T, ctx *core.Context, config LocalDatabaseConfig) bool {
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		ldb.UpdateSchema(ctx, schema, core.MigrationOpHandlers{
+			Inclusions: map[core.PathPattern]*core.MigrationOpHandler{
+				"/users": {
+					InitialValue: core.NewWrappedValueList(),
+				},
+			},
+		})
+		return assert.NoError(t, ldb.Close(ctx))
+	}
+
+	t.Run("JSON fixtures", func(t *testing.T) {
+		ctx, config := setup(t)
+		if !createSchema(t, ctx, config) {
+			return
+		}
+
+		util.WriteFile(ctx.GetFileSystem(), "/fixtures.json", []byte(`{"users": [{"name": "foo"}, {"name": "bar"}]}`), 0600)
+
+		//seeding twice should not duplicate the elements because their URLs are stable.
+		for i := 0; i < 2; i++ {
+			seeded, err := SeedDatabase(ctx, config, "/fixtures.json")
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, []string{"users"}, seeded)
+		}
+
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ldb.Close(ctx)
+
+		users := utils.Must(ldb.LoadTopLevelEntities(ctx))["users"].(*setcoll.Set)
+
+		for i, name := range []string{"foo", "bar"} {
+			key := core.MustElementKeyFrom(core.MakeStableFixtureULID("users", i).String())
+			user, err := users.GetElementByKey(ctx, key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, core.String(name), user.(*core.Object).Prop(ctx, "name"))
+		}
+	})
+
+	t.Run("Inox fixtures", func(t *testing.T) {
+		ctx, config := setup(t)
+		if !createSchema(t, ctx, config) {
+			return
+		}
+
+		util.WriteFile(ctx.GetFileSystem(), "/fixtures.ix", []byte(`{users: [{name: "foo"}]}`), 0600)
+
+		seeded, err := SeedDatabase(ctx, config, "/fixtures.ix")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"users"}, seeded)
+
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ldb.Close(ctx)
+
+		users := utils.Must(ldb.LoadTopLevelEntities(ctx))["users"].(*setcoll.Set)
+		key := core.MustElementKeyFrom(core.MakeStableFixtureULID("users", 0).String())
+		_, err = users.GetElementByKey(ctx, key)
+		assert.NoError(t, err)
+	})
+
+	t.Run("fixtures not matching the schema", func(t *testing.T) {
+		ctx, config := setup(t)
+		if !createSchema(t, ctx, config) {
+			return
+		}
+
+		util.WriteFile(ctx.GetFileSystem(), "/fixtures.json", []byte(`{"users": [{"name": 1}]}`), 0600)
+
+		_, err := SeedDatabase(ctx, config, "/fixtures.json")
+		assert.ErrorIs(t, err, core.ErrInvalidDatabaseFixtures)
+	})
+
+	t.Run("database without schema", func(t *testing.T) {
+		ctx, config := setup(t)
+
+		util.WriteFile(ctx.GetFileSystem(), "/fixtures.json", []byte(`{"users": []}`), 0600)
+
+		_, err := SeedDatabase(ctx, config, "/fixtures.json")
+		assert.ErrorIs(t, err, ErrCannotSeedDatabaseWithoutSchema)
+	})
+}
+
+func TestConvertDatabase(t *testing.T) {
+	const HOST = core.Host("ldb://main")
+
+	namedObjectPattern := core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+		{
+			Name:    "name",
+			Pattern: core.STR_PATTERN,
+		},
+	})
+
+	setPattern :=
+		utils.Must(setcoll.SET_PATTERN.CallImpl(
+			setcoll.SET_PATTERN,
+			[]core.Serializable{namedObjectPattern, common.URL_UNIQUENESS_IDENT}),
+		)
+
+	statsPattern := core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+		{
+			Name:    "count",
+			Pattern: core.INT_PATTERN,
+		},
+		{
+			Name:    "home",
+			Pattern: core.URL_PATTERN,
+		},
+	})
+
+	schema := core.NewInexactObjectPattern([]core.ObjectPatternEntry{
+		{Name: "users", Pattern: setPattern},
+		{Name: "stats", Pattern: statsPattern},
+	})
+
+	setup := func(t *testing.T, format core.SerializationFormat) (*core.Context, LocalDatabaseConfig, bool) {
+		tempdir := t.TempDir()
+		fls := fs_ns.NewMemFilesystem(MEM_FS_STORAGE_SIZE)
+
+		ctx := core.NewContexWithEmptyState(core.ContextConfig{
+			Permissions: []core.Permission{
+				core.DatabasePermission{Kind_: permkind.Read, Entity: HOST},
+				core.DatabasePermission{Kind_: permkind.Write, Entity: HOST},
+			},
+			Filesystem: fls,
+		}, nil)
+		t.Cleanup(func() { ctx.CancelGracefully() })
+
+		config := LocalDatabaseConfig{
+			Host:    HOST,
+			OsFsDir: core.DirPathFrom(filepath.Join(tempdir, "data")),
+			Format:  format,
+		}
+
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return nil, config, false
+		}
+		ldb.UpdateSchema(ctx, schema, core.MigrationOpHandlers{
+			Inclusions: map[core.PathPattern]*core.MigrationOpHandler{
+				"/users": {
+					InitialValue: core.NewWrappedValueList(),
+				},
+				"/stats": {
+					InitialValue: core.NewObjectFromMapNoInit(core.ValMap{"count": core.Int(3), "home": core.URL("https://example.com/")}),
+				},
+			},
+		})
+		if !assert.NoError(t, ldb.Close(ctx)) {
+			return nil, config, false
+		}
+
+		util.WriteFile(ctx.GetFileSystem(), "/fixtures.json", []byte(`{"users": [{"name": "foo"}, {"name": "bar"}]}`), 0600)
+
+		_, err = SeedDatabase(ctx, config, "/fixtures.json")
+		return ctx, config, assert.NoError(t, err)
+	}
+
+	checkContent := func(t *testing.T, ctx *core.Context, config LocalDatabaseConfig, expectedFormat core.SerializationFormat) {
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ldb.Close(ctx)
+
+		assert.Equal(t, expectedFormat, ldb.SerializationFormat())
+
+		serializedUsers, _ := ldb.GetSerialized(ctx, "/users")
+		assert.Equal(t, expectedFormat == core.JSON_SERIALIZATION_FORMAT, serializedUsers[0] == '[')
+
+		entities := utils.Must(ldb.LoadTopLevelEntities(ctx))
+
+		users := entities["users"].(*setcoll.Set)
+		for i, name := range []string{"foo", "bar"} {
+			key := core.MustElementKeyFrom(core.MakeStableFixtureULID("users", i).String())
+			user, err := users.GetElementByKey(ctx, key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, core.String(name), user.(*core.Object).Prop(ctx, "name"))
+		}
+
+		stats := entities["stats"].(*core.Object)
+		assert.Equal(t, core.Int(3), stats.Prop(ctx, "count"))
+		assert.Equal(t, core.URL("https://example.com/"), stats.Prop(ctx, "home"))
+	}
+
+	t.Run("JSON -> binary -> JSON", func(t *testing.T) {
+		ctx, config, ok := setup(t, core.JSON_SERIALIZATION_FORMAT)
+		if !ok {
+			return
+		}
+		checkContent(t, ctx, config, core.JSON_SERIALIZATION_FORMAT)
+
+		prevFormat, err := ConvertDatabase(ctx, config, core.BINARY_SERIALIZATION_FORMAT)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, core.JSON_SERIALIZATION_FORMAT, prevFormat)
+		checkContent(t, ctx, config, core.BINARY_SERIALIZATION_FORMAT)
+
+		prevFormat, err = ConvertDatabase(ctx, config, core.JSON_SERIALIZATION_FORMAT)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, core.BINARY_SERIALIZATION_FORMAT, prevFormat)
+		checkContent(t, ctx, config, core.JSON_SERIALIZATION_FORMAT)
+	})
+
+	t.Run("new database using the binary format", func(t *testing.T) {
+		ctx, config, ok := setup(t, core.BINARY_SERIALIZATION_FORMAT)
+		if !ok {
+			return
+		}
+
+		//the format of an existing database should not depend on the configuration.
+		config.Format = core.JSON_SERIALIZATION_FORMAT
+		checkContent(t, ctx, config, core.BINARY_SERIALIZATION_FORMAT)
+	})
+
+	t.Run("a value that cannot be converted should leave the database untouched", func(t *testing.T) {
+		ctx, config, ok := setup(t, core.JSON_SERIALIZATION_FORMAT)
+		if !ok {
+			return
+		}
+
+		ldb, err := openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		serializedStats, _ := ldb.GetSerialized(ctx, "/stats")
+		ldb.SetSerialized(ctx, "/stats", `{"count": "3"}`)
+		if !assert.NoError(t, ldb.Close(ctx)) {
+			return
+		}
+
+		_, err = ConvertDatabase(ctx, config, core.BINARY_SERIALIZATION_FORMAT)
+		if !assert.Error(t, err) {
+			return
+		}
+
+		ldb, err = openLocalDatabaseWithConfig(ctx, config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		ldb.SetSerialized(ctx, "/stats", serializedStats)
+		if !assert.NoError(t, ldb.Close(ctx)) {
+			return
+		}
+
+		checkContent(t, ctx, config, core.JSON_SERIALIZATION_FORMAT)
+	})
+
+	t.Run("converting to the current format should do nothing", func(t *testing.T) {
+		ctx, config, ok := setup(t, core.JSON_SERIALIZATION_FORMAT)
+		if !ok {
+			return
+		}
+
+		prevFormat, err := ConvertDatabase(ctx, config, core.JSON_SERIALIZATION_FORMAT)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, core.JSON_SERIALIZATION_FORMAT, prevFormat)
+		checkContent(t, ctx, config, core.JSON_SERIALIZATION_FORMAT)
+	})
 }
@@ -0,0 +1,55 @@
+package localdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/inoxlang/inox/internal/core"
+)
+
+var (
+	ErrCannotSeedDatabaseWithoutSchema = errors.New("the database has no schema, the program using it should be executed at least once")
+)
+
+// SeedDatabase writes the fixtures located at fixturesPath (see core.ReadDatabaseFixtures) into an existing local database,
+// the fixtures are validated against the current schema of the database. The top-level entities initialized by the
+// fixtures are overwritten, the other top-level entities are left untouched. The names of the seeded top-level entities
+// are returned.
+func SeedDatabase(ctx *core.Context, config LocalDatabaseConfig, fixturesPath core.Path) (_ []string, finalErr error) {
+	if config.Restricted {
+		return nil, errors.New("a database opened in restricted mode cannot be seeded")
+	}
+
+	db, err := openLocalDatabaseWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		closeErr := db.Close(ctx)
+		if finalErr == nil && closeErr != nil {
+			finalErr = closeErr
+		}
+	}()
+
+	schema := db.Schema()
+	if schema.EntryCount() == 0 {
+		return nil, ErrCannotSeedDatabaseWithoutSchema
+	}
+
+	fixtures, err := core.ReadDatabaseFixtures(ctx, fixturesPath, schema, db.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fixtures.Seed(ctx, db); err != nil {
+		return nil, err
+	}
+
+	//make sure the seeded database can be loaded.
+	if _, err := db.LoadTopLevelEntities(ctx); err != nil {
+		return nil, fmt.Errorf("the seeded database cannot be loaded: %w", err)
+	}
+
+	return fixtures.EntityNames(), nil
+}
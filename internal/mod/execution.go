@@ -50,6 +50,10 @@ type RunLocalModuleArgs struct {
 	UseBytecode      bool
 	OptimizeBytecode bool
 	ShowBytecode     bool
+	//optimization passes that should not be applied, ignored if .OptimizeBytecode is false.
+	DisabledOptimizationPasses []core.BytecodeOptimizationPass
+	//if set and .UseBytecode is true the bytecode of the module is loaded from the cache when possible.
+	BytecodeCache *core.BytecodeCache
 
 	AllowMissingEnvVars bool
 	IgnoreHighRiskScore bool
@@ -57,7 +61,11 @@ type RunLocalModuleArgs struct {
 	EnableTesting bool
 	TestFilters   core.TestFilters
 
-	//if not nil AND UseBytecode is false the script is executed in debug mode with this debugger.
+	EnableBenchmarking bool
+	BenchmarkBaseline  *core.BenchmarkBaseline //can be nil
+	UpdateGoldenFiles  bool
+
+	//if not nil the script is executed in debug mode with this debugger.
 	//Debugger.AttachAndStart is called before starting the evaluation.
 	//if nil the parent state's debugger is used if present.
 	Debugger *core.Debugger
@@ -111,6 +119,12 @@ func RunLocalModule(args RunLocalModuleArgs) (
 
 		EnableTesting: args.EnableTesting,
 		TestFilters:   args.TestFilters,
+
+		EnableBenchmarking: args.EnableBenchmarking,
+		BenchmarkBaseline:  args.BenchmarkBaseline,
+		UpdateGoldenFiles:  args.UpdateGoldenFiles,
+
+		BytecodeCache: utils.If(args.UseBytecode && !args.ShowBytecode, args.BytecodeCache, nil),
 	})
 
 	if args.PreparedChan != nil {
@@ -141,6 +155,8 @@ func RunLocalModule(args RunLocalModuleArgs) (
 		OptimizeBytecode: args.OptimizeBytecode,
 		ShowBytecode:     args.ShowBytecode,
 
+		DisabledOptimizationPasses: args.DisabledOptimizationPasses,
+
 		Debugger: args.Debugger,
 	})
 }
@@ -158,6 +174,8 @@ type RunPreparedModuleArgs struct {
 	ShowBytecode            bool
 	DoNotCancelWhenFinished bool
 
+	DisabledOptimizationPasses []core.BytecodeOptimizationPass
+
 	Debugger *core.Debugger
 }
 
@@ -236,6 +254,22 @@ func RunPreparedModule(args RunPreparedModuleArgs) (
 
 	//execute the script
 
+	debugger := args.Debugger
+	if debugger == nil && args.ParentContext != nil {
+		closestState := args.ParentContext.GetClosestState()
+		parentDebugger, _ := closestState.Debugger.Load().(*core.Debugger)
+		if parentDebugger != nil {
+			debugger = parentDebugger.NewChild()
+		}
+	}
+	if debugger != nil {
+		defer func() {
+			go func() {
+				debugger.ControlChan() <- core.DebugCommandCloseDebugger{}
+			}()
+		}()
+	}
+
 	if args.UseBytecode {
 		tracer := io.Discard
 		if args.ShowBytecode {
@@ -246,27 +280,17 @@ func RunPreparedModule(args RunPreparedModuleArgs) (
 			ShowCompilationTrace: args.ShowBytecode,
 			OptimizeBytecode:     args.OptimizeBytecode,
 			CompilationContext:   args.ParsingCompilationContext,
+			Debugger:             debugger,
+
+			DisabledOptimizationPasses: args.DisabledOptimizationPasses,
 		})
 
 		return res, state, mod, true, err
 	}
 
 	treeWalkState := core.NewTreeWalkStateWithGlobal(state)
-	debugger := args.Debugger
-	if debugger == nil && args.ParentContext != nil {
-		closestState := args.ParentContext.GetClosestState()
-		parentDebugger, _ := closestState.Debugger.Load().(*core.Debugger)
-		if parentDebugger != nil {
-			debugger = parentDebugger.NewChild()
-		}
-	}
 	if debugger != nil {
 		debugger.AttachAndStart(treeWalkState)
-		defer func() {
-			go func() {
-				debugger.ControlChan() <- core.DebugCommandCloseDebugger{}
-			}()
-		}()
 	}
 
 	res, err := core.TreeWalkEval(state.Module.MainChunk.Node, treeWalkState)
@@ -143,6 +143,7 @@ func IsScopeContainerNode(node Node) bool {
 	switch node.(type) {
 	case *Chunk, *EmbeddedModule, *FunctionExpression, *FunctionPatternExpression, *LazyExpression,
 		*InitializationBlock, *MappingExpression, *StaticMappingEntry, *DynamicMappingEntry, *TestSuiteExpression, *TestCaseExpression,
+		*BenchmarkExpression,
 		*ExtendStatement,       //ExtendStatement being a scope container is not 100% incorrect
 		*StructDefinition,      //same
 		*LifetimejobExpression: // <-- remove ?
@@ -1166,6 +1167,9 @@ type PatternIdentifierLiteral struct {
 	NodeBase
 	Unprefixed bool
 	Name       string
+
+	//set if the identifier refers to a type parameter of an enclosing generic function or pattern definition.
+	TypeParameter *TypeParameter
 }
 
 func (PatternIdentifierLiteral) Kind() NodeKind {
@@ -1719,6 +1723,7 @@ func (RuneRangeExpression) Kind() NodeKind {
 type FunctionExpression struct {
 	NodeBase
 	CaptureList            []Node
+	TypeParameters         []*TypeParameter //nil if the function is not generic
 	Parameters             []*FunctionParameter
 	AdditionalInvalidNodes []Node
 	ReturnType             Node //can be nil
@@ -1772,6 +1777,14 @@ func (FunctionDeclaration) Kind() NodeKind {
 	return Stmt
 }
 
+// A TypeParameter is a type parameter of a generic function or pattern definition,
+// the constraint is a pattern that type arguments should be a subtype of.
+type TypeParameter struct {
+	NodeBase
+	Name       *PatternIdentifierLiteral
+	Constraint Node //can be nil
+}
+
 type FunctionParameter struct {
 	NodeBase
 	Var        *IdentifierLiteral //can be nil
@@ -2078,9 +2091,10 @@ type PipelineStage struct {
 
 type PatternDefinition struct {
 	NodeBase
-	Left   Node //*PatternIdentifierLiteral if valid
-	Right  Node
-	IsLazy bool
+	Left           Node             //*PatternIdentifierLiteral if valid
+	TypeParameters []*TypeParameter //nil if the pattern is not parametrized
+	Right          Node
+	IsLazy         bool
 }
 
 func (d PatternDefinition) PatternName() (string, bool) {
@@ -2224,6 +2238,20 @@ func (e TestCaseExpression) Kind() NodeKind {
 	return Expr
 }
 
+type BenchmarkExpression struct {
+	NodeBase    `json:"base:benchmark-expr"`
+	Meta        Node            `json:"meta,omitempty"`
+	Module      *EmbeddedModule `json:"embeddedModule,omitempty"`
+	IsStatement bool            `json:"isStatement"`
+}
+
+func (e BenchmarkExpression) Kind() NodeKind {
+	if e.IsStatement {
+		return Stmt
+	}
+	return Expr
+}
+
 type LifetimejobExpression struct {
 	NodeBase
 	Meta    Node
@@ -2576,6 +2604,10 @@ func walk(node, parent Node, ancestorChain *[]Node, fn, afterFn NodeHandler) {
 			walk(e, node, ancestorChain, fn, afterFn)
 		}
 
+		for _, p := range n.TypeParameters {
+			walk(p, node, ancestorChain, fn, afterFn)
+		}
+
 		for _, p := range n.Parameters {
 			walk(p, node, ancestorChain, fn, afterFn)
 		}
@@ -2600,6 +2632,9 @@ func walk(node, parent Node, ancestorChain *[]Node, fn, afterFn NodeHandler) {
 	case *FunctionParameter:
 		walk(n.Var, node, ancestorChain, fn, afterFn)
 		walk(n.Type, node, ancestorChain, fn, afterFn)
+	case *TypeParameter:
+		walk(n.Name, node, ancestorChain, fn, afterFn)
+		walk(n.Constraint, node, ancestorChain, fn, afterFn)
 	case *StructDefinition:
 		walk(n.Name, node, ancestorChain, fn, afterFn)
 		walk(n.Body, node, ancestorChain, fn, afterFn)
@@ -2899,6 +2934,9 @@ func walk(node, parent Node, ancestorChain *[]Node, fn, afterFn NodeHandler) {
 		}
 	case *PatternDefinition:
 		walk(n.Left, node, ancestorChain, fn, afterFn)
+		for _, p := range n.TypeParameters {
+			walk(p, node, ancestorChain, fn, afterFn)
+		}
 		walk(n.Right, node, ancestorChain, fn, afterFn)
 	case *PatternNamespaceDefinition:
 		walk(n.Left, node, ancestorChain, fn, afterFn)
@@ -2937,6 +2975,9 @@ func walk(node, parent Node, ancestorChain *[]Node, fn, afterFn NodeHandler) {
 	case *TestCaseExpression:
 		walk(n.Meta, node, ancestorChain, fn, afterFn)
 		walk(n.Module, node, ancestorChain, fn, afterFn)
+	case *BenchmarkExpression:
+		walk(n.Meta, node, ancestorChain, fn, afterFn)
+		walk(n.Module, node, ancestorChain, fn, afterFn)
 	case *LifetimejobExpression:
 		walk(n.Meta, node, ancestorChain, fn, afterFn)
 		walk(n.Subject, node, ancestorChain, fn, afterFn)
@@ -0,0 +1,344 @@
+package parse
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+)
+
+const (
+	//Minimum distance in runes between an edit and the reused statements. Some error messages
+	//contain the runes around the location of the error.
+	INCREMENTAL_PARSING_MARGIN = 10
+)
+
+var (
+	nodeInterfaceType = reflect.TypeOf((*Node)(nil)).Elem()
+)
+
+// A SourceEdit is the replacement of the runes in the range [Start, End) of a chunk's code.
+type SourceEdit struct {
+	Start       int32 //rune index in the code before the edit
+	End         int32 //exclusive rune index in the code before the edit
+	Replacement string
+}
+
+// GetSourceEdit returns the smallest edit that transforms before into after.
+func GetSourceEdit(before, after []rune) SourceEdit {
+	commonPrefixLen := 0
+	for commonPrefixLen < len(before) && commonPrefixLen < len(after) && before[commonPrefixLen] == after[commonPrefixLen] {
+		commonPrefixLen++
+	}
+
+	commonSuffixLen := 0
+	for commonSuffixLen < len(before)-commonPrefixLen &&
+		commonSuffixLen < len(after)-commonPrefixLen &&
+		before[len(before)-1-commonSuffixLen] == after[len(after)-1-commonSuffixLen] {
+		commonSuffixLen++
+	}
+
+	return SourceEdit{
+		Start:       int32(commonPrefixLen),
+		End:         int32(len(before) - commonSuffixLen),
+		Replacement: string(after[commonPrefixLen : len(after)-commonSuffixLen]),
+	}
+}
+
+// incrementalParsingData is the data recorded during the parsing of a chunk in order to incrementally parse
+// the next versions of the chunk.
+type incrementalParsingData struct {
+	stmtStates []topLevelStmtParsingState //states of the parser before each top level statement
+	tokens     []Token                    //tokens in the order they were created (not sorted)
+}
+
+// ParseChunkSourceIncrementally parses src, whose code is the result of applying edit to the code of prev.
+// The top level statements of prev that are not affected by the edit are reused: statements before the edit
+// are shared with prev and statements after the edit are copied and shifted. The result is identical to the
+// result of ParseChunkSource(src), the function falls back to ParseChunkSource if the edit cannot be handled
+// incrementally (e.g. an edit in the manifest, embedded modules). prev is never modified.
+func ParseChunkSourceIncrementally(prev *ParsedChunkSource, src ChunkSource, edit SourceEdit) (*ParsedChunkSource, error) {
+	var (
+		runes []rune
+		chunk *Chunk
+		data  *incrementalParsingData
+	)
+
+	if prev != nil && edit.Start == edit.End && edit.Replacement == "" && prev.Source.Code() == src.Code() {
+		//the code has not changed.
+		runes = prev.Runes()
+		chunk = prev.Node
+		data = prev.incrementalParsingData
+	} else {
+		runes = []rune(src.Code())
+
+		if int32(len(src.Code())) > MAX_MODULE_BYTE_LEN || !isIncrementalParsingPossible(prev, runes, edit) {
+			return ParseChunkSource(src)
+		}
+
+		var ok bool
+		chunk, data, ok = parseChunkIncrementally(prev, runes, edit)
+		if !ok {
+			return ParseChunkSource(src)
+		}
+	}
+
+	var resultErr error
+	if aggregation := aggregateParsingErrors(chunk, runes, src.Name()); aggregation != nil {
+		resultErr = aggregation
+	}
+
+	return &ParsedChunkSource{
+		Node:                   chunk,
+		Source:                 src,
+		runes:                  runes,
+		incrementalParsingData: data,
+	}, resultErr
+}
+
+func isIncrementalParsingPossible(prev *ParsedChunkSource, runes []rune, edit SourceEdit) bool {
+	if prev == nil || prev.incrementalParsingData == nil || len(prev.incrementalParsingData.stmtStates) != len(prev.Node.Statements) {
+		return false
+	}
+
+	prevRunes := prev.Runes()
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len32(prevRunes) {
+		return false
+	}
+
+	//check that the edit is consistent with the code of the two versions.
+	replacement := []rune(edit.Replacement)
+	if len(runes) != len(prevRunes)-int(edit.End-edit.Start)+len(replacement) ||
+		!slices.Equal(runes[:edit.Start], prevRunes[:edit.Start]) ||
+		!slices.Equal(runes[edit.Start:edit.Start+len32(replacement)], replacement) ||
+		!slices.Equal(runes[edit.Start+len32(replacement):], prevRunes[edit.End:]) {
+		return false
+	}
+
+	//the tokens of embedded modules are subslices of the chunk's tokens,
+	//they cannot be reused.
+	return !containsEmbeddedModule(prev.Node)
+}
+
+// parseChunkIncrementally parses the new version of prev's code, ok is false if the incremental parsing failed.
+func parseChunkIncrementally(prev *ParsedChunkSource, runes []rune, edit SourceEdit) (chunk *Chunk, data *incrementalParsingData, ok bool) {
+	prevChunk := prev.Node
+	prevStmts := prevChunk.Statements
+	prevStates := prev.incrementalParsingData.stmtStates
+	prevTokens := prev.incrementalParsingData.tokens
+
+	//number of statements ending before the edit.
+	stmtCountBeforeEdit := 0
+	for stmtCountBeforeEdit < len(prevStmts) && prevStmts[stmtCountBeforeEdit].Base().Span.End+INCREMENTAL_PARSING_MARGIN < edit.Start {
+		stmtCountBeforeEdit++
+	}
+
+	if stmtCountBeforeEdit == 0 {
+		//the start of the chunk (manifest, ...) may be affected.
+		return nil, nil, false
+	}
+
+	//The last statement before the edit is parsed again because the parser may have looked ahead.
+	firstReparsedStmtIndex := stmtCountBeforeEdit - 1
+	reparsingState := prevStates[firstReparsedStmtIndex]
+	offset := len32(runes) - len32(prev.Runes())
+
+	defer func() {
+		if e := recover(); e != nil {
+			//the parser panics if it times out.
+			ok = false
+		}
+	}()
+
+	p := newParser(runes)
+	defer p.cancel()
+
+	p.i = reparsingState.start
+	p.tokens = append(p.tokens, prevTokens[:reparsingState.tokenCount]...)
+	p.incrementalParsingData = &incrementalParsingData{
+		stmtStates: slices.Clone(prevStates[:firstReparsedStmtIndex]),
+	}
+
+	reusedStmtIndex := -1
+
+	stmts := p.parseTopLevelStatements(slices.Clone(prevStmts[:firstReparsedStmtIndex]), reparsingState, func(state topLevelStmtParsingState) bool {
+		prevStart := state.start - offset
+
+		//search for a previous statement starting at the same position.
+		index, found := sort.Find(len(prevStates), func(i int) int {
+			return int(prevStart - prevStates[i].start)
+		})
+
+		if !found || index == 0 || prevStates[index-1].start < edit.End || prevStart-INCREMENTAL_PARSING_MARGIN < edit.End {
+			return false
+		}
+
+		//the previous statement can be reused if the parser is in the same state.
+		prevState := prevStates[index]
+		if prevState.prevStmtErrKind != state.prevStmtErrKind || prevState.prevStmtEndIndex+offset != state.prevStmtEndIndex {
+			return false
+		}
+
+		reusedStmtIndex = index
+		return true
+	})
+
+	for _, stmt := range stmts[firstReparsedStmtIndex:] {
+		if containsEmbeddedModule(stmt) {
+			return nil, nil, false
+		}
+	}
+
+	data = p.incrementalParsingData
+
+	//copy and shift the reused statements after the edit.
+	if reusedStmtIndex >= 0 {
+		reusedStmtsState := prevStates[reusedStmtIndex]
+		tokenCountOffset := len32(p.tokens) - reusedStmtsState.tokenCount
+
+		//the same cloner is used for all statements because a node may be referenced by several statements.
+		cloner := newNodeCloner(offset)
+		for _, stmt := range prevStmts[reusedStmtIndex:] {
+			stmts = append(stmts, cloner.cloneNodeAndShiftSpans(stmt))
+		}
+
+		for _, state := range prevStates[reusedStmtIndex:] {
+			state.start += offset
+			state.tokenCount += tokenCountOffset
+			state.prevStmtEndIndex += offset
+			data.stmtStates = append(data.stmtStates, state)
+		}
+
+		for _, token := range prevTokens[reusedStmtsState.tokenCount:] {
+			token.Span = shiftSpan(token.Span, offset)
+			p.tokens = append(p.tokens, token)
+		}
+	}
+
+	data.tokens = slices.Clone(p.tokens)
+
+	slices.SortFunc(p.tokens, func(a, b Token) int {
+		return int(a.Span.Start) - int(b.Span.Start)
+	})
+
+	chunk = &Chunk{
+		NodeBase: NodeBase{
+			Span: NodeSpan{Start: 0, End: len32(runes)},
+		},
+		GlobalConstantDeclarations: prevChunk.GlobalConstantDeclarations,
+		Preinit:                    prevChunk.Preinit,
+		Manifest:                   prevChunk.Manifest,
+		IncludableChunkDesc:        prevChunk.IncludableChunkDesc,
+		Statements:                 stmts,
+		Tokens:                     p.tokens,
+	}
+
+	return chunk, data, true
+}
+
+func containsEmbeddedModule(node Node) bool {
+	found := false
+
+	Walk(node, func(node, parent, scopeNode Node, ancestorChain []Node, after bool) (TraversalAction, error) {
+		if _, ok := node.(*EmbeddedModule); ok {
+			found = true
+			return StopTraversal, nil
+		}
+		return ContinueTraversal, nil
+	}, nil)
+
+	return found
+}
+
+// A nodeCloner deeply copies nodes and shifts their spans by an offset. A node referenced several times (e.g. a
+// *TypeParameter referenced by the .TypeParameter field of pattern identifiers) is copied once, so the copies
+// reference the same node.
+type nodeCloner struct {
+	offset int32
+	copies map[Node]reflect.Value //original node -> copy
+}
+
+func newNodeCloner(offset int32) *nodeCloner {
+	return &nodeCloner{
+		offset: offset,
+		copies: map[Node]reflect.Value{},
+	}
+}
+
+// cloneNodeAndShiftSpans returns a deep copy of node whose spans are shifted by the offset. shiftNodeSpans is not used
+// because the walk function does not visit all nodes.
+func (c *nodeCloner) cloneNodeAndShiftSpans(node Node) Node {
+	return c.cloneValue(reflect.ValueOf(node)).Interface().(Node)
+}
+
+// cloneValue deeply copies a value found in an AST and shifts the spans of the copied nodes. Pointers to values
+// that are not nodes (e.g. *ParsingError) are not copied because they are never modified.
+func (c *nodeCloner) cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct || !v.Type().Implements(nodeInterfaceType) {
+			return v
+		}
+		node := v.Interface().(Node)
+		if copy, ok := c.copies[node]; ok {
+			return copy
+		}
+
+		copy := reflect.New(v.Type().Elem())
+		c.copies[node] = copy
+		copy.Elem().Set(v.Elem())
+		c.cloneStructFields(copy.Elem())
+
+		base := copy.Interface().(Node).BasePtr()
+		base.Span = shiftSpan(base.Span, c.offset)
+		return copy
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copy := reflect.New(v.Type()).Elem()
+		copy.Set(c.cloneValue(v.Elem()))
+		return copy
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copy := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copy.Index(i).Set(c.cloneValue(v.Index(i)))
+		}
+		return copy
+	case reflect.Struct:
+		copy := reflect.New(v.Type()).Elem()
+		copy.Set(v)
+		c.cloneStructFields(copy)
+		return copy
+	default:
+		return v
+	}
+}
+
+func (c *nodeCloner) cloneStructFields(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Struct:
+			field.Set(c.cloneValue(field))
+		}
+	}
+}
+
+// shiftSpan shifts the positions of a span that is located after an edit. Positions that are not set (zero values)
+// are not shifted, some nodes and tokens are created without a position or without an end position.
+func shiftSpan(span NodeSpan, offset int32) NodeSpan {
+	if span.Start > 0 {
+		span.Start += offset
+	}
+	if span.End > 0 {
+		span.End += offset
+	}
+	return span
+}
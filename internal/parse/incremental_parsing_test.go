@@ -0,0 +1,218 @@
+package parse
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var incrementalParsingTestCodes = []string{
+	"a = 1\nb = 2\nc = 3\n",
+	"manifest {}\n\nfn f(a int){\n\treturn a\n}\n\nif f(1) {\n\tprint(1)\n} else {\n\tprint(2)\n}\n\nx = [1, 2, 3]\n",
+	"const (\n\tA = 1\n)\n\n# comment\nfn g() {\n\tfor i, e in [1, 2] {\n\t\tassign a b = Array(1, 2)\n\t}\n}\n\nobj = {a: 1, b: \"s\"}\nprint(obj.a)\n",
+	"pattern user = {\n\tname: str\n}\n\nvar u user = {name: \"foo\"}\nmatch u {\n\t%user {\n\t\tprint(u)\n\t}\n\tdefaultcase {}\n}\n",
+	"a = 1\n\nfn first<T>(list []T) T {\n\treturn list[0]\n}\n\npattern pair<T> = [T, T]\nb = first([1])\n",
+	"a = 1;b = 2;c = 3\nswitch a {\n\t1 { }\n\t2 { }\n}\n$x = /a/b\n$$y = https://example.com/\n",
+}
+
+var incrementalParsingTestReplacements = []string{
+	"", "", " ", "\n", "a", "1", "{", "}", "(", ")", "[", "]", "if", "else", "#", "\"", "fn", ";", ",", "=", "%", "$", ".", "é",
+	"\nx = 1\n", "fn(){}", "go do {}", " # comment\n",
+}
+
+func TestIncrementalParsing(t *testing.T) {
+
+	parse := func(code string) *ParsedChunkSource {
+		chunk, _ := ParseChunkSource(InMemorySource{NameString: "test", CodeString: code})
+		return chunk
+	}
+
+	t.Run("statements before and after the edit should be reused", func(t *testing.T) {
+		prevCode := "first = 1\nsecond = 2\nthird = 3\nfourth = 4\nfifth = 5\nsixth = 6\nseventh = 7\n"
+		prev := parse(prevCode)
+
+		code := "first = 1\nsecond = 2\nthird = 3\nfourth = 40\nfifth = 5\nsixth = 6\nseventh = 7\n"
+		edit := GetSourceEdit([]rune(prevCode), []rune(code))
+		assert.Equal(t, SourceEdit{Start: 41, End: 41, Replacement: "0"}, edit)
+
+		chunk, err := ParseChunkSourceIncrementally(prev, InMemorySource{NameString: "test", CodeString: code}, edit)
+
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, parse(code).Node, chunk.Node)
+
+		//statements before the edit are shared.
+		assert.Same(t, prev.Node.Statements[0], chunk.Node.Statements[0])
+
+		//statements after the edit are copied.
+		assert.NotSame(t, prev.Node.Statements[6], chunk.Node.Statements[6])
+		seventhStart := int32(strings.Index(code, "seventh"))
+		assert.Equal(t, NodeSpan{Start: seventhStart, End: seventhStart + 11}, chunk.Node.Statements[6].Base().Span)
+
+		//the previous chunk should not be modified.
+		assert.Equal(t, parse(prevCode).Node, prev.Node)
+	})
+
+	t.Run("references to type parameters in reused statements should be preserved", func(t *testing.T) {
+		prevCode := "first = 1\nsecond = 2\nthird = 3\nfourth = 4\nfn first<T>(list []T) T {\n\treturn list[0]\n}\n"
+		prev := parse(prevCode)
+
+		code := "first = 1\nsecond = 20\nthird = 3\nfourth = 4\nfn first<T>(list []T) T {\n\treturn list[0]\n}\n"
+		chunk, err := ParseChunkSourceIncrementally(prev, InMemorySource{NameString: "test", CodeString: code},
+			GetSourceEdit([]rune(prevCode), []rune(code)))
+
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		//the function declaration is a copy of the previous one.
+		fn := chunk.Node.Statements[4].(*FunctionDeclaration).Function
+		assert.NotSame(t, prev.Node.Statements[4], chunk.Node.Statements[4])
+
+		typeParam := fn.TypeParameters[0]
+		assert.Same(t, typeParam, fn.ReturnType.(*PatternIdentifierLiteral).TypeParameter)
+		listPattern := fn.Parameters[0].Type.(*ListPatternLiteral)
+		assert.Same(t, typeParam, listPattern.GeneralElement.(*PatternIdentifierLiteral).TypeParameter)
+	})
+
+	t.Run("unchanged code", func(t *testing.T) {
+		prev := parse("a = 1\nb = (\n")
+
+		chunk, err := ParseChunkSourceIncrementally(prev, InMemorySource{NameString: "test", CodeString: "a = 1\nb = (\n"}, SourceEdit{})
+		assert.Same(t, prev.Node, chunk.Node)
+		assert.Error(t, err)
+	})
+
+	t.Run("edit with parsing errors", func(t *testing.T) {
+		prev := parse("a = 1\nb = 2\nc = 3\n")
+
+		code := "a = 1\nb = (\nc = 3\n"
+		chunk, err := ParseChunkSourceIncrementally(prev, InMemorySource{NameString: "test", CodeString: code},
+			GetSourceEdit([]rune(prev.Source.Code()), []rune(code)))
+
+		expectedChunk, expectedErr := ParseChunkSource(InMemorySource{NameString: "test", CodeString: code})
+
+		assert.Equal(t, expectedChunk.Node, chunk.Node)
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("random edits", func(t *testing.T) {
+		random := rand.New(rand.NewSource(0))
+
+		for _, code := range incrementalParsingTestCodes {
+			var prev *ParsedChunkSource
+			var runes []rune
+
+			for i := 0; i < 300; i++ {
+				//the edits are applied to the original code after every 10 edits.
+				if i%10 == 0 {
+					prev = parse(code)
+					runes = []rune(code)
+				}
+
+				start := random.Intn(len(runes) + 1)
+				end := min(len(runes), start+random.Intn(4))
+				replacement := incrementalParsingTestReplacements[random.Intn(len(incrementalParsingTestReplacements))]
+
+				newRunes := append(append(append([]rune{}, runes[:start]...), []rune(replacement)...), runes[end:]...)
+
+				if !checkIncrementalParsing(t, prev, string(newRunes), SourceEdit{
+					Start:       int32(start),
+					End:         int32(end),
+					Replacement: replacement,
+				}) {
+					return
+				}
+
+				//the next edit is applied to the result of the incremental parsing.
+				prev, _ = ParseChunkSourceIncrementally(prev, InMemorySource{NameString: "test", CodeString: string(newRunes)},
+					SourceEdit{Start: int32(start), End: int32(end), Replacement: replacement})
+				runes = newRunes
+			}
+		}
+	})
+}
+
+func FuzzIncrementalParsing(f *testing.F) {
+	for _, code := range incrementalParsingTestCodes {
+		f.Add(code, 0, 0, "")
+		f.Add(code, len(code)/2, len(code)/2+1, "{")
+		f.Add(code, len(code)-3, len(code)-1, "\n1")
+	}
+
+	f.Fuzz(func(t *testing.T, code string, start, end int, replacement string) {
+		runes := []rune(code)
+		if start < 0 || start > end || end > len(runes) {
+			return
+		}
+
+		prev, _ := ParseChunkSource(InMemorySource{NameString: "test", CodeString: code})
+		if prev == nil {
+			return
+		}
+
+		newRunes := append(append(append([]rune{}, runes[:start]...), []rune(replacement)...), runes[end:]...)
+
+		checkIncrementalParsing(t, prev, string(newRunes), SourceEdit{
+			Start:       int32(start),
+			End:         int32(end),
+			Replacement: replacement,
+		})
+	})
+}
+
+// checkIncrementalParsing checks that the incremental parsing of code gives the same result as a full parsing.
+func checkIncrementalParsing(t *testing.T, prev *ParsedChunkSource, code string, edit SourceEdit) bool {
+	src := InMemorySource{NameString: "test", CodeString: code}
+
+	chunk, err := ParseChunkSourceIncrementally(prev, src, edit)
+	expectedChunk, expectedErr := ParseChunkSource(src)
+
+	if expectedChunk == nil {
+		return assert.Nil(t, chunk)
+	}
+
+	if !reflect.DeepEqual(expectedChunk.Node, chunk.Node) {
+		return assert.Equal(t, expectedChunk.Node, chunk.Node, "previous code:\n%s\nnew code:\n%s", prev.Source.Code(), code)
+	}
+
+	if !checkTypeParameterReferences(t, chunk.Node) {
+		return false
+	}
+
+	if _, ok := expectedErr.(*ParsingErrorAggregation); ok || expectedErr == nil {
+		return assert.Equal(t, expectedErr, err, "previous code:\n%s\nnew code:\n%s", prev.Source.Code(), code)
+	}
+	return true
+}
+
+// checkTypeParameterReferences checks that the type parameters referenced by the pattern identifiers are nodes of
+// the chunk, reflect.DeepEqual does not check pointer identity.
+func checkTypeParameterReferences(t *testing.T, chunk *Chunk) bool {
+	typeParams := map[*TypeParameter]bool{}
+	var references []*PatternIdentifierLiteral
+
+	Walk(chunk, func(node, parent, scopeNode Node, ancestorChain []Node, after bool) (TraversalAction, error) {
+		switch n := node.(type) {
+		case *TypeParameter:
+			typeParams[n] = true
+		case *PatternIdentifierLiteral:
+			if n.TypeParameter != nil {
+				references = append(references, n)
+			}
+		}
+		return ContinueTraversal, nil
+	}, nil)
+
+	for _, ident := range references {
+		if !assert.True(t, typeParams[ident.TypeParameter], "%%%s does not reference a type parameter of the chunk", ident.Name) {
+			return false
+		}
+	}
+	return true
+}
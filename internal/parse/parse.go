@@ -7456,6 +7456,50 @@ func (p *parser) parseTestCaseExpression(ident *IdentifierLiteral) *TestCaseExpr
 	}
 }
 
+func (p *parser) parseBenchmarkExpression(ident *IdentifierLiteral) *BenchmarkExpression {
+	p.panicIfContextDone()
+
+	start := ident.Base().Span.Start
+	p.tokens = append(p.tokens, Token{Type: BENCHMARK_KEYWORD, Span: ident.Base().Span})
+
+	p.eatSpace()
+	if p.i >= p.len {
+		return &BenchmarkExpression{
+			NodeBase: NodeBase{
+				Span: NodeSpan{start, p.i},
+				Err:  &ParsingError{MissingBlock, UNTERMINATED_BENCHMARK_EXPRESSION_MISSING_BLOCK},
+			},
+		}
+	}
+
+	var meta Node
+
+	if p.s[p.i] != '{' {
+		meta, _ = p.parseExpression()
+		p.eatSpace()
+	}
+
+	if p.i >= p.len || p.s[p.i] != '{' {
+		return &BenchmarkExpression{
+			NodeBase: NodeBase{
+				Span: NodeSpan{start, p.i},
+				Err:  &ParsingError{UnspecifiedParsingError, UNTERMINATED_BENCHMARK_EXPRESSION_MISSING_BLOCK},
+			},
+			Meta: meta,
+		}
+	}
+
+	emod := p.parseEmbeddedModule()
+
+	return &BenchmarkExpression{
+		NodeBase: NodeBase{
+			Span: NodeSpan{start, p.i},
+		},
+		Meta:   meta,
+		Module: emod,
+	}
+}
+
 func (p *parser) parseLifetimeJobExpression(ident *IdentifierLiteral) *LifetimejobExpression {
 	p.panicIfContextDone()
 
@@ -8250,6 +8294,19 @@ func (p *parser) parseFunction(start int32) Node {
 		}
 	}
 
+	var typeParameters []*TypeParameter
+
+	if p.i < p.len && p.s[p.i] == '<' {
+		var invalidNodes []Node
+		typeParameters, invalidNodes, parsingErr = p.parseTypeParameters()
+		additionalInvalidNodes = append(additionalInvalidNodes, invalidNodes...)
+
+		if parsingErr != nil && parsingErr.Kind == InvalidNext {
+			return createNodeWithError()
+		}
+		p.eatSpace()
+	}
+
 	if p.i >= p.len || p.s[p.i] != '(' {
 		if hasCaptureList && ident == nil {
 			parsingErr = &ParsingError{InvalidNext, CAPTURE_LIST_SHOULD_BE_FOLLOWED_BY_PARAMS}
@@ -8421,6 +8478,7 @@ func (p *parser) parseFunction(start int32) Node {
 			Err:  parsingErr,
 		},
 		CaptureList:            capturedLocals,
+		TypeParameters:         typeParameters,
 		Parameters:             parameters,
 		AdditionalInvalidNodes: additionalInvalidNodes,
 		ReturnType:             returnType,
@@ -8429,6 +8487,10 @@ func (p *parser) parseFunction(start int32) Node {
 		IsBodyExpression:       isBodyExpression,
 	}
 
+	if len(typeParameters) > 0 {
+		resolveTypeParameterReferences(typeParameters, &fn)
+	}
+
 	if ident != nil {
 		fn.Err = nil
 
@@ -8449,6 +8511,119 @@ func (p *parser) parseFunction(start int32) Node {
 	return &fn
 }
 
+// parseTypeParameters parses a type parameter list (e.g. <T, U serializable>), p.i should be at the '<' character.
+// A parsing error with the InvalidNext kind is returned if the list is not terminated.
+func (p *parser) parseTypeParameters() (typeParameters []*TypeParameter, invalidNodes []Node, parsingErr *ParsingError) {
+	p.panicIfContextDone()
+
+	p.tokens = append(p.tokens, Token{Type: LESS_THAN, Span: NodeSpan{p.i, p.i + 1}})
+	p.i++
+
+	prev := p.inPattern
+	p.inPattern = true
+	defer func() {
+		p.inPattern = prev
+	}()
+
+	for p.i < p.len && p.s[p.i] != '>' {
+		p.eatSpaceNewlineComma()
+
+		if p.i >= p.len || p.s[p.i] == '>' {
+			break
+		}
+
+		name, isMissingExpr := p.parseExpression()
+
+		if isMissingExpr {
+			r := p.s[p.i]
+			p.i++
+			p.tokens = append(p.tokens, Token{Type: UNEXPECTED_CHAR, Span: NodeSpan{p.i - 1, p.i}, Raw: string(r)})
+
+			invalidNodes = append(invalidNodes, &UnknownNode{
+				NodeBase: NodeBase{
+					NodeSpan{p.i - 1, p.i},
+					&ParsingError{UnspecifiedParsingError, fmtUnexpectedCharInTypeParameters(r)},
+					false,
+				},
+			})
+			continue
+		}
+
+		ident, ok := name.(*PatternIdentifierLiteral)
+		if !ok {
+			if name.Base().Err == nil {
+				name.BasePtr().Err = &ParsingError{UnspecifiedParsingError, TYPE_PARAM_LIST_SHOULD_CONTAIN_TYPE_PARAMS_SEP_BY_COMMAS}
+			}
+			invalidNodes = append(invalidNodes, name)
+			p.eatSpaceNewlineComma()
+			continue
+		}
+
+		p.eatSpace()
+
+		var constraint Node
+		if p.i < p.len && p.s[p.i] != ',' && p.s[p.i] != '>' && p.s[p.i] != '\n' {
+			constraint, isMissingExpr = p.parseExpression()
+			if isMissingExpr {
+				constraint = nil
+			}
+		}
+
+		span := ident.Span
+		if constraint != nil {
+			span.End = constraint.Base().Span.End
+		}
+
+		typeParameters = append(typeParameters, &TypeParameter{
+			NodeBase:   NodeBase{Span: span},
+			Name:       ident,
+			Constraint: constraint,
+		})
+
+		p.eatSpaceNewlineComma()
+	}
+
+	if p.i >= p.len {
+		parsingErr = &ParsingError{InvalidNext, UNTERMINATED_TYPE_PARAM_LIST_MISSING_CLOSING_ANGLE_BRACKET}
+		return
+	}
+
+	p.tokens = append(p.tokens, Token{Type: GREATER_THAN, Span: NodeSpan{p.i, p.i + 1}})
+	p.i++
+
+	if len(typeParameters) == 0 && len(invalidNodes) == 0 {
+		parsingErr = &ParsingError{UnspecifiedParsingError, EMPTY_TYPE_PARAM_LIST}
+	}
+	return
+}
+
+// resolveTypeParameterReferences sets the .TypeParameter field of the pattern identifiers in $nodes that refer
+// to one of the type parameters. References that are already resolved (to the type parameters of a nested generic
+// function) are left unchanged.
+func resolveTypeParameterReferences(typeParameters []*TypeParameter, nodes ...Node) {
+	for _, node := range nodes {
+		Walk(node, func(node, parent, scopeNode Node, ancestorChain []Node, after bool) (TraversalAction, error) {
+			ident, ok := node.(*PatternIdentifierLiteral)
+			if !ok || ident.TypeParameter != nil {
+				return ContinueTraversal, nil
+			}
+
+			if param, ok := parent.(*TypeParameter); ok && param.Name == ident {
+				//declaration
+				return ContinueTraversal, nil
+			}
+
+			for _, param := range typeParameters {
+				if param.Name.Name == ident.Name {
+					ident.TypeParameter = param
+					break
+				}
+			}
+			return ContinueTraversal, nil
+		}, nil)
+	}
+}
+
 // parseFunctionPattern parses function patterns
 func (p *parser) parseFunctionPattern(start int32, percentPrefixed bool) Node {
 	p.panicIfContextDone()
@@ -9998,6 +10173,22 @@ func (p *parser) parsePatternDefinition(patternIdent *IdentifierLiteral) *Patter
 			}
 		}()
 
+		if p.i < p.len && p.s[p.i] == '<' {
+			typeParameters, invalidNodes, parsingErr := p.parseTypeParameters()
+			patternDef.TypeParameters = typeParameters
+			patternDef.Span.End = p.i
+
+			if parsingErr == nil && len(invalidNodes) > 0 {
+				parsingErr = invalidNodes[0].Base().Err
+			}
+			if parsingErr != nil {
+				patternDef.Err = parsingErr
+				if parsingErr.Kind == InvalidNext {
+					return patternDef
+				}
+			}
+		}
+
 		p.eatSpace()
 
 		if p.i >= p.len || p.s[p.i] != '=' {
@@ -10029,6 +10220,10 @@ func (p *parser) parsePatternDefinition(patternIdent *IdentifierLiteral) *Patter
 
 				patternDef.Right, _ = p.parseExpression()
 				patternDef.Span.End = p.i
+
+				if len(patternDef.TypeParameters) > 0 {
+					resolveTypeParameterReferences(patternDef.TypeParameters, patternDef.Right)
+				}
 			}
 		}
 	}
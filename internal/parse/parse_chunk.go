@@ -25,9 +25,18 @@ func ParseChunk(str string, fpath string, opts ...ParserOptions) (result *Chunk,
 
 // ParseChunk2 has the same behavior as ParseChunk2 but returns the rune slice created for parsing.
 func ParseChunk2(str string, fpath string, opts ...ParserOptions) (runes []rune, result *Chunk, resultErr error) {
+	runes, result, _, resultErr = parseChunk3(str, fpath, false, opts...)
+	return
+}
+
+// parseChunk3 has the same behavior as ParseChunk2 but can also record the data required by the incremental parsing
+// of the next versions of the chunk.
+func parseChunk3(str string, fpath string, recordIncrementalParsingData bool, opts ...ParserOptions) (
+	runes []rune, result *Chunk, data *incrementalParsingData, resultErr error,
+) {
 
 	if int32(len(str)) > MAX_MODULE_BYTE_LEN {
-		return nil, nil, &ParsingError{UnspecifiedParsingError, fmt.Sprintf("module'p.s code is too long (%d bytes)", len(str))}
+		return nil, nil, nil, &ParsingError{UnspecifiedParsingError, fmt.Sprintf("module'p.s code is too long (%d bytes)", len(str))}
 	}
 
 	//check that the passed context is not done.
@@ -36,7 +45,7 @@ func ParseChunk2(str string, fpath string, opts ...ParserOptions) (runes []rune,
 		if ctx != nil {
 			select {
 			case <-ctx.Done():
-				return nil, nil, ctx.Err()
+				return nil, nil, nil, ctx.Err()
 			default:
 			}
 		}
@@ -46,6 +55,10 @@ func ParseChunk2(str string, fpath string, opts ...ParserOptions) (runes []rune,
 	p := newParser(runes, opts...)
 	defer p.cancel()
 
+	if recordIncrementalParsingData {
+		p.incrementalParsingData = &incrementalParsingData{}
+	}
+
 	defer func() {
 		v := recover()
 		if err, ok := v.(error); ok {
@@ -57,77 +70,83 @@ func ParseChunk2(str string, fpath string, opts ...ParserOptions) (runes []rune,
 		}
 
 		if result != nil {
-			//we walk the AST and adds each node'p.s error to resultErr
-
-			var aggregation *ParsingErrorAggregation
-
-			Walk(result, func(node, parent, scopeNode Node, ancestorChain []Node, _ bool) (TraversalAction, error) {
-				if reflect.ValueOf(node).IsNil() {
-					return ContinueTraversal, nil
-				}
-
-				nodeBase := node.Base()
-
-				parsingErr := nodeBase.Err
-				if parsingErr == nil {
-					return ContinueTraversal, nil
-				}
-
-				if aggregation == nil {
-					aggregation = &ParsingErrorAggregation{}
-				}
-
-				//add location in error message
-				line := int32(1)
-				col := int32(1)
-				i := int32(0)
-
-				for i < nodeBase.Span.Start {
-					if p.s[i] == '\n' {
-						line++
-						col = 1
-					} else {
-						col++
-					}
-
-					i++
-				}
-
-				endLine := line
-				endCol := col
-
-				for i < nodeBase.Span.End {
-					if p.s[i] == '\n' {
-						endLine++
-						endCol = 1
-					} else {
-						endCol++
-					}
-					i++
-				}
-
-				aggregation.Errors = append(aggregation.Errors, parsingErr)
-				aggregation.ErrorPositions = append(aggregation.ErrorPositions, SourcePositionRange{
-					SourceName:  fpath,
-					StartLine:   line,
-					StartColumn: col,
-					EndLine:     endLine,
-					EndColumn:   endCol,
-					Span:        nodeBase.Span,
-				})
-
-				aggregation.Message = fmt.Sprintf("%s\n%s:%d:%d: %s", aggregation.Message, fpath, line, col, parsingErr.Message)
+			if aggregation := aggregateParsingErrors(result, p.s, fpath); aggregation != nil {
 				resultErr = aggregation
-				return ContinueTraversal, nil
-			}, nil)
+			}
 		}
-
 	}()
 
 	result, resultErr = p.parseChunk()
+	data = p.incrementalParsingData
 	return
 }
 
+// aggregateParsingErrors walks the AST and aggregates the errors of the nodes, nil is returned if there are no errors.
+func aggregateParsingErrors(chunk *Chunk, runes []rune, fpath string) *ParsingErrorAggregation {
+	var aggregation *ParsingErrorAggregation
+
+	Walk(chunk, func(node, parent, scopeNode Node, ancestorChain []Node, _ bool) (TraversalAction, error) {
+		if reflect.ValueOf(node).IsNil() {
+			return ContinueTraversal, nil
+		}
+
+		nodeBase := node.Base()
+
+		parsingErr := nodeBase.Err
+		if parsingErr == nil {
+			return ContinueTraversal, nil
+		}
+
+		if aggregation == nil {
+			aggregation = &ParsingErrorAggregation{}
+		}
+
+		//add location in error message
+		line := int32(1)
+		col := int32(1)
+		i := int32(0)
+
+		for i < nodeBase.Span.Start {
+			if runes[i] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+
+			i++
+		}
+
+		endLine := line
+		endCol := col
+
+		for i < nodeBase.Span.End {
+			if runes[i] == '\n' {
+				endLine++
+				endCol = 1
+			} else {
+				endCol++
+			}
+			i++
+		}
+
+		aggregation.Errors = append(aggregation.Errors, parsingErr)
+		aggregation.ErrorPositions = append(aggregation.ErrorPositions, SourcePositionRange{
+			SourceName:  fpath,
+			StartLine:   line,
+			StartColumn: col,
+			EndLine:     endLine,
+			EndColumn:   endCol,
+			Span:        nodeBase.Span,
+		})
+
+		aggregation.Message = fmt.Sprintf("%s\n%s:%d:%d: %s", aggregation.Message, fpath, line, col, parsingErr.Message)
+		return ContinueTraversal, nil
+	}, nil)
+
+	return aggregation
+}
+
 func (p *parser) parseChunk() (*Chunk, error) {
 	p.panicIfContextDone()
 
@@ -166,18 +185,66 @@ func (p *parser) parseChunk() (*Chunk, error) {
 		manifest = p.parseManifestIfPresent()
 	}
 
-	prevStmtEndIndex := int32(-1)
-	var prevStmtErrKind ParsingErrorKind
-
 	if p.onlyChunkStart {
 		goto finalize_chunk_node
 	}
 
 	p.eatSpaceNewlineSemicolonComment()
 
-	//parse statements
+	stmts = p.parseTopLevelStatements(nil, topLevelStmtParsingState{prevStmtEndIndex: -1}, nil)
+
+finalize_chunk_node:
+
+	chunk.Preinit = preinit
+	chunk.Manifest = manifest
+	chunk.IncludableChunkDesc = includableChunkDesc
+	chunk.Statements = stmts
+	chunk.GlobalConstantDeclarations = globalConstDecls
+	chunk.Tokens = p.tokens
+	if p.incrementalParsingData != nil {
+		p.incrementalParsingData.tokens = slices.Clone(p.tokens)
+	}
+	slices.SortFunc(chunk.Tokens, func(a, b Token) int {
+		return int(a.Span.Start) - int(b.Span.Start)
+	})
+
+	return chunk, nil
+}
+
+// A topLevelStmtParsingState is the state of the parser before the parsing of a top level statement.
+type topLevelStmtParsingState struct {
+	start            int32 //index of the first rune of the statement
+	tokenCount       int32 //number of tokens created before the statement
+	prevStmtEndIndex int32
+	prevStmtErrKind  ParsingErrorKind
+}
+
+// parseTopLevelStatements parses top level statements until the end of the chunk is reached or until shouldStop
+// returns true, the parsed statements are appended to stmts. shouldStop is called before each statement
+// and can be nil. The state before each statement is recorded if p.incrementalParsingData is not nil.
+func (p *parser) parseTopLevelStatements(
+	stmts []Node,
+	state topLevelStmtParsingState,
+	shouldStop func(state topLevelStmtParsingState) bool,
+) []Node {
+	prevStmtEndIndex := state.prevStmtEndIndex
+	prevStmtErrKind := state.prevStmtErrKind
 
 	for p.i < p.len {
+		state := topLevelStmtParsingState{
+			start:            p.i,
+			tokenCount:       len32(p.tokens),
+			prevStmtEndIndex: prevStmtEndIndex,
+			prevStmtErrKind:  prevStmtErrKind,
+		}
+
+		if shouldStop != nil && shouldStop(state) {
+			break
+		}
+		if p.incrementalParsingData != nil {
+			p.incrementalParsingData.stmtStates = append(p.incrementalParsingData.stmtStates, state)
+		}
+
 		if IsForbiddenSpaceCharacter(p.s[p.i]) {
 			p.tokens = append(p.tokens, Token{Type: UNEXPECTED_CHAR, Span: NodeSpan{p.i, p.i + 1}, Raw: string(p.s[p.i])})
 			stmts = append(stmts, &UnknownNode{
@@ -215,17 +282,5 @@ func (p *parser) parseChunk() (*Chunk, error) {
 		p.eatSpaceNewlineSemicolonComment()
 	}
 
-finalize_chunk_node:
-
-	chunk.Preinit = preinit
-	chunk.Manifest = manifest
-	chunk.IncludableChunkDesc = includableChunkDesc
-	chunk.Statements = stmts
-	chunk.GlobalConstantDeclarations = globalConstDecls
-	chunk.Tokens = p.tokens
-	slices.SortFunc(chunk.Tokens, func(a, b Token) int {
-		return int(a.Span.Start) - int(b.Span.Start)
-	})
-
-	return chunk, nil
+	return stmts
 }
@@ -233,6 +233,7 @@ const (
 	//test suite
 	UNTERMINATED_TESTSUITE_EXPRESSION_MISSING_BLOCK = "unterminated test suite expression: missing block"
 	UNTERMINATED_TESTCASE_EXPRESSION_MISSING_BLOCK  = "unterminated test case expression: missing block"
+	UNTERMINATED_BENCHMARK_EXPRESSION_MISSING_BLOCK = "unterminated benchmark expression: missing block"
 
 	// lifetimejob
 	UNTERMINATED_LIFETIMEJOB_EXPRESSION_MISSING_META            = "unterminated lifetimejob expression: missing meta"
@@ -287,6 +288,10 @@ const (
 	PARAM_LIST_OF_FUNC_SHOULD_CONTAIN_PARAMETERS_SEP_BY_COMMAS = "the parameter list of a function should contain parameters (a parameter is an identifier followed (or not) by a type) separated by commas"
 	UNTERMINATED_CAPTURE_LIST_MISSING_CLOSING_BRACKET          = "unterminated capture list: missing closing bracket"
 
+	UNTERMINATED_TYPE_PARAM_LIST_MISSING_CLOSING_ANGLE_BRACKET = "unterminated type parameter list: missing closing '>'"
+	TYPE_PARAM_LIST_SHOULD_CONTAIN_TYPE_PARAMS_SEP_BY_COMMAS   = "the type parameter list should contain type parameters (a type parameter is a name followed (or not) by a constraint) separated by commas"
+	EMPTY_TYPE_PARAM_LIST                                      = "the type parameter list should not be empty"
+
 	PERCENT_FN_SHOULD_BE_FOLLOWED_BY_PARAMETERS                     = "'%fn' should be followed by parameters "
 	PARAM_LIST_OF_FUNC_PATT_SHOULD_CONTAIN_PARAMETERS_SEP_BY_COMMAS = "the parameter list of a function pattern should contain parameters (a parameter is a type preceded (or not) by an identifier) separated by commas"
 
@@ -672,6 +677,10 @@ func fmtUnexpectedCharInParameters(r rune) string {
 	return fmt.Sprintf("unexpected char %s in parameters", fmtRuneInfo(r))
 }
 
+func fmtUnexpectedCharInTypeParameters(r rune) string {
+	return fmt.Sprintf("unexpected char %s in type parameters", fmtRuneInfo(r))
+}
+
 func fmtUnexpectedCharInCaptureList(r rune) string {
 	return fmt.Sprintf("unexpected char %s in capture lisr", fmtRuneInfo(r))
 }
@@ -335,6 +335,9 @@ func (p *parser) parseUnderscoreAlphaStartingExpression(precededByOpeningParen .
 		case tokenStrings[TESTCASE_KEYWORD]:
 			node = p.parseTestCaseExpression(v)
 			return
+		case tokenStrings[BENCHMARK_KEYWORD]:
+			node = p.parseBenchmarkExpression(v)
+			return
 		case tokenStrings[LIFETIMEJOB_KEYWORD]:
 			node = p.parseLifetimeJobExpression(v)
 			return
@@ -71,6 +71,12 @@ func (p *parser) parseStatement() Node {
 			break
 		}
 
+		e.IsStatement = true
+	case *BenchmarkExpression:
+		if expr.Base().IsParenthesized {
+			break
+		}
+
 		e.IsStatement = true
 	}
 
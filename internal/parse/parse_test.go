@@ -17934,6 +17934,107 @@ func testParse(
 			assert.NotNil(t, res)
 			assert.ErrorContains(t, err, KEYWORDS_SHOULD_NOT_BE_USED_AS_FN_NAMES)
 		})
+
+		t.Run("type parameter", func(t *testing.T) {
+			n := mustparseChunk(t, "fn f<T>(a T) T {}")
+
+			typeParam := &TypeParameter{
+				NodeBase: NodeBase{Span: NodeSpan{5, 6}},
+				Name: &PatternIdentifierLiteral{
+					NodeBase:   NodeBase{Span: NodeSpan{5, 6}},
+					Name:       "T",
+					Unprefixed: true,
+				},
+			}
+
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 17}, nil, false},
+				Statements: []Node{
+					&FunctionDeclaration{
+						NodeBase: NodeBase{Span: NodeSpan{0, 17}},
+						Name: &IdentifierLiteral{
+							NodeBase: NodeBase{Span: NodeSpan{3, 4}},
+							Name:     "f",
+						},
+						Function: &FunctionExpression{
+							NodeBase:       NodeBase{Span: NodeSpan{0, 17}},
+							TypeParameters: []*TypeParameter{typeParam},
+							Parameters: []*FunctionParameter{
+								{
+									NodeBase: NodeBase{Span: NodeSpan{8, 11}},
+									Var: &IdentifierLiteral{
+										NodeBase: NodeBase{Span: NodeSpan{8, 9}},
+										Name:     "a",
+									},
+									Type: &PatternIdentifierLiteral{
+										NodeBase:      NodeBase{Span: NodeSpan{10, 11}},
+										Name:          "T",
+										Unprefixed:    true,
+										TypeParameter: typeParam,
+									},
+								},
+							},
+							ReturnType: &PatternIdentifierLiteral{
+								NodeBase:      NodeBase{Span: NodeSpan{13, 14}},
+								Name:          "T",
+								Unprefixed:    true,
+								TypeParameter: typeParam,
+							},
+							Body: &Block{
+								NodeBase: NodeBase{Span: NodeSpan{15, 17}},
+							},
+						},
+					},
+				},
+			}, n)
+		})
+
+		t.Run("type parameters with a constraint", func(t *testing.T) {
+			n := mustparseChunk(t, "fn f<T, U serializable>(a T, b U){}")
+			fn := n.Statements[0].(*FunctionDeclaration).Function
+
+			if !assert.Len(t, fn.TypeParameters, 2) {
+				return
+			}
+
+			assert.Nil(t, fn.TypeParameters[0].Constraint)
+			assert.Equal(t, NodeSpan{8, 22}, fn.TypeParameters[1].Span)
+			assert.Equal(t, &PatternIdentifierLiteral{
+				NodeBase:   NodeBase{Span: NodeSpan{10, 22}},
+				Name:       "serializable",
+				Unprefixed: true,
+			}, fn.TypeParameters[1].Constraint)
+
+			assert.Same(t, fn.TypeParameters[0], fn.Parameters[0].Type.(*PatternIdentifierLiteral).TypeParameter)
+			assert.Same(t, fn.TypeParameters[1], fn.Parameters[1].Type.(*PatternIdentifierLiteral).TypeParameter)
+		})
+
+		t.Run("type parameters of a nested generic function shadow the ones of the outer function", func(t *testing.T) {
+			n := mustparseChunk(t, "fn f<T>(a T){ return fn<T>(b T) => b }")
+			outer := n.Statements[0].(*FunctionDeclaration).Function
+			inner := outer.Body.(*Block).Statements[0].(*ReturnStatement).Expr.(*FunctionExpression)
+
+			assert.Same(t, outer.TypeParameters[0], outer.Parameters[0].Type.(*PatternIdentifierLiteral).TypeParameter)
+			assert.Same(t, inner.TypeParameters[0], inner.Parameters[0].Type.(*PatternIdentifierLiteral).TypeParameter)
+		})
+
+		t.Run("anonymous generic function", func(t *testing.T) {
+			n := mustparseChunk(t, "fn<T>(a T) => a")
+			fn := n.Statements[0].(*FunctionExpression)
+			assert.Len(t, fn.TypeParameters, 1)
+		})
+
+		t.Run("empty type parameter list", func(t *testing.T) {
+			res, err := parseChunk(t, "fn f<>(a){}", "")
+			assert.NotNil(t, res)
+			assert.ErrorContains(t, err, EMPTY_TYPE_PARAM_LIST)
+		})
+
+		t.Run("unterminated type parameter list", func(t *testing.T) {
+			res, err := parseChunk(t, "fn f<T", "")
+			assert.NotNil(t, res)
+			assert.ErrorContains(t, err, UNTERMINATED_TYPE_PARAM_LIST_MISSING_CLOSING_ANGLE_BRACKET)
+		})
 	})
 
 	t.Run("function pattern expression", func(t *testing.T) {
@@ -21651,6 +21752,107 @@ func testParse(
 
 	})
 
+	t.Run("benchmark expression", func(t *testing.T) {
+		t.Run("no meta", func(t *testing.T) {
+			n := mustparseChunk(t, `benchmark {}`)
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 12}, nil, false},
+				Statements: []Node{
+					&BenchmarkExpression{
+						NodeBase: NodeBase{
+							NodeSpan{0, 12},
+							nil,
+							false,
+						},
+						IsStatement: true,
+						Module: &EmbeddedModule{
+							NodeBase: NodeBase{
+								NodeSpan{10, 12},
+								nil,
+								false,
+							},
+						},
+					},
+				},
+			}, n)
+		})
+
+		t.Run("with meta", func(t *testing.T) {
+			n := mustparseChunk(t, `benchmark "name" {}`)
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 19}, nil, false},
+				Statements: []Node{
+					&BenchmarkExpression{
+						IsStatement: true,
+						NodeBase: NodeBase{
+							NodeSpan{0, 19},
+							nil,
+							false,
+						},
+						Meta: &QuotedStringLiteral{
+							NodeBase: NodeBase{
+								Span: NodeSpan{10, 16},
+							},
+							Raw:   `"name"`,
+							Value: "name",
+						},
+						Module: &EmbeddedModule{
+							NodeBase: NodeBase{
+								NodeSpan{17, 19},
+								nil,
+								false,
+							},
+						},
+					},
+				},
+			}, n)
+		})
+
+		t.Run("missing embedded module and no meta", func(t *testing.T) {
+			n, err := parseChunk(t, `benchmark`, "")
+			assert.Error(t, err)
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 9}, nil, false},
+				Statements: []Node{
+					&BenchmarkExpression{
+						IsStatement: true,
+						NodeBase: NodeBase{
+							NodeSpan{0, 9},
+							&ParsingError{MissingBlock, UNTERMINATED_BENCHMARK_EXPRESSION_MISSING_BLOCK},
+							false,
+						},
+					},
+				},
+			}, n)
+		})
+
+		t.Run("with meta but missing embedded module", func(t *testing.T) {
+			n, err := parseChunk(t, `benchmark "name"`, "")
+			assert.Error(t, err)
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 16}, nil, false},
+				Statements: []Node{
+					&BenchmarkExpression{
+						IsStatement: true,
+						NodeBase: NodeBase{
+							NodeSpan{0, 16},
+							&ParsingError{UnspecifiedParsingError, UNTERMINATED_BENCHMARK_EXPRESSION_MISSING_BLOCK},
+							false,
+						},
+						Meta: &QuotedStringLiteral{
+							NodeBase: NodeBase{
+								Span: NodeSpan{10, 16},
+							},
+							Raw:   `"name"`,
+							Value: "name",
+						},
+					},
+				},
+			}, n)
+		})
+
+	})
+
 	t.Run("lifetimejob expression", func(t *testing.T) {
 
 		t.Run("ok", func(t *testing.T) {
@@ -23938,6 +24140,49 @@ func testParse(
 	})
 
 	t.Run("pattern definition", func(t *testing.T) {
+		t.Run("type parameter", func(t *testing.T) {
+			n := mustparseChunk(t, "pattern p<T> = []T")
+
+			typeParam := &TypeParameter{
+				NodeBase: NodeBase{Span: NodeSpan{10, 11}},
+				Name: &PatternIdentifierLiteral{
+					NodeBase:   NodeBase{Span: NodeSpan{10, 11}},
+					Name:       "T",
+					Unprefixed: true,
+				},
+			}
+
+			assert.EqualValues(t, &Chunk{
+				NodeBase: NodeBase{NodeSpan{0, 18}, nil, false},
+				Statements: []Node{
+					&PatternDefinition{
+						NodeBase: NodeBase{Span: NodeSpan{0, 18}},
+						Left: &PatternIdentifierLiteral{
+							NodeBase:   NodeBase{Span: NodeSpan{8, 9}},
+							Name:       "p",
+							Unprefixed: true,
+						},
+						TypeParameters: []*TypeParameter{typeParam},
+						Right: &ListPatternLiteral{
+							NodeBase: NodeBase{Span: NodeSpan{15, 18}},
+							GeneralElement: &PatternIdentifierLiteral{
+								NodeBase:      NodeBase{Span: NodeSpan{17, 18}},
+								Name:          "T",
+								Unprefixed:    true,
+								TypeParameter: typeParam,
+							},
+						},
+					},
+				},
+			}, n)
+		})
+
+		t.Run("unterminated type parameter list", func(t *testing.T) {
+			res, err := parseChunk(t, "pattern p<T", "")
+			assert.NotNil(t, res)
+			assert.ErrorContains(t, err, UNTERMINATED_TYPE_PARAM_LIST_MISSING_CLOSING_ANGLE_BRACKET)
+		})
+
 		t.Run("RHS is a pattern identifier literal ", func(t *testing.T) {
 			n := mustparseChunk(t, "pattern i = %int")
 			assert.EqualValues(t, &Chunk{
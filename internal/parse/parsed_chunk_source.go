@@ -14,19 +14,23 @@ type ParsedChunkSource struct {
 	Source    ChunkSource
 	runes     []rune
 	runesLock sync.Mutex
+
+	//nil if the chunk was not parsed by ParseChunkSource or ParseChunkSourceIncrementally.
+	incrementalParsingData *incrementalParsingData
 }
 
 func ParseChunkSource(src ChunkSource) (*ParsedChunkSource, error) {
-	runes, chunk, err := ParseChunk2(src.Code(), src.Name())
+	runes, chunk, incrementalParsingData, err := parseChunk3(src.Code(), src.Name(), true)
 
 	if chunk == nil {
 		return nil, err
 	}
 
 	return &ParsedChunkSource{
-		Node:   chunk,
-		Source: src,
-		runes:  runes,
+		Node:                   chunk,
+		Source:                 src,
+		runes:                  runes,
+		incrementalParsingData: incrementalParsingData,
 	}, err
 }
 
@@ -17,6 +17,9 @@ type parser struct {
 	//mostly valueless tokens, the slice may be not perfectly ordered.
 	tokens []Token
 
+	//data recorded for the incremental parsing of the next versions of the chunk, can be nil.
+	incrementalParsingData *incrementalParsingData
+
 	noCheckFuel          int //-1 if infinite fuel
 	remainingNoCheckFuel int //refueled after each context check.
 
@@ -92,6 +92,7 @@ const (
 	CONCAT_KEYWORD
 	TESTSUITE_KEYWORD
 	TESTCASE_KEYWORD
+	BENCHMARK_KEYWORD
 	SYNCHRONIZED_KEYWORD
 	LIFETIMEJOB_KEYWORD
 	ON_KEYWORD
@@ -330,6 +331,7 @@ var tokenStrings = [...]string{
 	CONCAT_KEYWORD:                 "concat",
 	TESTSUITE_KEYWORD:              "testsuite",
 	TESTCASE_KEYWORD:               "testcase",
+	BENCHMARK_KEYWORD:              "benchmark",
 	SYNCHRONIZED_KEYWORD:           "synchronized",
 	LIFETIMEJOB_KEYWORD:            "lifetimejob",
 	ON_KEYWORD:                     "on",
@@ -500,6 +502,7 @@ var tokenTypenames = [...]string{
 	CONCAT_KEYWORD:                 "CONCAT_KEYWORD",
 	TESTSUITE_KEYWORD:              "TESTSUITE_KEYWORD",
 	TESTCASE_KEYWORD:               "TESTCASE_KEYWORD",
+	BENCHMARK_KEYWORD:              "BENCHMARK_KEYWORD",
 	SYNCHRONIZED_KEYWORD:           "SYNCHRONIZED_KEYWORD",
 	LIFETIMEJOB_KEYWORD:            "LIFETIMEJOB_KEYWORD",
 	ON_KEYWORD:                     "ON_KEYWORD",
@@ -135,7 +135,7 @@ func (r *Registry) getCreateDevDatabasesDir(id core.ProjectID) (projectDevDataba
 	}
 
 	//create the <dev dir>/<project id>/databases dir
-	projectDevDatabasesDir = filepath.Join(devDir, string(id), DEV_DATABASES_OS_DIR)
+	projectDevDatabasesDir = GetDevDatabasesDir(r.projectsDir, id)
 	err = r.filesystem.MkdirAll(projectDevDatabasesDir, fs_ns.DEFAULT_DIR_FMODE)
 	if err != nil {
 		projectDevDatabasesDir = ""
@@ -145,6 +145,11 @@ func (r *Registry) getCreateDevDatabasesDir(id core.ProjectID) (projectDevDataba
 	return projectDevDatabasesDir, nil
 }
 
+// GetDevDatabasesDir returns the directory storing the dev databases of a project, the directory may not exist.
+func GetDevDatabasesDir(projectsDir string, id core.ProjectID) string {
+	return filepath.Join(projectsDir, DEV_OS_DIR, string(id), DEV_DATABASES_OS_DIR)
+}
+
 type OpenProjectParams struct {
 	Id               core.ProjectID
 	DevSideConfig    DevSideProjectConfig `json:"config"`
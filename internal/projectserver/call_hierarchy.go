@@ -0,0 +1,564 @@
+package projectserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/projectserver/lsp"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	PREPARE_CALL_HIERARCHY_METHOD        = "textDocument/prepareCallHierarchy"
+	CALL_HIERARCHY_INCOMING_CALLS_METHOD = "callHierarchy/incomingCalls"
+	CALL_HIERARCHY_OUTGOING_CALLS_METHOD = "callHierarchy/outgoingCalls"
+
+	GO_FUNCTION_CALLABLE_DETAIL = "Go function"
+	MODULE_CALLABLE_DETAIL      = "module"
+)
+
+type callableKind string
+
+const (
+	inoxFunctionCallable callableKind = "inox-function"
+	goFunctionCallable   callableKind = "go-function"
+	moduleCallable       callableKind = "module"
+)
+
+// A callable is an item of a call hierarchy: a declared Inox function, a Go function or a module. Calls located
+// at the top level of a module (or in the body of an anonymous function defined at the top level) are attributed
+// to the module. Imports and inclusions are considered as calls to the imported module.
+// Inox functions and modules are identified by the position of their name, Go functions by their name.
+// The exported fields are preserved between a call hierarchy preparation and incoming/outgoing calls requests.
+type callable struct {
+	Kind       callableKind              `json:"kind"`
+	Name       string                    `json:"name"`
+	Definition parse.SourcePositionRange `json:"definition"` //zero for Go functions
+
+	//position of the whole declaration, module or callee (Go functions).
+	declaration parse.SourcePositionRange
+}
+
+func (c callable) is(other callable) bool {
+	return c.Kind == other.Kind && c.Name == other.Name && c.Definition == other.Definition
+}
+
+func (c callable) toCallHierarchyItem(scheme string) defines.CallHierarchyItem {
+	declaration := c.declaration
+	if declaration == (parse.SourcePositionRange{}) {
+		declaration = c.Definition
+	}
+
+	selection := c.Definition
+	if c.Kind == goFunctionCallable {
+		selection = declaration
+	}
+
+	item := defines.CallHierarchyItem{
+		Name:           c.Name,
+		Kind:           defines.SymbolKindFunction,
+		Uri:            defines.DocumentUri(scheme + "://" + declaration.SourceName),
+		Range:          rangeToLspRange(declaration),
+		SelectionRange: rangeToLspRange(selection),
+		Data:           c,
+	}
+
+	switch c.Kind {
+	case goFunctionCallable:
+		detail := GO_FUNCTION_CALLABLE_DETAIL
+		item.Detail = &detail
+	case moduleCallable:
+		detail := MODULE_CALLABLE_DETAIL
+		item.Kind = defines.SymbolKindModule
+		item.Name = filepath.Base(c.Name)
+		item.Detail = &detail
+	}
+
+	return item
+}
+
+// A callableCalls is a callable and the positions of the calls made by or to this callable.
+type callableCalls struct {
+	callable  callable
+	positions []parse.SourcePositionRange
+}
+
+// A callSite is a call (or an import) made by a callable, position is the position of the callee or of the
+// import statement.
+type callSite struct {
+	caller   callable
+	callee   callable
+	position parse.SourcePositionRange
+}
+
+func registerCallHierarchyMethodHandlers(server *lsp.Server) {
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: PREPARE_CALL_HIERARCHY_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.CallHierarchyPrepareParams{}
+		},
+		Handler: handlePrepareCallHierarchy,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: CALL_HIERARCHY_INCOMING_CALLS_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.CallHierarchyIncomingCallsParams{}
+		},
+		Handler: handleCallHierarchyIncomingCalls,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: CALL_HIERARCHY_OUTGOING_CALLS_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.CallHierarchyOutgoingCallsParams{}
+		},
+		Handler: handleCallHierarchyOutgoingCalls,
+	})
+}
+
+func handlePrepareCallHierarchy(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.CallHierarchyPrepareParams)
+	session := jsonrpc.GetSession(ctx)
+
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+
+	if fls == nil {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+
+	fpath, err := getFilePath(params.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+	line, column := getLineColumn(params.Position)
+
+	handlingCtx := session.Context().BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	var items []defines.CallHierarchyItem
+
+	ok := withPreparedFile(fpath, handlingCtx, session, func(prepResult preparationResult) {
+		callable, ok := getCallableAt(line, column, prepResult)
+		if ok {
+			items = append(items, callable.toCallHierarchyItem(sessionData.Scheme()))
+		}
+	})
+
+	if !ok || len(items) == 0 {
+		return nil, nil
+	}
+	return items, nil
+}
+
+func handleCallHierarchyIncomingCalls(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.CallHierarchyIncomingCallsParams)
+	session := jsonrpc.GetSession(ctx)
+
+	var target callable
+	if err := decodeHierarchyItemData(params.Item.Data, &target); err != nil {
+		return nil, err
+	}
+
+	handlingCtx, ok := newHierarchyHandlingContext(session)
+	if !ok {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+	defer handlingCtx.CancelGracefully()
+
+	incomingCalls, err := findIncomingCallsInProject(target, handlingCtx, session)
+	if err != nil {
+		logs.Println("failed to get incoming calls", err)
+		return nil, nil
+	}
+
+	scheme := getSessionData(session).Scheme()
+	result := []defines.CallHierarchyIncomingCall{}
+
+	for _, calls := range incomingCalls {
+		result = append(result, defines.CallHierarchyIncomingCall{
+			From:       calls.callable.toCallHierarchyItem(scheme),
+			FromRanges: utils.MapSlice(calls.positions, rangeToLspRange),
+		})
+	}
+
+	return result, nil
+}
+
+func handleCallHierarchyOutgoingCalls(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.CallHierarchyOutgoingCallsParams)
+	session := jsonrpc.GetSession(ctx)
+
+	var source callable
+	if err := decodeHierarchyItemData(params.Item.Data, &source); err != nil {
+		return nil, err
+	}
+
+	if source.Kind == goFunctionCallable {
+		return []defines.CallHierarchyOutgoingCall{}, nil
+	}
+
+	handlingCtx, ok := newHierarchyHandlingContext(session)
+	if !ok {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+	defer handlingCtx.CancelGracefully()
+
+	scheme := getSessionData(session).Scheme()
+	result := []defines.CallHierarchyOutgoingCall{}
+
+	withPreparedFile(source.Definition.SourceName, handlingCtx, session, func(prepResult preparationResult) {
+		for _, calls := range findOutgoingCallsInModule(source, prepResult) {
+			result = append(result, defines.CallHierarchyOutgoingCall{
+				To:         calls.callable.toCallHierarchyItem(scheme),
+				FromRanges: utils.MapSlice(calls.positions, rangeToLspRange),
+			})
+		}
+	})
+
+	return result, nil
+}
+
+// getCallableAt returns the callable at a specific position in a prepared chunk: the name of a function declaration,
+// a reference to a function, the source of an import or the manifest of the module.
+func getCallableAt(line, column int32, prepResult preparationResult) (callable, bool) {
+	chunk := prepResult.chunk
+	data := prepResult.state.SymbolicData.Data
+
+	span := chunk.GetLineColumnSingeCharSpan(line, column)
+	node, ancestors, ok := chunk.GetNodeAndChainAtSpan(span)
+	if !ok || node == nil {
+		return callable{}, false
+	}
+
+	var parent parse.Node
+	if len(ancestors) > 0 {
+		parent = ancestors[len(ancestors)-1]
+	}
+
+	switch n := node.(type) {
+	case *parse.Manifest, *parse.IncludableChunkDescription:
+		return getModuleCallable(chunk), true
+	case *parse.IdentifierLiteral:
+		if decl, ok := parent.(*parse.FunctionDeclaration); ok && decl.Name == n {
+			return getDeclaredFunctionCallable(decl, chunk), true
+		}
+		if memberExpr, ok := parent.(*parse.IdentifierMemberExpression); ok {
+			return resolveCallable(memberExpr, ancestors[:len(ancestors)-1], chunk, data, prepResult)
+		}
+		return resolveCallable(n, ancestors, chunk, data, prepResult)
+	case *parse.Variable, *parse.GlobalVariable, *parse.IdentifierMemberExpression:
+		return resolveCallable(n, ancestors, chunk, data, prepResult)
+	}
+
+	//source of an import or an inclusion
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		switch stmt := ancestors[i].(type) {
+		case *parse.ImportStatement, *parse.InclusionImportStatement:
+			if imported, ok := getImportedModuleCallable(stmt, prepResult.module); ok {
+				return imported, true
+			}
+			return callable{}, false
+		}
+	}
+
+	return callable{}, false
+}
+
+// resolveCallable returns the function referenced by $node.
+func resolveCallable(node parse.Node, ancestors []parse.Node, chunk *parse.ParsedChunkSource, data *symbolic.Data, prepResult preparationResult) (callable, bool) {
+	value, ok := data.GetMostSpecificNodeValue(node)
+	if !ok {
+		return callable{}, false
+	}
+
+	if fn, ok := value.(*symbolic.Function); ok {
+		//Go functions are converted to *symbolic.Function values when called.
+		goFn, ok := fn.OriginGoFunction()
+		if !ok {
+			return callable{}, false
+		}
+		value = goFn
+	}
+
+	switch value.(type) {
+	case *symbolic.GoFunction:
+		return callable{
+			Kind:        goFunctionCallable,
+			Name:        getGoFunctionName(node, chunk),
+			declaration: chunk.GetSourcePosition(node.Base().Span),
+		}, true
+	case *symbolic.InoxFunction:
+		kind, name, ok := getSymbolKindAndName(node, ancestors)
+		if !ok || kind != variableSymbol {
+			return callable{}, false
+		}
+
+		definition, ok := getSymbolDefinition(kind, node, ancestors, data)
+		if !ok {
+			return callable{}, false
+		}
+
+		fn := callable{
+			Kind:        inoxFunctionCallable,
+			Name:        name,
+			Definition:  definition,
+			declaration: definition,
+		}
+
+		//find the declaration in the chunks of the module.
+		for _, moduleChunk := range getModuleChunks(prepResult) {
+			if moduleChunk.Name() != definition.SourceName {
+				continue
+			}
+			for _, stmt := range moduleChunk.Node.Statements {
+				decl, ok := stmt.(*parse.FunctionDeclaration)
+				if ok && moduleChunk.GetSourcePosition(decl.Name.Span) == definition {
+					fn.declaration = moduleChunk.GetSourcePosition(decl.Span)
+				}
+			}
+		}
+
+		return fn, true
+	}
+
+	return callable{}, false
+}
+
+// getGoFunctionName returns the name of the Go function referenced by $node, the name of Go functions in namespaces
+// is prefixed by the namespace's name (e.g. fs.read).
+func getGoFunctionName(node parse.Node, chunk *parse.ParsedChunkSource) string {
+	switch n := node.(type) {
+	case *parse.IdentifierLiteral:
+		return n.Name
+	case *parse.Variable:
+		return n.Name
+	case *parse.GlobalVariable:
+		return n.Name
+	case *parse.IdentifierMemberExpression:
+		name := n.Left.Name
+		for _, propName := range n.PropertyNames {
+			name += "." + propName.Name
+		}
+		return name
+	}
+	span := node.Base().Span
+	return string(chunk.Runes()[span.Start:span.End])
+}
+
+func getDeclaredFunctionCallable(decl *parse.FunctionDeclaration, chunk *parse.ParsedChunkSource) callable {
+	return callable{
+		Kind:        inoxFunctionCallable,
+		Name:        decl.Name.Name,
+		Definition:  chunk.GetSourcePosition(decl.Name.Span),
+		declaration: chunk.GetSourcePosition(decl.Span),
+	}
+}
+
+func getModuleCallable(chunk *parse.ParsedChunkSource) callable {
+	return callable{
+		Kind:        moduleCallable,
+		Name:        chunk.Name(),
+		Definition:  chunk.GetSourcePosition(parse.NodeSpan{Start: 0, End: 0}),
+		declaration: chunk.GetSourcePosition(chunk.Node.Span),
+	}
+}
+
+// getImportedModuleCallable returns the module or includable chunk imported by an import statement.
+func getImportedModuleCallable(stmt parse.Node, module *core.Module) (callable, bool) {
+	if module == nil {
+		return callable{}, false
+	}
+
+	switch s := stmt.(type) {
+	case *parse.ImportStatement:
+		importedModule, ok := module.DirectlyImportedModulesByStatement[s]
+		if ok && importedModule.MainChunk != nil {
+			return getModuleCallable(importedModule.MainChunk), true
+		}
+	case *parse.InclusionImportStatement:
+		includedChunk, ok := module.InclusionStatementMap[s]
+		if ok && includedChunk.ParsedChunkSource != nil {
+			return getModuleCallable(includedChunk.ParsedChunkSource), true
+		}
+	}
+	return callable{}, false
+}
+
+// getCaller returns the callable a node located in $chunk is attributed to: the closest declared function or
+// the module.
+func getCaller(ancestors []parse.Node, chunk *parse.ParsedChunkSource) callable {
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if decl, ok := ancestors[i].(*parse.FunctionDeclaration); ok {
+			return getDeclaredFunctionCallable(decl, chunk)
+		}
+	}
+	return getModuleCallable(chunk)
+}
+
+// getCallSites returns the calls and the imports made in a prepared chunk, calls to values that are not functions
+// are ignored.
+func getCallSites(chunk *parse.ParsedChunkSource, prepResult preparationResult) (sites []callSite) {
+	data := prepResult.state.SymbolicData.Data
+
+	parse.Walk(chunk.Node, func(node, _, _ parse.Node, ancestors []parse.Node, _ bool) (parse.TraversalAction, error) {
+		switch n := node.(type) {
+		case *parse.CallExpression:
+			calleeAncestors := append(slices.Clip(ancestors), n)
+			callee, ok := resolveCallable(n.Callee, calleeAncestors, chunk, data, prepResult)
+			if !ok {
+				break
+			}
+			sites = append(sites, callSite{
+				caller:   getCaller(ancestors, chunk),
+				callee:   callee,
+				position: chunk.GetSourcePosition(n.Callee.Base().Span),
+			})
+		case *parse.ImportStatement, *parse.InclusionImportStatement:
+			imported, ok := getImportedModuleCallable(n, prepResult.module)
+			if !ok {
+				break
+			}
+			sites = append(sites, callSite{
+				caller:   getModuleCallable(chunk),
+				callee:   imported,
+				position: chunk.GetSourcePosition(n.Base().Span),
+			})
+		}
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return
+}
+
+// findIncomingCallsInModule returns the calls to $target in a prepared module or includable file, the chunks
+// included by the module are also searched. The calls are grouped by caller.
+func findIncomingCallsInModule(target callable, prepResult preparationResult) (incomingCalls []callableCalls) {
+	for _, chunk := range getModuleChunks(prepResult) {
+		for _, site := range getCallSites(chunk, prepResult) {
+			if site.callee.is(target) {
+				incomingCalls = addCallPosition(incomingCalls, site.caller, site.position)
+			}
+		}
+	}
+	return
+}
+
+// findOutgoingCallsInModule returns the calls made by $source in a prepared module or includable file,
+// the calls are grouped by callee.
+func findOutgoingCallsInModule(source callable, prepResult preparationResult) (outgoingCalls []callableCalls) {
+	for _, chunk := range getModuleChunks(prepResult) {
+		if chunk.Name() != source.Definition.SourceName {
+			continue
+		}
+		for _, site := range getCallSites(chunk, prepResult) {
+			if site.caller.is(source) {
+				outgoingCalls = addCallPosition(outgoingCalls, site.callee, site.position)
+			}
+		}
+	}
+	return
+}
+
+// findIncomingCallsInProject searches for the calls to $target in all the Inox files of the project, files that do
+// not contain the name of the target are not prepared. The result does not contain duplicates.
+func findIncomingCallsInProject(target callable, handlingCtx *core.Context, session *jsonrpc.Session) ([]callableCalls, error) {
+	var (
+		incomingCalls []callableCalls
+		seen          = map[callSite]struct{}{}
+	)
+
+	substring := target.Name
+	switch target.Kind {
+	case goFunctionCallable:
+		substring = substring[strings.LastIndexByte(substring, '.')+1:]
+	case moduleCallable:
+		substring = filepath.Base(substring)
+	}
+
+	err := forEachPreparedProjectFile(substring, handlingCtx, session, func(prepResult preparationResult) {
+		for _, calls := range findIncomingCallsInModule(target, prepResult) {
+			for _, pos := range calls.positions {
+				site := callSite{caller: calls.callable, callee: target, position: pos}
+				if _, ok := seen[site]; ok {
+					continue
+				}
+				seen[site] = struct{}{}
+				incomingCalls = addCallPosition(incomingCalls, calls.callable, pos)
+			}
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(incomingCalls, func(a, b callableCalls) int {
+		return compareSourcePositions(a.positions[0], b.positions[0])
+	})
+
+	return incomingCalls, nil
+}
+
+func addCallPosition(calls []callableCalls, c callable, pos parse.SourcePositionRange) []callableCalls {
+	for i, e := range calls {
+		if e.callable.is(c) {
+			calls[i].positions = append(calls[i].positions, pos)
+			return calls
+		}
+	}
+	return append(calls, callableCalls{callable: c, positions: []parse.SourcePositionRange{pos}})
+}
+
+// getModuleChunks returns the prepared chunk and the chunks it includes.
+func getModuleChunks(prepResult preparationResult) []*parse.ParsedChunkSource {
+	chunks := []*parse.ParsedChunkSource{prepResult.chunk}
+
+	if prepResult.module != nil {
+		for _, includedChunk := range prepResult.module.FlattenedIncludedChunkList {
+			if includedChunk.ParsedChunkSource != prepResult.chunk {
+				chunks = append(chunks, includedChunk.ParsedChunkSource)
+			}
+		}
+	}
+	return chunks
+}
+
+func newHierarchyHandlingContext(session *jsonrpc.Session) (*core.Context, bool) {
+	fls, ok := getLspFilesystem(session)
+	if !ok {
+		return nil, false
+	}
+
+	return session.Context().BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	}), true
+}
+
+// decodeHierarchyItemData decodes the data field of a call hierarchy item or a type hierarchy item.
+func decodeHierarchyItemData(data interface{}, v any) error {
+	marshalled, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(marshalled, v); err != nil {
+		return errors.New("invalid hierarchy item data")
+	}
+	return nil
+}
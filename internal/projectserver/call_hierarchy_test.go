@@ -0,0 +1,159 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallHierarchy(t *testing.T) {
+	getCallStrings := func(calls []callableCalls) (strings []string) {
+		for _, call := range calls {
+			s := call.callable.Name + " @"
+			for _, pos := range call.positions {
+				s += " " + pos.String()
+			}
+			strings = append(strings, s)
+		}
+		return
+	}
+
+	t.Run("incoming calls of a function", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(){}\nfn g(){ f(); f() }\nf()",
+		})
+		if !ok {
+			return
+		}
+
+		target, ok := getCallableAt(2, 4, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, inoxFunctionCallable, target.Kind)
+		assert.Equal(t, "f", target.Name)
+
+		calls := findIncomingCallsInModule(target, prepResult)
+		assert.Equal(t, []string{"g @ /main.ix:3:9: /main.ix:3:14:", "/main.ix @ /main.ix:4:1:"}, getCallStrings(calls))
+	})
+
+	t.Run("outgoing calls of a function", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(){}\nfn g(){ f(); m = Mapping {}; m.compute(1); f() }\ng()",
+		})
+		if !ok {
+			return
+		}
+
+		source, ok := getCallableAt(3, 4, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+
+		calls := findOutgoingCallsInModule(source, prepResult)
+		assert.Equal(t, []string{"f @ /main.ix:3:9: /main.ix:3:44:", "m.compute @ /main.ix:3:30:"}, getCallStrings(calls))
+		if assert.Len(t, calls, 2) {
+			assert.Equal(t, goFunctionCallable, calls[1].callable.Kind)
+		}
+	})
+
+	t.Run("incoming calls of a Go function", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn g(){ m = Mapping {}; m.compute(1) }\nm = Mapping {}\nm.compute(2)",
+		})
+		if !ok {
+			return
+		}
+
+		target, ok := getCallableAt(4, 4, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, goFunctionCallable, target.Kind)
+		assert.Equal(t, "m.compute", target.Name)
+
+		calls := findIncomingCallsInModule(target, prepResult)
+		assert.Equal(t, []string{"g @ /main.ix:2:25:", "/main.ix @ /main.ix:4:1:"}, getCallStrings(calls))
+	})
+
+	t.Run("function declared in an included chunk", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nimport ./lib.ix\nf()",
+			"/lib.ix":  "includable-chunk\nfn f(){}",
+		})
+		if !ok {
+			return
+		}
+
+		target, ok := getCallableAt(3, 1, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, "/lib.ix:2:4:", target.Definition.String())
+		assert.Equal(t, "/lib.ix:2:1:", target.declaration.String())
+
+		calls := findIncomingCallsInModule(target, prepResult)
+		assert.Equal(t, []string{"/main.ix @ /main.ix:3:1:"}, getCallStrings(calls))
+	})
+
+	t.Run("incoming calls from another module importing the library declaring the function", func(t *testing.T) {
+		const lib = "includable-chunk\nfn f(){}"
+
+		libPrepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nimport ./lib.ix\nf()",
+			"/lib.ix":  lib,
+		})
+		if !ok {
+			return
+		}
+
+		target, ok := getCallableAt(3, 1, libPrepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+
+		//the target is sent to the client and is received back in the incoming calls request.
+		var receivedTarget callable
+		if !assert.NoError(t, decodeHierarchyItemData(target.toCallHierarchyItem("file").Data, &receivedTarget)) {
+			return
+		}
+
+		handlerPrepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nimport ./lib.ix\nfn handle(){ f() }\nf()",
+			"/lib.ix":  lib,
+		})
+		if !ok {
+			return
+		}
+
+		calls := findIncomingCallsInModule(receivedTarget, handlerPrepResult)
+		assert.Equal(t, []string{"handle @ /main.ix:3:14:", "/main.ix @ /main.ix:4:1:"}, getCallStrings(calls))
+	})
+
+	t.Run("included chunk", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nimport ./lib.ix",
+			"/lib.ix":  "includable-chunk\nfn f(){}",
+		})
+		if !ok {
+			return
+		}
+
+		target, ok := getCallableAt(2, 10, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, moduleCallable, target.Kind)
+		assert.Equal(t, "/lib.ix", target.Name)
+
+		calls := findIncomingCallsInModule(target, prepResult)
+		assert.Equal(t, []string{"/main.ix @ /main.ix:2:1:"}, getCallStrings(calls))
+
+		mainModule, ok := getCallableAt(1, 2, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		calls = findOutgoingCallsInModule(mainModule, prepResult)
+		assert.Equal(t, []string{"/lib.ix @ /main.ix:2:1:"}, getCallStrings(calls))
+	})
+}
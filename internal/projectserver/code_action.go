@@ -2,10 +2,19 @@ package projectserver
 
 import (
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/afs"
+
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/globals/globalnames"
+	"github.com/inoxlang/inox/internal/inoxconsts"
 	"github.com/inoxlang/inox/internal/parse"
 	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
 	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
@@ -13,6 +22,40 @@ import (
 
 var quickfixKind = defines.CodeActionKindQuickFix
 
+var (
+	PATTERN_NOT_DECLARED_REGEX  = regexp.MustCompile(`^pattern %([a-zA-Z_][a-zA-Z0-9_-]*) is not declared`)
+	VARIABLE_NOT_DECLARED_REGEX = regexp.MustCompile(`^(?:global )?variable '([^']+)' is not declared`)
+	PROP_DOES_NOT_EXIST_REGEX   = regexp.MustCompile(`^property \.([^ ]+) does not exist in `)
+)
+
+// A quickFix returns a code action fixing the problem reported by a diagnostic, ok is false if the
+// diagnostic is not handled by the quick fix.
+type quickFix func(params quickFixParams) (action defines.CodeAction, ok bool)
+
+type quickFixParams struct {
+	doc        defines.TextDocumentIdentifier
+	fpath      string
+	diagnostic defines.Diagnostic
+	span       parse.NodeSpan //span of the diagnostic
+	chunk      *parse.ParsedChunkSource
+	fls        afs.Filesystem
+	indentUnit string
+
+	snippetEditSupport bool //true if the client supports snippet text edits
+}
+
+// quickFixes is the catalogue of quick fixes, each fix handles specific static check, symbolic evaluation or
+// runtime errors.
+var quickFixes = []quickFix{
+	tryGetMissingPermissionAction,
+	tryGetImportPatternAction,
+	tryGetAddManifestParameterAction,
+	tryGetMustCallAction,
+	tryGetDeclareGlobalVariableAction,
+	tryGetAddObjectPatternPropertyAction,
+	tryGetWrapInTransactionAction,
+}
+
 func getCodeActions(
 	session *jsonrpc.Session, diagnostics []defines.Diagnostic, _range defines.Range,
 	doc defines.TextDocumentIdentifier, fpath string, fls *Filesystem,
@@ -23,6 +66,21 @@ func getCodeActions(
 		return nil, err
 	}
 
+	indentUnit := chunk.EstimatedIndentationUnit()
+	if indentUnit == "" {
+		indentUnit = strings.Repeat(" ", 4)
+	}
+
+	snippetEditSupport := false
+	if session != nil {
+		sessionData := getLockedSessionData(session)
+		workspaceCapabilities := sessionData.clientCapabilities.Workspace
+		sessionData.lock.Unlock()
+
+		snippetEditSupport = workspaceCapabilities != nil && workspaceCapabilities.WorkspaceEdit != nil &&
+			workspaceCapabilities.WorkspaceEdit.SnippetEditSupport != nil && *workspaceCapabilities.WorkspaceEdit.SnippetEditSupport
+	}
+
 	var codeActions []defines.CodeAction
 
 	for _, diagnostic := range diagnostics {
@@ -30,22 +88,74 @@ func getCodeActions(
 			continue
 		}
 
-		action, ok := tryGetMissingPermissionAction(doc, diagnostic, chunk)
-		if ok {
-			codeActions = append(codeActions, action)
+		startLine, startColumn := getLineColumn(diagnostic.Range.Start)
+		endLine, endColumn := getLineColumn(diagnostic.Range.End)
+
+		params := quickFixParams{
+			doc:        doc,
+			fpath:      fpath,
+			diagnostic: diagnostic,
+			span: parse.NodeSpan{
+				Start: chunk.GetLineColumnPosition(startLine, startColumn),
+				End:   chunk.GetLineColumnPosition(endLine, endColumn),
+			},
+			chunk:      chunk,
+			fls:        fls,
+			indentUnit: indentUnit,
+
+			snippetEditSupport: snippetEditSupport,
 		}
 
+		for _, fix := range quickFixes {
+			action, ok := fix(params)
+			if ok {
+				codeActions = append(codeActions, action)
+			}
+		}
 	}
 
 	return &codeActions, nil
 }
 
-func tryGetMissingPermissionAction(doc defines.TextDocumentIdentifier, diagnostic defines.Diagnostic, chunk *parse.ParsedChunkSource) (action defines.CodeAction, actionOk bool) {
-	indentUnit := chunk.EstimatedIndentationUnit()
-	if indentUnit == "" {
-		indentUnit = strings.Repeat(" ", 4)
+func makeQuickFixAction(title string, doc defines.TextDocumentIdentifier, textEdits ...defines.TextEdit) defines.CodeAction {
+	return defines.CodeAction{
+		Title: title,
+		Kind:  &quickfixKind,
+		Edit: &defines.WorkspaceEdit{
+			Changes: &map[string][]defines.TextEdit{
+				string(doc.Uri): textEdits,
+			},
+		},
+	}
+}
+
+// makeSnippetQuickFixAction makes a quick fix whose edits are snippet text edits, the client should support them
+// (workspace.workspaceEdit.snippetEditSupport capability).
+func makeSnippetQuickFixAction(title string, doc defines.TextDocumentIdentifier, snippetEdits ...defines.SnippetTextEdit) defines.CodeAction {
+	var edits []interface{}
+	for _, edit := range snippetEdits {
+		edits = append(edits, edit)
 	}
 
+	return defines.CodeAction{
+		Title: title,
+		Kind:  &quickfixKind,
+		Edit: &defines.WorkspaceEdit{
+			DocumentChanges: &[]interface{}{
+				defines.TextDocumentEdit{
+					TextDocument: defines.OptionalVersionedTextDocumentIdentifier{TextDocumentIdentifier: doc},
+					Edits:        edits,
+				},
+			},
+		},
+	}
+}
+
+func tryGetMissingPermissionAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	diagnostic := params.diagnostic
+	chunk := params.chunk
+	indentUnit := params.indentUnit
+
 	switch *diagnostic.Severity {
 	case defines.DiagnosticSeverityWarning:
 		if strings.Contains(diagnostic.Message, symbolic.POSSIBLE_MISSING_PERM_TO_CREATE_A_LTHREAD) {
@@ -61,23 +171,465 @@ func tryGetMissingPermissionAction(doc defines.TextDocumentIdentifier, diagnosti
 				textEdits = []defines.TextEdit{makeTextEditAddManifest(chunk, []string{missingPerm}, indentUnit)}
 			}
 
-			action = defines.CodeAction{
-				Title: "Add Missing Permission",
-				Kind:  &quickfixKind,
-				Edit: &defines.WorkspaceEdit{
-					Changes: &map[string][]defines.TextEdit{
-						string(doc.Uri): textEdits,
-					},
-				},
+			action = makeQuickFixAction("Add Missing Permission", params.doc, textEdits...)
+			actionOk = true
+		}
+	}
+
+	return
+}
+
+// tryGetImportPatternAction handles undeclared patterns: if an includable chunk of the project declares the
+// pattern at the top level an inclusion import of the chunk is added.
+func tryGetImportPatternAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	match := PATTERN_NOT_DECLARED_REGEX.FindStringSubmatch(params.diagnostic.Message)
+	if match == nil || params.fls == nil {
+		return
+	}
+	patternName := match[1]
+
+	var declaringChunkPath string
+
+	fsutil.Walk(params.fls, "/", func(path string, info fs.FileInfo, err error) error {
+		if err != nil || declaringChunkPath != "" {
+			return nil
+		}
+		if info.IsDir() || path == params.fpath || !strings.HasSuffix(path, inoxconsts.INOXLANG_FILE_EXTENSION) {
+			return nil
+		}
+
+		chunk, _ := core.ParseFileChunk(path, params.fls)
+		if chunk == nil || chunk.Node.IncludableChunkDesc == nil {
+			return nil
+		}
+
+		for _, stmt := range chunk.Node.Statements {
+			if def, ok := stmt.(*parse.PatternDefinition); ok {
+				if name, ok := def.PatternName(); ok && name == patternName {
+					declaringChunkPath = path
+					return nil
+				}
 			}
+		}
+		return nil
+	})
 
-			actionOk = true
+	if declaringChunkPath == "" {
+		return
+	}
+
+	importPath := declaringChunkPath
+	relativePath, err := filepath.Rel(filepath.Dir(params.fpath), declaringChunkPath)
+	if err == nil && !strings.HasPrefix(relativePath, "..") {
+		importPath = "./" + relativePath
+	}
+
+	//add the import after the last top-level import, or after the manifest.
+
+	chunkNode := params.chunk.Node
+	var previousNode parse.Node
+
+	if chunkNode.Manifest != nil {
+		previousNode = chunkNode.Manifest
+	} else if chunkNode.IncludableChunkDesc != nil {
+		previousNode = chunkNode.IncludableChunkDesc
+	}
+
+	for _, stmt := range chunkNode.Statements {
+		if _, ok := stmt.(*parse.InclusionImportStatement); ok {
+			previousNode = stmt
+		}
+	}
+
+	var textEdit defines.TextEdit
+	if previousNode == nil {
+		textEdit = makeInsertionTextEdit(params.chunk, 0, "import "+importPath+"\n")
+	} else {
+		textEdit = makeInsertionTextEdit(params.chunk, previousNode.Base().Span.End, "\nimport "+importPath)
+	}
+
+	return makeQuickFixAction("Import "+importPath, params.doc, textEdit), true
+}
+
+// tryGetAddManifestParameterAction handles accesses to non-existing module arguments (mod-args.name), a parameter
+// is added to the manifest.
+func tryGetAddManifestParameterAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	_, left, propName, ok := getMemberExprOfNonExistingProp(params)
+	if !ok {
+		return
+	}
+
+	if ident, ok := left.(*parse.IdentifierLiteral); !ok || ident.Name != core.MOD_ARGS_VARNAME {
+		return
+	}
+
+	chunk := params.chunk
+	if chunk.Node.Manifest == nil {
+		return
+	}
+
+	manifestObj, ok := chunk.Node.Manifest.Object.(*parse.ObjectLiteral)
+	if !ok {
+		return
+	}
+
+	indentUnit := params.indentUnit
+	param := propName + ": %str"
+
+	var textEdit defines.TextEdit
+	section, ok := manifestObj.PropValue(core.MANIFEST_PARAMS_SECTION_NAME)
+
+	if ok {
+		sectionObj, ok := section.(*parse.ObjectLiteral)
+		if !ok {
+			return
+		}
+		textEdit, ok = makeTextEditAddObjectProperty(chunk, sectionObj, param, indentUnit+indentUnit, indentUnit)
+		if !ok {
+			return
+		}
+	} else {
+		sectionText := core.MANIFEST_PARAMS_SECTION_NAME + ": {\n" + indentUnit + indentUnit + param + "\n" + indentUnit + "}"
+		textEdit, ok = makeTextEditAddObjectProperty(chunk, manifestObj, sectionText, indentUnit, "")
+		if !ok {
+			return
+		}
+	}
+
+	return makeQuickFixAction("Add Manifest Parameter '"+propName+"'", params.doc, textEdit), true
+}
+
+// tryGetMustCallAction turns a call whose error is not handled into a must call.
+func tryGetMustCallAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	if !strings.Contains(params.diagnostic.Message, symbolic.CALL_MAY_RETURN_ERROR_NOT_HANDLED_EITHER_HANDLE_IT_OR_TURN_THE_CALL_IN_A_MUST_CALL) {
+		return
+	}
+
+	node, _, ok := getSmallestNodeIncludingSpan(params.chunk, params.span)
+	if !ok {
+		return
+	}
+
+	callExpr, ok := node.(*parse.CallExpression)
+	if !ok || callExpr.Must || callExpr.CommandLikeSyntax {
+		return
+	}
+
+	textEdit := makeInsertionTextEdit(params.chunk, callExpr.Callee.Base().Span.End, "!")
+	return makeQuickFixAction("Turn Into a Must Call", params.doc, textEdit), true
+}
+
+// tryGetDeclareGlobalVariableAction declares an undeclared variable as a global variable before the top-level
+// statement containing the reference. The value of the variable is left for the user to fill in: if the client
+// supports snippet edits the cursor is placed on a placeholder, otherwise the value is missing.
+func tryGetDeclareGlobalVariableAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	match := VARIABLE_NOT_DECLARED_REGEX.FindStringSubmatch(params.diagnostic.Message)
+	if match == nil {
+		return
+	}
+	name := match[1]
+
+	var topLevelStmt parse.Node
+	for _, stmt := range params.chunk.Node.Statements {
+		span := stmt.Base().Span
+		if span.Start <= params.span.Start && params.span.Start < span.End {
+			topLevelStmt = stmt
+			break
+		}
+	}
+
+	if topLevelStmt == nil {
+		return
+	}
+
+	start := topLevelStmt.Base().Span.Start
+	indentation := getLineIndentation(params.chunk, start)
+
+	title := "Declare Global Variable '" + name + "'"
+
+	if params.snippetEditSupport {
+		snippet := "globalvar " + name + " = ${1:value}\n" + indentation
+		return makeSnippetQuickFixAction(title, params.doc, makeSnippetInsertionTextEdit(params.chunk, start, snippet)), true
+	}
+
+	textEdit := makeInsertionTextEdit(params.chunk, start, "globalvar "+name+" = nil\n"+indentation)
+	return makeQuickFixAction(title, params.doc, textEdit), true
+}
+
+// tryGetAddObjectPatternPropertyAction handles accesses to non-existing properties of variables whose type is
+// a named object pattern declared in the chunk, the property is added to the pattern.
+func tryGetAddObjectPatternPropertyAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	memberExpr, left, propName, ok := getMemberExprOfNonExistingProp(params)
+	if !ok {
+		return
+	}
+
+	var varName string
+	switch left := left.(type) {
+	case *parse.IdentifierLiteral:
+		varName = left.Name
+	case *parse.Variable:
+		varName = left.Name
+	default:
+		return
+	}
+
+	if varName == core.MOD_ARGS_VARNAME {
+		return
+	}
+
+	chunk := params.chunk
+	patternName, ok := getDeclaredVariablePatternName(chunk, varName, memberExpr.Base().Span.Start)
+	if !ok {
+		return
+	}
+
+	var objectPattern *parse.ObjectPatternLiteral
+
+	for _, stmt := range chunk.Node.Statements {
+		def, ok := stmt.(*parse.PatternDefinition)
+		if !ok {
+			continue
+		}
+		if name, ok := def.PatternName(); ok && name == patternName {
+			objectPattern, _ = def.Right.(*parse.ObjectPatternLiteral)
+			break
+		}
+	}
+
+	if objectPattern == nil {
+		return
+	}
+
+	prop := propName + ": %serializable"
+	span := objectPattern.Span
+	runes := chunk.Runes()
+
+	if span.End-span.Start < 2 || runes[span.Start] != '{' || runes[span.End-1] != '}' {
+		return
+	}
+
+	var textEdit defines.TextEdit
+	if len(objectPattern.Properties) == 0 {
+		textEdit = makeInsertionTextEdit(chunk, span.Start+1, prop)
+	} else {
+		lastProp := objectPattern.Properties[len(objectPattern.Properties)-1]
+		textEdit = makeInsertionTextEdit(chunk, lastProp.Span.End, ", "+prop)
+	}
+
+	return makeQuickFixAction("Add Property ."+propName+" to %"+patternName, params.doc, textEdit), true
+}
+
+// tryGetWrapInTransactionAction handles errors caused by the absence of a transaction, the statement containing
+// the error is wrapped between the start and the commit of a transaction.
+func tryGetWrapInTransactionAction(params quickFixParams) (action defines.CodeAction, actionOk bool) {
+	msg := params.diagnostic.Message
+	if !strings.Contains(msg, core.ErrRunningTransactionExpected.Error()) && !strings.Contains(msg, core.ErrLoadingRequireTransaction.Error()) {
+		return
+	}
+
+	chunk := params.chunk
+	node, ancestors, ok := getSmallestNodeIncludingSpan(chunk, params.span)
+	if !ok {
+		return
+	}
+
+	//find the outermost statement of the innermost block.
+
+	var stmt parse.Node
+	chain := append(slices.Clone(ancestors), node)
+
+	for i := len(chain) - 1; i > 0; i-- {
+		switch chain[i-1].(type) {
+		case *parse.Block, *parse.Chunk:
+			stmt = chain[i]
+		}
+		if stmt != nil {
+			break
 		}
 	}
 
+	if stmt == nil {
+		return
+	}
+
+	span := stmt.Base().Span
+	indentation := getLineIndentation(chunk, span.Start)
+
+	return makeQuickFixAction("Wrap in a Transaction", params.doc,
+		makeInsertionTextEdit(chunk, span.Start, "tx = "+globalnames.START_TX_FN+"()\n"+indentation),
+		makeInsertionTextEdit(chunk, span.End, "\n"+indentation+"tx.commit()"),
+	), true
+}
+
+// getMemberExprOfNonExistingProp returns the member expression (*parse.MemberExpression or *parse.IdentifierMemberExpression)
+// at the diagnostic's span and the left operand whose property does not exist, if the diagnostic reports a non-existing property.
+func getMemberExprOfNonExistingProp(params quickFixParams) (memberExpr parse.Node, left parse.Node, propName string, _ bool) {
+	match := PROP_DOES_NOT_EXIST_REGEX.FindStringSubmatch(params.diagnostic.Message)
+	if match == nil {
+		return nil, nil, "", false
+	}
+	propName = match[1]
+
+	node, ancestors, ok := getSmallestNodeIncludingSpan(params.chunk, params.span)
+	if !ok {
+		return nil, nil, "", false
+	}
+
+	if _, ok := node.(*parse.IdentifierLiteral); ok && len(ancestors) > 0 {
+		//the span of the diagnostic may be the span of the property name.
+		node = ancestors[len(ancestors)-1]
+	}
+
+	switch n := node.(type) {
+	case *parse.MemberExpression:
+		if n.PropertyName != nil && n.PropertyName.Name == propName {
+			return n, n.Left, propName, true
+		}
+	case *parse.IdentifierMemberExpression:
+		if len(n.PropertyNames) == 1 && n.PropertyNames[0].Name == propName {
+			return n, n.Left, propName, true
+		}
+	}
+
+	return nil, nil, "", false
+}
+
+// getDeclaredVariablePatternName searches for the last declaration (variable declaration or function parameter)
+// of a variable located before $beforePos and returns the name of its type if the type is a named pattern.
+func getDeclaredVariablePatternName(chunk *parse.ParsedChunkSource, name string, beforePos int32) (patternName string, found bool) {
+	parse.Walk(chunk.Node, func(node, _, _ parse.Node, _ []parse.Node, _ bool) (parse.TraversalAction, error) {
+		if node.Base().Span.Start >= beforePos {
+			return parse.Prune, nil
+		}
+
+		var left, typ parse.Node
+
+		switch n := node.(type) {
+		case *parse.LocalVariableDeclaration:
+			left, typ = n.Left, n.Type
+		case *parse.GlobalVariableDeclaration:
+			left, typ = n.Left, n.Type
+		case *parse.FunctionParameter:
+			if n.Var != nil {
+				left, typ = n.Var, n.Type
+			}
+		default:
+			return parse.ContinueTraversal, nil
+		}
+
+		ident, ok := left.(*parse.IdentifierLiteral)
+		if !ok || ident.Name != name {
+			return parse.ContinueTraversal, nil
+		}
+
+		if patternIdent, ok := typ.(*parse.PatternIdentifierLiteral); ok {
+			patternName = patternIdent.Name
+			found = true
+		} else {
+			patternName = ""
+			found = false
+		}
+		return parse.ContinueTraversal, nil
+	}, nil)
+
 	return
 }
 
+// getSmallestNodeIncludingSpan returns the deepest node whose span includes $span.
+func getSmallestNodeIncludingSpan(chunk *parse.ParsedChunkSource, span parse.NodeSpan) (foundNode parse.Node, ancestors []parse.Node, ok bool) {
+	parse.Walk(chunk.Node, func(node, _, _ parse.Node, chain []parse.Node, _ bool) (parse.TraversalAction, error) {
+		nodeSpan := node.Base().Span
+
+		if nodeSpan.Start > span.Start || nodeSpan.End < span.End {
+			return parse.Prune, nil
+		}
+
+		foundNode = node
+		ancestors = slices.Clone(chain)
+		ok = true
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return
+}
+
+// makeTextEditAddObjectProperty makes a text edit that adds a property at the end of an object literal,
+// innerIndent is the indentation of the properties and outerIndent the indentation of the closing brace.
+func makeTextEditAddObjectProperty(chunk *parse.ParsedChunkSource, obj *parse.ObjectLiteral, prop string, innerIndent, outerIndent string) (defines.TextEdit, bool) {
+	span := obj.Span
+	runes := chunk.Runes()
+
+	if span.End-span.Start < 2 || runes[span.Start] != '{' || runes[span.End-1] != '}' {
+		return defines.TextEdit{}, false
+	}
+
+	if len(obj.Properties) == 0 {
+		//replace the content of the object.
+		startLine, startCol := chunk.GetSpanLineColumn(parse.NodeSpan{Start: span.Start + 1, End: span.Start + 1})
+		endLine, endCol := chunk.GetSpanLineColumn(parse.NodeSpan{Start: span.End - 1, End: span.End - 1})
+
+		return defines.TextEdit{
+			Range: rangeToLspRange(parse.SourcePositionRange{
+				StartLine:   startLine,
+				StartColumn: startCol,
+				EndLine:     endLine,
+				EndColumn:   endCol,
+				Span:        parse.NodeSpan{Start: span.Start + 1, End: span.End - 1},
+			}),
+			NewText: "\n" + innerIndent + prop + "\n" + outerIndent,
+		}, true
+	}
+
+	lastProp := obj.Properties[len(obj.Properties)-1]
+	return makeInsertionTextEdit(chunk, lastProp.Span.End, "\n"+innerIndent+prop), true
+}
+
+// makeSnippetInsertionTextEdit makes a snippet text edit that inserts snippet at pos.
+func makeSnippetInsertionTextEdit(chunk *parse.ParsedChunkSource, pos int32, snippet string) defines.SnippetTextEdit {
+	textEdit := makeInsertionTextEdit(chunk, pos, "")
+
+	return defines.SnippetTextEdit{
+		Range: textEdit.Range,
+		Snippet: defines.StringValue{
+			Kind:  "snippet",
+			Value: snippet,
+		},
+	}
+}
+
+// makeInsertionTextEdit makes a text edit that inserts text at pos.
+func makeInsertionTextEdit(chunk *parse.ParsedChunkSource, pos int32, text string) defines.TextEdit {
+	span := parse.NodeSpan{Start: pos, End: pos}
+	line, col := chunk.GetSpanLineColumn(span)
+
+	return defines.TextEdit{
+		Range: rangeToLspRange(parse.SourcePositionRange{
+			StartLine:   line,
+			StartColumn: col,
+			EndLine:     line,
+			EndColumn:   col,
+			Span:        span,
+		}),
+		NewText: text,
+	}
+}
+
+// getLineIndentation returns the leading whitespace of the line containing pos.
+func getLineIndentation(chunk *parse.ParsedChunkSource, pos int32) string {
+	runes := chunk.Runes()
+	lineStart := pos
+	for lineStart > 0 && runes[lineStart-1] != '\n' {
+		lineStart--
+	}
+
+	end := lineStart
+	for end < int32(len(runes)) && (runes[end] == ' ' || runes[end] == '\t') {
+		end++
+	}
+	return string(runes[lineStart:end])
+}
+
 func getPermissionsObject(chunk *parse.ParsedChunkSource) (*parse.ObjectLiteral, bool) {
 	if chunk.Node.Manifest == nil {
 		return nil, false
@@ -0,0 +1,233 @@
+package projectserver
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/globals/fs_ns"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCodeActions(t *testing.T) {
+	const MAIN_URI = "inox:///main.ix"
+
+	//getActions returns the code actions for a diagnostic located at the first occurrence of $diagnosticCode.
+	getActions := func(t *testing.T, files map[string]string, diagnosticCode string, message string, severity defines.DiagnosticSeverity) ([]defines.CodeAction, string) {
+		fls := fs_ns.NewMemFilesystem(100_000)
+		for path, content := range files {
+			fsutil.WriteFile(fls, path, []byte(content), 0600)
+		}
+
+		code := files["/main.ix"]
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{NameString: "/main.ix", CodeString: code}))
+
+		start := int32(len([]rune(code[:strings.Index(code, diagnosticCode)])))
+		span := parse.NodeSpan{Start: start, End: start + int32(len([]rune(diagnosticCode)))}
+
+		diagnostic := defines.Diagnostic{
+			Range:    rangeToLspRange(chunk.GetSourcePosition(span)),
+			Severity: &severity,
+			Message:  message,
+		}
+
+		doc := defines.TextDocumentIdentifier{Uri: MAIN_URI}
+		actions, err := getCodeActions(nil, []defines.Diagnostic{diagnostic}, diagnostic.Range, doc, "/main.ix", NewFilesystem(fls, fs_ns.NewMemFilesystem(10_000)))
+		if !assert.NoError(t, err) {
+			return nil, code
+		}
+		return *actions, code
+	}
+
+	//applyEdits returns the code of /main.ix after the edits of the action.
+	applyEdits := func(code string, action defines.CodeAction) string {
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{NameString: "/main.ix", CodeString: code}))
+
+		edits := slices.Clone((*action.Edit.Changes)[MAIN_URI])
+		slices.SortFunc(edits, func(a, b defines.TextEdit) int {
+			startA, _ := getLineColumn(a.Range.Start)
+			startB, _ := getLineColumn(b.Range.Start)
+			if startA == startB {
+				return int(a.Range.Start.Character) - int(b.Range.Start.Character)
+			}
+			return int(startA - startB)
+		})
+
+		runes := []rune(code)
+		for i := len(edits) - 1; i >= 0; i-- {
+			edit := edits[i]
+			start := chunk.GetLineColumnPosition(getLineColumn(edit.Range.Start))
+			end := chunk.GetLineColumnPosition(getLineColumn(edit.Range.End))
+			runes = append(append(slices.Clone(runes[:start]), []rune(edit.NewText)...), runes[end:]...)
+		}
+		return string(runes)
+	}
+
+	t.Run("import a pattern", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix":         "manifest {}\nvar u %user = {name: \"a\"}",
+			"/patterns/lib.ix": "includable-chunk\npattern user = {name: str}",
+			"/other.ix":        "includable-chunk\npattern other = {}",
+		}, "%user", "pattern %user is not declared", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Import ./patterns/lib.ix", actions[0].Title)
+		assert.Equal(t, "manifest {}\nimport ./patterns/lib.ix\nvar u %user = {name: \"a\"}", applyEdits(code, actions[0]))
+	})
+
+	t.Run("add a missing manifest parameter", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix": "manifest {\n    parameters: {\n        a: %str\n    }\n}\nmod-args.b",
+		}, "mod-args.b", "property .b does not exist in %module-arguments{a: %string-like}", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Add Manifest Parameter 'b'", actions[0].Title)
+		assert.Equal(t,
+			"manifest {\n    parameters: {\n        a: %str\n        b: %str\n    }\n}\nmod-args.b",
+			applyEdits(code, actions[0]),
+		)
+
+		//no parameters section
+		actions, code = getActions(t, map[string]string{
+			"/main.ix": "manifest {}\nmod-args.b",
+		}, "mod-args.b", "property .b does not exist in %module-arguments{ }", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t,
+			"manifest {\n    parameters: {\n        b: %str\n    }\n}\nmod-args.b",
+			applyEdits(code, actions[0]),
+		)
+	})
+
+	t.Run("turn a call into a must call", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\nf(1)",
+		}, "f(1)", symbolic.CALL_MAY_RETURN_ERROR_NOT_HANDLED_EITHER_HANDLE_IT_OR_TURN_THE_CALL_IN_A_MUST_CALL, defines.DiagnosticSeverityWarning)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Turn Into a Must Call", actions[0].Title)
+		assert.Equal(t, "manifest {}\nf!(1)", applyEdits(code, actions[0]))
+	})
+
+	t.Run("declare an undefined global", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\na = 1\nfn f(){\n    return count\n}",
+		}, "count", "variable 'count' is not declared", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Declare Global Variable 'count'", actions[0].Title)
+		//the value is left for the user to fill in.
+		assert.Equal(t, "manifest {}\na = 1\nglobalvar count = nil\nfn f(){\n    return count\n}", applyEdits(code, actions[0]))
+
+		//local variables are not declared as globals.
+		actions, _ = getActions(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(){\n    return $count\n}",
+		}, "$count", "local variable 'count' is not declared", defines.DiagnosticSeverityError)
+		assert.Empty(t, actions)
+	})
+
+	t.Run("declare an undefined global: client supporting snippet edits", func(t *testing.T) {
+		code := "manifest {}\nfn f(){\n    return count\n}"
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{NameString: "/main.ix", CodeString: code}))
+
+		start := int32(strings.Index(code, "count"))
+		doc := defines.TextDocumentIdentifier{Uri: MAIN_URI}
+
+		action, ok := tryGetDeclareGlobalVariableAction(quickFixParams{
+			doc:                doc,
+			fpath:              "/main.ix",
+			diagnostic:         defines.Diagnostic{Message: "variable 'count' is not declared"},
+			span:               parse.NodeSpan{Start: start, End: start + 5},
+			chunk:              chunk,
+			snippetEditSupport: true,
+		})
+
+		if !assert.True(t, ok) {
+			return
+		}
+
+		assert.Nil(t, action.Edit.Changes)
+		assert.Equal(t, &[]interface{}{
+			defines.TextDocumentEdit{
+				TextDocument: defines.OptionalVersionedTextDocumentIdentifier{TextDocumentIdentifier: doc},
+				Edits: []interface{}{
+					defines.SnippetTextEdit{
+						Range: defines.Range{
+							Start: defines.Position{Line: 1, Character: 0},
+							End:   defines.Position{Line: 1, Character: 0},
+						},
+						Snippet: defines.StringValue{Kind: "snippet", Value: "globalvar count = ${1:value}\n"},
+					},
+				},
+			},
+		}, action.Edit.DocumentChanges)
+	})
+
+	t.Run("add a missing property to an object pattern", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\npattern user = {name: str}\nfn f(u %user){\n    return u.age\n}",
+		}, "u.age", "property .age does not exist in {\"name\": %string-like}", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Add Property .age to %user", actions[0].Title)
+		assert.Equal(t, "manifest {}\npattern user = {name: str, age: %serializable}\nfn f(u %user){\n    return u.age\n}", applyEdits(code, actions[0]))
+
+		//variable declaration
+		actions, code = getActions(t, map[string]string{
+			"/main.ix": "manifest {}\npattern user = {}\nvar u %user = {}\nu.age",
+		}, "u.age", "property .age does not exist in {}", defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "manifest {}\npattern user = {age: %serializable}\nvar u %user = {}\nu.age", applyEdits(code, actions[0]))
+	})
+
+	t.Run("wrap code in a transaction", func(t *testing.T) {
+		actions, code := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(){\n    db.update()\n}",
+		}, "db.update()", core.ErrRunningTransactionExpected.Error(), defines.DiagnosticSeverityError)
+
+		if !assert.Len(t, actions, 1) {
+			return
+		}
+		assert.Equal(t, "Wrap in a Transaction", actions[0].Title)
+		assert.Equal(t, "manifest {}\nfn f(){\n    tx = start_tx()\n    db.update()\n    tx.commit()\n}", applyEdits(code, actions[0]))
+	})
+
+	t.Run("missing permission", func(t *testing.T) {
+		actions, _ := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\ngo do {}",
+		}, "go do {}", symbolic.POSSIBLE_MISSING_PERM_TO_CREATE_A_LTHREAD, defines.DiagnosticSeverityWarning)
+
+		if assert.Len(t, actions, 1) {
+			assert.Equal(t, "Add Missing Permission", actions[0].Title)
+		}
+	})
+
+	t.Run("unrelated diagnostic", func(t *testing.T) {
+		actions, _ := getActions(t, map[string]string{
+			"/main.ix": "manifest {}\n1",
+		}, "1", "some error", defines.DiagnosticSeverityError)
+
+		assert.Empty(t, actions)
+	})
+}
@@ -0,0 +1,364 @@
+package projectserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/inoxlang/inox/internal/core"
+	httpspec "github.com/inoxlang/inox/internal/globals/http_ns/spec"
+	"github.com/inoxlang/inox/internal/inoxconsts"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/lsp"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	//commands executed by the server (workspace/executeCommand).
+
+	RUN_TEST_ITEM_COMMAND           = "inox.testing.runItem"
+	OPEN_HANDLER_MODULE_URL_COMMAND = "inox.http.openHandlerURL"
+
+	//commands executed by the client, the only argument is a DebugLaunchArgs that should be used
+	//to start a debug session.
+
+	DEBUG_TEST_ITEM_CLIENT_COMMAND = "inox.testing.debugItem"
+
+	DEV_SERVER_BASE_URL = "https://localhost:" + inoxconsts.DEFAULT_DEV_APP_PORT
+
+	MAX_TEST_RESULT_LENS_MESSAGE_LENGTH = 60
+)
+
+// TestItemCommandArgs is the argument of RUN_TEST_ITEM_COMMAND.
+type TestItemCommandArgs struct {
+	Path     string         `json:"path"`
+	NodeSpan parse.NodeSpan `json:"span"`
+}
+
+func (a TestItemCommandArgs) filter() TestFilter {
+	return TestFilter{
+		Regex:        ".*",
+		AbsolutePath: a.Path,
+		NodeSpan:     a.NodeSpan,
+	}
+}
+
+// testItemResults stores the result of the last execution of each test suite and test case,
+// the results are displayed in the titles of the code lenses.
+type testItemResults struct {
+	lock    sync.Mutex
+	results map[string] /*path*/ map[parse.NodeSpan]testItemResult
+}
+
+type testItemResult struct {
+	success bool
+	message string //first line of the message, can be empty
+}
+
+func newTestItemResults() *testItemResults {
+	return &testItemResults{
+		results: map[string]map[parse.NodeSpan]testItemResult{},
+	}
+}
+
+// record stores the results of the test suites and of their test cases.
+func (r *testItemResults) record(suiteResults []*core.TestSuiteResult) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, suiteResult := range suiteResults {
+		r.recordSuiteResult(suiteResult)
+	}
+}
+
+func (r *testItemResults) recordSuiteResult(suiteResult *core.TestSuiteResult) {
+	if suite := suiteResult.TestSuite(); suite != nil {
+		r.set(suite, testItemResult{success: suiteResult.Success})
+	}
+
+	for _, caseResult := range suiteResult.CaseResults() {
+		testCase := caseResult.TestCase()
+		if testCase == nil {
+			continue
+		}
+		r.set(testCase, testItemResult{
+			success: caseResult.Success,
+			message: firstLine(caseResult.FailureMessage()),
+		})
+	}
+
+	for _, subSuiteResult := range suiteResult.SubSuiteResults() {
+		r.recordSuiteResult(subSuiteResult)
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
+
+func (r *testItemResults) set(item core.TestItem, result testItemResult) {
+	chunk := item.ParentChunk()
+	if chunk == nil {
+		return
+	}
+	path := chunk.Name()
+
+	results := r.results[path]
+	if results == nil {
+		results = map[parse.NodeSpan]testItemResult{}
+		r.results[path] = results
+	}
+	results[item.Statement().Base().Span] = result
+}
+
+func (r *testItemResults) get(path string, span parse.NodeSpan) (testItemResult, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result, ok := r.results[path][span]
+	return result, ok
+}
+
+// invalidate removes the results of the test items in the file at path, it should be called when the file
+// is modified because the spans of the test items may have changed.
+func (r *testItemResults) invalidate(path string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.results, path)
+}
+
+func registerCodeLensMethodHandlers(server *lsp.Server) {
+	server.OnCodeLens(handleCodeLens)
+	server.OnExecuteCommand(handleExecuteCommand)
+}
+
+func handleCodeLens(ctx context.Context, req *defines.CodeLensParams) (result *[]defines.CodeLens, err error) {
+	session := jsonrpc.GetSession(ctx)
+
+	//----------------------------------------
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	results := sessionData.testItemResults
+	routesDir := ""
+	if sessionData.serverAPI != nil {
+		routesDir = sessionData.serverAPI.dynamicDir
+	}
+	sessionData.lock.Unlock()
+	//----------------------------------------
+
+	lenses := []defines.CodeLens{}
+
+	if fls == nil {
+		return &lenses, nil
+	}
+
+	fpath, err := getFilePath(req.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk, _ := core.ParseFileChunk(fpath, fls)
+	if chunk == nil { //unrecoverable error
+		return &lenses, nil
+	}
+
+	lenses = append(lenses, getTestItemCodeLenses(fpath, chunk, results)...)
+
+	if routesDir != "" {
+		lenses = append(lenses, getHandlerModuleCodeLenses(fpath, chunk, routesDir)...)
+	}
+
+	return &lenses, nil
+}
+
+// getTestItemCodeLenses returns the lenses allowing to run or debug the test suites and test cases of a chunk.
+// If the item has already been executed its result is displayed in the title of the run lens.
+func getTestItemCodeLenses(fpath string, chunk *parse.ParsedChunkSource, results *testItemResults) (lenses []defines.CodeLens) {
+	parse.Walk(chunk.Node, func(node, parent, scopeNode parse.Node, ancestorChain []parse.Node, after bool) (parse.TraversalAction, error) {
+		var keyword string
+
+		switch node.(type) {
+		case *parse.TestSuiteExpression:
+			keyword = "testsuite"
+		case *parse.TestCaseExpression:
+			keyword = "testcase"
+		default:
+			return parse.ContinueTraversal, nil
+		}
+
+		span := node.Base().Span
+		keywordSpan := parse.NodeSpan{Start: span.Start, End: min(span.End, span.Start+int32(len(keyword)))}
+		lensRange := rangeToLspRange(chunk.GetSourcePosition(keywordSpan))
+
+		args := TestItemCommandArgs{Path: fpath, NodeSpan: span}
+
+		runTitle := "Run " + keyword
+		if result, ok := results.get(fpath, span); ok {
+			runTitle = formatTestItemResult(result) + "  " + runTitle
+		}
+
+		debugArgs := DebugLaunchArgs{
+			Program:     fpath,
+			TestFilters: []TestFilter{args.filter()},
+		}
+
+		lenses = append(lenses, defines.CodeLens{
+			Range: lensRange,
+			Command: &defines.Command{
+				Title:     runTitle,
+				Command:   RUN_TEST_ITEM_COMMAND,
+				Arguments: &[]interface{}{args},
+			},
+		}, defines.CodeLens{
+			Range: lensRange,
+			Command: &defines.Command{
+				Title:     "Debug " + keyword,
+				Command:   DEBUG_TEST_ITEM_CLIENT_COMMAND,
+				Arguments: &[]interface{}{debugArgs},
+			},
+		})
+
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return
+}
+
+func formatTestItemResult(result testItemResult) string {
+	if result.success {
+		return "✔ passed"
+	}
+	if result.message == "" {
+		return "✘ failed"
+	}
+	msg := []rune(result.message)
+	if len(msg) > MAX_TEST_RESULT_LENS_MESSAGE_LENGTH {
+		return "✘ failed: " + string(msg[:MAX_TEST_RESULT_LENS_MESSAGE_LENGTH]) + "..."
+	}
+	return "✘ failed: " + string(msg)
+}
+
+// getHandlerModuleCodeLenses returns a lens allowing to open the URL of the endpoint implemented by a handler
+// module of the dev server. No lens is returned if the chunk is not a handler module, if the endpoint has
+// path parameters or if the module does not handle GET requests.
+func getHandlerModuleCodeLenses(fpath string, chunk *parse.ParsedChunkSource, routesDir string) []defines.CodeLens {
+	if chunk.Node.Manifest == nil {
+		return nil
+	}
+
+	endpointPath, method, ok := httpspec.GetFSRoutingHandlerEndpoint(fpath, routesDir)
+	if !ok || (method != "" && method != "GET") || strings.Contains(endpointPath, "{") {
+		return nil
+	}
+
+	url := DEV_SERVER_BASE_URL + endpointPath
+	manifestSpan := chunk.Node.Manifest.Span
+
+	return []defines.CodeLens{
+		{
+			Range: rangeToLspRange(chunk.GetSourcePosition(parse.NodeSpan{Start: manifestSpan.Start, End: manifestSpan.Start + 1})),
+			Command: &defines.Command{
+				Title:     "Open " + endpointPath,
+				Command:   OPEN_HANDLER_MODULE_URL_COMMAND,
+				Arguments: &[]interface{}{url},
+			},
+		},
+	}
+}
+
+func handleExecuteCommand(ctx context.Context, req *defines.ExecuteCommandParams) error {
+	session := jsonrpc.GetSession(ctx)
+
+	var args []interface{}
+	if req.Arguments != nil {
+		args = *req.Arguments
+	}
+
+	if len(args) != 1 {
+		return jsonrpc.ResponseError{
+			Code:    jsonrpc.InvalidParams.Code,
+			Message: fmt.Sprintf("the command %s expects a single argument", req.Command),
+		}
+	}
+
+	switch req.Command {
+	case RUN_TEST_ITEM_COMMAND:
+		var testItemArgs TestItemCommandArgs
+		if err := decodeCommandArgument(args[0], &testItemArgs); err != nil {
+			return err
+		}
+
+		filters := TestFileParams{PositiveFilters: []TestFilter{testItemArgs.filter()}}.Filters()
+		_, err := startTestRun(testItemArgs.Path, filters, benchmarkingConfig{}, false, session)
+		return err
+	case OPEN_HANDLER_MODULE_URL_COMMAND:
+		url, ok := args[0].(string)
+		if !ok || !strings.HasPrefix(url, DEV_SERVER_BASE_URL+"/") {
+			return jsonrpc.ResponseError{
+				Code:    jsonrpc.InvalidParams.Code,
+				Message: "invalid URL",
+			}
+		}
+
+		return session.SendRequest(jsonrpc.RequestMessage{
+			Method: "window/showDocument",
+			ID:     uuid.New(),
+			Params: utils.Must(json.Marshal(defines.ShowDocumentParams{
+				Uri:      defines.URI(url),
+				External: &True,
+			})),
+		})
+	default:
+		return jsonrpc.ResponseError{
+			Code:    jsonrpc.MethodNotFound.Code,
+			Message: fmt.Sprintf("unknown command %q", req.Command),
+		}
+	}
+}
+
+func decodeCommandArgument(arg interface{}, v any) error {
+	data, err := json.Marshal(arg)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return jsonrpc.ResponseError{
+			Code:    jsonrpc.InvalidParams.Code,
+			Message: "invalid command argument: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+// recordTestResultsAndRefreshCodeLenses stores the results of the test items executed by state and asks the client
+// to refresh the code lenses so that the results are displayed.
+func recordTestResultsAndRefreshCodeLenses(state *core.GlobalState, session *jsonrpc.Session) {
+	if state == nil || state.TestingState.SuiteResults == nil {
+		return
+	}
+
+	sessionData := getLockedSessionData(session)
+	results := sessionData.testItemResults
+	workspaceCapabilities := sessionData.clientCapabilities.Workspace
+	sessionData.lock.Unlock()
+
+	results.record(state.TestingState.SuiteResults)
+
+	if workspaceCapabilities == nil || workspaceCapabilities.CodeLens == nil ||
+		workspaceCapabilities.CodeLens.RefreshSupport == nil || !*workspaceCapabilities.CodeLens.RefreshSupport {
+		return
+	}
+
+	session.SendRequest(jsonrpc.RequestMessage{
+		Method: "workspace/codeLens/refresh",
+		ID:     uuid.New(),
+	})
+}
@@ -0,0 +1,98 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCodeLenses(t *testing.T) {
+	getTitles := func(lenses []defines.CodeLens) (titles []string) {
+		for _, lens := range lenses {
+			titles = append(titles, lens.Command.Title)
+		}
+		return
+	}
+
+	t.Run("test items", func(t *testing.T) {
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "/main.spec.ix",
+			CodeString: "manifest {}\ntestsuite \"suite\" {\n  testcase {}\n}",
+		}))
+
+		results := newTestItemResults()
+		lenses := getTestItemCodeLenses("/main.spec.ix", chunk, results)
+
+		assert.Equal(t, []string{"Run testsuite", "Debug testsuite", "Run testcase", "Debug testcase"}, getTitles(lenses))
+		if !assert.Len(t, lenses, 4) {
+			return
+		}
+
+		suiteSpan := chunk.Node.Statements[0].Base().Span
+		assert.Equal(t, defines.Position{Line: 1, Character: 0}, lenses[0].Range.Start)
+		assert.Equal(t, RUN_TEST_ITEM_COMMAND, lenses[0].Command.Command)
+		assert.Equal(t, []interface{}{TestItemCommandArgs{Path: "/main.spec.ix", NodeSpan: suiteSpan}}, *lenses[0].Command.Arguments)
+
+		assert.Equal(t, DEBUG_TEST_ITEM_CLIENT_COMMAND, lenses[1].Command.Command)
+		assert.Equal(t, []interface{}{
+			DebugLaunchArgs{
+				Program:     "/main.spec.ix",
+				TestFilters: []TestFilter{{Regex: ".*", AbsolutePath: "/main.spec.ix", NodeSpan: suiteSpan}},
+			},
+		}, *lenses[1].Command.Arguments)
+
+		assert.Equal(t, defines.Position{Line: 2, Character: 2}, lenses[2].Range.Start)
+
+		//results of a previous run
+
+		caseSpan := (*lenses[2].Command.Arguments)[0].(TestItemCommandArgs).NodeSpan
+		results.results["/main.spec.ix"] = map[parse.NodeSpan]testItemResult{
+			suiteSpan: {success: false},
+			caseSpan:  {success: false, message: "assertion failed"},
+		}
+
+		lenses = getTestItemCodeLenses("/main.spec.ix", chunk, results)
+		assert.Equal(t, []string{
+			"✘ failed  Run testsuite", "Debug testsuite",
+			"✘ failed: assertion failed  Run testcase", "Debug testcase",
+		}, getTitles(lenses))
+
+		results.invalidate("/main.spec.ix")
+
+		lenses = getTestItemCodeLenses("/main.spec.ix", chunk, results)
+		assert.Equal(t, []string{"Run testsuite", "Debug testsuite", "Run testcase", "Debug testcase"}, getTitles(lenses))
+	})
+
+	t.Run("handler modules", func(t *testing.T) {
+		module := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "/routes/users/GET.ix",
+			CodeString: "manifest {}\nreturn []",
+		}))
+
+		lenses := getHandlerModuleCodeLenses("/routes/users/GET.ix", module, "/routes/")
+		if assert.Len(t, lenses, 1) {
+			assert.Equal(t, "Open /users", lenses[0].Command.Title)
+			assert.Equal(t, OPEN_HANDLER_MODULE_URL_COMMAND, lenses[0].Command.Command)
+			assert.Equal(t, []interface{}{"https://localhost:8080/users"}, *lenses[0].Command.Arguments)
+		}
+
+		//POST handler
+		lenses = getHandlerModuleCodeLenses("/routes/users/POST.ix", module, "/routes/")
+		assert.Empty(t, lenses)
+
+		//endpoint with a path parameter
+		lenses = getHandlerModuleCodeLenses("/routes/users/:user-id/GET.ix", module, "/routes/")
+		assert.Empty(t, lenses)
+
+		//not a module
+		includedChunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "/routes/users/GET.ix",
+			CodeString: "includable-chunk",
+		}))
+		lenses = getHandlerModuleCodeLenses("/routes/users/GET.ix", includedChunk, "/routes/")
+		assert.Empty(t, lenses)
+	})
+}
@@ -28,6 +28,8 @@ const (
 	GET_STACK_TRACE_METHOD           = "debug/stackTrace"
 	GET_SCOPES_METHOD                = "debug/scopes"
 	GET_VARIABLES_METHOD             = "debug/variables"
+	DEBUG_EVALUATE_METHOD            = "debug/evaluate"
+	SET_VARIABLE_METHOD              = "debug/setVariable"
 	SET_BREAKPOINTS_METHOD           = "debug/setBreakpoints"
 	SET_EXCEPTION_BREAKPOINTS_METHOD = "debug/setExceptionBreakpoints"
 	DEBUG_PAUSE_METHOD               = "debug/pause"
@@ -112,6 +114,16 @@ type DebugVariablesParams struct {
 	Request   dap.VariablesRequest `json:"request"`
 }
 
+type DebugEvaluateParams struct {
+	SessionId string              `json:"sessionID"`
+	Request   dap.EvaluateRequest `json:"request"`
+}
+
+type DebugSetVariableParams struct {
+	SessionId string                 `json:"sessionID"`
+	Request   dap.SetVariableRequest `json:"request"`
+}
+
 type DebugSetBreakpointsParams struct {
 	SessionId string                    `json:"sessionID"`
 	Request   dap.SetBreakpointsRequest `json:"request"`
@@ -125,6 +137,9 @@ type DebugSetExceptionBreakpointsParams struct {
 type DebugLaunchArgs struct {
 	Program   string                                                       `json:"program"`
 	LogLevels map[ /*'default' | 'enableInternalDebug' | path*/ string]any `json:"logLevels,omitempty"`
+
+	//if not empty the program is executed in testing mode and only the matching tests are executed.
+	TestFilters []TestFilter `json:"testFilters,omitempty"`
 }
 
 type DebugDisconnectParams struct {
@@ -197,6 +212,22 @@ func registerDebugMethodHandlers(
 		Handler: handleGetVariables,
 	})
 
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: DEBUG_EVALUATE_METHOD,
+		NewRequest: func() interface{} {
+			return &DebugEvaluateParams{}
+		},
+		Handler: handleDebugEvaluate,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: SET_VARIABLE_METHOD,
+		NewRequest: func() interface{} {
+			return &DebugSetVariableParams{}
+		},
+		Handler: handleSetVariable,
+	})
+
 	server.OnCustom(jsonrpc.MethodInfo{
 		Name: SET_BREAKPOINTS_METHOD,
 		NewRequest: func() interface{} {
@@ -283,6 +314,7 @@ func breakpointInfoToDebugAdapterProtocolBreakpoint(breakpoint core.BreakpointIn
 		Id:       int(breakpoint.Id),
 		Line:     int(breakpoint.StartLine),
 		Column:   int(breakpoint.StartColumn),
+		Message:  breakpoint.Message,
 	}
 
 	if !columnsStartAt1 {
@@ -426,6 +458,9 @@ func startDebugEventSenders(debugSession *DebugSession, session *jsonrpc.Session
 						})),
 					})
 					continue
+				case core.LogpointHitEvent:
+					notifyOutputEvent(e.Message+"\n", ConsoleDebugEvent, debugSession, session)
+					continue
 				}
 
 				//TODO: check format of event type
@@ -486,6 +521,11 @@ func handleInitializeDebug(ctx context.Context, req interface{}) (interface{}, e
 		Body: dap.Capabilities{
 			SupportsConfigurationDoneRequest:      true,
 			SupportsSingleThreadExecutionRequests: true,
+			SupportsConditionalBreakpoints:        true,
+			SupportsHitConditionalBreakpoints:     true,
+			SupportsLogPoints:                     true,
+			SupportsEvaluateForHovers:             true,
+			SupportsSetVariable:                   true,
 			ExceptionBreakpointFilters: []dap.ExceptionBreakpointsFilter{
 				{
 					Filter: EXCEPTION_ERROR_FILTER,
@@ -667,6 +707,7 @@ func handleDebugLaunch(ctx context.Context, req interface{}) (interface{}, error
 	go launchDebuggedProgram(debuggedProgramLaunch{
 		programPath:  programPath,
 		logLevels:    logLevels,
+		testFilters:  TestFileParams{PositiveFilters: launchArgs.TestFilters}.Filters(),
 		session:      session,
 		debugSession: debugSession,
 		fls:          fls,
@@ -1051,6 +1092,181 @@ func handleGetVariables(ctx context.Context, req interface{}) (interface{}, erro
 	}, nil
 }
 
+func handleDebugEvaluate(ctx context.Context, req interface{}) (interface{}, error) {
+	session := jsonrpc.GetSession(ctx)
+	params := req.(*DebugEvaluateParams)
+	dapRequest := params.Request
+
+	failure := func(seq int, msg string) dap.EvaluateResponse {
+		return dap.EvaluateResponse{
+			Response: dap.Response{
+				RequestSeq: dapRequest.Seq,
+				Success:    false,
+				ProtocolMessage: dap.ProtocolMessage{
+					Seq:  seq,
+					Type: "response",
+				},
+				Message: msg,
+				Command: dapRequest.Command,
+			},
+		}
+	}
+
+	debugSession, err := getDebugSession(session, params.SessionId)
+
+	if err != nil {
+		return failure(0, err.Error()), nil
+	}
+
+	if dapRequest.Arguments.FrameId == 0 {
+		return failure(debugSession.NextSeq(), "failed to evaluate: expressions can only be evaluated in a stack frame"), nil
+	}
+
+	threadId, ok := debugSession.debugger.ThreadIfOfStackFrame(int32(dapRequest.Arguments.FrameId))
+	if !ok {
+		return failure(debugSession.NextSeq(), "failed to evaluate: failed to find thread of stack frame"), nil
+	}
+
+	expr, ok := core.ParseDebugExpression(dapRequest.Arguments.Expression)
+	if !ok {
+		return failure(debugSession.NextSeq(), "failed to evaluate: invalid expression"), nil
+	}
+
+	//the channels are buffered in order to not block the debuggee if the request times out.
+	resultChan := make(chan dap.EvaluateResponseBody, 1)
+	errChan := make(chan error, 1)
+
+	debugSession.debugger.ControlChan() <- core.DebugCommandEvaluate{
+		ThreadId: threadId,
+		FrameId:  int32(dapRequest.Arguments.FrameId),
+		Expr:     expr,
+		//hovering should never change the state of the program.
+		DisallowSideEffects: dapRequest.Arguments.Context == "hover",
+		Get: func(result core.Value, err error) {
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			handlingCtx := session.Context().BoundChild()
+			defer handlingCtx.CancelGracefully()
+
+			resultChan <- dap.EvaluateResponseBody{
+				Result: core.Stringify(result, handlingCtx),
+			}
+		},
+	}
+
+	var body dap.EvaluateResponseBody
+
+	select {
+	case body = <-resultChan:
+	case err := <-errChan:
+		return failure(debugSession.NextSeq(), "failed to evaluate: "+err.Error()), nil
+	case <-time.After(DEFAULT_DEBUG_COMMAND_TIMEOUT):
+		return failure(debugSession.NextSeq(), "failed to evaluate"), nil
+	}
+
+	return dap.EvaluateResponse{
+		Response: dap.Response{
+			RequestSeq: dapRequest.Seq,
+			Success:    true,
+			ProtocolMessage: dap.ProtocolMessage{
+				Seq:  debugSession.NextSeq(),
+				Type: "response",
+			},
+			Command: dapRequest.Command,
+		},
+		Body: body,
+	}, nil
+}
+
+func handleSetVariable(ctx context.Context, req interface{}) (interface{}, error) {
+	session := jsonrpc.GetSession(ctx)
+	params := req.(*DebugSetVariableParams)
+	dapRequest := params.Request
+
+	failure := func(seq int, msg string) dap.SetVariableResponse {
+		return dap.SetVariableResponse{
+			Response: dap.Response{
+				RequestSeq: dapRequest.Seq,
+				Success:    false,
+				ProtocolMessage: dap.ProtocolMessage{
+					Seq:  seq,
+					Type: "response",
+				},
+				Message: msg,
+				Command: dapRequest.Command,
+			},
+		}
+	}
+
+	debugSession, err := getDebugSession(session, params.SessionId)
+
+	if err != nil {
+		return failure(0, err.Error()), nil
+	}
+
+	ref := dapRequest.Arguments.VariablesReference
+	threadId, refs, ok := debugSession.getThreadOfVariablesReference(ref)
+
+	if !ok || (ref != refs.globalScope && ref != refs.localScope) {
+		return failure(debugSession.NextSeq(), "failed to set variable: failed to find thread"), nil
+	}
+
+	valueExpr, ok := core.ParseDebugExpression(dapRequest.Arguments.Value)
+	if !ok {
+		return failure(debugSession.NextSeq(), "failed to set variable: invalid value"), nil
+	}
+
+	//the channels are buffered in order to not block the debuggee if the request times out.
+	resultChan := make(chan dap.SetVariableResponseBody, 1)
+	errChan := make(chan error, 1)
+
+	debugSession.debugger.ControlChan() <- core.DebugCommandSetVariable{
+		ThreadId: threadId,
+		Global:   ref == refs.globalScope,
+		Name:     dapRequest.Arguments.Name,
+		Value:    valueExpr,
+		Get: func(newValue core.Value, err error) {
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			handlingCtx := session.Context().BoundChild()
+			defer handlingCtx.CancelGracefully()
+
+			resultChan <- dap.SetVariableResponseBody{
+				Value: core.Stringify(newValue, handlingCtx),
+			}
+		},
+	}
+
+	var body dap.SetVariableResponseBody
+
+	select {
+	case body = <-resultChan:
+	case err := <-errChan:
+		return failure(debugSession.NextSeq(), "failed to set variable: "+err.Error()), nil
+	case <-time.After(DEFAULT_DEBUG_COMMAND_TIMEOUT):
+		return failure(debugSession.NextSeq(), "failed to set variable"), nil
+	}
+
+	return dap.SetVariableResponse{
+		Response: dap.Response{
+			RequestSeq: dapRequest.Seq,
+			Success:    true,
+			ProtocolMessage: dap.ProtocolMessage{
+				Seq:  debugSession.NextSeq(),
+				Type: "response",
+			},
+			Command: dapRequest.Command,
+		},
+		Body: body,
+	}, nil
+}
+
 func handleSetBreakpoints(ctx context.Context, req interface{}) (interface{}, error) {
 	session := jsonrpc.GetSession(ctx)
 	params := req.(*DebugSetBreakpointsParams)
@@ -1091,10 +1307,18 @@ func handleSetBreakpoints(ctx context.Context, req interface{}) (interface{}, er
 		}, nil
 	}
 
-	var lines []int
+	var (
+		lines   []int
+		options []core.BreakpointOptions
+	)
 
 	for _, srcBreakpoint := range dapRequest.Arguments.Breakpoints {
 		lines = append(lines, srcBreakpoint.Line)
+		options = append(options, core.BreakpointOptions{
+			Condition:    srcBreakpoint.Condition,
+			HitCondition: srcBreakpoint.HitCondition,
+			LogMessage:   srcBreakpoint.LogMessage,
+		})
 	}
 
 	//read & parse file
@@ -1132,7 +1356,7 @@ func handleSetBreakpoints(ctx context.Context, req interface{}) (interface{}, er
 			defer debugSession.initialBreakpointsLock.Unlock()
 			nextBreakpointId := &debugSession.nextInitialBreakpointId
 
-			breakpoints, err = core.GetBreakpointsFromLines(lines, chunk, nextBreakpointId)
+			breakpoints, err = core.GetBreakpointsFromLinesWithOptions(lines, options, chunk, nextBreakpointId)
 		}()
 
 		//get breakpoints & return them in the response
@@ -1183,8 +1407,9 @@ func handleSetBreakpoints(ctx context.Context, req interface{}) (interface{}, er
 	breakpointsChan := make(chan []dap.Breakpoint)
 
 	cmd := core.DebugCommandSetBreakpoints{
-		Chunk:             chunk,
-		BreakPointsByLine: lines,
+		Chunk:                   chunk,
+		BreakPointsByLine:       lines,
+		BreakpointOptionsByLine: options,
 		GetBreakpointsSetByLine: func(breakpoints []core.BreakpointInfo) {
 			var dapBreakpoints []dap.Breakpoint
 			for _, breakpoint := range breakpoints {
@@ -18,6 +18,7 @@ import (
 type debuggedProgramLaunch struct {
 	programPath  string
 	logLevels    *core.LogLevels
+	testFilters  core.TestFilters //if there are no positive filters the program is not executed in testing mode
 	session      *jsonrpc.Session
 	debugSession *DebugSession
 	fls          *Filesystem
@@ -124,7 +125,9 @@ func launchDebuggedProgram(args debuggedProgramLaunch) {
 		}
 	}))
 
-	_, _, _, preparationOk, err := mod.RunLocalModule(mod.RunLocalModuleArgs{
+	enableTesting := len(args.testFilters.PositiveTestFilters) != 0
+
+	_, state, _, preparationOk, err := mod.RunLocalModule(mod.RunLocalModuleArgs{
 		Fpath:                     programPath,
 		ParsingCompilationContext: ctx,
 		ParentContext:             ctx,
@@ -139,10 +142,17 @@ func launchDebuggedProgram(args debuggedProgramLaunch) {
 		Logger:    logger,
 		LogLevels: logLevels,
 
+		EnableTesting: enableTesting,
+		TestFilters:   args.testFilters,
+
 		Debugger:     debugSession.debugger,
 		PreparedChan: debugSession.programPreparedOrFailedToChan,
 	})
 
+	if enableTesting {
+		recordTestResultsAndRefreshCodeLenses(state, session)
+	}
+
 	if preparationOk {
 		debugSession.programDoneChan <- err
 	} else {
@@ -0,0 +1,120 @@
+package projectserver
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/inoxlang/inox/internal/afs"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/parse"
+)
+
+// A moduleDependencyGraph caches the direct dependencies (imported modules and included chunks) of source files,
+// the transitive dependencies are computed on demand. The graph of a session is stored in its preparedFileCache.
+type moduleDependencyGraph struct {
+	lock         sync.Mutex
+	dependencies map[ /* fpath */ string][]string
+}
+
+func newModuleDependencyGraph() *moduleDependencyGraph {
+	return &moduleDependencyGraph{
+		dependencies: map[string][]string{},
+	}
+}
+
+// invalidate removes the cached dependencies of the file at fpath, they are computed again the next time they are needed.
+func (g *moduleDependencyGraph) invalidate(fpath string) {
+	if g == nil {
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	delete(g.dependencies, fpath)
+}
+
+// getDirectDependencies returns the absolute paths of the modules imported and the chunks included by the file at fpath,
+// the result is cached. The result is empty if the file does not exist or cannot be parsed.
+func (g *moduleDependencyGraph) getDirectDependencies(fpath string, fls afs.Filesystem) []string {
+	g.lock.Lock()
+	deps, ok := g.dependencies[fpath]
+	g.lock.Unlock()
+
+	if ok {
+		return deps
+	}
+
+	chunk, _ := core.ParseFileChunk(fpath, fls)
+	if chunk != nil {
+		deps = getChunkDependencies(fpath, chunk.Node)
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.dependencies[fpath] = deps
+
+	return deps
+}
+
+// dependsOn returns true if the file at fpath directly or indirectly depends on the file at dependencyPath.
+func (g *moduleDependencyGraph) dependsOn(fpath, dependencyPath string, fls afs.Filesystem) bool {
+	visited := map[string]struct{}{fpath: {}}
+	queue := []string{fpath}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range g.getDirectDependencies(current, fls) {
+			if dep == dependencyPath {
+				return true
+			}
+			if _, ok := visited[dep]; !ok {
+				visited[dep] = struct{}{}
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return false
+}
+
+// getChunkDependencies returns the absolute paths of the local modules imported and the chunks included by
+// the top-level statements of a chunk.
+func getChunkDependencies(fpath string, chunk *parse.Chunk) (deps []string) {
+	dir := filepath.Dir(fpath)
+
+	addDependency := func(path string, absolute bool) {
+		if !absolute {
+			path = filepath.Join(dir, path)
+		}
+		if !slices.Contains(deps, path) {
+			deps = append(deps, path)
+		}
+	}
+
+	for _, stmt := range chunk.Statements {
+		var source parse.Node
+
+		switch s := stmt.(type) {
+		case *parse.ImportStatement:
+			source = s.Source
+		case *parse.InclusionImportStatement:
+			source = s.Source
+		default:
+			continue
+		}
+
+		//URL imports and invalid sources are ignored.
+		switch src := source.(type) {
+		case *parse.AbsolutePathLiteral:
+			addDependency(src.Value, true)
+		case *parse.RelativePathLiteral:
+			addDependency(src.Value, false)
+		}
+	}
+
+	return
+}
@@ -68,6 +68,17 @@ func getHoverContent(fpath string, line, column int32, handlingCtx *core.Context
 		}, nil
 	}
 
+	//help about type parameters
+	typeParamHelp, ok := getTypeParameterHelp(hoveredNode, ancestors, state.SymbolicData.Data)
+	if ok {
+		return &defines.Hover{
+			Contents: defines.MarkupContent{
+				Kind:  defines.MarkupKindMarkdown,
+				Value: typeParamHelp,
+			},
+		}, nil
+	}
+
 	//help about tag or attribute
 	xmlElementInfo, hasXmlElementInfo := getXmlElementInfo(hoveredNode, ancestors)
 
@@ -128,6 +139,11 @@ func getHoverContent(fpath string, line, column int32, handlingCtx *core.Context
 				if ok {
 					helpMessage = "\n-----\n" + strings.ReplaceAll(markdown, "\n\r", "\n")
 				}
+			case *symbolic.InoxFunction:
+				//list the kinds of the errors the function can return.
+				if kinds := symbolic.GetErrorKinds(val.Result()); len(kinds) > 0 {
+					helpMessage = "\n-----\nerror kinds: #" + strings.Join(kinds, ", #")
+				}
 			}
 			if helpMessage == "" && val == mostSpecificVal && lessSpecificVal != nil {
 				val = lessSpecificVal
@@ -237,6 +253,33 @@ func getXmlElementInfo(node parse.Node, ancestors []parse.Node) (string, bool) {
 	return "", false
 }
 
+// getTypeParameterHelp returns the help about a type parameter if n is the name of a type parameter or a reference to it.
+// The value of a reference depends on the call being checked, so the constraint of the type parameter is shown instead.
+func getTypeParameterHelp(n parse.Node, ancestors []parse.Node, data *symbolic.Data) (string, bool) {
+	ident, ok := n.(*parse.PatternIdentifierLiteral)
+	if !ok {
+		return "", false
+	}
+
+	typeParam := ident.TypeParameter
+	if typeParam == nil && len(ancestors) > 0 {
+		if param, ok := ancestors[len(ancestors)-1].(*parse.TypeParameter); ok && param.Name == ident {
+			typeParam = param
+		}
+	}
+
+	if typeParam == nil {
+		return "", false
+	}
+
+	signature := "%" + typeParam.Name.Name
+	if constraint, ok := data.GetMostSpecificNodeValue(typeParam.Name); ok {
+		signature += " " + symbolic.Stringify(constraint)
+	}
+
+	return "```inox\n" + signature + "\n```\n-----\ntype parameter, the type arguments should match its constraint.", true
+}
+
 func getSectionHelp(n parse.Node, ancestors []parse.Node) (string, bool) {
 	ancestorCount := len(ancestors)
 
@@ -0,0 +1,78 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTypeParameterHelp(t *testing.T) {
+
+	getHelpAt := func(line, column int32, prepResult preparationResult) (string, bool) {
+		chunk := prepResult.chunk
+		span := chunk.GetLineColumnSingeCharSpan(line, column)
+		node, ancestors, ok := chunk.GetNodeAndChainAtSpan(span)
+		if !assert.True(t, ok) {
+			return "", false
+		}
+		return getTypeParameterHelp(node, ancestors, prepResult.state.SymbolicData.Data)
+	}
+
+	t.Run("name of the type parameter of a generic function", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f<T int>(a T) T {\n  return a\n}",
+		})
+		if !ok {
+			return
+		}
+
+		help, ok := getHelpAt(2, 6, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, "```inox\n%T %type-pattern(%int)\n```\n-----\ntype parameter, the type arguments should match its constraint.", help)
+	})
+
+	t.Run("reference to the type parameter of a generic function", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f<T int>(a T) T {\n  return a\n}\nf(1)",
+		})
+		if !ok {
+			return
+		}
+
+		//the constraint should be shown even if the function is called.
+		help, ok := getHelpAt(2, 18, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, "```inox\n%T %type-pattern(%int)\n```\n-----\ntype parameter, the type arguments should match its constraint.", help)
+	})
+
+	t.Run("reference to the type parameter of a pattern definition", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\npattern p<T> = [T]",
+		})
+		if !ok {
+			return
+		}
+
+		help, ok := getHelpAt(2, 17, prepResult)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Contains(t, help, "```inox\n%T ")
+	})
+
+	t.Run("pattern identifier that is not a type parameter", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f<T int>(a int) T {\n  return a\n}",
+		})
+		if !ok {
+			return
+		}
+
+		_, ok = getHelpAt(2, 15, prepResult)
+		assert.False(t, ok)
+	})
+}
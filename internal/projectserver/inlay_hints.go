@@ -0,0 +1,224 @@
+package projectserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	INLAY_HINT_METHOD = "textDocument/inlayHint"
+
+	MAX_INLAY_HINT_TYPE_LENGTH = 40
+)
+
+func handleInlayHint(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.InlayHintParams)
+	session := jsonrpc.GetSession(ctx)
+	sessionCtx := session.Context()
+
+	//----------------------------------------
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+	//----------------------------------------
+
+	hints := []defines.InlayHint{}
+
+	if fls == nil {
+		return hints, nil
+	}
+
+	fpath, err := getFilePath(params.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+
+	handlingCtx := sessionCtx.BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	//inlay hints are requested very often so we only use the cached preparation result.
+	preparationResult, ok := prepareSourceFileInExtractionMode(handlingCtx, filePreparationParams{
+		fpath:                              fpath,
+		session:                            session,
+		requiresState:                      true,
+		requiresCache:                      true,
+		forcePrepareIfNoVeryRecentActivity: true,
+	})
+
+	if !ok {
+		return hints, nil
+	}
+
+	if !preparationResult.cachedOrGotCache && preparationResult.state != nil {
+		//teardown in separate goroutine to return quickly
+		defer func() {
+			go func() {
+				defer utils.Recover()
+				preparationResult.state.Ctx.CancelGracefully()
+			}()
+		}()
+	}
+
+	if preparationResult.state == nil || preparationResult.state.SymbolicData == nil {
+		logs.Println("no data")
+		return hints, nil
+	}
+
+	hints = append(hints, getInlayHints(preparationResult.chunk, preparationResult.state.SymbolicData.Data, params.Range)...)
+	return hints, nil
+}
+
+// getInlayHints returns the hints located in $r: the inferred types of local variable declarations,
+// the inferred return types of functions and the permissions that might be missing for calls
+// (including pattern calls and XML expressions).
+func getInlayHints(chunk *parse.ParsedChunkSource, data *symbolic.Data, r defines.Range) []defines.InlayHint {
+	rangeStart := chunk.GetLineColumnPosition(int32(r.Start.Line)+1, int32(r.Start.Character)+1)
+	rangeEnd := chunk.GetLineColumnPosition(int32(r.End.Line)+1, int32(r.End.Character)+1)
+
+	var hints []defines.InlayHint
+
+	addHint := func(pos int32, hint defines.InlayHint) {
+		if pos < rangeStart || pos > rangeEnd {
+			return
+		}
+		position := chunk.GetSourcePosition(parse.NodeSpan{Start: pos, End: pos})
+		hint.Position = defines.Position{
+			Line:      uint(position.StartLine - 1),
+			Character: uint(position.StartColumn - 1),
+		}
+		hints = append(hints, hint)
+	}
+
+	addTypeHint := func(pos int32, value symbolic.Value) {
+		hint := defines.InlayHint{
+			Label: ": " + stringifyInlayHintType(value),
+			Kind:  defines.InlayHintKindType,
+		}
+		addHint(pos, hint)
+	}
+
+	parse.Walk(chunk.Node, func(node, parent, scopeNode parse.Node, ancestors []parse.Node, after bool) (parse.TraversalAction, error) {
+		span := node.Base().Span
+		if span.End < rangeStart || span.Start > rangeEnd {
+			return parse.Prune, nil
+		}
+
+		switch n := node.(type) {
+		case *parse.Assignment:
+			ident, ok := n.Left.(*parse.IdentifierLiteral)
+			if !ok || n.Operator != parse.Assign || isSimpleValueLiteral(n.Right) {
+				break
+			}
+
+			//only the assignment declaring the variable is annotated.
+			if scopeData, ok := data.GetLocalScopeData(n, ancestors); ok {
+				varData, found := findVarData(scopeData, ident.Name)
+				if found && varData.DefinitionPosition.Span != ident.Span {
+					break
+				}
+			}
+
+			if value, ok := data.GetMostSpecificNodeValue(ident); ok {
+				addTypeHint(ident.Span.End, value)
+			}
+		case *parse.LocalVariableDeclaration:
+			ident, ok := n.Left.(*parse.IdentifierLiteral)
+			if !ok || n.Type != nil || isSimpleValueLiteral(n.Right) {
+				break
+			}
+
+			if value, ok := data.GetMostSpecificNodeValue(ident); ok {
+				addTypeHint(ident.Span.End, value)
+			}
+		case *parse.FunctionExpression:
+			if n.ReturnType != nil || n.Body == nil {
+				break
+			}
+
+			value, ok := data.GetMostSpecificNodeValue(n)
+			if !ok {
+				break
+			}
+
+			fn, ok := value.(*symbolic.InoxFunction)
+			if !ok || fn.Result() == nil || utils.Implements[*symbolic.NilT](fn.Result()) {
+				break
+			}
+
+			pos, ok := findParametersEnd(n, chunk.Runes())
+			if ok {
+				addHint(pos, defines.InlayHint{
+					Label:       stringifyInlayHintType(fn.Result()),
+					Kind:        defines.InlayHintKindType,
+					PaddingLeft: true,
+				})
+			}
+		case *parse.CallExpression, *parse.PatternCallExpression, *parse.XMLExpression:
+			//the permissions required by the Go functions called by these nodes are recorded.
+			permissions, ok := data.GetMissingPermissions(n)
+			if !ok {
+				break
+			}
+
+			var permissionStrings []string
+			for _, perm := range permissions {
+				permissionStrings = append(permissionStrings, perm.String())
+			}
+
+			addHint(span.End, defines.InlayHint{
+				Label:       "missing permission: " + strings.Join(permissionStrings, ", "),
+				Tooltip:     "The manifest may not grant the permissions required by this expression.",
+				PaddingLeft: true,
+			})
+		}
+
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return hints
+}
+
+func isSimpleValueLiteral(node parse.Node) bool {
+	_, ok := node.(parse.SimpleValueLiteral)
+	return ok
+}
+
+// findParametersEnd returns the position following the closing parenthesis of the parameter list.
+func findParametersEnd(fnExpr *parse.FunctionExpression, runes []rune) (int32, bool) {
+	i := fnExpr.Body.Base().Span.Start - 1
+
+	for i > fnExpr.Span.Start && i < int32(len(runes)) {
+		switch runes[i] {
+		case ')':
+			return i + 1, true
+		case ' ', '\t', '\n', '\r', '=', '>':
+			i--
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// stringifyInlayHintType returns a single-line representation of the static type of a value.
+func stringifyInlayHintType(value symbolic.Value) string {
+	s := symbolic.Stringify(symbolic.WidenToStaticType(value))
+	s = strings.Join(strings.Fields(s), " ")
+
+	runes := []rune(s)
+	if len(runes) > MAX_INLAY_HINT_TYPE_LENGTH {
+		return string(runes[:MAX_INLAY_HINT_TYPE_LENGTH-3]) + "..."
+	}
+	return s
+}
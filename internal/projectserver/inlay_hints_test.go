@@ -0,0 +1,82 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInlayHints(t *testing.T) {
+	prepResult, ok := prepareTestModule(t, map[string]string{
+		"/main.ix": "manifest {}\n" +
+			"a = 1\n" +
+			"var b = [1, \"a\"]\n" +
+			"fn f(x int){ return {a: x} }\n" +
+			"c = f(1)\n" +
+			"c = f(2)",
+	})
+	if !ok {
+		return
+	}
+
+	wholeDocument := defines.Range{
+		Start: defines.Position{Line: 0, Character: 0},
+		End:   defines.Position{Line: 5, Character: 8},
+	}
+
+	hints := getInlayHints(prepResult.chunk, prepResult.state.SymbolicData.Data, wholeDocument)
+
+	assert.Equal(t, []defines.InlayHint{
+		{
+			Position: defines.Position{Line: 2, Character: 5},
+			Label:    ": [](%int | %string)",
+			Kind:     defines.InlayHintKindType,
+		},
+		{
+			Position:    defines.Position{Line: 3, Character: 11},
+			Label:       "{\"a\": %int}",
+			Kind:        defines.InlayHintKindType,
+			PaddingLeft: true,
+		},
+		{
+			Position: defines.Position{Line: 4, Character: 1},
+			Label:    ": {\"a\": %int}",
+			Kind:     defines.InlayHintKindType,
+		},
+	}, hints)
+
+	t.Run("only the hints in the range are returned", func(t *testing.T) {
+		hints := getInlayHints(prepResult.chunk, prepResult.state.SymbolicData.Data, defines.Range{
+			Start: defines.Position{Line: 4, Character: 0},
+			End:   defines.Position{Line: 5, Character: 8},
+		})
+
+		if assert.Len(t, hints, 1) {
+			assert.Equal(t, defines.Position{Line: 4, Character: 1}, hints[0].Position)
+		}
+	})
+	t.Run("missing permission", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "manifest {}\n" +
+				"http.get!(https://example.com/)",
+		})
+		if !ok {
+			return
+		}
+
+		hints := getInlayHints(prepResult.chunk, prepResult.state.SymbolicData.Data, defines.Range{
+			Start: defines.Position{Line: 0, Character: 0},
+			End:   defines.Position{Line: 1, Character: 31},
+		})
+
+		assert.Equal(t, []defines.InlayHint{
+			{
+				Position:    defines.Position{Line: 1, Character: 31},
+				Label:       "missing permission: read http",
+				Tooltip:     "The manifest may not grant the permissions required by this expression.",
+				PaddingLeft: true,
+			},
+		}, hints)
+	})
+}
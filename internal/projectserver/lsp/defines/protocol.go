@@ -104,6 +104,16 @@ type ServerCapabilities_ struct {
 	// @since 3.16.0
 	SemanticTokensProvider interface{} `json:"semanticTokensProvider,omitempty"` // SemanticTokensOptions, SemanticTokensRegistrationOptions,
 
+	// The server provides inlay hints.
+	//
+	// @since 3.17.0
+	InlayHintProvider interface{} `json:"inlayHintProvider,omitempty"` // bool, InlayHintOptions,
+
+	// The server provides type hierarchy support.
+	//
+	// @since 3.17.0
+	TypeHierarchyProvider interface{} `json:"typeHierarchyProvider,omitempty"` // bool, TypeHierarchyOptions, TypeHierarchyRegistrationOptions,
+
 	// Window specific server capabilities.
 	Workspace *struct {
 
@@ -407,6 +417,11 @@ type TextDocumentClientCapabilities struct {
 	//
 	// @since 3.17.0 - proposed state
 	InlineValues *InlineValuesClientCapabilities `json:"inlineValues,omitempty"`
+
+	// Capabilities specific to the `textDocument/inlayHint` request.
+	//
+	// @since 3.17.0
+	InlayHint *InlayHintClientCapabilities `json:"inlayHint,omitempty"`
 }
 
 type WindowClientCapabilities struct {
@@ -1875,6 +1890,12 @@ type WorkspaceEditClientCapabilities struct {
 		// be a tree node.
 		GroupsOnLabel *bool `json:"groupsOnLabel,omitempty"`
 	} `json:"changeAnnotationSupport,omitempty"`
+
+	// Whether the client supports snippets as text edits.
+	//
+	// @since 3.18.0
+	// @proposed
+	SnippetEditSupport *bool `json:"snippetEditSupport,omitempty"`
 }
 
 /**
@@ -0,0 +1,96 @@
+package defines
+
+/**
+ * Inlay hint client capabilities.
+ *
+ * @since 3.17.0
+ */
+type InlayHintClientCapabilities struct {
+
+	// Whether inlay hints support dynamic registration.
+	DynamicRegistration *bool `json:"dynamicRegistration,omitempty"`
+
+	// Indicates which properties a client can resolve lazily on an inlay
+	// hint.
+	ResolveSupport *struct {
+
+		// The properties that a client can resolve lazily.
+		Properties []string `json:"properties"`
+	} `json:"resolveSupport,omitempty"`
+}
+
+/**
+ * Inlay hint options used during static registration.
+ *
+ * @since 3.17.0
+ */
+type InlayHintOptions struct {
+	WorkDoneProgressOptions
+
+	// The server provides support to resolve additional
+	// information for an inlay hint item.
+	ResolveProvider *bool `json:"resolveProvider,omitempty"`
+}
+
+/**
+ * A parameter literal used in inlay hint requests.
+ *
+ * @since 3.17.0
+ */
+type InlayHintParams struct {
+	WorkDoneProgressParams
+
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument,omitempty"`
+
+	// The visible document range for which inlay hints should be computed.
+	Range Range `json:"range,omitempty"`
+}
+
+/**
+ * Inlay hint kinds.
+ *
+ * @since 3.17.0
+ */
+type InlayHintKind int
+
+const (
+	// An inlay hint that for a type annotation.
+	InlayHintKindType InlayHintKind = 1
+
+	// An inlay hint that is for a parameter.
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+/**
+ * Inlay hint information.
+ *
+ * @since 3.17.0
+ */
+type InlayHint struct {
+
+	// The position of this hint.
+	Position Position `json:"position"`
+
+	// The label of this hint. A human readable string or an array of
+	// InlayHintLabelPart label parts.
+	//
+	// *Note* that neither the string nor the label part can be empty.
+	Label interface{} `json:"label"` // string, []InlayHintLabelPart,
+
+	// The kind of this hint. Can be omitted in which case the client
+	// should fall back to a reasonable default.
+	Kind InlayHintKind `json:"kind,omitempty"`
+
+	// Optional text edits that are performed when accepting this inlay hint.
+	TextEdits []TextEdit `json:"textEdits,omitempty"`
+
+	// The tooltip text when you hover over this item.
+	Tooltip interface{} `json:"tooltip,omitempty"` // string, MarkupContent,
+
+	// Render padding before the hint.
+	PaddingLeft bool `json:"paddingLeft,omitempty"`
+
+	// Render padding after the hint.
+	PaddingRight bool `json:"paddingRight,omitempty"`
+}
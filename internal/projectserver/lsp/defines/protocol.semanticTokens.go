@@ -72,7 +72,16 @@ type SemanticTokensOptions struct {
 	Range *bool `json:"range,omitempty"`
 
 	// Server supports providing semantic tokens for a full document.
-	Full *bool `json:"full,omitempty"`
+	Full interface{} `json:"full,omitempty"` // bool, SemanticTokensFullOptions,
+}
+
+/**
+ * @since 3.16.0
+ */
+type SemanticTokensFullOptions struct {
+
+	// The server supports deltas for full documents.
+	Delta *bool `json:"delta,omitempty"`
 }
 
 /**
@@ -303,6 +303,37 @@ type TextEdit struct {
 	NewText string `json:"newText,omitempty"`
 }
 
+/**
+ * An interactive text edit.
+ *
+ * @since 3.18.0
+ * @proposed
+ */
+type SnippetTextEdit struct {
+
+	// The range of the text document to be manipulated.
+	Range Range `json:"range"`
+
+	// The snippet to be inserted.
+	Snippet StringValue `json:"snippet"`
+}
+
+/**
+ * A string value used as a snippet is a template which allows to insert text
+ * and to control the editor cursor when insertion happens.
+ *
+ * @since 3.18.0
+ * @proposed
+ */
+type StringValue struct {
+
+	// The kind of string value, always "snippet".
+	Kind string `json:"kind"`
+
+	// The snippet string.
+	Value string `json:"value"`
+}
+
 /**
  * Additional information that describes document changes.
  *
@@ -1475,7 +1506,7 @@ type TextDocumentEdit struct {
 	 * @since 3.16.0 - support for AnnotatedTextEdit. This is guarded using a
 	 * client capability.
 	 */
-	Edits []interface{} `json:"edits,omitempty"` // (TextEdit | AnnotatedTextEdit)[]
+	Edits []interface{} `json:"edits,omitempty"` // (TextEdit | AnnotatedTextEdit | SnippetTextEdit)[]
 }
 
 /**
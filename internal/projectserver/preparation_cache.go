@@ -5,6 +5,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/inoxlang/inox/internal/afs"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/parse"
 	"github.com/inoxlang/inox/internal/projectserver/logs"
@@ -32,13 +33,16 @@ func init() {
 type preparedFileCache struct {
 	lock    sync.RWMutex
 	entries map[ /* fpath */ string]*preparedFileCacheEntry
+
+	dependencyGraph *moduleDependencyGraph //never nil, the entries are not removed by clearUnusedCachePeriodically.
 }
 
 // newPreparedFileCache creates a new *newPreparedFileCache and puts in the
 // global preparedFileCaches map.
 func newPreparedFileCache() *preparedFileCache {
 	cache := &preparedFileCache{
-		entries: map[string]*preparedFileCacheEntry{},
+		entries:         map[string]*preparedFileCacheEntry{},
+		dependencyGraph: newModuleDependencyGraph(),
 	}
 
 	preparedFileCachesLock.Lock()
@@ -66,6 +70,8 @@ func (c *preparedFileCache) acknowledgeSourceFileChange(fpath string) {
 		return
 	}
 
+	c.dependencyGraph.invalidate(fpath)
+
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
@@ -97,6 +103,14 @@ type preparedFileCacheEntry struct {
 	chunk                    *parse.ParsedChunkSource
 	lastUpdateOrInvalidation time.Time
 
+	//last parsed version of the file, it is not removed by clear() in order for the next versions to be parsed
+	//incrementally: only the top level statements affected by the changes are parsed again.
+	lastParsedChunk *parse.ParsedChunkSource
+
+	//check data of the last prepared version of the module, it is not removed by clear() in order for the
+	//checking of the unchanged top level statements to be reused. It is removed when the entry is unused.
+	lastCheckData *core.IncrementalCheckData
+
 	sourceChanged atomic.Bool
 	lastAccess    atomic.Value //time.Time
 }
@@ -145,6 +159,16 @@ func (c *preparedFileCacheEntry) LastUpdateOrInvalidation() time.Time {
 	return c.lastUpdateOrInvalidation
 }
 
+// parseFile parses the current version of the file by reusing the last parsed version,
+// it is assumed that the cache entry has been locked by the caller.
+func (c *preparedFileCacheEntry) parseFile(fls afs.Filesystem) (*parse.ParsedChunkSource, error) {
+	chunk, err := core.ParseFileChunkIncrementally(c.fpath, fls, c.lastParsedChunk)
+	if chunk != nil {
+		c.lastParsedChunk = chunk
+	}
+	return chunk, err
+}
+
 // preparedFileCacheEntry clears the cache,
 // it is assumed that the cache entry has been locked by the caller.
 func (c *preparedFileCacheEntry) clear() {
@@ -186,6 +210,7 @@ func (c *preparedFileCacheEntry) update(state *core.GlobalState, mod *core.Modul
 	} else {
 		c.chunk = chunk
 	}
+	c.lastParsedChunk = c.chunk
 }
 
 // clearUnusedCachePeriodically periodically iterates over file caches
@@ -230,6 +255,7 @@ func clearUnusedCachePeriodically() {
 		for _, entry := range entriesToClear {
 			entry.lock.Lock()
 			entry.clear()
+			entry.lastCheckData = nil
 			entry.lock.Unlock()
 		}
 
@@ -79,10 +79,7 @@ func prepareSourceFileInExtractionMode(ctx *core.Context, params filePreparation
 
 	//we avoid locking the session data
 	if sessionData.lock.TryLock() || sessionData.lock.TryLock() {
-		if sessionData.preparedSourceFilesCache == nil {
-			sessionData.preparedSourceFilesCache = newPreparedFileCache()
-		}
-		cache := sessionData.preparedSourceFilesCache
+		cache := sessionData.getOrCreatePreparedFileCache()
 		sessionData.lock.Unlock()
 		func() {
 			fileCache, _ = cache.getOrCreate(fpath)
@@ -125,7 +122,15 @@ func prepareSourceFileInExtractionMode(ctx *core.Context, params filePreparation
 		return
 	}
 
-	chunk, err := core.ParseFileChunk(fpath, fls)
+	var chunk *parse.ParsedChunkSource
+	var err error
+
+	if fileCache != nil {
+		//only the statements affected by the changes since the last parsing are parsed again.
+		chunk, err = fileCache.parseFile(fls)
+	} else {
+		chunk, err = core.ParseFileChunk(fpath, fls)
+	}
 
 	if chunk == nil { //unrecoverable parsing error
 		logs.Println("unrecoverable parsing error", err.Error())
@@ -208,6 +213,12 @@ func prepareSourceFileInExtractionMode(ctx *core.Context, params filePreparation
 			Fpath:                     fpath,
 			ParsingCompilationContext: ctx,
 
+			//the chunk has already been parsed, the module's chunk is created from it.
+			PreviousMainChunk: chunk,
+
+			//the checking of the top level statements that are not affected by the changes is reused.
+			IncrementalChecking: fileCache != nil,
+
 			//set if the module uses databases from another module.
 			ParentContext:         parentCtx,
 			ParentContextRequired: parentCtx != nil,
@@ -227,8 +238,18 @@ func prepareSourceFileInExtractionMode(ctx *core.Context, params filePreparation
 			args.EnableTesting = true
 		}
 
+		if fileCache != nil {
+			//the previous check data cannot be used after the preparation.
+			args.PreviousCheckData = fileCache.lastCheckData
+			fileCache.lastCheckData = nil
+		}
+
 		state, mod, _, err := core.PrepareLocalModule(args)
 
+		if fileCache != nil && state != nil {
+			fileCache.lastCheckData = state.IncrementalCheckData
+		}
+
 		if mod == nil {
 			logs.Println("unrecoverable parsing error", err.Error())
 			session.Notify(NewShowMessage(defines.MessageTypeError, err.Error()))
@@ -139,6 +139,7 @@ func registerProjectMethodHandlers(server *lsp.Server, opts LSPServerConfigurati
 
 			err = startNotifyingFilesystemStructureEvents(session, project.LiveFilesystem(), func(event fs_ns.Event) {
 				sessionData.serverAPI.acknowledgeStructureChangeEvent(event)
+				sessionData.workspaceSymbols.invalidate(event.Path().UnderlyingString())
 			})
 
 			if err != nil {
@@ -0,0 +1,449 @@
+package projectserver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/inoxconsts"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+var (
+	ErrNoSymbolAtPosition      = errors.New("no renamable symbol at the position")
+	ErrBuiltinsCannotBeRenamed = errors.New("builtins cannot be renamed")
+)
+
+type symbolKind int
+
+const (
+	variableSymbol symbolKind = iota + 1 //local & global variables, functions, imported modules
+	patternSymbol
+	patternNamespaceSymbol
+)
+
+// A projectSymbol is a variable, a function, an imported module, a pattern or a pattern namespace defined in a
+// source file of the project. Symbols are identified by the position of their definition.
+type projectSymbol struct {
+	kind       symbolKind
+	name       string
+	definition parse.SourcePositionRange
+}
+
+func (s projectSymbol) isDefinedAt(pos parse.SourcePositionRange) bool {
+	return s.definition.SourceName == pos.SourceName && s.definition.Span == pos.Span
+}
+
+// A symbolOccurrence is the position of the name of a symbol in a source file, prefixes such as '$' and '%'
+// are not included.
+type symbolOccurrence struct {
+	nameRange    parse.SourcePositionRange
+	isDefinition bool
+}
+
+// getSymbolAt returns the symbol at a specific position in a prepared chunk, nameRange is the position of the
+// name of the symbol at line:column.
+func getSymbolAt(line, column int32, chunk *parse.ParsedChunkSource, state *core.GlobalState) (symbol projectSymbol, nameRange parse.SourcePositionRange, _ error) {
+	span := chunk.GetLineColumnSingeCharSpan(line, column)
+	node, ancestors, ok := chunk.GetNodeAndChainAtSpan(span)
+	if !ok || node == nil {
+		return projectSymbol{}, parse.SourcePositionRange{}, ErrNoSymbolAtPosition
+	}
+
+	kind, name, ok := getSymbolKindAndName(node, ancestors)
+	if !ok {
+		return projectSymbol{}, parse.SourcePositionRange{}, ErrNoSymbolAtPosition
+	}
+
+	data := state.SymbolicData.Data
+
+	definition, ok := getSymbolDefinition(kind, node, ancestors, data)
+	if !ok {
+		if isBuiltinSymbol(kind, name, node, ancestors, data) {
+			return projectSymbol{}, parse.SourcePositionRange{}, ErrBuiltinsCannotBeRenamed
+		}
+		return projectSymbol{}, parse.SourcePositionRange{}, fmt.Errorf("the definition of %s has not been found", name)
+	}
+
+	symbol = projectSymbol{
+		kind:       kind,
+		name:       name,
+		definition: definition,
+	}
+
+	return symbol, getSymbolNameRange(node, name, chunk), nil
+}
+
+// findSymbolOccurrences returns the occurrences of a symbol in a prepared chunk.
+func findSymbolOccurrences(symbol projectSymbol, chunk *parse.ParsedChunkSource, data *symbolic.Data) (occurrences []symbolOccurrence) {
+	parse.Walk(chunk.Node, func(node, _, _ parse.Node, ancestors []parse.Node, _ bool) (parse.TraversalAction, error) {
+		kind, name, ok := getSymbolKindAndName(node, ancestors)
+		if !ok || kind != symbol.kind || name != symbol.name {
+			return parse.ContinueTraversal, nil
+		}
+
+		isDefinition := symbol.isDefinedAt(chunk.GetSourcePosition(node.Base().Span))
+
+		if !isDefinition {
+			definition, ok := getSymbolDefinition(kind, node, ancestors, data)
+			if !ok || !symbol.isDefinedAt(definition) {
+				return parse.ContinueTraversal, nil
+			}
+		}
+
+		occurrences = append(occurrences, symbolOccurrence{
+			nameRange:    getSymbolNameRange(node, name, chunk),
+			isDefinition: isDefinition,
+		})
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return
+}
+
+// findSymbolOccurrencesInModule returns the occurrences of a symbol in a prepared module or includable file,
+// the chunks included by the module are also searched.
+func findSymbolOccurrencesInModule(symbol projectSymbol, prepResult preparationResult) (occurrences []symbolOccurrence) {
+	data := prepResult.state.SymbolicData.Data
+
+	occurrences = findSymbolOccurrences(symbol, prepResult.chunk, data)
+
+	if prepResult.module != nil {
+		for _, includedChunk := range prepResult.module.FlattenedIncludedChunkList {
+			if includedChunk.ParsedChunkSource == prepResult.chunk {
+				continue
+			}
+			occurrences = append(occurrences, findSymbolOccurrences(symbol, includedChunk.ParsedChunkSource, data)...)
+		}
+	}
+
+	return
+}
+
+// findSymbolOccurrencesInProject searches for the occurrences of a symbol in all the Inox files of the project,
+// files that do not contain the name of the symbol are not prepared. The result does not contain duplicates.
+func findSymbolOccurrencesInProject(symbol projectSymbol, handlingCtx *core.Context, session *jsonrpc.Session) ([]symbolOccurrence, error) {
+	var (
+		occurrences []symbolOccurrence
+		seen        = map[parse.SourcePositionRange]struct{}{}
+	)
+
+	err := forEachPreparedProjectFile(symbol.name, handlingCtx, session, func(prepResult preparationResult) {
+		for _, occurrence := range findSymbolOccurrencesInModule(symbol, prepResult) {
+			if _, ok := seen[occurrence.nameRange]; ok {
+				continue
+			}
+			seen[occurrence.nameRange] = struct{}{}
+			occurrences = append(occurrences, occurrence)
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(occurrences, func(a, b symbolOccurrence) int {
+		return compareSourcePositions(a.nameRange, b.nameRange)
+	})
+
+	return occurrences, nil
+}
+
+// forEachPreparedProjectFile prepares the Inox files of the project that contain $substring and calls fn with the
+// preparation results. Files that fail to be prepared are ignored.
+func forEachPreparedProjectFile(substring string, handlingCtx *core.Context, session *jsonrpc.Session, fn func(prepResult preparationResult)) error {
+	fls, ok := getLspFilesystem(session)
+	if !ok {
+		return errors.New(string(FsNoFilesystem))
+	}
+
+	return fsutil.Walk(fls, "/", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, inoxconsts.INOXLANG_FILE_EXTENSION) {
+			return nil
+		}
+
+		content, err := fsutil.ReadFile(fls, path)
+		if err != nil || !strings.Contains(string(content), substring) {
+			return nil
+		}
+
+		prepResult, ok := prepareSourceFileInExtractionMode(handlingCtx, filePreparationParams{
+			fpath:         path,
+			session:       session,
+			requiresState: true,
+		})
+
+		if !ok || prepResult.state == nil || prepResult.state.SymbolicData == nil {
+			logs.Println("failed to prepare", path, "while searching for the occurrences of", substring)
+			return nil
+		}
+
+		if !prepResult.cachedOrGotCache {
+			//teardown in separate goroutine to return quickly
+			defer func() {
+				go func() {
+					defer utils.Recover()
+					prepResult.state.Ctx.CancelGracefully()
+				}()
+			}()
+		}
+
+		fn(prepResult)
+		return nil
+	})
+}
+
+// withPreparedFile prepares the file at $fpath and calls fn with the preparation result,
+// ok is false if the preparation failed.
+func withPreparedFile(fpath string, handlingCtx *core.Context, session *jsonrpc.Session, fn func(prepResult preparationResult)) (ok bool) {
+	preparationResult, ok := prepareSourceFileInExtractionMode(handlingCtx, filePreparationParams{
+		fpath:         fpath,
+		session:       session,
+		requiresState: true,
+	})
+
+	if !ok || preparationResult.state == nil || preparationResult.state.SymbolicData == nil {
+		return false
+	}
+
+	if !preparationResult.cachedOrGotCache {
+		//teardown in separate goroutine to return quickly
+		defer func() {
+			go func() {
+				defer utils.Recover()
+				preparationResult.state.Ctx.CancelGracefully()
+			}()
+		}()
+	}
+
+	fn(preparationResult)
+	return true
+}
+
+func compareSourcePositions(a, b parse.SourcePositionRange) int {
+	if a.SourceName != b.SourceName {
+		return strings.Compare(a.SourceName, b.SourceName)
+	}
+	return int(a.Span.Start - b.Span.Start)
+}
+
+// getSymbolKindAndName returns the kind and the name of the symbol referenced or defined by node. Identifiers that
+// are not variables (e.g. property names) are ignored.
+func getSymbolKindAndName(node parse.Node, ancestors []parse.Node) (kind symbolKind, name string, ok bool) {
+	var parent parse.Node
+	if len(ancestors) > 0 {
+		parent = ancestors[len(ancestors)-1]
+	}
+
+	switch n := node.(type) {
+	case *parse.Variable:
+		return variableSymbol, n.Name, true
+	case *parse.GlobalVariable:
+		return variableSymbol, n.Name, true
+	case *parse.IdentifierLiteral:
+		if !isIdentifierUsedAsVariable(n, parent) {
+			return
+		}
+		return variableSymbol, n.Name, true
+	case *parse.PatternIdentifierLiteral:
+		return patternSymbol, n.Name, true
+	case *parse.PatternNamespaceIdentifierLiteral:
+		return patternNamespaceSymbol, n.Name, true
+	}
+	return
+}
+
+func isIdentifierUsedAsVariable(ident *parse.IdentifierLiteral, parent parse.Node) bool {
+	switch p := parent.(type) {
+	case *parse.MemberExpression:
+		return p.PropertyName != ident
+	case *parse.DynamicMemberExpression:
+		return p.PropertyName != ident
+	case *parse.IdentifierMemberExpression:
+		return p.Left == ident
+	case *parse.DoubleColonExpression:
+		return p.Element != ident
+	case *parse.PatternNamespaceMemberExpression:
+		return false
+	case *parse.ObjectProperty:
+		return p.Key != ident
+	case *parse.ObjectPatternProperty:
+		return p.Key != ident
+	case *parse.ObjectMetaProperty:
+		return p.Key != ident
+	case *parse.KeyListExpression:
+		return false
+	case *parse.StructFieldDefinition:
+		return p.Name != ident
+	case *parse.StructFieldInitialization:
+		return p.Name != ident
+	case *parse.XMLOpeningElement, *parse.XMLClosingElement:
+		return false
+	case *parse.XMLAttribute:
+		return p.Name != ident
+	}
+	return true
+}
+
+func getSymbolDefinition(kind symbolKind, node parse.Node, ancestors []parse.Node, data *symbolic.Data) (parse.SourcePositionRange, bool) {
+	switch kind {
+	case variableSymbol:
+		return data.GetVariableDefinitionPosition(node, ancestors)
+	case patternSymbol, patternNamespaceSymbol:
+		return data.GetNamedPatternOrPatternNamespacePositionDefinition(node, ancestors)
+	}
+	return parse.SourcePositionRange{}, false
+}
+
+// isBuiltinSymbol returns true if the symbol referenced by node is known by the static check but has no definition
+// in the source files.
+func isBuiltinSymbol(kind symbolKind, name string, node parse.Node, ancestors []parse.Node, data *symbolic.Data) bool {
+	switch kind {
+	case variableSymbol:
+		scopeData, ok := data.GetGlobalScopeData(node, ancestors)
+		if !ok {
+			return false
+		}
+		return slices.ContainsFunc(scopeData.Variables, func(v symbolic.VarData) bool { return v.Name == name })
+	case patternSymbol:
+		contextData, ok := data.GetContextData(node, ancestors)
+		if !ok {
+			return false
+		}
+		return slices.ContainsFunc(contextData.Patterns, func(p symbolic.NamedPatternData) bool { return p.Name == name })
+	case patternNamespaceSymbol:
+		contextData, ok := data.GetContextData(node, ancestors)
+		if !ok {
+			return false
+		}
+		return slices.ContainsFunc(contextData.PatternNamespaces, func(p symbolic.PatternNamespaceData) bool { return p.Name == name })
+	}
+	return false
+}
+
+// getSymbolNameRange returns the position of the name in the node, prefixes such as '$' and '%' are not included.
+func getSymbolNameRange(node parse.Node, name string, chunk *parse.ParsedChunkSource) parse.SourcePositionRange {
+	runes := chunk.Runes()
+	start := node.Base().Span.Start
+
+	for int(start) < len(runes) && (runes[start] == '$' || runes[start] == '%') {
+		start++
+	}
+
+	return chunk.GetSourcePosition(parse.NodeSpan{Start: start, End: start + int32(len([]rune(name)))})
+}
+
+// checkNewSymbolName returns an error if newName is not a valid name for a symbol.
+func checkNewSymbolName(newName string) error {
+	if newName == "" {
+		return errors.New("the new name should not be empty")
+	}
+
+	for i, r := range newName {
+		if (i == 0 && !parse.IsFirstIdentChar(r)) || !parse.IsIdentChar(r) {
+			return fmt.Errorf("%q is not a valid identifier", newName)
+		}
+	}
+
+	if slices.Contains(parse.KEYWORDS, newName) {
+		return fmt.Errorf("%q is a keyword", newName)
+	}
+	return nil
+}
+
+// getReferences returns the locations of the occurrences of the symbol at a specific position in an Inox code file.
+func getReferences(fpath string, line, column int32, includeDeclaration bool, handlingCtx *core.Context, session *jsonrpc.Session) ([]defines.Location, error) {
+	symbol, _, err := getSymbolAtPosition(fpath, line, column, handlingCtx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := findSymbolOccurrencesInProject(symbol, handlingCtx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionData := getSessionData(session)
+	locations := []defines.Location{}
+
+	for _, occurrence := range occurrences {
+		if occurrence.isDefinition && !includeDeclaration {
+			continue
+		}
+		locations = append(locations, defines.Location{
+			Uri:   defines.DocumentUri(sessionData.Scheme() + "://" + occurrence.nameRange.SourceName),
+			Range: rangeToLspRange(occurrence.nameRange),
+		})
+	}
+
+	return locations, nil
+}
+
+// getRenameEdit returns a workspace edit renaming all the occurrences of the symbol at a specific position in an
+// Inox code file.
+func getRenameEdit(fpath string, line, column int32, newName string, handlingCtx *core.Context, session *jsonrpc.Session) (*defines.WorkspaceEdit, error) {
+	if err := checkNewSymbolName(newName); err != nil {
+		return nil, err
+	}
+
+	symbol, _, err := getSymbolAtPosition(fpath, line, column, handlingCtx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := findSymbolOccurrencesInProject(symbol, handlingCtx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionData := getSessionData(session)
+	changes := map[string][]defines.TextEdit{}
+
+	for _, occurrence := range occurrences {
+		uri := sessionData.Scheme() + "://" + occurrence.nameRange.SourceName
+		changes[uri] = append(changes[uri], defines.TextEdit{
+			Range:   rangeToLspRange(occurrence.nameRange),
+			NewText: newName,
+		})
+	}
+
+	return &defines.WorkspaceEdit{Changes: &changes}, nil
+}
+
+// getSymbolAtPosition prepares the file at fpath and calls getSymbolAt.
+func getSymbolAtPosition(fpath string, line, column int32, handlingCtx *core.Context, session *jsonrpc.Session) (projectSymbol, parse.SourcePositionRange, error) {
+	preparationResult, ok := prepareSourceFileInExtractionMode(handlingCtx, filePreparationParams{
+		fpath:         fpath,
+		session:       session,
+		requiresState: true,
+	})
+
+	if !ok || preparationResult.state == nil || preparationResult.state.SymbolicData == nil {
+		return projectSymbol{}, parse.SourcePositionRange{}, fmt.Errorf("failed to prepare %s", fpath)
+	}
+
+	if !preparationResult.cachedOrGotCache {
+		//teardown in separate goroutine to return quickly
+		defer func() {
+			go func() {
+				defer utils.Recover()
+				preparationResult.state.Ctx.CancelGracefully()
+			}()
+		}()
+	}
+
+	return getSymbolAt(line, column, preparationResult.chunk, preparationResult.state)
+}
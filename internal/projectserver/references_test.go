@@ -0,0 +1,206 @@
+package projectserver
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/permkind"
+	"github.com/inoxlang/inox/internal/globals/fs_ns"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSymbolOccurrences(t *testing.T) {
+
+	if core.NewDefaultContext == nil {
+		core.SetNewDefaultContext(func(config core.DefaultContextConfig) (*core.Context, error) {
+			ctx := core.NewContext(core.ContextConfig{
+				Permissions:   config.Permissions,
+				ParentContext: config.ParentContext,
+				Filesystem:    config.Filesystem,
+			})
+
+			for name, pattern := range core.DEFAULT_NAMED_PATTERNS {
+				ctx.AddNamedPattern(name, pattern)
+			}
+			return ctx, nil
+		})
+		core.SetNewDefaultGlobalStateFn(func(ctx *core.Context, conf core.DefaultGlobalStateConfig) (*core.GlobalState, error) {
+			state := core.NewGlobalState(ctx)
+			state.Out = io.Discard
+			state.Logger = zerolog.Nop()
+			state.OutputFieldsInitialized.Store(true)
+			return state, nil
+		})
+		defer core.UnsetNewDefaultContext()
+		defer core.UnsetNewDefaultGlobalStateFn()
+	}
+
+	setup := func(t *testing.T, files map[string]string) (preparationResult, bool) {
+		fls := fs_ns.NewMemFilesystem(10_000)
+		for path, content := range files {
+			util.WriteFile(fls, path, []byte(content), 0600)
+		}
+
+		parsingCtx := core.NewContexWithEmptyState(core.ContextConfig{
+			Filesystem:  fls,
+			Permissions: []core.Permission{core.FilesystemPermission{Kind_: permkind.Read, Entity: core.PathPattern("/...")}},
+		}, nil)
+		defer parsingCtx.CancelGracefully()
+
+		state, mod, _, _ := core.PrepareLocalModule(core.ModulePreparationArgs{
+			Fpath:                     "/main.ix",
+			ParsingCompilationContext: parsingCtx,
+			PreinitFilesystem:         fls,
+			ScriptContextFileSystem:   fls,
+			Out:                       io.Discard,
+			LogOut:                    io.Discard,
+			DataExtractionMode:        true,
+		})
+
+		if !assert.NotNil(t, state) {
+			return preparationResult{}, false
+		}
+		t.Cleanup(func() { state.Ctx.CancelGracefully() })
+
+		return preparationResult{state: state, module: mod, chunk: mod.MainChunk}, true
+	}
+
+	getOccurrenceStrings := func(occurrences []symbolOccurrence) (positions []string) {
+		for _, occurrence := range occurrences {
+			positions = append(positions, occurrence.nameRange.String())
+		}
+		return
+	}
+
+	t.Run("module-level variable", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\na = 1\nprint(a, $a, {a: a}.a)",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, nameRange, err := getSymbolAt(3, 7, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "/main.ix:3:7:", nameRange.String())
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:2:1:", "/main.ix:3:7:", "/main.ix:3:11:", "/main.ix:3:18:"}, getOccurrenceStrings(occurrences))
+		assert.True(t, occurrences[0].isDefinition)
+	})
+
+	t.Run("local variable", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(a int){ return a }\nfn g(){ a = 1; return $a }",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, _, err := getSymbolAt(3, 24, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:3:9:", "/main.ix:3:24:"}, getOccurrenceStrings(occurrences))
+	})
+
+	t.Run("function", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\nfn f(){}\nf()\nf()",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, _, err := getSymbolAt(2, 4, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:2:4:", "/main.ix:3:1:", "/main.ix:4:1:"}, getOccurrenceStrings(occurrences))
+	})
+
+	t.Run("pattern defined in an included chunk", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\nimport ./lib.ix\nv = %user\nv2 = %user",
+			"/lib.ix":  "includable-chunk\npattern user = {name: str}",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, nameRange, err := getSymbolAt(3, 6, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "/main.ix:3:6:", nameRange.String())
+		assert.Equal(t, "/lib.ix", symbol.definition.SourceName)
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:3:6:", "/main.ix:4:7:", "/lib.ix:2:9:"}, getOccurrenceStrings(occurrences))
+	})
+
+	t.Run("pattern namespace", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\npnamespace ns. = {a: 1}\nv = %ns.a",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, _, err := getSymbolAt(3, 6, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:2:12:", "/main.ix:3:6:"}, getOccurrenceStrings(occurrences))
+	})
+
+	t.Run("imported module", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\nimport lib /lib.ix {}\nprint(lib)",
+			"/lib.ix":  "manifest {}",
+		})
+		if !ok {
+			return
+		}
+
+		symbol, _, err := getSymbolAt(3, 7, prepResult.chunk, prepResult.state)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		occurrences := findSymbolOccurrencesInModule(symbol, prepResult)
+		assert.Equal(t, []string{"/main.ix:2:8:", "/main.ix:3:7:"}, getOccurrenceStrings(occurrences))
+	})
+
+	t.Run("builtins cannot be renamed", func(t *testing.T) {
+		prepResult, ok := setup(t, map[string]string{
+			"/main.ix": "manifest {}\nv = %int",
+		})
+		if !ok {
+			return
+		}
+
+		_, _, err := getSymbolAt(2, 6, prepResult.chunk, prepResult.state)
+		assert.ErrorIs(t, err, ErrBuiltinsCannotBeRenamed)
+	})
+}
+
+func TestCheckNewSymbolName(t *testing.T) {
+	assert.NoError(t, checkNewSymbolName("user-name"))
+	assert.Error(t, checkNewSymbolName(""))
+	assert.Error(t, checkNewSymbolName("$a"))
+	assert.Error(t, checkNewSymbolName("1a"))
+	assert.Error(t, checkNewSymbolName(parse.KEYWORDS[0]))
+}
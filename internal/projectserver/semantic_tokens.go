@@ -0,0 +1,652 @@
+package projectserver
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/projectserver/lsp"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	SEMANTIC_TOKENS_FULL_METHOD       = "textDocument/semanticTokens/full"
+	SEMANTIC_TOKENS_FULL_DELTA_METHOD = "textDocument/semanticTokens/full/delta"
+	SEMANTIC_TOKENS_RANGE_METHOD      = "textDocument/semanticTokens/range"
+)
+
+// semantic token types, the order should match SEMANTIC_TOKEN_LEGEND.TokenTypes.
+const (
+	namespaceToken semanticTokenType = iota
+	typeToken
+	parameterToken
+	variableToken
+	propertyToken
+	functionToken
+	keywordToken
+	commentToken
+	stringToken
+	numberToken
+	regexpToken
+)
+
+// semantic token modifiers, the order should match SEMANTIC_TOKEN_LEGEND.TokenModifiers.
+const (
+	declarationModifier semanticTokenModifiers = 1 << iota
+	readonlyModifier
+	defaultLibraryModifier //Go functions and builtin patterns
+	globalModifier
+	databaseModifier
+	secretModifier
+	sensitiveModifier
+)
+
+var (
+	SEMANTIC_TOKEN_LEGEND = defines.SemanticTokensLegend{
+		TokenTypes: []string{
+			string(defines.SemanticTokenTypesNamespace),
+			string(defines.SemanticTokenTypesType),
+			string(defines.SemanticTokenTypesParameter),
+			string(defines.SemanticTokenTypesVariable),
+			string(defines.SemanticTokenTypesProperty),
+			string(defines.SemanticTokenTypesFunction),
+			string(defines.SemanticTokenTypesKeyword),
+			string(defines.SemanticTokenTypesComment),
+			string(defines.SemanticTokenTypesString),
+			string(defines.SemanticTokenTypesNumber),
+			string(defines.SemanticTokenTypesRegexp),
+		},
+		TokenModifiers: []string{
+			string(defines.SemanticTokenModifiersDeclaration),
+			string(defines.SemanticTokenModifiersReadonly),
+			string(defines.SemanticTokenModifiersDefaultLibrary),
+			"global",
+			"database",
+			"secret",
+			"sensitive",
+		},
+	}
+)
+
+type semanticTokenType uint
+type semanticTokenModifiers uint
+
+// A semanticToken is a classified token located on a single line, line and character are 0-indexed.
+type semanticToken struct {
+	line, character, length uint
+	tokenType               semanticTokenType
+	modifiers               semanticTokenModifiers
+}
+
+type classifiedSpan struct {
+	span      parse.NodeSpan
+	tokenType semanticTokenType
+	modifiers semanticTokenModifiers
+}
+
+func registerSemanticTokensMethodHandlers(server *lsp.Server) {
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: SEMANTIC_TOKENS_FULL_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.SemanticTokensParams{}
+		},
+		Handler: handleSemanticTokensFull,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: SEMANTIC_TOKENS_FULL_DELTA_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.SemanticTokensDeltaParams{}
+		},
+		Handler: handleSemanticTokensFullDelta,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: SEMANTIC_TOKENS_RANGE_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.SemanticTokensRangeParams{}
+		},
+		Handler: handleSemanticTokensRange,
+	})
+}
+
+func handleSemanticTokensFull(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.SemanticTokensParams)
+	session := jsonrpc.GetSession(ctx)
+
+	fpath, tokens, ok := getSemanticTokensOfDocument(params.TextDocument.Uri, session)
+	if !ok {
+		return defines.SemanticTokens{}, nil
+	}
+
+	data := encodeSemanticTokens(tokens)
+	resultId := getSessionData(session).semanticTokens.store(fpath, data)
+
+	return defines.SemanticTokens{
+		ResultId: &resultId,
+		Data:     data,
+	}, nil
+}
+
+func handleSemanticTokensFullDelta(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.SemanticTokensDeltaParams)
+	session := jsonrpc.GetSession(ctx)
+
+	fpath, tokens, ok := getSemanticTokensOfDocument(params.TextDocument.Uri, session)
+	if !ok {
+		return defines.SemanticTokens{}, nil
+	}
+
+	data := encodeSemanticTokens(tokens)
+	cache := getSessionData(session).semanticTokens
+
+	previousData, hasPrevious := cache.get(fpath, params.PreviousResultId)
+	resultId := cache.store(fpath, data)
+
+	if !hasPrevious {
+		//the client will replace all the tokens.
+		return defines.SemanticTokens{
+			ResultId: &resultId,
+			Data:     data,
+		}, nil
+	}
+
+	return defines.SemanticTokensDelta{
+		ResultId: &resultId,
+		Edits:    computeSemanticTokensEdits(previousData, data),
+	}, nil
+}
+
+func handleSemanticTokensRange(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.SemanticTokensRangeParams)
+	session := jsonrpc.GetSession(ctx)
+
+	_, tokens, ok := getSemanticTokensOfDocument(params.TextDocument.Uri, session)
+	if !ok {
+		return defines.SemanticTokens{}, nil
+	}
+
+	var tokensInRange []semanticToken
+	for _, token := range tokens {
+		if isSemanticTokenInRange(token, params.Range) {
+			tokensInRange = append(tokensInRange, token)
+		}
+	}
+
+	return defines.SemanticTokens{
+		Data: encodeSemanticTokens(tokensInRange),
+	}, nil
+}
+
+// getSemanticTokensOfDocument prepares the document in extraction mode and computes its semantic tokens.
+// If the preparation fails the tokens are computed without symbolic data.
+func getSemanticTokensOfDocument(uri defines.DocumentUri, session *jsonrpc.Session) (fpath string, _ []semanticToken, _ bool) {
+	sessionCtx := session.Context()
+
+	//----------------------------------------
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+	//----------------------------------------
+
+	if fls == nil {
+		return "", nil, false
+	}
+
+	fpath, err := getFilePath(uri, projectMode)
+	if err != nil {
+		logs.Println(err)
+		return "", nil, false
+	}
+
+	handlingCtx := sessionCtx.BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	preparationResult, ok := prepareSourceFileInExtractionMode(handlingCtx, filePreparationParams{
+		fpath:         fpath,
+		session:       session,
+		requiresState: true,
+	})
+
+	if ok && preparationResult.state != nil && !preparationResult.cachedOrGotCache {
+		//teardown in separate goroutine to return quickly
+		defer func() {
+			go func() {
+				defer utils.Recover()
+				preparationResult.state.Ctx.CancelGracefully()
+			}()
+		}()
+	}
+
+	chunk := preparationResult.chunk
+	var data *symbolic.Data
+
+	if ok && preparationResult.state != nil && preparationResult.state.SymbolicData != nil {
+		data = preparationResult.state.SymbolicData.Data
+	}
+
+	if chunk == nil {
+		chunk, _ = core.ParseFileChunk(fpath, fls)
+		if chunk == nil { //unrecoverable error
+			return "", nil, false
+		}
+	}
+
+	return fpath, getSemanticTokens(chunk, data), true
+}
+
+// getSemanticTokens classifies the tokens of a chunk, $data is used to distinguish globals from locals,
+// Go functions from Inox functions, readonly from mutable bindings, ... $data can be nil.
+// Punctuation and operators are not included.
+func getSemanticTokens(chunk *parse.ParsedChunkSource, data *symbolic.Data) []semanticToken {
+	var spans []classifiedSpan
+
+	//literals, keywords and comments
+
+	for _, token := range parse.GetTokens(chunk.Node, chunk.Node, false) {
+		tokenType, ok := getStaticSemanticTokenType(token.Type)
+		if ok {
+			spans = append(spans, classifiedSpan{span: token.Span, tokenType: tokenType})
+		}
+	}
+
+	//identifiers
+
+	parse.Walk(chunk.Node, func(node, parent, scopeNode parse.Node, ancestors []parse.Node, after bool) (parse.TraversalAction, error) {
+		tokenType, modifiers, ok := classifyIdentifier(node, parent, ancestors, chunk, data)
+		if ok {
+			spans = append(spans, classifiedSpan{
+				span:      node.Base().Span,
+				tokenType: tokenType,
+				modifiers: modifiers,
+			})
+		}
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].span.Start < spans[j].span.Start
+	})
+
+	//split the spans into single-line tokens, overlapping spans are ignored.
+
+	runes := chunk.Runes()
+	var tokens []semanticToken
+	var line, lineStart int32
+	var prevEnd int32
+	var pos int32
+
+	advance := func(target int32) {
+		for ; pos < target && pos < int32(len(runes)); pos++ {
+			if runes[pos] == '\n' {
+				line++
+				lineStart = pos + 1
+			}
+		}
+	}
+
+	for _, span := range spans {
+		if span.span.Start < prevEnd || span.span.End <= span.span.Start {
+			continue
+		}
+		prevEnd = span.span.End
+		advance(span.span.Start)
+
+		start := span.span.Start
+		for start < span.span.End {
+			end := start
+			for end < span.span.End && end < int32(len(runes)) && runes[end] != '\n' {
+				end++
+			}
+
+			if end > start {
+				tokens = append(tokens, semanticToken{
+					line:      uint(line),
+					character: uint(start - lineStart),
+					length:    uint(end - start),
+					tokenType: span.tokenType,
+					modifiers: span.modifiers,
+				})
+			}
+
+			if end >= span.span.End || end >= int32(len(runes)) {
+				break
+			}
+			//skip the newline character
+			start = end + 1
+			advance(start)
+		}
+	}
+
+	return tokens
+}
+
+func getStaticSemanticTokenType(tokenType parse.TokenType) (semanticTokenType, bool) {
+	switch tokenType {
+	case parse.COMMENT:
+		return commentToken, true
+	case parse.QUOTED_STRING_LITERAL, parse.MULTILINE_STRING_LITERAL, parse.STR_TEMPLATE_SLICE, parse.RUNE_LITERAL:
+		return stringToken, true
+	case parse.INT_LITERAL, parse.FLOAT_LITERAL, parse.PORT_LITERAL, parse.RATE_LITERAL, parse.QUANTITY_LITERAL,
+		parse.YEAR_LITERAL, parse.DATE_LITERAL, parse.DATETIME_LITERAL:
+		return numberToken, true
+	case parse.REGEX_LITERAL:
+		return regexpToken, true
+	case parse.SELF_KEYWORD, parse.BOOLEAN_LITERAL, parse.NIL_LITERAL:
+		return keywordToken, true
+	}
+	if tokenType >= parse.IF_KEYWORD && tokenType <= parse.OR_KEYWORD {
+		return keywordToken, true
+	}
+	return 0, false
+}
+
+func classifyIdentifier(
+	node, parent parse.Node, ancestors []parse.Node,
+	chunk *parse.ParsedChunkSource, data *symbolic.Data,
+) (tokenType semanticTokenType, modifiers semanticTokenModifiers, ok bool) {
+
+	switch n := node.(type) {
+	case *parse.PatternIdentifierLiteral:
+		if def, ok := parent.(*parse.PatternDefinition); ok && def.Left == n {
+			modifiers |= declarationModifier
+		} else if data != nil {
+			if _, found := data.GetNamedPatternOrPatternNamespacePositionDefinition(n, ancestors); !found {
+				modifiers |= defaultLibraryModifier
+			}
+		}
+		return typeToken, modifiers, true
+	case *parse.PatternNamespaceIdentifierLiteral:
+		if def, ok := parent.(*parse.PatternNamespaceDefinition); ok && def.Left == n {
+			modifiers |= declarationModifier
+		} else if data != nil {
+			if _, found := data.GetNamedPatternOrPatternNamespacePositionDefinition(n, ancestors); !found {
+				modifiers |= defaultLibraryModifier
+			}
+		}
+		return namespaceToken, modifiers, true
+	case *parse.PropertyNameLiteral:
+		return propertyToken, getPropertyModifiers(n.Name), true
+	case *parse.Variable:
+		tokenType, modifiers = classifyVariable(n.Name, n, ancestors, chunk, data, true, false)
+		return tokenType, modifiers, true
+	case *parse.GlobalVariable:
+		tokenType, modifiers = classifyVariable(n.Name, n, ancestors, chunk, data, false, true)
+		return tokenType, modifiers, true
+	case *parse.IdentifierLiteral:
+		switch parent.(type) {
+		case *parse.PatternNamespaceMemberExpression:
+			return typeToken, 0, true
+		case *parse.FunctionParameter:
+			return parameterToken, declarationModifier, true
+		case *parse.FunctionDeclaration:
+			//functions declared at the top level are global constants.
+			return functionToken, declarationModifier | readonlyModifier | globalModifier, true
+		case *parse.XMLOpeningElement, *parse.XMLClosingElement, *parse.XMLAttribute:
+			return 0, 0, false
+		case *parse.KeyListExpression:
+			return propertyToken, getPropertyModifiers(n.Name), true
+		}
+
+		if !isIdentifierUsedAsVariable(n, parent) {
+			return propertyToken, getPropertyModifiers(n.Name), true
+		}
+
+		tokenType, modifiers = classifyVariable(n.Name, n, ancestors, chunk, data, true, true)
+		return tokenType, modifiers, true
+	}
+
+	return
+}
+
+func classifyVariable(
+	name string, node parse.Node, ancestors []parse.Node,
+	chunk *parse.ParsedChunkSource, data *symbolic.Data,
+	local, global bool,
+) (tokenType semanticTokenType, modifiers semanticTokenModifiers) {
+	tokenType = variableToken
+
+	if _, ok := core.SELF_SENSITIVE_DATA_NAMES[name]; ok {
+		modifiers |= sensitiveModifier
+	}
+
+	if data == nil {
+		if !local {
+			modifiers |= globalModifier
+		}
+		return
+	}
+
+	var varData symbolic.VarData
+	found := false
+	isGlobal := false
+
+	if local {
+		if scopeData, ok := data.GetLocalScopeData(node, ancestors); ok {
+			varData, found = findVarData(scopeData, name)
+		}
+	}
+
+	if !found && global {
+		if scopeData, ok := data.GetGlobalScopeData(node, ancestors); ok {
+			varData, found = findVarData(scopeData, name)
+			isGlobal = found
+		}
+	}
+
+	if !found {
+		//the scope data of a statement does not include the variables it declares.
+		if len(ancestors) > 0 && isVariableDeclaration(node, ancestors[len(ancestors)-1]) {
+			modifiers |= declarationModifier
+		}
+		if !local {
+			modifiers |= globalModifier
+		}
+		return
+	}
+
+	nodePosition := chunk.GetSourcePosition(node.Base().Span)
+
+	if isGlobal {
+		modifiers |= globalModifier
+		if varData.DefinitionPosition == (parse.SourcePositionRange{}) {
+			modifiers |= defaultLibraryModifier
+		}
+	} else if isFunctionParameter(name, varData.DefinitionPosition, ancestors, chunk) {
+		tokenType = parameterToken
+	}
+
+	if varData.IsConstant {
+		modifiers |= readonlyModifier
+	}
+	if varData.DefinitionPosition == nodePosition {
+		modifiers |= declarationModifier
+	}
+
+	value, ok := data.GetMostSpecificNodeValue(node)
+	if !ok {
+		value = varData.Value
+	}
+
+	switch value.(type) {
+	case *symbolic.InoxFunction:
+		tokenType = functionToken
+	case *symbolic.GoFunction:
+		tokenType = functionToken
+		modifiers |= defaultLibraryModifier
+	case *symbolic.DatabaseIL:
+		modifiers |= databaseModifier
+	case *symbolic.Secret:
+		modifiers |= secretModifier
+	case *symbolic.EmailAddress:
+		modifiers |= sensitiveModifier
+	}
+
+	return
+}
+
+func isVariableDeclaration(node, parent parse.Node) bool {
+	switch p := parent.(type) {
+	case *parse.Assignment:
+		return p.Left == node && p.Operator == parse.Assign
+	case *parse.MultiAssignment:
+		return slices.Contains(p.Variables, node)
+	case *parse.LocalVariableDeclaration:
+		return p.Left == node
+	case *parse.GlobalVariableDeclaration:
+		return p.Left == node
+	}
+	return false
+}
+
+func findVarData(scopeData symbolic.ScopeData, name string) (symbolic.VarData, bool) {
+	for _, varData := range scopeData.Variables {
+		if varData.Name == name {
+			return varData, true
+		}
+	}
+	return symbolic.VarData{}, false
+}
+
+// isFunctionParameter returns true if a variable defined at $definition is a parameter of the closest function.
+func isFunctionParameter(name string, definition parse.SourcePositionRange, ancestors []parse.Node, chunk *parse.ParsedChunkSource) bool {
+	fnExpr, _, ok := parse.FindClosest(ancestors, (*parse.FunctionExpression)(nil))
+	if !ok {
+		return false
+	}
+
+	for _, param := range fnExpr.Parameters {
+		if param.Var != nil && param.Var.Name == name && chunk.GetSourcePosition(param.Var.Span) == definition {
+			return true
+		}
+	}
+	return false
+}
+
+func getPropertyModifiers(name string) semanticTokenModifiers {
+	if _, ok := core.SELF_SENSITIVE_DATA_NAMES[name]; ok {
+		return sensitiveModifier
+	}
+	return 0
+}
+
+func isSemanticTokenInRange(token semanticToken, r defines.Range) bool {
+	if token.line < r.Start.Line || token.line > r.End.Line {
+		return false
+	}
+	if token.line == r.Start.Line && token.character+token.length <= r.Start.Character {
+		return false
+	}
+	if token.line == r.End.Line && token.character >= r.End.Character {
+		return false
+	}
+	return true
+}
+
+// encodeSemanticTokens encodes the tokens using the relative format of the LSP specification,
+// the tokens are expected to be sorted by position.
+func encodeSemanticTokens(tokens []semanticToken) []uint {
+	data := make([]uint, 0, 5*len(tokens))
+	var prevLine, prevCharacter uint
+
+	for _, token := range tokens {
+		deltaLine := token.line - prevLine
+		deltaCharacter := token.character
+		if deltaLine == 0 {
+			deltaCharacter -= prevCharacter
+		}
+
+		data = append(data, deltaLine, deltaCharacter, token.length, uint(token.tokenType), uint(token.modifiers))
+		prevLine = token.line
+		prevCharacter = token.character
+	}
+
+	return data
+}
+
+// computeSemanticTokensEdits computes a single edit transforming $prev into $current,
+// the common prefix and suffix are not included in the edit.
+func computeSemanticTokensEdits(prev, current []uint) []defines.SemanticTokensEdit {
+	if slices.Equal(prev, current) {
+		return []defines.SemanticTokensEdit{}
+	}
+
+	prefixLen := 0
+	for prefixLen < len(prev) && prefixLen < len(current) && prev[prefixLen] == current[prefixLen] {
+		prefixLen++
+	}
+
+	suffixLen := 0
+	for suffixLen < len(prev)-prefixLen && suffixLen < len(current)-prefixLen &&
+		prev[len(prev)-1-suffixLen] == current[len(current)-1-suffixLen] {
+		suffixLen++
+	}
+
+	inserted := slices.Clone(current[prefixLen : len(current)-suffixLen])
+
+	return []defines.SemanticTokensEdit{
+		{
+			Start:       uint(prefixLen),
+			DeleteCount: uint(len(prev) - prefixLen - suffixLen),
+			Data:        &inserted,
+		},
+	}
+}
+
+// semanticTokensCache stores the last semantic tokens sent for each document, it allows the server to
+// only send the edits for the next request.
+type semanticTokensCache struct {
+	lock         sync.Mutex
+	nextResultId int64
+	results      map[ /*fpath*/ string]cachedSemanticTokens
+}
+
+type cachedSemanticTokens struct {
+	resultId string
+	data     []uint
+}
+
+func newSemanticTokensCache() *semanticTokensCache {
+	return &semanticTokensCache{
+		results: map[string]cachedSemanticTokens{},
+	}
+}
+
+func (c *semanticTokensCache) store(fpath string, data []uint) (resultId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nextResultId++
+	resultId = strconv.FormatInt(c.nextResultId, 10)
+	c.results[fpath] = cachedSemanticTokens{resultId: resultId, data: data}
+	return
+}
+
+func (c *semanticTokensCache) get(fpath string, resultId string) ([]uint, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result, ok := c.results[fpath]
+	if !ok || result.resultId != resultId {
+		return nil, false
+	}
+	return result.data, true
+}
+
+func (c *semanticTokensCache) remove(fpath string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.results, fpath)
+}
@@ -0,0 +1,136 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSemanticTokens(t *testing.T) {
+
+	findToken := func(tokens []semanticToken, line, character uint) (semanticToken, bool) {
+		for _, token := range tokens {
+			if token.line == line && token.character == character {
+				return token, true
+			}
+		}
+		return semanticToken{}, false
+	}
+
+	t.Run("without symbolic data", func(t *testing.T) {
+		chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+			NameString: "/main.ix",
+			CodeString: "manifest {}\n# a\na = \"s\"\nprint(`x\ny`, %int)",
+		}))
+
+		tokens := getSemanticTokens(chunk, nil)
+
+		assert.Equal(t, []semanticToken{
+			{line: 0, character: 0, length: 8, tokenType: keywordToken},
+			{line: 1, character: 0, length: 3, tokenType: commentToken},
+			{line: 2, character: 0, length: 1, tokenType: variableToken},
+			{line: 2, character: 4, length: 3, tokenType: stringToken},
+			{line: 3, character: 0, length: 5, tokenType: variableToken},
+			//multiline tokens are split.
+			{line: 3, character: 6, length: 2, tokenType: stringToken},
+			{line: 4, character: 0, length: 2, tokenType: stringToken},
+			{line: 4, character: 4, length: 4, tokenType: typeToken},
+		}, tokens)
+	})
+
+	t.Run("with symbolic data", func(t *testing.T) {
+		prepResult, ok := prepareTestModule(t, map[string]string{
+			"/main.ix": "const (\n  C = 1\n)\nmanifest {}\n" +
+				"pattern user = {name: str}\n" +
+				"fn f(a int){ return a }\n" +
+				"b = f(C)\n" +
+				"o = {password: \"x\"}\n" +
+				"print(%user, %int, b, $$C, o.password)",
+		})
+		if !ok {
+			return
+		}
+
+		tokens := getSemanticTokens(prepResult.chunk, prepResult.state.SymbolicData.Data)
+
+		assertToken := func(line, character uint, tokenType semanticTokenType, modifiers semanticTokenModifiers) {
+			t.Helper()
+			token, ok := findToken(tokens, line, character)
+			if assert.True(t, ok, "no token at %d:%d", line, character) {
+				assert.Equal(t, tokenType, token.tokenType, "type of the token at %d:%d", line, character)
+				assert.Equal(t, modifiers, token.modifiers, "modifiers of the token at %d:%d", line, character)
+			}
+		}
+
+		//global constant
+		assertToken(1, 2, variableToken, declarationModifier|readonlyModifier|globalModifier)
+		assertToken(8, 22, variableToken, readonlyModifier|globalModifier)
+
+		//patterns
+		assertToken(4, 8, typeToken, declarationModifier)
+		assertToken(8, 6, typeToken, 0)
+		assertToken(8, 13, typeToken, defaultLibraryModifier)
+
+		//function and parameter
+		assertToken(5, 3, functionToken, declarationModifier|readonlyModifier|globalModifier)
+		assertToken(5, 5, parameterToken, declarationModifier)
+		assertToken(5, 20, parameterToken, 0)
+		assertToken(6, 4, functionToken, readonlyModifier|globalModifier)
+
+		//locals
+		assertToken(6, 0, variableToken, declarationModifier)
+		assertToken(8, 19, variableToken, 0)
+
+		//sensitive property
+		assertToken(7, 5, propertyToken, sensitiveModifier)
+		assertToken(8, 29, propertyToken, sensitiveModifier)
+	})
+}
+
+func TestEncodeSemanticTokens(t *testing.T) {
+	data := encodeSemanticTokens([]semanticToken{
+		{line: 0, character: 2, length: 3, tokenType: variableToken, modifiers: globalModifier},
+		{line: 0, character: 7, length: 1, tokenType: numberToken},
+		{line: 2, character: 4, length: 2, tokenType: keywordToken},
+	})
+
+	assert.Equal(t, []uint{
+		0, 2, 3, uint(variableToken), uint(globalModifier),
+		0, 5, 1, uint(numberToken), 0,
+		2, 4, 2, uint(keywordToken), 0,
+	}, data)
+}
+
+func TestComputeSemanticTokensEdits(t *testing.T) {
+	assert.Empty(t, computeSemanticTokensEdits([]uint{1, 2, 3}, []uint{1, 2, 3}))
+
+	edits := computeSemanticTokensEdits([]uint{1, 2, 3, 4, 5}, []uint{1, 2, 7, 8, 4, 5})
+	if assert.Len(t, edits, 1) {
+		assert.Equal(t, uint(2), edits[0].Start)
+		assert.Equal(t, uint(1), edits[0].DeleteCount)
+		assert.Equal(t, []uint{7, 8}, *edits[0].Data)
+	}
+
+	edits = computeSemanticTokensEdits([]uint{1, 2, 3}, []uint{1})
+	if assert.Len(t, edits, 1) {
+		assert.Equal(t, uint(1), edits[0].Start)
+		assert.Equal(t, uint(2), edits[0].DeleteCount)
+		assert.Empty(t, *edits[0].Data)
+	}
+}
+
+func TestIsSemanticTokenInRange(t *testing.T) {
+	r := defines.Range{
+		Start: defines.Position{Line: 1, Character: 4},
+		End:   defines.Position{Line: 3, Character: 2},
+	}
+
+	assert.False(t, isSemanticTokenInRange(semanticToken{line: 0, character: 0, length: 2}, r))
+	assert.False(t, isSemanticTokenInRange(semanticToken{line: 1, character: 0, length: 4}, r))
+	assert.True(t, isSemanticTokenInRange(semanticToken{line: 1, character: 2, length: 4}, r))
+	assert.True(t, isSemanticTokenInRange(semanticToken{line: 2, character: 0, length: 1}, r))
+	assert.False(t, isSemanticTokenInRange(semanticToken{line: 3, character: 2, length: 1}, r))
+}
@@ -127,6 +127,26 @@ func TestSignatureHelpAt(t *testing.T) {
 		assert.EqualValues(t, "b int", (*signature.Parameters)[1].Label)
 	})
 
+	t.Run("call of a generic function; single argument", func(t *testing.T) {
+		state, ok := setup("manifest {}\nfn f<T>(a T, b []T){}\nf(1)")
+		if !ok {
+			return
+		}
+
+		help, ok := getSignatureHelpAt(3, 3, state.Module.MainChunk, state)
+		if !assert.True(t, ok) {
+			return
+		}
+		if !assert.NotEmpty(t, help.Signatures) {
+			return
+		}
+		signature := help.Signatures[0]
+
+		//the parameters of the instantiated function should be shown.
+		assert.EqualValues(t, "a int", (*signature.Parameters)[0].Label)
+		assert.EqualValues(t, "b []int", (*signature.Parameters)[1].Label)
+	})
+
 	t.Run("call of a two-param function; single argument + comma", func(t *testing.T) {
 		state, ok := setup("manifest {}\nfn f(a int, b int){}\nf(1,)")
 		if !ok {
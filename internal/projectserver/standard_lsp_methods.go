@@ -63,8 +63,13 @@ type additionalSessionData struct {
 
 	serverAPI *serverAPI //set during project opening
 
+	workspaceSymbols *workspaceSymbolIndex
+	semanticTokens   *semanticTokensCache
+
 	//testing
-	testRuns map[TestRunId]*TestRun
+	testRuns        map[TestRunId]*TestRun
+	testWatchMode   *testWatchMode //nil if the watch mode is disabled
+	testItemResults *testItemResults
 
 	//debug adapter protocol
 	debugSessions *DebugSessions
@@ -77,6 +82,15 @@ func (d *additionalSessionData) Scheme() string {
 	return "file"
 }
 
+// getOrCreatePreparedFileCache returns the prepared file cache of the session, it is assumed that
+// the session data has been locked by the caller.
+func (d *additionalSessionData) getOrCreatePreparedFileCache() *preparedFileCache {
+	if d.preparedSourceFilesCache == nil {
+		d.preparedSourceFilesCache = newPreparedFileCache()
+	}
+	return d.preparedSourceFilesCache
+}
+
 func getLockedSessionData(session *jsonrpc.Session) *additionalSessionData {
 	sessionData := getSessionData(session)
 	sessionData.lock.Lock()
@@ -91,6 +105,9 @@ func getSessionData(session *jsonrpc.Session) *additionalSessionData {
 			didSaveCapabilityRegistrationIds: make(map[defines.DocumentUri]uuid.UUID, 0),
 			unsavedDocumentSyncData:          make(map[string]*unsavedDocumentSyncData, 0),
 			testRuns:                         make(map[TestRunId]*TestRun, 0),
+			testItemResults:                  newTestItemResults(),
+			workspaceSymbols:                 newWorkspaceSymbolIndex(),
+			semanticTokens:                   newSemanticTokensCache(),
 		}
 		sessionToAdditionalData[session] = sessionData
 	}
@@ -124,6 +141,32 @@ func registerStandardMethodHandlers(server *lsp.Server, serverConfig LSPServerCo
 
 	server.OnDefinition(handleDefinition)
 
+	server.OnReferences(handleReferences)
+
+	server.OnPrepareRename(handlePrepareRename)
+
+	server.OnRenameRequest(handleRename)
+
+	server.OnDocumentSymbolWithSliceDocumentSymbol(handleDocumentSymbol)
+
+	server.OnWorkspaceSymbol(handleWorkspaceSymbol)
+
+	registerSemanticTokensMethodHandlers(server)
+
+	registerCallHierarchyMethodHandlers(server)
+
+	registerTypeHierarchyMethodHandlers(server)
+
+	registerCodeLensMethodHandlers(server)
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: INLAY_HINT_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.InlayHintParams{}
+		},
+		Handler: handleInlayHint,
+	})
+
 	//Document synchronization
 
 	server.OnDidOpenTextDocument(handleDidOpenDocument)
@@ -196,11 +239,30 @@ func handleInitialize(ctx context.Context, req *defines.InitializeParams, projec
 		RetriggerCharacters: &[]string{","},
 	}
 	s.Capabilities.WorkspaceSymbolProvider = true
+	s.Capabilities.DocumentSymbolProvider = true
 	s.Capabilities.DefinitionProvider = true
+	s.Capabilities.ReferencesProvider = true
+	s.Capabilities.RenameProvider = &defines.RenameOptions{
+		PrepareProvider: &True,
+	}
 	s.Capabilities.CodeActionProvider = &defines.CodeActionOptions{
 		CodeActionKinds: &[]defines.CodeActionKind{defines.CodeActionKindQuickFix},
 	}
 	s.Capabilities.DocumentFormattingProvider = true
+	s.Capabilities.SemanticTokensProvider = defines.SemanticTokensOptions{
+		Legend: SEMANTIC_TOKEN_LEGEND,
+		Range:  &True,
+		Full: defines.SemanticTokensFullOptions{
+			Delta: &True,
+		},
+	}
+	s.Capabilities.InlayHintProvider = true
+	s.Capabilities.CallHierarchyProvider = true
+	s.Capabilities.TypeHierarchyProvider = true
+	s.Capabilities.CodeLensProvider = &defines.CodeLensOptions{}
+	s.Capabilities.ExecuteCommandProvider = &defines.ExecuteCommandOptions{
+		Commands: []string{RUN_TEST_ITEM_COMMAND, OPEN_HANDLER_MODULE_URL_COMMAND},
+	}
 
 	if *req.Capabilities.TextDocument.Synchronization.DidSave && *req.Capabilities.TextDocument.Synchronization.DynamicRegistration {
 		s.Capabilities.TextDocumentSync = defines.TextDocumentSyncKindIncremental
@@ -564,6 +626,171 @@ func handleDefinition(ctx context.Context, req *defines.DefinitionParams) (resul
 	return &links, nil
 }
 
+func handleReferences(ctx context.Context, req *defines.ReferenceParams) (result *[]defines.Location, err error) {
+	session := jsonrpc.GetSession(ctx)
+	sessionCtx := session.Context()
+
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+
+	if fls == nil {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+
+	fpath, err := getFilePath(req.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+	line, column := getLineColumn(req.Position)
+
+	handlingCtx := sessionCtx.BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	locations, err := getReferences(fpath, line, column, req.Context.IncludeDeclaration, handlingCtx, session)
+	if err != nil {
+		logs.Println("failed to get references", err)
+		return nil, nil
+	}
+	return &locations, nil
+}
+
+func handlePrepareRename(ctx context.Context, req *defines.PrepareRenameParams) (result *defines.Range, err error) {
+	session := jsonrpc.GetSession(ctx)
+	sessionCtx := session.Context()
+
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+
+	if fls == nil {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+
+	fpath, err := getFilePath(req.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+	line, column := getLineColumn(req.Position)
+
+	handlingCtx := sessionCtx.BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	_, nameRange, err := getSymbolAtPosition(fpath, line, column, handlingCtx, session)
+	if err != nil {
+		return nil, jsonrpc.ResponseError{
+			Code:    jsonrpc.InvalidRequest.Code,
+			Message: err.Error(),
+		}
+	}
+
+	lspRange := rangeToLspRange(nameRange)
+	return &lspRange, nil
+}
+
+func handleRename(ctx context.Context, req *defines.RenameParams) (result *defines.WorkspaceEdit, err error) {
+	session := jsonrpc.GetSession(ctx)
+	sessionCtx := session.Context()
+
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+
+	if fls == nil {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+
+	fpath, err := getFilePath(req.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+	line, column := getLineColumn(req.Position)
+
+	handlingCtx := sessionCtx.BoundChildWithOptions(core.BoundChildContextOptions{
+		Filesystem: fls,
+	})
+	defer handlingCtx.CancelGracefully()
+
+	edit, err := getRenameEdit(fpath, line, column, req.NewName, handlingCtx, session)
+	if err != nil {
+		return nil, jsonrpc.ResponseError{
+			Code:    jsonrpc.InvalidRequest.Code,
+			Message: err.Error(),
+		}
+	}
+	return edit, nil
+}
+
+func handleDocumentSymbol(ctx context.Context, req *defines.DocumentSymbolParams) (result *[]defines.DocumentSymbol, err error) {
+	session := jsonrpc.GetSession(ctx)
+
+	//----------------------------------------
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	fls := sessionData.filesystem
+	sessionData.lock.Unlock()
+	//----------------------------------------
+
+	if fls == nil {
+		return nil, nil
+	}
+
+	fpath, err := getFilePath(req.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk, _ := core.ParseFileChunk(fpath, fls)
+	if chunk == nil { //unrecoverable error
+		return nil, nil
+	}
+
+	symbols := getDocumentSymbols(chunk)
+	return &symbols, nil
+}
+
+func handleWorkspaceSymbol(ctx context.Context, req *defines.WorkspaceSymbolParams) (result *[]defines.SymbolInformation, err error) {
+	session := jsonrpc.GetSession(ctx)
+
+	//----------------------------------------
+	sessionData := getLockedSessionData(session)
+	fls := sessionData.filesystem
+	index := sessionData.workspaceSymbols
+	sessionData.lock.Unlock()
+	//----------------------------------------
+
+	if fls == nil {
+		return nil, nil
+	}
+
+	symbols := []defines.SymbolInformation{}
+
+	for _, symbol := range index.search(req.Query, fls) {
+		info := defines.SymbolInformation{
+			Name: symbol.name,
+			Kind: symbol.kind,
+			Location: defines.Location{
+				Uri:   defines.DocumentUri(sessionData.Scheme() + "://" + symbol.position.SourceName),
+				Range: rangeToLspRange(symbol.position),
+			},
+		}
+		if symbol.containerName != "" {
+			containerName := symbol.containerName
+			info.ContainerName = &containerName
+		}
+		symbols = append(symbols, info)
+	}
+
+	return &symbols, nil
+}
+
 func handleFormatDocument(ctx context.Context, req *defines.DocumentFormattingParams) (result *[]defines.TextEdit, err error) {
 	session := jsonrpc.GetSession(ctx)
 
@@ -686,6 +913,7 @@ func handleDidSaveDocument(ctx context.Context, req *defines.DidSaveTextDocument
 	}
 
 	syncData, hasSyncData := sessionData.unsavedDocumentSyncData[fpath]
+	testWatchMode := sessionData.testWatchMode
 	sessionData.lock.Unlock()
 	//----------------------------------------
 
@@ -697,17 +925,20 @@ func handleDidSaveDocument(ctx context.Context, req *defines.DidSaveTextDocument
 		syncData.reactToDidChange(fls)
 	}
 
+	sessionData.workspaceSymbols.invalidate(fpath)
+
 	// The document's text should be included because we asked for it:
 	// a client/registerCapability request was sent for the textDocument/didSave method.
 	// After the document is saved we immediately unregister the capability. The only purpose is
 	// to get the initial content for a newly created file as no textDocument/didChange request
 	// is sent for the first modification.
-	if req.Text != nil {
-		fsErr := fsutil.WriteFile(fls.unsavedDocumentsFS(), fpath, []byte(*req.Text), 0700)
-		if fsErr != nil {
-			logs.Println("failed to update state of document", fpath+":", fsErr)
+	acknowledgeDocumentSave(fls, fpath, req.Text, func() {
+		if testWatchMode != nil {
+			testWatchMode.acknowledgeSave(fpath, session)
 		}
+	})
 
+	if req.Text != nil {
 		sessionData := getLockedSessionData(session)
 		registrationId, ok := sessionData.didSaveCapabilityRegistrationIds[req.TextDocument.Uri]
 
@@ -789,6 +1020,7 @@ func handleDidChangeDocument(ctx context.Context, req *defines.DidChangeTextDocu
 	}
 
 	sessionData.preparedSourceFilesCache.acknowledgeSourceFileChange(fpath)
+	sessionData.testItemResults.invalidate(fpath)
 
 	if syncFull {
 		fullDocumentText = req.ContentChanges[0].Text.(string)
@@ -819,22 +1051,17 @@ func handleDidChangeDocument(ctx context.Context, req *defines.DidChangeTextDocu
 			startLine, startColumn := getLineColumn(change.Range.Start)
 			endLine, endColumn := getLineColumn(change.Range.End)
 
-			chunk, err := parse.ParseChunkSource(parse.InMemorySource{
+			lastReplacementStirng = change.Text.(string)
+
+			//the positions of a change are relative to the content resulting from the previous changes.
+			//The document is not parsed here: it is parsed incrementally during the preparation of the file.
+			content := parse.NewParsedChunkSource(nil, parse.InMemorySource{
 				NameString: "script",
-				CodeString: beforeEditContentString,
+				CodeString: string(nextContent),
 			})
 
-			if err != nil && chunk == nil { //critical parsing error
-				return jsonrpc.ResponseError{
-					Code:    jsonrpc.InternalError.Code,
-					Message: fmt.Sprintf("failed to update state of document %s: critical parsing error: %s", fpath+":", err),
-				}
-			}
-
-			lastReplacementStirng = change.Text.(string)
-
-			lastRangeStart = chunk.GetLineColumnPosition(startLine, startColumn)
-			lastRangeExlusiveEnd = chunk.GetLineColumnPosition(endLine, endColumn)
+			lastRangeStart = content.GetLineColumnPosition(startLine, startColumn)
+			lastRangeExlusiveEnd = content.GetLineColumnPosition(endLine, endColumn)
 			rangeLength := lastRangeExlusiveEnd - lastRangeStart
 
 			afterRange := slices.Clone(nextContent[lastRangeStart+rangeLength:])
@@ -883,6 +1110,8 @@ func handleDidChangeDocument(ctx context.Context, req *defines.DidChangeTextDocu
 		}
 	}
 
+	sessionData.workspaceSymbols.invalidate(fpath)
+
 	return notifyDiagnostics(session, req.TextDocument.Uri, projectMode, fls)
 }
 
@@ -906,6 +1135,7 @@ func handleDidCloseDocument(ctx context.Context, req *defines.DidCloseTextDocume
 	}
 
 	delete(sessionData.unsavedDocumentSyncData, fpath)
+	sessionData.semanticTokens.remove(fpath)
 
 	//NOTE: the file cache is not removed because other modules may still need it
 	sessionData.lock.Unlock()
@@ -917,3 +1147,16 @@ func handleDidCloseDocument(ctx context.Context, req *defines.DidCloseTextDocume
 	}
 	return nil
 }
+
+// acknowledgeDocumentSave writes the saved text (if included) to the unsaved documents' filesystem and then calls
+// onSaved, this way the functions reacting to the save (e.g. the test watch mode) see the saved content.
+func acknowledgeDocumentSave(fls *Filesystem, fpath string, text *string, onSaved func()) {
+	if text != nil {
+		fsErr := fsutil.WriteFile(fls.unsavedDocumentsFS(), fpath, []byte(*text), 0700)
+		if fsErr != nil {
+			logs.Println("failed to update state of document", fpath+":", fsErr)
+		}
+	}
+
+	onSaved()
+}
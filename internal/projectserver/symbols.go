@@ -0,0 +1,417 @@
+package projectserver
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/afs"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/core/symbolic"
+	"github.com/inoxlang/inox/internal/inoxconsts"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+)
+
+const (
+	MAX_WORKSPACE_SYMBOL_RESULTS = 200
+)
+
+// An outlineItem is a symbol of the outline of a chunk: the manifest and its sections, functions, pattern definitions,
+// pattern namespace definitions, test suites, test cases and XML expressions.
+type outlineItem struct {
+	name           string
+	detail         string
+	kind           defines.SymbolKind
+	position       parse.SourcePositionRange
+	namePosition   parse.SourcePositionRange
+	children       []*outlineItem
+	workspaceLevel bool //if false the item is not included in the results of workspace/symbol requests
+}
+
+func (item *outlineItem) toDocumentSymbol() defines.DocumentSymbol {
+	symbol := defines.DocumentSymbol{
+		Name:           item.name,
+		Kind:           item.kind,
+		Range:          rangeToLspRange(item.position),
+		SelectionRange: rangeToLspRange(item.namePosition),
+	}
+
+	if item.detail != "" {
+		detail := item.detail
+		symbol.Detail = &detail
+	}
+
+	if len(item.children) > 0 {
+		children := make([]defines.DocumentSymbol, len(item.children))
+		for i, child := range item.children {
+			children[i] = child.toDocumentSymbol()
+		}
+		symbol.Children = &children
+	}
+
+	return symbol
+}
+
+// getOutline returns the outline of a chunk, the result only depends on the syntax.
+func getOutline(chunk *parse.ParsedChunkSource) []*outlineItem {
+	var (
+		rootItems []*outlineItem
+		nodeItems = map[parse.Node]*outlineItem{}
+	)
+
+	addItem := func(node parse.Node, ancestors []parse.Node, item *outlineItem) {
+		nodeItems[node] = item
+
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if parentItem, ok := nodeItems[ancestors[i]]; ok {
+				parentItem.children = append(parentItem.children, item)
+				return
+			}
+		}
+		rootItems = append(rootItems, item)
+	}
+
+	getPosition := func(node parse.Node) parse.SourcePositionRange {
+		return chunk.GetSourcePosition(node.Base().Span)
+	}
+
+	parse.Walk(chunk.Node, func(node, _, _ parse.Node, ancestors []parse.Node, _ bool) (parse.TraversalAction, error) {
+		switch n := node.(type) {
+		case *parse.Manifest:
+			manifestItem := &outlineItem{
+				name:         "manifest",
+				kind:         defines.SymbolKindModule,
+				position:     getPosition(n),
+				namePosition: chunk.GetSourcePosition(parse.NodeSpan{Start: n.Span.Start, End: n.Span.Start + int32(len("manifest"))}),
+			}
+			addItem(n, ancestors, manifestItem)
+
+			if objLit, ok := n.Object.(*parse.ObjectLiteral); ok {
+				for _, prop := range objLit.Properties {
+					if prop.HasImplicitKey() {
+						continue
+					}
+					manifestItem.children = append(manifestItem.children, &outlineItem{
+						name:         prop.Name(),
+						kind:         defines.SymbolKindProperty,
+						position:     getPosition(prop),
+						namePosition: getPosition(prop.Key),
+					})
+				}
+			}
+			return parse.Prune, nil
+		case *parse.FunctionDeclaration:
+			if n.Name == nil || n.Function == nil {
+				break
+			}
+
+			detail := ""
+			if n.Function.Body != nil {
+				runes := chunk.Runes()
+				detail = strings.TrimSpace(string(runes[n.Name.Span.End:n.Function.Body.Base().Span.Start]))
+			}
+
+			addItem(n, ancestors, &outlineItem{
+				name:           n.Name.Name,
+				detail:         detail,
+				kind:           defines.SymbolKindFunction,
+				position:       getPosition(n),
+				namePosition:   getPosition(n.Name),
+				workspaceLevel: true,
+			})
+		case *parse.PatternDefinition:
+			name, ok := n.PatternName()
+			if !ok {
+				break
+			}
+			addItem(n, ancestors, &outlineItem{
+				name:           "%" + name,
+				kind:           defines.SymbolKindInterface,
+				position:       getPosition(n),
+				namePosition:   getPosition(n.Left),
+				workspaceLevel: true,
+			})
+		case *parse.PatternNamespaceDefinition:
+			name, ok := n.NamespaceName()
+			if !ok {
+				break
+			}
+			addItem(n, ancestors, &outlineItem{
+				name:           "%" + name + ".",
+				kind:           defines.SymbolKindNamespace,
+				position:       getPosition(n),
+				namePosition:   getPosition(n.Left),
+				workspaceLevel: true,
+			})
+		case *parse.TestSuiteExpression:
+			addItem(n, ancestors, &outlineItem{
+				name:           getTestItemOutlineName("testsuite", n.Meta),
+				kind:           defines.SymbolKindModule,
+				position:       getPosition(n),
+				namePosition:   getTestItemNamePosition(n, n.Meta, chunk),
+				workspaceLevel: true,
+			})
+		case *parse.TestCaseExpression:
+			addItem(n, ancestors, &outlineItem{
+				name:           getTestItemOutlineName("testcase", n.Meta),
+				kind:           defines.SymbolKindMethod,
+				position:       getPosition(n),
+				namePosition:   getTestItemNamePosition(n, n.Meta, chunk),
+				workspaceLevel: true,
+			})
+		case *parse.XMLExpression:
+			if n.Element == nil || n.Element.Opening == nil {
+				break
+			}
+			tagName, ok := n.Element.Opening.Name.(*parse.IdentifierLiteral)
+			if !ok {
+				break
+			}
+
+			namespace := ""
+			if ident, ok := n.Namespace.(*parse.IdentifierLiteral); ok {
+				namespace = ident.Name
+			}
+
+			addItem(n, ancestors, &outlineItem{
+				name:           namespace + "<" + tagName.Name + ">",
+				kind:           defines.SymbolKindObject,
+				position:       getPosition(n),
+				namePosition:   getPosition(n.Element.Opening),
+				workspaceLevel: false,
+			})
+		}
+		return parse.ContinueTraversal, nil
+	}, nil)
+
+	return rootItems
+}
+
+func getTestItemOutlineName(keyword string, meta parse.Node) string {
+	var nameNode parse.Node = meta
+
+	if objLit, ok := meta.(*parse.ObjectLiteral); ok {
+		nameNode, _ = objLit.PropValue(symbolic.TEST_ITEM_META__NAME_PROPNAME)
+	}
+
+	switch n := nameNode.(type) {
+	case *parse.QuotedStringLiteral:
+		return keyword + " " + n.Raw
+	case *parse.MultilineStringLiteral:
+		return keyword + " " + n.Raw
+	}
+	return keyword
+}
+
+func getTestItemNamePosition(testItem parse.Node, meta parse.Node, chunk *parse.ParsedChunkSource) parse.SourcePositionRange {
+	if meta != nil {
+		return chunk.GetSourcePosition(meta.Base().Span)
+	}
+	span := testItem.Base().Span
+	//keyword
+	return chunk.GetSourcePosition(parse.NodeSpan{Start: span.Start, End: min(span.End, span.Start+int32(len("testsuite")))})
+}
+
+// getDocumentSymbols returns the hierarchical symbols of a chunk.
+func getDocumentSymbols(chunk *parse.ParsedChunkSource) []defines.DocumentSymbol {
+	symbols := []defines.DocumentSymbol{}
+	for _, item := range getOutline(chunk) {
+		symbols = append(symbols, item.toDocumentSymbol())
+	}
+	return symbols
+}
+
+// An indexedSymbol is a symbol of the workspace symbol index.
+type indexedSymbol struct {
+	name          string
+	kind          defines.SymbolKind
+	containerName string
+	position      parse.SourcePositionRange
+}
+
+// A workspaceSymbolIndex indexes the workspace-level symbols of the Inox files in a filesystem. The index is
+// updated incrementally: the files that changed are invalidated and re-indexed during the next search.
+type workspaceSymbolIndex struct {
+	lock       sync.Mutex
+	upToDate   bool //if false all the files are re-indexed during the next search
+	files      map[ /*fpath*/ string][]indexedSymbol
+	staleFiles map[ /*fpath*/ string]struct{}
+}
+
+func newWorkspaceSymbolIndex() *workspaceSymbolIndex {
+	return &workspaceSymbolIndex{
+		files:      map[string][]indexedSymbol{},
+		staleFiles: map[string]struct{}{},
+	}
+}
+
+// invalidate marks the file at path as stale, if path is not the path of an Inox file (e.g. a directory) the whole
+// index is invalidated.
+func (index *workspaceSymbolIndex) invalidate(path string) {
+	if index == nil {
+		return
+	}
+
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	if strings.HasSuffix(path, inoxconsts.INOXLANG_FILE_EXTENSION) {
+		index.staleFiles[path] = struct{}{}
+	} else {
+		index.upToDate = false
+	}
+}
+
+// search re-indexes the stale files and returns the symbols whose name matches query, the best matches come first.
+func (index *workspaceSymbolIndex) search(query string, fls afs.Filesystem) []indexedSymbol {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	index.update(fls)
+
+	type match struct {
+		symbol indexedSymbol
+		score  int
+	}
+
+	var matches []match
+
+	for _, symbols := range index.files {
+		for _, symbol := range symbols {
+			score, ok := fuzzyMatchSymbolName(query, symbol.name)
+			if ok {
+				matches = append(matches, match{symbol, score})
+			}
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.score != b.score {
+			return a.score - b.score
+		}
+		if a.symbol.name != b.symbol.name {
+			return strings.Compare(a.symbol.name, b.symbol.name)
+		}
+		if a.symbol.position.SourceName != b.symbol.position.SourceName {
+			return strings.Compare(a.symbol.position.SourceName, b.symbol.position.SourceName)
+		}
+		return int(a.symbol.position.Span.Start - b.symbol.position.Span.Start)
+	})
+
+	if len(matches) > MAX_WORKSPACE_SYMBOL_RESULTS {
+		matches = matches[:MAX_WORKSPACE_SYMBOL_RESULTS]
+	}
+
+	symbols := make([]indexedSymbol, len(matches))
+	for i, match := range matches {
+		symbols[i] = match.symbol
+	}
+	return symbols
+}
+
+// update should be called while the index is locked.
+func (index *workspaceSymbolIndex) update(fls afs.Filesystem) {
+	if !index.upToDate {
+		clear(index.files)
+		clear(index.staleFiles)
+
+		fsutil.Walk(fls, "/", func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() && strings.HasSuffix(path, inoxconsts.INOXLANG_FILE_EXTENSION) {
+				index.indexFile(path, fls)
+			}
+			return nil
+		})
+
+		index.upToDate = true
+		return
+	}
+
+	for path := range index.staleFiles {
+		index.indexFile(path, fls)
+	}
+	clear(index.staleFiles)
+}
+
+func (index *workspaceSymbolIndex) indexFile(fpath string, fls afs.Filesystem) {
+	chunk, _ := core.ParseFileChunk(fpath, fls)
+	if chunk == nil { //the file does not exist or cannot be parsed
+		delete(index.files, fpath)
+		return
+	}
+
+	var symbols []indexedSymbol
+
+	var addItems func(items []*outlineItem, containerName string)
+	addItems = func(items []*outlineItem, containerName string) {
+		for _, item := range items {
+			if !item.workspaceLevel {
+				continue
+			}
+			symbols = append(symbols, indexedSymbol{
+				name:          item.name,
+				kind:          item.kind,
+				containerName: containerName,
+				position:      item.namePosition,
+			})
+			addItems(item.children, item.name)
+		}
+	}
+	addItems(getOutline(chunk), "")
+
+	index.files[fpath] = symbols
+}
+
+// fuzzyMatchSymbolName checks that the characters of query appear in name in the same order (case insensitive),
+// the returned score is lower for better matches: consecutive characters and matches at the start of words are
+// favored.
+func fuzzyMatchSymbolName(query, name string) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	queryRunes := []rune(strings.ToLower(query))
+	nameRunes := []rune(name)
+
+	queryIndex := 0
+	lastMatchIndex := -1
+
+	for i, r := range nameRunes {
+		if queryIndex >= len(queryRunes) {
+			break
+		}
+		if unicode.ToLower(r) != queryRunes[queryIndex] {
+			continue
+		}
+
+		isWordStart := i == 0 || !unicode.IsLetter(nameRunes[i-1]) || (unicode.IsUpper(r) && unicode.IsLower(nameRunes[i-1]))
+
+		switch {
+		case lastMatchIndex >= 0 && i == lastMatchIndex+1:
+			//consecutive characters
+		case isWordStart:
+			score += 1
+		case lastMatchIndex < 0:
+			score += 2 + i
+		default:
+			score += 2 + i - lastMatchIndex
+		}
+
+		lastMatchIndex = i
+		queryIndex++
+	}
+
+	if queryIndex < len(queryRunes) {
+		return 0, false
+	}
+
+	//favor short names
+	score += len(nameRunes) - len(queryRunes)
+	return score, true
+}
@@ -0,0 +1,109 @@
+package projectserver
+
+import (
+	"testing"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/globals/fs_ns"
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDocumentSymbols(t *testing.T) {
+	chunk := utils.Must(parse.ParseChunkSource(parse.InMemorySource{
+		NameString: "/main.ix",
+		CodeString: "manifest {\n  permissions: {}\n}\n" +
+			"pattern user = {name: str}\n" +
+			"pnamespace ns. = {}\n" +
+			"fn f(a int) int {\n  return html<div></div>\n}\n" +
+			"testsuite \"suite\" {\n  testcase {}\n}",
+	}))
+
+	symbols := getDocumentSymbols(chunk)
+	if !assert.Len(t, symbols, 5) {
+		return
+	}
+
+	manifest := symbols[0]
+	assert.Equal(t, "manifest", manifest.Name)
+	if assert.NotNil(t, manifest.Children) && assert.Len(t, *manifest.Children, 1) {
+		assert.Equal(t, "permissions", (*manifest.Children)[0].Name)
+	}
+
+	assert.Equal(t, "%user", symbols[1].Name)
+	assert.Equal(t, defines.SymbolKindInterface, symbols[1].Kind)
+
+	assert.Equal(t, "%ns.", symbols[2].Name)
+	assert.Equal(t, defines.SymbolKindNamespace, symbols[2].Kind)
+
+	function := symbols[3]
+	assert.Equal(t, "f", function.Name)
+	assert.Equal(t, defines.SymbolKindFunction, function.Kind)
+	if assert.NotNil(t, function.Detail) {
+		assert.Equal(t, "(a int) int", *function.Detail)
+	}
+	assert.Equal(t, defines.Range{
+		Start: defines.Position{Line: 5, Character: 3},
+		End:   defines.Position{Line: 5, Character: 4},
+	}, function.SelectionRange)
+
+	if assert.NotNil(t, function.Children) && assert.Len(t, *function.Children, 1) {
+		assert.Equal(t, "html<div>", (*function.Children)[0].Name)
+	}
+
+	suite := symbols[4]
+	assert.Equal(t, `testsuite "suite"`, suite.Name)
+	if assert.NotNil(t, suite.Children) && assert.Len(t, *suite.Children, 1) {
+		assert.Equal(t, "testcase", (*suite.Children)[0].Name)
+	}
+}
+
+func TestWorkspaceSymbolIndex(t *testing.T) {
+	fls := fs_ns.NewMemFilesystem(10_000)
+	fsutil.WriteFile(fls, "/main.ix", []byte("manifest {}\nfn getUser(){}\nfn getUsers(){}"), 0600)
+	fsutil.WriteFile(fls, "/lib/patterns.ix", []byte("includable-chunk\npattern user = {}"), 0600)
+
+	index := newWorkspaceSymbolIndex()
+
+	getNames := func(symbols []indexedSymbol) (names []string) {
+		for _, symbol := range symbols {
+			names = append(names, symbol.name)
+		}
+		return
+	}
+
+	assert.Equal(t, []string{"%user", "getUser", "getUsers"}, getNames(index.search("user", fls)))
+	assert.Equal(t, []string{"getUser", "getUsers"}, getNames(index.search("gus", fls)))
+
+	//the index is not updated until the file is invalidated.
+	fsutil.WriteFile(fls, "/main.ix", []byte("manifest {}\nfn getAccount(){}"), 0600)
+	assert.Equal(t, []string{"%user", "getUser", "getUsers"}, getNames(index.search("user", fls)))
+
+	index.invalidate("/main.ix")
+	assert.Equal(t, []string{"%user"}, getNames(index.search("user", fls)))
+
+	//removal of a directory
+	fls.Remove("/lib/patterns.ix")
+	fls.Remove("/lib")
+	index.invalidate("/lib")
+	assert.Empty(t, index.search("user", fls))
+	assert.Equal(t, []string{"getAccount"}, getNames(index.search("", fls)))
+}
+
+func TestFuzzyMatchSymbolName(t *testing.T) {
+	_, ok := fuzzyMatchSymbolName("usr", "user")
+	assert.True(t, ok)
+
+	_, ok = fuzzyMatchSymbolName("rsu", "user")
+	assert.False(t, ok)
+
+	exactScore, _ := fuzzyMatchSymbolName("user", "user")
+	prefixScore, _ := fuzzyMatchSymbolName("user", "users")
+	wordStartScore, _ := fuzzyMatchSymbolName("gu", "getUser")
+	scatteredScore, _ := fuzzyMatchSymbolName("gu", "digitus")
+
+	assert.Less(t, exactScore, prefixScore)
+	assert.Less(t, wordStartScore, scatteredScore)
+}
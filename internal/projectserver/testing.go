@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	fsutil "github.com/go-git/go-billy/v5/util"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
 	"github.com/inoxlang/inox/internal/utils"
@@ -16,24 +17,69 @@ import (
 type TestRun struct {
 	id    TestRunId
 	state *core.GlobalState
+	done  chan struct{} //closed when the run is finished
 }
 
 type TestRunId string
 
+type benchmarkingConfig struct {
+	enabled      bool
+	baselinePath string //can be empty
+}
+
 // testModuleAsync creates a goroutine that executes the module at $path in testing mode, testModuleAsync immediately returns
 // without waiting for the tests to finish. The goroutine notifies the LSP client with TEST_RUN_FINISHED_METHOD when it is done.
 // testModuleAsync should NOT be called while the session data is locked because it acquires the lock in order to
 // store the testRunId in additionalSessionData.testRuns.
-func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Session) (TestFileResponse, error) {
+func testModuleAsync(
+	path string,
+	filters core.TestFilters,
+	benchmarking benchmarkingConfig,
+	updateGoldenFiles bool,
+	session *jsonrpc.Session,
+) (TestFileResponse, error) {
+	testRun, err := startTestRun(path, filters, benchmarking, updateGoldenFiles, session)
+	if err != nil {
+		return TestFileResponse{}, err
+	}
+
+	return TestFileResponse{
+		TestRunId: testRun.id,
+	}, nil
+}
+
+// startTestRun is the implementation of testModuleAsync, the .done channel of the returned run is closed
+// after TEST_RUN_FINISHED_METHOD is sent.
+func startTestRun(
+	path string,
+	filters core.TestFilters,
+	benchmarking benchmarkingConfig,
+	updateGoldenFiles bool,
+	session *jsonrpc.Session,
+) (*TestRun, error) {
 
 	fls, ok := getLspFilesystem(session)
 	if !ok {
-		return TestFileResponse{}, errors.New(string(FsNoFilesystem))
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+
+	var benchmarkBaseline *core.BenchmarkBaseline
+	if benchmarking.enabled && benchmarking.baselinePath != "" {
+		content, err := fsutil.ReadFile(fls, benchmarking.baselinePath)
+		if err == nil {
+			benchmarkBaseline, err = core.ParseBenchmarkBaseline(content)
+		}
+		if err != nil {
+			return nil, jsonrpc.ResponseError{
+				Code:    jsonrpc.InvalidParams.Code,
+				Message: fmt.Sprintf("failed to read the benchmark baseline %q: %s", benchmarking.baselinePath, err.Error()),
+			}
+		}
 	}
 
 	project, ok := getProject(session)
 	if !ok {
-		return TestFileResponse{}, jsonrpc.ResponseError{
+		return nil, jsonrpc.ResponseError{
 			Code:    jsonrpc.InternalError.Code,
 			Message: "testing using the LSP only works in project mode for now",
 		}
@@ -58,6 +104,9 @@ func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Ses
 		FullAccessToDatabases: true,
 		EnableTesting:         true,
 		TestFilters:           filters,
+		EnableBenchmarking:    benchmarking.enabled,
+		BenchmarkBaseline:     benchmarkBaseline,
+		UpdateGoldenFiles:     updateGoldenFiles,
 
 		Project: project,
 
@@ -70,7 +119,7 @@ func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Ses
 	})
 
 	if err != nil {
-		return TestFileResponse{}, jsonrpc.ResponseError{
+		return nil, jsonrpc.ResponseError{
 			Code:    jsonrpc.InternalError.Code,
 			Message: fmt.Sprintf("failed to prepare %q: %s", path, err.Error()),
 		}
@@ -79,6 +128,7 @@ func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Ses
 	testRun := &TestRun{
 		id:    makeTestRunId(),
 		state: state,
+		done:  make(chan struct{}),
 	}
 	data := getLockedSessionData(session)
 	data.testRuns[testRun.id] = testRun
@@ -87,8 +137,12 @@ func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Ses
 	go func() {
 		defer utils.Recover()
 
+		var benchmarkResults []*core.BenchmarkResult
+
 		defer func() {
-			sendTestRunFinished(session)
+			recordTestResultsAndRefreshCodeLenses(state, session)
+			sendTestRunFinished(benchmarkResults, session)
+			close(testRun.done)
 		}()
 
 		twState := core.NewTreeWalkStateWithGlobal(state)
@@ -111,14 +165,13 @@ func testModuleAsync(path string, filters core.TestFilters, session *jsonrpc.Ses
 		for _, suiteResult := range state.TestingState.SuiteResults {
 			msg := utils.AddCarriageReturnAfterNewlines(suiteResult.MostAdaptedMessage(colorized, backgroundIsDark))
 			fmt.Fprint(buf, msg)
+			benchmarkResults = append(benchmarkResults, suiteResult.BenchmarkResults()...)
 		}
 
 		sendTestOutput(buf.Bytes(), session)
 	}()
 
-	return TestFileResponse{
-		TestRunId: testRun.id,
-	}, nil
+	return testRun, nil
 }
 
 func sendTestOutput(bytesOrStringBytes []byte, session *jsonrpc.Session) {
@@ -137,8 +190,10 @@ func sendTestOutput(bytesOrStringBytes []byte, session *jsonrpc.Session) {
 	})
 }
 
-func sendTestRunFinished(session *jsonrpc.Session) {
-	runFinished := RunFinishedParams{}
+func sendTestRunFinished(benchmarkResults []*core.BenchmarkResult, session *jsonrpc.Session) {
+	runFinished := RunFinishedParams{
+		BenchmarkResults: benchmarkResults,
+	}
 
 	session.Notify(jsonrpc.NotificationMessage{
 		Method: TEST_RUN_FINISHED_METHOD,
@@ -12,9 +12,11 @@ import (
 const (
 	//request methods
 
-	ENABLE_TEST_DISCOVERY_METHOD = "testing/enableContinousDiscovery"
-	TEST_FILE_METHOD             = "testing/testFileAsync"
-	STOP_TEST_RUN_METHOD         = "testing/stopRun"
+	ENABLE_TEST_DISCOVERY_METHOD   = "testing/enableContinousDiscovery"
+	TEST_FILE_METHOD               = "testing/testFileAsync"
+	STOP_TEST_RUN_METHOD           = "testing/stopRun"
+	ENABLE_TEST_WATCH_MODE_METHOD  = "testing/enableWatchMode"
+	DISABLE_TEST_WATCH_MODE_METHOD = "testing/disableWatchMode"
 
 	//notification methods
 
@@ -25,16 +27,44 @@ const (
 type EnableContinuousTestDiscoveryParams struct {
 }
 
+type EnableTestWatchModeParams struct {
+	//if empty all tests of the affected spec files are executed.
+	PositiveFilters []TestFilter `json:"positiveFilters,omitempty"`
+}
+
+func (p EnableTestWatchModeParams) Filters() core.TestFilters {
+	if len(p.PositiveFilters) == 0 {
+		return core.TestFilters{
+			PositiveTestFilters: []core.TestFilter{{NameRegex: ".*"}},
+		}
+	}
+
+	return TestFileParams{PositiveFilters: p.PositiveFilters}.Filters()
+}
+
+type DisableTestWatchModeParams struct {
+}
+
 type TestOutputEvent struct {
 	DataBase64 string `json:"data"`
 }
 
 type RunFinishedParams struct {
+	BenchmarkResults []*core.BenchmarkResult `json:"benchmarkResults,omitempty"`
 }
 
 type TestFileParams struct {
 	Path            string       `json:"path"`
 	PositiveFilters []TestFilter `json:"positiveFilters"`
+
+	//if true the benchmarks are executed.
+	EnableBenchmarks bool `json:"enableBenchmarks,omitempty"`
+
+	//optional path of a JSON file containing the results of a previous benchmark run.
+	BenchmarkBaseline string `json:"benchmarkBaseline,omitempty"`
+
+	//if true the golden file assertions (re)write the golden files instead of comparing.
+	UpdateGoldenFiles bool `json:"updateGoldenFiles,omitempty"`
 }
 
 func (p TestFileParams) Filters() core.TestFilters {
@@ -49,6 +79,13 @@ func (p TestFileParams) Filters() core.TestFilters {
 	}
 }
 
+func (p TestFileParams) benchmarkingConfig() benchmarkingConfig {
+	return benchmarkingConfig{
+		enabled:      p.EnableBenchmarks,
+		baselinePath: p.BenchmarkBaseline,
+	}
+}
+
 type TestFileResponse struct {
 	TestRunId TestRunId `json:"testRunId"`
 }
@@ -101,7 +138,52 @@ func registerTestingMethodHandlers(server *lsp.Server, opts LSPServerConfigurati
 			session := jsonrpc.GetSession(ctx)
 			params := req.(*TestFileParams)
 
-			return testModuleAsync(params.Path, params.Filters(), session)
+			return testModuleAsync(params.Path, params.Filters(), params.benchmarkingConfig(), params.UpdateGoldenFiles, session)
+		},
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: ENABLE_TEST_WATCH_MODE_METHOD,
+		NewRequest: func() interface{} {
+			return &EnableTestWatchModeParams{}
+		},
+		RateLimits: []int{2, 10, 30},
+		Handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			session := jsonrpc.GetSession(ctx)
+			params := req.(*EnableTestWatchModeParams)
+
+			data := getLockedSessionData(session)
+			prevWatchMode := data.testWatchMode
+			data.testWatchMode = newTestWatchMode(params.Filters())
+			data.lock.Unlock()
+
+			if prevWatchMode != nil {
+				prevWatchMode.stop()
+			}
+
+			return nil, nil
+		},
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: DISABLE_TEST_WATCH_MODE_METHOD,
+		NewRequest: func() interface{} {
+			return &DisableTestWatchModeParams{}
+		},
+		RateLimits: []int{2, 10, 30},
+		Handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			session := jsonrpc.GetSession(ctx)
+
+			data := getLockedSessionData(session)
+			watchMode := data.testWatchMode
+			data.testWatchMode = nil
+			data.lock.Unlock()
+
+			if watchMode != nil {
+				watchMode.stop()
+			}
+
+			return nil, nil
 		},
 	})
 
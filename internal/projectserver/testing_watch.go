@@ -0,0 +1,145 @@
+package projectserver
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/afs"
+	"github.com/inoxlang/inox/internal/core"
+	"github.com/inoxlang/inox/internal/inoxconsts"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+// A testWatchMode re-runs the spec files affected by a file save. Only a single watch run
+// is executed at a time: a save cancels the current watch run.
+type testWatchMode struct {
+	filters core.TestFilters
+
+	lock       sync.Mutex
+	generation int      //incremented on each save
+	currentRun *TestRun //nil if no spec file is being tested
+}
+
+func newTestWatchMode(filters core.TestFilters) *testWatchMode {
+	return &testWatchMode{
+		filters: filters,
+	}
+}
+
+// acknowledgeSave creates a goroutine that runs the spec files affected by the modification of the file at fpath.
+func (w *testWatchMode) acknowledgeSave(fpath string, session *jsonrpc.Session) {
+	sessionData := getLockedSessionData(session)
+	fls := sessionData.filesystem
+	graph := sessionData.getOrCreatePreparedFileCache().dependencyGraph
+	sessionData.lock.Unlock()
+
+	if fls == nil {
+		return
+	}
+
+	//the dependencies of the saved file may have changed.
+	graph.invalidate(fpath)
+
+	w.lock.Lock()
+	w.generation++
+	generation := w.generation
+	if w.currentRun != nil {
+		w.currentRun.state.Ctx.CancelGracefully()
+	}
+	w.lock.Unlock()
+
+	go func() {
+		defer utils.Recover()
+
+		specFiles, err := getAffectedSpecFiles(fpath, fls, graph)
+		if err != nil {
+			logs.Println("failed to determine the spec files affected by the modification of", fpath+":", err)
+			return
+		}
+
+		if len(specFiles) == 0 {
+			return
+		}
+
+		sendTestOutput([]byte(fmt.Sprintf("[watch] %s changed, %d affected spec file(s)\n", fpath, len(specFiles))), session)
+
+		for _, specFile := range specFiles {
+			if !w.run(specFile, generation, session) {
+				return
+			}
+		}
+	}()
+}
+
+// run tests a spec file and waits for the run to finish, false is returned if the watch run has been superseded.
+func (w *testWatchMode) run(specFile string, generation int, session *jsonrpc.Session) bool {
+	w.lock.Lock()
+	if w.generation != generation {
+		w.lock.Unlock()
+		return false
+	}
+
+	testRun, err := startTestRun(specFile, w.filters, benchmarkingConfig{}, false, session)
+	if err != nil {
+		w.lock.Unlock()
+		sendTestOutput([]byte(fmt.Sprintf("[watch] failed to run %s: %s\n", specFile, err.Error())), session)
+		return true
+	}
+	w.currentRun = testRun
+	w.lock.Unlock()
+
+	<-testRun.done
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.currentRun == testRun {
+		w.currentRun = nil
+	}
+	return w.generation == generation
+}
+
+// stop cancels the current watch run.
+func (w *testWatchMode) stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.generation++
+	if w.currentRun != nil {
+		w.currentRun.state.Ctx.CancelGracefully()
+		w.currentRun = nil
+	}
+}
+
+// getAffectedSpecFiles returns the sorted paths of the spec files that are equal to or (transitively) depend on
+// the file at fpath.
+func getAffectedSpecFiles(fpath string, fls afs.Filesystem, graph *moduleDependencyGraph) ([]string, error) {
+	var affected []string
+
+	err := fsutil.Walk(fls, "/", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(path, inoxconsts.INOXLANG_SPEC_FILE_SUFFIX) {
+			return nil
+		}
+
+		if path == fpath || graph.dependsOn(path, fpath, fls) {
+			affected = append(affected, path)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(affected)
+	return affected, nil
+}
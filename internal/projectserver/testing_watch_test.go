@@ -0,0 +1,77 @@
+package projectserver
+
+import (
+	"testing"
+
+	fsutil "github.com/go-git/go-billy/v5/util"
+	"github.com/inoxlang/inox/internal/globals/fs_ns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAffectedSpecFiles(t *testing.T) {
+	fls := fs_ns.NewMemFilesystem(1_000_000)
+
+	fsutil.WriteFile(fls, "/main.ix", []byte("manifest {}\nimport ./lib.ix"), 0600)
+	fsutil.WriteFile(fls, "/lib.ix", []byte("includable-chunk\nimport /utils/chunk.ix"), 0600)
+	fsutil.WriteFile(fls, "/utils/chunk.ix", []byte("includable-chunk"), 0600)
+	fsutil.WriteFile(fls, "/other.ix", []byte("includable-chunk"), 0600)
+
+	fsutil.WriteFile(fls, "/main.spec.ix", []byte("manifest {}\nimport ./main.ix"), 0600)
+	fsutil.WriteFile(fls, "/utils/chunk.spec.ix", []byte("manifest {}\nimport ./chunk.ix"), 0600)
+	fsutil.WriteFile(fls, "/other.spec.ix", []byte("manifest {}\nimport ./other.ix"), 0600)
+
+	graph := newModuleDependencyGraph()
+
+	specFiles, err := getAffectedSpecFiles("/utils/chunk.ix", fls, graph)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"/main.spec.ix", "/utils/chunk.spec.ix"}, specFiles)
+	}
+
+	specFiles, err = getAffectedSpecFiles("/main.spec.ix", fls, graph)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"/main.spec.ix"}, specFiles)
+	}
+
+	//the dependencies of /lib.ix are cached until they are invalidated.
+	fsutil.WriteFile(fls, "/lib.ix", []byte("includable-chunk\nimport ./other.ix"), 0600)
+
+	specFiles, err = getAffectedSpecFiles("/other.ix", fls, graph)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"/other.spec.ix"}, specFiles)
+	}
+
+	graph.invalidate("/lib.ix")
+
+	specFiles, err = getAffectedSpecFiles("/other.ix", fls, graph)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"/main.spec.ix", "/other.spec.ix"}, specFiles)
+	}
+}
+
+func TestAcknowledgeDocumentSave(t *testing.T) {
+	base := fs_ns.NewMemFilesystem(1_000_000)
+	fls := NewFilesystem(base, fs_ns.NewMemFilesystem(1_000_000))
+
+	fsutil.WriteFile(base, "/main.ix", []byte("manifest {}"), 0600)
+	fsutil.WriteFile(base, "/lib.ix", []byte("includable-chunk"), 0600)
+	fsutil.WriteFile(base, "/main.spec.ix", []byte("manifest {}\nimport ./main.ix"), 0600)
+
+	//state of the document after the last textDocument/didChange notification.
+	fsutil.WriteFile(fls.unsavedDocumentsFS(), "/main.ix", []byte("manifest {}"), 0600)
+
+	graph := newModuleDependencyGraph()
+
+	//the saved text differs from the text of the last textDocument/didChange notification.
+	savedText := "manifest {}\nimport ./lib.ix"
+	var specFiles []string
+	var err error
+
+	acknowledgeDocumentSave(fls, "/main.ix", &savedText, func() {
+		graph.invalidate("/main.ix")
+		specFiles, err = getAffectedSpecFiles("/lib.ix", fls, graph)
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"/main.spec.ix"}, specFiles)
+	}
+}
@@ -0,0 +1,391 @@
+package projectserver
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/inoxlang/inox/internal/parse"
+	"github.com/inoxlang/inox/internal/projectserver/jsonrpc"
+	"github.com/inoxlang/inox/internal/projectserver/logs"
+	"github.com/inoxlang/inox/internal/projectserver/lsp"
+	"github.com/inoxlang/inox/internal/projectserver/lsp/defines"
+	"github.com/inoxlang/inox/internal/utils"
+)
+
+const (
+	PREPARE_TYPE_HIERARCHY_METHOD = "textDocument/prepareTypeHierarchy"
+	TYPE_HIERARCHY_SUPERTYPES     = "typeHierarchy/supertypes"
+	TYPE_HIERARCHY_SUBTYPES       = "typeHierarchy/subtypes"
+
+	PATTERN_EXTENSION_TYPE_DETAIL = "extension"
+)
+
+type patternHierarchyItemKind string
+
+const (
+	namedPatternItem     patternHierarchyItemKind = "pattern"
+	patternExtensionItem patternHierarchyItemKind = "extension"
+)
+
+// A patternHierarchyItem is an item of a type hierarchy: an object or record pattern defined by a pattern definition,
+// or an extend statement. The supertypes of a pattern are the patterns spread in its definition
+// (e.g. pattern user = {...%named, age: int}), the supertype of an extension is the extended pattern.
+// Named patterns are identified by the position of their name, extensions by the position of the extend statement.
+// The exported fields are preserved between a type hierarchy preparation and supertypes/subtypes requests.
+type patternHierarchyItem struct {
+	Kind       patternHierarchyItemKind  `json:"kind"`
+	Name       string                    `json:"name"`
+	Definition parse.SourcePositionRange `json:"definition"`
+
+	declaration parse.SourcePositionRange
+}
+
+func (i patternHierarchyItem) is(other patternHierarchyItem) bool {
+	return i.Kind == other.Kind && i.Name == other.Name && i.Definition == other.Definition
+}
+
+func (i patternHierarchyItem) toTypeHierarchyItem(scheme string) defines.TypeHierarchyItem {
+	item := defines.TypeHierarchyItem{
+		Name:           i.Name,
+		Kind:           defines.SymbolKindInterface,
+		Uri:            defines.DocumentUri(scheme + "://" + i.declaration.SourceName),
+		Range:          rangeToLspRange(i.declaration),
+		SelectionRange: rangeToLspRange(i.Definition),
+		Data:           i,
+	}
+
+	if i.Kind == patternExtensionItem {
+		detail := PATTERN_EXTENSION_TYPE_DETAIL
+		item.Kind = defines.SymbolKindClass
+		item.Detail = &detail
+	}
+	return item
+}
+
+func registerTypeHierarchyMethodHandlers(server *lsp.Server) {
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: PREPARE_TYPE_HIERARCHY_METHOD,
+		NewRequest: func() interface{} {
+			return &defines.TypeHierarchyPrepareParams{}
+		},
+		Handler: handlePrepareTypeHierarchy,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: TYPE_HIERARCHY_SUPERTYPES,
+		NewRequest: func() interface{} {
+			return &defines.TypeHierarchySupertypesParams{}
+		},
+		Handler: handleTypeHierarchySupertypes,
+	})
+
+	server.OnCustom(jsonrpc.MethodInfo{
+		Name: TYPE_HIERARCHY_SUBTYPES,
+		NewRequest: func() interface{} {
+			return &defines.TypeHierarchySubtypesParams{}
+		},
+		Handler: handleTypeHierarchySubtypes,
+	})
+}
+
+func handlePrepareTypeHierarchy(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.TypeHierarchyPrepareParams)
+	session := jsonrpc.GetSession(ctx)
+
+	sessionData := getLockedSessionData(session)
+	projectMode := sessionData.projectMode
+	sessionData.lock.Unlock()
+
+	fpath, err := getFilePath(params.TextDocument.Uri, projectMode)
+	if err != nil {
+		return nil, err
+	}
+	line, column := getLineColumn(params.Position)
+
+	handlingCtx, ok := newHierarchyHandlingContext(session)
+	if !ok {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+	defer handlingCtx.CancelGracefully()
+
+	var items []defines.TypeHierarchyItem
+
+	ok = withPreparedFile(fpath, handlingCtx, session, func(prepResult preparationResult) {
+		item, ok := getPatternHierarchyItemAt(line, column, prepResult)
+		if ok {
+			items = append(items, item.toTypeHierarchyItem(sessionData.Scheme()))
+		}
+	})
+
+	if !ok || len(items) == 0 {
+		return nil, nil
+	}
+	return items, nil
+}
+
+func handleTypeHierarchySupertypes(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.TypeHierarchySupertypesParams)
+	session := jsonrpc.GetSession(ctx)
+
+	var item patternHierarchyItem
+	if err := decodeHierarchyItemData(params.Item.Data, &item); err != nil {
+		return nil, err
+	}
+
+	handlingCtx, ok := newHierarchyHandlingContext(session)
+	if !ok {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+	defer handlingCtx.CancelGracefully()
+
+	scheme := getSessionData(session).Scheme()
+	supertypes := []defines.TypeHierarchyItem{}
+
+	withPreparedFile(item.Definition.SourceName, handlingCtx, session, func(prepResult preparationResult) {
+		for _, supertype := range findSupertypesInModule(item, prepResult) {
+			supertypes = append(supertypes, supertype.toTypeHierarchyItem(scheme))
+		}
+	})
+
+	return supertypes, nil
+}
+
+func handleTypeHierarchySubtypes(ctx context.Context, req interface{}) (interface{}, error) {
+	params := req.(*defines.TypeHierarchySubtypesParams)
+	session := jsonrpc.GetSession(ctx)
+
+	var item patternHierarchyItem
+	if err := decodeHierarchyItemData(params.Item.Data, &item); err != nil {
+		return nil, err
+	}
+
+	if item.Kind == patternExtensionItem {
+		return []defines.TypeHierarchyItem{}, nil
+	}
+
+	handlingCtx, ok := newHierarchyHandlingContext(session)
+	if !ok {
+		return nil, errors.New(string(FsNoFilesystem))
+	}
+	defer handlingCtx.CancelGracefully()
+
+	var subtypes []patternHierarchyItem
+
+	err := forEachPreparedProjectFile(item.Name, handlingCtx, session, func(prepResult preparationResult) {
+		for _, subtype := range findSubtypesInModule(item, prepResult) {
+			if !slices.ContainsFunc(subtypes, subtype.is) {
+				subtypes = append(subtypes, subtype)
+			}
+		}
+	})
+
+	if err != nil {
+		logs.Println("failed to get subtypes", err)
+		return nil, nil
+	}
+
+	slices.SortFunc(subtypes, func(a, b patternHierarchyItem) int {
+		return compareSourcePositions(a.Definition, b.Definition)
+	})
+
+	scheme := getSessionData(session).Scheme()
+	return utils.MapSlice(subtypes, func(subtype patternHierarchyItem) defines.TypeHierarchyItem {
+		return subtype.toTypeHierarchyItem(scheme)
+	}), nil
+}
+
+// getPatternHierarchyItemAt returns the type hierarchy item at a specific position in a prepared chunk: a reference
+// to an object or record pattern, or an extend statement.
+func getPatternHierarchyItemAt(line, column int32, prepResult preparationResult) (patternHierarchyItem, bool) {
+	chunk := prepResult.chunk
+
+	span := chunk.GetLineColumnSingeCharSpan(line, column)
+	node, ancestors, ok := chunk.GetNodeAndChainAtSpan(span)
+	if !ok || node == nil {
+		return patternHierarchyItem{}, false
+	}
+
+	switch n := node.(type) {
+	case *parse.PatternIdentifierLiteral:
+		return resolvePatternHierarchyItem(n, ancestors, prepResult)
+	case *parse.ExtendStatement:
+		return getPatternExtensionItem(n, chunk)
+	}
+	return patternHierarchyItem{}, false
+}
+
+// resolvePatternHierarchyItem returns the item of the object or record pattern referenced by $ident.
+func resolvePatternHierarchyItem(ident *parse.PatternIdentifierLiteral, ancestors []parse.Node, prepResult preparationResult) (patternHierarchyItem, bool) {
+	data := prepResult.state.SymbolicData.Data
+
+	definition, ok := getSymbolDefinition(patternSymbol, ident, ancestors, data)
+	if !ok {
+		return patternHierarchyItem{}, false
+	}
+
+	for _, chunk := range getModuleChunks(prepResult) {
+		if chunk.Name() != definition.SourceName {
+			continue
+		}
+
+		var item patternHierarchyItem
+		found := false
+
+		parse.Walk(chunk.Node, func(node, _, _ parse.Node, _ []parse.Node, _ bool) (parse.TraversalAction, error) {
+			def, ok := node.(*parse.PatternDefinition)
+			if !ok {
+				return parse.ContinueTraversal, nil
+			}
+			if chunk.GetSourcePosition(def.Left.Base().Span) == definition {
+				item, found = getNamedPatternItem(def, chunk)
+				return parse.StopTraversal, nil
+			}
+			return parse.Prune, nil
+		}, nil)
+
+		if found {
+			return item, true
+		}
+	}
+
+	return patternHierarchyItem{}, false
+}
+
+// getNamedPatternItem returns the item of a pattern definition, ok is false if the defined pattern is not an
+// object or record pattern.
+func getNamedPatternItem(def *parse.PatternDefinition, chunk *parse.ParsedChunkSource) (patternHierarchyItem, bool) {
+	name, ok := def.PatternName()
+	if !ok || !isObjectOrRecordPattern(def.Right) {
+		return patternHierarchyItem{}, false
+	}
+
+	return patternHierarchyItem{
+		Kind:        namedPatternItem,
+		Name:        name,
+		Definition:  chunk.GetSourcePosition(def.Left.Base().Span),
+		declaration: chunk.GetSourcePosition(def.Span),
+	}, true
+}
+
+func getPatternExtensionItem(stmt *parse.ExtendStatement, chunk *parse.ParsedChunkSource) (patternHierarchyItem, bool) {
+	ident, ok := stmt.ExtendedPattern.(*parse.PatternIdentifierLiteral)
+	if !ok {
+		return patternHierarchyItem{}, false
+	}
+
+	pos := chunk.GetSourcePosition(stmt.Span)
+	return patternHierarchyItem{
+		Kind:        patternExtensionItem,
+		Name:        "extend " + ident.Name,
+		Definition:  pos,
+		declaration: pos,
+	}, true
+}
+
+func isObjectOrRecordPattern(node parse.Node) bool {
+	switch node.(type) {
+	case *parse.ObjectPatternLiteral, *parse.RecordPatternLiteral:
+		return true
+	}
+	return false
+}
+
+func getSpreadPatternElements(def *parse.PatternDefinition) []*parse.PatternPropertySpreadElement {
+	switch p := def.Right.(type) {
+	case *parse.ObjectPatternLiteral:
+		return p.SpreadElements
+	case *parse.RecordPatternLiteral:
+		return p.SpreadElements
+	}
+	return nil
+}
+
+// findSupertypesInModule returns the supertypes of $item, the module should contain the definition of $item.
+func findSupertypesInModule(item patternHierarchyItem, prepResult preparationResult) (supertypes []patternHierarchyItem) {
+	for _, chunk := range getModuleChunks(prepResult) {
+		if chunk.Name() != item.Definition.SourceName {
+			continue
+		}
+
+		parse.Walk(chunk.Node, func(node, _, _ parse.Node, ancestors []parse.Node, _ bool) (parse.TraversalAction, error) {
+			switch n := node.(type) {
+			case *parse.PatternDefinition:
+				if item.Kind != namedPatternItem || chunk.GetSourcePosition(n.Left.Base().Span) != item.Definition {
+					return parse.Prune, nil
+				}
+
+				for _, spreadElem := range getSpreadPatternElements(n) {
+					ident, ok := spreadElem.Expr.(*parse.PatternIdentifierLiteral)
+					if !ok {
+						continue
+					}
+					identAncestors := append(slices.Clip(ancestors), n, n.Right, spreadElem)
+					if supertype, ok := resolvePatternHierarchyItem(ident, identAncestors, prepResult); ok {
+						supertypes = append(supertypes, supertype)
+					}
+				}
+				return parse.StopTraversal, nil
+			case *parse.ExtendStatement:
+				if item.Kind != patternExtensionItem || chunk.GetSourcePosition(n.Span) != item.Definition {
+					return parse.Prune, nil
+				}
+
+				if ident, ok := n.ExtendedPattern.(*parse.PatternIdentifierLiteral); ok {
+					identAncestors := append(slices.Clip(ancestors), n)
+					if supertype, ok := resolvePatternHierarchyItem(ident, identAncestors, prepResult); ok {
+						supertypes = append(supertypes, supertype)
+					}
+				}
+				return parse.StopTraversal, nil
+			}
+			return parse.ContinueTraversal, nil
+		}, nil)
+	}
+	return
+}
+
+// findSubtypesInModule returns the subtypes of $item in a prepared module or includable file: the patterns spreading
+// $item in their definition and the extensions of $item. The chunks included by the module are also searched.
+func findSubtypesInModule(item patternHierarchyItem, prepResult preparationResult) (subtypes []patternHierarchyItem) {
+	if item.Kind != namedPatternItem {
+		return nil
+	}
+
+	isItem := func(ident parse.Node, ancestors []parse.Node) bool {
+		patternIdent, ok := ident.(*parse.PatternIdentifierLiteral)
+		if !ok || patternIdent.Name != item.Name {
+			return false
+		}
+		referenced, ok := resolvePatternHierarchyItem(patternIdent, ancestors, prepResult)
+		return ok && referenced.is(item)
+	}
+
+	for _, chunk := range getModuleChunks(prepResult) {
+		parse.Walk(chunk.Node, func(node, _, _ parse.Node, ancestors []parse.Node, _ bool) (parse.TraversalAction, error) {
+			switch n := node.(type) {
+			case *parse.PatternDefinition:
+				for _, spreadElem := range getSpreadPatternElements(n) {
+					if !isItem(spreadElem.Expr, append(slices.Clip(ancestors), n, n.Right, spreadElem)) {
+						continue
+					}
+					if subtype, ok := getNamedPatternItem(n, chunk); ok {
+						subtypes = append(subtypes, subtype)
+					}
+					break
+				}
+				return parse.Prune, nil
+			case *parse.ExtendStatement:
+				if !isItem(n.ExtendedPattern, append(slices.Clip(ancestors), n)) {
+					return parse.Prune, nil
+				}
+				if subtype, ok := getPatternExtensionItem(n, chunk); ok {
+					subtypes = append(subtypes, subtype)
+				}
+				return parse.Prune, nil
+			}
+			return parse.ContinueTraversal, nil
+		}, nil)
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package projectserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeHierarchy(t *testing.T) {
+	getItemStrings := func(items []patternHierarchyItem) (strings []string) {
+		for _, item := range items {
+			strings = append(strings, item.Name+" @ "+item.Definition.String())
+		}
+		return
+	}
+
+	prepResult, ok := prepareTestModule(t, map[string]string{
+		"/main.ix": "manifest {}\nimport ./lib.ix\npattern user = {...%named, age: int}\nextend named {}\nv = %user",
+		"/lib.ix":  "includable-chunk\npattern named = {name: str}",
+	})
+	if !ok {
+		return
+	}
+
+	user, ok := getPatternHierarchyItemAt(5, 6, prepResult)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "user @ /main.ix:3:9:", getItemStrings([]patternHierarchyItem{user})[0])
+
+	supertypes := findSupertypesInModule(user, prepResult)
+	assert.Equal(t, []string{"named @ /lib.ix:2:9:"}, getItemStrings(supertypes))
+
+	if len(supertypes) == 0 {
+		return
+	}
+
+	subtypes := findSubtypesInModule(supertypes[0], prepResult)
+	assert.Equal(t, []string{"user @ /main.ix:3:9:", "extend named @ /main.ix:4:1:"}, getItemStrings(subtypes))
+
+	extension, ok := getPatternHierarchyItemAt(4, 2, prepResult)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, patternExtensionItem, extension.Kind)
+	assert.Equal(t, []string{"named @ /lib.ix:2:9:"}, getItemStrings(findSupertypesInModule(extension, prepResult)))
+}
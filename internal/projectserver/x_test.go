@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/google/uuid"
 	"github.com/inoxlang/inox/internal/core"
 	"github.com/inoxlang/inox/internal/core/permkind"
@@ -24,6 +25,66 @@ import (
 
 //utils
 
+// prepareTestModule prepares the /main.ix module of an in-memory filesystem in data extraction mode,
+// the only global of the module is the http namespace.
+func prepareTestModule(t *testing.T, files map[string]string) (preparationResult, bool) {
+	if core.NewDefaultContext == nil {
+		core.SetNewDefaultContext(func(config core.DefaultContextConfig) (*core.Context, error) {
+			ctx := core.NewContext(core.ContextConfig{
+				Permissions:   config.Permissions,
+				ParentContext: config.ParentContext,
+				Filesystem:    config.Filesystem,
+			})
+
+			for name, pattern := range core.DEFAULT_NAMED_PATTERNS {
+				ctx.AddNamedPattern(name, pattern)
+			}
+			return ctx, nil
+		})
+		core.SetNewDefaultGlobalStateFn(func(ctx *core.Context, conf core.DefaultGlobalStateConfig) (*core.GlobalState, error) {
+			state := core.NewGlobalState(ctx, map[string]core.Value{
+				"http": http_ns.NewHttpNamespace(),
+			})
+			state.Out = io.Discard
+			state.Logger = zerolog.Nop()
+			state.OutputFieldsInitialized.Store(true)
+			return state, nil
+		})
+		t.Cleanup(func() {
+			core.UnsetNewDefaultContext()
+			core.UnsetNewDefaultGlobalStateFn()
+		})
+	}
+
+	fls := fs_ns.NewMemFilesystem(10_000)
+	for path, content := range files {
+		util.WriteFile(fls, path, []byte(content), 0600)
+	}
+
+	parsingCtx := core.NewContexWithEmptyState(core.ContextConfig{
+		Filesystem:  fls,
+		Permissions: []core.Permission{core.FilesystemPermission{Kind_: permkind.Read, Entity: core.PathPattern("/...")}},
+	}, nil)
+	defer parsingCtx.CancelGracefully()
+
+	state, mod, _, _ := core.PrepareLocalModule(core.ModulePreparationArgs{
+		Fpath:                     "/main.ix",
+		ParsingCompilationContext: parsingCtx,
+		PreinitFilesystem:         fls,
+		ScriptContextFileSystem:   fls,
+		Out:                       io.Discard,
+		LogOut:                    io.Discard,
+		DataExtractionMode:        true,
+	})
+
+	if !assert.NotNil(t, state) {
+		return preparationResult{}, false
+	}
+	t.Cleanup(func() { state.Ctx.CancelGracefully() })
+
+	return preparationResult{state: state, module: mod, chunk: mod.MainChunk}, true
+}
+
 func createTestServerAndClient(t *testing.T) (*core.Context, *testClient, bool) {
 
 	if !core.AreDefaultScriptLimitsSet() {